@@ -1,19 +1,18 @@
 package agent
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"flag"
-	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/netip"
 	"os"
+	"os/exec"
 	"os/user"
 	"path/filepath"
 	"reflect"
@@ -23,8 +22,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/armon/circbuf"
 	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/afero"
 	"go.uber.org/atomic"
 	"golang.org/x/exp/slices"
@@ -34,13 +33,14 @@ import (
 	"tailscale.com/types/netlogtype"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentcontainers"
+	"github.com/coder/coder/agent/agentresources"
 	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/agent/reconnectingpty"
 	"github.com/coder/coder/buildinfo"
-	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
-	"github.com/coder/coder/pty"
 	"github.com/coder/coder/tailnet"
 	"github.com/coder/retry"
 )
@@ -58,11 +58,35 @@ type Options struct {
 	ExchangeToken          func(ctx context.Context) (string, error)
 	Client                 Client
 	ReconnectingPTYTimeout time.Duration
+	// ReconnectingPTYBackend is one of reconnectingpty.Backends. Sessions are
+	// kept in memory when it's empty.
+	ReconnectingPTYBackend string
 	EnvironmentVariables   map[string]string
 	Logger                 slog.Logger
 	AgentPorts             map[int]string
 	SSHMaxTimeout          time.Duration
-	TailnetListenPort      uint16
+	// SSHListenAddress serves SSH outside of the Coder network for standard
+	// SSH clients, which must authenticate with a certificate issued by
+	// coderd. It's disabled when empty.
+	SSHListenAddress  string
+	TailnetListenPort uint16
+	// DebugLogs buffers the logs of the agent so they can be uploaded to
+	// coderd. The buffer is only uploaded when coderd asks for it unless
+	// DebugLogUploadLevel is set.
+	DebugLogs *DebugLogSink
+	// DebugLogUploadLevel is the minimum level of buffered logs that are
+	// uploaded continuously.
+	DebugLogUploadLevel *slog.Level
+	// Devcontainers starts the dev containers of the workspace directory
+	// once the startup scripts have finished, each with an agent that runs
+	// in this process. It's disabled when nil.
+	Devcontainers *agentcontainers.Options
+	// DevcontainerClient returns the client of the agent of a dev container
+	// for its auth token.
+	DevcontainerClient func(authToken string) Client
+	// Devcontainer is set for the agent of a dev container. The agent starts
+	// the container and runs commands in it.
+	Devcontainer *agentcontainers.Devcontainer
 }
 
 type Client interface {
@@ -74,6 +98,10 @@ type Client interface {
 	PostStartup(ctx context.Context, req agentsdk.PostStartupRequest) error
 	PostMetadata(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
 	PatchStartupLogs(ctx context.Context, req agentsdk.PatchStartupLogs) error
+	PostScriptStatus(ctx context.Context, scriptID uuid.UUID, req agentsdk.PostScriptStatusRequest) error
+	PatchDebugLogs(ctx context.Context, req agentsdk.PatchDebugLogs) error
+	PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) (agentsdk.PostSessionRecordingResponse, error)
+	PostDevcontainer(ctx context.Context, req agentsdk.PostDevcontainerRequest) (agentsdk.PostDevcontainerResponse, error)
 }
 
 func New(options Options) io.Closer {
@@ -115,6 +143,14 @@ func New(options Options) io.Closer {
 		ignorePorts:            options.AgentPorts,
 		connStatsChan:          make(chan *agentsdk.Stats, 1),
 		sshMaxTimeout:          options.SSHMaxTimeout,
+		sshListenAddress:       options.SSHListenAddress,
+		ptyBackendName:         options.ReconnectingPTYBackend,
+		resources:              agentresources.New(options.Filesystem),
+		debugLogs:              options.DebugLogs,
+		debugLogUploadLevel:    options.DebugLogUploadLevel,
+		devcontainers:          options.Devcontainers,
+		devcontainerClient:     options.DevcontainerClient,
+		devcontainer:           options.Devcontainer,
 	}
 	a.init(ctx)
 	return a
@@ -133,8 +169,11 @@ type agent struct {
 	// are used by the agent, that the user does not care about.
 	ignorePorts map[int]string
 
-	reconnectingPTYs       sync.Map
+	reconnectingPTYBackend reconnectingpty.Backend
 	reconnectingPTYTimeout time.Duration
+	// ptyBackendName is the configured reconnectingPTYBackend, which may
+	// not be available.
+	ptyBackendName string
 
 	connCloseWait sync.WaitGroup
 	closeCancel   context.CancelFunc
@@ -143,10 +182,13 @@ type agent struct {
 
 	envVars map[string]string
 	// manifest is atomic because values can change after reconnection.
-	manifest      atomic.Pointer[agentsdk.Manifest]
-	sessionToken  atomic.Pointer[string]
-	sshServer     *agentssh.Server
-	sshMaxTimeout time.Duration
+	manifest         atomic.Pointer[agentsdk.Manifest]
+	sessionToken     atomic.Pointer[string]
+	sshServer        *agentssh.Server
+	sshMaxTimeout    time.Duration
+	sshListenAddress string
+	// scriptCron runs scripts with a cron schedule.
+	scriptCron atomic.Pointer[cron.Cron]
 
 	lifecycleUpdate   chan struct{}
 	lifecycleReported chan codersdk.WorkspaceAgentLifecycle
@@ -156,6 +198,18 @@ type agent struct {
 	network       *tailnet.Conn
 	connStatsChan chan *agentsdk.Stats
 	latestStat    atomic.Pointer[agentsdk.Stats]
+	resources     *agentresources.Collector
+
+	debugLogs           *DebugLogSink
+	debugLogUploadLevel *slog.Level
+	debugLogsFlushMu    sync.Mutex
+
+	devcontainers      *agentcontainers.Options
+	devcontainerClient func(authToken string) Client
+	devcontainer       *agentcontainers.Devcontainer
+	// devcontainerAgents are the agents of the dev containers, protected by
+	// closeMutex.
+	devcontainerAgents []io.Closer
 
 	connCountReconnectingPTY atomic.Int64
 }
@@ -168,8 +222,39 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.Env = a.envVars
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
+	sshSrv.RecordingDone = func(recording *agentssh.Recording) {
+		a.uploadSessionRecording(ctx, recording)
+	}
+	if a.devcontainer != nil {
+		sshSrv.WrapCommand = a.devcontainer.Command
+		sshSrv.ForwardTCPHost = a.devcontainer.Host
+	}
 	a.sshServer = sshSrv
 
+	rptyOptions := reconnectingpty.Options{
+		Timeout:        a.reconnectingPTYTimeout,
+		SSHServer:      sshSrv,
+		TrackGoroutine: a.trackConnGoroutine,
+	}
+	a.reconnectingPTYBackend, err = reconnectingpty.NewBackend(a.ptyBackendName, rptyOptions)
+	if err != nil {
+		a.logger.Warn(ctx, "reconnecting pty backend unavailable, keeping sessions in memory", slog.F("backend", a.ptyBackendName), slog.Error(err))
+		a.reconnectingPTYBackend, _ = reconnectingpty.NewBackend(reconnectingpty.BackendBuffered, rptyOptions)
+	}
+	a.logger.Debug(ctx, "reconnecting pty backend", slog.F("backend", a.reconnectingPTYBackend.Name()))
+
+	if a.sshListenAddress != "" {
+		sshListener, err := net.Listen("tcp", a.sshListenAddress)
+		if err != nil {
+			a.logger.Error(ctx, "listen on ssh listen address", slog.F("address", a.sshListenAddress), slog.Error(err))
+		} else {
+			a.logger.Info(ctx, "serving ssh with certificate authentication", slog.F("address", sshListener.Addr().String()))
+			go func() {
+				_ = a.sshServer.ServeCertificateAuth(sshListener)
+			}()
+		}
+	}
+
 	go a.runLoop(ctx)
 }
 
@@ -180,6 +265,7 @@ func (a *agent) init(ctx context.Context) {
 func (a *agent) runLoop(ctx context.Context) {
 	go a.reportLifecycleLoop(ctx)
 	go a.reportMetadataLoop(ctx)
+	go a.reportDebugLogsLoop(ctx)
 
 	for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
 		a.logger.Info(ctx, "connecting to coderd")
@@ -498,56 +584,39 @@ func (a *agent) run(ctx context.Context) error {
 			}
 		}
 
-		lifecycleState := codersdk.WorkspaceAgentLifecycleReady
-		scriptDone := make(chan error, 1)
-		scriptStart := time.Now()
-		err = a.trackConnGoroutine(func() {
-			defer close(scriptDone)
-			scriptDone <- a.runStartupScript(ctx, manifest.StartupScript)
-		})
-		if err != nil {
-			return xerrors.Errorf("track startup script: %w", err)
-		}
-		go func() {
-			var timeout <-chan time.Time
-			// If timeout is zero, an older version of the coder
-			// provider was used. Otherwise a timeout is always > 0.
-			if manifest.StartupScriptTimeout > 0 {
-				t := time.NewTimer(manifest.StartupScriptTimeout)
-				defer t.Stop()
-				timeout = t.C
-			}
-
-			var err error
-			select {
-			case err = <-scriptDone:
-			case <-timeout:
-				a.logger.Warn(ctx, "startup script timed out")
-				a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStartTimeout)
-				err = <-scriptDone // The script can still complete after a timeout.
-			}
-			if errors.Is(err, context.Canceled) {
-				return
+		if a.devcontainer != nil {
+			go a.startDevcontainer(ctx)
+		} else {
+			scripts := manifestScripts(manifest)
+			scriptsDone := make(chan struct{})
+			err = a.trackConnGoroutine(func() {
+				defer close(scriptsDone)
+				a.runStartScripts(ctx, scripts)
+			})
+			if err != nil {
+				return xerrors.Errorf("track start scripts: %w", err)
 			}
-			// Only log if there was a startup script.
-			if manifest.StartupScript != "" {
-				execTime := time.Since(scriptStart)
-				if err != nil {
-					a.logger.Warn(ctx, "startup script failed", slog.F("execution_time", execTime), slog.Error(err))
-					lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
-				} else {
-					a.logger.Info(ctx, "startup script completed", slog.F("execution_time", execTime))
+			go func() {
+				select {
+				case <-scriptsDone:
+				case <-ctx.Done():
+					return
 				}
-			}
-			a.setLifecycle(ctx, lifecycleState)
-		}()
+				// Dev containers are usually defined in repositories that
+				// the startup scripts clone.
+				a.startDevcontainers(ctx, manifest.Directory)
+			}()
+			a.scheduleCronScripts(ctx, scripts)
+		}
 	}
 
 	// This automatically closes when the context ends!
 	appReporterCtx, appReporterCtxCancel := context.WithCancel(ctx)
 	defer appReporterCtxCancel()
 	go NewWorkspaceAppHealthReporter(
-		a.logger, manifest.Apps, a.client.PostAppHealth)(appReporterCtx)
+		a.logger, manifest.Apps, a.client.PostAppHealth, func(ctx context.Context, script string) (*exec.Cmd, error) {
+			return a.sshServer.CreateCommand(ctx, script, nil)
+		})(appReporterCtx)
 
 	a.closeMutex.Lock()
 	network := a.network
@@ -598,11 +667,16 @@ func (a *agent) trackConnGoroutine(fn func()) error {
 }
 
 func (a *agent) createTailnet(ctx context.Context, derpMap *tailcfg.DERPMap) (_ *tailnet.Conn, err error) {
+	var forwardTCPHost func() string
+	if a.devcontainer != nil {
+		forwardTCPHost = a.devcontainer.Host
+	}
 	network, err := tailnet.NewConn(&tailnet.Options{
-		Addresses:  []netip.Prefix{netip.PrefixFrom(codersdk.WorkspaceAgentIP, 128)},
-		DERPMap:    derpMap,
-		Logger:     a.logger.Named("tailnet"),
-		ListenPort: a.tailnetListenPort,
+		Addresses:      []netip.Prefix{netip.PrefixFrom(codersdk.WorkspaceAgentIP, 128)},
+		DERPMap:        derpMap,
+		Logger:         a.logger.Named("tailnet"),
+		ListenPort:     a.tailnetListenPort,
+		ForwardTCPHost: forwardTCPHost,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -786,179 +860,6 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 	}
 }
 
-func (a *agent) runStartupScript(ctx context.Context, script string) error {
-	return a.runScript(ctx, "startup", script)
-}
-
-func (a *agent) runShutdownScript(ctx context.Context, script string) error {
-	return a.runScript(ctx, "shutdown", script)
-}
-
-func (a *agent) runScript(ctx context.Context, lifecycle, script string) error {
-	if script == "" {
-		return nil
-	}
-
-	a.logger.Info(ctx, "running script", slog.F("lifecycle", lifecycle), slog.F("script", script))
-	fileWriter, err := a.filesystem.OpenFile(filepath.Join(a.logDir, fmt.Sprintf("coder-%s-script.log", lifecycle)), os.O_CREATE|os.O_RDWR, 0o600)
-	if err != nil {
-		return xerrors.Errorf("open %s script log file: %w", lifecycle, err)
-	}
-	defer func() {
-		_ = fileWriter.Close()
-	}()
-
-	var writer io.Writer = fileWriter
-	if lifecycle == "startup" {
-		// Create pipes for startup logs reader and writer
-		logsReader, logsWriter := io.Pipe()
-		defer func() {
-			_ = logsReader.Close()
-		}()
-		writer = io.MultiWriter(fileWriter, logsWriter)
-		flushedLogs, err := a.trackScriptLogs(ctx, logsReader)
-		if err != nil {
-			return xerrors.Errorf("track script logs: %w", err)
-		}
-		defer func() {
-			_ = logsWriter.Close()
-			<-flushedLogs
-		}()
-	}
-
-	cmd, err := a.sshServer.CreateCommand(ctx, script, nil)
-	if err != nil {
-		return xerrors.Errorf("create command: %w", err)
-	}
-	cmd.Stdout = writer
-	cmd.Stderr = writer
-	err = cmd.Run()
-	if err != nil {
-		// cmd.Run does not return a context canceled error, it returns "signal: killed".
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
-
-		return xerrors.Errorf("run: %w", err)
-	}
-	return nil
-}
-
-func (a *agent) trackScriptLogs(ctx context.Context, reader io.Reader) (chan struct{}, error) {
-	// Initialize variables for log management
-	queuedLogs := make([]agentsdk.StartupLog, 0)
-	var flushLogsTimer *time.Timer
-	var logMutex sync.Mutex
-	logsFlushed := sync.NewCond(&sync.Mutex{})
-	var logsSending bool
-	defer func() {
-		logMutex.Lock()
-		if flushLogsTimer != nil {
-			flushLogsTimer.Stop()
-		}
-		logMutex.Unlock()
-	}()
-
-	// sendLogs function uploads the queued logs to the server
-	sendLogs := func() {
-		// Lock logMutex and check if logs are already being sent
-		logMutex.Lock()
-		if logsSending {
-			logMutex.Unlock()
-			return
-		}
-		if flushLogsTimer != nil {
-			flushLogsTimer.Stop()
-		}
-		if len(queuedLogs) == 0 {
-			logMutex.Unlock()
-			return
-		}
-		// Move the current queued logs to logsToSend and clear the queue
-		logsToSend := queuedLogs
-		logsSending = true
-		queuedLogs = make([]agentsdk.StartupLog, 0)
-		logMutex.Unlock()
-
-		// Retry uploading logs until successful or a specific error occurs
-		for r := retry.New(time.Second, 5*time.Second); r.Wait(ctx); {
-			err := a.client.PatchStartupLogs(ctx, agentsdk.PatchStartupLogs{
-				Logs: logsToSend,
-			})
-			if err == nil {
-				break
-			}
-			var sdkErr *codersdk.Error
-			if errors.As(err, &sdkErr) {
-				if sdkErr.StatusCode() == http.StatusRequestEntityTooLarge {
-					a.logger.Warn(ctx, "startup logs too large, dropping logs")
-					break
-				}
-			}
-			a.logger.Error(ctx, "upload startup logs", slog.Error(err), slog.F("to_send", logsToSend))
-		}
-		// Reset logsSending flag
-		logMutex.Lock()
-		logsSending = false
-		flushLogsTimer.Reset(100 * time.Millisecond)
-		logMutex.Unlock()
-		logsFlushed.Broadcast()
-	}
-	// queueLog function appends a log to the queue and triggers sendLogs if necessary
-	queueLog := func(log agentsdk.StartupLog) {
-		logMutex.Lock()
-		defer logMutex.Unlock()
-
-		// Append log to the queue
-		queuedLogs = append(queuedLogs, log)
-
-		// If there are more than 100 logs, send them immediately
-		if len(queuedLogs) > 100 {
-			// Don't early return after this, because we still want
-			// to reset the timer just in case logs come in while
-			// we're sending.
-			go sendLogs()
-		}
-		// Reset or set the flushLogsTimer to trigger sendLogs after 100 milliseconds
-		if flushLogsTimer != nil {
-			flushLogsTimer.Reset(100 * time.Millisecond)
-			return
-		}
-		flushLogsTimer = time.AfterFunc(100*time.Millisecond, sendLogs)
-	}
-
-	// It's important that we either flush or drop all logs before returning
-	// because the startup state is reported after flush.
-	//
-	// It'd be weird for the startup state to be ready, but logs are still
-	// coming in.
-	logsFinished := make(chan struct{})
-	err := a.trackConnGoroutine(func() {
-		scanner := bufio.NewScanner(reader)
-		for scanner.Scan() {
-			queueLog(agentsdk.StartupLog{
-				CreatedAt: database.Now(),
-				Output:    scanner.Text(),
-			})
-		}
-		defer close(logsFinished)
-		logsFlushed.L.Lock()
-		for {
-			logMutex.Lock()
-			if len(queuedLogs) == 0 {
-				logMutex.Unlock()
-				break
-			}
-			logMutex.Unlock()
-			logsFlushed.Wait()
-		}
-	})
-	if err != nil {
-		return nil, xerrors.Errorf("track conn goroutine: %w", err)
-	}
-	return logsFinished, nil
-}
-
 func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, msg codersdk.WorkspaceAgentReconnectingPTYInit, conn net.Conn) (retErr error) {
 	defer conn.Close()
 
@@ -985,173 +886,29 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		logger.Debug(ctx, "session closed")
 	}()
 
-	var rpty *reconnectingPTY
-	rawRPTY, ok := a.reconnectingPTYs.Load(msg.ID)
-	if ok {
-		logger.Debug(ctx, "connecting to existing session")
-		rpty, ok = rawRPTY.(*reconnectingPTY)
-		if !ok {
-			return xerrors.Errorf("found invalid type in reconnecting pty map: %T", rawRPTY)
-		}
-	} else {
-		logger.Debug(ctx, "creating new session")
-
-		// Empty command will default to the users shell!
-		cmd, err := a.sshServer.CreateCommand(ctx, msg.Command, nil)
-		if err != nil {
-			return xerrors.Errorf("create command: %w", err)
-		}
-		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
-
-		// Default to buffer 64KiB.
-		circularBuffer, err := circbuf.NewBuffer(64 << 10)
-		if err != nil {
-			return xerrors.Errorf("create circular buffer: %w", err)
-		}
-
-		ptty, process, err := pty.Start(cmd)
-		if err != nil {
-			return xerrors.Errorf("start command: %w", err)
-		}
-
-		ctx, cancelFunc := context.WithCancel(ctx)
-		rpty = &reconnectingPTY{
-			activeConns: map[string]net.Conn{
-				// We have to put the connection in the map instantly otherwise
-				// the connection won't be closed if the process instantly dies.
-				connectionID: conn,
-			},
-			ptty: ptty,
-			// Timeouts created with an after func can be reset!
-			timeout:        time.AfterFunc(a.reconnectingPTYTimeout, cancelFunc),
-			circularBuffer: circularBuffer,
-		}
-		a.reconnectingPTYs.Store(msg.ID, rpty)
-		go func() {
-			// CommandContext isn't respected for Windows PTYs right now,
-			// so we need to manually track the lifecycle.
-			// When the context has been completed either:
-			// 1. The timeout completed.
-			// 2. The parent context was canceled.
-			<-ctx.Done()
-			_ = process.Kill()
-		}()
-		go func() {
-			// If the process dies randomly, we should
-			// close the pty.
-			_ = process.Wait()
-			rpty.Close()
-		}()
-		if err = a.trackConnGoroutine(func() {
-			buffer := make([]byte, 1024)
-			for {
-				read, err := rpty.ptty.Output().Read(buffer)
-				if err != nil {
-					// When the PTY is closed, this is triggered.
-					break
-				}
-				part := buffer[:read]
-				rpty.circularBufferMutex.Lock()
-				_, err = rpty.circularBuffer.Write(part)
-				rpty.circularBufferMutex.Unlock()
-				if err != nil {
-					logger.Error(ctx, "write to circular buffer", slog.Error(err))
-					break
-				}
-				rpty.activeConnsMutex.Lock()
-				for _, conn := range rpty.activeConns {
-					_, _ = conn.Write(part)
-				}
-				rpty.activeConnsMutex.Unlock()
-			}
+	return a.reconnectingPTYBackend.Attach(ctx, logger, msg, conn)
+}
 
-			// Cleanup the process, PTY, and delete it's
-			// ID from memory.
-			_ = process.Kill()
-			rpty.Close()
-			a.reconnectingPTYs.Delete(msg.ID)
-		}); err != nil {
-			return xerrors.Errorf("start routine: %w", err)
-		}
-	}
-	// Resize the PTY to initial height + width.
-	err := rpty.ptty.Resize(msg.Height, msg.Width)
-	if err != nil {
-		// We can continue after this, it's not fatal!
-		logger.Error(ctx, "resize", slog.Error(err))
-	}
-	// Write any previously stored data for the TTY.
-	rpty.circularBufferMutex.RLock()
-	prevBuf := slices.Clone(rpty.circularBuffer.Bytes())
-	rpty.circularBufferMutex.RUnlock()
-	// Note that there is a small race here between writing buffered
-	// data and storing conn in activeConns. This is likely a very minor
-	// edge case, but we should look into ways to avoid it. Holding
-	// activeConnsMutex would be one option, but holding this mutex
-	// while also holding circularBufferMutex seems dangerous.
-	_, err = conn.Write(prevBuf)
-	if err != nil {
-		return xerrors.Errorf("write buffer to conn: %w", err)
-	}
-	// Multiple connections to the same TTY are permitted.
-	// This could easily be used for terminal sharing, but
-	// we do it because it's a nice user experience to
-	// copy/paste a terminal URL and have it _just work_.
-	rpty.activeConnsMutex.Lock()
-	rpty.activeConns[connectionID] = conn
-	rpty.activeConnsMutex.Unlock()
-	// Resetting this timeout prevents the PTY from exiting.
-	rpty.timeout.Reset(a.reconnectingPTYTimeout)
-
-	ctx, cancelFunc := context.WithCancel(ctx)
-	defer cancelFunc()
-	heartbeat := time.NewTicker(a.reconnectingPTYTimeout / 2)
-	defer heartbeat.Stop()
-	go func() {
-		// Keep updating the activity while this
-		// connection is alive!
-		for {
-			select {
-			case <-ctx.Done():
+// uploadSessionRecording uploads the recording of an interactive session in
+// the background, retrying until it succeeds or the agent is closed.
+func (a *agent) uploadSessionRecording(ctx context.Context, recording *agentssh.Recording) {
+	req := recording.Request()
+	err := a.trackConnGoroutine(func() {
+		for retrier := retry.New(time.Second, 30*time.Second); retrier.Wait(ctx); {
+			resp, err := a.client.PostSessionRecording(ctx, req)
+			if err == nil {
+				a.logger.Debug(ctx, "uploaded session recording", slog.F("id", resp.ID), slog.F("type", req.Type))
 				return
-			case <-heartbeat.C:
 			}
-			rpty.timeout.Reset(a.reconnectingPTYTimeout)
-		}
-	}()
-	defer func() {
-		// After this connection ends, remove it from
-		// the PTYs active connections. If it isn't
-		// removed, all PTY data will be sent to it.
-		rpty.activeConnsMutex.Lock()
-		delete(rpty.activeConns, connectionID)
-		rpty.activeConnsMutex.Unlock()
-	}()
-	decoder := json.NewDecoder(conn)
-	var req codersdk.ReconnectingPTYRequest
-	for {
-		err = decoder.Decode(&req)
-		if xerrors.Is(err, io.EOF) {
-			return nil
-		}
-		if err != nil {
-			logger.Warn(ctx, "read conn", slog.Error(err))
-			return nil
-		}
-		_, err = rpty.ptty.Input().Write([]byte(req.Data))
-		if err != nil {
-			logger.Warn(ctx, "write to pty", slog.Error(err))
-			return nil
-		}
-		// Check if a resize needs to happen!
-		if req.Height == 0 || req.Width == 0 {
-			continue
-		}
-		err = rpty.ptty.Resize(req.Height, req.Width)
-		if err != nil {
-			// We can continue after this, it's not fatal!
-			logger.Error(ctx, "resize", slog.Error(err))
+			if ctx.Err() != nil {
+				break
+			}
+			a.logger.Warn(ctx, "failed to upload session recording", slog.Error(err))
 		}
+		a.logger.Error(ctx, "session recording lost because the agent is closing", slog.F("type", req.Type))
+	})
+	if err != nil {
+		a.logger.Error(ctx, "session recording lost because the agent is closed", slog.F("type", req.Type))
 	}
 }
 
@@ -1221,6 +978,18 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 		// Convert from microseconds to milliseconds.
 		stats.ConnectionMedianLatencyMS /= 1000
 
+		// Resource usage is only available on some platforms.
+		var diskPath string
+		if manifest := a.manifest.Load(); manifest != nil {
+			diskPath = manifest.Directory
+		}
+		resources, err := a.resources.Collect(diskPath)
+		if err != nil {
+			a.logger.Debug(ctx, "collect resource usage", slog.Error(err))
+		} else {
+			stats.Resources = resources
+		}
+
 		lastStat := a.latestStat.Load()
 		if lastStat != nil && reflect.DeepEqual(lastStat, stats) {
 			a.logger.Info(ctx, "skipping stat because nothing changed")
@@ -1278,6 +1047,10 @@ func (a *agent) Close() error {
 	a.logger.Info(ctx, "shutting down agent")
 	a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleShuttingDown)
 
+	for _, devcontainerAgent := range a.devcontainerAgents {
+		_ = devcontainerAgent.Close()
+	}
+
 	// Attempt to gracefully shut down all active SSH connections and
 	// stop accepting new ones.
 	err := a.sshServer.Shutdown(ctx)
@@ -1285,39 +1058,13 @@ func (a *agent) Close() error {
 		a.logger.Error(ctx, "ssh server shutdown", slog.Error(err))
 	}
 
-	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
-	if manifest := a.manifest.Load(); manifest != nil && manifest.ShutdownScript != "" {
-		scriptDone := make(chan error, 1)
-		scriptStart := time.Now()
-		go func() {
-			defer close(scriptDone)
-			scriptDone <- a.runShutdownScript(ctx, manifest.ShutdownScript)
-		}()
-
-		var timeout <-chan time.Time
-		// If timeout is zero, an older version of the coder
-		// provider was used. Otherwise a timeout is always > 0.
-		if manifest.ShutdownScriptTimeout > 0 {
-			t := time.NewTimer(manifest.ShutdownScriptTimeout)
-			defer t.Stop()
-			timeout = t.C
-		}
+	if scheduler := a.scriptCron.Load(); scheduler != nil {
+		scheduler.Stop()
+	}
 
-		var err error
-		select {
-		case err = <-scriptDone:
-		case <-timeout:
-			a.logger.Warn(ctx, "shutdown script timed out")
-			a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleShutdownTimeout)
-			err = <-scriptDone // The script can still complete after a timeout.
-		}
-		execTime := time.Since(scriptStart)
-		if err != nil {
-			a.logger.Warn(ctx, "shutdown script failed", slog.F("execution_time", execTime), slog.Error(err))
-			lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownError
-		} else {
-			a.logger.Info(ctx, "shutdown script completed", slog.F("execution_time", execTime))
-		}
+	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
+	if manifest := a.manifest.Load(); manifest != nil {
+		lifecycleState = a.runStopScripts(ctx, manifestScripts(*manifest))
 	}
 
 	// Set final state and wait for it to be reported because context
@@ -1351,31 +1098,6 @@ lifecycleWaitLoop:
 	return nil
 }
 
-type reconnectingPTY struct {
-	activeConnsMutex sync.Mutex
-	activeConns      map[string]net.Conn
-
-	circularBuffer      *circbuf.Buffer
-	circularBufferMutex sync.RWMutex
-	timeout             *time.Timer
-	ptty                pty.PTY
-}
-
-// Close ends all connections to the reconnecting
-// PTY and clear the circular buffer.
-func (r *reconnectingPTY) Close() {
-	r.activeConnsMutex.Lock()
-	defer r.activeConnsMutex.Unlock()
-	for _, conn := range r.activeConns {
-		_ = conn.Close()
-	}
-	_ = r.ptty.Close()
-	r.circularBufferMutex.Lock()
-	r.circularBuffer.Reset()
-	r.circularBufferMutex.Unlock()
-	r.timeout.Stop()
-}
-
 // userHomeDir returns the home directory of the current user, giving
 // priority to the $HOME environment variable.
 func userHomeDir() (string, error) {
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,6 +36,7 @@ import (
 	"go.uber.org/goleak"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
@@ -41,6 +44,7 @@ import (
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
+	"github.com/coder/coder/agent/agentcontainers"
 	"github.com/coder/coder/agent/agentssh"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/codersdk"
@@ -119,6 +123,94 @@ func TestAgent_Stats_ReconnectingPTY(t *testing.T) {
 	)
 }
 
+func TestAgent_DebugLogs(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	sink := agent.NewDebugLogSink(1000)
+	uploadLevel := slog.LevelWarn
+	//nolint:dogsled
+	conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(o *agent.Options) {
+		o.Logger = o.Logger.AppendSinks(sink)
+		o.DebugLogs = sink
+		o.DebugLogUploadLevel = &uploadLevel
+	})
+	logger := slog.Make(sink).Named("test")
+	logger.Warn(ctx, "disk almost full", slog.F("path", "/home/coder"), slog.F("reason", "too many files"))
+
+	// Only warnings and errors are uploaded continuously.
+	require.Eventually(t, func() bool {
+		return len(client.getDebugLogs()) > 0
+	}, testutil.WaitLong, testutil.IntervalFast)
+	logs := client.getDebugLogs()
+	for _, log := range logs {
+		require.Contains(t, []codersdk.LogLevel{codersdk.LogLevelWarn, codersdk.LogLevelError}, log.Level)
+	}
+	require.Equal(t, `test: disk almost full path=/home/coder reason="too many files"`, logs[0].Output)
+
+	// Flushing uploads the remaining logs of any level.
+	logger.Info(ctx, "still running")
+	require.True(t, conn.AwaitReachable(ctx))
+	err := conn.FlushDebugLogs(ctx)
+	require.NoError(t, err)
+	var warnings, infos int
+	for _, log := range client.getDebugLogs() {
+		switch log.Output {
+		case logs[0].Output:
+			warnings++
+		case "test: still running":
+			require.Equal(t, codersdk.LogLevelInfo, log.Level)
+			infos++
+		}
+	}
+	require.Equal(t, 1, warnings, "logs must only be uploaded once")
+	require.Equal(t, 1, infos)
+}
+
+func TestAgent_Stats_Resources(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	//nolint:dogsled
+	conn, _, stats, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	err := afero.WriteFile(fs, "/proc/meminfo", []byte("MemTotal: 2048 kB\nMemAvailable: 1024 kB\n"), 0o600)
+	require.NoError(t, err)
+	err = afero.WriteFile(fs, "/sys/fs/cgroup/cgroup.controllers", []byte("cpu memory"), 0o600)
+	require.NoError(t, err)
+	err = afero.WriteFile(fs, "/sys/fs/cgroup/memory.current", []byte("512"), 0o600)
+	require.NoError(t, err)
+	err = afero.WriteFile(fs, "/sys/fs/cgroup/memory.max", []byte("1024"), 0o600)
+	require.NoError(t, err)
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	stdin, err := session.StdinPipe()
+	require.NoError(t, err)
+	err = session.Shell()
+	require.NoError(t, err)
+
+	var s *agentsdk.Stats
+	require.Eventuallyf(t, func() bool {
+		var ok bool
+		s, ok = <-stats
+		return ok && s.Resources != nil
+	}, testutil.WaitLong, testutil.IntervalFast,
+		"never saw stats: %+v", s,
+	)
+	// The memory limit of the cgroup is used instead of the host memory.
+	require.EqualValues(t, 512, s.Resources.MemoryUsedBytes)
+	require.EqualValues(t, 1024, s.Resources.MemoryLimitBytes)
+	_ = stdin.Close()
+	err = session.Wait()
+	require.NoError(t, err)
+}
+
 func TestAgent_Stats_Magic(t *testing.T) {
 	t.Parallel()
 	t.Run("StripsEnvironmentVariable", func(t *testing.T) {
@@ -970,6 +1062,150 @@ func TestAgent_StartupScript(t *testing.T) {
 	})
 }
 
+func TestAgent_Scripts(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts use sh")
+	}
+
+	newAgent := func(t *testing.T, scripts []codersdk.WorkspaceAgentScript) (*client, afero.Fs) {
+		client := &client{
+			t:       t,
+			agentID: uuid.New(),
+			manifest: agentsdk.Manifest{
+				Scripts: scripts,
+				DERPMap: &tailcfg.DERPMap{},
+			},
+			statsChan:   make(chan *agentsdk.Stats),
+			coordinator: tailnet.NewCoordinator(),
+		}
+		fs := afero.NewMemMapFs()
+		closer := agent.New(agent.Options{
+			Client:                 client,
+			Filesystem:             fs,
+			Logger:                 slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+			ReconnectingPTYTimeout: 0,
+		})
+		t.Cleanup(func() {
+			_ = closer.Close()
+		})
+		return client, fs
+	}
+	waitLifecycle := func(t *testing.T, client *client, want codersdk.WorkspaceAgentLifecycle) {
+		require.Eventually(t, func() bool {
+			got := client.getLifecycleStates()
+			return len(got) > 0 && got[len(got)-1] == want
+		}, testutil.WaitShort, testutil.IntervalFast)
+	}
+
+	t.Run("OrderAndLogs", func(t *testing.T) {
+		t.Parallel()
+		marker := filepath.Join(t.TempDir(), "first")
+		first := codersdk.WorkspaceAgentScript{
+			ID:               uuid.New(),
+			DisplayName:      "first",
+			Script:           fmt.Sprintf("sleep 0.5 && touch %q && echo first", marker),
+			RunOrder:         0,
+			RunOnStart:       true,
+			StartBlocksLogin: true,
+		}
+		second := codersdk.WorkspaceAgentScript{
+			ID:               uuid.New(),
+			DisplayName:      "second",
+			Script:           fmt.Sprintf("test -f %q && echo second", marker),
+			RunOrder:         1,
+			RunOnStart:       true,
+			StartBlocksLogin: true,
+		}
+		// Pass them out of order to ensure they are sorted.
+		client, _ := newAgent(t, []codersdk.WorkspaceAgentScript{second, first})
+		waitLifecycle(t, client, codersdk.WorkspaceAgentLifecycleReady)
+
+		for _, script := range []codersdk.WorkspaceAgentScript{first, second} {
+			logs := client.getScriptLogs(script.ID)
+			require.Len(t, logs, 1)
+			require.Equal(t, script.DisplayName, logs[0].Output)
+
+			statuses := client.getScriptStatuses(script.ID)
+			require.Len(t, statuses, 2)
+			require.Equal(t, codersdk.WorkspaceAgentScriptStatusRunning, statuses[0].Status)
+			require.Equal(t, codersdk.WorkspaceAgentScriptStatusOK, statuses[1].Status)
+			require.NotNil(t, statuses[1].CompletedAt)
+		}
+	})
+
+	t.Run("NonBlockingFailure", func(t *testing.T) {
+		t.Parallel()
+		script := codersdk.WorkspaceAgentScript{
+			ID:          uuid.New(),
+			DisplayName: "fails",
+			Script:      "exit 3",
+			RunOnStart:  true,
+		}
+		client, _ := newAgent(t, []codersdk.WorkspaceAgentScript{script})
+		// The script doesn't block login, so its failure doesn't
+		// affect the lifecycle.
+		waitLifecycle(t, client, codersdk.WorkspaceAgentLifecycleReady)
+		require.Eventually(t, func() bool {
+			statuses := client.getScriptStatuses(script.ID)
+			if len(statuses) != 2 {
+				return false
+			}
+			return statuses[1].Status == codersdk.WorkspaceAgentScriptStatusError && statuses[1].ExitCode == 3
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("BlockingFailure", func(t *testing.T) {
+		t.Parallel()
+		client, _ := newAgent(t, []codersdk.WorkspaceAgentScript{{
+			ID:               uuid.New(),
+			DisplayName:      "fails",
+			Script:           "exit 1",
+			RunOnStart:       true,
+			StartBlocksLogin: true,
+		}})
+		waitLifecycle(t, client, codersdk.WorkspaceAgentLifecycleStartError)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		script := codersdk.WorkspaceAgentScript{
+			ID:               uuid.New(),
+			DisplayName:      "slow",
+			Script:           "sleep 3",
+			RunOnStart:       true,
+			StartBlocksLogin: true,
+			TimeoutSeconds:   1,
+		}
+		client, _ := newAgent(t, []codersdk.WorkspaceAgentScript{script})
+		require.Eventually(t, func() bool {
+			return slices.Contains(client.getLifecycleStates(), codersdk.WorkspaceAgentLifecycleStartTimeout)
+		}, testutil.WaitShort, testutil.IntervalFast)
+		waitLifecycle(t, client, codersdk.WorkspaceAgentLifecycleReady)
+		statuses := client.getScriptStatuses(script.ID)
+		require.Equal(t, codersdk.WorkspaceAgentScriptStatusTimedOut, statuses[len(statuses)-1].Status)
+	})
+
+	t.Run("Cron", func(t *testing.T) {
+		t.Parallel()
+		script := codersdk.WorkspaceAgentScript{
+			ID:          uuid.New(),
+			DisplayName: "cron",
+			Script:      "echo tick",
+			Cron:        "@every 1s",
+			LogPath:     "/cron.log",
+		}
+		client, fs := newAgent(t, []codersdk.WorkspaceAgentScript{script})
+		// Runs are appended to the log file.
+		require.Eventually(t, func() bool {
+			logs, err := afero.ReadFile(fs, script.LogPath)
+			return err == nil && strings.Count(string(logs), "tick\n") >= 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		// Cron runs aren't streamed to the startup logs.
+		require.Empty(t, client.getScriptLogs(script.ID))
+	})
+}
+
 func TestAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1490,6 +1726,210 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	expectLine(matchEchoOutput)
 }
 
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{SessionRecording: true}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+
+		// Sessions without a PTY aren't interactive, so they aren't recorded.
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		_, err = session.Output("echo plain")
+		require.NoError(t, err)
+
+		session, err = sshClient.NewSession()
+		require.NoError(t, err)
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		output, err := session.Output("echo recorded")
+		require.NoError(t, err)
+		require.Contains(t, string(output), "recorded")
+
+		require.Eventually(t, func() bool {
+			return len(client.getSessionRecordings()) > 0
+		}, testutil.WaitLong, testutil.IntervalFast)
+		recordings := client.getSessionRecordings()
+		require.Len(t, recordings, 1)
+		require.Equal(t, agentsdk.SessionRecordingTypeSSH, recordings[0].Type)
+		require.False(t, recordings[0].Truncated)
+		lines := strings.Split(strings.TrimSpace(string(recordings[0].Recording)), "\n")
+		require.JSONEq(t, fmt.Sprintf(`{"version":2,"width":80,"height":24,"timestamp":%d,"env":{"TERM":"xterm"}}`, recordings[0].StartedAt.Unix()), lines[0])
+		require.Contains(t, strings.Join(lines[1:], "\n"), "recorded")
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{SessionRecording: true}, 0)
+		netConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 30, 100, "/bin/bash")
+		require.NoError(t, err)
+		defer netConn.Close()
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo recorded; sleep 0.5; exit\r\n",
+		})
+		require.NoError(t, err)
+		_, err = netConn.Write(data)
+		require.NoError(t, err)
+		// The PTY is closed once the shell exits.
+		_, _ = io.Copy(io.Discard, netConn)
+
+		require.Eventually(t, func() bool {
+			return len(client.getSessionRecordings()) > 0
+		}, testutil.WaitLong, testutil.IntervalFast)
+		recording := client.getSessionRecordings()[0]
+		require.Equal(t, agentsdk.SessionRecordingTypeReconnectingPTY, recording.Type)
+		require.Contains(t, string(recording.Recording), `"width":100,"height":30`)
+		require.Contains(t, string(recording.Recording), "recorded")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		_, err = session.Output("echo recorded")
+		require.NoError(t, err)
+		require.Never(t, func() bool {
+			return len(client.getSessionRecordings()) > 0
+		}, testutil.IntervalMedium, testutil.IntervalFast)
+	})
+}
+
+func TestAgent_Devcontainers(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake devcontainer and docker CLIs are shell scripts.")
+	}
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	cliDir := t.TempDir()
+	writeCLI := func(name, script string) string {
+		path := filepath.Join(cliDir, name)
+		//nolint:gosec // The script must be executable.
+		err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755)
+		require.NoError(t, err)
+		return path
+	}
+	devcontainerCLI := writeCLI("devcontainer", `echo "building image" >&2
+echo '{"outcome":"success","containerId":"0123456789abcdef","remoteUser":"vscode","remoteWorkspaceFolder":"/workspaces/project"}'`)
+	// The fake container runs commands on the host, and marks them so the
+	// test can tell they were executed in the container.
+	dockerCLI := writeCLI("docker", `if [ "$1" = inspect ]; then echo 127.0.0.1; exit; fi
+shift
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-i | -t) shift ;;
+	-w | -u | -e) shift 2 ;;
+	*) break ;;
+	esac
+done
+shift
+export IN_DEVCONTAINER=true
+exec "$@"`)
+
+	derpMap := tailnettest.RunDERPAndSTUN(t)
+	coordinator := tailnet.NewCoordinator()
+	t.Cleanup(func() {
+		_ = coordinator.Close()
+	})
+	devcontainerAgentID := uuid.New()
+	devcontainerClient := &client{
+		t:       t,
+		agentID: devcontainerAgentID,
+		manifest: agentsdk.Manifest{
+			DERPMap:   derpMap,
+			Directory: "/workspaces/project",
+		},
+		statsChan:   make(chan *agentsdk.Stats, 50),
+		coordinator: coordinator,
+	}
+	//nolint:dogsled
+	_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		DERPMap:   derpMap,
+		Directory: "/home/coder",
+	}, 0, func(o *agent.Options) {
+		err := afero.WriteFile(o.Filesystem, "/home/coder/project/.devcontainer.json", []byte(`{"name": "Project"}`), 0o600)
+		require.NoError(t, err)
+		o.Devcontainers = &agentcontainers.Options{
+			CLIPath:    devcontainerCLI,
+			DockerPath: dockerCLI,
+		}
+		o.DevcontainerClient = func(string) agent.Client {
+			return devcontainerClient
+		}
+	})
+
+	require.Eventually(t, func() bool {
+		states := devcontainerClient.getLifecycleStates()
+		return len(states) > 0 && states[len(states)-1] == codersdk.WorkspaceAgentLifecycleReady
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, []codersdk.WorkspaceAgentLifecycle{
+		codersdk.WorkspaceAgentLifecycleStarting,
+		codersdk.WorkspaceAgentLifecycleReady,
+	}, devcontainerClient.getLifecycleStates())
+	require.Equal(t, []agentsdk.PostDevcontainerRequest{{
+		Name:      "project",
+		Directory: "/workspaces/project",
+	}}, client.getDevcontainers())
+	var output []string
+	for _, log := range devcontainerClient.getStartupLogs() {
+		output = append(output, log.Output)
+	}
+	require.Contains(t, output, "building image")
+
+	// Commands of the dev container's agent run in the container.
+	conn := connectAgent(t, coordinator, devcontainerAgentID, derpMap)
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	out, err := session.Output("echo $IN_DEVCONTAINER")
+	require.NoError(t, err)
+	require.Equal(t, "true", strings.TrimSpace(string(out)))
+
+	// Forwarded connections to localhost are dialed on the container, which
+	// the fake docker reports at 127.0.0.1.
+	containerListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer containerListener.Close()
+	_, port, err := net.SplitHostPort(containerListener.Addr().String())
+	require.NoError(t, err)
+	forwarded, err := sshClient.Dial("tcp", net.JoinHostPort("localhost", port))
+	require.NoError(t, err)
+	_ = forwarded.Close()
+
+	// Reverse forwarding and file transfers would reach the host instead of
+	// the container, so they're rejected.
+	_, err = sshClient.Listen("tcp", "127.0.0.1:0")
+	require.Error(t, err)
+	_, err = sftp.NewClient(sshClient)
+	require.Error(t, err)
+	_, err = conn.StatFile(ctx, "/home/coder/project/.devcontainer.json")
+	require.ErrorContains(t, err, "dev container")
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -1714,7 +2154,7 @@ func (c closeFunc) Close() error {
 	return c()
 }
 
-func setupAgent(t *testing.T, metadata agentsdk.Manifest, ptyTimeout time.Duration) (
+func setupAgent(t *testing.T, metadata agentsdk.Manifest, ptyTimeout time.Duration, opts ...func(*agent.Options)) (
 	*codersdk.WorkspaceAgentConn,
 	*client,
 	<-chan *agentsdk.Stats,
@@ -1738,18 +2178,28 @@ func setupAgent(t *testing.T, metadata agentsdk.Manifest, ptyTimeout time.Durati
 		statsChan:   statsCh,
 		coordinator: coordinator,
 	}
-	closer := agent.New(agent.Options{
+	options := agent.Options{
 		Client:                 c,
 		Filesystem:             fs,
 		Logger:                 slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
 		ReconnectingPTYTimeout: ptyTimeout,
-	})
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	closer := agent.New(options)
 	t.Cleanup(func() {
 		_ = closer.Close()
 	})
+	return connectAgent(t, coordinator, agentID, metadata.DERPMap), c, statsCh, fs, closer
+}
+
+// connectAgent connects to the agent with the ID through the coordinator.
+func connectAgent(t *testing.T, coordinator tailnet.Coordinator, agentID uuid.UUID, derpMap *tailcfg.DERPMap) *codersdk.WorkspaceAgentConn {
+	t.Helper()
 	conn, err := tailnet.NewConn(&tailnet.Options{
 		Addresses: []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
-		DERPMap:   metadata.DERPMap,
+		DERPMap:   derpMap,
 		Logger:    slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
 	})
 	require.NoError(t, err)
@@ -1780,7 +2230,7 @@ func setupAgent(t *testing.T, metadata agentsdk.Manifest, ptyTimeout time.Durati
 	if !agentConn.AwaitReachable(ctx) {
 		t.Fatal("agent not reachable")
 	}
-	return agentConn, c, statsCh, fs, closer
+	return agentConn
 }
 
 var dialTestPayload = []byte("dean-was-here123")
@@ -1824,10 +2274,15 @@ type client struct {
 	lastWorkspaceAgent func()
 	patchWorkspaceLogs func() error
 
-	mu              sync.Mutex // Protects following.
-	lifecycleStates []codersdk.WorkspaceAgentLifecycle
-	startup         agentsdk.PostStartupRequest
-	logs            []agentsdk.StartupLog
+	mu                sync.Mutex // Protects following.
+	lifecycleStates   []codersdk.WorkspaceAgentLifecycle
+	startup           agentsdk.PostStartupRequest
+	logs              []agentsdk.StartupLog
+	scriptLogs        map[uuid.UUID][]agentsdk.StartupLog
+	scriptStatuses    map[uuid.UUID][]agentsdk.PostScriptStatusRequest
+	debugLogs         []agentsdk.DebugLog
+	sessionRecordings []agentsdk.PostSessionRecordingRequest
+	devcontainers     []agentsdk.PostDevcontainerRequest
 }
 
 func (c *client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
@@ -1941,9 +2396,74 @@ func (c *client) PatchStartupLogs(_ context.Context, logs agentsdk.PatchStartupL
 		return c.patchWorkspaceLogs()
 	}
 	c.logs = append(c.logs, logs.Logs...)
+	if c.scriptLogs == nil {
+		c.scriptLogs = make(map[uuid.UUID][]agentsdk.StartupLog)
+	}
+	c.scriptLogs[logs.ScriptID] = append(c.scriptLogs[logs.ScriptID], logs.Logs...)
 	return nil
 }
 
+func (c *client) getScriptLogs(scriptID uuid.UUID) []agentsdk.StartupLog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]agentsdk.StartupLog(nil), c.scriptLogs[scriptID]...)
+}
+
+func (c *client) PostScriptStatus(_ context.Context, scriptID uuid.UUID, req agentsdk.PostScriptStatusRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.scriptStatuses == nil {
+		c.scriptStatuses = make(map[uuid.UUID][]agentsdk.PostScriptStatusRequest)
+	}
+	c.scriptStatuses[scriptID] = append(c.scriptStatuses[scriptID], req)
+	return nil
+}
+
+func (c *client) getScriptStatuses(scriptID uuid.UUID) []agentsdk.PostScriptStatusRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]agentsdk.PostScriptStatusRequest(nil), c.scriptStatuses[scriptID]...)
+}
+
+func (c *client) PatchDebugLogs(_ context.Context, logs agentsdk.PatchDebugLogs) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.debugLogs = append(c.debugLogs, logs.Logs...)
+	return nil
+}
+
+func (c *client) getDebugLogs() []agentsdk.DebugLog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]agentsdk.DebugLog(nil), c.debugLogs...)
+}
+
+func (c *client) PostSessionRecording(_ context.Context, req agentsdk.PostSessionRecordingRequest) (agentsdk.PostSessionRecordingResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionRecordings = append(c.sessionRecordings, req)
+	return agentsdk.PostSessionRecordingResponse{ID: uuid.New()}, nil
+}
+
+func (c *client) getSessionRecordings() []agentsdk.PostSessionRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]agentsdk.PostSessionRecordingRequest(nil), c.sessionRecordings...)
+}
+
+func (c *client) PostDevcontainer(_ context.Context, req agentsdk.PostDevcontainerRequest) (agentsdk.PostDevcontainerResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.devcontainers = append(c.devcontainers, req)
+	return agentsdk.PostDevcontainerResponse{AgentID: uuid.New(), AuthToken: uuid.New()}, nil
+}
+
+func (c *client) getDevcontainers() []agentsdk.PostDevcontainerRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]agentsdk.PostDevcontainerRequest(nil), c.devcontainers...)
+}
+
 // tempDirUnixSocket returns a temporary directory that can safely hold unix
 // sockets (probably).
 //
@@ -1966,3 +2486,58 @@ func tempDirUnixSocket(t *testing.T) string {
 
 	return t.TempDir()
 }
+
+func TestAgent_Files(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:dogsled
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	dir := filepath.Join(os.TempDir(), "files")
+	file := filepath.Join(dir, "sub", "hello.txt")
+
+	err := conn.WriteFile(ctx, file, 0, 0o600, strings.NewReader("hello"))
+	require.NoError(t, err)
+	// Resuming discards anything past the offset.
+	err = conn.WriteFile(ctx, file, 4, 0o640, strings.NewReader("o world"))
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, file)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(content))
+
+	info, err := conn.StatFile(ctx, file)
+	require.NoError(t, err)
+	require.Equal(t, "hello.txt", info.Name)
+	require.EqualValues(t, 11, info.Size)
+	require.EqualValues(t, 0o640, info.Mode)
+	require.False(t, info.IsDir)
+
+	err = conn.MakeDirectory(ctx, filepath.Join(dir, "empty"), 0o700)
+	require.NoError(t, err)
+	list, err := conn.ListFiles(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, list.Files, 2)
+	require.Equal(t, "empty", list.Files[0].Name)
+	require.True(t, list.Files[0].IsDir)
+	require.EqualValues(t, 0o700, list.Files[0].Mode)
+	require.Equal(t, "sub", list.Files[1].Name)
+
+	hash, err := conn.HashFile(ctx, file, 5)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, hash.Length)
+	sum := sha256.Sum256([]byte("hello"))
+	require.Equal(t, hex.EncodeToString(sum[:]), hash.SHA256)
+
+	reader, length, err := conn.ReadFile(ctx, file, 6)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.EqualValues(t, 5, length)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "world", string(content))
+
+	_, err = conn.StatFile(ctx, filepath.Join(dir, "missing"))
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
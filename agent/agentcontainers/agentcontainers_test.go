@@ -0,0 +1,162 @@
+package agentcontainers_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/agent/agentcontainers"
+	"github.com/coder/coder/testutil"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("None", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll("/home/coder/project", 0o755))
+		configs, err := agentcontainers.Find(fs, "/home/coder")
+		require.NoError(t, err)
+		require.Empty(t, configs)
+	})
+
+	t.Run("Folders", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/home/coder/.devcontainer.json", `{}`)
+		writeFile(t, fs, "/home/coder/Frontend App/.devcontainer/devcontainer.json", `{
+	// Comments and trailing commas are allowed.
+	"name": "Frontend App", /* The agent name is derived from this. */
+	"workspaceFolder": "/src",
+	"forwardPorts": [3000,],
+}`)
+		// The config in .devcontainer takes precedence.
+		writeFile(t, fs, "/home/coder/api/.devcontainer.json", `{"name": "ignored"}`)
+		writeFile(t, fs, "/home/coder/api/.devcontainer/devcontainer.json", `{"name": "Frontend-App"}`)
+		// Hidden folders and nested folders are ignored.
+		writeFile(t, fs, "/home/coder/.cache/.devcontainer.json", `{}`)
+		writeFile(t, fs, "/home/coder/api/nested/.devcontainer.json", `{}`)
+
+		configs, err := agentcontainers.Find(fs, "/home/coder")
+		require.NoError(t, err)
+		require.Equal(t, []agentcontainers.Config{{
+			Name:                  "coder",
+			WorkspaceFolder:       "/home/coder",
+			ConfigPath:            "/home/coder/.devcontainer.json",
+			RemoteWorkspaceFolder: "/workspaces/coder",
+		}, {
+			Name:                  "frontend-app",
+			WorkspaceFolder:       "/home/coder/Frontend App",
+			ConfigPath:            "/home/coder/Frontend App/.devcontainer/devcontainer.json",
+			RemoteWorkspaceFolder: "/src",
+		}, {
+			Name:                  "frontend-app-2",
+			WorkspaceFolder:       "/home/coder/api",
+			ConfigPath:            "/home/coder/api/.devcontainer/devcontainer.json",
+			RemoteWorkspaceFolder: "/workspaces/api",
+		}}, configs)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/home/coder/project/.devcontainer.json", `{"name": `)
+		configs, err := agentcontainers.Find(fs, "/home/coder")
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		require.Equal(t, "project", configs[0].Name)
+	})
+}
+
+func TestDevcontainer(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake CLIs are shell scripts.")
+	}
+
+	t.Run("Up", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dir := t.TempDir()
+		devcontainer := agentcontainers.New(agentcontainers.Config{
+			Name:                  "project",
+			WorkspaceFolder:       "/home/coder/project",
+			ConfigPath:            "/home/coder/project/.devcontainer.json",
+			RemoteWorkspaceFolder: "/workspaces/project",
+		}, agentcontainers.Options{
+			CLIPath: fakeCLI(t, dir, "devcontainer", `echo "$@" > "$0.args"
+echo "building image" >&2
+echo '{"outcome":"success","containerId":"0123456789abcdef","remoteUser":"vscode","remoteWorkspaceFolder":"/workspaces/project"}'`),
+			DockerPath: fakeCLI(t, dir, "docker", `if [ "$1" = inspect ]; then echo "172.17.0.2 "; exit; fi
+echo "$@"`),
+		})
+		_, ok := devcontainer.Container()
+		require.False(t, ok)
+		require.Empty(t, devcontainer.Host())
+
+		var logs bytes.Buffer
+		container, err := devcontainer.Up(ctx, &logs)
+		require.NoError(t, err)
+		require.Equal(t, agentcontainers.Container{
+			ID:              "0123456789abcdef",
+			User:            "vscode",
+			WorkspaceFolder: "/workspaces/project",
+			Host:            "172.17.0.2",
+		}, container)
+		require.Equal(t, "172.17.0.2", devcontainer.Host())
+		require.Contains(t, logs.String(), "building image")
+		args, err := os.ReadFile(filepath.Join(dir, "devcontainer.args"))
+		require.NoError(t, err)
+		require.Equal(t, "up --workspace-folder /home/coder/project --config /home/coder/project/.devcontainer.json --docker-path "+filepath.Join(dir, "docker"), strings.TrimSpace(string(args)))
+
+		cmd := exec.Command("true")
+		cmd.Env = append(os.Environ(), "CODER=true", "PATH=/host/bin")
+		wrapped, err := devcontainer.Command(ctx, cmd, "echo hello")
+		require.NoError(t, err)
+		out, err := wrapped.Output()
+		require.NoError(t, err)
+		require.Equal(t, "exec -i -w /workspaces/project -u vscode -e CODER -e TERM 0123456789abcdef /bin/sh -c echo hello", strings.TrimSpace(string(out)))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dir := t.TempDir()
+		devcontainer := agentcontainers.New(agentcontainers.Config{
+			Name:            "project",
+			WorkspaceFolder: "/home/coder/project",
+			ConfigPath:      "/home/coder/project/.devcontainer.json",
+		}, agentcontainers.Options{
+			CLIPath: fakeCLI(t, dir, "devcontainer", `echo '{"outcome":"error","message":"Command failed","description":"An error occurred building the image."}'
+exit 1`),
+		})
+		_, err := devcontainer.Up(ctx, &bytes.Buffer{})
+		require.ErrorContains(t, err, "Command failed: An error occurred building the image.")
+
+		_, err = devcontainer.Command(ctx, exec.Command("true"), "")
+		require.ErrorContains(t, err, "not running")
+	})
+}
+
+func writeFile(t *testing.T, fs afero.Fs, name, data string) {
+	t.Helper()
+	require.NoError(t, fs.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, afero.WriteFile(fs, name, []byte(data), 0o600))
+}
+
+// fakeCLI writes a shell script that stands in for a CLI.
+func fakeCLI(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	//nolint:gosec // The script must be executable.
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+	return path
+}
@@ -0,0 +1,178 @@
+// Package agentcontainers finds the dev containers of a workspace and runs
+// them with the devcontainer CLI. The agent runs a separate agent for each dev
+// container, which executes its commands in the container.
+package agentcontainers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+)
+
+// Config is a devcontainer.json file found in the workspace.
+type Config struct {
+	// Name is the name of the agent of the dev container. It's derived from
+	// the name in the config or from the workspace folder.
+	Name string
+	// WorkspaceFolder is the folder the dev container is started for.
+	WorkspaceFolder string
+	// ConfigPath is the path of the devcontainer.json file.
+	ConfigPath string
+	// RemoteWorkspaceFolder is where the workspace folder is mounted in the
+	// dev container.
+	RemoteWorkspaceFolder string
+}
+
+// configPaths are the locations of devcontainer.json in a workspace folder,
+// in the order the devcontainer CLI looks them up.
+var configPaths = []string{
+	filepath.Join(".devcontainer", "devcontainer.json"),
+	".devcontainer.json",
+}
+
+// Find finds the dev containers of dir and of the folders in it, where
+// repositories are usually cloned to.
+func Find(fs afero.Fs, dir string) ([]Config, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	folders := []string{dir}
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, xerrors.Errorf("read %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		folders = append(folders, filepath.Join(dir, entry.Name()))
+	}
+
+	var configs []Config
+	names := map[string]int{}
+	for _, folder := range folders {
+		for _, configPath := range configPaths {
+			configPath = filepath.Join(folder, configPath)
+			data, err := afero.ReadFile(fs, configPath)
+			if err != nil {
+				continue
+			}
+			config := parseConfig(folder, configPath, data)
+			// Names must be unique within the workspace.
+			names[config.Name]++
+			if count := names[config.Name]; count > 1 {
+				config.Name = fmt.Sprintf("%s-%d", config.Name, count)
+			}
+			configs = append(configs, config)
+			break
+		}
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].WorkspaceFolder < configs[j].WorkspaceFolder
+	})
+	return configs, nil
+}
+
+// parseConfig reads the fields of a devcontainer.json file that the agent
+// needs. Invalid files are still returned, since the devcontainer CLI reports
+// the error when the dev container is started.
+func parseConfig(folder, configPath string, data []byte) Config {
+	var file struct {
+		Name            string `json:"name"`
+		WorkspaceFolder string `json:"workspaceFolder"`
+	}
+	_ = json.Unmarshal(standardizeJSON(data), &file)
+
+	config := Config{
+		Name:                  agentName(file.Name),
+		WorkspaceFolder:       folder,
+		ConfigPath:            configPath,
+		RemoteWorkspaceFolder: file.WorkspaceFolder,
+	}
+	if config.Name == "" {
+		config.Name = agentName(filepath.Base(folder))
+	}
+	if config.Name == "" {
+		config.Name = "devcontainer"
+	}
+	if config.RemoteWorkspaceFolder == "" {
+		// This is the default of the devcontainer CLI.
+		config.RemoteWorkspaceFolder = path.Join("/workspaces", filepath.Base(folder))
+	}
+	return config
+}
+
+// agentName turns the name of a dev container into a valid agent name, which
+// is alphanumeric with hyphens.
+func agentName(name string) string {
+	var (
+		builder strings.Builder
+		hyphen  bool
+	)
+	for _, r := range strings.ToLower(name) {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			hyphen = true
+			continue
+		}
+		if hyphen && builder.Len() > 0 {
+			_, _ = builder.WriteRune('-')
+		}
+		hyphen = false
+		_, _ = builder.WriteRune(r)
+	}
+	result := builder.String()
+	if len(result) > 32 {
+		result = strings.TrimRight(result[:32], "-")
+	}
+	return result
+}
+
+// standardizeJSON removes the comments and trailing commas that
+// devcontainer.json files may contain.
+func standardizeJSON(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			// Strings are copied as they are.
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(data) {
+				i = len(data) - 1
+			}
+			out = append(out, data[start:i+1]...)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+				continue
+			}
+			i += end + 3
+		case c == '}' || c == ']':
+			trimmed := bytes.TrimRight(out, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = append(out[:len(trimmed)-1], out[len(trimmed):]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
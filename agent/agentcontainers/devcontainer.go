@@ -0,0 +1,238 @@
+package agentcontainers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+type Options struct {
+	// CLIPath is the path of the devcontainer CLI. It's looked up in $PATH
+	// when empty.
+	CLIPath string
+	// DockerPath is the path of the container runtime CLI, e.g. podman. It's
+	// docker when empty.
+	DockerPath string
+}
+
+// Devcontainer is a dev container of the workspace.
+type Devcontainer struct {
+	Config
+	cli    string
+	docker string
+
+	mu        sync.RWMutex
+	container *Container
+}
+
+// Container is a running dev container.
+type Container struct {
+	ID string
+	// User is the user that commands run as.
+	User string
+	// WorkspaceFolder is the folder that commands run in.
+	WorkspaceFolder string
+	// Host is the address that the ports of the container are reachable at.
+	Host string
+}
+
+func New(config Config, options Options) *Devcontainer {
+	if options.CLIPath == "" {
+		options.CLIPath = "devcontainer"
+	}
+	if options.DockerPath == "" {
+		options.DockerPath = "docker"
+	}
+	return &Devcontainer{
+		Config: config,
+		cli:    options.CLIPath,
+		docker: options.DockerPath,
+	}
+}
+
+// upResult is the output of "devcontainer up".
+type upResult struct {
+	Outcome               string `json:"outcome"`
+	Message               string `json:"message"`
+	Description           string `json:"description"`
+	ContainerID           string `json:"containerId"`
+	RemoteUser            string `json:"remoteUser"`
+	RemoteWorkspaceFolder string `json:"remoteWorkspaceFolder"`
+}
+
+// Up builds and starts the dev container, or starts the existing container
+// of the config. The output of the devcontainer CLI is written to logs.
+func (d *Devcontainer) Up(ctx context.Context, logs io.Writer) (Container, error) {
+	_, _ = fmt.Fprintf(logs, "Starting dev container %q from %s\n", d.Name, d.ConfigPath)
+
+	var stdout bytes.Buffer
+	//nolint:gosec // The paths are configured by the agent.
+	cmd := exec.CommandContext(ctx, d.cli, "up",
+		"--workspace-folder", d.WorkspaceFolder,
+		"--config", d.ConfigPath,
+		"--docker-path", d.docker,
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = logs
+	runErr := cmd.Run()
+
+	// The result is the last line of the output, the CLI exits with an
+	// error if it is not a success.
+	var result upResult
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	err := json.Unmarshal([]byte(lines[len(lines)-1]), &result)
+	if err != nil {
+		if runErr != nil {
+			return Container{}, xerrors.Errorf("run devcontainer up: %w", runErr)
+		}
+		return Container{}, xerrors.Errorf("parse devcontainer up result %q: %w", stdout.String(), err)
+	}
+	if result.Outcome != "success" {
+		message := result.Message
+		if result.Description != "" {
+			message += ": " + result.Description
+		}
+		return Container{}, xerrors.Errorf("devcontainer up: %s", message)
+	}
+
+	host, err := d.containerHost(ctx, result.ContainerID)
+	if err != nil {
+		return Container{}, err
+	}
+	container := Container{
+		ID:              result.ContainerID,
+		User:            result.RemoteUser,
+		WorkspaceFolder: result.RemoteWorkspaceFolder,
+		Host:            host,
+	}
+	if container.WorkspaceFolder == "" {
+		container.WorkspaceFolder = d.RemoteWorkspaceFolder
+	}
+	d.mu.Lock()
+	d.container = &container
+	d.mu.Unlock()
+	_, _ = fmt.Fprintf(logs, "Dev container %q is running in container %s\n", d.Name, shortID(container.ID))
+	return container, nil
+}
+
+// containerHost returns the IP address of the container. Containers on the
+// host network have no address of their own.
+func (d *Devcontainer) containerHost(ctx context.Context, id string) (string, error) {
+	//nolint:gosec // The paths are configured by the agent.
+	out, err := exec.CommandContext(ctx, d.docker, "inspect",
+		"--format", "{{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}",
+		id,
+	).Output()
+	if err != nil {
+		return "", xerrors.Errorf("inspect container %s: %w", shortID(id), err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "127.0.0.1", nil
+	}
+	return fields[0], nil
+}
+
+// Container returns the running container, or false if it wasn't started.
+func (d *Devcontainer) Container() (Container, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.container == nil {
+		return Container{}, false
+	}
+	return *d.container, true
+}
+
+// Host returns the address of the running container, or an empty string if
+// it wasn't started.
+func (d *Devcontainer) Host() string {
+	container, _ := d.Container()
+	return container.Host
+}
+
+// execShim allocates a TTY in the container when the command runs in a PTY.
+const execShim = `if [ -t 0 ]; then exec "$0" exec -i -t "$@"; fi; exec "$0" exec -i "$@"`
+
+// loginShell starts the login shell of the user in the container.
+const loginShell = `shell=$(getent passwd "$(id -un)" 2>/dev/null | cut -d: -f7); exec "${shell:-/bin/sh}" -l`
+
+// Command returns a command that runs the script in the dev container, or the
+// login shell of its user if the script is empty. The environment variables
+// that cmd adds to the ones of the agent are passed to the container.
+func (d *Devcontainer) Command(ctx context.Context, cmd *exec.Cmd, script string) (*exec.Cmd, error) {
+	container, ok := d.Container()
+	if !ok {
+		return nil, xerrors.Errorf("dev container %q is not running", d.Name)
+	}
+	if script == "" {
+		script = loginShell
+	}
+	args := []string{"-c", execShim, d.docker, "-w", container.WorkspaceFolder}
+	if container.User != "" {
+		args = append(args, "-u", container.User)
+	}
+	for _, key := range containerEnv(cmd.Env) {
+		// Without a value, docker passes the variable of its own
+		// environment.
+		args = append(args, "-e", key)
+	}
+	args = append(args, container.ID, "/bin/sh", "-c", script)
+
+	//nolint:gosec // The script is run in the container by design.
+	wrapped := exec.CommandContext(ctx, "/bin/sh", args...)
+	wrapped.Env = cmd.Env
+	wrapped.Dir = cmd.Dir
+	return wrapped, nil
+}
+
+// hostEnv are variables that describe the agent's host and are not passed to
+// the container.
+var hostEnv = map[string]bool{
+	"GIT_ASKPASS":     true,
+	"GIT_SSH_COMMAND": true,
+	"HOME":            true,
+	"PATH":            true,
+	"SHELL":           true,
+	"SSH_AUTH_SOCK":   true,
+	"USER":            true,
+}
+
+// containerEnv returns the names of the variables in env that are not
+// inherited from the agent's environment. TERM is always included, since
+// it's set after the command was created.
+func containerEnv(env []string) []string {
+	inherited := map[string]bool{}
+	for _, kv := range os.Environ() {
+		inherited[kv] = true
+	}
+	keys := map[string]bool{"TERM": true}
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if inherited[kv] || hostEnv[key] || key == "" {
+			continue
+		}
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
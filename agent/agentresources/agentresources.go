@@ -0,0 +1,364 @@
+// Package agentresources collects the resource usage of the workspace an agent
+// runs in. When the agent runs in a container, usage is measured against the
+// limits of its cgroup (v1 or v2). Otherwise, the host values from /proc are
+// used.
+package agentresources
+
+import (
+	"bufio"
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	// userHZ is the unit of the CPU times in /proc/stat. It's 100 on all
+	// architectures supported by Linux.
+	userHZ = 100
+)
+
+// cgroupV1Dirs are the directories a cgroup v1 controller can be mounted at.
+// Most distributions mount cpu and cpuacct together.
+var cgroupV1Dirs = map[string][]string{
+	"cpu":     {cgroupRoot + "/cpu", cgroupRoot + "/cpu,cpuacct"},
+	"cpuacct": {cgroupRoot + "/cpuacct", cgroupRoot + "/cpu,cpuacct"},
+	"memory":  {cgroupRoot + "/memory"},
+}
+
+// Collector reads the resource usage of the workspace. CPU usage is averaged
+// over the time between two collections, so the first collection reports no
+// CPU usage.
+type Collector struct {
+	fs afero.Fs
+	// now and diskUsage are swapped in tests.
+	now       func() time.Time
+	diskUsage func(path string) (used, total int64, err error)
+
+	mu      sync.Mutex
+	prevCPU *cpuSample
+}
+
+type cpuSample struct {
+	at time.Time
+	// usage is the total CPU time in seconds.
+	usage float64
+}
+
+// New creates a collector that reads /proc and /sys/fs/cgroup from fs.
+func New(fs afero.Fs) *Collector {
+	return &Collector{
+		fs:        fs,
+		now:       time.Now,
+		diskUsage: diskUsage,
+	}
+}
+
+// Collect returns the current resource usage. diskPath is a path on the
+// filesystem to report disk usage for. An error is returned if the platform
+// does not expose resource usage.
+func (c *Collector) Collect(diskPath string) (*agentsdk.ResourceUsage, error) {
+	hostMemTotal, hostMemAvailable, err := c.hostMemory()
+	if err != nil {
+		return nil, xerrors.Errorf("read host memory: %w", err)
+	}
+	usage := &agentsdk.ResourceUsage{
+		MemoryUsedBytes:  hostMemTotal - hostMemAvailable,
+		MemoryLimitBytes: hostMemTotal,
+	}
+	if used, limit, ok := c.cgroupMemory(); ok {
+		usage.MemoryUsedBytes = used
+		// The limit of a cgroup without a limit is a large sentinel value.
+		if limit > 0 && limit < hostMemTotal {
+			usage.MemoryLimitBytes = limit
+		}
+	}
+
+	usage.CPULimitCores = c.cpuLimit()
+	usage.CPUUsedCores = c.cpuUsed()
+
+	if diskPath != "" {
+		// Disk usage is best-effort, the directory may not exist yet.
+		used, total, err := c.diskUsage(diskPath)
+		if err == nil {
+			usage.DiskUsedBytes = used
+			usage.DiskTotalBytes = total
+		}
+	}
+
+	usage.NetworkRxBytes, usage.NetworkTxBytes = c.network()
+	return usage, nil
+}
+
+// isCgroupV2 returns whether the unified cgroup hierarchy is mounted.
+func (c *Collector) isCgroupV2() bool {
+	_, err := c.fs.Stat(cgroupRoot + "/cgroup.controllers")
+	return err == nil
+}
+
+// readCgroupV1 reads a file of a cgroup v1 controller.
+func (c *Collector) readCgroupV1(controller, name string) (string, bool) {
+	for _, dir := range cgroupV1Dirs[controller] {
+		data, err := afero.ReadFile(c.fs, dir+"/"+name)
+		if err == nil {
+			return strings.TrimSpace(string(data)), true
+		}
+	}
+	return "", false
+}
+
+func (c *Collector) readCgroupV2(name string) (string, bool) {
+	data, err := afero.ReadFile(c.fs, cgroupRoot+"/"+name)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// cgroupMemory returns the memory usage and limit of the cgroup. Reclaimable
+// page cache is excluded from the usage, which matches the working set
+// reported by container runtimes. A limit of zero means there's no limit.
+func (c *Collector) cgroupMemory() (used, limit int64, ok bool) {
+	var (
+		current, limitStr, stat string
+		inactiveFileKey         string
+	)
+	if c.isCgroupV2() {
+		// The root cgroup doesn't have memory.current, so this fails when
+		// the agent doesn't run in a container.
+		if current, ok = c.readCgroupV2("memory.current"); !ok {
+			return 0, 0, false
+		}
+		limitStr, _ = c.readCgroupV2("memory.max")
+		stat, _ = c.readCgroupV2("memory.stat")
+		inactiveFileKey = "inactive_file"
+	} else {
+		if current, ok = c.readCgroupV1("memory", "memory.usage_in_bytes"); !ok {
+			return 0, 0, false
+		}
+		limitStr, _ = c.readCgroupV1("memory", "memory.limit_in_bytes")
+		stat, _ = c.readCgroupV1("memory", "memory.stat")
+		inactiveFileKey = "total_inactive_file"
+	}
+
+	used, err := strconv.ParseInt(current, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if inactiveFile, ok := parseKeyValues(stat)[inactiveFileKey]; ok && inactiveFile < used {
+		used -= inactiveFile
+	}
+	// "max" in cgroup v2 means there's no limit.
+	limit, err = strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		limit = 0
+	}
+	return used, limit, true
+}
+
+// cpuLimit returns the CPU quota of the cgroup in cores, or the number of host
+// CPUs if there's no quota.
+func (c *Collector) cpuLimit() float64 {
+	if c.isCgroupV2() {
+		// Formatted as "$MAX $PERIOD", where $MAX is "max" without a quota.
+		if cpuMax, ok := c.readCgroupV2("cpu.max"); ok {
+			fields := strings.Fields(cpuMax)
+			if len(fields) == 2 {
+				quota, qerr := strconv.ParseFloat(fields[0], 64)
+				period, perr := strconv.ParseFloat(fields[1], 64)
+				if qerr == nil && perr == nil && quota > 0 && period > 0 {
+					return quota / period
+				}
+			}
+		}
+	} else {
+		// The quota is -1 without a limit.
+		quotaStr, qok := c.readCgroupV1("cpu", "cpu.cfs_quota_us")
+		periodStr, pok := c.readCgroupV1("cpu", "cpu.cfs_period_us")
+		if qok && pok {
+			quota, qerr := strconv.ParseFloat(quotaStr, 64)
+			period, perr := strconv.ParseFloat(periodStr, 64)
+			if qerr == nil && perr == nil && quota > 0 && period > 0 {
+				return quota / period
+			}
+		}
+	}
+
+	data, err := afero.ReadFile(c.fs, "/proc/stat")
+	if err == nil {
+		var cpus int
+		for _, line := range strings.Split(string(data), "\n") {
+			// The aggregate line is "cpu", the per-CPU lines are "cpuN".
+			if strings.HasPrefix(line, "cpu") && !strings.HasPrefix(line, "cpu ") {
+				cpus++
+			}
+		}
+		if cpus > 0 {
+			return float64(cpus)
+		}
+	}
+	return float64(runtime.NumCPU())
+}
+
+// cpuUsed returns the average number of cores used since the previous call.
+func (c *Collector) cpuUsed() float64 {
+	usage, ok := c.cpuUsage()
+	if !ok {
+		return 0
+	}
+	sample := &cpuSample{at: c.now(), usage: usage}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev := c.prevCPU
+	c.prevCPU = sample
+	if prev == nil {
+		return 0
+	}
+	elapsed := sample.at.Sub(prev.at).Seconds()
+	if elapsed <= 0 || sample.usage < prev.usage {
+		return 0
+	}
+	return (sample.usage - prev.usage) / elapsed
+}
+
+// cpuUsage returns the total CPU time used by the cgroup, or the host if the
+// agent doesn't run in a container, in seconds.
+func (c *Collector) cpuUsage() (float64, bool) {
+	if c.isCgroupV2() {
+		if stat, ok := c.readCgroupV2("cpu.stat"); ok {
+			// The root cgroup reports usage_usec too, but it excludes
+			// time that isn't attributed to a cgroup. /proc/stat is
+			// more accurate for the host.
+			if _, err := c.fs.Stat(cgroupRoot + "/memory.current"); err == nil {
+				if usec, ok := parseKeyValues(stat)["usage_usec"]; ok {
+					return float64(usec) / 1e6, true
+				}
+			}
+		}
+	} else if usage, ok := c.readCgroupV1("cpuacct", "cpuacct.usage"); ok {
+		if nsec, err := strconv.ParseInt(usage, 10, 64); err == nil {
+			return float64(nsec) / 1e9, true
+		}
+	}
+
+	data, err := afero.ReadFile(c.fs, "/proc/stat")
+	if err != nil {
+		return 0, false
+	}
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) < 8 || fields[0] != "cpu" {
+		return 0, false
+	}
+	// Fields are user, nice, system, idle, iowait, irq, softirq, steal...
+	// Idle and iowait aren't usage.
+	var jiffies int64
+	for i, field := range fields[1:] {
+		if i == 3 || i == 4 {
+			continue
+		}
+		// Guest time is already included in user and nice.
+		if i >= 8 {
+			break
+		}
+		value, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		jiffies += value
+	}
+	return float64(jiffies) / userHZ, true
+}
+
+// hostMemory returns the total and available memory of the host in bytes.
+func (c *Collector) hostMemory() (total, available int64, err error) {
+	data, err := afero.ReadFile(c.fs, "/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	var foundTotal, foundAvailable bool
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// Lines are formatted as "MemTotal:       16318404 kB".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) == 3 && fields[2] == "kB" {
+			value *= 1024
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total, foundTotal = value, true
+		case "MemAvailable:":
+			available, foundAvailable = value, true
+		}
+	}
+	if !foundTotal || !foundAvailable {
+		return 0, 0, xerrors.New("MemTotal or MemAvailable missing from /proc/meminfo")
+	}
+	return total, available, nil
+}
+
+// network returns the bytes received and transmitted by all interfaces except
+// loopback. /proc/net/dev is namespaced, so in a container this only includes
+// the interfaces of the container.
+func (c *Collector) network() (rx, tx int64) {
+	data, err := afero.ReadFile(c.fs, "/proc/net/dev")
+	if err != nil {
+		return 0, 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		// The first two lines are headers, interface lines are formatted
+		// as "  eth0: $RX_BYTES $RX_PACKETS ... $TX_BYTES $TX_PACKETS ...".
+		name, counters, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(name) == "lo" {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			continue
+		}
+		rxBytes, rerr := strconv.ParseInt(fields[0], 10, 64)
+		txBytes, terr := strconv.ParseInt(fields[8], 10, 64)
+		if rerr != nil || terr != nil {
+			continue
+		}
+		rx += rxBytes
+		tx += txBytes
+	}
+	return rx, tx
+}
+
+// parseKeyValues parses the "key value" lines of cgroup stat files.
+func parseKeyValues(data string) map[string]int64 {
+	values := make(map[string]int64)
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		values[key] = parsed
+	}
+	return values
+}
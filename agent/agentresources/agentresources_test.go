@@ -0,0 +1,173 @@
+package agentresources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+const (
+	procMeminfo = `MemTotal:       16384000 kB
+MemFree:         1024000 kB
+MemAvailable:    8192000 kB
+`
+	procNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0:    2000      20    0    0    0     0          0         0     3000      30    0    0    0     0       0          0
+  eth1:     500       5    0    0    0     0          0         0      700       7    0    0    0     0       0          0
+`
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Host", func(t *testing.T) {
+		t.Parallel()
+		collector, fs, clock := newCollector(t)
+		writeFile(t, fs, "/proc/stat", `cpu  100 0 100 1000 50 0 0 0 0 0
+cpu0 50 0 50 500 25 0 0 0 0 0
+cpu1 50 0 50 500 25 0 0 0 0 0
+`)
+
+		usage, err := collector.Collect("/home/coder")
+		require.NoError(t, err)
+		require.EqualValues(t, 2, usage.CPULimitCores)
+		// The first collection has nothing to compare against.
+		require.EqualValues(t, 0, usage.CPUUsedCores)
+		require.EqualValues(t, 8192000*1024, usage.MemoryUsedBytes)
+		require.EqualValues(t, 16384000*1024, usage.MemoryLimitBytes)
+		require.EqualValues(t, 10, usage.DiskUsedBytes)
+		require.EqualValues(t, 100, usage.DiskTotalBytes)
+		// Loopback is excluded.
+		require.EqualValues(t, 2500, usage.NetworkRxBytes)
+		require.EqualValues(t, 3700, usage.NetworkTxBytes)
+
+		// 300 jiffies (3 seconds) of usage over 2 seconds is 1.5 cores.
+		writeFile(t, fs, "/proc/stat", `cpu  250 0 250 1100 60 0 0 0 0 0
+cpu0 125 0 125 550 30 0 0 0 0 0
+cpu1 125 0 125 550 30 0 0 0 0 0
+`)
+		*clock = clock.Add(2 * time.Second)
+		usage, err = collector.Collect("/home/coder")
+		require.NoError(t, err)
+		require.InDelta(t, 1.5, usage.CPUUsedCores, 0.001)
+	})
+
+	t.Run("CgroupV2", func(t *testing.T) {
+		t.Parallel()
+		collector, fs, clock := newCollector(t)
+		writeFile(t, fs, "/sys/fs/cgroup/cgroup.controllers", "cpu memory")
+		writeFile(t, fs, "/sys/fs/cgroup/cpu.max", "250000 100000")
+		writeFile(t, fs, "/sys/fs/cgroup/cpu.stat", "usage_usec 1000000\nuser_usec 800000\n")
+		writeFile(t, fs, "/sys/fs/cgroup/memory.current", "3000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory.max", "4096")
+		writeFile(t, fs, "/sys/fs/cgroup/memory.stat", "anon 2000\ninactive_file 1000\n")
+
+		usage, err := collector.Collect("")
+		require.NoError(t, err)
+		require.EqualValues(t, 2.5, usage.CPULimitCores)
+		require.EqualValues(t, 2000, usage.MemoryUsedBytes)
+		require.EqualValues(t, 4096, usage.MemoryLimitBytes)
+		require.EqualValues(t, 0, usage.DiskTotalBytes)
+
+		writeFile(t, fs, "/sys/fs/cgroup/cpu.stat", "usage_usec 3000000\nuser_usec 2000000\n")
+		*clock = clock.Add(4 * time.Second)
+		usage, err = collector.Collect("")
+		require.NoError(t, err)
+		require.InDelta(t, 0.5, usage.CPUUsedCores, 0.001)
+	})
+
+	t.Run("CgroupV2Unlimited", func(t *testing.T) {
+		t.Parallel()
+		collector, fs, _ := newCollector(t)
+		writeFile(t, fs, "/sys/fs/cgroup/cgroup.controllers", "cpu memory")
+		writeFile(t, fs, "/sys/fs/cgroup/cpu.max", "max 100000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory.current", "3000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory.max", "max")
+		writeFile(t, fs, "/proc/stat", "cpu  1 0 1 1 1 0 0 0 0 0\ncpu0 1 0 1 1 1 0 0 0 0 0\n")
+
+		usage, err := collector.Collect("")
+		require.NoError(t, err)
+		// Limits fall back to the host.
+		require.EqualValues(t, 1, usage.CPULimitCores)
+		require.EqualValues(t, 3000, usage.MemoryUsedBytes)
+		require.EqualValues(t, 16384000*1024, usage.MemoryLimitBytes)
+	})
+
+	t.Run("CgroupV1", func(t *testing.T) {
+		t.Parallel()
+		collector, fs, clock := newCollector(t)
+		writeFile(t, fs, "/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us", "50000")
+		writeFile(t, fs, "/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us", "100000")
+		writeFile(t, fs, "/sys/fs/cgroup/cpu,cpuacct/cpuacct.usage", "1000000000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory/memory.usage_in_bytes", "5000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory/memory.limit_in_bytes", "8192")
+		writeFile(t, fs, "/sys/fs/cgroup/memory/memory.stat", "cache 2000\ntotal_inactive_file 1500\n")
+
+		usage, err := collector.Collect("")
+		require.NoError(t, err)
+		require.EqualValues(t, 0.5, usage.CPULimitCores)
+		require.EqualValues(t, 3500, usage.MemoryUsedBytes)
+		require.EqualValues(t, 8192, usage.MemoryLimitBytes)
+
+		writeFile(t, fs, "/sys/fs/cgroup/cpu,cpuacct/cpuacct.usage", "1500000000")
+		*clock = clock.Add(time.Second)
+		usage, err = collector.Collect("")
+		require.NoError(t, err)
+		require.InDelta(t, 0.5, usage.CPUUsedCores, 0.001)
+	})
+
+	t.Run("CgroupV1Unlimited", func(t *testing.T) {
+		t.Parallel()
+		collector, fs, _ := newCollector(t)
+		writeFile(t, fs, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us", "-1")
+		writeFile(t, fs, "/sys/fs/cgroup/cpu/cpu.cfs_period_us", "100000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory/memory.usage_in_bytes", "5000")
+		writeFile(t, fs, "/sys/fs/cgroup/memory/memory.limit_in_bytes", "9223372036854771712")
+		writeFile(t, fs, "/proc/stat", "cpu  1 0 1 1 1 0 0 0 0 0\ncpu0 1 0 1 1 1 0 0 0 0 0\ncpu1 1 0 1 1 1 0 0 0 0 0\ncpu2 1 0 1 1 1 0 0 0 0 0\n")
+
+		usage, err := collector.Collect("")
+		require.NoError(t, err)
+		require.EqualValues(t, 3, usage.CPULimitCores)
+		require.EqualValues(t, 16384000*1024, usage.MemoryLimitBytes)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Parallel()
+		collector := New(afero.NewMemMapFs())
+		_, err := collector.Collect("")
+		require.Error(t, err)
+	})
+}
+
+// newCollector returns a collector with host memory and network files, a fake
+// clock and a fake disk of 100 bytes with 10 bytes used at /home/coder.
+func newCollector(t *testing.T) (*Collector, afero.Fs, *time.Time) {
+	t.Helper()
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/proc/meminfo", procMeminfo)
+	writeFile(t, fs, "/proc/net/dev", procNetDev)
+
+	clock := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	collector := New(fs)
+	collector.now = func() time.Time {
+		return clock
+	}
+	collector.diskUsage = func(path string) (int64, int64, error) {
+		if path != "/home/coder" {
+			return 0, 0, xerrors.New("not found")
+		}
+		return 10, 100, nil
+	}
+	return collector, fs, &clock
+}
+
+func writeFile(t *testing.T, fs afero.Fs, name, content string) {
+	t.Helper()
+	err := afero.WriteFile(fs, name, []byte(content), 0o600)
+	require.NoError(t, err)
+}
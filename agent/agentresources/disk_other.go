@@ -0,0 +1,20 @@
+//go:build !windows
+
+package agentresources
+
+import "golang.org/x/sys/unix"
+
+// diskUsage returns the used and total bytes of the filesystem at path.
+func diskUsage(path string) (used, total int64, err error) {
+	var stat unix.Statfs_t
+	err = unix.Statfs(path, &stat)
+	if err != nil {
+		return 0, 0, err
+	}
+	// nolint:unconvert // The field types differ between platforms.
+	blockSize := int64(stat.Bsize)
+	total = int64(stat.Blocks) * blockSize
+	// Bfree includes the blocks reserved for root, Bavail doesn't.
+	used = total - int64(stat.Bfree)*blockSize
+	return used, total, nil
+}
@@ -0,0 +1,17 @@
+package agentresources
+
+import "golang.org/x/sys/windows"
+
+// diskUsage returns the used and total bytes of the volume at path.
+func diskUsage(path string) (used, total int64, err error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var free, totalBytes, totalFree uint64
+	err = windows.GetDiskFreeSpaceEx(pathPtr, &free, &totalBytes, &totalFree)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(totalBytes - totalFree), int64(totalBytes), nil
+}
@@ -2,6 +2,7 @@ package agentssh
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
@@ -27,6 +28,7 @@ import (
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/agent/usershell"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/pty"
 )
@@ -62,6 +64,16 @@ type Server struct {
 	Env        map[string]string
 	AgentToken func() string
 	Manifest   *atomic.Pointer[agentsdk.Manifest]
+	// RecordingDone is called with the recording of an interactive session
+	// once it ends. Sessions are only recorded when enabled in the manifest.
+	RecordingDone func(recording *Recording)
+	// WrapCommand runs the commands created by CreateCommand elsewhere, e.g.
+	// in a container, when set. The script is empty for a login shell.
+	WrapCommand func(ctx context.Context, cmd *exec.Cmd, script string) (*exec.Cmd, error)
+	// ForwardTCPHost returns the host that forwarded connections to the
+	// loopback address are dialed on, e.g. the address of the container that
+	// wrapped commands run in. They're rejected if it returns an empty host.
+	ForwardTCPHost func() string
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -93,8 +105,8 @@ func NewServer(ctx context.Context, logger slog.Logger, maxTimeout time.Duration
 
 	s.srv = &ssh.Server{
 		ChannelHandlers: map[string]ssh.ChannelHandler{
-			"direct-tcpip":                   ssh.DirectTCPIPHandler,
-			"direct-streamlocal@openssh.com": directStreamLocalHandler,
+			"direct-tcpip":                   s.directTCPIPHandler,
+			"direct-streamlocal@openssh.com": s.directStreamLocalHandler,
 			"session":                        ssh.DefaultSessionHandler,
 		},
 		ConnectionFailedCallback: func(_ net.Conn, err error) {
@@ -113,6 +125,9 @@ func NewServer(ctx context.Context, logger slog.Logger, maxTimeout time.Duration
 			return true
 		},
 		ReversePortForwardingCallback: func(ctx ssh.Context, bindHost string, bindPort uint32) bool {
+			if s.forwardingUnsupported(ctx, "reverse port forward") {
+				return false
+			}
 			// Allow reverse port forwarding all!
 			s.logger.Debug(ctx, "local port forward",
 				slog.F("bind-host", bindHost),
@@ -122,12 +137,23 @@ func NewServer(ctx context.Context, logger slog.Logger, maxTimeout time.Duration
 		RequestHandlers: map[string]ssh.RequestHandler{
 			"tcpip-forward":                          forwardHandler.HandleSSHRequest,
 			"cancel-tcpip-forward":                   forwardHandler.HandleSSHRequest,
-			"streamlocal-forward@openssh.com":        unixForwardHandler.HandleSSHRequest,
+			"streamlocal-forward@openssh.com":        s.streamLocalForwardHandler(unixForwardHandler),
 			"cancel-streamlocal-forward@openssh.com": unixForwardHandler.HandleSSHRequest,
 		},
+		// Connections over the Coder network are authenticated by coderd
+		// already, only connections to listeners served with
+		// ServeCertificateAuth must present a certificate.
+		ConnCallback: func(ctx ssh.Context, conn net.Conn) net.Conn {
+			if _, ok := conn.(certificateAuthConn); ok {
+				ctx.SetValue(contextKeyCertificateAuth, true)
+			}
+			return conn
+		},
+		PublicKeyHandler: s.certificateHandler,
 		ServerConfigCallback: func(ctx ssh.Context) *gossh.ServerConfig {
+			certificateAuth, _ := ctx.Value(contextKeyCertificateAuth).(bool)
 			return &gossh.ServerConfig{
-				NoClientAuth: true,
+				NoClientAuth: !certificateAuth,
 			}
 		},
 		SubsystemHandlers: map[string]ssh.SubsystemHandler{
@@ -139,6 +165,63 @@ func NewServer(ctx context.Context, logger slog.Logger, maxTimeout time.Duration
 	return s, nil
 }
 
+// forwardingUnsupported reports whether forwarding is rejected, since it would
+// reach the agent's host instead of the place wrapped commands run in. Only
+// local TCP forwarding can be routed there, see directTCPIPHandler.
+func (s *Server) forwardingUnsupported(ctx ssh.Context, kind string) bool {
+	if s.WrapCommand == nil {
+		return false
+	}
+	s.logger.Debug(ctx, "forwarding is unsupported for wrapped commands", slog.F("kind", kind))
+	return true
+}
+
+// directTCPIPHandler dials forwarded connections to the loopback address on
+// ForwardTCPHost, so they reach the ports of the place commands run in.
+func (s *Server) directTCPIPHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	if s.ForwardTCPHost == nil {
+		ssh.DirectTCPIPHandler(srv, conn, newChan, ctx)
+		return
+	}
+	var payload directTCPIPPayload
+	err := gossh.Unmarshal(newChan.ExtraData(), &payload)
+	if err != nil {
+		_ = newChan.Reject(gossh.ConnectionFailed, "could not parse direct-tcpip channel payload")
+		return
+	}
+	if !isLoopbackHost(payload.DestAddr) {
+		ssh.DirectTCPIPHandler(srv, conn, newChan, ctx)
+		return
+	}
+	host := s.ForwardTCPHost()
+	if host == "" {
+		_ = newChan.Reject(gossh.ConnectionFailed, "the container isn't running")
+		return
+	}
+	payload.DestAddr = host
+	ssh.DirectTCPIPHandler(srv, conn, rewrittenChannel{
+		NewChannel: newChan,
+		extraData:  gossh.Marshal(&payload),
+	}, ctx)
+}
+
+func (s *Server) directStreamLocalHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	if s.forwardingUnsupported(ctx, "direct-streamlocal") {
+		_ = newChan.Reject(gossh.Prohibited, "forwarding is unsupported for wrapped commands")
+		return
+	}
+	directStreamLocalHandler(srv, conn, newChan, ctx)
+}
+
+func (s *Server) streamLocalForwardHandler(handler *forwardedUnixHandler) ssh.RequestHandler {
+	return func(ctx ssh.Context, srv *ssh.Server, req *gossh.Request) (bool, []byte) {
+		if s.forwardingUnsupported(ctx, "streamlocal forward") {
+			return false, nil
+		}
+		return handler.HandleSSHRequest(ctx, srv, req)
+	}
+}
+
 type ConnStats struct {
 	Sessions  int64
 	VSCode    int64
@@ -166,6 +249,13 @@ func (s *Server) sessionHandler(session ssh.Session) {
 	switch ss := session.Subsystem(); ss {
 	case "":
 	case "sftp":
+		if s.WrapCommand != nil {
+			// The SFTP server would serve the files of the agent's host
+			// instead of the ones the commands run with.
+			s.logger.Debug(ctx, "sftp is unsupported for wrapped commands")
+			_ = session.Exit(1)
+			return
+		}
 		s.sftpHandler(session)
 		return
 	default:
@@ -251,6 +341,17 @@ func (s *Server) sessionStart(session ssh.Session) (retErr error) {
 
 		cmd.Env = append(cmd.Env, fmt.Sprintf("TERM=%s", sshPty.Term))
 
+		var output io.Writer = session
+		recording := s.StartRecording(agentsdk.SessionRecordingTypeSSH, RecordingSource{
+			RemoteAddr: session.RemoteAddr(),
+		}, uint16(sshPty.Window.Width), uint16(sshPty.Window.Height), sshPty.Term)
+		if recording != nil {
+			// Deferred before closing the pty, so the recording is only
+			// done once all output has been copied.
+			defer s.RecordingDone(recording)
+			output = io.MultiWriter(session, recording)
+		}
+
 		// The pty package sets `SSH_TTY` on supported platforms.
 		ptty, process, err := pty.Start(cmd, pty.WithPTYOption(
 			pty.WithSSHRequest(sshPty),
@@ -272,6 +373,9 @@ func (s *Server) sessionStart(session ssh.Session) (retErr error) {
 		}()
 		go func() {
 			for win := range windowSize {
+				if recording != nil {
+					recording.Resize(uint16(win.Width), uint16(win.Height))
+				}
 				resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
 				// If the pty is closed, then command has exited, no need to log.
 				if resizeErr != nil && !errors.Is(resizeErr, pty.ErrClosed) {
@@ -315,7 +419,7 @@ func (s *Server) sessionStart(session ssh.Session) (retErr error) {
 			stdout := ptyOutput()
 			defer stdout.Close()
 
-			_, _ = io.Copy(session, stdout)
+			_, _ = io.Copy(output, stdout)
 		}()
 		<-outputCopyStarted
 
@@ -348,6 +452,22 @@ func (s *Server) sessionStart(session ssh.Session) (retErr error) {
 	return cmd.Wait()
 }
 
+// StartRecording starts recording a session if session recording is enabled,
+// otherwise it returns nil. The caller must pass the recording to
+// RecordingDone once the session ends.
+func (s *Server) StartRecording(typ agentsdk.SessionRecordingType, source RecordingSource, width, height uint16, term string) *Recording {
+	if s.RecordingDone == nil {
+		return nil
+	}
+	manifest := s.Manifest.Load()
+	if manifest == nil || !manifest.SessionRecording {
+		return nil
+	}
+	recording := NewRecording(typ, width, height, term)
+	recording.source = source
+	return recording
+}
+
 type readNopCloser struct{ io.Reader }
 
 // Close implements io.Closer.
@@ -497,10 +617,25 @@ func (s *Server) CreateCommand(ctx context.Context, script string, env []string)
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envKey, value))
 	}
 
+	if s.WrapCommand != nil {
+		return s.WrapCommand(ctx, cmd, script)
+	}
 	return cmd, nil
 }
 
 func (s *Server) Serve(l net.Listener) error {
+	return s.serve(l, false)
+}
+
+// ServeCertificateAuth serves connections that must authenticate with a
+// user certificate issued by the certificate authority in the manifest for
+// the workspace of the agent. It's used for listeners outside of the Coder
+// network, which are reachable by standard SSH clients.
+func (s *Server) ServeCertificateAuth(l net.Listener) error {
+	return s.serve(l, true)
+}
+
+func (s *Server) serve(l net.Listener, certificateAuth bool) error {
 	defer l.Close()
 
 	s.trackListener(l, true)
@@ -510,10 +645,64 @@ func (s *Server) Serve(l net.Listener) error {
 		if err != nil {
 			return err
 		}
+		if certificateAuth {
+			conn = certificateAuthConn{Conn: conn}
+		}
 		go s.handleConn(l, conn)
 	}
 }
 
+// certificateAuthConn marks connections that must authenticate with a
+// certificate.
+type certificateAuthConn struct {
+	net.Conn
+}
+
+type contextKey struct{ name string }
+
+var contextKeyCertificateAuth = &contextKey{"certificate-auth"}
+
+// certificateHandler accepts user certificates signed by the certificate
+// authority in the manifest that are valid for the workspace of the agent.
+func (s *Server) certificateHandler(ctx ssh.Context, key ssh.PublicKey) bool {
+	logger := s.logger.With(slog.F("remote_addr", ctx.RemoteAddr()))
+	manifest := s.Manifest.Load()
+	if manifest == nil || manifest.SSHCAPublicKey == "" {
+		logger.Warn(ctx, "rejected ssh certificate, certificate authority is unknown")
+		return false
+	}
+	authority, _, _, _, err := gossh.ParseAuthorizedKey([]byte(manifest.SSHCAPublicKey))
+	if err != nil {
+		logger.Error(ctx, "parse ssh certificate authority", slog.Error(err))
+		return false
+	}
+	cert, ok := key.(*gossh.Certificate)
+	if !ok {
+		logger.Debug(ctx, "rejected ssh public key without certificate")
+		return false
+	}
+	checker := &gossh.CertChecker{
+		IsUserAuthority: func(auth gossh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), authority.Marshal())
+		},
+	}
+	if !checker.IsUserAuthority(cert.SignatureKey) {
+		logger.Warn(ctx, "rejected ssh certificate from unknown authority", slog.F("key_id", cert.KeyId))
+		return false
+	}
+	if cert.CertType != gossh.UserCert {
+		logger.Warn(ctx, "rejected ssh certificate that isn't a user certificate", slog.F("key_id", cert.KeyId))
+		return false
+	}
+	err = checker.CheckCert(codersdk.WorkspaceSSHCertificatePrincipal(manifest.WorkspaceID), cert)
+	if err != nil {
+		logger.Warn(ctx, "rejected ssh certificate", slog.F("key_id", cert.KeyId), slog.Error(err))
+		return false
+	}
+	logger.Info(ctx, "accepted ssh certificate", slog.F("key_id", cert.KeyId))
+	return true
+}
+
 func (s *Server) handleConn(l net.Listener, c net.Conn) {
 	defer c.Close()
 
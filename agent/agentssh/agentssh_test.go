@@ -5,11 +5,15 @@ package agentssh_test
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"net"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -19,6 +23,7 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/pty/ptytest"
 )
@@ -117,17 +122,157 @@ func TestNewServer_CloseActiveConnections(t *testing.T) {
 	wg.Wait()
 }
 
+func TestNewServer_CertificateAuth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	s, err := agentssh.NewServer(ctx, logger, 0)
+	require.NoError(t, err)
+
+	authority := newSigner(t)
+	workspaceID := uuid.New()
+	s.AgentToken = func() string { return "" }
+	s.Manifest = atomic.NewPointer(&agentsdk.Manifest{
+		WorkspaceID:    workspaceID,
+		SSHCAPublicKey: string(ssh.MarshalAuthorizedKey(authority.PublicKey())),
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := s.ServeCertificateAuth(ln)
+		assert.Error(t, err) // Server is closed.
+	}()
+	// Subtests run in parallel, so close the server once they're done.
+	t.Cleanup(func() {
+		err := s.Close()
+		assert.NoError(t, err)
+		<-done
+	})
+
+	now := time.Now()
+	validCert := func() *ssh.Certificate {
+		return &ssh.Certificate{
+			CertType:        ssh.UserCert,
+			KeyId:           "testuser",
+			ValidPrincipals: []string{codersdk.WorkspaceSSHCertificatePrincipal(workspaceID)},
+			ValidAfter:      uint64(now.Add(-time.Minute).Unix()),
+			ValidBefore:     uint64(now.Add(time.Hour).Unix()),
+		}
+	}
+
+	for _, tt := range []struct {
+		name      string
+		modify    func(cert *ssh.Certificate)
+		authority ssh.Signer
+		ok        bool
+	}{
+		{
+			name: "Valid",
+			ok:   true,
+		},
+		{
+			name: "OtherWorkspace",
+			modify: func(cert *ssh.Certificate) {
+				cert.ValidPrincipals = []string{codersdk.WorkspaceSSHCertificatePrincipal(uuid.New())}
+			},
+		},
+		{
+			name: "Expired",
+			modify: func(cert *ssh.Certificate) {
+				cert.ValidBefore = uint64(now.Add(-time.Second).Unix())
+			},
+		},
+		{
+			name: "HostCertificate",
+			modify: func(cert *ssh.Certificate) {
+				cert.CertType = ssh.HostCert
+			},
+		},
+		{
+			name:      "OtherAuthority",
+			authority: newSigner(t),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := newSigner(t)
+			cert := validCert()
+			cert.Key = key.PublicKey()
+			if tt.modify != nil {
+				tt.modify(cert)
+			}
+			signWith := authority
+			if tt.authority != nil {
+				signWith = tt.authority
+			}
+			err := cert.SignCert(rand.Reader, signWith)
+			require.NoError(t, err)
+			certSigner, err := ssh.NewCertSigner(cert, key)
+			require.NoError(t, err)
+
+			c, err := sshClientWithConfig(t, ln.Addr().String(), &ssh.ClientConfig{
+				Auth: []ssh.AuthMethod{ssh.PublicKeys(certSigner)},
+			})
+			if !tt.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			sess, err := c.NewSession()
+			require.NoError(t, err)
+			out, err := sess.Output("echo hello")
+			require.NoError(t, err)
+			require.Equal(t, "hello", strings.TrimSpace(string(out)))
+		})
+	}
+
+	t.Run("NoCertificate", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := sshClientWithConfig(t, ln.Addr().String(), &ssh.ClientConfig{})
+		require.Error(t, err)
+
+		_, err = sshClientWithConfig(t, ln.Addr().String(), &ssh.ClientConfig{
+			Auth: []ssh.AuthMethod{ssh.PublicKeys(newSigner(t))},
+		})
+		require.Error(t, err)
+	})
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return signer
+}
+
 func sshClient(t *testing.T, addr string) *ssh.Client {
+	c, err := sshClientWithConfig(t, addr, &ssh.ClientConfig{})
+	require.NoError(t, err)
+	return c
+}
+
+func sshClientWithConfig(t *testing.T, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
 	conn, err := net.Dial("tcp", addr)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		_ = conn.Close()
 	})
 
-	sshConn, channels, requests, err := ssh.NewClientConn(conn, "localhost:22", &ssh.ClientConfig{
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // This is a test.
-	})
-	require.NoError(t, err)
+	config.HostKeyCallback = ssh.InsecureIgnoreHostKey() //nolint:gosec // This is a test.
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, "localhost:22", config)
+	if err != nil {
+		return nil, err
+	}
 	t.Cleanup(func() {
 		_ = sshConn.Close()
 	})
@@ -135,5 +280,5 @@ func sshClient(t *testing.T, addr string) *ssh.Client {
 	t.Cleanup(func() {
 		_ = c.Close()
 	})
-	return c
+	return c, nil
 }
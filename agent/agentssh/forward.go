@@ -201,3 +201,30 @@ func directStreamLocalHandler(_ *ssh.Server, _ *gossh.ServerConn, newChan gossh.
 
 	Bicopy(ctx, ch, dconn)
 }
+
+// directTCPIPPayload is the payload of direct-tcpip channels, see RFC 4254
+// section 7.2.
+type directTCPIPPayload struct {
+	DestAddr   string
+	DestPort   uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+// rewrittenChannel replaces the payload of a channel request.
+type rewrittenChannel struct {
+	gossh.NewChannel
+	extraData []byte
+}
+
+func (c rewrittenChannel) ExtraData() []byte {
+	return c.extraData
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
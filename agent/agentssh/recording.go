@@ -0,0 +1,137 @@
+package agentssh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// RecordingSource identifies the connection that opened a recorded session.
+type RecordingSource struct {
+	RemoteAddr        net.Addr
+	ReconnectingPTYID uuid.UUID
+}
+
+// Recording records the output of a terminal session in the asciicast v2
+// format, see https://docs.asciinema.org/manual/asciicast/v2/. Writes never
+// fail, so it can be used alongside the session output.
+type Recording struct {
+	mu        sync.Mutex
+	typ       agentsdk.SessionRecordingType
+	source    RecordingSource
+	startedAt time.Time
+	buf       bytes.Buffer
+	// partial holds the start of a multi-byte character split between
+	// writes, events must contain valid UTF-8.
+	partial   []byte
+	truncated bool
+	now       func() time.Time
+}
+
+type recordingHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// NewRecording starts recording a terminal of the given size.
+func NewRecording(typ agentsdk.SessionRecordingType, width, height uint16, term string) *Recording {
+	return newRecording(typ, width, height, term, time.Now)
+}
+
+func newRecording(typ agentsdk.SessionRecordingType, width, height uint16, term string, now func() time.Time) *Recording {
+	r := &Recording{
+		typ:       typ,
+		startedAt: now(),
+		now:       now,
+	}
+	header := recordingHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.startedAt.Unix(),
+	}
+	if term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	// Marshaling the header can't fail.
+	data, _ := json.Marshal(header)
+	_, _ = r.buf.Write(data)
+	_ = r.buf.WriteByte('\n')
+	return r
+}
+
+// Write records p as output of the terminal.
+func (r *Recording) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data := append(r.partial, p...)
+	r.partial = nil
+	// Hold back a trailing incomplete character until the rest of it is
+	// written.
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			r.partial = append([]byte(nil), data[i:]...)
+			data = data[:i]
+		}
+		break
+	}
+	if len(data) > 0 {
+		r.event("o", string(data))
+	}
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size.
+func (r *Recording) Resize(width, height uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// event appends an event to the recording, r.mu must be held.
+func (r *Recording) event(code, data string) {
+	if r.truncated {
+		return
+	}
+	elapsed := r.now().Sub(r.startedAt).Seconds()
+	// Marshaling strings and floats can't fail.
+	line, _ := json.Marshal([]any{elapsed, code, data})
+	if r.buf.Len()+len(line)+1 > agentsdk.MaxSessionRecordingSize {
+		r.truncated = true
+		return
+	}
+	_, _ = r.buf.Write(line)
+	_ = r.buf.WriteByte('\n')
+}
+
+// Request ends the recording and returns it as a request to upload it.
+func (r *Recording) Request() agentsdk.PostSessionRecordingRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req := agentsdk.PostSessionRecordingRequest{
+		Type:              r.typ,
+		StartedAt:         r.startedAt,
+		EndedAt:           r.now(),
+		Truncated:         r.truncated,
+		ReconnectingPTYID: r.source.ReconnectingPTYID,
+		Recording:         bytes.Clone(r.buf.Bytes()),
+	}
+	if r.source.RemoteAddr != nil {
+		req.RemoteAddr = r.source.RemoteAddr.String()
+	}
+	return req
+}
@@ -0,0 +1,63 @@
+package agentssh_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+func TestRecording(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Events", func(t *testing.T) {
+		t.Parallel()
+		recording := agentssh.NewRecording(agentsdk.SessionRecordingTypeSSH, 80, 24, "xterm")
+		// Split a multi-byte character between writes.
+		_, _ = recording.Write([]byte("hello \xe2\x82"))
+		_, _ = recording.Write([]byte("\xac"))
+		recording.Resize(100, 30)
+
+		req := recording.Request()
+		require.Equal(t, agentsdk.SessionRecordingTypeSSH, req.Type)
+		require.False(t, req.Truncated)
+		lines := strings.Split(strings.TrimSpace(string(req.Recording)), "\n")
+		require.Len(t, lines, 4)
+
+		var header map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+		require.EqualValues(t, 2, header["version"])
+		require.EqualValues(t, 80, header["width"])
+		require.EqualValues(t, 24, header["height"])
+
+		events := make([][]any, 0, 3)
+		for _, line := range lines[1:] {
+			var event []any
+			require.NoError(t, json.Unmarshal([]byte(line), &event))
+			require.Len(t, event, 3)
+			events = append(events, event)
+		}
+		require.Equal(t, []any{"o", "hello "}, events[0][1:])
+		require.Equal(t, []any{"o", "€"}, events[1][1:])
+		require.Equal(t, []any{"r", "100x30"}, events[2][1:])
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+		recording := agentssh.NewRecording(agentsdk.SessionRecordingTypeSSH, 80, 24, "")
+		chunk := bytes.Repeat([]byte("a"), 1<<20)
+		for i := 0; i < 11; i++ {
+			n, err := recording.Write(chunk)
+			require.NoError(t, err)
+			require.Equal(t, len(chunk), n)
+		}
+		req := recording.Request()
+		require.True(t, req.Truncated)
+		require.LessOrEqual(t, len(req.Recording), agentsdk.MaxSessionRecordingSize)
+	})
+}
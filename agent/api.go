@@ -29,9 +29,42 @@ func (a *agent) apiHandler() http.Handler {
 	lp := &listeningPortsHandler{ignorePorts: cpy}
 	r.Get("/api/v0/listening-ports", lp.handler)
 
+	if a.devcontainer != nil {
+		// The files API would act on the agent's host instead of the dev
+		// container that commands run in, like sftp.
+		r.HandleFunc("/api/v0/files/*", func(rw http.ResponseWriter, r *http.Request) {
+			httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Files can't be transferred to or from dev container agents.",
+			})
+		})
+	} else {
+		files := &filesHandler{fs: a.filesystem}
+		r.Route("/api/v0/files", files.routes)
+	}
+
+	r.Post("/api/v0/debug/logs/flush", a.debugLogsFlushHandler)
+
+	r.Get("/api/v0/reconnecting-ptys", a.reconnectingPTYsHandler)
+
 	return r
 }
 
+// reconnectingPTYsHandler lists the sessions of the reconnecting PTY backend.
+func (a *agent) reconnectingPTYsHandler(rw http.ResponseWriter, r *http.Request) {
+	sessions, err := a.reconnectingPTYBackend.Sessions(r.Context())
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Could not list reconnecting PTY sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentReconnectingPTYsResponse{
+		Sessions: sessions,
+	})
+}
+
 type listeningPortsHandler struct {
 	mut         sync.Mutex
 	ports       []codersdk.WorkspaceAgentListeningPort
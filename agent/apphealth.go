@@ -1,12 +1,17 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"net"
 	"net/http"
+	"os/exec"
+	"regexp"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -21,11 +26,15 @@ type WorkspaceAgentApps func(context.Context) ([]codersdk.WorkspaceApp, error)
 // PostWorkspaceAgentAppHealth updates the workspace app health.
 type PostWorkspaceAgentAppHealth func(context.Context, agentsdk.PostAppHealthsRequest) error
 
+// CreateHealthcheckCommand creates the command that runs the script of a
+// command healthcheck.
+type CreateHealthcheckCommand func(ctx context.Context, script string) (*exec.Cmd, error)
+
 // WorkspaceAppHealthReporter is a function that checks and reports the health of the workspace apps until the passed context is canceled.
 type WorkspaceAppHealthReporter func(ctx context.Context)
 
 // NewWorkspaceAppHealthReporter creates a WorkspaceAppHealthReporter that reports app health to coderd.
-func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.WorkspaceApp, postWorkspaceAgentAppHealth PostWorkspaceAgentAppHealth) WorkspaceAppHealthReporter {
+func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.WorkspaceApp, postWorkspaceAgentAppHealth PostWorkspaceAgentAppHealth, createCommand CreateHealthcheckCommand) WorkspaceAppHealthReporter {
 	runHealthcheckLoop := func(ctx context.Context) error {
 		// no need to run this loop if no apps for this workspace.
 		if len(apps) == 0 {
@@ -65,27 +74,7 @@ func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.Workspace
 						return
 					case <-t.C:
 					}
-					// we set the http timeout to the healthcheck interval to prevent getting too backed up.
-					client := &http.Client{
-						Timeout: time.Duration(app.Healthcheck.Interval) * time.Second,
-					}
-					err := func() error {
-						req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.Healthcheck.URL, nil)
-						if err != nil {
-							return err
-						}
-						res, err := client.Do(req)
-						if err != nil {
-							return err
-						}
-						// successful healthcheck is a non-5XX status code
-						_ = res.Body.Close()
-						if res.StatusCode >= http.StatusInternalServerError {
-							return xerrors.Errorf("error status code: %d", res.StatusCode)
-						}
-
-						return nil
-					}()
+					err := checkAppHealth(ctx, app, createCommand)
 					if err != nil {
 						mu.Lock()
 						if failures[app.ID] < int(app.Healthcheck.Threshold) {
@@ -155,7 +144,108 @@ func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.Workspace
 }
 
 func shouldStartTicker(app codersdk.WorkspaceApp) bool {
-	return app.Healthcheck.URL != "" && app.Healthcheck.Interval > 0 && app.Healthcheck.Threshold > 0
+	if app.Healthcheck.Interval <= 0 || app.Healthcheck.Threshold <= 0 {
+		return false
+	}
+	switch app.Healthcheck.Type {
+	case codersdk.WorkspaceAppHealthcheckTypeTCP:
+		return app.Healthcheck.Address != ""
+	case codersdk.WorkspaceAppHealthcheckTypeCommand:
+		return app.Healthcheck.Command != ""
+	default:
+		return app.Healthcheck.URL != ""
+	}
+}
+
+// checkAppHealth runs a single healthcheck of the app. The timeout of every
+// check is the healthcheck interval to prevent getting too backed up.
+func checkAppHealth(ctx context.Context, app codersdk.WorkspaceApp, createCommand CreateHealthcheckCommand) error {
+	timeout := time.Duration(app.Healthcheck.Interval) * time.Second
+	switch app.Healthcheck.Type {
+	case codersdk.WorkspaceAppHealthcheckTypeTCP:
+		dialer := &net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", app.Healthcheck.Address)
+		if err != nil {
+			return err
+		}
+		_ = conn.Close()
+		return nil
+	case codersdk.WorkspaceAppHealthcheckTypeCommand:
+		return checkAppHealthCommand(ctx, app.Healthcheck, timeout, createCommand)
+	default:
+		client := &http.Client{
+			Timeout: timeout,
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.Healthcheck.URL, nil)
+		if err != nil {
+			return err
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		// successful healthcheck is a non-5XX status code
+		_ = res.Body.Close()
+		if res.StatusCode >= http.StatusInternalServerError {
+			return xerrors.Errorf("error status code: %d", res.StatusCode)
+		}
+		return nil
+	}
+}
+
+// checkAppHealthCommand runs the healthcheck command. It's healthy if it exits
+// with one of the expected exit codes and its output matches the output
+// regex, if one is set.
+func checkAppHealthCommand(ctx context.Context, healthcheck codersdk.Healthcheck, timeout time.Duration, createCommand CreateHealthcheckCommand) error {
+	if createCommand == nil {
+		return xerrors.New("command healthchecks are not supported")
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd, err := createCommand(ctx, healthcheck.Command)
+	if err != nil {
+		return xerrors.Errorf("create command: %w", err)
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return xerrors.Errorf("command timed out after %s", timeout)
+	}
+
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !xerrors.As(err, &exitErr) {
+			return xerrors.Errorf("run command: %w", err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	expectedExitCodes := healthcheck.ExpectedExitCodes
+	if len(expectedExitCodes) == 0 {
+		expectedExitCodes = []int32{0}
+	}
+	if !slices.Contains(expectedExitCodes, int32(exitCode)) {
+		return xerrors.Errorf("unexpected exit code: %d", exitCode)
+	}
+
+	if healthcheck.OutputRegex != "" {
+		re, err := regexp.Compile(healthcheck.OutputRegex)
+		if err != nil {
+			return xerrors.Errorf("compile output regex: %w", err)
+		}
+		// Like metadata, only the start of the output is considered.
+		const outputLimit = 10 << 10
+		output := out.Bytes()
+		if len(output) > outputLimit {
+			output = output[:outputLimit]
+		}
+		if !re.Match(output) {
+			return xerrors.Errorf("output does not match %q", healthcheck.OutputRegex)
+		}
+	}
+	return nil
 }
 
 func healthChanged(old map[uuid.UUID]codersdk.WorkspaceAppHealth, new map[uuid.UUID]codersdk.WorkspaceAppHealth) bool {
@@ -2,13 +2,17 @@ package agent_test
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os/exec"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog"
@@ -161,6 +165,126 @@ func TestAppHealth_NotSpamming(t *testing.T) {
 	require.LessOrEqual(t, atomic.LoadInt32(counter), int32(2))
 }
 
+func TestAppHealth_TCP(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	// Listen and close to get an address nothing is listening on.
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	_ = closedListener.Close()
+
+	apps := []codersdk.WorkspaceApp{
+		{
+			ID:   uuid.New(),
+			Slug: "listening",
+			Healthcheck: codersdk.Healthcheck{
+				Type:      codersdk.WorkspaceAppHealthcheckTypeTCP,
+				Address:   listener.Addr().String(),
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+		{
+			ID:   uuid.New(),
+			Slug: "closed",
+			Healthcheck: codersdk.Healthcheck{
+				Type:      codersdk.WorkspaceAppHealthcheckTypeTCP,
+				Address:   closedAddress,
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+	}
+	getApps, closeFn := setupAppReporter(ctx, t, apps, nil)
+	defer closeFn()
+	require.Eventually(t, func() bool {
+		apps, err := getApps(ctx)
+		if err != nil {
+			return false
+		}
+
+		return apps[0].Health == codersdk.WorkspaceAppHealthHealthy &&
+			apps[1].Health == codersdk.WorkspaceAppHealthUnhealthy
+	}, testutil.WaitLong, testutil.IntervalSlow)
+}
+
+func TestAppHealth_Command(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses sh")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	commandApp := func(slug string, healthcheck codersdk.Healthcheck) codersdk.WorkspaceApp {
+		healthcheck.Type = codersdk.WorkspaceAppHealthcheckTypeCommand
+		healthcheck.Interval = 1
+		healthcheck.Threshold = 1
+		return codersdk.WorkspaceApp{
+			ID:          uuid.New(),
+			Slug:        slug,
+			Healthcheck: healthcheck,
+			Health:      codersdk.WorkspaceAppHealthInitializing,
+		}
+	}
+	apps := []codersdk.WorkspaceApp{
+		commandApp("success", codersdk.Healthcheck{
+			Command: "true",
+		}),
+		commandApp("failure", codersdk.Healthcheck{
+			Command: "exit 1",
+		}),
+		commandApp("expected-exit-code", codersdk.Healthcheck{
+			Command:           "exit 3",
+			ExpectedExitCodes: []int32{0, 3},
+		}),
+		commandApp("output-match", codersdk.Healthcheck{
+			Command:     "echo accepting connections",
+			OutputRegex: "^accepting",
+		}),
+		commandApp("output-mismatch", codersdk.Healthcheck{
+			Command:     "echo no response",
+			OutputRegex: "^accepting",
+		}),
+	}
+	getApps, closeFn := setupAppReporter(ctx, t, apps, nil)
+	defer closeFn()
+	expected := []codersdk.WorkspaceAppHealth{
+		codersdk.WorkspaceAppHealthHealthy,
+		codersdk.WorkspaceAppHealthUnhealthy,
+		codersdk.WorkspaceAppHealthHealthy,
+		codersdk.WorkspaceAppHealthHealthy,
+		codersdk.WorkspaceAppHealthUnhealthy,
+	}
+	require.Eventually(t, func() bool {
+		apps, err := getApps(ctx)
+		if err != nil {
+			return false
+		}
+		for i, app := range apps {
+			if app.Health != expected[i] {
+				return false
+			}
+		}
+		return true
+	}, testutil.WaitLong, testutil.IntervalSlow)
+}
+
 func setupAppReporter(ctx context.Context, t *testing.T, apps []codersdk.WorkspaceApp, handlers []http.Handler) (agent.WorkspaceAgentApps, func()) {
 	closers := []func(){}
 	for i, handler := range handlers {
@@ -197,7 +321,10 @@ func setupAppReporter(ctx context.Context, t *testing.T, apps []codersdk.Workspa
 		return nil
 	}
 
-	go agent.NewWorkspaceAppHealthReporter(slogtest.Make(t, nil).Leveled(slog.LevelDebug), apps, postWorkspaceAgentAppHealth)(ctx)
+	createCommand := func(ctx context.Context, script string) (*exec.Cmd, error) {
+		return exec.CommandContext(ctx, "sh", "-c", script), nil
+	}
+	go agent.NewWorkspaceAppHealthReporter(slogtest.Make(t, nil).Leveled(slog.LevelDebug), apps, postWorkspaceAgentAppHealth, createCommand)(ctx)
 
 	return workspaceAgentApps, func() {
 		for _, closeFn := range closers {
@@ -0,0 +1,221 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+const (
+	// debugLogUploadInterval is how often debug logs are uploaded when
+	// they're uploaded continuously.
+	debugLogUploadInterval = 5 * time.Second
+	// debugLogUploadBatchSize is the maximum number of logs uploaded in a
+	// single request.
+	debugLogUploadBatchSize = 100
+	// maxDebugLogOutputLength matches the limit of coderd.
+	maxDebugLogOutputLength = 4096
+)
+
+// DebugLogSink is a slog.Sink that keeps the most recent logs of the agent in
+// memory so they can be uploaded to coderd for troubleshooting. Logs are only
+// uploaded once.
+type DebugLogSink struct {
+	mu      sync.Mutex
+	size    int
+	nextSeq uint64
+	entries []debugLogEntry
+}
+
+type debugLogEntry struct {
+	seq      uint64
+	level    slog.Level
+	log      agentsdk.DebugLog
+	uploaded bool
+}
+
+// NewDebugLogSink creates a sink that buffers the last size log entries.
+func NewDebugLogSink(size int) *DebugLogSink {
+	return &DebugLogSink{
+		size: size,
+	}
+}
+
+// LogEntry implements slog.Sink.
+func (s *DebugLogSink) LogEntry(_ context.Context, e slog.SinkEntry) {
+	log := agentsdk.DebugLog{
+		CreatedAt: e.Time,
+		Output:    formatDebugLog(e),
+		Level:     convertDebugLogLevel(e.Level),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSeq++
+	s.entries = append(s.entries, debugLogEntry{
+		seq:   s.nextSeq,
+		level: e.Level,
+		log:   log,
+	})
+	if len(s.entries) > s.size {
+		s.entries = s.entries[len(s.entries)-s.size:]
+	}
+}
+
+// Sync implements slog.Sink.
+func (*DebugLogSink) Sync() {}
+
+// pending returns the buffered logs at or above level that haven't been
+// uploaded yet, oldest first.
+func (s *DebugLogSink) pending(level slog.Level) ([]uint64, []agentsdk.DebugLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		seqs []uint64
+		logs []agentsdk.DebugLog
+	)
+	for _, entry := range s.entries {
+		if entry.uploaded || entry.level < level {
+			continue
+		}
+		seqs = append(seqs, entry.seq)
+		logs = append(logs, entry.log)
+	}
+	return seqs, logs
+}
+
+func (s *DebugLogSink) markUploaded(seqs []uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uploaded := make(map[uint64]struct{}, len(seqs))
+	for _, seq := range seqs {
+		uploaded[seq] = struct{}{}
+	}
+	for i, entry := range s.entries {
+		if _, ok := uploaded[entry.seq]; ok {
+			s.entries[i].uploaded = true
+		}
+	}
+}
+
+// formatDebugLog formats an entry like "name: message key=value", the level
+// and time are stored separately.
+func formatDebugLog(e slog.SinkEntry) string {
+	var sb strings.Builder
+	if len(e.LoggerNames) > 0 {
+		_, _ = sb.WriteString(strings.Join(e.LoggerNames, "."))
+		_, _ = sb.WriteString(": ")
+	}
+	_, _ = sb.WriteString(e.Message)
+	for _, field := range e.Fields {
+		value := fmt.Sprint(field.Value)
+		if strings.ContainsAny(value, " \t\n\"") {
+			value = strconv.Quote(value)
+		}
+		_, _ = fmt.Fprintf(&sb, " %s=%s", field.Name, value)
+	}
+	output := sb.String()
+	if len(output) > maxDebugLogOutputLength {
+		output = output[:maxDebugLogOutputLength]
+		// Don't cut a multi-byte character in half.
+		for !utf8.ValidString(output) {
+			output = output[:len(output)-1]
+		}
+	}
+	return output
+}
+
+func convertDebugLogLevel(level slog.Level) codersdk.LogLevel {
+	switch level {
+	case slog.LevelDebug:
+		return codersdk.LogLevelDebug
+	case slog.LevelInfo:
+		return codersdk.LogLevelInfo
+	case slog.LevelWarn:
+		return codersdk.LogLevelWarn
+	default:
+		return codersdk.LogLevelError
+	}
+}
+
+// flushDebugLogs uploads the buffered logs at or above level that haven't
+// been uploaded yet.
+func (a *agent) flushDebugLogs(ctx context.Context, level slog.Level) error {
+	if a.debugLogs == nil {
+		return nil
+	}
+	// Prevent concurrent flushes from uploading the same logs.
+	a.debugLogsFlushMu.Lock()
+	defer a.debugLogsFlushMu.Unlock()
+
+	seqs, logs := a.debugLogs.pending(level)
+	for len(logs) > 0 {
+		n := len(logs)
+		if n > debugLogUploadBatchSize {
+			n = debugLogUploadBatchSize
+		}
+		err := a.client.PatchDebugLogs(ctx, agentsdk.PatchDebugLogs{
+			Logs: logs[:n],
+		})
+		if err != nil {
+			return xerrors.Errorf("upload debug logs: %w", err)
+		}
+		a.debugLogs.markUploaded(seqs[:n])
+		seqs, logs = seqs[n:], logs[n:]
+	}
+	return nil
+}
+
+// reportDebugLogsLoop continuously uploads debug logs at or above the
+// configured level.
+func (a *agent) reportDebugLogsLoop(ctx context.Context) {
+	if a.debugLogs == nil || a.debugLogUploadLevel == nil {
+		return
+	}
+	ticker := time.NewTicker(debugLogUploadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := a.flushDebugLogs(ctx, *a.debugLogUploadLevel)
+		if err != nil && ctx.Err() == nil {
+			a.logger.Debug(ctx, "failed to upload debug logs", slog.Error(err))
+		}
+	}
+}
+
+// debugLogsFlushHandler uploads all buffered debug logs, regardless of the
+// configured upload level. coderd calls this when an admin requests the logs.
+func (a *agent) debugLogsFlushHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if a.debugLogs == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Debug logs are not buffered by this agent.",
+		})
+		return
+	}
+	err := a.flushDebugLogs(ctx, slog.LevelDebug)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to upload debug logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
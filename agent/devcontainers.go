@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentcontainers"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/retry"
+)
+
+// startDevcontainers starts an agent for each dev container of the workspace
+// directory. The agents are registered with coderd, so dev containers can be
+// connected to like any other agent of the workspace.
+func (a *agent) startDevcontainers(ctx context.Context, dir string) {
+	if a.devcontainers == nil || a.devcontainerClient == nil || ctx.Err() != nil {
+		return
+	}
+	configs, err := agentcontainers.Find(a.filesystem, dir)
+	if err != nil {
+		a.logger.Warn(ctx, "find dev containers", slog.Error(err))
+		return
+	}
+	for _, config := range configs {
+		logger := a.logger.With(slog.F("devcontainer", config.Name))
+		var resp agentsdk.PostDevcontainerResponse
+		for r := retry.New(time.Second, 10*time.Second); r.Wait(ctx); {
+			resp, err = a.client.PostDevcontainer(ctx, agentsdk.PostDevcontainerRequest{
+				Name:      config.Name,
+				Directory: config.RemoteWorkspaceFolder,
+			})
+			if err == nil {
+				break
+			}
+			// Retrying doesn't help if the dev container was rejected,
+			// e.g. because an agent with its name already exists.
+			var sdkErr *codersdk.Error
+			if errors.As(err, &sdkErr) && sdkErr.StatusCode() < http.StatusInternalServerError {
+				break
+			}
+			logger.Warn(ctx, "register dev container", slog.Error(err))
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Error(ctx, "register dev container", slog.Error(err))
+			continue
+		}
+
+		authToken := resp.AuthToken.String()
+		devcontainerAgent := New(Options{
+			Filesystem: a.filesystem,
+			LogDir:     a.logDir,
+			TempDir:    a.tempDir,
+			ExchangeToken: func(context.Context) (string, error) {
+				return authToken, nil
+			},
+			Client:                 a.devcontainerClient(authToken),
+			ReconnectingPTYTimeout: a.reconnectingPTYTimeout,
+			ReconnectingPTYBackend: a.ptyBackendName,
+			EnvironmentVariables:   a.envVars,
+			Logger:                 a.logger.Named("devcontainer").With(slog.F("name", config.Name)),
+			AgentPorts:             a.ignorePorts,
+			SSHMaxTimeout:          a.sshMaxTimeout,
+			Devcontainer:           agentcontainers.New(config, *a.devcontainers),
+		})
+		a.closeMutex.Lock()
+		closed := a.isClosed()
+		if !closed {
+			a.devcontainerAgents = append(a.devcontainerAgents, devcontainerAgent)
+		}
+		a.closeMutex.Unlock()
+		if closed {
+			_ = devcontainerAgent.Close()
+			return
+		}
+		logger.Info(ctx, "started dev container agent", slog.F("agent_id", resp.AgentID))
+	}
+}
+
+// startDevcontainer starts the dev container of the agent of a dev container.
+// The output of the devcontainer CLI is streamed as the startup logs of the
+// agent, which is ready once the container runs.
+func (a *agent) startDevcontainer(ctx context.Context) {
+	logger := a.logger.With(slog.F("config", a.devcontainer.ConfigPath))
+	logPath := filepath.Join(a.logDir, fmt.Sprintf("coder-devcontainer-%s.log", a.devcontainer.Name))
+	fileWriter, err := a.filesystem.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		logger.Error(ctx, "open dev container log file", slog.Error(err))
+		a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStartError)
+		return
+	}
+	defer func() {
+		_ = fileWriter.Close()
+	}()
+
+	logsReader, logsWriter := io.Pipe()
+	defer func() {
+		_ = logsReader.Close()
+	}()
+	flushedLogs, err := a.trackScriptLogs(ctx, uuid.Nil, logsReader)
+	if err != nil {
+		logger.Error(ctx, "track dev container logs", slog.Error(err))
+		return
+	}
+	writer := io.MultiWriter(fileWriter, logsWriter)
+
+	_, err = a.devcontainer.Up(ctx, writer)
+	if err != nil && ctx.Err() == nil {
+		logger.Warn(ctx, "start dev container", slog.Error(err))
+		_, _ = fmt.Fprintf(writer, "Failed to start dev container: %s\n", err)
+	}
+	_ = logsWriter.Close()
+	<-flushedLogs
+	if ctx.Err() != nil {
+		return
+	}
+
+	lifecycleState := codersdk.WorkspaceAgentLifecycleReady
+	if err != nil {
+		lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
+	}
+	a.setLifecycle(ctx, lifecycleState)
+}
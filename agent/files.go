@@ -0,0 +1,313 @@
+package agent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/spf13/afero"
+
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+// filesHandler serves the file transfer API used by `coder cp`. Relative paths
+// are resolved against the home directory of the user running the agent,
+// matching SFTP.
+type filesHandler struct {
+	fs afero.Fs
+}
+
+func (h *filesHandler) routes(r chi.Router) {
+	r.Get("/stat", h.stat)
+	r.Get("/list", h.list)
+	r.Get("/hash", h.hash)
+	r.Get("/read", h.read)
+	r.Post("/write", h.write)
+	r.Post("/mkdir", h.mkdir)
+}
+
+func (h *filesHandler) stat(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.path(rw, r)
+	if !ok {
+		return
+	}
+
+	info, err := h.fs.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertFileInfo(path, info))
+}
+
+func (h *filesHandler) list(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.path(rw, r)
+	if !ok {
+		return
+	}
+
+	infos, err := afero.ReadDir(h.fs, path)
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	files := make([]codersdk.WorkspaceAgentFileInfo, 0, len(infos))
+	for _, info := range infos {
+		files = append(files, convertFileInfo(filepath.Join(path, info.Name()), info))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentListFilesResponse{
+		Files: files,
+	})
+}
+
+// hash returns the SHA-256 of the first "length" bytes of a file, which
+// clients use to verify a partially transferred file before resuming.
+func (h *filesHandler) hash(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.path(rw, r)
+	if !ok {
+		return
+	}
+	length, ok := queryInt64(rw, r, "length")
+	if !ok {
+		return
+	}
+
+	file, err := h.fs.Open(path)
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, io.LimitReader(file, length))
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentFileHashResponse{
+		Length: n,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+}
+
+func (h *filesHandler) read(rw http.ResponseWriter, r *http.Request) {
+	path, ok := h.path(rw, r)
+	if !ok {
+		return
+	}
+	offset, ok := queryInt64(rw, r, "offset")
+	if !ok {
+		return
+	}
+
+	file, err := h.fs.Open(path)
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	if info.IsDir() {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Path is a directory.",
+			Detail:  path,
+		})
+		return
+	}
+	if offset > 0 {
+		_, err = file.Seek(offset, io.SeekStart)
+		if err != nil {
+			writeFileError(rw, r, path, err)
+			return
+		}
+	}
+
+	remaining := info.Size() - offset
+	if remaining < 0 {
+		remaining = 0
+	}
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Length", strconv.FormatInt(remaining, 10))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.CopyN(rw, file, remaining)
+}
+
+// write writes the request body to a file, starting at the given offset. Any
+// existing content past the offset is discarded so that an interrupted upload
+// can be resumed from the size of the partial file.
+func (h *filesHandler) write(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.path(rw, r)
+	if !ok {
+		return
+	}
+	offset, ok := queryInt64(rw, r, "offset")
+	if !ok {
+		return
+	}
+	mode, ok := queryMode(rw, r, 0o644)
+	if !ok {
+		return
+	}
+
+	err := h.fs.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	// An existing file may be read-only from an earlier copy.
+	if _, err := h.fs.Stat(path); err == nil {
+		err = h.fs.Chmod(path, mode|0o600)
+		if err != nil {
+			writeFileError(rw, r, path, err)
+			return
+		}
+	}
+	file, err := h.fs.OpenFile(path, os.O_WRONLY|os.O_CREATE, mode|0o600)
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	defer file.Close()
+
+	err = file.Truncate(offset)
+	if err == nil {
+		_, err = file.Seek(offset, io.SeekStart)
+	}
+	if err == nil {
+		_, err = io.Copy(file, r.Body)
+	}
+	if err == nil {
+		err = file.Close()
+	}
+	if err == nil {
+		err = h.fs.Chmod(path, mode)
+	}
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "File written.",
+	})
+}
+
+func (h *filesHandler) mkdir(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.path(rw, r)
+	if !ok {
+		return
+	}
+	mode, ok := queryMode(rw, r, 0o755)
+	if !ok {
+		return
+	}
+
+	err := h.fs.MkdirAll(path, mode)
+	if err == nil {
+		err = h.fs.Chmod(path, mode)
+	}
+	if err != nil {
+		writeFileError(rw, r, path, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Directory created.",
+	})
+}
+
+// path returns the absolute path from the "path" query parameter.
+func (*filesHandler) path(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter \"path\" is required.",
+		})
+		return "", false
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") || !filepath.IsAbs(path) {
+		home, err := userHomeDir()
+		if err != nil {
+			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Could not resolve home directory.",
+				Detail:  err.Error(),
+			})
+			return "", false
+		}
+		path = filepath.Join(home, strings.TrimPrefix(strings.TrimPrefix(path, "~"), "/"))
+	}
+	return filepath.Clean(path), true
+}
+
+func queryInt64(rw http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return 0, true
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || value < 0 {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Query parameter %q must be a non-negative integer.", key),
+		})
+		return 0, false
+	}
+	return value, true
+}
+
+func queryMode(rw http.ResponseWriter, r *http.Request, def os.FileMode) (os.FileMode, bool) {
+	raw := r.URL.Query().Get("mode")
+	if raw == "" {
+		return def, true
+	}
+	mode, err := strconv.ParseUint(raw, 8, 32)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter \"mode\" must be an octal file mode.",
+		})
+		return 0, false
+	}
+	return os.FileMode(mode) & os.ModePerm, true
+}
+
+func writeFileError(rw http.ResponseWriter, r *http.Request, path string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, os.ErrPermission):
+		status = http.StatusForbidden
+	}
+	httpapi.Write(r.Context(), rw, status, codersdk.Response{
+		Message: fmt.Sprintf("Could not access %q.", path),
+		Detail:  err.Error(),
+	})
+}
+
+func convertFileInfo(path string, info os.FileInfo) codersdk.WorkspaceAgentFileInfo {
+	return codersdk.WorkspaceAgentFileInfo{
+		Path:    path,
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    uint32(info.Mode().Perm()),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
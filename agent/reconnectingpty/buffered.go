@@ -0,0 +1,252 @@
+package reconnectingpty
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armon/circbuf"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/pty"
+)
+
+// buffered runs sessions in the agent process and keeps the last output of
+// each session in memory to replay it to new connections.
+type buffered struct {
+	options  Options
+	sessions sync.Map
+}
+
+func newBuffered(options Options) *buffered {
+	return &buffered{options: options}
+}
+
+func (*buffered) Name() string {
+	return BackendBuffered
+}
+
+func (b *buffered) Attach(ctx context.Context, logger slog.Logger, msg codersdk.WorkspaceAgentReconnectingPTYInit, conn net.Conn) error {
+	connectionID := uuid.NewString()
+	logger = logger.With(slog.F("connection_id", connectionID))
+
+	var rpty *bufferedPTY
+	rawRPTY, ok := b.sessions.Load(msg.ID)
+	if ok {
+		logger.Debug(ctx, "connecting to existing session")
+		rpty, ok = rawRPTY.(*bufferedPTY)
+		if !ok {
+			return xerrors.Errorf("found invalid type in reconnecting pty map: %T", rawRPTY)
+		}
+	} else {
+		logger.Debug(ctx, "creating new session")
+
+		// Empty command will default to the users shell!
+		cmd, err := b.options.SSHServer.CreateCommand(ctx, msg.Command, nil)
+		if err != nil {
+			return xerrors.Errorf("create command: %w", err)
+		}
+		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+
+		// Default to buffer 64KiB.
+		circularBuffer, err := circbuf.NewBuffer(64 << 10)
+		if err != nil {
+			return xerrors.Errorf("create circular buffer: %w", err)
+		}
+
+		ptty, process, err := pty.Start(cmd)
+		if err != nil {
+			return xerrors.Errorf("start command: %w", err)
+		}
+		recording := b.options.SSHServer.StartRecording(agentsdk.SessionRecordingTypeReconnectingPTY, agentssh.RecordingSource{
+			RemoteAddr:        conn.RemoteAddr(),
+			ReconnectingPTYID: msg.ID,
+		}, msg.Width, msg.Height, "xterm-256color")
+
+		ctx, cancelFunc := context.WithCancel(ctx)
+		rpty = &bufferedPTY{
+			session: codersdk.WorkspaceAgentReconnectingPTY{
+				ID:      msg.ID,
+				Name:    msg.Name,
+				Backend: BackendBuffered,
+				Command: msg.Command,
+			},
+			activeConns: map[string]net.Conn{
+				// We have to put the connection in the map instantly otherwise
+				// the connection won't be closed if the process instantly dies.
+				connectionID: conn,
+			},
+			ptty: ptty,
+			// Timeouts created with an after func can be reset!
+			timeout:        time.AfterFunc(b.options.Timeout, cancelFunc),
+			circularBuffer: circularBuffer,
+			recording:      recording,
+		}
+		b.sessions.Store(msg.ID, rpty)
+		go func() {
+			// CommandContext isn't respected for Windows PTYs right now,
+			// so we need to manually track the lifecycle.
+			// When the context has been completed either:
+			// 1. The timeout completed.
+			// 2. The parent context was canceled.
+			<-ctx.Done()
+			_ = process.Kill()
+		}()
+		go func() {
+			// If the process dies randomly, we should
+			// close the pty.
+			_ = process.Wait()
+			rpty.Close()
+		}()
+		if err = b.options.TrackGoroutine(func() {
+			buffer := make([]byte, 1024)
+			for {
+				read, err := rpty.ptty.Output().Read(buffer)
+				if err != nil {
+					// When the PTY is closed, this is triggered.
+					break
+				}
+				part := buffer[:read]
+				rpty.circularBufferMutex.Lock()
+				_, err = rpty.circularBuffer.Write(part)
+				rpty.circularBufferMutex.Unlock()
+				if err != nil {
+					logger.Error(ctx, "write to circular buffer", slog.Error(err))
+					break
+				}
+				if recording != nil {
+					_, _ = recording.Write(part)
+				}
+				rpty.activeConnsMutex.Lock()
+				for _, conn := range rpty.activeConns {
+					_, _ = conn.Write(part)
+				}
+				rpty.activeConnsMutex.Unlock()
+			}
+
+			// Cleanup the process, PTY, and delete it's
+			// ID from memory.
+			_ = process.Kill()
+			rpty.Close()
+			b.sessions.Delete(msg.ID)
+			if recording != nil {
+				b.options.SSHServer.RecordingDone(recording)
+			}
+		}); err != nil {
+			return xerrors.Errorf("start routine: %w", err)
+		}
+	}
+	// Resize the PTY to initial height + width.
+	if rpty.recording != nil {
+		rpty.recording.Resize(msg.Width, msg.Height)
+	}
+	err := rpty.ptty.Resize(msg.Height, msg.Width)
+	if err != nil {
+		// We can continue after this, it's not fatal!
+		logger.Error(ctx, "resize", slog.Error(err))
+	}
+	// Write any previously stored data for the TTY.
+	rpty.circularBufferMutex.RLock()
+	prevBuf := slices.Clone(rpty.circularBuffer.Bytes())
+	rpty.circularBufferMutex.RUnlock()
+	// Note that there is a small race here between writing buffered
+	// data and storing conn in activeConns. This is likely a very minor
+	// edge case, but we should look into ways to avoid it. Holding
+	// activeConnsMutex would be one option, but holding this mutex
+	// while also holding circularBufferMutex seems dangerous.
+	_, err = conn.Write(prevBuf)
+	if err != nil {
+		return xerrors.Errorf("write buffer to conn: %w", err)
+	}
+	// Multiple connections to the same TTY are permitted.
+	// This could easily be used for terminal sharing, but
+	// we do it because it's a nice user experience to
+	// copy/paste a terminal URL and have it _just work_.
+	rpty.activeConnsMutex.Lock()
+	rpty.activeConns[connectionID] = conn
+	rpty.activeConnsMutex.Unlock()
+	// Resetting this timeout prevents the PTY from exiting.
+	rpty.timeout.Reset(b.options.Timeout)
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+	heartbeat := time.NewTicker(b.options.Timeout / 2)
+	defer heartbeat.Stop()
+	go func() {
+		// Keep updating the activity while this
+		// connection is alive!
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+			}
+			rpty.timeout.Reset(b.options.Timeout)
+		}
+	}()
+	defer func() {
+		// After this connection ends, remove it from
+		// the PTYs active connections. If it isn't
+		// removed, all PTY data will be sent to it.
+		rpty.activeConnsMutex.Lock()
+		delete(rpty.activeConns, connectionID)
+		rpty.activeConnsMutex.Unlock()
+	}()
+	handleInput(ctx, logger, conn, rpty.ptty, rpty.recording)
+	return nil
+}
+
+func (b *buffered) Sessions(_ context.Context) ([]codersdk.WorkspaceAgentReconnectingPTY, error) {
+	sessions := make([]codersdk.WorkspaceAgentReconnectingPTY, 0)
+	b.sessions.Range(func(_, value any) bool {
+		rpty, ok := value.(*bufferedPTY)
+		if !ok {
+			return true
+		}
+		session := rpty.session
+		rpty.activeConnsMutex.Lock()
+		session.ActiveConnections = len(rpty.activeConns)
+		rpty.activeConnsMutex.Unlock()
+		sessions = append(sessions, session)
+		return true
+	})
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ID.String() < sessions[j].ID.String()
+	})
+	return sessions, nil
+}
+
+type bufferedPTY struct {
+	session codersdk.WorkspaceAgentReconnectingPTY
+
+	activeConnsMutex sync.Mutex
+	activeConns      map[string]net.Conn
+
+	circularBuffer      *circbuf.Buffer
+	circularBufferMutex sync.RWMutex
+	timeout             *time.Timer
+	ptty                pty.PTY
+	// recording is nil unless session recording is enabled.
+	recording *agentssh.Recording
+}
+
+func (r *bufferedPTY) Close() {
+	r.activeConnsMutex.Lock()
+	defer r.activeConnsMutex.Unlock()
+	for _, conn := range r.activeConns {
+		_ = conn.Close()
+	}
+	_ = r.ptty.Close()
+	r.circularBufferMutex.Lock()
+	r.circularBuffer.Reset()
+	r.circularBufferMutex.Unlock()
+	r.timeout.Stop()
+}
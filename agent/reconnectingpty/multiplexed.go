@@ -0,0 +1,291 @@
+package reconnectingpty
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/pty"
+)
+
+// multiplexer runs sessions in a terminal multiplexer, which keeps them
+// running when the agent restarts.
+type multiplexer interface {
+	name() string
+	// start starts a detached session that runs cmd.
+	start(ctx context.Context, session codersdk.WorkspaceAgentReconnectingPTY, cmd *exec.Cmd, height, width uint16) error
+	// attach returns a command that attaches the terminal it runs in to the
+	// session until it is killed or the session ends.
+	attach(id uuid.UUID) *exec.Cmd
+	// list lists the running sessions. The name and command of sessions are
+	// empty if the multiplexer can't store them.
+	list(ctx context.Context) ([]codersdk.WorkspaceAgentReconnectingPTY, error)
+	// kill ends the session and its command.
+	kill(ctx context.Context, id uuid.UUID) error
+}
+
+// multiplexed attaches every connection with a separate client of the
+// terminal multiplexer, which redraws the screen of the session for it.
+type multiplexed struct {
+	mux     multiplexer
+	options Options
+
+	mutex sync.Mutex
+	// sessions are the sessions started or attached to by this agent process.
+	sessions map[uuid.UUID]*multiplexedSession
+}
+
+type multiplexedSession struct {
+	session     codersdk.WorkspaceAgentReconnectingPTY
+	activeConns int
+	// timeout kills the session when it had no connections for the timeout
+	// of the options. It's nil while connections are attached.
+	timeout *time.Timer
+}
+
+func newMultiplexed(mux multiplexer, options Options) *multiplexed {
+	m := &multiplexed{
+		mux:      mux,
+		options:  options,
+		sessions: map[uuid.UUID]*multiplexedSession{},
+	}
+	// Sessions left by a previous agent process time out like detached
+	// sessions, unless they are attached to again.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	running, err := mux.list(ctx)
+	if err == nil {
+		m.mutex.Lock()
+		for _, session := range running {
+			tracked := &multiplexedSession{session: session}
+			m.sessions[session.ID] = tracked
+			m.startTimeout(tracked)
+		}
+		m.mutex.Unlock()
+	}
+	return m
+}
+
+func (m *multiplexed) Name() string {
+	return m.mux.name()
+}
+
+func (m *multiplexed) Attach(ctx context.Context, logger slog.Logger, msg codersdk.WorkspaceAgentReconnectingPTYInit, conn net.Conn) error {
+	// The session is tracked before it's looked up, so it can't time out
+	// while the connection attaches to it.
+	m.mutex.Lock()
+	tracked, ok := m.sessions[msg.ID]
+	if !ok {
+		tracked = &multiplexedSession{session: codersdk.WorkspaceAgentReconnectingPTY{
+			ID:      msg.ID,
+			Backend: m.mux.name(),
+		}}
+		m.sessions[msg.ID] = tracked
+	}
+	tracked.activeConns++
+	if tracked.timeout != nil {
+		tracked.timeout.Stop()
+		tracked.timeout = nil
+	}
+	m.mutex.Unlock()
+	defer func() {
+		m.mutex.Lock()
+		tracked.activeConns--
+		if tracked.activeConns == 0 {
+			m.startTimeout(tracked)
+		}
+		m.mutex.Unlock()
+	}()
+
+	running, err := m.mux.list(ctx)
+	if err != nil {
+		return xerrors.Errorf("list sessions: %w", err)
+	}
+	if slices.ContainsFunc(running, func(s codersdk.WorkspaceAgentReconnectingPTY) bool { return s.ID == msg.ID }) {
+		logger.Debug(ctx, "connecting to existing session")
+	} else {
+		logger.Debug(ctx, "creating new session")
+
+		// Empty command will default to the users shell!
+		cmd, err := m.options.SSHServer.CreateCommand(ctx, msg.Command, nil)
+		if err != nil {
+			return xerrors.Errorf("create command: %w", err)
+		}
+		m.mutex.Lock()
+		tracked.session.Name = msg.Name
+		tracked.session.Command = msg.Command
+		session := tracked.session
+		m.mutex.Unlock()
+		err = m.mux.start(ctx, session, cmd, msg.Height, msg.Width)
+		if err != nil {
+			return xerrors.Errorf("start session: %w", err)
+		}
+	}
+
+	cmd := m.mux.attach(msg.ID)
+	cmd.Env = append(multiplexerEnv(), "TERM=xterm-256color")
+	ptty, process, err := pty.Start(cmd)
+	if err != nil {
+		return xerrors.Errorf("attach to session: %w", err)
+	}
+	defer ptty.Close()
+	err = ptty.Resize(msg.Height, msg.Width)
+	if err != nil {
+		// We can continue after this, it's not fatal!
+		logger.Error(ctx, "resize", slog.Error(err))
+	}
+
+	// Every connection is recorded separately, since each of them has its
+	// own client that draws the screen.
+	recording := m.options.SSHServer.StartRecording(agentsdk.SessionRecordingTypeReconnectingPTY, agentssh.RecordingSource{
+		RemoteAddr:        conn.RemoteAddr(),
+		ReconnectingPTYID: msg.ID,
+	}, msg.Width, msg.Height, "xterm-256color")
+	if recording != nil {
+		defer m.options.SSHServer.RecordingDone(recording)
+	}
+
+	processDone := make(chan struct{})
+	go func() {
+		// The client exits when the session ends. The PTY has to be closed
+		// to stop reading its output.
+		_ = process.Wait()
+		close(processDone)
+		_ = ptty.Close()
+	}()
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		buffer := make([]byte, 1024)
+		for {
+			read, err := ptty.Output().Read(buffer)
+			if err != nil {
+				break
+			}
+			part := buffer[:read]
+			if recording != nil {
+				_, _ = recording.Write(part)
+			}
+			_, err = conn.Write(part)
+			if err != nil {
+				break
+			}
+		}
+		// Stop reading input if the session ended.
+		_ = conn.Close()
+	}()
+
+	handleInput(ctx, logger, conn, ptty, recording)
+	// Killing the client detaches it, the session keeps running.
+	_ = process.Kill()
+	<-processDone
+	<-outputDone
+	return nil
+}
+
+func (m *multiplexed) Sessions(ctx context.Context) ([]codersdk.WorkspaceAgentReconnectingPTY, error) {
+	sessions, err := m.mux.list(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("list sessions: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	running := make(map[uuid.UUID]struct{}, len(sessions))
+	for i, session := range sessions {
+		running[session.ID] = struct{}{}
+		tracked, ok := m.sessions[session.ID]
+		if !ok {
+			continue
+		}
+		if session.Name == "" {
+			sessions[i].Name = tracked.session.Name
+		}
+		if session.Command == "" {
+			sessions[i].Command = tracked.session.Command
+		}
+		sessions[i].ActiveConnections = tracked.activeConns
+	}
+	// Forget sessions that ended.
+	for id, tracked := range m.sessions {
+		if _, ok := running[id]; !ok && tracked.activeConns == 0 {
+			if tracked.timeout != nil {
+				tracked.timeout.Stop()
+			}
+			delete(m.sessions, id)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ID.String() < sessions[j].ID.String()
+	})
+	return sessions, nil
+}
+
+// startTimeout kills the session if no connection attaches to it within the
+// timeout of the options. The mutex must be held.
+func (m *multiplexed) startTimeout(tracked *multiplexedSession) {
+	if m.options.Timeout <= 0 {
+		return
+	}
+	tracked.timeout = time.AfterFunc(m.options.Timeout, func() {
+		m.mutex.Lock()
+		if m.sessions[tracked.session.ID] != tracked || tracked.activeConns > 0 {
+			m.mutex.Unlock()
+			return
+		}
+		delete(m.sessions, tracked.session.ID)
+		m.mutex.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = m.mux.kill(ctx, tracked.session.ID)
+	})
+}
+
+// sessionName is the name of the session in the terminal multiplexer.
+func sessionName(id uuid.UUID) string {
+	return "coder-" + id.String()
+}
+
+// parseSessionName returns the ID of a session name, or false if the session
+// wasn't started by the agent.
+func parseSessionName(name string) (uuid.UUID, bool) {
+	if !strings.HasPrefix(name, "coder-") {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimPrefix(name, "coder-"))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// multiplexerEnv is the environment of the agent without the variables that
+// make terminal multiplexers refuse to attach when the agent itself runs in
+// one.
+func multiplexerEnv() []string {
+	env := os.Environ()
+	filtered := env[:0]
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		switch key {
+		case "TMUX", "TMUX_PANE", "STY":
+			continue
+		}
+		filtered = append(filtered, kv)
+	}
+	return filtered
+}
@@ -0,0 +1,126 @@
+// Package reconnectingpty implements the terminal sessions of the web terminal
+// and "coder ssh --session". Connections attach to a session and detach from
+// it without ending it, so a session can be reattached after a page reload or
+// network interruption.
+//
+// The buffered backend keeps sessions and their scrollback in the memory of
+// the agent. The tmux and screen backends run sessions in a terminal
+// multiplexer instead, so they keep running when the agent restarts.
+package reconnectingpty
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os/exec"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/pty"
+)
+
+const (
+	// BackendAuto uses tmux or screen if one of them is
+	// installed and falls back to the buffered backend otherwise.
+	BackendAuto     = "auto"
+	BackendBuffered = "buffered"
+	BackendTmux     = "tmux"
+	BackendScreen   = "screen"
+)
+
+// Backends are the backends that can be passed to NewBackend.
+var Backends = []string{BackendAuto, BackendBuffered, BackendTmux, BackendScreen}
+
+// Backend runs reconnecting PTY sessions.
+type Backend interface {
+	// Name is the name of the backend, e.g. "tmux".
+	Name() string
+	// Attach attaches conn to the session with the ID of init and starts the
+	// session if it doesn't exist. It returns when conn is closed or the
+	// session ends.
+	Attach(ctx context.Context, logger slog.Logger, init codersdk.WorkspaceAgentReconnectingPTYInit, conn net.Conn) error
+	// Sessions lists the running sessions.
+	Sessions(ctx context.Context) ([]codersdk.WorkspaceAgentReconnectingPTY, error)
+}
+
+type Options struct {
+	// Timeout ends sessions that had no connections for this long.
+	Timeout time.Duration
+	// SSHServer creates the commands of sessions and records them.
+	SSHServer *agentssh.Server
+	// TrackGoroutine runs fn in a goroutine that the agent waits for when it
+	// is closed.
+	TrackGoroutine func(fn func()) error
+}
+
+// NewBackend returns the backend with the given name. An empty name returns
+// the buffered backend. It fails if the program of a terminal multiplexer is
+// not installed.
+func NewBackend(name string, options Options) (Backend, error) {
+	switch name {
+	case "", BackendBuffered:
+		return newBuffered(options), nil
+	case BackendAuto:
+		for _, name := range []string{BackendTmux, BackendScreen} {
+			backend, err := NewBackend(name, options)
+			if err == nil {
+				return backend, nil
+			}
+		}
+		return newBuffered(options), nil
+	case BackendTmux, BackendScreen:
+		path, err := exec.LookPath(name)
+		if err != nil {
+			return nil, xerrors.Errorf("find %s: %w", name, err)
+		}
+		if name == BackendTmux {
+			return newMultiplexed(&tmux{path: path, socket: "coder"}, options), nil
+		}
+		mux, err := newScreen(path)
+		if err != nil {
+			return nil, err
+		}
+		return newMultiplexed(mux, options), nil
+	default:
+		return nil, xerrors.Errorf("unknown reconnecting pty backend %q", name)
+	}
+}
+
+// handleInput writes the data of requests read from conn to the PTY and
+// resizes it until conn is closed.
+func handleInput(ctx context.Context, logger slog.Logger, conn net.Conn, ptty pty.PTY, recording *agentssh.Recording) {
+	decoder := json.NewDecoder(conn)
+	var req codersdk.ReconnectingPTYRequest
+	for {
+		err := decoder.Decode(&req)
+		if xerrors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			logger.Warn(ctx, "read conn", slog.Error(err))
+			return
+		}
+		_, err = ptty.Input().Write([]byte(req.Data))
+		if err != nil {
+			logger.Warn(ctx, "write to pty", slog.Error(err))
+			return
+		}
+		// Check if a resize needs to happen!
+		if req.Height == 0 || req.Width == 0 {
+			continue
+		}
+		if recording != nil {
+			recording.Resize(req.Width, req.Height)
+		}
+		err = ptty.Resize(req.Height, req.Width)
+		if err != nil {
+			// We can continue after this, it's not fatal!
+			logger.Error(ctx, "resize", slog.Error(err))
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package reconnectingpty_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os/exec"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/agent/reconnectingpty"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+//nolint:paralleltest // The sockets of tmux and screen are set with t.Setenv.
+func TestBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	for _, backend := range []string{reconnectingpty.BackendBuffered, reconnectingpty.BackendTmux, reconnectingpty.BackendScreen} {
+		backend := backend
+		t.Run(backend, func(t *testing.T) {
+			if backend != reconnectingpty.BackendBuffered {
+				if _, err := exec.LookPath(backend); err != nil {
+					t.Skipf("%s is not installed", backend)
+				}
+			}
+			// Isolate the sessions of the test from the ones of the user.
+			dir := t.TempDir()
+			t.Setenv("TMUX_TMPDIR", dir)
+			t.Setenv("SCREENDIR", dir)
+			t.Cleanup(func() {
+				if backend == reconnectingpty.BackendTmux {
+					_ = exec.Command("tmux", "-L", "coder", "kill-server").Run()
+				}
+			})
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+			logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+			server, err := agentssh.NewServer(ctx, logger, 0)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = server.Close()
+			})
+			server.Manifest = atomic.NewPointer(&agentsdk.Manifest{
+				Directory: dir,
+				EnvironmentVariables: map[string]string{
+					"CODER_TEST_ENV": "from-manifest",
+				},
+			})
+			server.AgentToken = func() string { return "" }
+
+			options := reconnectingpty.Options{
+				Timeout:   time.Minute,
+				SSHServer: server,
+				TrackGoroutine: func(fn func()) error {
+					go fn()
+					return nil
+				},
+			}
+			rpty, err := reconnectingpty.NewBackend(backend, options)
+			require.NoError(t, err)
+			require.Equal(t, backend, rpty.Name())
+
+			init := codersdk.WorkspaceAgentReconnectingPTYInit{
+				ID:     codersdk.ReconnectingPTYSessionID("test"),
+				Name:   "test",
+				Height: 24,
+				Width:  80,
+			}
+			conn, done := attach(ctx, t, rpty, logger, init)
+			// Brief pause to reduce the likelihood that we send keystrokes
+			// while the shell is simultaneously sending a prompt.
+			time.Sleep(100 * time.Millisecond)
+			conn.send(t, "echo hello-$((40 + 2))\r")
+			conn.expectOutput(t, "hello-42")
+			// Sessions get the environment of the command.
+			conn.send(t, "echo env-$CODER_TEST_ENV\r")
+			conn.expectOutput(t, "env-from-manifest")
+
+			expected := []codersdk.WorkspaceAgentReconnectingPTY{{
+				ID:                init.ID,
+				Name:              "test",
+				Backend:           backend,
+				ActiveConnections: 1,
+			}}
+			var sessions []codersdk.WorkspaceAgentReconnectingPTY
+			require.Eventually(t, func() bool {
+				var err error
+				sessions, err = rpty.Sessions(ctx)
+				require.NoError(t, err)
+				return len(sessions) == 1
+			}, testutil.WaitLong, testutil.IntervalFast)
+			require.Equal(t, expected, sessions)
+
+			// Detaching keeps the session running.
+			_ = conn.Close()
+			require.NoError(t, <-done)
+			sessions, err = rpty.Sessions(ctx)
+			require.NoError(t, err)
+			require.Len(t, sessions, 1)
+			require.Zero(t, sessions[0].ActiveConnections)
+
+			if backend != reconnectingpty.BackendBuffered {
+				// The session outlives the agent, a new backend finds it.
+				rpty, err = reconnectingpty.NewBackend(backend, options)
+				require.NoError(t, err)
+				sessions, err = rpty.Sessions(ctx)
+				require.NoError(t, err)
+				require.Len(t, sessions, 1)
+				require.Equal(t, init.ID, sessions[0].ID)
+			}
+
+			// The output of the session is shown again when it's reattached.
+			conn, done = attach(ctx, t, rpty, logger, init)
+			conn.expectOutput(t, "hello-42")
+
+			// The session ends with its command.
+			conn.send(t, "exit\r")
+			require.NoError(t, <-done)
+			require.Eventually(t, func() bool {
+				sessions, err := rpty.Sessions(ctx)
+				return err == nil && len(sessions) == 0
+			}, testutil.WaitLong, testutil.IntervalFast)
+		})
+	}
+}
+
+//nolint:paralleltest // The sockets of tmux and screen are set with t.Setenv.
+func TestBackendTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	for _, backend := range []string{reconnectingpty.BackendBuffered, reconnectingpty.BackendTmux, reconnectingpty.BackendScreen} {
+		backend := backend
+		t.Run(backend, func(t *testing.T) {
+			if backend != reconnectingpty.BackendBuffered {
+				if _, err := exec.LookPath(backend); err != nil {
+					t.Skipf("%s is not installed", backend)
+				}
+			}
+			dir := t.TempDir()
+			t.Setenv("TMUX_TMPDIR", dir)
+			t.Setenv("SCREENDIR", dir)
+			t.Cleanup(func() {
+				if backend == reconnectingpty.BackendTmux {
+					_ = exec.Command("tmux", "-L", "coder", "kill-server").Run()
+				}
+			})
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+			logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+			server, err := agentssh.NewServer(ctx, logger, 0)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = server.Close()
+			})
+			server.Manifest = atomic.NewPointer(&agentsdk.Manifest{Directory: dir})
+			server.AgentToken = func() string { return "" }
+
+			rpty, err := reconnectingpty.NewBackend(backend, reconnectingpty.Options{
+				Timeout:   time.Second,
+				SSHServer: server,
+				TrackGoroutine: func(fn func()) error {
+					go fn()
+					return nil
+				},
+			})
+			require.NoError(t, err)
+
+			init := codersdk.WorkspaceAgentReconnectingPTYInit{
+				ID:     codersdk.ReconnectingPTYSessionID("test"),
+				Height: 24,
+				Width:  80,
+			}
+			conn, done := attach(ctx, t, rpty, logger, init)
+			time.Sleep(100 * time.Millisecond)
+			conn.send(t, "echo hello-$((40 + 2))\r")
+			conn.expectOutput(t, "hello-42")
+
+			// Detached sessions end after the timeout.
+			_ = conn.Close()
+			require.NoError(t, <-done)
+			require.Eventually(t, func() bool {
+				sessions, err := rpty.Sessions(ctx)
+				return err == nil && len(sessions) == 0
+			}, testutil.WaitLong, testutil.IntervalFast)
+		})
+	}
+}
+
+type testConn struct {
+	net.Conn
+	mu     sync.Mutex
+	output bytes.Buffer
+}
+
+// attach attaches to the session in the background and collects its output.
+func attach(ctx context.Context, t *testing.T, rpty reconnectingpty.Backend, logger slog.Logger, init codersdk.WorkspaceAgentReconnectingPTYInit) (*testConn, <-chan error) {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- rpty.Attach(ctx, logger, init, server)
+		_ = server.Close()
+	}()
+	conn := &testConn{Conn: client}
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			read, err := client.Read(buffer)
+			conn.mu.Lock()
+			_, _ = conn.output.Write(buffer[:read])
+			conn.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return conn, done
+}
+
+func (c *testConn) send(t *testing.T, data string) {
+	t.Helper()
+	payload, err := json.Marshal(codersdk.ReconnectingPTYRequest{Data: data})
+	require.NoError(t, err)
+	_, err = c.Write(payload)
+	if err != nil && err != io.ErrClosedPipe {
+		require.NoError(t, err)
+	}
+}
+
+func (c *testConn) expectOutput(t *testing.T, output string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return bytes.Contains(c.output.Bytes(), []byte(output))
+	}, testutil.WaitLong, testutil.IntervalFast, "output %q", output)
+}
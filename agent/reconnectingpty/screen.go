@@ -0,0 +1,115 @@
+package reconnectingpty
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// screenConfig replaces the screenrc of the user for sessions of the agent.
+var screenConfig = strings.Join([]string{
+	"startup_message off",
+	// Let the terminal scroll with the mouse wheel instead of cycling
+	// through the command history.
+	"termcapinfo xterm* ti@:te@",
+	// Keep the output of full screen applications out of the scrollback.
+	"altscreen on",
+	"defscrollback 10000",
+	// C-a is used by shells and editors. C-s is free since it would only
+	// pause the output of the terminal.
+	"escape ^Ss",
+}, "\n") + "\n"
+
+// screenSessionRegex matches the sessions in the output of "screen -ls", e.g.
+// "	1234.coder-<id>	(Detached)".
+var screenSessionRegex = regexp.MustCompile(`^\s*\d+\.(coder-[0-9a-f-]{36})\s`)
+
+// screen runs sessions with GNU Screen. Screen can't store the name and command
+// of sessions, so they are only listed for sessions started by the running
+// agent.
+type screen struct {
+	path   string
+	config string
+}
+
+func newScreen(path string) (*screen, error) {
+	// The temporary directory is shared with other users, so the config is
+	// written to a new private directory instead of a path that someone else
+	// could have created first.
+	dir, err := os.MkdirTemp("", "coder-screen-")
+	if err != nil {
+		return nil, xerrors.Errorf("create screen config directory: %w", err)
+	}
+	config := filepath.Join(dir, "screenrc")
+	err = os.WriteFile(config, []byte(screenConfig), 0o600)
+	if err != nil {
+		return nil, xerrors.Errorf("write screen config: %w", err)
+	}
+	return &screen{path: path, config: config}, nil
+}
+
+func (*screen) name() string {
+	return BackendScreen
+}
+
+func (s *screen) command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, s.path, append([]string{"-c", s.config}, args...)...)
+}
+
+func (s *screen) start(ctx context.Context, session codersdk.WorkspaceAgentReconnectingPTY, cmd *exec.Cmd, _, _ uint16) error {
+	// Screen takes the size of the terminal that attaches to the session.
+	args := append([]string{"-dmS", sessionName(session.ID), cmd.Path}, cmd.Args[1:]...)
+	start := s.command(ctx, args...)
+	start.Env = cmd.Env
+	start.Dir = cmd.Dir
+	out, err := start.CombinedOutput()
+	if err != nil {
+		return xerrors.Errorf("%s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (s *screen) attach(id uuid.UUID) *exec.Cmd {
+	// -x attaches without detaching other connections.
+	return s.command(context.Background(), "-x", sessionName(id))
+}
+
+func (s *screen) kill(ctx context.Context, id uuid.UUID) error {
+	out, err := s.command(ctx, "-S", sessionName(id), "-X", "quit").CombinedOutput()
+	if err != nil {
+		return xerrors.Errorf("%s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (s *screen) list(ctx context.Context) ([]codersdk.WorkspaceAgentReconnectingPTY, error) {
+	// The exit code of "screen -ls" is not zero even if there are sessions.
+	out, err := s.command(ctx, "-ls").CombinedOutput()
+	sessions := make([]codersdk.WorkspaceAgentReconnectingPTY, 0)
+	for _, line := range strings.Split(string(out), "\n") {
+		match := screenSessionRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		id, ok := parseSessionName(match[1])
+		if !ok {
+			continue
+		}
+		sessions = append(sessions, codersdk.WorkspaceAgentReconnectingPTY{
+			ID:      id,
+			Backend: BackendScreen,
+		})
+	}
+	if err != nil && len(sessions) == 0 && !strings.Contains(string(out), "No Sockets found") {
+		return nil, xerrors.Errorf("%s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,123 @@
+package reconnectingpty
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// tmux runs sessions on a tmux server with a separate socket, so the sessions
+// of the user aren't listed and the options below don't apply to them.
+type tmux struct {
+	path   string
+	socket string
+}
+
+func (*tmux) name() string {
+	return BackendTmux
+}
+
+func (t *tmux) command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, t.path, append([]string{"-L", t.socket}, args...)...)
+}
+
+func (t *tmux) start(ctx context.Context, session codersdk.WorkspaceAgentReconnectingPTY, cmd *exec.Cmd, height, width uint16) error {
+	name := sessionName(session.ID)
+	// The server only inherits the environment of the command that starts
+	// it, so the environment of every session is copied from the client
+	// with update-environment. Values aren't passed as arguments since
+	// those are visible to other users of the workspace.
+	envNames := make([]string, 0, len(cmd.Env))
+	for _, env := range cmd.Env {
+		key, _, _ := strings.Cut(env, "=")
+		if key != "" {
+			envNames = append(envNames, key)
+		}
+	}
+	args := []string{
+		// The web terminal has no use for the status line and the prefix
+		// key would swallow input meant for applications.
+		"start-server", ";",
+		"set-option", "-g", "status", "off", ";",
+		"set-option", "-g", "prefix", "None", ";",
+		"set-option", "-g", "prefix2", "None", ";",
+		"set-option", "-s", "escape-time", "0", ";",
+		"set-option", "-g", "update-environment", strings.Join(envNames, " "), ";",
+		"new-session", "-d", "-s", name,
+		"-x", strconv.Itoa(int(width)), "-y", strconv.Itoa(int(height)),
+	}
+	if cmd.Dir != "" {
+		args = append(args, "-c", cmd.Dir)
+	}
+	args = append(args, "--", cmd.Path)
+	args = append(args, cmd.Args[1:]...)
+	args = append(args, ";",
+		// Attaching would copy the environment of the agent otherwise.
+		"set-option", "-g", "update-environment", "", ";",
+		// User options keep the details of the session for the session list.
+		"set-option", "-t", name, "@coder_name", session.Name, ";",
+		"set-option", "-t", name, "@coder_command", session.Command,
+	)
+
+	start := t.command(ctx, args...)
+	start.Env = cmd.Env
+	out, err := start.CombinedOutput()
+	if err != nil {
+		return xerrors.Errorf("%s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (t *tmux) attach(id uuid.UUID) *exec.Cmd {
+	//nolint:gosec // The ID is always a UUID.
+	return t.command(context.Background(), "attach-session", "-t", "="+sessionName(id))
+}
+
+func (t *tmux) kill(ctx context.Context, id uuid.UUID) error {
+	out, err := t.command(ctx, "kill-session", "-t", "="+sessionName(id)).CombinedOutput()
+	if err != nil {
+		return xerrors.Errorf("%s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (t *tmux) list(ctx context.Context) ([]codersdk.WorkspaceAgentReconnectingPTY, error) {
+	out, err := t.command(ctx, "list-sessions", "-F", "#{session_name}\t#{@coder_name}\t#{@coder_command}").CombinedOutput()
+	if err != nil {
+		// The server exits with its last session and removes its socket.
+		// Newer versions of tmux report the missing socket instead of "no
+		// server running". Other errors, like refused connections, don't
+		// mean that there are no sessions.
+		msg := string(out)
+		if strings.Contains(msg, "no server running") ||
+			(strings.Contains(msg, "error connecting to") && strings.Contains(msg, "No such file or directory")) {
+			return []codersdk.WorkspaceAgentReconnectingPTY{}, nil
+		}
+		return nil, xerrors.Errorf("%s: %w", strings.TrimSpace(msg), err)
+	}
+
+	sessions := make([]codersdk.WorkspaceAgentReconnectingPTY, 0)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		id, ok := parseSessionName(fields[0])
+		if !ok {
+			continue
+		}
+		sessions = append(sessions, codersdk.WorkspaceAgentReconnectingPTY{
+			ID:      id,
+			Name:    fields[1],
+			Backend: BackendTmux,
+			Command: fields[2],
+		})
+	}
+	return sessions, nil
+}
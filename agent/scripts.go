@@ -0,0 +1,470 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/retry"
+)
+
+// agentScript is a script run by the agent. Scripts from `coder_script`
+// resources have an ID, the legacy startup and shutdown scripts of the
+// `coder_agent` resource do not.
+type agentScript struct {
+	codersdk.WorkspaceAgentScript
+	timeout time.Duration
+}
+
+// scriptRun is the reason a script is run.
+type scriptRun int
+
+const (
+	scriptRunStart scriptRun = iota
+	scriptRunStop
+	scriptRunCron
+)
+
+// manifestScripts returns all scripts of a manifest, including the legacy
+// startup and shutdown scripts.
+func manifestScripts(manifest agentsdk.Manifest) []agentScript {
+	scripts := make([]agentScript, 0, len(manifest.Scripts)+2)
+	if manifest.StartupScript != "" {
+		scripts = append(scripts, agentScript{
+			WorkspaceAgentScript: codersdk.WorkspaceAgentScript{
+				DisplayName:      "startup",
+				Script:           manifest.StartupScript,
+				StartBlocksLogin: true,
+				RunOnStart:       true,
+				LogPath:          "coder-startup-script.log",
+			},
+			timeout: manifest.StartupScriptTimeout,
+		})
+	}
+	if manifest.ShutdownScript != "" {
+		scripts = append(scripts, agentScript{
+			WorkspaceAgentScript: codersdk.WorkspaceAgentScript{
+				DisplayName: "shutdown",
+				Script:      manifest.ShutdownScript,
+				RunOnStop:   true,
+				LogPath:     "coder-shutdown-script.log",
+			},
+			timeout: manifest.ShutdownScriptTimeout,
+		})
+	}
+	for _, script := range manifest.Scripts {
+		scripts = append(scripts, agentScript{
+			WorkspaceAgentScript: script,
+			timeout:              time.Duration(script.TimeoutSeconds) * time.Second,
+		})
+	}
+	// Stable, so that the legacy startup script runs first within its order.
+	sort.SliceStable(scripts, func(i, j int) bool {
+		return scripts[i].RunOrder < scripts[j].RunOrder
+	})
+	return scripts
+}
+
+// runStartScripts runs the scripts that run on start and reports the agent
+// lifecycle once the login-blocking scripts have finished. Scripts are started
+// in ascending order, and each order waits for the login-blocking scripts of
+// the previous orders. Scripts that don't block login keep running in the
+// background.
+//
+// The caller must run it in a goroutine tracked by trackConnGoroutine.
+func (a *agent) runStartScripts(ctx context.Context, scripts []agentScript) {
+	var (
+		mu     sync.Mutex
+		failed bool
+	)
+	start := make([]agentScript, 0, len(scripts))
+	for _, script := range scripts {
+		if script.RunOnStart {
+			start = append(start, script)
+		}
+	}
+	for i := 0; i < len(start); {
+		j := i
+		for j < len(start) && start[j].RunOrder == start[i].RunOrder {
+			j++
+		}
+
+		var blocking sync.WaitGroup
+		for _, script := range start[i:j] {
+			script := script
+			blocks := script.StartBlocksLogin
+			if blocks {
+				blocking.Add(1)
+			}
+			// trackConnGoroutine can't be used since Close holds the close
+			// mutex while it waits. The goroutine of the caller is tracked,
+			// so adding to the wait group is safe while Close waits.
+			a.connCloseWait.Add(1)
+			go func() {
+				defer a.connCloseWait.Done()
+				if blocks {
+					defer blocking.Done()
+				}
+				err := a.executeScript(ctx, script, scriptRunStart, func() {
+					if blocks {
+						a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStartTimeout)
+					}
+				})
+				if err != nil && blocks {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}()
+		}
+		blocking.Wait()
+		i = j
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	lifecycleState := codersdk.WorkspaceAgentLifecycleReady
+	mu.Lock()
+	if failed {
+		lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
+	}
+	mu.Unlock()
+	a.setLifecycle(ctx, lifecycleState)
+}
+
+// scheduleCronScripts runs scripts with a cron schedule until the context is
+// canceled. A run is skipped if the previous run of the script is still going.
+func (a *agent) scheduleCronScripts(ctx context.Context, scripts []agentScript) {
+	scheduler := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	for _, script := range scripts {
+		if script.Cron == "" {
+			continue
+		}
+		script := script
+		_, err := scheduler.AddFunc(script.Cron, func() {
+			done := make(chan struct{})
+			err := a.trackConnGoroutine(func() {
+				defer close(done)
+				_ = a.executeScript(ctx, script, scriptRunCron, nil)
+			})
+			if err != nil {
+				return
+			}
+			<-done
+		})
+		if err != nil {
+			a.logger.Warn(ctx, "invalid script cron schedule", slog.F("script", script.DisplayName), slog.F("cron", script.Cron), slog.Error(err))
+		}
+	}
+	if len(scheduler.Entries()) == 0 {
+		return
+	}
+	a.scriptCron.Store(scheduler)
+	scheduler.Start()
+	go func() {
+		<-ctx.Done()
+		scheduler.Stop()
+	}()
+}
+
+// runStopScripts runs the scripts that run on stop in order, and returns the
+// lifecycle state to report once they have finished.
+func (a *agent) runStopScripts(ctx context.Context, scripts []agentScript) codersdk.WorkspaceAgentLifecycle {
+	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
+	for _, script := range scripts {
+		if !script.RunOnStop {
+			continue
+		}
+		err := a.executeScript(ctx, script, scriptRunStop, func() {
+			a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleShutdownTimeout)
+		})
+		if err != nil {
+			lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownError
+		}
+	}
+	return lifecycleState
+}
+
+// executeScript runs a script and reports its status. If the script exceeds
+// its timeout, onTimeout is called and the script keeps running until it exits.
+func (a *agent) executeScript(ctx context.Context, script agentScript, run scriptRun, onTimeout func()) error {
+	logger := a.logger.With(slog.F("script", script.DisplayName))
+	startedAt := time.Now()
+	a.reportScriptStatus(ctx, script, agentsdk.PostScriptStatusRequest{
+		Status:    codersdk.WorkspaceAgentScriptStatusRunning,
+		StartedAt: &startedAt,
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- a.runScript(ctx, script, run)
+	}()
+	var timeout <-chan time.Time
+	if script.timeout > 0 {
+		t := time.NewTimer(script.timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	var (
+		err      error
+		timedOut bool
+	)
+	select {
+	case err = <-done:
+	case <-timeout:
+		timedOut = true
+		logger.Warn(ctx, "script timed out")
+		a.reportScriptStatus(ctx, script, agentsdk.PostScriptStatusRequest{
+			Status:    codersdk.WorkspaceAgentScriptStatusTimedOut,
+			StartedAt: &startedAt,
+		})
+		if onTimeout != nil {
+			onTimeout()
+		}
+		err = <-done // The script can still complete after a timeout.
+	}
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	completedAt := time.Now()
+	status := agentsdk.PostScriptStatusRequest{
+		Status:      codersdk.WorkspaceAgentScriptStatusOK,
+		StartedAt:   &startedAt,
+		CompletedAt: &completedAt,
+	}
+	execTime := completedAt.Sub(startedAt)
+	if err != nil {
+		logger.Warn(ctx, "script failed", slog.F("execution_time", execTime), slog.Error(err))
+		status.Status = codersdk.WorkspaceAgentScriptStatusError
+		status.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			status.ExitCode = int32(exitErr.ExitCode())
+		}
+	} else {
+		logger.Info(ctx, "script completed", slog.F("execution_time", execTime))
+	}
+	if timedOut {
+		status.Status = codersdk.WorkspaceAgentScriptStatusTimedOut
+	}
+	a.reportScriptStatus(ctx, script, status)
+	return err
+}
+
+// reportScriptStatus reports the status of a script to coderd. Failures are
+// logged, since the status is informational.
+func (a *agent) reportScriptStatus(ctx context.Context, script agentScript, req agentsdk.PostScriptStatusRequest) {
+	if script.ID == uuid.Nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	err := a.client.PostScriptStatus(ctx, script.ID, req)
+	if err != nil && ctx.Err() == nil {
+		a.logger.Warn(ctx, "report script status", slog.F("script", script.DisplayName), slog.Error(err))
+	}
+}
+
+// runScript runs a script and writes its output to the log file of the script.
+// Only the output of start runs is streamed to coderd: logs aren't streamed on
+// shutdown because the agent is closing, and cron runs would fill up the
+// startup logs of the agent. Cron runs are appended to the log file, other
+// runs replace it.
+func (a *agent) runScript(ctx context.Context, script agentScript, run scriptRun) error {
+	if script.Script == "" {
+		return nil
+	}
+
+	a.logger.Info(ctx, "running script", slog.F("name", script.DisplayName), slog.F("script", script.Script))
+	logPath := script.LogPath
+	if logPath == "" {
+		logPath = fmt.Sprintf("coder-script-%s.log", script.ID)
+	}
+	if !filepath.IsAbs(logPath) {
+		logPath = filepath.Join(a.logDir, logPath)
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if run == scriptRunCron {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	fileWriter, err := a.filesystem.OpenFile(logPath, flags, 0o600)
+	if err != nil {
+		return xerrors.Errorf("open %s script log file: %w", script.DisplayName, err)
+	}
+	defer func() {
+		_ = fileWriter.Close()
+	}()
+
+	var writer io.Writer = fileWriter
+	if run == scriptRunStart {
+		// Create pipes for script logs reader and writer
+		logsReader, logsWriter := io.Pipe()
+		defer func() {
+			_ = logsReader.Close()
+		}()
+		writer = io.MultiWriter(fileWriter, logsWriter)
+		flushedLogs, err := a.trackScriptLogs(ctx, script.ID, logsReader)
+		if err != nil {
+			return xerrors.Errorf("track script logs: %w", err)
+		}
+		defer func() {
+			_ = logsWriter.Close()
+			<-flushedLogs
+		}()
+	}
+
+	cmd, err := a.sshServer.CreateCommand(ctx, script.Script, nil)
+	if err != nil {
+		return xerrors.Errorf("create command: %w", err)
+	}
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	err = cmd.Run()
+	if err != nil {
+		// cmd.Run does not return a context canceled error, it returns "signal: killed".
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return xerrors.Errorf("run: %w", err)
+	}
+	return nil
+}
+
+// trackScriptLogs streams the output of a script to coderd as the log source
+// of the script.
+func (a *agent) trackScriptLogs(ctx context.Context, scriptID uuid.UUID, reader io.Reader) (chan struct{}, error) {
+	// Initialize variables for log management
+	queuedLogs := make([]agentsdk.StartupLog, 0)
+	var flushLogsTimer *time.Timer
+	var logMutex sync.Mutex
+	logsFlushed := sync.NewCond(&sync.Mutex{})
+	var logsSending bool
+	defer func() {
+		logMutex.Lock()
+		if flushLogsTimer != nil {
+			flushLogsTimer.Stop()
+		}
+		logMutex.Unlock()
+	}()
+
+	// sendLogs function uploads the queued logs to the server
+	sendLogs := func() {
+		// Lock logMutex and check if logs are already being sent
+		logMutex.Lock()
+		if logsSending {
+			logMutex.Unlock()
+			return
+		}
+		if flushLogsTimer != nil {
+			flushLogsTimer.Stop()
+		}
+		if len(queuedLogs) == 0 {
+			logMutex.Unlock()
+			return
+		}
+		// Move the current queued logs to logsToSend and clear the queue
+		logsToSend := queuedLogs
+		logsSending = true
+		queuedLogs = make([]agentsdk.StartupLog, 0)
+		logMutex.Unlock()
+
+		// Retry uploading logs until successful or a specific error occurs
+		for r := retry.New(time.Second, 5*time.Second); r.Wait(ctx); {
+			err := a.client.PatchStartupLogs(ctx, agentsdk.PatchStartupLogs{
+				ScriptID: scriptID,
+				Logs:     logsToSend,
+			})
+			if err == nil {
+				break
+			}
+			var sdkErr *codersdk.Error
+			if errors.As(err, &sdkErr) {
+				if sdkErr.StatusCode() == http.StatusRequestEntityTooLarge {
+					a.logger.Warn(ctx, "startup logs too large, dropping logs")
+					break
+				}
+			}
+			a.logger.Error(ctx, "upload startup logs", slog.Error(err), slog.F("to_send", logsToSend))
+		}
+		// Reset logsSending flag
+		logMutex.Lock()
+		logsSending = false
+		flushLogsTimer.Reset(100 * time.Millisecond)
+		logMutex.Unlock()
+		logsFlushed.Broadcast()
+	}
+	// queueLog function appends a log to the queue and triggers sendLogs if necessary
+	queueLog := func(log agentsdk.StartupLog) {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+
+		// Append log to the queue
+		queuedLogs = append(queuedLogs, log)
+
+		// If there are more than 100 logs, send them immediately
+		if len(queuedLogs) > 100 {
+			// Don't early return after this, because we still want
+			// to reset the timer just in case logs come in while
+			// we're sending.
+			go sendLogs()
+		}
+		// Reset or set the flushLogsTimer to trigger sendLogs after 100 milliseconds
+		if flushLogsTimer != nil {
+			flushLogsTimer.Reset(100 * time.Millisecond)
+			return
+		}
+		flushLogsTimer = time.AfterFunc(100*time.Millisecond, sendLogs)
+	}
+
+	// It's important that we either flush or drop all logs before returning
+	// because the startup state is reported after flush.
+	//
+	// It'd be weird for the startup state to be ready, but logs are still
+	// coming in.
+	logsFinished := make(chan struct{})
+	err := a.trackConnGoroutine(func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			queueLog(agentsdk.StartupLog{
+				CreatedAt: database.Now(),
+				Output:    scanner.Text(),
+			})
+		}
+		defer close(logsFinished)
+		logsFlushed.L.Lock()
+		for {
+			logMutex.Lock()
+			if len(queuedLogs) == 0 {
+				logMutex.Unlock()
+				break
+			}
+			logMutex.Unlock()
+			logsFlushed.Wait()
+		}
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("track conn goroutine: %w", err)
+	}
+	return logsFinished, nil
+}
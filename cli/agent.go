@@ -23,7 +23,9 @@ import (
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/sloghuman"
 	"github.com/coder/coder/agent"
+	"github.com/coder/coder/agent/agentcontainers"
 	"github.com/coder/coder/agent/reaper"
+	"github.com/coder/coder/agent/reconnectingpty"
 	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/cli/clibase"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -36,8 +38,13 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		pprofAddress      string
 		noReap            bool
 		sshMaxTimeout     time.Duration
+		sshListenAddress  string
 		tailnetListenPort int64
 		prometheusAddress string
+		debugLogLevel     string
+		ptyBackend        string
+		devcontainers     bool
+		devcontainerCLI   string
 	)
 	cmd := &clibase.Cmd{
 		Use:   "agent",
@@ -102,7 +109,19 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			logWriter := &closeWriter{w: ljLogger}
 			defer logWriter.Close()
 
-			logger := slog.Make(sloghuman.Sink(inv.Stderr), sloghuman.Sink(logWriter)).Leveled(slog.LevelDebug)
+			// Keep recent logs in memory so they can be uploaded to coderd
+			// for troubleshooting.
+			debugLogs := agent.NewDebugLogSink(1000)
+			logger := slog.Make(sloghuman.Sink(inv.Stderr), sloghuman.Sink(logWriter), debugLogs).Leveled(slog.LevelDebug)
+
+			var debugLogUploadLevel *slog.Level
+			if debugLogLevel != "none" {
+				level, err := parseDebugLogLevel(debugLogLevel)
+				if err != nil {
+					return err
+				}
+				debugLogUploadLevel = &level
+			}
 
 			version := buildinfo.Version()
 			logger.Info(ctx, "starting agent",
@@ -196,6 +215,13 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				return xerrors.Errorf("add executable to $PATH: %w", err)
 			}
 
+			var devcontainerOptions *agentcontainers.Options
+			if devcontainers {
+				devcontainerOptions = &agentcontainers.Options{
+					CLIPath: devcontainerCLI,
+				}
+			}
+
 			closer := agent.New(agent.Options{
 				Client:            client,
 				Logger:            logger,
@@ -215,8 +241,20 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				EnvironmentVariables: map[string]string{
 					"GIT_ASKPASS": executablePath,
 				},
-				AgentPorts:    agentPorts,
-				SSHMaxTimeout: sshMaxTimeout,
+				AgentPorts:             agentPorts,
+				SSHMaxTimeout:          sshMaxTimeout,
+				SSHListenAddress:       sshListenAddress,
+				DebugLogs:              debugLogs,
+				DebugLogUploadLevel:    debugLogUploadLevel,
+				ReconnectingPTYBackend: ptyBackend,
+				Devcontainers:          devcontainerOptions,
+				DevcontainerClient: func(token string) agent.Client {
+					devcontainerClient := agentsdk.New(r.agentURL)
+					devcontainerClient.SDK.Logger = logger
+					devcontainerClient.SDK.HTTPClient = client.SDK.HTTPClient
+					devcontainerClient.SetSessionToken(token)
+					return devcontainerClient
+				},
 			})
 			<-ctx.Done()
 			return closer.Close()
@@ -259,6 +297,12 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Description: "Specify the max timeout for a SSH connection.",
 			Value:       clibase.DurationOf(&sshMaxTimeout),
 		},
+		{
+			Flag:        "ssh-listen-address",
+			Env:         "CODER_AGENT_SSH_LISTEN_ADDRESS",
+			Description: "Serve SSH on this address for standard SSH clients that can't connect through the Coder network, e.g. 0.0.0.0:2222. Clients must authenticate with a certificate from \"coder ssh-cert\".",
+			Value:       clibase.StringOf(&sshListenAddress),
+		},
 		{
 			Flag:        "tailnet-listen-port",
 			Default:     "0",
@@ -273,11 +317,53 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Value:       clibase.StringOf(&prometheusAddress),
 			Description: "The bind address to serve Prometheus metrics.",
 		},
+		{
+			Flag:        "debug-log-upload-level",
+			Default:     "warn",
+			Env:         "CODER_AGENT_DEBUG_LOG_UPLOAD_LEVEL",
+			Description: "The minimum level of the agent's own logs that are continuously uploaded to coderd. Logs below this level are only uploaded when requested by an admin. Set to none to only upload on request.",
+			Value:       clibase.EnumOf(&debugLogLevel, "none", "debug", "info", "warn", "error"),
+		},
+		{
+			Flag:        "reconnecting-pty-backend",
+			Default:     reconnectingpty.BackendAuto,
+			Env:         "CODER_AGENT_RECONNECTING_PTY_BACKEND",
+			Description: "Where to run the sessions of the web terminal and \"coder ssh --session\". Sessions in tmux or screen keep running when the agent restarts, buffered sessions are kept in the memory of the agent. The auto backend uses tmux or screen if one of them is installed. Sessions end after five minutes without connections.",
+			Value:       clibase.EnumOf(&ptyBackend, reconnectingpty.Backends...),
+		},
+		{
+			Flag:        "devcontainers",
+			Env:         "CODER_AGENT_DEVCONTAINERS_ENABLE",
+			Description: "Start the dev containers of the workspace directory and its folders after the startup script, and connect to each of them with a separate agent. Requires the devcontainer CLI and docker.",
+			Value:       clibase.BoolOf(&devcontainers),
+		},
+		{
+			Flag:        "devcontainer-cli-path",
+			Default:     "devcontainer",
+			Env:         "CODER_AGENT_DEVCONTAINER_CLI_PATH",
+			Description: "The path of the devcontainer CLI.",
+			Value:       clibase.StringOf(&devcontainerCLI),
+		},
 	}
 
 	return cmd
 }
 
+func parseDebugLogLevel(level string) (slog.Level, error) {
+	switch level {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, xerrors.Errorf("unknown debug log upload level %q", level)
+	}
+}
+
 func ServeHandler(ctx context.Context, logger slog.Logger, handler http.Handler, addr, name string) (closeFunc func()) {
 	logger.Debug(ctx, "http server listening", slog.F("addr", addr), slog.F("name", name))
 
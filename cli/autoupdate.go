@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) autoupdate() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "autoupdate <workspace> <always|never>",
+		Short:       "Toggle automatic updates for a workspace",
+		Long: "Workspaces that update automatically are started on the active version of their template every time they start. " +
+			"If the parameters of the workspace are invalid for the active version, the workspace is started on its current version instead.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			policy := codersdk.AutomaticUpdates(strings.ToLower(inv.Args[1]))
+			switch policy {
+			case codersdk.AutomaticUpdatesAlways, codersdk.AutomaticUpdatesNever:
+			default:
+				return xerrors.Errorf("invalid option %q, must be %q or %q", inv.Args[1], codersdk.AutomaticUpdatesAlways, codersdk.AutomaticUpdatesNever)
+			}
+
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			err = client.UpdateWorkspaceAutomaticUpdates(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceAutomaticUpdatesRequest{
+				AutomaticUpdates: policy,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace automatic updates: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated workspace %s auto-update policy to %s\n", cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.Keyword.Render(string(policy)))
+			if policy == codersdk.AutomaticUpdatesNever && workspace.TemplateRequireActiveVersion {
+				cliui.Warn(inv.Stderr, "The template of the workspace requires the active version, so the workspace is still updated when it starts.")
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/google/uuid"
 	"github.com/muesli/reflow/indent"
 	"github.com/muesli/reflow/wordwrap"
 	"golang.org/x/xerrors"
@@ -25,9 +26,12 @@ var (
 type AgentOptions struct {
 	WorkspaceName string
 	Fetch         func(context.Context) (codersdk.WorkspaceAgent, error)
+	// FetchLogs is optional, when set the startup logs of the agent are
+	// streamed to the writer while waiting for the agent to be ready.
+	FetchLogs     func(ctx context.Context, agentID uuid.UUID, after int64) (<-chan []codersdk.WorkspaceAgentStartupLog, io.Closer, error)
 	FetchInterval time.Duration
 	WarnInterval  time.Duration
-	NoWait        bool // If true, don't wait for the agent to be ready.
+	Wait          bool // If true, wait for the agent to be ready (startup script).
 }
 
 // Agent displays a spinning indicator that waits for a workspace agent to connect.
@@ -45,10 +49,10 @@ func Agent(ctx context.Context, writer io.Writer, opts AgentOptions) error {
 	}
 
 	// Fast path if the agent is ready (avoid showing connecting prompt).
-	// We don't take the fast path for opts.NoWait yet because we want to
+	// We don't take the fast path for !opts.Wait yet because we want to
 	// show the message.
 	if agent.Status == codersdk.WorkspaceAgentConnected &&
-		(agent.LoginBeforeReady || agent.LifecycleState == codersdk.WorkspaceAgentLifecycleReady) {
+		agent.LifecycleState == codersdk.WorkspaceAgentLifecycleReady {
 		return nil
 	}
 
@@ -61,10 +65,17 @@ func Agent(ctx context.Context, writer io.Writer, opts AgentOptions) error {
 	spin.Suffix = waitingMessage(agent, opts).Spin
 
 	waitMessage := &message{}
+	streamingLogs := false
 	showMessage := func() {
 		resourceMutex.Lock()
 		defer resourceMutex.Unlock()
 
+		// Messages would be interleaved with the startup logs, the logs
+		// are more useful so we keep quiet until they have been streamed.
+		if streamingLogs {
+			return
+		}
+
 		m := waitingMessage(agent, opts)
 		if m.Prompt == waitMessage.Prompt {
 			return
@@ -95,8 +106,7 @@ func Agent(ctx context.Context, writer io.Writer, opts AgentOptions) error {
 	// Fast path for showing the error message even when using no wait,
 	// we do this just before starting the spinner to avoid needless
 	// spinning.
-	if agent.Status == codersdk.WorkspaceAgentConnected &&
-		!agent.LoginBeforeReady && opts.NoWait {
+	if agent.Status == codersdk.WorkspaceAgentConnected && !opts.Wait {
 		showMessage()
 		return nil
 	}
@@ -120,6 +130,45 @@ func Agent(ctx context.Context, writer io.Writer, opts AgentOptions) error {
 		}
 	}()
 
+	// streamLogs writes the startup logs of the agent to the writer
+	// until the agent has finished starting or the stream is closed.
+	var (
+		lastLogID       int64
+		logsHeaderShown bool
+	)
+	streamLogs := func(agentID uuid.UUID) error {
+		logStream, logsCloser, err := opts.FetchLogs(ctx, agentID, lastLogID)
+		if err != nil {
+			return xerrors.Errorf("fetch startup logs: %w", err)
+		}
+		defer logsCloser.Close()
+
+		resourceMutex.Lock()
+		spin.Stop()
+		if !logsHeaderShown {
+			_, _ = fmt.Fprintf(writer, "\033[2K==> %s\n", Styles.Field.Render("Running workspace agent startup script..."))
+			logsHeaderShown = true
+		}
+		streamingLogs = true
+		resourceMutex.Unlock()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case logs, ok := <-logStream:
+				if !ok {
+					return nil
+				}
+				for _, log := range logs {
+					_, _ = fmt.Fprintln(writer, log.Output)
+					lastLogID = log.ID
+				}
+			}
+		}
+	}
+	logsFailed := false
+
 	fetchInterval := time.NewTicker(opts.FetchInterval)
 	defer fetchInterval.Stop()
 	for {
@@ -137,10 +186,24 @@ func Agent(ctx context.Context, writer io.Writer, opts AgentOptions) error {
 		resourceMutex.Unlock()
 		switch agent.Status {
 		case codersdk.WorkspaceAgentConnected:
-			// NOTE(mafredri): Once we have access to the workspace agent's
-			// startup script logs, we can show them here.
-			// https://github.com/coder/coder/issues/2957
-			if !agent.LoginBeforeReady && !opts.NoWait {
+			if opts.Wait {
+				if opts.FetchLogs != nil && !logsFailed && agent.LifecycleState.Starting() {
+					// The stream is closed by coderd once the startup
+					// script has finished, after which the lifecycle
+					// state is fetched again. A failure to stream logs
+					// isn't fatal, we fall back to waiting on the
+					// lifecycle state.
+					err = streamLogs(agent.ID)
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					logsFailed = err != nil
+					continue
+				}
+				resourceMutex.Lock()
+				streamingLogs = false
+				resourceMutex.Unlock()
+
 				switch agent.LifecycleState {
 				case codersdk.WorkspaceAgentLifecycleReady:
 					return nil
@@ -183,7 +246,7 @@ func waitingMessage(agent codersdk.WorkspaceAgent, opts AgentOptions) (m *messag
 		Prompt: "Don't panic, your workspace is booting up!",
 	}
 	defer func() {
-		if agent.Status == codersdk.WorkspaceAgentConnected && opts.NoWait {
+		if agent.Status == codersdk.WorkspaceAgentConnected && !opts.Wait {
 			m.Spin = ""
 		}
 		if m.Spin != "" {
@@ -225,7 +288,7 @@ func waitingMessage(agent codersdk.WorkspaceAgent, opts AgentOptions) (m *messag
 	case codersdk.WorkspaceAgentConnected:
 		m.Spin = fmt.Sprintf("Waiting for %s to become ready...", Styles.Field.Render(agent.Name))
 		m.Prompt = "Don't panic, your workspace agent has connected and the workspace is getting ready!"
-		if opts.NoWait {
+		if !opts.Wait {
 			m.Prompt = "Your workspace is still getting ready, it may be in an incomplete state."
 		}
 
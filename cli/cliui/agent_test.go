@@ -2,9 +2,11 @@ package cliui_test
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -30,8 +32,7 @@ func TestAgent(t *testing.T) {
 				WorkspaceName: "example",
 				Fetch: func(_ context.Context) (codersdk.WorkspaceAgent, error) {
 					agent := codersdk.WorkspaceAgent{
-						Status:           codersdk.WorkspaceAgentDisconnected,
-						LoginBeforeReady: true,
+						Status: codersdk.WorkspaceAgentDisconnected,
 					}
 					if disconnected.Load() {
 						agent.Status = codersdk.WorkspaceAgentConnected
@@ -75,7 +76,6 @@ func TestAgent_TimeoutWithTroubleshootingURL(t *testing.T) {
 					agent := codersdk.WorkspaceAgent{
 						Status:             codersdk.WorkspaceAgentConnecting,
 						TroubleshootingURL: wantURL,
-						LoginBeforeReady:   true,
 					}
 					switch {
 					case !connected.Load() && timeout.Load():
@@ -125,7 +125,6 @@ func TestAgent_StartupTimeout(t *testing.T) {
 				Fetch: func(_ context.Context) (codersdk.WorkspaceAgent, error) {
 					agent := codersdk.WorkspaceAgent{
 						Status:             codersdk.WorkspaceAgentConnecting,
-						LoginBeforeReady:   false,
 						LifecycleState:     codersdk.WorkspaceAgentLifecycleCreated,
 						TroubleshootingURL: wantURL,
 					}
@@ -140,7 +139,7 @@ func TestAgent_StartupTimeout(t *testing.T) {
 				},
 				FetchInterval: time.Millisecond,
 				WarnInterval:  time.Millisecond,
-				NoWait:        false,
+				Wait:          true,
 			})
 			return err
 		},
@@ -184,7 +183,6 @@ func TestAgent_StartErrorExit(t *testing.T) {
 				Fetch: func(_ context.Context) (codersdk.WorkspaceAgent, error) {
 					agent := codersdk.WorkspaceAgent{
 						Status:             codersdk.WorkspaceAgentConnecting,
-						LoginBeforeReady:   false,
 						LifecycleState:     codersdk.WorkspaceAgentLifecycleCreated,
 						TroubleshootingURL: wantURL,
 					}
@@ -199,7 +197,7 @@ func TestAgent_StartErrorExit(t *testing.T) {
 				},
 				FetchInterval: time.Millisecond,
 				WarnInterval:  60 * time.Second,
-				NoWait:        false,
+				Wait:          true,
 			})
 			return err
 		},
@@ -240,7 +238,6 @@ func TestAgent_NoWait(t *testing.T) {
 				Fetch: func(_ context.Context) (codersdk.WorkspaceAgent, error) {
 					agent := codersdk.WorkspaceAgent{
 						Status:             codersdk.WorkspaceAgentConnecting,
-						LoginBeforeReady:   false,
 						LifecycleState:     codersdk.WorkspaceAgentLifecycleCreated,
 						TroubleshootingURL: wantURL,
 					}
@@ -255,7 +252,7 @@ func TestAgent_NoWait(t *testing.T) {
 				},
 				FetchInterval: time.Millisecond,
 				WarnInterval:  time.Second,
-				NoWait:        true,
+				Wait:          false,
 			})
 			return err
 		},
@@ -292,40 +289,31 @@ func TestAgent_NoWait(t *testing.T) {
 	require.NoError(t, <-done, "ready - should exit early")
 }
 
-func TestAgent_LoginBeforeReadyEnabled(t *testing.T) {
+func TestAgent_StartupLogs(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
 	defer cancel()
 
-	wantURL := "https://coder.com/this-is-a-really-long-troubleshooting-url-that-should-not-wrap"
-
-	var status, state atomic.String
-	setStatus := func(s codersdk.WorkspaceAgentStatus) { status.Store(string(s)) }
-	setState := func(s codersdk.WorkspaceAgentLifecycle) { state.Store(string(s)) }
+	var state atomic.String
+	state.Store(string(codersdk.WorkspaceAgentLifecycleStarting))
+	logs := make(chan []codersdk.WorkspaceAgentStartupLog, 1)
 	cmd := &clibase.Cmd{
 		Handler: func(inv *clibase.Invocation) error {
 			err := cliui.Agent(inv.Context(), inv.Stdout, cliui.AgentOptions{
 				WorkspaceName: "example",
 				Fetch: func(_ context.Context) (codersdk.WorkspaceAgent, error) {
-					agent := codersdk.WorkspaceAgent{
-						Status:             codersdk.WorkspaceAgentConnecting,
-						LoginBeforeReady:   true,
-						LifecycleState:     codersdk.WorkspaceAgentLifecycleCreated,
-						TroubleshootingURL: wantURL,
-					}
-
-					if s := status.Load(); s != "" {
-						agent.Status = codersdk.WorkspaceAgentStatus(s)
-					}
-					if s := state.Load(); s != "" {
-						agent.LifecycleState = codersdk.WorkspaceAgentLifecycle(s)
-					}
-					return agent, nil
+					return codersdk.WorkspaceAgent{
+						Status:         codersdk.WorkspaceAgentConnected,
+						LifecycleState: codersdk.WorkspaceAgentLifecycle(state.Load()),
+					}, nil
+				},
+				FetchLogs: func(_ context.Context, _ uuid.UUID, _ int64) (<-chan []codersdk.WorkspaceAgentStartupLog, io.Closer, error) {
+					return logs, closeFunc(func() error { return nil }), nil
 				},
 				FetchInterval: time.Millisecond,
 				WarnInterval:  time.Second,
-				NoWait:        false,
+				Wait:          true,
 			})
 			return err
 		},
@@ -339,25 +327,14 @@ func TestAgent_LoginBeforeReadyEnabled(t *testing.T) {
 	go func() {
 		done <- inv.WithContext(ctx).Run()
 	}()
-	setStatus(codersdk.WorkspaceAgentConnecting)
-	ptty.ExpectMatchContext(ctx, "Don't panic, your workspace is booting")
-
-	setStatus(codersdk.WorkspaceAgentConnected)
-	require.NoError(t, <-done, "created - should exit early")
-
-	setState(codersdk.WorkspaceAgentLifecycleStarting)
-	go func() { done <- inv.WithContext(ctx).Run() }()
-	require.NoError(t, <-done, "starting - should exit early")
-
-	setState(codersdk.WorkspaceAgentLifecycleStartTimeout)
-	go func() { done <- inv.WithContext(ctx).Run() }()
-	require.NoError(t, <-done, "start timeout - should exit early")
-
-	setState(codersdk.WorkspaceAgentLifecycleStartError)
-	go func() { done <- inv.WithContext(ctx).Run() }()
-	require.NoError(t, <-done, "start error - should exit early")
-
-	setState(codersdk.WorkspaceAgentLifecycleReady)
-	go func() { done <- inv.WithContext(ctx).Run() }()
-	require.NoError(t, <-done, "ready - should exit early")
+	ptty.ExpectMatchContext(ctx, "Running workspace agent startup script")
+	logs <- []codersdk.WorkspaceAgentStartupLog{{ID: 1, Output: "Installing dependencies"}}
+	ptty.ExpectMatchContext(ctx, "Installing dependencies")
+	logs <- []codersdk.WorkspaceAgentStartupLog{{ID: 2, Output: "Done"}}
+	ptty.ExpectMatchContext(ctx, "Done")
+
+	// The stream is closed once the startup script has finished.
+	state.Store(string(codersdk.WorkspaceAgentLifecycleReady))
+	close(logs)
+	require.NoError(t, <-done)
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/google/uuid"
 	"github.com/kirsle/configdir"
 	"golang.org/x/xerrors"
 )
@@ -46,6 +47,13 @@ func (r Root) Organization() File {
 	return File(filepath.Join(string(r), "organization"))
 }
 
+// Proxy is the workspace proxy that connections to workspaces are relayed
+// through.
+func (r Root) Proxy() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "proxy"))
+}
+
 func (r Root) DotfilesURL() File {
 	r.mustNotEmpty()
 	return File(filepath.Join(string(r), "dotfilesurl"))
@@ -66,6 +74,25 @@ func (r Root) PostgresPort() File {
 	return File(filepath.Join(r.PostgresPath(), "port"))
 }
 
+// SSHPath is the directory of the key and certificates used to connect to
+// workspaces with standard SSH clients.
+func (r Root) SSHPath() string {
+	r.mustNotEmpty()
+	return filepath.Join(string(r), "ssh")
+}
+
+func (r Root) SSHKey() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(r.SSHPath(), "id_ed25519"))
+}
+
+// SSHCertificate is the certificate for the SSH key that grants access to the
+// workspace.
+func (r Root) SSHCertificate(workspaceID uuid.UUID) File {
+	r.mustNotEmpty()
+	return File(filepath.Join(r.SSHPath(), workspaceID.String()+"-cert.pub"))
+}
+
 // File provides convenience methods for interacting with *os.File.
 type File string
 
@@ -0,0 +1,495 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) cp() *clibase.Cmd {
+	var (
+		recursive bool
+		resume    bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "cp <source> <destination>",
+		Short: "Copy files to and from a workspace",
+		Long: "Exactly one of the source and destination must be in a workspace, written as <workspace[.agent]>:<path>. " +
+			"Relative workspace paths are resolved against the home directory. File permissions are preserved.\n" + formatExamples(
+			example{
+				Description: "Copy a file into the home directory of a workspace",
+				Command:     "coder cp ./notes.txt my-workspace:",
+			},
+			example{
+				Description: "Copy a directory out of the \"dev\" agent of a workspace",
+				Command:     "coder cp --recursive my-workspace.dev:projects/app ./app",
+			},
+			example{
+				Description: "Resume an interrupted transfer of a large file",
+				Command:     "coder cp --resume ./dataset.tar my-workspace:/tmp/dataset.tar",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			src, dst := parseCopyPath(inv.Args[0]), parseCopyPath(inv.Args[1])
+			if (src.workspace == "") == (dst.workspace == "") {
+				return xerrors.New("exactly one of the source and destination must be a workspace path, like my-workspace:path")
+			}
+			remote := src
+			if dst.workspace != "" {
+				remote = dst
+			}
+
+			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, remote.workspace)
+			if err != nil {
+				return err
+			}
+			err = cliui.Agent(ctx, inv.Stderr, cliui.AgentOptions{
+				WorkspaceName: workspace.Name,
+				Fetch: func(ctx context.Context) (codersdk.WorkspaceAgent, error) {
+					return client.WorkspaceAgent(ctx, workspaceAgent.ID)
+				},
+				Wait: !workspaceAgent.LoginBeforeReady,
+			})
+			if err != nil && !xerrors.Is(err, cliui.AgentStartError) {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			logger, ok := LoggerFromContext(ctx)
+			if !ok {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr))
+			}
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger: logger,
+			})
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			if !conn.AwaitReachable(ctx) {
+				return xerrors.Errorf("workspace agent not reachable: %w", ctx.Err())
+			}
+
+			c := &copier{
+				conn:      conn,
+				recursive: recursive,
+				resume:    resume,
+				progress:  inv.Stderr,
+				tty:       isTTYErr(inv),
+			}
+			start := time.Now()
+			if dst.workspace != "" {
+				err = c.upload(ctx, src.path, dst.path)
+			} else {
+				err = c.download(ctx, src.path, dst.path)
+			}
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Copied %d file(s), %s in %s.\n", c.files, formatBytes(c.bytes), time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "recursive",
+			FlagShorthand: "r",
+			Description:   "Copy directories recursively.",
+			Value:         clibase.BoolOf(&recursive),
+		},
+		{
+			Flag:        "resume",
+			Description: "Resume interrupted transfers. A partially copied destination file is continued if its contents match the start of the source file.",
+			Value:       clibase.BoolOf(&resume),
+		},
+	}
+	return cmd
+}
+
+type copyPath struct {
+	// workspace is empty for local paths.
+	workspace string
+	path      string
+}
+
+// parseCopyPath splits "workspace[.agent]:path" into its parts. Arguments
+// without a colon, or with a path separator or Windows drive letter before the
+// first colon, are local paths.
+func parseCopyPath(arg string) copyPath {
+	i := strings.Index(arg, ":")
+	if i <= 0 || strings.ContainsAny(arg[:i], `/\`) || (runtime.GOOS == "windows" && i == 1) {
+		return copyPath{path: arg}
+	}
+	p := arg[i+1:]
+	if p == "" {
+		p = "~"
+	}
+	return copyPath{workspace: arg[:i], path: p}
+}
+
+type copier struct {
+	conn      *codersdk.WorkspaceAgentConn
+	recursive bool
+	resume    bool
+	progress  io.Writer
+	tty       bool
+
+	files int
+	bytes int64
+}
+
+func (c *copier) upload(ctx context.Context, src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return xerrors.Errorf("stat %q: %w", src, err)
+	}
+	if info.IsDir() && !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy it", src)
+	}
+	dstInfo, err := c.conn.StatFile(ctx, dst)
+	if err == nil && dstInfo.IsDir {
+		dst = path.Join(dstInfo.Path, filepath.Base(src))
+	} else if err != nil && !isNotFound(err) {
+		return xerrors.Errorf("stat %q in workspace: %w", dst, err)
+	}
+
+	if !info.IsDir() {
+		return c.uploadFile(ctx, src, dst, info)
+	}
+	// Directories stay writable until their children are written, so
+	// read-only directories can be copied too. Their modes are applied
+	// afterwards, children first.
+	type directory struct {
+		path string
+		mode os.FileMode
+	}
+	var directories []directory
+	err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		remote := path.Join(dst, filepath.ToSlash(rel))
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			err = c.conn.MakeDirectory(ctx, remote, info.Mode()|0o700)
+			if err != nil {
+				return xerrors.Errorf("create directory %q in workspace: %w", remote, err)
+			}
+			directories = append(directories, directory{path: remote, mode: info.Mode()})
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			_, _ = fmt.Fprintf(c.progress, "Skipping %s: not a regular file\n", p)
+			return nil
+		}
+		return c.uploadFile(ctx, p, remote, info)
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(directories) - 1; i >= 0; i-- {
+		err = c.conn.MakeDirectory(ctx, directories[i].path, directories[i].mode)
+		if err != nil {
+			return xerrors.Errorf("chmod %q in workspace: %w", directories[i].path, err)
+		}
+	}
+	return nil
+}
+
+func (c *copier) uploadFile(ctx context.Context, src, dst string, info os.FileInfo) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return xerrors.Errorf("open %q: %w", src, err)
+	}
+	defer file.Close()
+
+	var offset int64
+	if c.resume {
+		remote, err := c.conn.StatFile(ctx, dst)
+		if err == nil && !remote.IsDir && remote.Size <= info.Size() {
+			offset, err = c.resumeOffset(ctx, file, dst, remote.Size)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return xerrors.Errorf("seek %q: %w", src, err)
+	}
+
+	pw := c.newProgress(dst, offset, info.Size())
+	err = c.conn.WriteFile(ctx, dst, offset, info.Mode(), io.TeeReader(file, pw))
+	pw.finish(err)
+	if err != nil {
+		return xerrors.Errorf("write %q in workspace: %w", dst, err)
+	}
+	c.files++
+	c.bytes += info.Size() - offset
+	return nil
+}
+
+// resumeOffset returns the size of the partial remote file if it matches the
+// start of the local file, or zero if the transfer has to start over.
+func (c *copier) resumeOffset(ctx context.Context, local *os.File, remote string, size int64) (int64, error) {
+	remoteHash, err := c.conn.HashFile(ctx, remote, size)
+	if err != nil {
+		return 0, xerrors.Errorf("hash %q in workspace: %w", remote, err)
+	}
+	localHash, err := hashPrefix(local, size)
+	if err != nil {
+		return 0, xerrors.Errorf("hash %q: %w", local.Name(), err)
+	}
+	if remoteHash.Length != size || remoteHash.SHA256 != localHash {
+		return 0, nil
+	}
+	return size, nil
+}
+
+func (c *copier) download(ctx context.Context, src, dst string) error {
+	info, err := c.conn.StatFile(ctx, src)
+	if err != nil {
+		return xerrors.Errorf("stat %q in workspace: %w", src, err)
+	}
+	if info.IsDir && !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy it", info.Path)
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && dstInfo.IsDir() {
+		dst = filepath.Join(dst, info.Name)
+	}
+	return c.downloadTree(ctx, info, dst)
+}
+
+func (c *copier) downloadTree(ctx context.Context, info codersdk.WorkspaceAgentFileInfo, dst string) error {
+	if !info.IsDir {
+		return c.downloadFile(ctx, info, dst)
+	}
+
+	// The directory stays writable until its children are written, so
+	// read-only directories can be copied too.
+	mode := os.FileMode(info.Mode)
+	err := os.MkdirAll(dst, 0o700)
+	if err != nil {
+		return xerrors.Errorf("create directory %q: %w", dst, err)
+	}
+	err = os.Chmod(dst, mode|0o700)
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", dst, err)
+	}
+	children, err := c.conn.ListFiles(ctx, info.Path)
+	if err != nil {
+		return xerrors.Errorf("list %q in workspace: %w", info.Path, err)
+	}
+	for _, child := range children.Files {
+		childDst, err := childPath(dst, child.Name)
+		if err != nil {
+			return xerrors.Errorf("list %q in workspace: %w", info.Path, err)
+		}
+		err = c.downloadTree(ctx, child, childDst)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.Chmod(dst, mode)
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", dst, err)
+	}
+	return nil
+}
+
+func (c *copier) downloadFile(ctx context.Context, info codersdk.WorkspaceAgentFileInfo, dst string) error {
+	mode := os.FileMode(info.Mode)
+	// An existing file may be read-only from an earlier copy.
+	if _, err := os.Lstat(dst); err == nil {
+		err = os.Chmod(dst, mode|0o600)
+		if err != nil {
+			return xerrors.Errorf("chmod %q: %w", dst, err)
+		}
+	}
+	file, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE, mode|0o600)
+	if err != nil {
+		return xerrors.Errorf("open %q: %w", dst, err)
+	}
+	defer file.Close()
+
+	var offset int64
+	if c.resume {
+		local, err := file.Stat()
+		if err != nil {
+			return xerrors.Errorf("stat %q: %w", dst, err)
+		}
+		if local.Size() <= info.Size {
+			offset, err = c.resumeOffset(ctx, file, info.Path, local.Size())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = file.Truncate(offset)
+	if err != nil {
+		return xerrors.Errorf("truncate %q: %w", dst, err)
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return xerrors.Errorf("seek %q: %w", dst, err)
+	}
+
+	reader, _, err := c.conn.ReadFile(ctx, info.Path, offset)
+	if err != nil {
+		return xerrors.Errorf("read %q in workspace: %w", info.Path, err)
+	}
+	defer reader.Close()
+
+	pw := c.newProgress(info.Path, offset, info.Size)
+	_, err = io.Copy(io.MultiWriter(file, pw), reader)
+	pw.finish(err)
+	if err != nil {
+		return xerrors.Errorf("copy %q: %w", info.Path, err)
+	}
+	err = file.Close()
+	if err != nil {
+		return xerrors.Errorf("close %q: %w", dst, err)
+	}
+	err = os.Chmod(dst, mode)
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", dst, err)
+	}
+	c.files++
+	c.bytes += info.Size - offset
+	return nil
+}
+
+func (c *copier) newProgress(name string, done, total int64) *copyProgress {
+	return &copyProgress{
+		w:       c.progress,
+		tty:     c.tty,
+		name:    name,
+		resumed: done,
+		done:    done,
+		total:   total,
+	}
+}
+
+// copyProgress reports the progress of a single file transfer. Terminals get
+// a continuously updated line, other writers a single line per file.
+type copyProgress struct {
+	w       io.Writer
+	tty     bool
+	name    string
+	resumed int64
+	done    int64
+	total   int64
+	printed time.Time
+}
+
+func (p *copyProgress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if p.tty && time.Since(p.printed) > 100*time.Millisecond {
+		p.printed = time.Now()
+		_, _ = fmt.Fprintf(p.w, "\r%s", p.line())
+	}
+	return len(b), nil
+}
+
+func (p *copyProgress) finish(err error) {
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, "\r")
+	}
+	line := p.line()
+	if p.resumed > 0 {
+		line += fmt.Sprintf(" (resumed at %s)", formatBytes(p.resumed))
+	}
+	if err != nil {
+		line += " (failed)"
+	}
+	_, _ = fmt.Fprintln(p.w, line)
+}
+
+func (p *copyProgress) line() string {
+	percent := int64(100)
+	if p.total > 0 {
+		percent = p.done * 100 / p.total
+	}
+	return fmt.Sprintf("%s  %s / %s  %3d%%", p.name, formatBytes(p.done), formatBytes(p.total), percent)
+}
+
+func hashPrefix(file *os.File, length int64) (string, error) {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, io.LimitReader(file, length))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// childPath joins the name of a file listed by the workspace to the local
+// directory. Names are checked so a workspace can't write outside of dir.
+func childPath(dir, name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", xerrors.Errorf("invalid file name %q", name)
+	}
+	p := filepath.Join(dir, name)
+	if filepath.Dir(p) != filepath.Clean(dir) {
+		return "", xerrors.Errorf("invalid file name %q", name)
+	}
+	return p, nil
+}
+
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_childPath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p, err := childPath(dir, "file.txt")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "file.txt"), p)
+
+	// Names from a workspace must not escape the destination.
+	for _, name := range []string{"", ".", "..", "../escape", "a/b", `a\b`, "/etc"} {
+		_, err := childPath(dir, name)
+		require.Error(t, err, "name %q", name)
+	}
+}
@@ -0,0 +1,200 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows paths contain colons")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	runCp := func(t *testing.T, args ...string) error {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, append([]string{"cp"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		return inv.WithContext(ctx).Run()
+	}
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+		src := filepath.Join(t.TempDir(), "hello.sh")
+		err := os.WriteFile(src, []byte("echo hello"), 0o700)
+		require.NoError(t, err)
+		remote := filepath.Join(t.TempDir(), "remote.sh")
+
+		err = runCp(t, src, workspace.Name+":"+remote)
+		require.NoError(t, err)
+		info, err := os.Stat(remote)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+		dst := filepath.Join(t.TempDir(), "local.sh")
+		err = runCp(t, workspace.Name+":"+remote, dst)
+		require.NoError(t, err)
+		content, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "echo hello", string(content))
+		info, err = os.Stat(dst)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+	})
+
+	t.Run("DirectoryRequiresRecursive", func(t *testing.T) {
+		t.Parallel()
+		err := runCp(t, t.TempDir(), workspace.Name+":"+t.TempDir())
+		require.ErrorContains(t, err, "--recursive")
+	})
+
+	t.Run("Recursive", func(t *testing.T) {
+		t.Parallel()
+		src := filepath.Join(t.TempDir(), "project")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "nested"), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "nested", "b.txt"), []byte("b"), 0o600))
+
+		// Copying into an existing directory creates the source inside it.
+		remote := t.TempDir()
+		err := runCp(t, "-r", src, workspace.Name+":"+remote)
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(remote, "project", "nested", "b.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "b", string(content))
+
+		dst := filepath.Join(t.TempDir(), "copy")
+		err = runCp(t, "--recursive", workspace.Name+":"+filepath.Join(remote, "project"), dst)
+		require.NoError(t, err)
+		content, err = os.ReadFile(filepath.Join(dst, "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "a", string(content))
+		info, err := os.Stat(filepath.Join(dst, "nested"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		t.Parallel()
+		remote := filepath.Join(t.TempDir(), "readonly")
+		require.NoError(t, os.MkdirAll(filepath.Join(remote, "nested"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(remote, "nested", "a.txt"), []byte("a"), 0o444))
+		require.NoError(t, os.Chmod(filepath.Join(remote, "nested"), 0o555))
+		require.NoError(t, os.Chmod(remote, 0o555))
+		t.Cleanup(func() {
+			_ = os.Chmod(filepath.Join(remote, "nested"), 0o755)
+			_ = os.Chmod(remote, 0o755)
+		})
+
+		dst := filepath.Join(t.TempDir(), "copy")
+		t.Cleanup(func() {
+			_ = os.Chmod(filepath.Join(dst, "nested"), 0o755)
+			_ = os.Chmod(dst, 0o755)
+		})
+		err := runCp(t, "-r", workspace.Name+":"+remote, dst)
+		require.NoError(t, err)
+		// Copying again overwrites the read-only file of the first copy.
+		err = runCp(t, workspace.Name+":"+filepath.Join(remote, "nested", "a.txt"), filepath.Join(dst, "nested", "a.txt"))
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(dst, "nested", "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "a", string(content))
+		info, err := os.Stat(filepath.Join(dst, "nested", "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o444), info.Mode().Perm())
+		info, err = os.Stat(filepath.Join(dst, "nested"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o555), info.Mode().Perm())
+	})
+
+	t.Run("ReadOnlyUpload", func(t *testing.T) {
+		t.Parallel()
+		src := filepath.Join(t.TempDir(), "readonly")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "nested"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "nested", "a.txt"), []byte("a"), 0o444))
+		require.NoError(t, os.Chmod(filepath.Join(src, "nested"), 0o555))
+		require.NoError(t, os.Chmod(src, 0o555))
+		t.Cleanup(func() {
+			_ = os.Chmod(filepath.Join(src, "nested"), 0o755)
+			_ = os.Chmod(src, 0o755)
+		})
+
+		remote := filepath.Join(t.TempDir(), "copy")
+		t.Cleanup(func() {
+			_ = os.Chmod(filepath.Join(remote, "nested"), 0o755)
+			_ = os.Chmod(remote, 0o755)
+		})
+		err := runCp(t, "-r", src, workspace.Name+":"+remote)
+		require.NoError(t, err)
+		// Copying again overwrites the read-only file of the first copy.
+		err = runCp(t, filepath.Join(src, "nested", "a.txt"), workspace.Name+":"+filepath.Join(remote, "nested", "a.txt"))
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(remote, "nested", "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "a", string(content))
+		info, err := os.Stat(filepath.Join(remote, "nested", "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o444), info.Mode().Perm())
+		info, err = os.Stat(filepath.Join(remote, "nested"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o555), info.Mode().Perm())
+		info, err = os.Stat(remote)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o555), info.Mode().Perm())
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+		src := filepath.Join(t.TempDir(), "data")
+		require.NoError(t, os.WriteFile(src, []byte("0123456789"), 0o644))
+
+		// A matching partial file is continued.
+		partial := filepath.Join(t.TempDir(), "partial")
+		require.NoError(t, os.WriteFile(partial, []byte("01234"), 0o644))
+		err := runCp(t, "--resume", src, workspace.Name+":"+partial)
+		require.NoError(t, err)
+		content, err := os.ReadFile(partial)
+		require.NoError(t, err)
+		require.Equal(t, "0123456789", string(content))
+
+		// A partial file with different content is copied from the start.
+		mismatch := filepath.Join(t.TempDir(), "mismatch")
+		require.NoError(t, os.WriteFile(mismatch, []byte("abc"), 0o644))
+		err = runCp(t, "--resume", workspace.Name+":"+src, mismatch)
+		require.NoError(t, err)
+		content, err = os.ReadFile(mismatch)
+		require.NoError(t, err)
+		require.Equal(t, "0123456789", string(content))
+	})
+
+	t.Run("BothLocal", func(t *testing.T) {
+		t.Parallel()
+		err := runCp(t, "a", "b")
+		require.ErrorContains(t, err, "exactly one")
+	})
+}
@@ -24,6 +24,8 @@ func (r *RootCmd) create() *clibase.Cmd {
 		startAt           string
 		stopAfter         time.Duration
 		workspaceName     string
+		wait              string
+		automaticUpdates  string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -153,6 +155,7 @@ func (r *RootCmd) create() *clibase.Cmd {
 				TTLMillis:           ttlMillis,
 				ParameterValues:     buildParams.parameters,
 				RichParameterValues: buildParams.richParameters,
+				AutomaticUpdates:    codersdk.AutomaticUpdates(automaticUpdates),
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
@@ -162,6 +165,10 @@ func (r *RootCmd) create() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("watch build: %w", err)
 			}
+			err = awaitWorkspaceAgents(inv, client, workspace, workspace.LatestBuild.ID, wait)
+			if err != nil {
+				return err
+			}
 
 			_, _ = fmt.Fprintf(inv.Stdout, "\nThe %s workspace has been created at %s!\n", cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
 			return nil
@@ -199,6 +206,14 @@ func (r *RootCmd) create() *clibase.Cmd {
 			Description: "Specify a duration after which the workspace should shut down (e.g. 8h).",
 			Value:       clibase.DurationOf(&stopAfter),
 		},
+		clibase.Option{
+			Flag:        "automatic-updates",
+			Env:         "CODER_WORKSPACE_AUTOMATIC_UPDATES",
+			Description: "Specify automatic updates setting for the workspace (accepts 'always' or 'never').",
+			Default:     string(codersdk.AutomaticUpdatesNever),
+			Value:       clibase.EnumOf(&automaticUpdates, string(codersdk.AutomaticUpdatesAlways), string(codersdk.AutomaticUpdatesNever)),
+		},
+		agentWaitOption(&wait, "CODER_CREATE_WAIT", agentWaitNo),
 		cliui.SkipPromptOption(),
 	)
 
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/coder/coder/cli/clibase"
 	"github.com/coder/coder/cli/cliui"
@@ -32,6 +34,13 @@ type workspaceListRow struct {
 
 func workspaceListRowFromWorkspace(now time.Time, usersByID map[uuid.UUID]codersdk.User, workspace codersdk.Workspace) workspaceListRow {
 	status := codersdk.WorkspaceDisplayStatus(workspace.LatestBuild.Job.Status, workspace.LatestBuild.Transition)
+	if workspace.DormantAt != nil {
+		dormant := "Dormant"
+		if workspace.DeletingAt != nil {
+			dormant += ", deletes in " + durationDisplay(workspace.DeletingAt.Sub(now))
+		}
+		status = fmt.Sprintf("%s (%s)", status, dormant)
+	}
 
 	lastBuilt := now.UTC().Sub(workspace.LatestBuild.Job.CreatedAt).Truncate(time.Second)
 	autostartDisplay := "-"
@@ -51,11 +60,16 @@ func workspaceListRowFromWorkspace(now time.Time, usersByID map[uuid.UUID]coders
 		}
 	}
 
+	template := workspace.TemplateName
+	if workspace.TemplateDeprecationMessage != "" {
+		template += " (deprecated)"
+	}
+
 	user := usersByID[workspace.OwnerID]
 	return workspaceListRow{
 		Workspace:     workspace,
 		WorkspaceName: user.Username + "/" + workspace.Name,
-		Template:      workspace.TemplateName,
+		Template:      template,
 		Status:        status,
 		LastBuilt:     durationDisplay(lastBuilt),
 		Outdated:      workspace.Outdated,
@@ -127,7 +141,24 @@ func (r *RootCmd) list() *clibase.Cmd {
 			}
 
 			_, err = fmt.Fprintln(inv.Stdout, out)
-			return err
+			if err != nil {
+				return err
+			}
+
+			// Let users know why they won't be able to create new
+			// workspaces from the templates of their existing ones.
+			deprecated := map[string]string{}
+			for _, workspace := range res.Workspaces {
+				if workspace.TemplateDeprecationMessage != "" {
+					deprecated[workspace.TemplateName] = workspace.TemplateDeprecationMessage
+				}
+			}
+			templateNames := maps.Keys(deprecated)
+			slices.Sort(templateNames)
+			for _, templateName := range templateNames {
+				cliui.Warn(inv.Stderr, fmt.Sprintf("Template %q is deprecated", templateName), deprecated[templateName])
+			}
+			return nil
 		},
 	}
 	cmd.Options = clibase.OptionSet{
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) logs() *clibase.Cmd {
+	var noFlush bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "logs <workspace>",
+		Short:       "Print the debug logs uploaded by a workspace agent",
+		Long: "Requires access to deployment debug info.\n" + formatExamples(
+			example{
+				Description: "Print the debug logs of the agent named \"main\"",
+				Command:     "coder logs my-workspace.main",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			if !noFlush {
+				// The agent only uploads warnings and errors on its own,
+				// ask it to upload everything else it has buffered.
+				err = client.FlushWorkspaceAgentDebugLogs(ctx, workspaceAgent.ID)
+				if err != nil {
+					var sdkErr *codersdk.Error
+					if !xerrors.As(err, &sdkErr) || sdkErr.StatusCode() != http.StatusBadRequest {
+						return xerrors.Errorf("flush debug logs: %w", err)
+					}
+					// The agent isn't connected, print what has been
+					// uploaded so far.
+					cliui.Warnf(inv.Stderr, "Unable to flush debug logs: %s", sdkErr.Message)
+				}
+			}
+
+			logs, err := client.WorkspaceAgentDebugLogs(ctx, workspaceAgent.ID, 0)
+			if err != nil {
+				return xerrors.Errorf("get debug logs: %w", err)
+			}
+			for _, log := range logs {
+				_, _ = fmt.Fprintf(inv.Stdout, "%s [%s] %s\n",
+					log.CreatedAt.Local().Format("2006-01-02 15:04:05.000"), log.Level, log.Output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "no-flush",
+			Description: "Don't ask the agent to upload its buffered logs before printing. Only logs that were already uploaded are printed.",
+			Value:       clibase.BoolOf(&noFlush),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoFlush", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		err := agentClient.PatchDebugLogs(ctx, agentsdk.PatchDebugLogs{
+			Logs: []agentsdk.DebugLog{{
+				CreatedAt: database.Now(),
+				Output:    "agent: something went wrong",
+				Level:     codersdk.LogLevelError,
+			}},
+		})
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--no-flush")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "[error] agent: something went wrong")
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		sink := agent.NewDebugLogSink(1000)
+		agentCloser := agent.New(agent.Options{
+			Client:    agentClient,
+			Logger:    slogtest.Make(t, nil).Named("agent").AppendSinks(sink),
+			DebugLogs: sink,
+		})
+		defer func() {
+			_ = agentCloser.Close()
+		}()
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		inv, root := clitest.New(t, "logs", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "[info] agent: ")
+	})
+}
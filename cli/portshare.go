@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) port() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "port",
+		Short: "Share ports of a workspace with other users",
+		Long: "Shared ports are reachable through subdomain app URLs by other users, up to the maximum share level of the workspace's template.\n" + formatExamples(
+			example{
+				Description: "Share port 3000 of a workspace with all authenticated users",
+				Command:     "coder port share my-workspace 3000 --level authenticated",
+			},
+			example{
+				Description: "Share port 8443 of the \"dev\" agent publicly over HTTPS",
+				Command:     "coder port share my-workspace.dev 8443 --level public --protocol https",
+			},
+			example{
+				Description: "Stop sharing a port",
+				Command:     "coder port unshare my-workspace 3000",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.portShare(),
+			r.portUnshare(),
+			r.portShareList(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) portShare() *clibase.Cmd {
+	var (
+		level    string
+		protocol string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "share <workspace[.agent]> <port>",
+		Short: "Share a port of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			port, err := parseSharedPort(inv.Args[1])
+			if err != nil {
+				return err
+			}
+			workspace, agent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			share, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+				AgentName:  agent.Name,
+				Port:       port,
+				ShareLevel: codersdk.WorkspaceAppSharingLevel(level),
+				Protocol:   codersdk.WorkspacePortShareProtocol(protocol),
+			})
+			if err != nil {
+				return xerrors.Errorf("share port: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Port %s of agent %s is shared at level %s over %s.\n",
+				cliui.Styles.Keyword.Render(strconv.Itoa(int(share.Port))),
+				cliui.Styles.Keyword.Render(share.AgentName),
+				cliui.Styles.Keyword.Render(string(share.ShareLevel)),
+				share.Protocol,
+			)
+
+			scheme, appHost, err := r.appURLBase(inv, client)
+			if err != nil {
+				return err
+			}
+			if appHost != "" {
+				subdomain := httpapi.ApplicationURL{
+					AppSlugOrPort: strconv.Itoa(int(share.Port)),
+					AgentName:     share.AgentName,
+					WorkspaceName: workspace.Name,
+					Username:      workspace.OwnerName,
+				}.String()
+				_, _ = fmt.Fprintf(inv.Stdout, "URL: %s://%s\n", scheme, strings.Replace(appHost, "*", subdomain, 1))
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "level",
+			Description: "The share level of the port. Cannot exceed the maximum port share level of the workspace's template.",
+			Default:     string(codersdk.WorkspaceAppSharingLevelAuthenticated),
+			Value: clibase.EnumOf(&level,
+				string(codersdk.WorkspaceAppSharingLevelOwner),
+				string(codersdk.WorkspaceAppSharingLevelAuthenticated),
+				string(codersdk.WorkspaceAppSharingLevelPublic),
+			),
+		},
+		{
+			Flag:        "protocol",
+			Description: "The protocol used to proxy requests to the port.",
+			Default:     string(codersdk.WorkspacePortShareProtocolHTTP),
+			Value: clibase.EnumOf(&protocol,
+				string(codersdk.WorkspacePortShareProtocolHTTP),
+				string(codersdk.WorkspacePortShareProtocolHTTPS),
+			),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) portUnshare() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "unshare <workspace[.agent]> <port>",
+		Short: "Stop sharing a port of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			port, err := parseSharedPort(inv.Args[1])
+			if err != nil {
+				return err
+			}
+			workspace, agent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+				AgentName: agent.Name,
+				Port:      port,
+			})
+			if err != nil {
+				return xerrors.Errorf("unshare port: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Port %s of agent %s is no longer shared.\n",
+				cliui.Styles.Keyword.Render(strconv.Itoa(int(port))),
+				cliui.Styles.Keyword.Render(agent.Name),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+type portShareRow struct {
+	codersdk.WorkspacePortShare `table:"-"`
+
+	// For table format:
+	Agent    string `json:"-" table:"agent,default_sort"`
+	Port     int32  `json:"-" table:"port"`
+	Level    string `json:"-" table:"level"`
+	Protocol string `json:"-" table:"protocol"`
+}
+
+func (r *RootCmd) portShareList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]portShareRow{}, []string{"agent", "port", "level", "protocol"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the shared ports of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			shares, err := client.WorkspacePortShares(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list port shares: %w", err)
+			}
+			if len(shares.Shares) == 0 {
+				cliui.Infof(inv.Stdout, "No shared ports found.\n")
+				return nil
+			}
+
+			rows := make([]portShareRow, 0, len(shares.Shares))
+			for _, share := range shares.Shares {
+				rows = append(rows, portShareRow{
+					WorkspacePortShare: share,
+					Agent:              share.AgentName,
+					Port:               share.Port,
+					Level:              string(share.ShareLevel),
+					Protocol:           string(share.Protocol),
+				})
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func parseSharedPort(s string) (int32, error) {
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || port == 0 {
+		return 0, xerrors.Errorf("invalid port %q: must be a number between 1 and 65535", s)
+	}
+	return int32(port), nil
+}
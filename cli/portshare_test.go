@@ -0,0 +1,104 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestPortShare(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Workspace) {
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAppSharingLevelAuthenticated),
+		})
+		require.NoError(t, err)
+		return client, workspace
+	}
+
+	t.Run("ShareListUnshare", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace := setup(t)
+
+		inv, root := clitest.New(t, "port", "share", workspace.Name, "3000", "--protocol", "https")
+		clitest.SetupConfig(t, client, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "is shared at level")
+
+		inv, root = clitest.New(t, "port", "list", workspace.Name, "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		buf.Reset()
+		inv.Stdout = &buf
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), `"port": 3000`)
+		require.Contains(t, buf.String(), `"share_level": "authenticated"`)
+		require.Contains(t, buf.String(), `"protocol": "https"`)
+
+		inv, root = clitest.New(t, "port", "unshare", workspace.Name, "3000")
+		clitest.SetupConfig(t, client, root)
+		buf.Reset()
+		inv.Stdout = &buf
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "no longer shared")
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+		shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares.Shares)
+	})
+
+	t.Run("ExceedsTemplateMaximum", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace := setup(t)
+
+		inv, root := clitest.New(t, "port", "share", workspace.Name, "3000", "--level", "public")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "only allows ports to be shared up to")
+	})
+
+	t.Run("InvalidPort", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "port", "share", "my-workspace", "http")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "invalid port")
+	})
+}
@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) roles() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "roles",
+		Short: "Manage custom roles",
+		Long: "Custom roles grant a set of permissions on top of the built-in roles. " +
+			"Permissions are written as <resource>:<action>, prefix a permission with ! to revoke it instead.\n" + formatExamples(
+			example{
+				Description: "Create a site wide role that can manage templates",
+				Command:     "coder roles create template-editor --site-permission template:* --site-permission file:*",
+			},
+			example{
+				Description: "Create a role in the current organization",
+				Command:     "coder roles create org-auditor --org --org-permission audit_log:read",
+			},
+			example{
+				Description: "List custom roles",
+				Command:     "coder roles list",
+			},
+		),
+		Aliases: []string{"role"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.roleCreate(),
+			r.roleEdit(),
+			r.roleList(),
+		},
+	}
+	return cmd
+}
+
+// rolePermissionFlags are the flags shared by the create and edit commands.
+type rolePermissionFlags struct {
+	displayName     string
+	org             bool
+	sitePermissions []string
+	orgPermissions  []string
+	userPermissions []string
+}
+
+func (f *rolePermissionFlags) attach(opts *clibase.OptionSet) {
+	*opts = append(*opts,
+		clibase.Option{
+			Flag:        "display-name",
+			Description: "Human readable name of the role.",
+			Value:       clibase.StringOf(&f.displayName),
+		},
+		clibase.Option{
+			Flag:        "org",
+			Description: "Use a role of the current organization instead of a site wide role.",
+			Value:       clibase.BoolOf(&f.org),
+		},
+		clibase.Option{
+			Flag:        "site-permission",
+			Description: "Permission granted on every resource of the deployment, e.g. template:read. Can be specified multiple times.",
+			Value:       clibase.StringArrayOf(&f.sitePermissions),
+		},
+		clibase.Option{
+			Flag:        "org-permission",
+			Description: "Permission granted on every resource of the role's organization, e.g. workspace:read. Requires --org. Can be specified multiple times.",
+			Value:       clibase.StringArrayOf(&f.orgPermissions),
+		},
+		clibase.Option{
+			Flag:        "user-permission",
+			Description: "Permission granted on resources owned by the user with the role, e.g. workspace:*. Can be specified multiple times.",
+			Value:       clibase.StringArrayOf(&f.userPermissions),
+		},
+	)
+}
+
+func (r *RootCmd) roleCreate() *clibase.Cmd {
+	var flags rolePermissionFlags
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a custom role",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			req := codersdk.CreateCustomRoleRequest{
+				Name:        inv.Args[0],
+				DisplayName: flags.displayName,
+			}
+			var err error
+			req.SitePermissions, err = parsePermissions(flags.sitePermissions)
+			if err != nil {
+				return xerrors.Errorf("parse --site-permission: %w", err)
+			}
+			req.OrganizationPermissions, err = parsePermissions(flags.orgPermissions)
+			if err != nil {
+				return xerrors.Errorf("parse --org-permission: %w", err)
+			}
+			req.UserPermissions, err = parsePermissions(flags.userPermissions)
+			if err != nil {
+				return xerrors.Errorf("parse --user-permission: %w", err)
+			}
+
+			var role codersdk.CustomRole
+			if flags.org {
+				organization, err := CurrentOrganization(inv, client)
+				if err != nil {
+					return xerrors.Errorf("get current organization: %w", err)
+				}
+				role, err = client.CreateOrganizationCustomRole(inv.Context(), organization.ID, req)
+				if err != nil {
+					return xerrors.Errorf("create role: %w", err)
+				}
+			} else {
+				role, err = client.CreateCustomRole(inv.Context(), req)
+				if err != nil {
+					return xerrors.Errorf("create role: %w", err)
+				}
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Created role %s!\n", cliui.Styles.Keyword.Render(role.Name))
+			return nil
+		},
+	}
+	flags.attach(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) roleEdit() *clibase.Cmd {
+	var flags rolePermissionFlags
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name>",
+		Short: "Edit a custom role",
+		Long:  "Only the flags that are passed are changed. Passing a permission flag replaces all permissions of that kind.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			var (
+				roles []codersdk.CustomRole
+				err   error
+			)
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			if flags.org {
+				roles, err = client.OrganizationCustomRoles(inv.Context(), organization.ID)
+			} else {
+				roles, err = client.CustomRoles(inv.Context())
+			}
+			if err != nil {
+				return xerrors.Errorf("list roles: %w", err)
+			}
+
+			var (
+				role  codersdk.CustomRole
+				found bool
+			)
+			for _, rl := range roles {
+				if strings.EqualFold(rl.Name, inv.Args[0]) {
+					role, found = rl, true
+					break
+				}
+			}
+			if !found {
+				return xerrors.Errorf("role %q not found", inv.Args[0])
+			}
+
+			req := codersdk.UpdateCustomRoleRequest{
+				DisplayName:             role.DisplayName,
+				SitePermissions:         role.SitePermissions,
+				OrganizationPermissions: role.OrganizationPermissions,
+				UserPermissions:         role.UserPermissions,
+			}
+			if inv.ParsedFlags().Changed("display-name") {
+				req.DisplayName = flags.displayName
+			}
+			if inv.ParsedFlags().Changed("site-permission") {
+				req.SitePermissions, err = parsePermissions(flags.sitePermissions)
+				if err != nil {
+					return xerrors.Errorf("parse --site-permission: %w", err)
+				}
+			}
+			if inv.ParsedFlags().Changed("org-permission") {
+				req.OrganizationPermissions, err = parsePermissions(flags.orgPermissions)
+				if err != nil {
+					return xerrors.Errorf("parse --org-permission: %w", err)
+				}
+			}
+			if inv.ParsedFlags().Changed("user-permission") {
+				req.UserPermissions, err = parsePermissions(flags.userPermissions)
+				if err != nil {
+					return xerrors.Errorf("parse --user-permission: %w", err)
+				}
+			}
+
+			if flags.org {
+				_, err = client.UpdateOrganizationCustomRole(inv.Context(), organization.ID, role.Name, req)
+			} else {
+				_, err = client.UpdateCustomRole(inv.Context(), role.Name, req)
+			}
+			if err != nil {
+				return xerrors.Errorf("update role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated role %s!\n", cliui.Styles.Keyword.Render(role.Name))
+			return nil
+		},
+	}
+	flags.attach(&cmd.Options)
+	return cmd
+}
+
+type roleListRow struct {
+	// For JSON format:
+	codersdk.CustomRole `table:"-"`
+
+	// For table format:
+	Name                    string `json:"-" table:"name,default_sort"`
+	DisplayName             string `json:"-" table:"display name"`
+	Scope                   string `json:"-" table:"scope"`
+	SitePermissions         string `json:"-" table:"site permissions"`
+	OrganizationPermissions string `json:"-" table:"org permissions"`
+	UserPermissions         string `json:"-" table:"user permissions"`
+}
+
+func roleListRowFromRole(role codersdk.CustomRole) roleListRow {
+	scope := "site"
+	if role.OrganizationID != nil {
+		scope = "organization"
+	}
+	return roleListRow{
+		CustomRole:              role,
+		Name:                    role.Name,
+		DisplayName:             role.DisplayName,
+		Scope:                   scope,
+		SitePermissions:         formatPermissions(role.SitePermissions),
+		OrganizationPermissions: formatPermissions(role.OrganizationPermissions),
+		UserPermissions:         formatPermissions(role.UserPermissions),
+	}
+}
+
+func (r *RootCmd) roleList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]roleListRow{}, []string{"name", "display name", "scope", "site permissions", "org permissions", "user permissions"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the site wide custom roles and the custom roles of the current organization",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			siteRoles, err := client.CustomRoles(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("list site roles: %w", err)
+			}
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			orgRoles, err := client.OrganizationCustomRoles(inv.Context(), organization.ID)
+			if err != nil {
+				return xerrors.Errorf("list organization roles: %w", err)
+			}
+
+			rows := make([]roleListRow, 0, len(siteRoles)+len(orgRoles))
+			for _, role := range append(siteRoles, orgRoles...) {
+				rows = append(rows, roleListRowFromRole(role))
+			}
+			if len(rows) == 0 {
+				_, _ = fmt.Fprintln(inv.Stderr, cliui.Styles.Prompt.String()+"No custom roles found! Create one with:")
+				_, _ = fmt.Fprintln(inv.Stderr)
+				_, _ = fmt.Fprintln(inv.Stderr, "  "+cliui.Styles.Code.Render("coder roles create <name> --site-permission <resource>:<action>"))
+				return nil
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// parsePermissions parses permissions in the <resource>:<action> format. A
+// leading ! negates the permission.
+func parsePermissions(values []string) ([]codersdk.Permission, error) {
+	perms := make([]codersdk.Permission, 0, len(values))
+	for _, value := range values {
+		negate := strings.HasPrefix(value, "!")
+		resource, action, ok := strings.Cut(strings.TrimPrefix(value, "!"), ":")
+		if !ok || resource == "" || action == "" {
+			return nil, xerrors.Errorf("invalid permission %q, expected <resource>:<action>", value)
+		}
+		perms = append(perms, codersdk.Permission{
+			Negate:       negate,
+			ResourceType: codersdk.RBACResource(resource),
+			Action:       action,
+		})
+	}
+	return perms, nil
+}
+
+func formatPermissions(perms []codersdk.Permission) string {
+	formatted := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		prefix := ""
+		if perm.Negate {
+			prefix = "!"
+		}
+		formatted = append(formatted, fmt.Sprintf("%s%s:%s", prefix, perm.ResourceType, perm.Action))
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,96 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "roles", "create", "template-editor",
+		"--display-name", "Template Editor",
+		"--site-permission", "template:*",
+		"--site-permission", "!template:delete",
+	)
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "template-editor")
+
+	inv, root = clitest.New(t, "roles", "create", "workspace-viewer", "--org",
+		"--org-permission", "workspace:read",
+	)
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	roles, err := client.CustomRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	require.Equal(t, "Template Editor", roles[0].DisplayName)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceTemplate, Action: "*"},
+		{ResourceType: codersdk.ResourceTemplate, Action: "delete", Negate: true},
+	}, roles[0].SitePermissions)
+
+	// Only the permissions that are passed are changed.
+	inv, root = clitest.New(t, "roles", "edit", "template-editor",
+		"--user-permission", "workspace:read",
+	)
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	roles, err = client.CustomRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	require.Equal(t, "Template Editor", roles[0].DisplayName)
+	require.Len(t, roles[0].SitePermissions, 2)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+	}, roles[0].UserPermissions)
+
+	inv, root = clitest.New(t, "roles", "list")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "template-editor")
+	require.Contains(t, buf.String(), "template:*, !template:delete")
+	require.Contains(t, buf.String(), "workspace-viewer")
+
+	inv, root = clitest.New(t, "roles", "list", "--output=json")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	var listed []codersdk.CustomRole
+	err = json.Unmarshal(buf.Bytes(), &listed)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	for _, role := range listed {
+		if role.Name == "workspace-viewer" {
+			require.NotNil(t, role.OrganizationID)
+			require.Equal(t, user.OrganizationID, *role.OrganizationID)
+		}
+	}
+}
@@ -58,6 +58,7 @@ const (
 	varNoFeatureWarning = "no-feature-warning"
 	varForceTty         = "force-tty"
 	varVerbose          = "verbose"
+	varProxy            = "proxy"
 	notLoggedInMessage  = "You are not logged in. Try logging in using 'coder login <url>'."
 
 	envNoVersionCheck   = "CODER_NO_VERSION_WARNING"
@@ -79,6 +80,8 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
+		r.roles(),
+		r.sessions(),
 		r.state(),
 		r.templates(),
 		r.users(),
@@ -87,15 +90,20 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 
 		// Workspace Commands
 		r.configSSH(),
+		r.cp(),
 		r.rename(),
 		r.ping(),
+		r.port(),
+		r.autoupdate(),
 		r.create(),
 		r.deleteWorkspace(),
 		r.list(),
+		r.logs(),
 		r.schedules(),
 		r.show(),
 		r.speedtest(),
 		r.ssh(),
+		r.sshCert(),
 		r.start(),
 		r.stop(),
 		r.update(),
@@ -340,6 +348,13 @@ func (r *RootCmd) Command(subcommands []*clibase.Cmd) (*clibase.Cmd, error) {
 			Value:       clibase.StringArrayOf(&r.header),
 			Group:       globalGroup,
 		},
+		{
+			Flag:        varProxy,
+			Env:         "CODER_PROXY",
+			Description: `The workspace proxy to connect to workspaces through. Either the name of a proxy, or "primary" for the primary access URL. Defaults to the proxy that was pinned with "coder proxy use".`,
+			Value:       clibase.StringOf(&r.proxy),
+			Group:       globalGroup,
+		},
 		{
 			Flag:        varNoOpen,
 			Env:         "CODER_NO_OPEN",
@@ -412,6 +427,7 @@ type RootCmd struct {
 	forceTTY     bool
 	noOpen       bool
 	verbose      bool
+	proxy        string
 
 	noVersionCheck   bool
 	noFeatureWarning bool
@@ -420,6 +436,17 @@ type RootCmd struct {
 // InitClient sets client to a new client.
 // It reads from global configuration files if flags are not set.
 func (r *RootCmd) InitClient(client *codersdk.Client) clibase.MiddlewareFunc {
+	return r.initClientInternal(client, false)
+}
+
+// InitClientMissingTokenOK is like InitClient, but doesn't fail when the
+// session token is absent. It's for commands that authenticate some other
+// way, like provisioner daemons started with a provisioner key.
+func (r *RootCmd) InitClientMissingTokenOK(client *codersdk.Client) clibase.MiddlewareFunc {
+	return r.initClientInternal(client, true)
+}
+
+func (r *RootCmd) initClientInternal(client *codersdk.Client, allowTokenMissing bool) clibase.MiddlewareFunc {
 	if client == nil {
 		panic("client is nil")
 	}
@@ -450,9 +477,10 @@ func (r *RootCmd) InitClient(client *codersdk.Client) clibase.MiddlewareFunc {
 				r.token, err = conf.Session().Read()
 				// If the configuration files are absent, the user is logged out
 				if os.IsNotExist(err) {
-					return (errUnauthenticated)
-				}
-				if err != nil {
+					if !allowTokenMissing {
+						return (errUnauthenticated)
+					}
+				} else if err != nil {
 					return err
 				}
 			}
@@ -50,13 +50,22 @@ When enabling scheduled stop, enter a duration in one of the following formats:
   * The new stop time is calculated from *now*.
   * The new stop time must be at least 30 minutes in the future.
   * The workspace template may restrict the maximum workspace runtime.
+`
+	scheduleQuietHoursDescriptionLong = `Shows or edits your quiet hours schedule.
+  * Quiet hours are a daily window in which your workspaces may be restarted to
+    satisfy template restart requirements.
+  * Start-time is accepted either in 12-hour (hh:mm{am|pm}) format, or 24-hour format hh:mm.
+  * Location (optional) must be a valid location in the IANA timezone database.
+    If omitted, we will fall back to either the TZ environment variable or /etc/localtime.
+  * Pass "default" to go back to using the deployment default schedule.
+  * Changes only take effect upon the next build of each workspace.
 `
 )
 
 func (r *RootCmd) schedules() *clibase.Cmd {
 	scheduleCmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
-		Use:         "schedule { show | start | stop | override } <workspace>",
+		Use:         "schedule { show | start | stop | override | quiet-hours } <workspace>",
 		Short:       "Schedule automated start and stop times for workspaces",
 		Handler: func(inv *clibase.Invocation) error {
 			return inv.Command.HelpHandler(inv)
@@ -66,6 +75,7 @@ func (r *RootCmd) schedules() *clibase.Cmd {
 			r.scheduleStart(),
 			r.scheduleStop(),
 			r.scheduleOverride(),
+			r.scheduleQuietHours(),
 		},
 	}
 
@@ -242,6 +252,78 @@ func (r *RootCmd) scheduleOverride() *clibase.Cmd {
 	return overrideCmd
 }
 
+func (r *RootCmd) scheduleQuietHours() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "quiet-hours [ <start-time> [location] | default ]",
+		Short: "Show or edit your quiet hours schedule",
+		Long: scheduleQuietHoursDescriptionLong + "\n" + formatExamples(
+			example{
+				Description: "Show your current quiet hours schedule",
+				Command:     "coder schedule quiet-hours",
+			},
+			example{
+				Description: "Start your quiet hours at 1:30am (in Dublin) every day",
+				Command:     "coder schedule quiet-hours 1:30AM Europe/Dublin",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if len(inv.Args) == 0 {
+				sched, err := client.UserQuietHoursSchedule(inv.Context(), codersdk.Me)
+				if err != nil {
+					return xerrors.Errorf("get quiet hours schedule: %w", err)
+				}
+				return displayQuietHoursSchedule(sched, inv.Stdout)
+			}
+
+			var schedStr string
+			if inv.Args[0] != "default" {
+				sched, err := parseCLISchedule(inv.Args...)
+				if err != nil {
+					return err
+				}
+				if sched.DaysOfWeek() != "daily" {
+					return xerrors.New("Quiet hours schedules must apply every day: specify only a start time and an optional location")
+				}
+				schedStr = sched.String()
+			}
+
+			updated, err := client.UpdateUserQuietHoursSchedule(inv.Context(), codersdk.Me, codersdk.UpdateUserQuietHoursScheduleRequest{
+				Schedule: schedStr,
+			})
+			if err != nil {
+				return xerrors.Errorf("update quiet hours schedule: %w", err)
+			}
+			return displayQuietHoursSchedule(updated, inv.Stdout)
+		},
+	}
+	return cmd
+}
+
+func displayQuietHoursSchedule(sched codersdk.UserQuietHoursScheduleResponse, out io.Writer) error {
+	source := "default"
+	if sched.UserSet {
+		source = "custom"
+	}
+
+	loc, err := time.LoadLocation(sched.Timezone)
+	if err != nil {
+		loc = time.UTC // best effort
+	}
+
+	tw := cliui.Table()
+	tw.AppendRow(table.Row{"Starts at", fmt.Sprintf("%s daily (%s)", sched.Time, sched.Timezone)})
+	tw.AppendRow(table.Row{"Starts next", sched.Next.In(loc).Format(timeFormat + " on " + dateFormat)})
+	tw.AppendRow(table.Row{"Schedule", source})
+
+	_, _ = fmt.Fprintln(out, tw.Render())
+	return nil
+}
+
 func displaySchedule(workspace codersdk.Workspace, out io.Writer) error {
 	loc, err := tz.TimezoneIANA()
 	if err != nil {
@@ -43,6 +43,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/afero"
 	"go.opentelemetry.io/otel/trace"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/mod/semver"
 	"golang.org/x/oauth2"
 	xgithub "golang.org/x/oauth2/github"
@@ -475,6 +476,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				FilesRateLimit:              filesRateLimit,
 				HTTPClient:                  httpClient,
 				TemplateScheduleStore:       &atomic.Pointer[schedule.TemplateScheduleStore]{},
+				UserQuietHoursScheduleStore: &atomic.Pointer[schedule.UserQuietHoursScheduleStore]{},
 				SSHConfig: codersdk.SSHConfigResponse{
 					HostnamePrefix:   cfg.SSHConfig.DeploymentName.String(),
 					SSHConfigOptions: configSSHOptions,
@@ -657,6 +659,31 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 
 				options.AppSecurityKey = appSecurityKey
+
+				// The SSH certificate authority key is generated once and
+				// shared by all replicas. Regenerating it invalidates
+				// previously issued certificates, which are short-lived.
+				sshCAKeyStr, err := tx.GetSSHCAKey(ctx)
+				if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+					return xerrors.Errorf("get ssh ca key: %w", err)
+				}
+				sshCAKey, err := gossh.ParsePrivateKey([]byte(sshCAKeyStr))
+				if err != nil {
+					sshCAKeyStr, _, err = gitsshkey.Generate(gitsshkey.AlgorithmEd25519)
+					if err != nil {
+						return xerrors.Errorf("generate ssh ca key: %w", err)
+					}
+					err = tx.UpsertSSHCAKey(ctx, sshCAKeyStr)
+					if err != nil {
+						return xerrors.Errorf("insert freshly generated ssh ca key to database: %w", err)
+					}
+					sshCAKey, err = gossh.ParsePrivateKey([]byte(sshCAKeyStr))
+					if err != nil {
+						return xerrors.Errorf("parse ssh ca key: %w", err)
+					}
+				}
+
+				options.SSHCAKey = sshCAKey
 				return nil
 			}, nil)
 			if err != nil {
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+func (r *RootCmd) sessions() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "sessions",
+		Short: "Manage recordings of interactive workspace sessions",
+		Long: "Templates with session recording enabled record interactive terminal sessions. The ID of each recording is linked from the \"connect\" audit log of the session.\n" + formatExamples(
+			example{
+				Description: "Replay a session recording twice as fast",
+				Command:     "coder sessions replay 0ac0d3f6-3b59-4c88-a1c5-1d2c8c2d8a3e --speed 2",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.sessionReplay(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) sessionReplay() *clibase.Cmd {
+	var (
+		speed     string
+		idleLimit time.Duration
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "replay <id>",
+		Short: "Replay a session recording in the terminal",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			id, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("parse recording id: %w", err)
+			}
+			speedFactor, err := strconv.ParseFloat(speed, 64)
+			if err != nil || speedFactor <= 0 {
+				return xerrors.Errorf("speed must be a positive number, got %q", speed)
+			}
+
+			data, contentType, err := client.Download(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("download recording: %w", err)
+			}
+			if contentType != "application/x-asciicast" {
+				return xerrors.Errorf("file %s is not a session recording", id)
+			}
+
+			return replaySessionRecording(ctx, inv, bytes.NewReader(data), speedFactor, idleLimit)
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "speed",
+			Default:     "1",
+			Description: "Playback speed, e.g. 2 replays the session twice as fast.",
+			Value:       clibase.StringOf(&speed),
+		},
+		{
+			Flag:        "idle-limit",
+			Default:     "2s",
+			Description: "Shorten pauses in the session to at most this duration. Set to 0 to keep the original timing.",
+			Value:       clibase.DurationOf(&idleLimit),
+		},
+	}
+	return cmd
+}
+
+// replaySessionRecording writes the output events of an asciicast v2
+// recording to stdout with their original timing.
+func replaySessionRecording(ctx context.Context, inv *clibase.Invocation, recording io.Reader, speed float64, idleLimit time.Duration) error {
+	scanner := bufio.NewScanner(recording)
+	scanner.Buffer(make([]byte, 64<<10), agentsdk.MaxSessionRecordingSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return xerrors.Errorf("read recording header: %w", err)
+		}
+		return xerrors.New("recording is empty")
+	}
+	var header struct {
+		Version   int   `json:"version"`
+		Width     int   `json:"width"`
+		Height    int   `json:"height"`
+		Timestamp int64 `json:"timestamp"`
+	}
+	err := json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil {
+		return xerrors.Errorf("parse recording header: %w", err)
+	}
+	if header.Version != 2 {
+		return xerrors.Errorf("unsupported recording version %d", header.Version)
+	}
+	cliui.Infof(inv.Stderr, "Replaying a %dx%d session recorded at %s.",
+		header.Width, header.Height, time.Unix(header.Timestamp, 0).Local().Format(time.RFC1123))
+
+	var previous float64
+	for scanner.Scan() {
+		var (
+			event   []json.RawMessage
+			elapsed float64
+			code    string
+			data    string
+		)
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err == nil && len(event) != 3 {
+			err = xerrors.Errorf("expected 3 fields, got %d", len(event))
+		}
+		if err == nil {
+			err = json.Unmarshal(event[0], &elapsed)
+		}
+		if err == nil {
+			err = json.Unmarshal(event[1], &code)
+		}
+		if err == nil {
+			err = json.Unmarshal(event[2], &data)
+		}
+		if err != nil {
+			return xerrors.Errorf("parse recording event: %w", err)
+		}
+
+		wait := time.Duration((elapsed - previous) / speed * float64(time.Second))
+		previous = elapsed
+		if idleLimit > 0 && wait > idleLimit {
+			wait = idleLimit
+		}
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		// Resizes can't be replayed, the output is written as is.
+		if code == "o" {
+			_, err = io.WriteString(inv.Stdout, data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
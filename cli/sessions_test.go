@@ -0,0 +1,49 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestSessionsReplay(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	enabled := true
+	_, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		SessionRecording: &enabled,
+	})
+	require.NoError(t, err)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	resp, err := agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+		Type:      agentsdk.SessionRecordingTypeSSH,
+		StartedAt: database.Now(),
+		EndedAt:   database.Now(),
+		Recording: []byte(`{"version":2,"width":80,"height":24,"timestamp":1680000000}
+[0.1,"o","hello "]
+[0.2,"r","100x30"]
+[30,"o","world"]
+`),
+	})
+	require.NoError(t, err)
+
+	// The 30 second pause is shortened by the idle limit.
+	inv, root := clitest.New(t, "sessions", "replay", resp.ID.String(), "--speed", "2", "--idle-limit", "100ms")
+	clitest.SetupConfig(t, client, root)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Equal(t, "hello world", stdout.String())
+}
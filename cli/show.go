@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"fmt"
+	"time"
+
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/clibase"
@@ -9,6 +12,7 @@ import (
 )
 
 func (r *RootCmd) show() *clibase.Cmd {
+	var timings bool
 	client := new(codersdk.Client)
 	return &clibase.Cmd{
 		Use:   "show <workspace>",
@@ -26,10 +30,75 @@ func (r *RootCmd) show() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("get workspace: %w", err)
 			}
-			return cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
+			if workspace.TemplateDeprecationMessage != "" {
+				cliui.Warn(inv.Stderr, fmt.Sprintf("Template %q is deprecated", workspace.TemplateName), workspace.TemplateDeprecationMessage)
+			}
+			err = cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
 				WorkspaceName: workspace.Name,
 				ServerVersion: buildInfo.Version,
 			})
+			if err != nil {
+				return err
+			}
+			if !timings {
+				return nil
+			}
+
+			buildTimings, err := client.WorkspaceBuildTimings(inv.Context(), workspace.LatestBuild.ID)
+			if err != nil {
+				return xerrors.Errorf("get workspace build timings: %w", err)
+			}
+			out, err := cliui.DisplayTable(buildTimingRows(buildTimings), "", nil)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+		Options: clibase.OptionSet{
+			{
+				Flag:        "timings",
+				Description: "Display the time each stage and resource of the latest build took.",
+				Value:       clibase.BoolOf(&timings),
+			},
 		},
 	}
 }
+
+// buildTimingFormat sorts chronologically as a string.
+const buildTimingFormat = "2006-01-02 15:04:05.000"
+
+type buildTimingRow struct {
+	Started  string `table:"started,default_sort"`
+	Stage    string `table:"stage"`
+	Source   string `table:"source"`
+	Action   string `table:"action"`
+	Resource string `table:"resource"`
+	Duration string `table:"duration"`
+}
+
+// buildTimingRows lists the provisioner and agent timings. The row of a whole
+// provisioner stage has no resource.
+func buildTimingRows(timings codersdk.WorkspaceBuildTimings) []buildTimingRow {
+	rows := make([]buildTimingRow, 0, len(timings.ProvisionerTimings)+len(timings.AgentTimings))
+	for _, timing := range timings.ProvisionerTimings {
+		rows = append(rows, buildTimingRow{
+			Started:  timing.StartedAt.Local().Format(buildTimingFormat),
+			Stage:    string(timing.Stage),
+			Source:   timing.Source,
+			Action:   timing.Action,
+			Resource: timing.Resource,
+			Duration: timing.EndedAt.Sub(timing.StartedAt).Round(time.Millisecond).String(),
+		})
+	}
+	for _, timing := range timings.AgentTimings {
+		rows = append(rows, buildTimingRow{
+			Started:  timing.StartedAt.Local().Format(buildTimingFormat),
+			Stage:    string(timing.Stage),
+			Source:   "agent",
+			Resource: timing.WorkspaceAgentName,
+			Duration: timing.EndedAt.Sub(timing.StartedAt).Round(time.Millisecond).String(),
+		})
+	}
+	return rows
+}
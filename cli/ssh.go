@@ -44,7 +44,10 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		forwardGPG     bool
 		identityAgent  string
 		wsPollInterval time.Duration
+		wait           string
 		noWait         bool
+		session        string
+		listSessions   bool
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -59,6 +62,10 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
+			if session != "" && (stdio || forwardAgent || forwardGPG) {
+				return xerrors.New("--session can't be combined with --stdio, --forward-agent or --forward-gpg")
+			}
+
 			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
 			if err != nil {
 				return err
@@ -69,6 +76,11 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				_, _ = fmt.Fprintln(inv.Stderr, updateWorkspaceBanner)
 			}
 
+			if noWait {
+				cliui.Warn(inv.Stderr, "Flag --no-wait is deprecated, please use --wait=no instead.")
+				wait = agentWaitNo
+			}
+
 			// OpenSSH passes stderr directly to the calling TTY.
 			// This is required in "stdio" mode so a connecting indicator can be displayed.
 			err = cliui.Agent(ctx, inv.Stderr, cliui.AgentOptions{
@@ -76,7 +88,8 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				Fetch: func(ctx context.Context) (codersdk.WorkspaceAgent, error) {
 					return client.WorkspaceAgent(ctx, workspaceAgent.ID)
 				},
-				NoWait: noWait,
+				FetchLogs: client.WorkspaceAgentStartupLogsAfter,
+				Wait:      shouldWaitForAgent(wait, workspaceAgent),
 			})
 			if err != nil {
 				if xerrors.Is(err, context.Canceled) {
@@ -91,7 +104,17 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				// We don't print the error because cliui.Agent does that for us.
 			}
 
-			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{})
+			if listSessions {
+				return listReconnectingPTYs(ctx, inv, client, workspace, workspaceAgent)
+			}
+
+			proxyURL, err := r.workspaceProxyURL(inv, client)
+			if err != nil {
+				return err
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				ProxyURL: proxyURL,
+			})
 			if err != nil {
 				return err
 			}
@@ -100,6 +123,10 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			stopPolling := tryPollWorkspaceAutostop(ctx, client, workspace)
 			defer stopPolling()
 
+			if session != "" {
+				return sshReconnectingPTY(ctx, inv, conn, workspace, session)
+			}
+
 			if stdio {
 				rawSSH, err := conn.SSH(ctx)
 				if err != nil {
@@ -266,15 +293,99 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			Value:       clibase.DurationOf(&wsPollInterval),
 		},
 		{
+			Flag:        "session",
+			Env:         "CODER_SSH_SESSION",
+			Description: "Attach to the terminal session with this name or ID, and start it if it doesn't exist. The session keeps running when you detach with ~. or the connection drops. Sessions of the web terminal can be attached by their ID.",
+			Value:       clibase.StringOf(&session),
+		},
+		{
+			Flag:        "list-sessions",
+			Description: "List the terminal sessions of the workspace that can be attached with --session.",
+			Value:       clibase.BoolOf(&listSessions),
+		},
+	}
+	cmd.Options = append(cmd.Options,
+		agentWaitOption(&wait, "CODER_SSH_WAIT", agentWaitAuto),
+		clibase.Option{
 			Flag:        "no-wait",
 			Env:         "CODER_SSH_NO_WAIT",
-			Description: "Specifies whether to wait for a workspace to become ready before logging in (only applicable when the login before ready option has not been enabled). Note that the workspace agent may still be in the process of executing the startup script and the workspace may be in an incomplete state.",
+			Description: "Deprecated: use --wait=no instead. Specifies whether to wait for a workspace to become ready before logging in (only applicable when the login before ready option has not been enabled).",
 			Value:       clibase.BoolOf(&noWait),
 		},
-	}
+	)
 	return cmd
 }
 
+const (
+	agentWaitYes  = "yes"
+	agentWaitNo   = "no"
+	agentWaitAuto = "auto"
+)
+
+// agentWaitOption returns the --wait option of the commands that can wait
+// for the workspace agents to finish running the startup script.
+func agentWaitOption(wait *string, env string, def string) clibase.Option {
+	return clibase.Option{
+		Flag:        "wait",
+		Env:         env,
+		Description: "Specifies whether to wait for the workspace agent startup script to finish, the startup logs are shown while waiting. With auto, the agent's login_before_ready setting decides. Note that when not waiting the workspace may be in an incomplete state.",
+		Default:     def,
+		Value:       clibase.EnumOf(wait, agentWaitYes, agentWaitNo, agentWaitAuto),
+	}
+}
+
+// shouldWaitForAgent resolves the value of the --wait option for the
+// given agent.
+func shouldWaitForAgent(wait string, agent codersdk.WorkspaceAgent) bool {
+	switch wait {
+	case agentWaitYes:
+		return true
+	case agentWaitNo:
+		return false
+	default:
+		return !agent.LoginBeforeReady
+	}
+}
+
+// awaitWorkspaceAgents waits for the agents of a workspace build to finish
+// running their startup scripts while streaming the startup logs.
+func awaitWorkspaceAgents(inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace, buildID uuid.UUID, wait string) error {
+	if wait == agentWaitNo {
+		return nil
+	}
+	build, err := client.WorkspaceBuild(inv.Context(), buildID)
+	if err != nil {
+		return xerrors.Errorf("get workspace build: %w", err)
+	}
+	for _, resource := range build.Resources {
+		for _, agent := range resource.Agents {
+			if !shouldWaitForAgent(wait, agent) {
+				continue
+			}
+			agentID := agent.ID
+			err = cliui.Agent(inv.Context(), inv.Stdout, cliui.AgentOptions{
+				WorkspaceName: workspace.Name,
+				Fetch: func(ctx context.Context) (codersdk.WorkspaceAgent, error) {
+					return client.WorkspaceAgent(ctx, agentID)
+				},
+				FetchLogs: client.WorkspaceAgentStartupLogsAfter,
+				Wait:      true,
+			})
+			if err != nil {
+				if xerrors.Is(err, context.Canceled) {
+					return cliui.Canceled
+				}
+				// A failing startup script has already been reported by
+				// cliui.Agent and the workspace is still usable.
+				if !xerrors.Is(err, cliui.AgentStartError) {
+					return xerrors.Errorf("await agent %s: %w", agent.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // getWorkspaceAgent returns the workspace and agent selected using either the
 // `<workspace>[.<agent>]` syntax via `in` or picks a random workspace and agent
 // if `shuffle` is true.
@@ -56,3 +56,40 @@ func TestBuildWorkspaceLink(t *testing.T) {
 
 	assert.Equal(t, workspaceLink.String(), fakeServerURL+"/@"+fakeOwnerName+"/"+fakeWorkspaceName)
 }
+
+func TestSessionEscape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  []string
+		output string
+		detach bool
+	}{
+		{name: "Plain", input: []string{"ls\r"}, output: "ls\r"},
+		{name: "DetachAtStart", input: []string{"~."}, output: "", detach: true},
+		{name: "DetachAfterNewline", input: []string{"ls\r~."}, output: "ls\r", detach: true},
+		{name: "DetachAcrossReads", input: []string{"ls\r~", "."}, output: "ls\r", detach: true},
+		{name: "TildeInLine", input: []string{"cd ~.\r"}, output: "cd ~.\r"},
+		{name: "DoubleTilde", input: []string{"~~."}, output: "~."},
+		{name: "TildeOther", input: []string{"~/bin\r"}, output: "~/bin\r"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			escape := &sessionEscape{lineStart: true}
+			var (
+				output []byte
+				detach bool
+			)
+			for _, input := range tt.input {
+				var data []byte
+				data, detach = escape.filter([]byte(input))
+				output = append(output, data...)
+			}
+			assert.Equal(t, tt.output, string(output))
+			assert.Equal(t, tt.detach, detach)
+		})
+	}
+}
@@ -114,6 +114,102 @@ func TestSSH(t *testing.T) {
 		pty.WriteLine("exit")
 		<-cmdDone
 	})
+	t.Run("Session", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("ConPTY appears to be inconsistent on Windows.")
+		}
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		defer func() {
+			_ = agentCloser.Close()
+		}()
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t, "ssh", workspace.Name, "--session", "dev")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		cmdDone := tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		pty.WriteLine("echo hello-$((40 + 2))")
+		pty.ExpectMatch("hello-42")
+		// Detaching keeps the session running.
+		pty.WriteLine("~.")
+		pty.ExpectMatch("Detached from session")
+		<-cmdDone
+
+		inv, root = clitest.New(t, "ssh", workspace.Name, "--list-sessions")
+		clitest.SetupConfig(t, client, root)
+		pty = ptytest.New(t).Attach(inv)
+		cmdDone = tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		pty.ExpectMatch(codersdk.ReconnectingPTYSessionID("dev").String())
+		pty.ExpectMatch("buffered")
+		<-cmdDone
+
+		// The output of the session is replayed when it's reattached.
+		inv, root = clitest.New(t, "ssh", workspace.Name, "--session", "dev")
+		clitest.SetupConfig(t, client, root)
+		pty = ptytest.New(t).Attach(inv)
+		cmdDone = tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		pty.ExpectMatch("hello-42")
+		pty.WriteLine("exit")
+		<-cmdDone
+	})
+
+	t.Run("WaitShowsStartupLogs", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, func(a []*proto.Agent) []*proto.Agent {
+			// Keep the script running so the logs are streamed before
+			// the agent is ready.
+			a[0].StartupScript = "echo hello from the startup script && sleep 3"
+			return a
+		})
+		inv, root := clitest.New(t, "ssh", workspace.Name, "--wait=yes")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		cmdDone := tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		pty.ExpectMatch("Waiting")
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		defer func() {
+			_ = agentCloser.Close()
+		}()
+
+		pty.ExpectMatch("hello from the startup script")
+
+		pty.WriteLine("exit")
+		<-cmdDone
+	})
 	t.Run("ShowTroubleshootingURLAfterTimeout", func(t *testing.T) {
 		t.Parallel()
 
@@ -207,6 +303,27 @@ func TestSSH(t *testing.T) {
 
 		<-cmdDone
 	})
+	t.Run("UnknownProxy", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		defer agentCloser.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Deployments without workspace proxies can't connect through one.
+		inv, root := clitest.New(t, "ssh", "--proxy", "missing", "--stdio", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		inv.Stderr = io.Discard
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "list workspace proxies")
+	})
 	t.Run("ForwardAgent", func(t *testing.T) {
 		if runtime.GOOS == "windows" {
 			t.Skip("Test not supported on windows")
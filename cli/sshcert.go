@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/cli/config"
+	"github.com/coder/coder/coderd/gitsshkey"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) sshCert() *clibase.Cmd {
+	var (
+		identityFile string
+		output       string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "ssh-cert <workspace>",
+		Short:       "Issue a certificate to connect to a workspace with standard SSH clients",
+		Long: fmt.Sprintf("Certificates are valid for %d hours and only for the given workspace. Agents accept them on the address set with \"coder agent --ssh-listen-address\". The path of the certificate is printed to stdout.\n", int(codersdk.WorkspaceSSHCertificateLifetime.Hours())) + formatExamples(
+			example{
+				Description: "Connect to an agent that serves SSH on port 2222",
+				Command:     "ssh -i ~/.config/coderv2/ssh/id_ed25519 -o CertificateFile=$(coder ssh-cert my-workspace) -p 2222 my-workspace.internal",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			root := r.createConfig()
+			publicKey, err := sshCertificateKey(root, identityFile)
+			if err != nil {
+				return err
+			}
+			cert, err := client.PostWorkspaceSSHCertificate(ctx, workspace.ID, codersdk.PostWorkspaceSSHCertificateRequest{
+				PublicKey: string(gossh.MarshalAuthorizedKey(publicKey)),
+			})
+			if err != nil {
+				return xerrors.Errorf("issue certificate: %w", err)
+			}
+
+			certFile := root.SSHCertificate(workspace.ID)
+			if output != "" {
+				certFile = config.File(output)
+			}
+			err = certFile.Write(cert.Certificate)
+			if err != nil {
+				return xerrors.Errorf("write certificate: %w", err)
+			}
+
+			cliui.Infof(inv.Stderr, "Issued a certificate for %s that is valid until %s.",
+				workspace.Name, cert.ExpiresAt.Local().Format(time.RFC1123))
+			_, _ = fmt.Fprintln(inv.Stdout, string(certFile))
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "identity-file",
+			FlagShorthand: "i",
+			Description:   "Private key to issue the certificate for, its public key is read from the .pub file next to it if it exists. Defaults to a key in the config directory that is generated on first use.",
+			Value:         clibase.StringOf(&identityFile),
+		},
+		{
+			Flag:        "output",
+			Description: "Path to write the certificate to. Defaults to a file in the config directory.",
+			Value:       clibase.StringOf(&output),
+		},
+	}
+	return cmd
+}
+
+// sshCertificateKey returns the public key of the identity file, or of the key
+// in the config directory if the identity file is empty.
+func sshCertificateKey(root config.Root, identityFile string) (gossh.PublicKey, error) {
+	if identityFile != "" {
+		data, err := os.ReadFile(identityFile + ".pub")
+		if err == nil {
+			publicKey, _, _, _, err := gossh.ParseAuthorizedKey(data)
+			if err != nil {
+				return nil, xerrors.Errorf("parse public key: %w", err)
+			}
+			return publicKey, nil
+		}
+		data, err = os.ReadFile(identityFile)
+		if err != nil {
+			return nil, xerrors.Errorf("read identity file: %w", err)
+		}
+		signer, err := gossh.ParsePrivateKey(data)
+		if err != nil {
+			return nil, xerrors.Errorf("parse identity file: %w", err)
+		}
+		return signer.PublicKey(), nil
+	}
+
+	keyFile := root.SSHKey()
+	privateKey, err := keyFile.Read()
+	if xerrors.Is(err, os.ErrNotExist) {
+		privateKey, _, err = gitsshkey.Generate(gitsshkey.AlgorithmEd25519)
+		if err != nil {
+			return nil, xerrors.Errorf("generate ssh key: %w", err)
+		}
+		err = keyFile.Write(privateKey)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("ssh key: %w", err)
+	}
+	signer, err := gossh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, xerrors.Errorf("parse ssh key: %w", err)
+	}
+	return signer.PublicKey(), nil
+}
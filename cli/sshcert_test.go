@@ -0,0 +1,86 @@
+package cli_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestSSHCert(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	// readCert parses the certificate at the path printed by the command.
+	readCert := func(t *testing.T, stdout string) *gossh.Certificate {
+		t.Helper()
+		data, err := os.ReadFile(strings.TrimSpace(stdout))
+		require.NoError(t, err)
+		key, _, _, _, err := gossh.ParseAuthorizedKey(data)
+		require.NoError(t, err)
+		cert, ok := key.(*gossh.Certificate)
+		require.True(t, ok)
+		require.Equal(t, []string{codersdk.WorkspaceSSHCertificatePrincipal(workspace.ID)}, cert.ValidPrincipals)
+		return cert
+	}
+
+	t.Run("GeneratedKey", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "ssh-cert", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Equal(t, string(root.SSHCertificate(workspace.ID)), strings.TrimSpace(stdout.String()))
+		cert := readCert(t, stdout.String())
+
+		privateKey, err := root.SSHKey().Read()
+		require.NoError(t, err)
+		signer, err := gossh.ParsePrivateKey([]byte(privateKey))
+		require.NoError(t, err)
+		require.Equal(t, signer.PublicKey().Marshal(), cert.Key.Marshal())
+	})
+
+	t.Run("IdentityFile", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		identityFile := filepath.Join(dir, "id_ed25519")
+		output := filepath.Join(dir, "id_ed25519-cert.pub")
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		signer, err := gossh.NewSignerFromKey(privateKey)
+		require.NoError(t, err)
+		err = os.WriteFile(identityFile+".pub", gossh.MarshalAuthorizedKey(signer.PublicKey()), 0o600)
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "ssh-cert", workspace.Name, "-i", identityFile, "--output", output)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Equal(t, output, strings.TrimSpace(stdout.String()))
+		cert := readCert(t, stdout.String())
+		require.Equal(t, signer.PublicKey().Marshal(), cert.Key.Marshal())
+	})
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+type reconnectingPTYRow struct {
+	ID          string `table:"id"`
+	Name        string `table:"name,default_sort"`
+	Command     string `table:"command"`
+	Backend     string `table:"backend"`
+	Connections int    `table:"connections"`
+}
+
+// listReconnectingPTYs prints the sessions of the web terminal and of
+// "coder ssh --session" that can be reattached.
+func listReconnectingPTYs(ctx context.Context, inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent) error {
+	res, err := client.WorkspaceAgentReconnectingPTYs(ctx, agent.ID)
+	if err != nil {
+		return xerrors.Errorf("list sessions: %w", err)
+	}
+	if len(res.Sessions) == 0 {
+		cliui.Infof(inv.Stderr, "No sessions are running in %s.", workspace.Name)
+		return nil
+	}
+	rows := make([]reconnectingPTYRow, 0, len(res.Sessions))
+	for _, session := range res.Sessions {
+		rows = append(rows, reconnectingPTYRow{
+			ID:          session.ID.String(),
+			Name:        session.Name,
+			Command:     session.Command,
+			Backend:     session.Backend,
+			Connections: session.ActiveConnections,
+		})
+	}
+	out, err := cliui.DisplayTable(rows, "", nil)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(inv.Stdout, out)
+	return err
+}
+
+// sshReconnectingPTY attaches the terminal to a reconnecting PTY session of
+// the agent, which keeps running when the connection ends. The session is
+// either a name or the ID of a listed session, e.g. one of the web terminal.
+func sshReconnectingPTY(ctx context.Context, inv *clibase.Invocation, conn *codersdk.WorkspaceAgentConn, workspace codersdk.Workspace, session string) error {
+	width, height := 80, 24
+	stdoutFile, validOut := inv.Stdout.(*os.File)
+	stdinFile, validIn := inv.Stdin.(*os.File)
+	isTTY := validOut && validIn && isatty.IsTerminal(stdoutFile.Fd())
+	if isTTY {
+		w, h, err := term.GetSize(int(stdoutFile.Fd()))
+		if err == nil {
+			width, height = w, h
+		}
+	}
+
+	var (
+		ptyConn net.Conn
+		err     error
+	)
+	if id, parseErr := uuid.Parse(session); parseErr == nil {
+		ptyConn, err = conn.ReconnectingPTY(ctx, id, uint16(height), uint16(width), "")
+	} else {
+		ptyConn, err = conn.NamedReconnectingPTY(ctx, session, uint16(height), uint16(width), "")
+	}
+	if err != nil {
+		return xerrors.Errorf("attach to session: %w", err)
+	}
+	defer ptyConn.Close()
+
+	var encoderMu sync.Mutex
+	encoder := json.NewEncoder(ptyConn)
+	send := func(req codersdk.ReconnectingPTYRequest) error {
+		encoderMu.Lock()
+		defer encoderMu.Unlock()
+		return encoder.Encode(req)
+	}
+
+	if isTTY {
+		cliui.Infof(inv.Stderr, "Attached to session %q. Type ~. at the start of a line to detach.", session)
+		state, err := term.MakeRaw(int(stdinFile.Fd()))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = term.Restore(int(stdinFile.Fd()), state)
+		}()
+
+		windowChange := listenWindowSize(ctx)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-windowChange:
+				}
+				width, height, err := term.GetSize(int(stdoutFile.Fd()))
+				if err != nil {
+					continue
+				}
+				_ = send(codersdk.ReconnectingPTYRequest{
+					Height: uint16(height),
+					Width:  uint16(width),
+				})
+			}
+		}()
+	}
+
+	detached := make(chan struct{})
+	go func() {
+		// Closing the connection detaches from the session.
+		defer ptyConn.Close()
+		escape := &sessionEscape{lineStart: true}
+		buffer := make([]byte, 1024)
+		for {
+			read, err := inv.Stdin.Read(buffer)
+			data, detach := escape.filter(buffer[:read])
+			if len(data) > 0 {
+				if send(codersdk.ReconnectingPTYRequest{Data: string(data)}) != nil {
+					return
+				}
+			}
+			if detach {
+				close(detached)
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	_, _ = io.Copy(inv.Stdout, ptyConn)
+
+	select {
+	case <-detached:
+		if isTTY {
+			// The terminal is still in raw mode.
+			_, _ = fmt.Fprint(inv.Stderr, "\r\n")
+		}
+		cliui.Infof(inv.Stderr, "Detached from session %q, reattach with \"coder ssh %s --session %s\".", session, workspace.Name, session)
+	default:
+	}
+	return nil
+}
+
+// sessionEscape detects the "~." sequence at the start of a line that detaches
+// from a session, like the escape sequence of OpenSSH. "~~" sends a single
+// tilde.
+type sessionEscape struct {
+	lineStart bool
+	tilde     bool
+}
+
+// filter returns the input without escape sequences, and whether it contained
+// the sequence to detach.
+func (e *sessionEscape) filter(p []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(p)+1)
+	for _, b := range p {
+		if e.tilde {
+			e.tilde = false
+			switch b {
+			case '.':
+				return out, true
+			case '~':
+				out = append(out, '~')
+				e.lineStart = false
+				continue
+			}
+			out = append(out, '~')
+		} else if e.lineStart && b == '~' {
+			e.tilde = true
+			continue
+		}
+		out = append(out, b)
+		e.lineStart = b == '\r' || b == '\n'
+	}
+	return out, false
+}
@@ -10,6 +10,7 @@ import (
 )
 
 func (r *RootCmd) start() *clibase.Cmd {
+	var wait string
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
@@ -21,15 +22,26 @@ func (r *RootCmd) start() *clibase.Cmd {
 		),
 		Options: clibase.OptionSet{
 			cliui.SkipPromptOption(),
+			agentWaitOption(&wait, "CODER_START_WAIT", agentWaitNo),
 		},
 		Handler: func(inv *clibase.Invocation) error {
 			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
-			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			req := codersdk.CreateWorkspaceBuildRequest{
 				Transition: codersdk.WorkspaceTransitionStart,
-			})
+			}
+			// Workspaces that update automatically are started on the active
+			// version, so prompt for any parameters it adds.
+			if workspace.Outdated && (workspace.AutomaticUpdates == codersdk.AutomaticUpdatesAlways || workspace.TemplateRequireActiveVersion) {
+				_, _ = fmt.Fprintf(inv.Stdout, "Updating the %s workspace to the active template version.\n", cliui.Styles.Keyword.Render(workspace.Name))
+				req, err = prepWorkspaceUpdate(inv, client, workspace, workspaceUpdateArgs{})
+				if err != nil {
+					return err
+				}
+			}
+			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, req)
 			if err != nil {
 				return err
 			}
@@ -38,6 +50,10 @@ func (r *RootCmd) start() *clibase.Cmd {
 			if err != nil {
 				return err
 			}
+			err = awaitWorkspaceAgents(inv, client, workspace, build.ID, wait)
+			if err != nil {
+				return err
+			}
 
 			_, _ = fmt.Fprintf(inv.Stdout, "\nThe %s workspace has been started at %s!\n", cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
 			return nil
@@ -46,7 +46,9 @@ func (r *RootCmd) templateDelete() *clibase.Cmd {
 					templates = append(templates, template)
 				}
 			} else {
-				allTemplates, err := client.TemplatesByOrganization(ctx, organization.ID)
+				allTemplates, err := client.TemplatesByOrganizationWithFilter(ctx, organization.ID, codersdk.TemplateFilter{
+					IncludeDeprecated: true,
+				})
 				if err != nil {
 					return xerrors.Errorf("get templates by organization: %w", err)
 				}
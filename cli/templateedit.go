@@ -20,9 +20,19 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		icon                         string
 		defaultTTL                   time.Duration
 		maxTTL                       time.Duration
+		inactivityTTL                time.Duration
+		dormantAutoDeleteTTL         time.Duration
+		failureTTL                   time.Duration
+		restartRequirementDaysOfWeek []string
+		restartRequirementWeeks      int64
 		allowUserCancelWorkspaceJobs bool
 		allowUserAutostart           bool
 		allowUserAutostop            bool
+		deprecationMessage           string
+		requireActiveVersion         bool
+		maxPortShareLevel            string
+		sessionRecording             bool
+		quotaCostMultiplier          float64
 	)
 	client := new(codersdk.Client)
 
@@ -34,17 +44,18 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		),
 		Short: "Edit the metadata of a template by name.",
 		Handler: func(inv *clibase.Invocation) error {
-			if maxTTL != 0 || !allowUserAutostart || !allowUserAutostop {
+			restartRequirementChanged := inv.ParsedFlags().Changed("restart-requirement-weekdays") || inv.ParsedFlags().Changed("restart-requirement-weeks")
+			if maxTTL != 0 || inactivityTTL != 0 || dormantAutoDeleteTTL != 0 || failureTTL != 0 || restartRequirementChanged || !allowUserAutostart || !allowUserAutostop {
 				entitlements, err := client.Entitlements(inv.Context())
 				var sdkErr *codersdk.Error
 				if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
-					return xerrors.Errorf("your deployment appears to be an AGPL deployment, so you cannot set --max-ttl, --allow-user-autostart=false or --allow-user-autostop=false")
+					return xerrors.Errorf("your deployment appears to be an AGPL deployment, so you cannot set --max-ttl, --inactivity-ttl, --dormant-autodelete-ttl, --failure-ttl, --restart-requirement-weekdays, --restart-requirement-weeks, --allow-user-autostart=false or --allow-user-autostop=false")
 				} else if err != nil {
 					return xerrors.Errorf("get entitlements: %w", err)
 				}
 
 				if !entitlements.Features[codersdk.FeatureAdvancedTemplateScheduling].Enabled {
-					return xerrors.Errorf("your license is not entitled to use advanced template scheduling, so you cannot set --max-ttl, --allow-user-autostart=false or --allow-user-autostop=false")
+					return xerrors.Errorf("your license is not entitled to use advanced template scheduling, so you cannot set --max-ttl, --inactivity-ttl, --dormant-autodelete-ttl, --failure-ttl, --restart-requirement-weekdays, --restart-requirement-weeks, --allow-user-autostart=false or --allow-user-autostop=false")
 				}
 			}
 
@@ -65,10 +76,44 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				Icon:                         icon,
 				DefaultTTLMillis:             defaultTTL.Milliseconds(),
 				MaxTTLMillis:                 maxTTL.Milliseconds(),
+				InactivityTTLMillis:          inactivityTTL.Milliseconds(),
+				DormantAutoDeleteTTLMillis:   dormantAutoDeleteTTL.Milliseconds(),
+				FailureTTLMillis:             failureTTL.Milliseconds(),
 				AllowUserCancelWorkspaceJobs: allowUserCancelWorkspaceJobs,
 				AllowUserAutostart:           allowUserAutostart,
 				AllowUserAutostop:            allowUserAutostop,
 			}
+			if restartRequirementChanged {
+				// Only the flags that were passed are changed, the rest of the
+				// restart requirement is kept as is.
+				restartRequirement := template.RestartRequirement
+				if inv.ParsedFlags().Changed("restart-requirement-weekdays") {
+					restartRequirement.DaysOfWeek = restartRequirementDaysOfWeek
+					if len(restartRequirementDaysOfWeek) == 1 && restartRequirementDaysOfWeek[0] == "none" {
+						restartRequirement.DaysOfWeek = []string{}
+					}
+				}
+				if inv.ParsedFlags().Changed("restart-requirement-weeks") {
+					restartRequirement.Weeks = restartRequirementWeeks
+				}
+				req.RestartRequirement = &restartRequirement
+			}
+			if inv.ParsedFlags().Changed("deprecated") {
+				req.DeprecationMessage = &deprecationMessage
+			}
+			if inv.ParsedFlags().Changed("require-active-version") {
+				req.RequireActiveVersion = &requireActiveVersion
+			}
+			if inv.ParsedFlags().Changed("max-port-share-level") {
+				level := codersdk.WorkspaceAppSharingLevel(maxPortShareLevel)
+				req.MaxPortShareLevel = &level
+			}
+			if inv.ParsedFlags().Changed("session-recording") {
+				req.SessionRecording = &sessionRecording
+			}
+			if inv.ParsedFlags().Changed("quota-cost-multiplier") {
+				req.QuotaCostMultiplier = &quotaCostMultiplier
+			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
 			if err != nil {
@@ -110,6 +155,31 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Description: "Edit the template maximum time before shutdown - workspaces created from this template must shutdown within the given duration after starting. This is an enterprise-only feature.",
 			Value:       clibase.DurationOf(&maxTTL),
 		},
+		{
+			Flag:        "inactivity-ttl",
+			Description: "Edit the template inactivity time before a workspace is marked dormant - dormant workspaces are stopped and cannot be started until they are made active again. This is an enterprise-only feature.",
+			Value:       clibase.DurationOf(&inactivityTTL),
+		},
+		{
+			Flag:        "dormant-autodelete-ttl",
+			Description: "Edit the template time before a dormant workspace is automatically deleted. This is an enterprise-only feature.",
+			Value:       clibase.DurationOf(&dormantAutoDeleteTTL),
+		},
+		{
+			Flag:        "failure-ttl",
+			Description: "Edit the template time after which a workspace whose start build failed is automatically stopped. This is an enterprise-only feature.",
+			Value:       clibase.DurationOf(&failureTTL),
+		},
+		{
+			Flag:        "restart-requirement-weekdays",
+			Description: "Edit the template restart requirement weekdays - workspaces created from this template must be restarted on the given weekdays during the owner's quiet hours. Use \"none\" to clear the requirement. This is an enterprise-only feature.",
+			Value:       clibase.StringArrayOf(&restartRequirementDaysOfWeek),
+		},
+		{
+			Flag:        "restart-requirement-weeks",
+			Description: "Edit the template restart requirement weeks - workspaces created from this template must be restarted on an n-weekly basis. This is an enterprise-only feature.",
+			Value:       clibase.Int64Of(&restartRequirementWeeks),
+		},
 		{
 			Flag:        "allow-user-cancel-workspace-jobs",
 			Description: "Allow users to cancel in-progress workspace jobs.",
@@ -128,6 +198,35 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Default:     "true",
 			Value:       clibase.BoolOf(&allowUserAutostop),
 		},
+		{
+			Flag:        "deprecated",
+			Description: "Deprecate the template with the given message - deprecated templates cannot be used to create new workspaces, and the message is shown to users of existing workspaces. Use an empty string to un-deprecate the template.",
+			Value:       clibase.StringOf(&deprecationMessage),
+		},
+		{
+			Flag:        "require-active-version",
+			Description: "Require workspaces to be started on the active version of the template, regardless of their automatic updates setting.",
+			Value:       clibase.BoolOf(&requireActiveVersion),
+		},
+		{
+			Flag:        "max-port-share-level",
+			Description: "The highest share level that workspace owners can grant to the ports of their workspaces.",
+			Value: clibase.EnumOf(&maxPortShareLevel,
+				string(codersdk.WorkspaceAppSharingLevelOwner),
+				string(codersdk.WorkspaceAppSharingLevelAuthenticated),
+				string(codersdk.WorkspaceAppSharingLevelPublic),
+			),
+		},
+		{
+			Flag:        "session-recording",
+			Description: "Record interactive terminal sessions in workspaces of the template. Recordings are linked from the audit log.",
+			Value:       clibase.BoolOf(&sessionRecording),
+		},
+		{
+			Flag:        "quota-cost-multiplier",
+			Description: "Multiply the daily cost of workspaces of the template by this factor before it's counted against quotas.",
+			Value:       clibase.Float64Of(&quotaCostMultiplier),
+		},
 		cliui.SkipPromptOption(),
 	}
 
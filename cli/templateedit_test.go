@@ -109,6 +109,131 @@ func TestTemplateEdit(t *testing.T) {
 		assert.Equal(t, template.DefaultTTLMillis, updated.DefaultTTLMillis)
 		assert.Equal(t, template.AllowUserCancelWorkspaceJobs, updated.AllowUserCancelWorkspaceJobs)
 	})
+	t.Run("Deprecated", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--deprecated", "Use the new template")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.Deprecated)
+		assert.Equal(t, "Use the new template", updated.DeprecationMessage)
+
+		// An empty message un-deprecates the template.
+		inv, root = clitest.New(t, "templates", "edit", template.Name, "--deprecated=")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.False(t, updated.Deprecated)
+	})
+	t.Run("RequireActiveVersion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--require-active-version")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.RequireActiveVersion)
+
+		// Editing other fields leaves the setting unchanged.
+		inv, root = clitest.New(t, "templates", "edit", template.Name, "--description", "New description")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.RequireActiveVersion)
+	})
+	t.Run("MaxPortShareLevel", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Equal(t, codersdk.WorkspaceAppSharingLevelOwner, template.MaxPortShareLevel)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--max-port-share-level", "public")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.WorkspaceAppSharingLevelPublic, updated.MaxPortShareLevel)
+	})
+	t.Run("SessionRecording", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.False(t, template.SessionRecording)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--session-recording")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.SessionRecording)
+	})
+	t.Run("QuotaCostMultiplier", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Equal(t, 1.0, template.QuotaCostMultiplier)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--quota-cost-multiplier", "2.5")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2.5, updated.QuotaCostMultiplier)
+
+		// Negative multipliers are rejected.
+		inv, root = clitest.New(t, "templates", "edit", template.Name, "--quota-cost-multiplier=-1")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "quota_cost_multiplier")
+	})
 	t.Run("InvalidDisplayName", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
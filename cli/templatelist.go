@@ -12,7 +12,7 @@ import (
 
 func (r *RootCmd) templateList() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
-		cliui.TableFormat([]templateTableRow{}, []string{"name", "last updated", "used by"}),
+		cliui.TableFormat([]templateTableRow{}, []string{"name", "last updated", "used by", "deprecated"}),
 		cliui.JSONFormat(),
 	)
 
@@ -29,7 +29,9 @@ func (r *RootCmd) templateList() *clibase.Cmd {
 			if err != nil {
 				return err
 			}
-			templates, err := client.TemplatesByOrganization(inv.Context(), organization.ID)
+			templates, err := client.TemplatesByOrganizationWithFilter(inv.Context(), organization.ID, codersdk.TemplateFilter{
+				IncludeDeprecated: true,
+			})
 			if err != nil {
 				return err
 			}
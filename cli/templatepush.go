@@ -114,6 +114,7 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 		variablesFile   string
 		variables       []string
 		alwaysPrompt    bool
+		activate        bool
 		provisionerTags []string
 		uploadFlags     templateUploadFlags
 	)
@@ -174,6 +175,15 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 				return xerrors.Errorf("job failed: %s", job.Job.Status)
 			}
 
+			if !activate {
+				_, _ = fmt.Fprintf(inv.Stdout, "Created version %s at %s! Promote it with: %s\n",
+					cliui.Styles.Keyword.Render(job.Name),
+					cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)),
+					cliui.Styles.Code.Render(fmt.Sprintf("coder templates versions promote %s %s", template.Name, job.Name)),
+				)
+				return nil
+			}
+
 			err = client.UpdateActiveTemplateVersion(inv.Context(), template.ID, codersdk.UpdateActiveTemplateVersion{
 				ID: job.ID,
 			})
@@ -233,6 +243,12 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 			Description: "Always prompt all parameters. Does not pull parameter values from active template version.",
 			Value:       clibase.BoolOf(&alwaysPrompt),
 		},
+		{
+			Flag:        "activate",
+			Description: "Whether the new template version will be promoted to the active version of the template once it builds successfully.",
+			Default:     "true",
+			Value:       clibase.BoolOf(&activate),
+		},
 		cliui.SkipPromptOption(),
 		uploadFlags.option(),
 	}
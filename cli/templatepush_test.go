@@ -19,6 +19,7 @@ import (
 	"github.com/coder/coder/provisioner/echo"
 	"github.com/coder/coder/provisionersdk/proto"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestTemplatePush(t *testing.T) {
@@ -156,6 +157,41 @@ func TestTemplatePush(t *testing.T) {
 		require.Equal(t, "example", templateVersions[1].Name)
 	})
 
+	t.Run("NoActivate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ProvisionComplete,
+		})
+		inv, root := clitest.New(t, "templates", "push", template.Name, "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho), "--name", "example", "--activate=false", "--yes")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		execDone := make(chan error)
+		go func() {
+			execDone <- inv.Run()
+		}()
+
+		pty.ExpectMatch("coder templates versions promote " + template.Name + " example")
+		require.NoError(t, <-execDone)
+
+		// Assert that the new version was created but not activated.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		templateVersion, err := client.TemplateVersionByName(ctx, template.ID, "example")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, templateVersion.Job.Status)
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+	})
+
 	t.Run("UseWorkingDir", func(t *testing.T) {
 		t.Parallel()
 
@@ -23,12 +23,22 @@ func (r *RootCmd) templateVersions() *clibase.Cmd {
 				Description: "List versions of a specific template",
 				Command:     "coder templates versions list my-template",
 			},
+			example{
+				Description: "Make a specific version the active version of a template",
+				Command:     "coder templates versions promote my-template my-version",
+			},
+			example{
+				Description: "Make the previously promoted version the active version of a template",
+				Command:     "coder templates versions rollback my-template",
+			},
 		),
 		Handler: func(inv *clibase.Invocation) error {
 			return inv.Command.HelpHandler(inv)
 		},
 		Children: []*clibase.Cmd{
 			r.templateVersionsList(),
+			r.templateVersionsPromote(),
+			r.templateVersionsRollback(),
 		},
 	}
 
@@ -82,16 +92,110 @@ func (r *RootCmd) templateVersionsList() *clibase.Cmd {
 	return cmd
 }
 
+func (r *RootCmd) templateVersionsPromote() *clibase.Cmd {
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use: "promote <template> <version>",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Short: "Make the specified version the active version of the template",
+		Handler: func(inv *clibase.Invocation) error {
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			version, err := client.TemplateVersionByName(inv.Context(), template.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("get template version by name: %w", err)
+			}
+
+			return promoteTemplateVersion(inv, client, template, version)
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) templateVersionsRollback() *clibase.Cmd {
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use: "rollback <template>",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Short: "Make the previously promoted version the active version of the template",
+		Long:  "Repeated rollbacks walk back through the versions that were promoted before.",
+		Handler: func(inv *clibase.Invocation) error {
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			version, err := client.RollbackActiveTemplateVersion(inv.Context(), template.ID)
+			if err != nil {
+				return xerrors.Errorf("roll back active template version: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Rolled back template %s to version %s at %s!\n",
+				cliui.Styles.Keyword.Render(template.Name),
+				cliui.Styles.Keyword.Render(version.Name),
+				cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)),
+			)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// promoteTemplateVersion makes the version the active version of the template.
+func promoteTemplateVersion(inv *clibase.Invocation, client *codersdk.Client, template codersdk.Template, version codersdk.TemplateVersion) error {
+	if version.ID == template.ActiveVersionID {
+		return xerrors.Errorf("version %q is already the active version of template %q", version.Name, template.Name)
+	}
+	if version.Job.Status != codersdk.ProvisionerJobSucceeded {
+		return xerrors.Errorf("version %q cannot be promoted: job %s", version.Name, version.Job.Status)
+	}
+
+	err := client.UpdateActiveTemplateVersion(inv.Context(), template.ID, codersdk.UpdateActiveTemplateVersion{
+		ID: version.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("update active template version: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(inv.Stdout, "Promoted version %s of template %s at %s!\n",
+		cliui.Styles.Keyword.Render(version.Name),
+		cliui.Styles.Keyword.Render(template.Name),
+		cliui.Styles.DateTimeStamp.Render(time.Now().Format(time.Stamp)),
+	)
+	return nil
+}
+
 type templateVersionRow struct {
 	// For json format:
 	TemplateVersion codersdk.TemplateVersion `table:"-"`
 
 	// For table format:
-	Name      string    `json:"-" table:"name,default_sort"`
-	CreatedAt time.Time `json:"-" table:"created at"`
-	CreatedBy string    `json:"-" table:"created by"`
-	Status    string    `json:"-" table:"status"`
-	Active    string    `json:"-" table:"active"`
+	Name       string    `json:"-" table:"name,default_sort"`
+	CreatedAt  time.Time `json:"-" table:"created at"`
+	CreatedBy  string    `json:"-" table:"created by"`
+	Status     string    `json:"-" table:"status"`
+	Active     string    `json:"-" table:"active"`
+	PromotedAt string    `json:"-" table:"promoted at"`
+	PromotedBy string    `json:"-" table:"promoted by"`
 }
 
 // templateVersionsToRows converts a list of template versions to a list of rows
@@ -104,12 +208,23 @@ func templateVersionsToRows(activeVersionID uuid.UUID, templateVersions ...coder
 			activeStatus = cliui.Styles.Code.Render(cliui.Styles.Keyword.Render("Active"))
 		}
 
+		var promotedAt, promotedBy string
+		if templateVersion.PromotedAt != nil {
+			promotedAt = templateVersion.PromotedAt.Format(time.Stamp)
+		}
+		if templateVersion.PromotedBy != nil {
+			promotedBy = templateVersion.PromotedBy.Username
+		}
+
 		rows[i] = templateVersionRow{
-			Name:      templateVersion.Name,
-			CreatedAt: templateVersion.CreatedAt,
-			CreatedBy: templateVersion.CreatedBy.Username,
-			Status:    strings.Title(string(templateVersion.Job.Status)),
-			Active:    activeStatus,
+			TemplateVersion: templateVersion,
+			Name:            templateVersion.Name,
+			CreatedAt:       templateVersion.CreatedAt,
+			CreatedBy:       templateVersion.CreatedBy.Username,
+			Status:          strings.Title(string(templateVersion.Job.Status)),
+			Active:          activeStatus,
+			PromotedAt:      promotedAt,
+			PromotedBy:      promotedBy,
 		}
 	}
 
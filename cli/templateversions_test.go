@@ -7,7 +7,9 @@ import (
 
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestTemplateVersions(t *testing.T) {
@@ -36,4 +38,96 @@ func TestTemplateVersions(t *testing.T) {
 		pty.ExpectMatch(version.CreatedBy.Username)
 		pty.ExpectMatch("Active")
 	})
+
+	t.Run("Promote", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+
+		inv, root := clitest.New(t, "templates", "versions", "promote", template.Name, version2.Name)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		errC := make(chan error)
+		go func() {
+			errC <- inv.Run()
+		}()
+
+		pty.ExpectMatch("Promoted version")
+		require.NoError(t, <-errC)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		template, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version2.ID, template.ActiveVersionID)
+
+		promoted, err := client.TemplateVersion(ctx, version2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, promoted.PromotedAt)
+		require.NotNil(t, promoted.PromotedBy)
+		require.Equal(t, user.UserID, promoted.PromotedBy.ID)
+
+		// Promoting the active version again fails.
+		inv, root = clitest.New(t, "templates", "versions", "promote", template.Name, version2.Name)
+		clitest.SetupConfig(t, client, root)
+		err = inv.Run()
+		require.ErrorContains(t, err, "already the active version")
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		// Nothing to roll back to yet.
+		inv, root := clitest.New(t, "templates", "versions", "rollback", template.Name)
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "no previously promoted version")
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+		version3 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version3.ID)
+		for _, promoted := range []codersdk.TemplateVersion{version2, version3} {
+			err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+				ID: promoted.ID,
+			})
+			require.NoError(t, err)
+		}
+
+		// Repeated rollbacks walk back through the promotions instead of
+		// flipping between the last two versions.
+		for _, previous := range []codersdk.TemplateVersion{version2, version} {
+			inv, root = clitest.New(t, "templates", "versions", "rollback", template.Name)
+			clitest.SetupConfig(t, client, root)
+			pty := ptytest.New(t).Attach(inv)
+
+			errC := make(chan error)
+			go func() {
+				errC <- inv.Run()
+			}()
+
+			pty.ExpectMatch(previous.Name)
+			require.NoError(t, <-errC)
+
+			template, err = client.Template(ctx, template.ID)
+			require.NoError(t, err)
+			require.Equal(t, previous.ID, template.ActiveVersionID)
+		}
+
+		inv, root = clitest.New(t, "templates", "versions", "rollback", template.Name)
+		clitest.SetupConfig(t, client, root)
+		err = inv.Run()
+		require.ErrorContains(t, err, "no previously promoted version")
+	})
 }
@@ -33,46 +33,15 @@ func (r *RootCmd) update() *clibase.Cmd {
 				_, _ = fmt.Fprintf(inv.Stdout, "Workspace isn't outdated!\n")
 				return nil
 			}
-			template, err := client.Template(inv.Context(), workspace.TemplateID)
-			if err != nil {
-				return nil
-			}
-
-			var existingParams []codersdk.Parameter
-			var existingRichParams []codersdk.WorkspaceBuildParameter
-			if !alwaysPrompt {
-				existingParams, err = client.Parameters(inv.Context(), codersdk.ParameterWorkspace, workspace.ID)
-				if err != nil {
-					return nil
-				}
-
-				existingRichParams, err = client.WorkspaceBuildParameters(inv.Context(), workspace.LatestBuild.ID)
-				if err != nil {
-					return nil
-				}
-			}
-
-			buildParams, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
-				Template:           template,
-				ExistingParams:     existingParams,
-				ParameterFile:      parameterFile,
-				ExistingRichParams: existingRichParams,
-				RichParameterFile:  richParameterFile,
-				NewWorkspaceName:   workspace.Name,
-
-				UpdateWorkspace: true,
-				WorkspaceID:     workspace.LatestBuild.ID,
+			req, err := prepWorkspaceUpdate(inv, client, workspace, workspaceUpdateArgs{
+				ParameterFile:     parameterFile,
+				RichParameterFile: richParameterFile,
+				AlwaysPrompt:      alwaysPrompt,
 			})
 			if err != nil {
-				return nil
+				return err
 			}
-
-			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, codersdk.CreateWorkspaceBuildRequest{
-				TemplateVersionID:   template.ActiveVersionID,
-				Transition:          codersdk.WorkspaceTransitionStart,
-				ParameterValues:     buildParams.parameters,
-				RichParameterValues: buildParams.richParameters,
-			})
+			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, req)
 			if err != nil {
 				return err
 			}
@@ -114,3 +83,55 @@ func (r *RootCmd) update() *clibase.Cmd {
 	}
 	return cmd
 }
+
+type workspaceUpdateArgs struct {
+	ParameterFile     string
+	RichParameterFile string
+	AlwaysPrompt      bool
+}
+
+// prepWorkspaceUpdate returns a request that starts the workspace on the
+// active version of its template. Parameter values of the workspace are
+// reused, and the user is prompted for any new parameters.
+func prepWorkspaceUpdate(inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace, args workspaceUpdateArgs) (codersdk.CreateWorkspaceBuildRequest, error) {
+	template, err := client.Template(inv.Context(), workspace.TemplateID)
+	if err != nil {
+		return codersdk.CreateWorkspaceBuildRequest{}, err
+	}
+
+	var existingParams []codersdk.Parameter
+	var existingRichParams []codersdk.WorkspaceBuildParameter
+	if !args.AlwaysPrompt {
+		existingParams, err = client.Parameters(inv.Context(), codersdk.ParameterWorkspace, workspace.ID)
+		if err != nil {
+			return codersdk.CreateWorkspaceBuildRequest{}, err
+		}
+
+		existingRichParams, err = client.WorkspaceBuildParameters(inv.Context(), workspace.LatestBuild.ID)
+		if err != nil {
+			return codersdk.CreateWorkspaceBuildRequest{}, err
+		}
+	}
+
+	buildParams, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
+		Template:           template,
+		ExistingParams:     existingParams,
+		ParameterFile:      args.ParameterFile,
+		ExistingRichParams: existingRichParams,
+		RichParameterFile:  args.RichParameterFile,
+		NewWorkspaceName:   workspace.Name,
+
+		UpdateWorkspace: true,
+		WorkspaceID:     workspace.LatestBuild.ID,
+	})
+	if err != nil {
+		return codersdk.CreateWorkspaceBuildRequest{}, err
+	}
+
+	return codersdk.CreateWorkspaceBuildRequest{
+		TemplateVersionID:   template.ActiveVersionID,
+		Transition:          codersdk.WorkspaceTransitionStart,
+		ParameterValues:     buildParams.parameters,
+		RichParameterValues: buildParams.richParameters,
+	}, nil
+}
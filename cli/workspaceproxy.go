@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+// WorkspaceProxyName returns the workspace proxy that was selected with
+// --proxy, or pinned with "coder proxy use". It's "primary" if neither is set.
+func (r *RootCmd) WorkspaceProxyName() (string, error) {
+	if r.proxy != "" {
+		return r.proxy, nil
+	}
+	name, err := r.createConfig().Proxy().Read()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", xerrors.Errorf("read workspace proxy config: %w", err)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return codersdk.WorkspaceProxyPrimary, nil
+	}
+	return name, nil
+}
+
+// PinWorkspaceProxy stores the workspace proxy that connections are relayed
+// through when --proxy isn't set.
+func (r *RootCmd) PinWorkspaceProxy(name string) error {
+	err := r.createConfig().Proxy().Write(name)
+	if err != nil {
+		return xerrors.Errorf("write workspace proxy config: %w", err)
+	}
+	return nil
+}
+
+// workspaceProxy returns the workspace proxy to connect to workspaces
+// through, or nil for the primary access URL. Proxies relay connections to
+// the DERP server of the primary, so they don't shorten the path to
+// workspaces. Unhealthy proxies fall back to the primary access URL with a
+// warning.
+func (r *RootCmd) workspaceProxy(inv *clibase.Invocation, client *codersdk.Client) (*codersdk.WorkspaceProxy, error) {
+	name, err := r.WorkspaceProxyName()
+	if err != nil {
+		return nil, err
+	}
+	if name == codersdk.WorkspaceProxyPrimary {
+		return nil, nil
+	}
+
+	proxies, err := client.WorkspaceProxies(inv.Context())
+	if err != nil {
+		return nil, xerrors.Errorf("list workspace proxies: %w", err)
+	}
+
+	for i := range proxies {
+		proxy := proxies[i]
+		if proxy.Name != name {
+			continue
+		}
+		if proxy.Status.Status != codersdk.ProxyHealthy {
+			cliui.Warnf(inv.Stderr, "Workspace proxy %q is %s, connecting through the primary access URL.", proxy.Name, proxy.Status.Status)
+			return nil, nil
+		}
+		return &proxy, nil
+	}
+	return nil, xerrors.Errorf("workspace proxy %q not found", name)
+}
+
+// workspaceProxyURL returns the URL of the workspace proxy to relay
+// connections to workspaces through, or nil for the primary access URL.
+func (r *RootCmd) workspaceProxyURL(inv *clibase.Invocation, client *codersdk.Client) (*url.URL, error) {
+	proxy, err := r.workspaceProxy(inv, client)
+	if err != nil || proxy == nil {
+		return nil, err
+	}
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url of workspace proxy %q: %w", proxy.Name, err)
+	}
+	return proxyURL, nil
+}
+
+// appURLBase returns the scheme and the wildcard host of subdomain apps. Apps
+// are served by the selected workspace proxy if it hosts subdomain apps. The
+// host is empty if subdomain apps aren't hosted at all.
+func (r *RootCmd) appURLBase(inv *clibase.Invocation, client *codersdk.Client) (scheme string, host string, err error) {
+	proxy, err := r.workspaceProxy(inv, client)
+	if err != nil {
+		return "", "", err
+	}
+	if proxy != nil && proxy.WildcardHostname != "" {
+		proxyURL, err := url.Parse(proxy.URL)
+		if err != nil {
+			return "", "", xerrors.Errorf("parse url of workspace proxy %q: %w", proxy.Name, err)
+		}
+		host = proxy.WildcardHostname
+		if proxyURL.Port() != "" {
+			host += fmt.Sprintf(":%s", proxyURL.Port())
+		}
+		return proxyURL.Scheme, host, nil
+	}
+
+	appHost, err := client.AppHost(inv.Context())
+	if err != nil {
+		return "", "", xerrors.Errorf("get app host: %w", err)
+	}
+	return client.URL.Scheme, appHost.Host, nil
+}
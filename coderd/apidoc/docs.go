@@ -710,6 +710,59 @@ const docTemplate = `{
                 }
             }
         },
+        "/insights/quota": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get quota insights",
+                "operationId": "get-quota-insights",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week"
+                        ],
+                        "type": "string",
+                        "description": "Interval",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.QuotaInsightsResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/licenses": {
             "get": {
                 "security": [
@@ -1163,8 +1216,8 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get provisioner daemons",
-                "operationId": "get-provisioner-daemons",
+                "summary": "Get provisioner daemons by organization",
+                "operationId": "get-provisioner-daemons-by-organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -1208,6 +1261,33 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Provisioner types",
+                        "name": "provisioner",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags in the key=value format",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version of the daemon",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1217,7 +1297,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/{organization}/templates": {
+        "/organizations/{organization}/provisionerkeys": {
             "get": {
                 "security": [
                     {
@@ -1228,10 +1308,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get templates by organization",
-                "operationId": "get-templates-by-organization",
+                "summary": "List provisioner keys",
+                "operationId": "list-provisioner-keys",
                 "parameters": [
                     {
                         "type": "string",
@@ -1248,7 +1328,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Template"
+                                "$ref": "#/definitions/codersdk.ProvisionerKey"
                             }
                         }
                     }
@@ -1267,39 +1347,76 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template by organization",
-                "operationId": "create-template-by-organization",
+                "summary": "Create provisioner key",
+                "operationId": "create-provisioner-key",
                 "parameters": [
                     {
-                        "description": "Request body",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create provisioner key request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
+                            "$ref": "#/definitions/codersdk.CreateProvisionerKeyRequest"
                         }
-                    },
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateProvisionerKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/provisionerkeys/{provisionerkey}": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Delete provisioner key",
+                "operationId": "delete-provisioner-key",
+                "parameters": [
                     {
                         "type": "string",
+                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provisioner key name",
+                        "name": "provisionerkey",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/examples": {
+        "/organizations/{organization}/quota": {
             "get": {
                 "security": [
                     {
@@ -1310,10 +1427,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template examples by organization",
-                "operationId": "get-template-examples-by-organization",
+                "summary": "Get organization quota",
+                "operationId": "get-organization-quota",
                 "parameters": [
                     {
                         "type": "string",
@@ -1328,30 +1445,28 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateExample"
-                            }
+                            "$ref": "#/definitions/codersdk.OrganizationQuota"
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/templates/{templatename}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get templates by organization and template name",
-                "operationId": "get-templates-by-organization-and-template-name",
+                "summary": "Update organization quota",
+                "operationId": "update-organization-quota",
                 "parameters": [
                     {
                         "type": "string",
@@ -1362,24 +1477,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
+                        "description": "Update organization quota request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateOrganizationQuotaRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.OrganizationQuota"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
+        "/organizations/{organization}/roles": {
             "get": {
                 "security": [
                     {
@@ -1390,10 +1507,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get template version by organization, template, and name",
-                "operationId": "get-template-version-by-organization-template-and-name",
+                "summary": "Get organization custom roles",
+                "operationId": "get-organization-custom-roles",
                 "parameters": [
                     {
                         "type": "string",
@@ -1402,47 +1519,37 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.CustomRole"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get previous template version by organization, template, and name",
-                "operationId": "get-previous-template-version-by-organization-template-and-name",
+                "summary": "Create organization custom role",
+                "operationId": "create-organization-custom-role",
                 "parameters": [
                     {
                         "type": "string",
@@ -1453,32 +1560,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Create custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateCustomRoleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templateversions": {
-            "post": {
+        "/organizations/{organization}/roles/{role}": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1491,10 +1593,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Create template version by organization",
-                "operationId": "create-template-version-by-organization",
+                "summary": "Update organization custom role",
+                "operationId": "update-organization-custom-role",
                 "parameters": [
                     {
                         "type": "string",
@@ -1505,26 +1607,33 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create template version request",
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update custom role request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                            "$ref": "#/definitions/codersdk.UpdateCustomRoleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 }
             }
         },
-        "/parameters/{scope}/{id}": {
+        "/organizations/{organization}/templates": {
             "get": {
                 "security": [
                     {
@@ -1535,30 +1644,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parameters"
+                    "Templates"
                 ],
-                "summary": "Get parameters",
-                "operationId": "get-parameters",
+                "summary": "Get templates by organization",
+                "operationId": "get-templates-by-organization",
                 "parameters": [
                     {
-                        "enum": [
-                            "template",
-                            "workspace",
-                            "import_job"
-                        ],
                         "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Include deprecated templates",
+                        "name": "include_deprecated",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1567,7 +1670,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Parameter"
+                                "$ref": "#/definitions/codersdk.Template"
                             }
                         }
                     }
@@ -1586,53 +1689,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parameters"
+                    "Templates"
                 ],
-                "summary": "Create parameter",
-                "operationId": "create-parameter",
+                "summary": "Create template by organization",
+                "operationId": "create-template-by-organization",
                 "parameters": [
                     {
-                        "description": "Parameter request",
+                        "description": "Request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateParameterRequest"
+                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
                         }
                     },
-                    {
-                        "enum": [
-                            "template",
-                            "workspace",
-                            "import_job"
-                        ],
-                        "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Parameter"
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/parameters/{scope}/{id}/{name}": {
-            "delete": {
+        "/organizations/{organization}/templates/examples": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1642,35 +1732,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parameters"
+                    "Templates"
                 ],
-                "summary": "Delete parameter",
-                "operationId": "delete-parameter",
+                "summary": "Get template examples by organization",
+                "operationId": "get-template-examples-by-organization",
                 "parameters": [
-                    {
-                        "enum": [
-                            "template",
-                            "workspace",
-                            "import_job"
-                        ],
-                        "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Name",
-                        "name": "name",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -1679,13 +1750,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateExample"
+                            }
                         }
                     }
                 }
             }
         },
-        "/replicas": {
+        "/organizations/{organization}/templates/{templatename}": {
             "get": {
                 "security": [
                     {
@@ -1696,45 +1770,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
+                ],
+                "summary": "Get templates by organization and template name",
+                "operationId": "get-templates-by-organization-and-template-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get active replicas",
-                "operationId": "get-active-replicas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Replica"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
             "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/scim+json"
-                ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "SCIM 2.0: Get users",
-                "operationId": "scim-get-users",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                }
-            },
-            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1744,32 +1812,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Create new user",
-                "operationId": "scim-create-new-user",
+                "summary": "Get template version by organization, template, and name",
+                "operationId": "get-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
-                        "description": "New user",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users/{id}": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
             "get": {
                 "security": [
                     {
@@ -1777,73 +1858,95 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Get user by ID",
-                "operationId": "scim-get-user-by-id",
+                "summary": "Get previous template version by organization, template, and name",
+                "operationId": "get-previous-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "404": {
-                        "description": "Not Found"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/templateversions": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Update user account",
-                "operationId": "scim-update-user-status",
+                "summary": "Create template version by organization",
+                "operationId": "create-template-version-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update user request",
+                        "description": "Create template version request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}": {
+        "/parameters/{scope}/{id}": {
             "get": {
                 "security": [
                     {
@@ -1854,16 +1957,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Parameters"
                 ],
-                "summary": "Get template metadata by ID",
-                "operationId": "get-template-metadata-by-id",
+                "summary": "Get parameters",
+                "operationId": "get-parameters",
                 "parameters": [
+                    {
+                        "enum": [
+                            "template",
+                            "workspace",
+                            "import_job"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1872,45 +1987,74 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Parameter"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Parameters"
                 ],
-                "summary": "Delete template by ID",
-                "operationId": "delete-template-by-id",
+                "summary": "Create parameter",
+                "operationId": "create-parameter",
                 "parameters": [
+                    {
+                        "description": "Parameter request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateParameterRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "template",
+                            "workspace",
+                            "import_job"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.Parameter"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/parameters/{scope}/{id}/{name}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1920,16 +2064,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Parameters"
                 ],
-                "summary": "Update template metadata by ID",
-                "operationId": "update-template-metadata-by-id",
+                "summary": "Delete parameter",
+                "operationId": "delete-parameter",
                 "parameters": [
+                    {
+                        "enum": [
+                            "template",
+                            "workspace",
+                            "import_job"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -1938,13 +2101,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/acl": {
+        "/provisionerdaemons": {
             "get": {
                 "security": [
                     {
@@ -1957,77 +2120,60 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get template ACLs",
-                "operationId": "get-template-acls",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Get provisioner daemons",
+                "operationId": "get-provisioner-daemons",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateUser"
+                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
                             }
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/provisionerdaemons/serve": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Update template ACL",
-                "operationId": "update-template-acl",
+                "summary": "Serve provisioner daemon with provisioner key",
+                "operationId": "serve-provisioner-daemon-with-provisioner-key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Provisioner types",
+                        "name": "provisioner",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Update template request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
-                        }
+                        "type": "string",
+                        "description": "Version of the daemon",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/templates/{template}/daus": {
+        "/replicas": {
             "get": {
                 "security": [
                     {
@@ -2038,31 +2184,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get template DAUs by ID",
-                "operationId": "get-template-daus-by-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "Get active replicas",
+                "operationId": "get-active-replicas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateDAUsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Replica"
+                            }
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions": {
+        "/roles": {
             "get": {
                 "security": [
                     {
@@ -2073,52 +2212,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "List template versions by template ID",
-                "operationId": "list-template-versions-by-template-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "Members"
                 ],
+                "summary": "Get site custom roles",
+                "operationId": "get-site-custom-roles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                                "$ref": "#/definitions/codersdk.CustomRole"
                             }
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2131,85 +2241,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Update active template version by template ID",
-                "operationId": "update-active-template-version-by-template-id",
+                "summary": "Create site custom role",
+                "operationId": "create-site-custom-role",
                 "parameters": [
                     {
-                        "description": "Modified template version",
+                        "description": "Create custom role request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
+                            "$ref": "#/definitions/codersdk.CreateCustomRoleRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions/{templateversionname}": {
-            "get": {
+        "/roles/{role}": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get template version by template ID and name",
-                "operationId": "get-template-version-by-template-id-and-name",
+                "summary": "Update site custom role",
+                "operationId": "update-site-custom-role",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Role name",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Update custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateCustomRoleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
-                            }
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}": {
+        "/scim/v2/Groups": {
             "get": {
                 "security": [
                     {
@@ -2217,33 +2320,46 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version by ID",
-                "operationId": "get-template-version-by-id",
+                "summary": "SCIM 2.0: Get groups",
+                "operationId": "scim-get-groups",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
-                        }
+                        "description": "Filter, only displayName eq is supported",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based index of the first group",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of groups",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to members to omit the members of groups",
+                        "name": "excludedAttributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2253,63 +2369,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Patch template version by ID",
-                "operationId": "patch-template-version-by-id",
+                "summary": "SCIM 2.0: Create group",
+                "operationId": "scim-create-group",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Patch template version request",
+                        "description": "New group",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/cancel": {
-            "patch": {
+        "/scim/v2/Groups/{id}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version by ID",
-                "operationId": "cancel-template-version-by-id",
+                "summary": "SCIM 2.0: Get group by ID",
+                "operationId": "scim-get-group-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2318,14 +2426,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2335,129 +2444,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template version dry-run",
-                "operationId": "create-template-version-dry-run",
+                "summary": "SCIM 2.0: Replace group",
+                "operationId": "scim-replace-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dry-run request",
+                        "description": "Replace group request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run/{jobID}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version dry-run by job ID",
-                "operationId": "get-template-version-dry-run-by-job-id",
+                "summary": "SCIM 2.0: Delete group",
+                "operationId": "scim-delete-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
+            },
             "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/scim+json"
+                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version dry-run by job ID",
-                "operationId": "cancel-template-version-dry-run-by-job-id",
+                "summary": "SCIM 2.0: Update group",
+                "operationId": "scim-update-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "Update group request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMPatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
+        "/scim/v2/ResourceTypes": {
             "get": {
                 "security": [
                     {
@@ -2465,63 +2560,21 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get template version dry-run logs by job ID",
-                "operationId": "get-template-version-dry-run-logs-by-job-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
-                    }
+                    "Enterprise"
                 ],
+                "summary": "SCIM 2.0: Get resource types",
+                "operationId": "scim-get-resource-types",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+        "/scim/v2/ResourceTypes/{id}": {
             "get": {
                 "security": [
                     {
@@ -2529,45 +2582,30 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version dry-run resources by job ID",
-                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "summary": "SCIM 2.0: Get resource type by ID",
+                "operationId": "scim-get-resource-type-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Resource type ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/gitauth": {
+        "/scim/v2/Schemas": {
             "get": {
                 "security": [
                     {
@@ -2575,37 +2613,21 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get git auth by template version",
-                "operationId": "get-git-auth-by-template-version",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "SCIM 2.0: Get schemas",
+                "operationId": "scim-get-schemas",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionGitAuth"
-                            }
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/logs": {
+        "/scim/v2/Schemas/{id}": {
             "get": {
                 "security": [
                     {
@@ -2613,56 +2635,93 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get logs by template version",
-                "operationId": "get-logs-by-template-version",
+                "summary": "SCIM 2.0: Get schema by ID",
+                "operationId": "scim-get-schema-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Schema URN",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/scim/v2/ServiceProviderConfig": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get service provider config",
+                "operationId": "scim-get-service-provider-config",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get users",
+                "operationId": "scim-get-users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter, only userName eq is supported",
+                        "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
+                        "description": "1-based index of the first user",
+                        "name": "startIndex",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
+                        "type": "integer",
+                        "description": "Maximum number of users",
+                        "name": "count",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
-                        }
+                        "description": "OK"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/parameters": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2672,34 +2731,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get parameters by template version",
-                "operationId": "get-parameters-by-template-version",
+                "summary": "SCIM 2.0: Create new user",
+                "operationId": "scim-create-new-user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "New user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/parameter.ComputedValue"
-                            }
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/resources": {
+        "/scim/v2/Users/{id}": {
             "get": {
                 "security": [
                     {
@@ -2707,19 +2764,19 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get resources by template version",
-                "operationId": "get-resources-by-template-version",
+                "summary": "SCIM 2.0: Get user by ID",
+                "operationId": "scim-get-user-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2728,92 +2785,133 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/rich-parameters": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/scim+json"
+                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get rich parameters by template version",
-                "operationId": "get-rich-parameters-by-template-version",
+                "summary": "SCIM 2.0: Replace user account",
+                "operationId": "scim-replace-user",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Replace user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
-                            }
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/schema": {
-            "get": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Delete user account",
+                "operationId": "scim-delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    }
+                }
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get schema by template version",
-                "operationId": "get-schema-by-template-version",
+                "summary": "SCIM 2.0: Update user account",
+                "operationId": "scim-update-user-status",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ParameterSchema"
-                            }
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/variables": {
+        "/templates/{template}": {
             "get": {
                 "security": [
                     {
@@ -2826,14 +2924,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template variables by template version",
-                "operationId": "get-template-variables-by-template-version",
+                "summary": "Get template metadata by ID",
+                "operationId": "get-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -2842,37 +2940,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
-            }
-        },
-        "/updatecheck": {
-            "get": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Templates"
+                ],
+                "summary": "Delete template by ID",
+                "operationId": "delete-template-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Update check",
-                "operationId": "update-check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
-            }
-        },
-        "/users": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2882,16 +2988,171 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get users",
-                "operationId": "get-users",
+                "summary": "Update template metadata by ID",
+                "operationId": "update-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query"
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Template"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{template}/acl": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get template ACLs",
+                "operationId": "get-template-acls",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateUser"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Update template ACL",
+                "operationId": "update-template-acl",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update template request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{template}/daus": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template DAUs by ID",
+                "operationId": "get-template-daus-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateDAUsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{template}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "List template versions by template ID",
+                "operationId": "list-template-versions-by-template-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -2917,12 +3178,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                            }
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2935,33 +3199,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create new user",
-                "operationId": "create-new-user",
+                "summary": "Update active template version by template ID",
+                "operationId": "update-active-template-version-by-template-id",
                 "parameters": [
                     {
-                        "description": "Create user request",
+                        "description": "Modified template version",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/authmethods": {
-            "get": {
+        "/templates/{template}/versions/rollback": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2971,21 +3243,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "Roll back active template version by template ID",
+                "operationId": "roll-back-active-template-version-by-template-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get authentication methods",
-                "operationId": "get-authentication-methods",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.AuthMethods"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/users/first": {
+        "/templates/{template}/versions/{templateversionname}": {
             "get": {
                 "security": [
                     {
@@ -2996,20 +3278,1600 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "Get template version by template ID and name",
+                "operationId": "get-template-version-by-template-id-and-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version by ID",
+                "operationId": "get-template-version-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Patch template version by ID",
+                "operationId": "patch-template-version-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch template version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Cancel template version by ID",
+                "operationId": "cancel-template-version-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template version dry-run",
+                "operationId": "create-template-version-dry-run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dry-run request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run by job ID",
+                "operationId": "get-template-version-dry-run-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Cancel template version dry-run by job ID",
+                "operationId": "cancel-template-version-dry-run-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run logs by job ID",
+                "operationId": "get-template-version-dry-run-logs-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run resources by job ID",
+                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/gitauth": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get git auth by template version",
+                "operationId": "get-git-auth-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionGitAuth"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get logs by template version",
+                "operationId": "get-logs-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/parameters": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get parameters by template version",
+                "operationId": "get-parameters-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/parameter.ComputedValue"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get resources by template version",
+                "operationId": "get-resources-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/rich-parameters": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get rich parameters by template version",
+                "operationId": "get-rich-parameters-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/schema": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get schema by template version",
+                "operationId": "get-schema-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ParameterSchema"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/variables": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template variables by template version",
+                "operationId": "get-template-variables-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/updatecheck": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Update check",
+                "operationId": "update-check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get users",
+                "operationId": "get-users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new user",
+                "operationId": "create-new-user",
+                "parameters": [
+                    {
+                        "description": "Create user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/authmethods": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get authentication methods",
+                "operationId": "get-authentication-methods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuthMethods"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/first": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Check initial user created",
+                "operationId": "check-initial-user-created",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create initial user",
+                "operationId": "create-initial-user",
+                "parameters": [
+                    {
+                        "description": "First user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Log in user",
+                "operationId": "log-in-user",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Log out user",
+                "operationId": "log-out-user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oauth2/github/callback": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "OAuth 2.0 GitHub Callback",
+                "operationId": "oauth-20-github-callback",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    }
+                }
+            }
+        },
+        "/users/oidc/callback": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "OpenID Connect Callback",
+                "operationId": "openid-connect-callback",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    }
+                }
+            }
+        },
+        "/users/roles": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get site member roles",
+                "operationId": "get-site-member-roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by name",
+                "operationId": "get-user-by-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user",
+                "operationId": "delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/gitsshkey": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user Git SSH key",
+                "operationId": "get-user-git-ssh-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Regenerate user SSH key",
+                "operationId": "regenerate-user-ssh-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new session key",
+                "operationId": "create-new-session-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user tokens",
+                "operationId": "get-user-tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.APIKey"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create token API key",
+                "operationId": "create-token-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens/tokenconfig": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get token config",
+                "operationId": "get-token-config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TokenConfig"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens/{keyname}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get API key by token name",
+                "operationId": "get-api-key-by-token-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "Key Name",
+                        "name": "keyname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.APIKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/{keyid}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get API key by ID",
+                "operationId": "get-api-key-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.APIKey"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete API key",
+                "operationId": "delete-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get organizations by user",
+                "operationId": "get-organizations-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Organization"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/organizations/{organizationname}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get organization by user and organization name",
+                "operationId": "get-organization-by-user-and-organization-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization name",
+                        "name": "organizationname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/password": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user password",
+                "operationId": "update-user-password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/profile": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user profile",
+                "operationId": "update-user-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated profile",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/quiet-hours": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get user quiet hours schedule",
+                "operationId": "get-user-quiet-hours-schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Check initial user created",
-                "operationId": "check-initial-user-created",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3022,130 +4884,152 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Create initial user",
-                "operationId": "create-initial-user",
+                "summary": "Update user quiet hours schedule",
+                "operationId": "update-user-quiet-hours-schedule",
                 "parameters": [
                     {
-                        "description": "First user request",
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update schedule request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                            "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
-            "post": {
-                "consumes": [
-                    "application/json"
+        "/users/{user}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authorization"
+                    "Users"
                 ],
-                "summary": "Log in user",
-                "operationId": "log-in-user",
+                "summary": "Get user roles",
+                "operationId": "get-user-roles",
                 "parameters": [
                     {
-                        "description": "Login request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
-            }
-        },
-        "/users/logout": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Log out user",
-                "operationId": "log-out-user",
+                "summary": "Assign role to user",
+                "operationId": "assign-role-to-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/users/oauth2/github/callback": {
-            "get": {
+        "/users/{user}/status/activate": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "OAuth 2.0 GitHub Callback",
-                "operationId": "oauth-20-github-callback",
-                "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
-                    }
-                }
-            }
-        },
-        "/users/oidc/callback": {
-            "get": {
-                "security": [
+                "summary": "Activate user account",
+                "operationId": "activate-user-account",
+                "parameters": [
                     {
-                        "CoderSessionToken": []
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "OpenID Connect Callback",
-                "operationId": "openid-connect-callback",
                 "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
                     }
                 }
             }
         },
-        "/users/roles": {
-            "get": {
+        "/users/{user}/status/suspend": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3155,24 +5039,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "Users"
+                ],
+                "summary": "Suspend user account",
+                "operationId": "suspend-user-account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get site member roles",
-                "operationId": "get-site-member-roles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
-                            }
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/users/{user}": {
+        "/users/{user}/workspace/{workspacename}": {
             "get": {
                 "security": [
                     {
@@ -3183,10 +5073,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Workspaces"
                 ],
-                "summary": "Get user by name",
-                "operationId": "get-user-by-name",
+                "summary": "Get workspace metadata by user and workspace name",
+                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -3194,18 +5084,33 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3215,10 +5120,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Builds"
                 ],
-                "summary": "Delete user",
-                "operationId": "delete-user",
+                "summary": "Get workspace build by user, workspace name, and build number",
+                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
                 "parameters": [
                     {
                         "type": "string",
@@ -3226,19 +5131,34 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "number",
+                        "description": "Build number",
+                        "name": "buildnumber",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/gitsshkey": {
+        "/webhooks": {
             "get": {
                 "security": [
                     {
@@ -3249,63 +5169,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Get user Git SSH key",
-                "operationId": "get-user-git-ssh-key",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Webhooks"
                 ],
+                "summary": "Get webhooks",
+                "operationId": "get-webhooks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Webhook"
+                            }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Webhooks"
                 ],
-                "summary": "Regenerate user SSH key",
-                "operationId": "regenerate-user-ssh-key",
+                "summary": "Create webhook",
+                "operationId": "create-webhook",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
+                        "description": "Create webhook request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWebhookRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.CreateWebhookResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys": {
-            "post": {
+        "/webhooks/{webhook}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3315,31 +5234,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Webhooks"
                 ],
-                "summary": "Create new session key",
-                "operationId": "create-new-session-key",
+                "summary": "Get webhook by ID",
+                "operationId": "get-webhook-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.Webhook"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/keys/tokens": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3349,15 +5267,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Webhooks"
                 ],
-                "summary": "Get user tokens",
-                "operationId": "get-user-tokens",
+                "summary": "Delete webhook",
+                "operationId": "delete-webhook",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
                     }
@@ -3366,15 +5285,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.APIKey"
-                            }
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3387,39 +5303,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Webhooks"
                 ],
-                "summary": "Create token API key",
-                "operationId": "create-token-api-key",
+                "summary": "Update webhook",
+                "operationId": "update-webhook",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Create token request",
+                        "description": "Update webhook request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWebhookRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.Webhook"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/tokenconfig": {
+        "/webhooks/{webhook}/deliveries": {
             "get": {
                 "security": [
                     {
@@ -3430,15 +5347,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Webhooks"
                 ],
-                "summary": "Get token config",
-                "operationId": "get-token-config",
+                "summary": "Get webhook deliveries",
+                "operationId": "get-webhook-deliveries",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
                     }
@@ -3447,13 +5365,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TokenConfig"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WebhookDelivery"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/{keyname}": {
+        "/workspace-quota/{user}": {
             "get": {
                 "security": [
                     {
@@ -3464,10 +5385,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Get API key by token name",
-                "operationId": "get-api-key-by-token-name",
+                "summary": "Get workspace quota by user",
+                "operationId": "get-workspace-quota-by-user",
                 "parameters": [
                     {
                         "type": "string",
@@ -3475,182 +5396,235 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "string",
-                        "description": "Key Name",
-                        "name": "keyname",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/{keyid}": {
-            "get": {
+        "/workspaceagents/aws-instance-identity": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Get API key by ID",
-                "operationId": "get-api-key-by-id",
+                "summary": "Authenticate agent on AWS instance",
+                "operationId": "authenticate-agent-on-aws-instance",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
-                        "in": "path",
-                        "required": true
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/workspaceagents/azure-instance-identity": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Delete API key",
-                "operationId": "delete-api-key",
+                "summary": "Authenticate agent on Azure instance",
+                "operationId": "authenticate-agent-on-azure-instance",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
-                        "in": "path",
-                        "required": true
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/organizations": {
-            "get": {
+        "/workspaceagents/google-instance-identity": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Get organizations by user",
-                "operationId": "get-organizations-by-user",
+                "summary": "Authenticate agent on Google Cloud instance",
+                "operationId": "authenticate-agent-on-google-cloud-instance",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Organization"
-                            }
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/organizations/{organizationname}": {
-            "get": {
+        "/workspaceagents/me/app-health": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Get organization by user and organization name",
-                "operationId": "get-organization-by-user-and-organization-name",
+                "summary": "Submit workspace agent application health",
+                "operationId": "submit-workspace-agent-application-health",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
+                        "description": "Application health request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/coordinate": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Organization name",
-                        "name": "organizationname",
-                        "in": "path",
-                        "required": true
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent via Tailnet",
+                "operationId": "coordinate-workspace-agent-via-tailnet",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/debug-logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Patch workspace agent debug logs",
+                "operationId": "patch-workspace-agent-debug-logs",
+                "parameters": [
+                    {
+                        "description": "Debug logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchDebugLogs"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/users/{user}/password": {
-            "put": {
+        "/workspaceagents/me/devcontainers": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3659,83 +5633,77 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Update user password",
-                "operationId": "update-user-password",
+                "summary": "Register workspace agent dev container",
+                "operationId": "register-workspace-agent-dev-container",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update password request",
+                        "description": "Dev container",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
+                            "$ref": "#/definitions/agentsdk.PostDevcontainerRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostDevcontainerResponse"
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/profile": {
-            "put": {
+        "/workspaceagents/me/gitauth": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Update user profile",
-                "operationId": "update-user-profile",
+                "summary": "Get workspace agent Git auth",
+                "operationId": "get-workspace-agent-git-auth",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
+                        "format": "uri",
+                        "description": "Git URL",
+                        "name": "url",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Updated profile",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Wait for a new token to be issued",
+                        "name": "listen",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/roles": {
+        "/workspaceagents/me/gitsshkey": {
             "get": {
                 "security": [
                     {
@@ -3746,272 +5714,254 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Get user roles",
-                "operationId": "get-user-roles",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Agents"
                 ],
+                "summary": "Get workspace agent Git SSH key",
+                "operationId": "get-workspace-agent-git-ssh-key",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/agentsdk.GitSSHKey"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/workspaceagents/me/manifest": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Assign role to user",
-                "operationId": "assign-role-to-user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update roles request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
-                        }
-                    }
+                    "Agents"
                 ],
+                "summary": "Get authorized workspace agent manifest",
+                "operationId": "get-authorized-workspace-agent-manifest",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/agentsdk.Manifest"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/activate": {
-            "put": {
+        "/workspaceagents/me/metadata/{key}": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Activate user account",
-                "operationId": "activate-user-account",
+                "summary": "Submit workspace agent metadata",
+                "operationId": "submit-workspace-agent-metadata",
                 "parameters": [
+                    {
+                        "description": "Workspace agent metadata request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "string",
+                        "description": "metadata key",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.User"
-                        }
+                    "204": {
+                        "description": "Success"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/users/{user}/status/suspend": {
-            "put": {
+        "/workspaceagents/me/report-lifecycle": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Agents"
                 ],
-                "summary": "Suspend user account",
-                "operationId": "suspend-user-account",
+                "summary": "Submit workspace agent lifecycle state",
+                "operationId": "submit-workspace-agent-lifecycle-state",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
+                        "description": "Workspace agent lifecycle request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.User"
-                        }
+                    "204": {
+                        "description": "Success"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}": {
-            "get": {
+        "/workspaceagents/me/report-stats": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Agents"
                 ],
-                "summary": "Get workspace metadata by user and workspace name",
-                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "summary": "Submit workspace agent stats",
+                "operationId": "submit-workspace-agent-stats",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
+                        "description": "Stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Stats"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
-            "get": {
+        "/workspaceagents/me/scripts/{script}/status": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Agents"
                 ],
-                "summary": "Get workspace build by user, workspace name, and build number",
-                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "summary": "Submit workspace agent script status",
+                "operationId": "submit-workspace-agent-script-status",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
+                        "description": "Script status request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostScriptStatusRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "format": "number",
-                        "description": "Build number",
-                        "name": "buildnumber",
+                        "format": "uuid",
+                        "description": "Script ID",
+                        "name": "script",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                        }
+                    "204": {
+                        "description": "Success"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspace-quota/{user}": {
-            "get": {
+        "/workspaceagents/me/session-recordings": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Agents"
                 ],
-                "summary": "Get workspace quota by user",
-                "operationId": "get-workspace-quota-by-user",
+                "summary": "Submit workspace agent session recording",
+                "operationId": "submit-workspace-agent-session-recording",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
+                        "description": "Session recording",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostSessionRecordingRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                            "$ref": "#/definitions/agentsdk.PostSessionRecordingResponse"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/aws-instance-identity": {
+        "/workspaceagents/me/startup": {
             "post": {
                 "security": [
                     {
@@ -4027,31 +5977,31 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Authenticate agent on AWS instance",
-                "operationId": "authenticate-agent-on-aws-instance",
+                "summary": "Submit workspace agent startup",
+                "operationId": "submit-workspace-agent-startup",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "description": "Startup request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
-                        }
+                        "description": "OK"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/azure-instance-identity": {
-            "post": {
+        "/workspaceagents/me/startup-logs": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4066,16 +6016,16 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Authenticate agent on Azure instance",
-                "operationId": "authenticate-agent-on-azure-instance",
+                "summary": "Patch workspace agent startup logs",
+                "operationId": "patch-workspace-agent-startup-logs",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "description": "Startup logs",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                            "$ref": "#/definitions/agentsdk.PatchStartupLogs"
                         }
                     }
                 ],
@@ -4083,100 +6033,107 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/google-instance-identity": {
-            "post": {
+        "/workspaceagents/{workspaceagent}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Authenticate agent on Google Cloud instance",
-                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "summary": "Get workspace agent by ID",
+                "operationId": "get-workspace-agent-by-id",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/app-health": {
-            "post": {
+        "/workspaceagents/{workspaceagent}/connection": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent application health",
-                "operationId": "submit-workspace-agent-application-health",
+                "summary": "Get connection info for workspace agent",
+                "operationId": "get-connection-info-for-workspace-agent",
                 "parameters": [
                     {
-                        "description": "Application health request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/coordinate": {
+        "/workspaceagents/{workspaceagent}/coordinate": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Coordinate workspace agent via Tailnet",
-                "operationId": "coordinate-workspace-agent-via-tailnet",
+                "summary": "Coordinate workspace agent",
+                "operationId": "coordinate-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "101": {
                         "description": "Switching Protocols"
@@ -4184,7 +6141,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaceagents/me/gitauth": {
+        "/workspaceagents/{workspaceagent}/debug-logs": {
             "get": {
                 "security": [
                     {
@@ -4197,21 +6154,21 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get workspace agent Git auth",
-                "operationId": "get-workspace-agent-git-auth",
+                "summary": "Get debug logs by workspace agent",
+                "operationId": "get-debug-logs-by-workspace-agent",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uri",
-                        "description": "Git URL",
-                        "name": "url",
-                        "in": "query",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Wait for a new token to be issued",
-                        "name": "listen",
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
                         "in": "query"
                     }
                 ],
@@ -4219,13 +6176,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentDebugLog"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitsshkey": {
+        "/workspaceagents/{workspaceagent}/debug-logs/flush": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Flush debug logs of workspace agent",
+                "operationId": "flush-debug-logs-of-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/listening-ports": {
             "get": {
                 "security": [
                     {
@@ -4238,19 +6227,58 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get workspace agent Git SSH key",
-                "operationId": "get-workspace-agent-git-ssh-key",
+                "summary": "Get listening ports for workspace agent",
+                "operationId": "get-listening-ports-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/manifest": {
+        "/workspaceagents/{workspaceagent}/pty": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Open PTY to workspace agent",
+                "operationId": "open-pty-to-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/reconnecting-ptys": {
             "get": {
                 "security": [
                     {
@@ -4263,90 +6291,108 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get authorized workspace agent manifest",
-                "operationId": "get-authorized-workspace-agent-manifest",
+                "summary": "Get reconnecting PTY sessions of workspace agent",
+                "operationId": "get-reconnecting-pty-sessions-of-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Manifest"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentReconnectingPTYsResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/metadata/{key}": {
-            "post": {
+        "/workspaceagents/{workspaceagent}/startup-logs": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent metadata",
-                "operationId": "submit-workspace-agent-metadata",
+                "summary": "Get startup logs by workspace agent",
+                "operationId": "get-startup-logs-by-workspace-agent",
                 "parameters": [
-                    {
-                        "description": "Workspace agent metadata request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "format": "string",
-                        "description": "metadata key",
-                        "name": "key",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentStartupLog"
+                            }
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/report-lifecycle": {
-            "post": {
+        "/workspaceagents/{workspaceagent}/watch-metadata": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent lifecycle state",
-                "operationId": "submit-workspace-agent-lifecycle-state",
+                "summary": "Watch for workspace agent metadata updates",
+                "operationId": "watch-for-workspace-agent-metadata-updates",
                 "parameters": [
                     {
-                        "description": "Workspace agent lifecycle request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
+                    "200": {
                         "description": "Success"
                     }
                 },
@@ -4355,130 +6401,130 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaceagents/me/report-stats": {
-            "post": {
+        "/workspacebuilds/{workspacebuild}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Submit workspace agent stats",
-                "operationId": "submit-workspace-agent-stats",
+                "summary": "Get workspace build",
+                "operationId": "get-workspace-build",
                 "parameters": [
                     {
-                        "description": "Stats request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.Stats"
-                        }
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/startup": {
-            "post": {
+        "/workspacebuilds/{workspacebuild}/cancel": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Submit workspace agent startup",
-                "operationId": "submit-workspace-agent-startup",
+                "summary": "Cancel workspace build",
+                "operationId": "cancel-workspace-build",
                 "parameters": [
                     {
-                        "description": "Startup request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
-                        }
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/startup-logs": {
-            "patch": {
+        "/workspacebuilds/{workspacebuild}/logs": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Patch workspace agent startup logs",
-                "operationId": "patch-workspace-agent-startup-logs",
+                "summary": "Get workspace build logs",
+                "operationId": "get-workspace-build-logs",
                 "parameters": [
                     {
-                        "description": "Startup logs",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchStartupLogs"
-                        }
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}": {
+        "/workspacebuilds/{workspacebuild}/parameters": {
             "get": {
                 "security": [
                     {
@@ -4489,16 +6535,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Get workspace agent by ID",
-                "operationId": "get-workspace-agent-by-id",
+                "summary": "Get build parameters for workspace build",
+                "operationId": "get-build-parameters-for-workspace-build",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
                         "in": "path",
                         "required": true
                     }
@@ -4507,13 +6552,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/connection": {
+        "/workspacebuilds/{workspacebuild}/resources": {
             "get": {
                 "security": [
                     {
@@ -4524,16 +6572,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Get connection info for workspace agent",
-                "operationId": "get-connection-info-for-workspace-agent",
+                "summary": "Get workspace resources for workspace build",
+                "operationId": "get-workspace-resources-for-workspace-build",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
                         "in": "path",
                         "required": true
                     }
@@ -4542,42 +6589,50 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/coordinate": {
+        "/workspacebuilds/{workspacebuild}/state": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Coordinate workspace agent",
-                "operationId": "coordinate-workspace-agent",
+                "summary": "Get provisioner state for workspace build",
+                "operationId": "get-provisioner-state-for-workspace-build",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/listening-ports": {
+        "/workspacebuilds/{workspacebuild}/timings": {
             "get": {
                 "security": [
                     {
@@ -4588,16 +6643,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Get listening ports for workspace agent",
-                "operationId": "get-listening-ports-for-workspace-agent",
+                "summary": "Get workspace build timings",
+                "operationId": "get-workspace-build-timings",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
                         "in": "path",
                         "required": true
                     }
@@ -4606,122 +6661,154 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildTimings"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/pty": {
+        "/workspaceproxies": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Open PTY to workspace agent",
-                "operationId": "open-pty-to-workspace-agent",
+                "summary": "Get workspace proxies",
+                "operationId": "get-workspace-proxies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Create workspace proxy",
+                "operationId": "create-workspace-proxy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
+                        "description": "Create workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceProxyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/startup-logs": {
-            "get": {
+        "/workspaceproxies/me/issue-signed-app-token": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Get startup logs by workspace agent",
-                "operationId": "get-startup-logs-by-workspace-agent",
+                "summary": "Issue signed workspace app token",
+                "operationId": "issue-signed-workspace-app-token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
+                        "description": "Issue signed app token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/workspaceapps.IssueTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceAgentStartupLog"
-                            }
+                            "$ref": "#/definitions/wsproxysdk.IssueSignedAppTokenResponse"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/watch-metadata": {
-            "get": {
+        "/workspaceproxies/me/register": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Watch for workspace agent metadata updates",
-                "operationId": "watch-for-workspace-agent-metadata-updates",
+                "summary": "Register workspace proxy",
+                "operationId": "register-workspace-proxy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
+                        "description": "Register workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyResponse"
+                        }
                     }
                 },
                 "x-apidocgen": {
@@ -4729,7 +6816,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}": {
+        "/workspaceproxies/{workspaceproxy}": {
             "get": {
                 "security": [
                     {
@@ -4740,15 +6827,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get workspace build",
-                "operationId": "get-workspace-build",
+                "summary": "Get workspace proxy",
+                "operationId": "get-workspace-proxy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
                     }
@@ -4757,14 +6845,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
                         }
                     }
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}/cancel": {
-            "patch": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4774,15 +6860,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Cancel workspace build",
-                "operationId": "cancel-workspace-build",
+                "summary": "Delete workspace proxy",
+                "operationId": "delete-workspace-proxy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
                     }
@@ -4795,64 +6882,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}/logs": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get workspace build logs",
-                "operationId": "get-workspace-build-logs",
+                "summary": "Update workspace proxy",
+                "operationId": "update-workspace-proxy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
+                        "description": "Update workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PatchWorkspaceProxy"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceProxyResponse"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/parameters": {
+        "/workspaces": {
             "get": {
                 "security": [
                     {
@@ -4863,33 +6940,76 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Workspaces"
                 ],
-                "summary": "Get build parameters for workspace build",
-                "operationId": "get-build-parameters-for-workspace-build",
+                "summary": "List workspaces",
+                "operationId": "list-workspaces",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by owner username",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by template name",
+                        "name": "template",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter with partial-match by workspace name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "running",
+                            "stopping",
+                            "stopped",
+                            "failed",
+                            "canceling",
+                            "canceled",
+                            "deleted",
+                            "deleting"
+                        ],
+                        "type": "string",
+                        "description": "Filter by workspace status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "connected",
+                            "connecting",
+                            "disconnected",
+                            "timeout"
+                        ],
+                        "type": "string",
+                        "description": "Filter by agent status",
+                        "name": "has_agent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter to only dormant workspaces",
+                        "name": "dormant",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspacesResponse"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/resources": {
+        "/workspaces/{workspace}": {
             "get": {
                 "security": [
                     {
@@ -4900,94 +7020,77 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Workspaces"
                 ],
-                "summary": "Get workspace resources for workspace build",
-                "operationId": "get-workspace-resources-for-workspace-build",
+                "summary": "Get workspace metadata by ID",
+                "operationId": "get-workspace-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}/state": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Workspaces"
                 ],
-                "summary": "Get provisioner state for workspace build",
-                "operationId": "get-provisioner-state-for-workspace-build",
+                "summary": "Update workspace metadata by ID",
+                "operationId": "update-workspace-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    {
+                        "description": "Metadata update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceRequest"
                         }
                     }
-                }
-            }
-        },
-        "/workspaceproxies": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Enterprise"
                 ],
-                "summary": "Get workspace proxies",
-                "operationId": "get-workspace-proxies",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceProxy"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/workspaces/{workspace}/autostart": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4996,37 +7099,39 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
                 ],
-                "summary": "Create workspace proxy",
-                "operationId": "create-workspace-proxy",
+                "summary": "Update workspace autostart schedule by ID",
+                "operationId": "update-workspace-autostart-schedule-by-id",
                 "parameters": [
                     {
-                        "description": "Create workspace proxy request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceProxyRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutostartRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/workspaceproxies/me/issue-signed-app-token": {
-            "post": {
+        "/workspaces/{workspace}/autoupdates": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5035,39 +7140,38 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
                 ],
-                "summary": "Issue signed workspace app token",
-                "operationId": "issue-signed-workspace-app-token",
+                "summary": "Update workspace automatic updates by ID",
+                "operationId": "update-workspace-automatic-updates-by-id",
                 "parameters": [
                     {
-                        "description": "Issue signed app token request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Automatic updates request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/workspaceapps.IssueTokenRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutomaticUpdatesRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/wsproxysdk.IssueSignedAppTokenResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaces": {
+        "/workspaces/{workspace}/builds": {
             "get": {
                 "security": [
                     {
@@ -5078,56 +7182,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Builds"
                 ],
-                "summary": "List workspaces",
-                "operationId": "list-workspaces",
+                "summary": "Get workspace builds by workspace ID",
+                "operationId": "get-workspace-builds-by-workspace-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by owner username",
-                        "name": "owner",
-                        "in": "query"
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Filter by template name",
-                        "name": "template",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter with partial-match by workspace name",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "pending",
-                            "running",
-                            "stopping",
-                            "stopped",
-                            "failed",
-                            "canceling",
-                            "canceled",
-                            "deleted",
-                            "deleting"
-                        ],
-                        "type": "string",
-                        "description": "Filter by workspace status",
-                        "name": "status",
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "connected",
-                            "connecting",
-                            "disconnected",
-                            "timeout"
-                        ],
                         "type": "string",
-                        "description": "Filter by agent status",
-                        "name": "has_agent",
+                        "format": "date-time",
+                        "description": "Since timestamp",
+                        "name": "since",
                         "in": "query"
                     }
                 ],
@@ -5135,27 +7226,31 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspacesResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/workspaces/{workspace}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Builds"
                 ],
-                "summary": "Get workspace metadata by ID",
-                "operationId": "get-workspace-metadata-by-id",
+                "summary": "Create workspace build",
+                "operationId": "create-workspace-build",
                 "parameters": [
                     {
                         "type": "string",
@@ -5163,25 +7258,30 @@ const docTemplate = `{
                         "description": "Workspace ID",
                         "name": "workspace",
                         "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace build request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/workspaces/{workspace}/dormant": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5193,8 +7293,8 @@ const docTemplate = `{
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace metadata by ID",
-                "operationId": "update-workspace-metadata-by-id",
+                "summary": "Update workspace dormancy by ID",
+                "operationId": "update-workspace-dormancy-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -5205,12 +7305,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Metadata update request",
+                        "description": "Workspace dormancy update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceDormancy"
                         }
                     }
                 ],
@@ -5221,7 +7321,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspace}/autostart": {
+        "/workspaces/{workspace}/extend": {
             "put": {
                 "security": [
                     {
@@ -5231,11 +7331,14 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace autostart schedule by ID",
-                "operationId": "update-workspace-autostart-schedule-by-id",
+                "summary": "Extend workspace deadline by ID",
+                "operationId": "extend-workspace-deadline-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -5246,23 +7349,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Schedule update request",
+                        "description": "Extend deadline update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutostartRequest"
+                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/builds": {
+        "/workspaces/{workspace}/port-share": {
             "get": {
                 "security": [
                     {
@@ -5273,10 +7379,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "PortSharing"
                 ],
-                "summary": "Get workspace builds by workspace ID",
-                "operationId": "get-workspace-builds-by-workspace-id",
+                "summary": "Get workspace agent port shares",
+                "operationId": "get-workspace-agent-port-shares",
                 "parameters": [
                     {
                         "type": "string",
@@ -5285,42 +7391,13 @@ const docTemplate = `{
                         "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "description": "Since timestamp",
-                        "name": "since",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspacePortShares"
                         }
                     }
                 }
@@ -5338,10 +7415,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "PortSharing"
                 ],
-                "summary": "Create workspace build",
-                "operationId": "create-workspace-build",
+                "summary": "Upsert workspace agent port share",
+                "operationId": "upsert-workspace-agent-port-share",
                 "parameters": [
                     {
                         "type": "string",
@@ -5352,12 +7429,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create workspace build request",
+                        "description": "Upsert port sharing level request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                            "$ref": "#/definitions/codersdk.UpsertWorkspacePortShareRequest"
                         }
                     }
                 ],
@@ -5365,14 +7442,53 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.WorkspacePortShare"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PortSharing"
+                ],
+                "summary": "Delete workspace agent port share",
+                "operationId": "delete-workspace-agent-port-share",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delete port sharing level request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DeleteWorkspacePortShareRequest"
                         }
                     }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
                 }
             }
         },
-        "/workspaces/{workspace}/extend": {
-            "put": {
+        "/workspaces/{workspace}/ssh-certificate": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5387,8 +7503,8 @@ const docTemplate = `{
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Extend workspace deadline by ID",
-                "operationId": "extend-workspace-deadline-by-id",
+                "summary": "Issue SSH certificate for workspace",
+                "operationId": "issue-ssh-certificate-for-workspace",
                 "parameters": [
                     {
                         "type": "string",
@@ -5399,20 +7515,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Extend deadline update request",
+                        "description": "Public key to certify",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
+                            "$ref": "#/definitions/codersdk.PostWorkspaceSSHCertificateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.WorkspaceSSHCertificate"
                         }
                     }
                 }
@@ -5534,6 +7650,20 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.DebugLog": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "level": {
+                    "$ref": "#/definitions/codersdk.LogLevel"
+                },
+                "output": {
+                    "type": "string"
+                }
+            }
+        },
         "agentsdk.GitAuthResponse": {
             "type": "object",
             "properties": {
@@ -5604,12 +7734,26 @@ const docTemplate = `{
                 "motd_file": {
                     "type": "string"
                 },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
+                },
+                "session_recording": {
+                    "description": "SessionRecording enables recording of interactive sessions.",
+                    "type": "boolean"
+                },
                 "shutdown_script": {
                     "type": "string"
                 },
                 "shutdown_script_timeout": {
                     "type": "integer"
                 },
+                "ssh_ca_public_key": {
+                    "description": "SSHCAPublicKey is the key of the certificate authority that signs the\ncertificates for plain SSH access in the authorized_keys format. It's\nempty if the deployment doesn't issue certificates.",
+                    "type": "string"
+                },
                 "startup_script": {
                     "type": "string"
                 },
@@ -5618,6 +7762,22 @@ const docTemplate = `{
                 },
                 "vscode_port_proxy_uri": {
                     "type": "string"
+                },
+                "workspace_id": {
+                    "description": "WorkspaceID is the ID of the workspace of the agent.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "agentsdk.PatchDebugLogs": {
+            "type": "object",
+            "properties": {
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.DebugLog"
+                    }
                 }
             }
         },
@@ -5629,6 +7789,11 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/agentsdk.StartupLog"
                     }
+                },
+                "script_id": {
+                    "description": "ScriptID is the script that produced the logs, or the nil UUID for the\nlegacy startup script.",
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -5644,6 +7809,35 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostDevcontainerRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "directory": {
+                    "description": "Directory is the workspace folder inside of the dev container.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the name of the agent of the dev container.",
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.PostDevcontainerResponse": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "auth_token": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "agentsdk.PostLifecycleRequest": {
             "type": "object",
             "properties": {
@@ -5671,6 +7865,71 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostScriptStatusRequest": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentScriptStatus"
+                }
+            }
+        },
+        "agentsdk.PostSessionRecordingRequest": {
+            "type": "object",
+            "properties": {
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "reconnecting_pty_id": {
+                    "description": "ReconnectingPTYID is the ID of the reconnecting PTY the session was\nrecorded in, if any.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "recording": {
+                    "description": "Recording is the session in the asciicast v2 format.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "remote_addr": {
+                    "description": "RemoteAddr is the tailnet address of the connection that opened the\nsession. It's used to attribute the session to the user who connected.",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "truncated": {
+                    "description": "Truncated is true when output was left out because the recording\nreached its maximum size.",
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/agentsdk.SessionRecordingType"
+                }
+            }
+        },
+        "agentsdk.PostSessionRecordingResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is the ID of the file the recording is stored in.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "agentsdk.PostStartupRequest": {
             "type": "object",
             "properties": {
@@ -5682,6 +7941,50 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.ResourceUsage": {
+            "type": "object",
+            "properties": {
+                "cpu_limit_cores": {
+                    "type": "number"
+                },
+                "cpu_used_cores": {
+                    "description": "CPUUsedCores is the average number of cores used since the previous\nreport.",
+                    "type": "number"
+                },
+                "disk_total_bytes": {
+                    "type": "integer"
+                },
+                "disk_used_bytes": {
+                    "description": "DiskUsedBytes and DiskTotalBytes are for the filesystem of the agent\ndirectory.",
+                    "type": "integer"
+                },
+                "memory_limit_bytes": {
+                    "type": "integer"
+                },
+                "memory_used_bytes": {
+                    "description": "MemoryUsedBytes excludes the page cache that can be reclaimed.",
+                    "type": "integer"
+                },
+                "network_rx_bytes": {
+                    "description": "NetworkRxBytes and NetworkTxBytes are the totals for all network\ninterfaces except loopback.",
+                    "type": "integer"
+                },
+                "network_tx_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "agentsdk.SessionRecordingType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "reconnecting_pty"
+            ],
+            "x-enum-varnames": [
+                "SessionRecordingTypeSSH",
+                "SessionRecordingTypeReconnectingPTY"
+            ]
+        },
         "agentsdk.StartupLog": {
             "type": "object",
             "properties": {
@@ -5714,6 +8017,14 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "resources": {
+                    "description": "Resources is the resource usage of the workspace. It is nil if the\nagent can't collect resource usage on its platform.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/agentsdk.ResourceUsage"
+                        }
+                    ]
+                },
                 "rx_bytes": {
                     "description": "RxBytes is the number of received bytes.",
                     "type": "integer"
@@ -5945,10 +8256,90 @@ const docTemplate = `{
                 "ValueSourceDefault"
             ]
         },
+        "coderd.SCIMGroup": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMGroupMember"
+                    }
+                },
+                "meta": {
+                    "type": "object",
+                    "properties": {
+                        "resourceType": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMGroupMember": {
+            "type": "object",
+            "properties": {
+                "display": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "coderd.SCIMPatchOperation": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "remove",
+                        "replace"
+                    ]
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
+                }
+            }
+        },
+        "coderd.SCIMPatchRequest": {
+            "type": "object",
+            "properties": {
+                "Operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMPatchOperation"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "coderd.SCIMUser": {
             "type": "object",
             "properties": {
                 "active": {
+                    "description": "Active is nil when a request omits it, which leaves the user's status\nunchanged.",
                     "type": "boolean"
                 },
                 "emails": {
@@ -6110,6 +8501,37 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AgentTiming": {
+            "type": "object",
+            "properties": {
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "stage": {
+                    "enum": [
+                        "connect",
+                        "startup_script"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildTimingStage"
+                        }
+                    ]
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspace_agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_agent_name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AppHostResponse": {
             "type": "object",
             "properties": {
@@ -6160,7 +8582,8 @@ const docTemplate = `{
                 "stop",
                 "login",
                 "logout",
-                "register"
+                "register",
+                "connect"
             ],
             "x-enum-varnames": [
                 "AuditActionCreate",
@@ -6170,7 +8593,8 @@ const docTemplate = `{
                 "AuditActionStop",
                 "AuditActionLogin",
                 "AuditActionLogout",
-                "AuditActionRegister"
+                "AuditActionRegister",
+                "AuditActionConnect"
             ]
         },
         "codersdk.AuditDiff": {
@@ -6360,6 +8784,17 @@ const docTemplate = `{
                 "type": "boolean"
             }
         },
+        "codersdk.AutomaticUpdates": {
+            "type": "string",
+            "enum": [
+                "always",
+                "never"
+            ],
+            "x-enum-varnames": [
+                "AutomaticUpdatesAlways",
+                "AutomaticUpdatesNever"
+            ]
+        },
         "codersdk.BuildInfoResponse": {
             "type": "object",
             "properties": {
@@ -6385,14 +8820,52 @@ const docTemplate = `{
             "enum": [
                 "initiator",
                 "autostart",
-                "autostop"
+                "autostop",
+                "dormancy",
+                "autodelete",
+                "failedstop"
             ],
             "x-enum-varnames": [
                 "BuildReasonInitiator",
                 "BuildReasonAutostart",
-                "BuildReasonAutostop"
+                "BuildReasonAutostop",
+                "BuildReasonDormancy",
+                "BuildReasonAutodelete",
+                "BuildReasonFailedStop"
             ]
         },
+        "codersdk.CreateCustomRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
         "codersdk.CreateFirstUserRequest": {
             "type": "object",
             "required": [
@@ -6499,6 +8972,35 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateProvisionerKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.CreateProvisionerKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "Key is only returned once, it can't be retrieved later.",
+                    "type": "string"
+                },
+                "provisioner_key": {
+                    "$ref": "#/definitions/codersdk.ProvisionerKey"
+                }
+            }
+        },
         "codersdk.CreateTemplateRequest": {
             "type": "object",
             "required": [
@@ -6530,10 +9032,22 @@ const docTemplate = `{
                     "description": "DisplayName is the displayed name of the template.",
                     "type": "string"
                 },
+                "dormant_autodelete_ttl_ms": {
+                    "description": "DormantAutoDeleteTTLMillis allows optionally specifying the duration\na workspace may remain dormant before it is deleted.",
+                    "type": "integer"
+                },
+                "failure_ttl_ms": {
+                    "description": "FailureTTLMillis allows optionally specifying the duration after which\nworkspaces whose start build failed are stopped automatically.",
+                    "type": "integer"
+                },
                 "icon": {
                     "description": "Icon is a relative path or external URL that specifies\nan icon to be displayed in the dashboard.",
                     "type": "string"
                 },
+                "inactivity_ttl_ms": {
+                    "description": "InactivityTTLMillis allows optionally specifying the duration of\ninactivity after which workspaces created from this template are\nmarked dormant.",
+                    "type": "integer"
+                },
                 "max_ttl_ms": {
                     "description": "MaxTTLMillis allows optionally specifying the max lifetime for\nworkspaces created from this template.",
                     "type": "integer"
@@ -6548,6 +9062,14 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.CreateParameterRequest"
                     }
                 },
+                "restart_requirement": {
+                    "description": "RestartRequirement allows optionally specifying the restart requirement\nfor workspaces created from this template. This is an enterprise feature.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateRestartRequirement"
+                        }
+                    ]
+                },
                 "template_version_id": {
                     "description": "VersionID is an in-progress or completed job to use as an initial version\nof the template.\n\nThis is required on creation to enable a user-flow of validating a\ntemplate works. There is no reason the data-model cannot support empty\ntemplates, but it doesn't make sense for users.",
                     "type": "string",
@@ -6668,7 +9190,10 @@ const docTemplate = `{
                     "enum": [
                         "autostart",
                         "autostop",
-                        "initiator"
+                        "initiator",
+                        "dormancy",
+                        "autodelete",
+                        "failedstop"
                     ],
                     "allOf": [
                         {
@@ -6749,6 +9274,44 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "name",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret is used to sign deliveries. A random secret is generated if\nempty.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.CreateWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "Secret is only returned when the webhook is created.",
+                    "type": "string"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/codersdk.Webhook"
+                }
+            }
+        },
         "codersdk.CreateWorkspaceBuildRequest": {
             "type": "object",
             "required": [
@@ -6838,6 +9401,18 @@ const docTemplate = `{
                 "template_id"
             ],
             "properties": {
+                "automatic_updates": {
+                    "description": "AutomaticUpdates defaults to never.",
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                },
                 "autostart_schedule": {
                     "type": "string"
                 },
@@ -6866,6 +9441,51 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CustomRole": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the role. Organization roles are assigned to members as\n\"\u003cname\u003e:\u003corganization_id\u003e\".",
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_permissions": {
+                    "description": "OrganizationPermissions apply to every resource in the role's\norganization.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "description": "SitePermissions apply to every resource on the deployment.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_permissions": {
+                    "description": "UserPermissions apply to resources owned by the user with the role.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
         "codersdk.DAUEntry": {
             "type": "object",
             "properties": {
@@ -6948,6 +9568,21 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DeleteWorkspacePortShareRequest": {
+            "type": "object",
+            "required": [
+                "agent_name",
+                "port"
+            ],
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DeploymentConfig": {
             "type": "object",
             "properties": {
@@ -7152,9 +9787,15 @@ const docTemplate = `{
                 "update_check": {
                     "type": "boolean"
                 },
+                "user_quiet_hours_schedule": {
+                    "$ref": "#/definitions/codersdk.UserQuietHoursScheduleConfig"
+                },
                 "verbose": {
                     "type": "boolean"
                 },
+                "webhooks": {
+                    "$ref": "#/definitions/codersdk.WebhooksConfig"
+                },
                 "wgtunnel_host": {
                     "type": "string"
                 },
@@ -7360,20 +10001,63 @@ const docTemplate = `{
         "codersdk.Healthcheck": {
             "type": "object",
             "properties": {
+                "address": {
+                    "description": "Address specifies the host and port to connect to for TCP checks.",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Command specifies the script to run for command checks.",
+                    "type": "string"
+                },
+                "expected_exit_codes": {
+                    "description": "ExpectedExitCodes specifies the exit codes of Command that are\nconsidered healthy. Defaults to 0.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "interval": {
                     "description": "Interval specifies the seconds between each health check.",
                     "type": "integer"
                 },
+                "output_regex": {
+                    "description": "OutputRegex, if set, must match the output of Command for the app to\nbe considered healthy.",
+                    "type": "string"
+                },
                 "threshold": {
                     "description": "Threshold specifies the number of consecutive failed health checks before returning \"unhealthy\".",
                     "type": "integer"
                 },
+                "type": {
+                    "description": "Type specifies how the app health is checked. HTTP checks succeed\nwhen URL responds with a non-5xx status, TCP checks when a connection\nto Address can be established, and command checks when Command exits\nwith one of ExpectedExitCodes.",
+                    "enum": [
+                        "http",
+                        "tcp",
+                        "command"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppHealthcheckType"
+                        }
+                    ]
+                },
                 "url": {
                     "description": "URL specifies the endpoint to check for the app health.",
                     "type": "string"
                 }
             }
         },
+        "codersdk.InsightsReportInterval": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week"
+            ],
+            "x-enum-varnames": [
+                "InsightsReportIntervalDay",
+                "InsightsReportIntervalWeek"
+            ]
+        },
         "codersdk.JobErrorCode": {
             "type": "string",
             "enum": [
@@ -7667,6 +10351,18 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OrganizationQuota": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "description": "Budget is 0 if the organization doesn't have a budget.",
+                    "type": "integer"
+                },
+                "credits_consumed": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.Parameter": {
             "description": "Parameter represents a set value for the scope.",
             "type": "object",
@@ -7852,6 +10548,65 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PatchWorkspaceProxy": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "regenerate_token": {
+                    "description": "RegenerateToken replaces the token of the proxy. The proxy must be\nrestarted with the new token.",
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "wildcard_hostname": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.Permission": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is one of \"create\", \"read\", \"update\", \"delete\" or \"*\" for all\nactions.",
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "read",
+                        "update",
+                        "delete",
+                        "*"
+                    ]
+                },
+                "negate": {
+                    "description": "Negate revokes the permission instead of granting it.",
+                    "type": "boolean"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.RBACResource"
+                }
+            }
+        },
+        "codersdk.PostWorkspaceSSHCertificateRequest": {
+            "type": "object",
+            "required": [
+                "public_key"
+            ],
+            "properties": {
+                "public_key": {
+                    "description": "PublicKey is the key to certify in the authorized_keys format.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.PprofConfig": {
             "type": "object",
             "properties": {
@@ -7901,10 +10656,26 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "current_job": {
+                    "$ref": "#/definitions/codersdk.ProvisionerDaemonJob"
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
+                "key_id": {
+                    "description": "KeyID is the provisioner key the daemon authenticated with. It is\nunset for daemons authenticated with a user session.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_seen_at": {
+                    "format": "date-time",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sql.NullTime"
+                        }
+                    ]
+                },
                 "name": {
                     "type": "string"
                 },
@@ -7927,6 +10698,33 @@ const docTemplate = `{
                             "$ref": "#/definitions/sql.NullTime"
                         }
                     ]
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ProvisionerDaemonJob": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "running",
+                        "succeeded",
+                        "canceling",
+                        "canceled",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobStatus"
+                        }
+                    ]
                 }
             }
         },
@@ -8052,6 +10850,32 @@ const docTemplate = `{
                 "ProvisionerJobFailed"
             ]
         },
+        "codersdk.ProvisionerKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.ProvisionerLogLevel": {
             "type": "string",
             "enum": [
@@ -8070,6 +10894,62 @@ const docTemplate = `{
                 "ProvisionerStorageMethodFile"
             ]
         },
+        "codersdk.ProvisionerTiming": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "job_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "resource": {
+                    "description": "Resource is the address of the resource. It is empty for the timing of\nthe whole stage.",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source is the Terraform provider of the resource.",
+                    "type": "string"
+                },
+                "stage": {
+                    "enum": [
+                        "init",
+                        "plan",
+                        "graph",
+                        "apply"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildTimingStage"
+                        }
+                    ]
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.ProxyHealthStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "unregistered",
+                "unresponsive",
+                "unreachable"
+            ],
+            "x-enum-varnames": [
+                "ProxyHealthy",
+                "ProxyUnregistered",
+                "ProxyUnresponsive",
+                "ProxyUnreachable"
+            ]
+        },
         "codersdk.PutExtendWorkspaceRequest": {
             "type": "object",
             "required": [
@@ -8082,6 +10962,88 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.QuotaInsightsInterval": {
+            "type": "object",
+            "properties": {
+                "credits_spent": {
+                    "type": "number"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.QuotaSpend"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.QuotaSpend"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.QuotaSpend"
+                    }
+                }
+            }
+        },
+        "codersdk.QuotaInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "credits_spent": {
+                    "type": "number"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "interval": {
+                    "enum": [
+                        "day",
+                        "week"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.InsightsReportInterval"
+                        }
+                    ]
+                },
+                "intervals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.QuotaInsightsInterval"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.QuotaSpend": {
+            "type": "object",
+            "properties": {
+                "credits_spent": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.RBACResource": {
             "type": "string",
             "enum": [
@@ -8106,6 +11068,8 @@ const docTemplate = `{
                 "deployment_stats",
                 "replicas",
                 "debug_info",
+                "webhook",
+                "custom_role",
                 "system"
             ],
             "x-enum-varnames": [
@@ -8130,6 +11094,8 @@ const docTemplate = `{
                 "ResourceDeploymentStats",
                 "ResourceReplicas",
                 "ResourceDebugInfo",
+                "ResourceWebhook",
+                "ResourceCustomRole",
                 "ResourceSystem"
             ]
         },
@@ -8190,7 +11156,10 @@ const docTemplate = `{
                 "git_ssh_key",
                 "api_key",
                 "group",
-                "license"
+                "license",
+                "webhook",
+                "custom_role",
+                "provisioner_key"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -8201,7 +11170,10 @@ const docTemplate = `{
                 "ResourceTypeGitSSHKey",
                 "ResourceTypeAPIKey",
                 "ResourceTypeGroup",
-                "ResourceTypeLicense"
+                "ResourceTypeLicense",
+                "ResourceTypeWebhook",
+                "ResourceTypeCustomRole",
+                "ResourceTypeProvisionerKey"
             ]
         },
         "codersdk.Response": {
@@ -8405,12 +11377,25 @@ const docTemplate = `{
                 "default_ttl_ms": {
                     "type": "integer"
                 },
+                "deprecated": {
+                    "description": "Deprecated templates can't be used to create new workspaces. The\nDeprecationMessage is displayed to users of existing workspaces.",
+                    "type": "boolean"
+                },
+                "deprecation_message": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
                 "display_name": {
                     "type": "string"
                 },
+                "dormant_autodelete_ttl_ms": {
+                    "type": "integer"
+                },
+                "failure_ttl_ms": {
+                    "type": "integer"
+                },
                 "icon": {
                     "type": "string"
                 },
@@ -8418,6 +11403,23 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "inactivity_ttl_ms": {
+                    "description": "InactivityTTLMillis, DormantAutoDeleteTTLMillis and FailureTTLMillis are\nenterprise-only. Their values are only used if your license is entitled\nto use the advanced template scheduling feature.",
+                    "type": "integer"
+                },
+                "max_port_share_level": {
+                    "description": "MaxPortShareLevel is the highest share level that workspace owners can\ngrant to ports of workspaces created from this template.",
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
+                },
                 "max_ttl_ms": {
                     "description": "MaxTTLMillis is an enterprise feature. It's value is only used if your\nlicense is entitled to use the advanced template scheduling feature.",
                     "type": "integer"
@@ -8435,6 +11437,26 @@ const docTemplate = `{
                         "terraform"
                     ]
                 },
+                "quota_cost_multiplier": {
+                    "description": "QuotaCostMultiplier multiplies the daily cost of workspaces created\nfrom the template before it's counted against quotas.",
+                    "type": "number"
+                },
+                "require_active_version": {
+                    "description": "RequireActiveVersion starts workspaces of the template on its active\nversion, regardless of their automatic updates setting.",
+                    "type": "boolean"
+                },
+                "restart_requirement": {
+                    "description": "RestartRequirement is an enterprise feature. Its value is only used if\nyour license is entitled to use the advanced template scheduling feature.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateRestartRequirement"
+                        }
+                    ]
+                },
+                "session_recording": {
+                    "description": "SessionRecording records interactive terminal sessions in workspaces\ncreated from the template and links them from the audit log.",
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "type": "string",
                     "format": "date-time"
@@ -8488,6 +11510,31 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateRestartRequirement": {
+            "type": "object",
+            "properties": {
+                "days_of_week": {
+                    "description": "DaysOfWeek is a list of days of the week on which restarts are required.\nRestarts happen within the user's quiet hours (in their configured\ntimezone). If no days are specified, restarts are not required. Weekdays\ncannot be specified twice.\n\nRestarts will only happen on weekdays in this list on weeks which line up\nwith Weeks.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "monday",
+                            "tuesday",
+                            "wednesday",
+                            "thursday",
+                            "friday",
+                            "saturday",
+                            "sunday"
+                        ]
+                    }
+                },
+                "weeks": {
+                    "description": "Weeks is the number of weeks between required restarts. Weeks are synced\nacross all workspaces (and Coder deployments) using modulo math on a\nhardcoded epoch week of January 2nd, 2023 (the first Monday of 2023).\nValues of 0 or 1 indicate weekly restarts. Values of 2 indicate\nfortnightly restarts, etc.",
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.TemplateRole": {
             "type": "string",
             "enum": [
@@ -8537,6 +11584,10 @@ const docTemplate = `{
                         "format": "uuid"
                     }
                 },
+                "quiet_hours_schedule": {
+                    "description": "QuietHoursSchedule is the user's custom quiet hours schedule. If empty,\nthe deployment default is used instead. Quiet hours are when workspaces\nare restarted to satisfy template restart requirements.",
+                    "type": "string"
+                },
                 "role": {
                     "enum": [
                         "admin",
@@ -8594,6 +11645,19 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "promoted_at": {
+                    "description": "PromotedAt is the last time the version was made the active version of\nits template.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "promoted_by": {
+                    "description": "PromotedBy is the user that last made the version the active version\nof its template.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    ]
+                },
                 "readme": {
                     "type": "string"
                 },
@@ -8817,6 +11881,42 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateCustomRoleRequest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateOrganizationQuotaRequest": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "description": "Budget limits the total daily cost of the workspaces of the\norganization. 0 removes the budget.",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "codersdk.UpdateRoles": {
             "type": "object",
             "properties": {
@@ -8870,6 +11970,54 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateUserQuietHoursScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "schedule": {
+                    "description": "Schedule is a cron expression that defines when the user's quiet hours\nwindow starts. Workspaces are restarted during quiet hours to satisfy\ntemplate restart requirements.\n\nThe schedule must be daily with a single time, and should have a timezone\nspecified via a CRON_TZ prefix (otherwise UTC will be used).\n\nIf the schedule is empty, the user will be updated to use the default\nschedule.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UpdateWorkspaceAutomaticUpdatesRequest": {
+            "type": "object",
+            "required": [
+                "automatic_updates"
+            ],
+            "properties": {
+                "automatic_updates": {
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceAutostartRequest": {
             "type": "object",
             "properties": {
@@ -8878,6 +12026,26 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspaceDormancy": {
+            "type": "object",
+            "properties": {
+                "dormant": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.UpdateWorkspaceProxyResponse": {
+            "type": "object",
+            "properties": {
+                "proxy": {
+                    "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                },
+                "proxy_token": {
+                    "description": "ProxyToken is only set if the token was regenerated.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceRequest": {
             "type": "object",
             "properties": {
@@ -8903,6 +12071,45 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpsertWorkspacePortShareRequest": {
+            "type": "object",
+            "required": [
+                "agent_name",
+                "port",
+                "share_level"
+            ],
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "enum": [
+                        "http",
+                        "https"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspacePortShareProtocol"
+                        }
+                    ]
+                },
+                "share_level": {
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.User": {
             "type": "object",
             "required": [
@@ -8939,6 +12146,10 @@ const docTemplate = `{
                         "format": "uuid"
                     }
                 },
+                "quiet_hours_schedule": {
+                    "description": "QuietHoursSchedule is the user's custom quiet hours schedule. If empty,\nthe deployment default is used instead. Quiet hours are when workspaces\nare restarted to satisfy template restart requirements.",
+                    "type": "string"
+                },
                 "roles": {
                     "type": "array",
                     "items": {
@@ -8961,6 +12172,39 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UserQuietHoursScheduleConfig": {
+            "type": "object",
+            "properties": {
+                "default_schedule": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UserQuietHoursScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "description": "Next is the next time that the quiet hours window will start.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "raw_schedule": {
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time is the time of day that the quiet hours window starts in the given\nTimezone each day.",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "raw format from the cron expression, UTC if unspecified",
+                    "type": "string"
+                },
+                "user_set": {
+                    "description": "UserSet is true if the user has set their own quiet hours schedule. If\nfalse, the user is using the default schedule.",
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.UserStatus": {
             "type": "string",
             "enum": [
@@ -9009,9 +12253,134 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "event": {
+                    "$ref": "#/definitions/codersdk.WebhookEvent"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt is nil once the delivery succeeded or ran out of\nattempts.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "webhook_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WebhookEvent": {
+            "type": "string",
+            "enum": [
+                "workspace_build_started",
+                "workspace_build_succeeded",
+                "workspace_build_failed",
+                "template_version_promoted",
+                "user_created",
+                "user_suspended"
+            ],
+            "x-enum-varnames": [
+                "WebhookEventWorkspaceBuildStarted",
+                "WebhookEventWorkspaceBuildSucceeded",
+                "WebhookEventWorkspaceBuildFailed",
+                "WebhookEventTemplateVersionPromoted",
+                "WebhookEventUserCreated",
+                "WebhookEventUserSuspended"
+            ]
+        },
+        "codersdk.WebhooksConfig": {
+            "type": "object",
+            "properties": {
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "retry_backoff": {
+                    "type": "integer"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
+                "automatic_updates": {
+                    "description": "AutomaticUpdates controls whether the workspace is started on the\nactive version of its template.",
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                },
                 "autostart_schedule": {
                     "type": "string"
                 },
@@ -9019,6 +12388,16 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "deleting_at": {
+                    "description": "DeletingAt indicates the time at which the dormant workspace will be\ndeleted if it is not made active again. It is only set if the template\nhas a dormant autodelete TTL.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "dormant_at": {
+                    "description": "DormantAt being non-nil indicates that the workspace was marked dormant\nafter a period of inactivity. Dormant workspaces cannot be started until\nthey are made active again.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
@@ -9050,6 +12429,10 @@ const docTemplate = `{
                 "template_allow_user_cancel_workspace_jobs": {
                     "type": "boolean"
                 },
+                "template_deprecation_message": {
+                    "description": "TemplateDeprecationMessage is set if the workspace's template has been\ndeprecated. New workspaces can't be created from deprecated templates.",
+                    "type": "string"
+                },
                 "template_display_name": {
                     "type": "string"
                 },
@@ -9063,6 +12446,10 @@ const docTemplate = `{
                 "template_name": {
                     "type": "string"
                 },
+                "template_require_active_version": {
+                    "description": "TemplateRequireActiveVersion is set if the workspace's template\nrequires workspaces to be started on its active version.",
+                    "type": "boolean"
+                },
                 "ttl_ms": {
                     "type": "integer"
                 },
@@ -9142,10 +12529,21 @@ const docTemplate = `{
                 "operating_system": {
                     "type": "string"
                 },
+                "parent_id": {
+                    "description": "ParentID is the agent that runs the dev container of this agent. It's\nempty for agents of the template.",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "resource_id": {
                     "type": "string",
                     "format": "uuid"
                 },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
+                },
                 "shutdown_script": {
                     "type": "string"
                 },
@@ -9188,6 +12586,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentDebugLog": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "$ref": "#/definitions/codersdk.LogLevel"
+                },
+                "output": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentLifecycle": {
             "type": "string",
             "enum": [
@@ -9261,6 +12677,117 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentReconnectingPTY": {
+            "type": "object",
+            "properties": {
+                "active_connections": {
+                    "type": "integer"
+                },
+                "backend": {
+                    "description": "Backend is \"buffered\" for sessions kept in the memory of the agent, or\n\"tmux\" or \"screen\" for sessions that keep running when the agent\nrestarts.",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Command is empty for sessions that run the shell of the user.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "description": "Name is empty for sessions of the web terminal.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentReconnectingPTYsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentReconnectingPTY"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentScript": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "cron": {
+                    "description": "Cron is a cron expression on which the script runs in addition to\nRunOnStart and RunOnStop.",
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "log_path": {
+                    "description": "LogPath is the path of the log file in the workspace, relative to the\nagent log directory.",
+                    "type": "string"
+                },
+                "run_on_start": {
+                    "type": "boolean"
+                },
+                "run_on_stop": {
+                    "type": "boolean"
+                },
+                "run_order": {
+                    "description": "RunOrder orders scripts that run on start. A script waits for the\nStartBlocksLogin scripts with a lower order to finish first.",
+                    "type": "integer"
+                },
+                "script": {
+                    "type": "string"
+                },
+                "start_blocks_login": {
+                    "description": "StartBlocksLogin scripts must finish before the agent is ready.",
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "description": "Status, ExitCode, StartedAt and CompletedAt describe the latest run of\nthe script.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentScriptStatus"
+                        }
+                    ]
+                },
+                "timeout_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentScriptStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "ok",
+                "error",
+                "timed_out"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentScriptStatusPending",
+                "WorkspaceAgentScriptStatusRunning",
+                "WorkspaceAgentScriptStatusOK",
+                "WorkspaceAgentScriptStatusError",
+                "WorkspaceAgentScriptStatusTimedOut"
+            ]
+        },
         "codersdk.WorkspaceAgentStartupLog": {
             "type": "object",
             "properties": {
@@ -9276,6 +12803,11 @@ const docTemplate = `{
                 },
                 "output": {
                     "type": "string"
+                },
+                "script_id": {
+                    "description": "ScriptID is the script that produced the log. It is the nil UUID for\nthe legacy startup script.",
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -9368,6 +12900,19 @@ const docTemplate = `{
                 "WorkspaceAppHealthUnhealthy"
             ]
         },
+        "codersdk.WorkspaceAppHealthcheckType": {
+            "type": "string",
+            "enum": [
+                "http",
+                "tcp",
+                "command"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAppHealthcheckTypeHTTP",
+                "WorkspaceAppHealthcheckTypeTCP",
+                "WorkspaceAppHealthcheckTypeCommand"
+            ]
+        },
         "codersdk.WorkspaceAppSharingLevel": {
             "type": "string",
             "enum": [
@@ -9420,7 +12965,10 @@ const docTemplate = `{
                     "enum": [
                         "initiator",
                         "autostart",
-                        "autostop"
+                        "autostop",
+                        "dormancy",
+                        "autodelete",
+                        "failedstop"
                     ],
                     "allOf": [
                         {
@@ -9503,6 +13051,42 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceBuildTimingStage": {
+            "type": "string",
+            "enum": [
+                "init",
+                "plan",
+                "graph",
+                "apply",
+                "connect",
+                "startup_script"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceBuildTimingStageInit",
+                "WorkspaceBuildTimingStagePlan",
+                "WorkspaceBuildTimingStageGraph",
+                "WorkspaceBuildTimingStageApply",
+                "WorkspaceBuildTimingStageConnect",
+                "WorkspaceBuildTimingStageStartupScript"
+            ]
+        },
+        "codersdk.WorkspaceBuildTimings": {
+            "type": "object",
+            "properties": {
+                "agent_timings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AgentTiming"
+                    }
+                },
+                "provisioner_timings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ProvisionerTiming"
+                    }
+                }
+            }
+        },
         "codersdk.WorkspaceConnectionLatencyMS": {
             "type": "object",
             "properties": {
@@ -9543,6 +13127,68 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspacePortShare": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "Protocol is the scheme used to proxy requests to the port.",
+                    "enum": [
+                        "http",
+                        "https"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspacePortShareProtocol"
+                        }
+                    ]
+                },
+                "share_level": {
+                    "description": "ShareLevel is capped by the template's max port sharing level when the\nport is accessed.",
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspacePortShareProtocol": {
+            "type": "string",
+            "enum": [
+                "http",
+                "https"
+            ],
+            "x-enum-varnames": [
+                "WorkspacePortShareProtocolHTTP",
+                "WorkspacePortShareProtocolHTTPS"
+            ]
+        },
+        "codersdk.WorkspacePortShares": {
+            "type": "object",
+            "properties": {
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspacePortShare"
+                    }
+                }
+            }
+        },
         "codersdk.WorkspaceProxy": {
             "type": "object",
             "properties": {
@@ -9553,6 +13199,9 @@ const docTemplate = `{
                 "deleted": {
                     "type": "boolean"
                 },
+                "display_name": {
+                    "type": "string"
+                },
                 "icon": {
                     "type": "string"
                 },
@@ -9563,6 +13212,9 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "status": {
+                    "$ref": "#/definitions/codersdk.WorkspaceProxyStatus"
+                },
                 "updated_at": {
                     "type": "string",
                     "format": "date-time"
@@ -9571,12 +13223,48 @@ const docTemplate = `{
                     "description": "Full url including scheme of the proxy api url: https://us.example.com",
                     "type": "string"
                 },
+                "version": {
+                    "description": "Version is the version of the proxy, as reported by the proxy when it\nregisters with coderd. It's only returned to users who can update the\nproxy.",
+                    "type": "string"
+                },
                 "wildcard_hostname": {
                     "description": "WildcardHostname with the wildcard for subdomain based app hosting: *.us.example.com",
                     "type": "string"
                 }
             }
         },
+        "codersdk.WorkspaceProxyStatus": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "description": "CheckedAt is the last time coderd checked that the proxy is reachable.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "description": "Error explains why the proxy isn't healthy. It's only returned to\nusers who can update the proxy.",
+                    "type": "string"
+                },
+                "last_heartbeat_at": {
+                    "description": "LastHeartbeatAt is the last time the proxy registered with coderd.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "enum": [
+                        "ok",
+                        "unregistered",
+                        "unresponsive",
+                        "unreachable"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProxyHealthStatus"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.WorkspaceQuota": {
             "type": "object",
             "properties": {
@@ -9658,6 +13346,19 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceSSHCertificate": {
+            "type": "object",
+            "properties": {
+                "certificate": {
+                    "description": "Certificate is in the authorized_keys format, so it can be written to\na file and passed to ssh with -o CertificateFile.",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.WorkspaceStatus": {
             "type": "string",
             "enum": [
@@ -9875,6 +13576,27 @@ const docTemplate = `{
                 "time": {
                     "description": "Time is the time the report was generated at.",
                     "type": "string"
+                },
+                "workspace_proxy": {
+                    "$ref": "#/definitions/healthcheck.WorkspaceProxyReport"
+                }
+            }
+        },
+        "healthcheck.WorkspaceProxyReport": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "unhealthy": {
+                    "description": "Unhealthy are the workspace proxies that aren't healthy. Users can't\nconnect to workspaces through them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                    }
                 }
             }
         },
@@ -10197,6 +13919,24 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "wsproxysdk.RegisterWorkspaceProxyRequest": {
+            "type": "object",
+            "properties": {
+                "version": {
+                    "description": "Version is the version of the proxy.",
+                    "type": "string"
+                }
+            }
+        },
+        "wsproxysdk.RegisterWorkspaceProxyResponse": {
+            "type": "object",
+            "properties": {
+                "heartbeat_interval": {
+                    "description": "HeartbeatInterval is how often the proxy must register again. The proxy\nis unhealthy if it stops registering.",
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
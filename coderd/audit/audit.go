@@ -17,6 +17,14 @@ type AdditionalFields struct {
 	BuildNumber    string               `json:"build_number"`
 	BuildReason    database.BuildReason `json:"build_reason"`
 	WorkspaceOwner string               `json:"workspace_owner"`
+
+	// The following are only set for connect actions.
+	AgentName          string `json:"agent_name,omitempty"`
+	SessionType        string `json:"session_type,omitempty"`
+	SessionRecordingID string `json:"session_recording_id,omitempty"`
+	// SessionRecordingTruncated is true when the end of the session is
+	// missing from the recording.
+	SessionRecordingTruncated bool `json:"session_recording_truncated,omitempty"`
 }
 
 func NewNop() Auditor {
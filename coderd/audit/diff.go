@@ -17,7 +17,10 @@ type Auditable interface {
 		database.WorkspaceBuild |
 		database.AuditableGroup |
 		database.License |
-		database.WorkspaceProxy
+		database.WorkspaceProxy |
+		database.Webhook |
+		database.CustomRole |
+		database.ProvisionerKey
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
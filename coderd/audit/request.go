@@ -84,6 +84,12 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return strconv.Itoa(int(typed.ID))
 	case database.WorkspaceProxy:
 		return typed.Name
+	case database.Webhook:
+		return typed.Name
+	case database.CustomRole:
+		return typed.Name
+	case database.ProvisionerKey:
+		return typed.Name
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -111,6 +117,12 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.UUID
 	case database.WorkspaceProxy:
 		return typed.ID
+	case database.Webhook:
+		return typed.ID
+	case database.CustomRole:
+		return typed.ID
+	case database.ProvisionerKey:
+		return typed.ID
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -138,6 +150,12 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeLicense
 	case database.WorkspaceProxy:
 		return database.ResourceTypeWorkspaceProxy
+	case database.Webhook:
+		return database.ResourceTypeWebhook
+	case database.CustomRole:
+		return database.ResourceTypeCustomRole
+	case database.ProvisionerKey:
+		return database.ResourceTypeProvisionerKey
 	default:
 		panic(fmt.Sprintf("unknown resource %T", typed))
 	}
@@ -2,7 +2,10 @@ package executor
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sync/atomic"
 	"time"
 
@@ -13,11 +16,13 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/parameter"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/schedule"
 )
 
-// Executor automatically starts or stops workspaces.
+// Executor automatically starts, stops or deletes workspaces, and marks
+// inactive workspaces dormant.
 type Executor struct {
 	ctx                   context.Context
 	db                    database.Store
@@ -105,7 +110,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	// NOTE: If a workspace build is created with a given TTL and then the user either
 	//       changes or unsets the TTL, the deadline for the workspace build will not
 	//       have changed. This behavior is as expected per #2229.
-	workspaces, err := e.db.GetWorkspacesEligibleForAutoStartStop(e.ctx, t)
+	workspaces, err := e.db.GetWorkspacesEligibleForTransition(e.ctx, t)
 	if err != nil {
 		e.log.Error(e.ctx, "get workspaces for autostart or autostop", slog.Error(err))
 		return stats
@@ -144,35 +149,75 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					return nil
 				}
 
-				if !isEligibleForAutoStartStop(ws, priorHistory, templateSchedule) {
-					return nil
-				}
-
 				priorJob, err := db.GetProvisionerJobByID(e.ctx, priorHistory.JobID)
 				if err != nil {
 					log.Warn(e.ctx, "get last provisioner job for workspace %q: %w", slog.Error(err))
 					return nil
 				}
 
-				validTransition, nextTransition, err := getNextTransition(ws, priorHistory, priorJob)
-				if err != nil {
-					log.Debug(e.ctx, "skipping workspace", slog.Error(err))
-					return nil
-				}
-
-				if currentTick.Before(nextTransition) {
-					log.Debug(e.ctx, "skipping workspace: too early",
-						slog.F("next_transition_at", nextTransition),
-						slog.F("transition", validTransition),
-						slog.F("current_tick", currentTick),
+				var (
+					validTransition database.WorkspaceTransition
+					reason          database.BuildReason
+				)
+				switch {
+				case isEligibleForDormancy(ws, priorJob, templateSchedule, t):
+					ws, err = db.UpdateWorkspaceDormantAt(e.ctx, database.UpdateWorkspaceDormantAtParams{
+						ID:        ws.ID,
+						DormantAt: sql.NullTime{Time: t, Valid: true},
+					})
+					if err != nil {
+						log.Error(e.ctx, "unable to mark workspace dormant", slog.Error(err))
+						return nil
+					}
+					log.Info(e.ctx, "marked workspace dormant",
+						slog.F("last_used_at", ws.LastUsedAt),
+						slog.F("inactivity_ttl", templateSchedule.InactivityTTL),
 					)
+					// Dormant workspaces must not keep running.
+					if priorHistory.Transition != database.WorkspaceTransitionStart {
+						return nil
+					}
+					validTransition, reason = database.WorkspaceTransitionStop, database.BuildReasonDormancy
+
+				case isEligibleForAutoDelete(ws, priorHistory, priorJob, templateSchedule, t):
+					validTransition, reason = database.WorkspaceTransitionDelete, database.BuildReasonAutodelete
+
+				case isEligibleForFailedStop(priorHistory, priorJob, templateSchedule, t):
+					validTransition, reason = database.WorkspaceTransitionStop, database.BuildReasonFailedstop
+
+				case isEligibleForAutoStartStop(ws, priorHistory, templateSchedule):
+					var nextTransition time.Time
+					validTransition, nextTransition, err = getNextTransition(ws, priorHistory, priorJob)
+					if err != nil {
+						log.Debug(e.ctx, "skipping workspace", slog.Error(err))
+						return nil
+					}
+
+					if currentTick.Before(nextTransition) {
+						log.Debug(e.ctx, "skipping workspace: too early",
+							slog.F("next_transition_at", nextTransition),
+							slog.F("transition", validTransition),
+							slog.F("current_tick", currentTick),
+						)
+						return nil
+					}
+
+					reason = database.BuildReasonAutostart
+					if validTransition == database.WorkspaceTransitionStop {
+						reason = database.BuildReasonAutostop
+					}
+
+				default:
 					return nil
 				}
 
-				log.Info(e.ctx, "scheduling workspace transition", slog.F("transition", validTransition))
+				log.Info(e.ctx, "scheduling workspace transition",
+					slog.F("transition", validTransition),
+					slog.F("reason", reason),
+				)
 
 				stats.Transitions[ws.ID] = validTransition
-				if err := build(e.ctx, db, ws, validTransition, priorHistory, priorJob); err != nil {
+				if err := build(e.ctx, db, ws, validTransition, reason, priorHistory, priorJob); err != nil {
 					log.Error(e.ctx, "unable to transition workspace",
 						slog.F("transition", validTransition),
 						slog.Error(err),
@@ -202,7 +247,9 @@ func isEligibleForAutoStartStop(ws database.Workspace, priorHistory database.Wor
 	if ws.Deleted {
 		return false
 	}
-	if templateSchedule.UserAutostartEnabled && ws.AutostartSchedule.Valid && ws.AutostartSchedule.String != "" {
+	// Dormant workspaces must be explicitly made active before they can be
+	// started again.
+	if !ws.DormantAt.Valid && templateSchedule.UserAutostartEnabled && ws.AutostartSchedule.Valid && ws.AutostartSchedule.String != "" {
 		return true
 	}
 	// Don't check the template schedule to see whether it allows autostop, this
@@ -214,6 +261,44 @@ func isEligibleForAutoStartStop(ws database.Workspace, priorHistory database.Wor
 	return false
 }
 
+// isEligibleForDormancy returns true if the workspace has not been used for
+// longer than the template's inactivity TTL and should be marked dormant.
+func isEligibleForDormancy(ws database.Workspace, priorJob database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, now time.Time) bool {
+	if ws.Deleted || ws.DormantAt.Valid || templateSchedule.InactivityTTL <= 0 {
+		return false
+	}
+	// Wait for any in-progress build to finish so the workspace can be
+	// stopped once it is marked dormant.
+	if !priorJob.CompletedAt.Valid {
+		return false
+	}
+	return ws.LastUsedAt.Add(templateSchedule.InactivityTTL).Before(now)
+}
+
+// isEligibleForAutoDelete returns true if the workspace has been dormant for
+// longer than the template's dormant autodelete TTL.
+func isEligibleForAutoDelete(ws database.Workspace, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, now time.Time) bool {
+	if ws.Deleted || !ws.DormantAt.Valid || templateSchedule.DormantAutoDeleteTTL <= 0 {
+		return false
+	}
+	if priorHistory.Transition == database.WorkspaceTransitionDelete || !priorJob.CompletedAt.Valid {
+		return false
+	}
+	return ws.DormantAt.Time.Add(templateSchedule.DormantAutoDeleteTTL).Before(now)
+}
+
+// isEligibleForFailedStop returns true if the workspace's latest start build
+// failed longer than the template's failure TTL ago.
+func isEligibleForFailedStop(priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, now time.Time) bool {
+	if templateSchedule.FailureTTL <= 0 || priorHistory.Transition != database.WorkspaceTransitionStart {
+		return false
+	}
+	if !priorJob.CompletedAt.Valid || priorJob.Error.String == "" {
+		return false
+	}
+	return priorJob.CompletedAt.Time.Add(templateSchedule.FailureTTL).Before(now)
+}
+
 func getNextTransition(
 	ws database.Workspace,
 	priorHistory database.WorkspaceBuild,
@@ -251,7 +336,7 @@ func getNextTransition(
 
 // TODO(cian): this function duplicates most of api.postWorkspaceBuilds. Refactor.
 // See: https://github.com/coder/coder/issues/1401
-func build(ctx context.Context, store database.Store, workspace database.Workspace, trans database.WorkspaceTransition, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob) error {
+func build(ctx context.Context, store database.Store, workspace database.Workspace, trans database.WorkspaceTransition, buildReason database.BuildReason, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob) error {
 	template, err := store.GetTemplateByID(ctx, workspace.TemplateID)
 	if err != nil {
 		return xerrors.Errorf("get workspace template: %w", err)
@@ -271,20 +356,64 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 	provisionerJobID := uuid.New()
 	now := database.Now()
 
-	var buildReason database.BuildReason
-	switch trans {
-	case database.WorkspaceTransitionStart:
-		buildReason = database.BuildReasonAutostart
-	case database.WorkspaceTransitionStop:
-		buildReason = database.BuildReasonAutostop
-	default:
-		return xerrors.Errorf("Unsupported transition: %q", trans)
-	}
-
 	lastBuildParameters, err := store.GetWorkspaceBuildParameters(ctx, priorHistory.ID)
 	if err != nil {
 		return xerrors.Errorf("fetch prior workspace build parameters: %w", err)
 	}
+	names := make([]string, 0, len(lastBuildParameters))
+	values := make([]string, 0, len(lastBuildParameters))
+	for _, param := range lastBuildParameters {
+		names = append(names, param.Name)
+		values = append(values, param.Value)
+	}
+
+	// Workspaces are rebuilt on the version of their latest build, unless they
+	// update automatically and are being started. If the parameters of the
+	// workspace are invalid for the active version, the workspace is started
+	// on its current version and the reason is recorded in the build logs.
+	var (
+		templateVersionID  = priorHistory.TemplateVersionID
+		storageMethod      = priorJob.StorageMethod
+		fileID             = priorJob.FileID
+		tags               = priorJob.Tags
+		automaticUpdateErr error
+	)
+	if trans == database.WorkspaceTransitionStart &&
+		(workspace.AutomaticUpdates == database.AutomaticUpdatesAlways || template.RequireActiveVersion) &&
+		priorHistory.TemplateVersionID != template.ActiveVersionID {
+		parameters, err := parameter.WorkspaceBuildParameters(ctx, store, parameter.WorkspaceBuildScope{
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: template.ActiveVersionID,
+			PriorBuildID:      priorHistory.ID,
+		}, nil)
+		var validationErr *parameter.ValidationError
+		switch {
+		case err == nil:
+			activeVersion, err := store.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+			if err != nil {
+				return xerrors.Errorf("get active template version: %w", err)
+			}
+			activeJob, err := store.GetProvisionerJobByID(ctx, activeVersion.JobID)
+			if err != nil {
+				return xerrors.Errorf("get active template version job: %w", err)
+			}
+			templateVersionID = activeVersion.ID
+			storageMethod = activeJob.StorageMethod
+			fileID = activeJob.FileID
+			tags = provisionerdserver.MutateTags(workspace.OwnerID, activeJob.Tags)
+
+			names = make([]string, 0, len(parameters))
+			values = make([]string, 0, len(parameters))
+			for _, param := range parameters {
+				names = append(names, param.Name)
+				values = append(values, param.Value)
+			}
+		case errors.As(err, &validationErr):
+			automaticUpdateErr = validationErr
+		default:
+			return xerrors.Errorf("resolve parameters for active template version: %w", err)
+		}
+	}
 
 	return store.InTx(func(db database.Store) error {
 		newProvisionerJob, err := store.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
@@ -295,20 +424,33 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 			OrganizationID: template.OrganizationID,
 			Provisioner:    template.Provisioner,
 			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			StorageMethod:  priorJob.StorageMethod,
-			FileID:         priorJob.FileID,
-			Tags:           priorJob.Tags,
+			StorageMethod:  storageMethod,
+			FileID:         fileID,
+			Tags:           tags,
 			Input:          input,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
 		}
+		if automaticUpdateErr != nil {
+			_, err = db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+				JobID:     newProvisionerJob.ID,
+				CreatedAt: []time.Time{now},
+				Source:    []database.LogSource{database.LogSourceProvisionerDaemon},
+				Level:     []database.LogLevel{database.LogLevelWarn},
+				Stage:     []string{"Updating workspace"},
+				Output:    []string{fmt.Sprintf("The workspace could not be updated to the active template version, so its current version is used: %s", automaticUpdateErr)},
+			})
+			if err != nil {
+				return xerrors.Errorf("insert automatic update log: %w", err)
+			}
+		}
 		workspaceBuild, err := store.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
 			ID:                workspaceBuildID,
 			CreatedAt:         now,
 			UpdatedAt:         now,
 			WorkspaceID:       workspace.ID,
-			TemplateVersionID: priorHistory.TemplateVersionID,
+			TemplateVersionID: templateVersionID,
 			BuildNumber:       priorBuildNumber + 1,
 			ProvisionerState:  priorHistory.ProvisionerState,
 			InitiatorID:       workspace.OwnerID,
@@ -320,12 +462,6 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 			return xerrors.Errorf("insert workspace build: %w", err)
 		}
 
-		names := make([]string, 0, len(lastBuildParameters))
-		values := make([]string, 0, len(lastBuildParameters))
-		for _, param := range lastBuildParameters {
-			names = append(names, param.Name)
-			values = append(values, param.Value)
-		}
 		err = db.InsertWorkspaceBuildParameters(ctx, database.InsertWorkspaceBuildParametersParams{
 			WorkspaceBuildID: workspaceBuild.ID,
 			Name:             names,
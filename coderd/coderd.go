@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,6 +26,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.opentelemetry.io/otel/trace"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 	"google.golang.org/api/idtoken"
 	"storj.io/drpc/drpcmux"
@@ -58,6 +60,7 @@ import (
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/coderd/updatecheck"
 	"github.com/coder/coder/coderd/util/slice"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/coderd/wsconncache"
 	"github.com/coder/coder/codersdk"
@@ -123,9 +126,16 @@ type Options struct {
 	SwaggerEndpoint       bool
 	SetUserGroups         func(ctx context.Context, tx database.Store, userID uuid.UUID, groupNames []string) error
 	TemplateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
+	// UserQuietHoursScheduleStore is used to look up the quiet hours schedule
+	// of workspace owners when calculating workspace deadlines.
+	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	// AppSecurityKey is the crypto key used to sign and encrypt tokens related to
 	// workspace applications. It consists of both a signing and encryption key.
-	AppSecurityKey     workspaceapps.SecurityKey
+	AppSecurityKey workspaceapps.SecurityKey
+	// SSHCAKey signs the short-lived certificates that users authenticate
+	// with when connecting to workspace agents over plain SSH. Certificates
+	// can't be issued if it's nil.
+	SSHCAKey           gossh.Signer
 	HealthcheckFunc    func(ctx context.Context) (*healthcheck.Report, error)
 	HealthcheckTimeout time.Duration
 	HealthcheckRefresh time.Duration
@@ -247,6 +257,13 @@ func New(options *Options) *API {
 		v := schedule.NewAGPLTemplateScheduleStore()
 		options.TemplateScheduleStore.Store(&v)
 	}
+	if options.UserQuietHoursScheduleStore == nil {
+		options.UserQuietHoursScheduleStore = &atomic.Pointer[schedule.UserQuietHoursScheduleStore]{}
+	}
+	if options.UserQuietHoursScheduleStore.Load() == nil {
+		v := schedule.NewAGPLUserQuietHoursScheduleStore()
+		options.UserQuietHoursScheduleStore.Store(&v)
+	}
 	if options.HealthcheckFunc == nil {
 		options.HealthcheckFunc = func(ctx context.Context) (*healthcheck.Report, error) {
 			return healthcheck.Run(ctx, &healthcheck.ReportOptions{
@@ -314,12 +331,25 @@ func New(options *Options) *API {
 			options.AgentInactiveDisconnectTimeout,
 			options.AppSecurityKey,
 		),
-		metricsCache:          metricsCache,
-		Auditor:               atomic.Pointer[audit.Auditor]{},
-		TemplateScheduleStore: options.TemplateScheduleStore,
-		Experiments:           experiments,
-		healthCheckGroup:      &singleflight.Group[string, *healthcheck.Report]{},
+		metricsCache:                metricsCache,
+		Auditor:                     atomic.Pointer[audit.Auditor]{},
+		TemplateScheduleStore:       options.TemplateScheduleStore,
+		UserQuietHoursScheduleStore: options.UserQuietHoursScheduleStore,
+		Experiments:                 experiments,
+		healthCheckGroup:            &singleflight.Group[string, *healthcheck.Report]{},
+		connectionActors:            newConnectionActors(),
 	}
+	api.webhookDispatcher = webhooks.New(
+		ctx,
+		options.Logger.Named("webhooks"),
+		options.Database,
+		options.Pubsub,
+		webhooks.Options{
+			MaxAttempts:  int(options.DeploymentValues.Webhooks.MaxAttempts.Value()),
+			Timeout:      options.DeploymentValues.Webhooks.Timeout.Value(),
+			RetryBackoff: options.DeploymentValues.Webhooks.RetryBackoff.Value(),
+		},
+	)
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
 			options.Database,
@@ -352,6 +382,8 @@ func New(options *Options) *API {
 
 		DisablePathApps:  options.DeploymentValues.DisablePathApps.Value(),
 		SecureAuthCookie: options.DeploymentValues.SecureAuthCookie.Value(),
+
+		ReconnectingPTYOpened: api.connectionActors.rememberPTY,
 	}
 
 	apiKeyMiddleware := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -511,6 +543,11 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/roles", func(r chi.Router) {
+					r.Get("/", api.customOrganizationRoles)
+					r.Post("/", api.postCustomOrganizationRole)
+					r.Patch("/{role}", api.patchCustomOrganizationRole)
+				})
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
@@ -544,6 +581,7 @@ func New(options *Options) *API {
 			r.Route("/versions", func(r chi.Router) {
 				r.Get("/", api.templateVersionsByTemplate)
 				r.Patch("/", api.patchActiveTemplateVersion)
+				r.Post("/rollback", api.postRollbackActiveTemplateVersion)
 				r.Get("/{templateversionname}", api.templateVersionByName)
 			})
 		})
@@ -661,6 +699,8 @@ func New(options *Options) *API {
 				r.Get("/metadata", api.workspaceAgentManifest)
 				r.Post("/startup", api.postWorkspaceAgentStartup)
 				r.Patch("/startup-logs", api.patchWorkspaceAgentStartupLogs)
+				r.Patch("/debug-logs", api.patchWorkspaceAgentDebugLogs)
+				r.Post("/session-recordings", api.postWorkspaceAgentSessionRecording)
 				r.Post("/app-health", api.postWorkspaceAppHealth)
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
 				r.Get("/gitsshkey", api.agentGitSSHKey)
@@ -668,6 +708,8 @@ func New(options *Options) *API {
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
+				r.Post("/scripts/{script}/status", api.workspaceAgentPostScriptStatus)
+				r.Post("/devcontainers", api.workspaceAgentPostDevcontainer)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -685,7 +727,10 @@ func New(options *Options) *API {
 				r.Get("/", api.workspaceAgent)
 				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadata)
 				r.Get("/startup-logs", api.workspaceAgentStartupLogs)
+				r.Get("/debug-logs", api.workspaceAgentDebugLogs)
+				r.Post("/debug-logs/flush", api.postWorkspaceAgentDebugLogsFlush)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/reconnecting-ptys", api.workspaceAgentReconnectingPTYs)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
@@ -715,6 +760,14 @@ func New(options *Options) *API {
 				})
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
+				r.Put("/dormant", api.putWorkspaceDormant)
+				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
+				r.Route("/port-share", func(r chi.Router) {
+					r.Get("/", api.workspacePortShares)
+					r.Post("/", api.postWorkspacePortShare)
+					r.Delete("/", api.deleteWorkspacePortShare)
+				})
+				r.Post("/ssh-certificate", api.postWorkspaceSSHCertificate)
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -729,6 +782,7 @@ func New(options *Options) *API {
 			r.Get("/parameters", api.workspaceBuildParameters)
 			r.Get("/resources", api.workspaceBuildResources)
 			r.Get("/state", api.workspaceBuildState)
+			r.Get("/timings", api.workspaceBuildTimings)
 		})
 		r.Route("/authcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -753,6 +807,24 @@ func New(options *Options) *API {
 			r.Use(apiKeyMiddleware)
 			r.Get("/daus", api.deploymentDAUs)
 		})
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.customSiteRoles)
+			r.Post("/", api.postCustomSiteRole)
+			r.Patch("/{role}", api.patchCustomSiteRole)
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Use(httpmw.ExtractWebhookParam(options.Database))
+				r.Get("/", api.webhook)
+				r.Patch("/", api.patchWebhook)
+				r.Delete("/", api.deleteWebhook)
+				r.Get("/deliveries", api.webhookDeliveries)
+			})
+		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -804,6 +876,7 @@ type API struct {
 	TailnetCoordinator                atomic.Pointer[tailnet.Coordinator]
 	QuotaCommitter                    atomic.Pointer[proto.QuotaCommitter]
 	TemplateScheduleStore             *atomic.Pointer[schedule.TemplateScheduleStore]
+	UserQuietHoursScheduleStore       *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 
 	HTTPAuth *HTTPAuthorizer
 
@@ -821,8 +894,10 @@ type API struct {
 	metricsCache          *metricscache.Cache
 	workspaceAgentCache   *wsconncache.Cache
 	updateChecker         *updatecheck.Checker
+	webhookDispatcher     *webhooks.Dispatcher
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
+	connectionActors      *connectionActors
 
 	// Experiments contains the list of experiments currently enabled.
 	// This is used to gate features that are not yet ready for production.
@@ -841,6 +916,7 @@ func (api *API) Close() error {
 	api.WebsocketWaitMutex.Unlock()
 
 	api.metricsCache.Close()
+	_ = api.webhookDispatcher.Close()
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
@@ -892,6 +968,8 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		Tags: dbtype.StringMap{
 			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
 		},
+		LastSeenAt: sql.NullTime{Time: database.Now(), Valid: true},
+		Version:    buildinfo.Version(),
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("insert provisioner daemon %q: %w", name, err)
@@ -905,20 +983,21 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 	mux := drpcmux.New()
 
 	err = proto.DRPCRegisterProvisionerDaemon(mux, &provisionerdserver.Server{
-		AccessURL:             api.AccessURL,
-		ID:                    daemon.ID,
-		OIDCConfig:            api.OIDCConfig,
-		Database:              api.Database,
-		Pubsub:                api.Pubsub,
-		Provisioners:          daemon.Provisioners,
-		GitAuthConfigs:        api.GitAuthConfigs,
-		Telemetry:             api.Telemetry,
-		Tags:                  tags,
-		QuotaCommitter:        &api.QuotaCommitter,
-		Auditor:               &api.Auditor,
-		TemplateScheduleStore: api.TemplateScheduleStore,
-		AcquireJobDebounce:    debounce,
-		Logger:                api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
+		AccessURL:                   api.AccessURL,
+		ID:                          daemon.ID,
+		OIDCConfig:                  api.OIDCConfig,
+		Database:                    api.Database,
+		Pubsub:                      api.Pubsub,
+		Provisioners:                daemon.Provisioners,
+		GitAuthConfigs:              api.GitAuthConfigs,
+		Telemetry:                   api.Telemetry,
+		Tags:                        tags,
+		QuotaCommitter:              &api.QuotaCommitter,
+		Auditor:                     &api.Auditor,
+		TemplateScheduleStore:       api.TemplateScheduleStore,
+		UserQuietHoursScheduleStore: api.UserQuietHoursScheduleStore,
+		AcquireJobDebounce:          debounce,
+		Logger:                      api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,122 @@
+package coderd
+
+import (
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// connectionActorTTL is how long the user behind a connection is remembered
+// after it was last seen. Recordings are uploaded when sessions end, which can
+// be long after the connection was established.
+const connectionActorTTL = 24 * time.Hour
+
+// connectionActors remembers which users opened connections to workspace
+// agents. Agents only know the tailnet address or reconnecting PTY ID of a
+// session, so this is used to attribute sessions to the user who connected.
+//
+// Only connections brokered by this replica are known.
+type connectionActors struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	addrs  map[connectionActorKey]connectionActor
+	ptys   map[connectionActorKey]connectionActor
+	pruned time.Time
+}
+
+type connectionActorKey struct {
+	agentID uuid.UUID
+	addr    netip.Addr
+	ptyID   uuid.UUID
+}
+
+type connectionActor struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+}
+
+func newConnectionActors() *connectionActors {
+	return &connectionActors{
+		now:   time.Now,
+		addrs: map[connectionActorKey]connectionActor{},
+		ptys:  map[connectionActorKey]connectionActor{},
+	}
+}
+
+// rememberAddrs records that userID is behind the tailnet addresses of a
+// client connected to the agent.
+func (c *connectionActors) rememberAddrs(agentID, userID uuid.UUID, addrs []netip.Prefix) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	for _, prefix := range addrs {
+		if !prefix.IsSingleIP() {
+			continue
+		}
+		c.remember(c.addrs, connectionActorKey{agentID: agentID, addr: prefix.Addr()}, userID)
+	}
+}
+
+// rememberPTY records that userID opened the reconnecting PTY on the agent.
+func (c *connectionActors) rememberPTY(agentID, userID, ptyID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	c.remember(c.ptys, connectionActorKey{agentID: agentID, ptyID: ptyID}, userID)
+}
+
+// remember stores the actor of key. Clients choose their own addresses and
+// PTY IDs, so an unexpired entry is never handed to another user. c.mu must
+// be held.
+func (c *connectionActors) remember(m map[connectionActorKey]connectionActor, key connectionActorKey, userID uuid.UUID) {
+	now := c.now()
+	if existing, ok := m[key]; ok && existing.userID != userID && now.Before(existing.expiresAt) {
+		return
+	}
+	m[key] = connectionActor{
+		userID:    userID,
+		expiresAt: now.Add(connectionActorTTL),
+	}
+}
+
+// resolve returns the user who opened a session on the agent. The
+// reconnecting PTY ID takes precedence, since the web terminal is reached
+// through a tailnet connection that coderd shares between users.
+func (c *connectionActors) resolve(agentID uuid.UUID, remoteAddr string, ptyID uuid.UUID) (uuid.UUID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if ptyID != uuid.Nil {
+		actor, ok := c.ptys[connectionActorKey{agentID: agentID, ptyID: ptyID}]
+		if ok && now.Before(actor.expiresAt) {
+			return actor.userID, true
+		}
+	}
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	actor, ok := c.addrs[connectionActorKey{agentID: agentID, addr: addrPort.Addr()}]
+	if !ok || !now.Before(actor.expiresAt) {
+		return uuid.Nil, false
+	}
+	return actor.userID, true
+}
+
+// prune removes expired entries at most once a minute. c.mu must be held.
+func (c *connectionActors) prune() {
+	now := c.now()
+	if now.Sub(c.pruned) < time.Minute {
+		return
+	}
+	c.pruned = now
+	for _, m := range []map[connectionActorKey]connectionActor{c.addrs, c.ptys} {
+		for key, actor := range m {
+			if !now.Before(actor.expiresAt) {
+				delete(m, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package coderd
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/tailnet"
+	"github.com/coder/coder/testutil"
+)
+
+func TestConnectionActors(t *testing.T) {
+	t.Parallel()
+
+	agentID := uuid.New()
+	alice := uuid.New()
+	bob := uuid.New()
+	addr := netip.MustParseAddr("fd7a:115c:a1e0::1")
+
+	t.Run("Address", func(t *testing.T) {
+		t.Parallel()
+		actors := newConnectionActors()
+		actors.rememberAddrs(agentID, alice, []netip.Prefix{
+			netip.PrefixFrom(addr, 128),
+			// Ranges aren't the address of a single client.
+			netip.MustParsePrefix("fd7a:115c:a1e0::/64"),
+		})
+
+		userID, ok := actors.resolve(agentID, netip.AddrPortFrom(addr, 52000).String(), uuid.Nil)
+		require.True(t, ok)
+		require.Equal(t, alice, userID)
+
+		_, ok = actors.resolve(agentID, "[fd7a:115c:a1e0::2]:52000", uuid.Nil)
+		require.False(t, ok)
+		_, ok = actors.resolve(uuid.New(), netip.AddrPortFrom(addr, 52000).String(), uuid.Nil)
+		require.False(t, ok)
+		_, ok = actors.resolve(agentID, "", uuid.Nil)
+		require.False(t, ok)
+	})
+
+	t.Run("PTYTakesPrecedence", func(t *testing.T) {
+		t.Parallel()
+		actors := newConnectionActors()
+		ptyID := uuid.New()
+		actors.rememberAddrs(agentID, alice, []netip.Prefix{netip.PrefixFrom(addr, 128)})
+		actors.rememberPTY(agentID, bob, ptyID)
+
+		userID, ok := actors.resolve(agentID, netip.AddrPortFrom(addr, 52000).String(), ptyID)
+		require.True(t, ok)
+		require.Equal(t, bob, userID)
+	})
+
+	t.Run("NoTakeover", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		actors := newConnectionActors()
+		actors.now = func() time.Time { return now }
+		ptyID := uuid.New()
+		actors.rememberPTY(agentID, alice, ptyID)
+		actors.rememberPTY(agentID, bob, ptyID)
+
+		userID, ok := actors.resolve(agentID, "", ptyID)
+		require.True(t, ok)
+		require.Equal(t, alice, userID)
+
+		// Once the entry expires the ID can be used by someone else.
+		now = now.Add(connectionActorTTL)
+		_, ok = actors.resolve(agentID, "", ptyID)
+		require.False(t, ok)
+		actors.rememberPTY(agentID, bob, ptyID)
+		userID, ok = actors.resolve(agentID, "", ptyID)
+		require.True(t, ok)
+		require.Equal(t, bob, userID)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		actors := newConnectionActors()
+		actors.now = func() time.Time { return now }
+		actors.rememberPTY(agentID, alice, uuid.New())
+		now = now.Add(connectionActorTTL)
+		actors.rememberPTY(agentID, bob, uuid.New())
+		require.Len(t, actors.ptys, 1)
+	})
+}
+
+func TestClientNodesConn(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	nodes := make(chan tailnet.Node, 1)
+	conn := newClientNodesConn(server, func(node tailnet.Node) {
+		nodes <- node
+	})
+	defer conn.Close()
+
+	node := tailnet.Node{
+		Addresses: []netip.Prefix{netip.MustParsePrefix("fd7a:115c:a1e0::1/128")},
+	}
+	go func() {
+		_ = json.NewEncoder(client).Encode(node)
+	}()
+
+	// The coordinator still reads what the client sent.
+	var read tailnet.Node
+	require.NoError(t, json.NewDecoder(io.LimitReader(conn, 1<<20)).Decode(&read))
+	require.Equal(t, node.Addresses, read.Addresses)
+
+	select {
+	case got := <-nodes:
+		require.Equal(t, node.Addresses, got.Addresses)
+	case <-time.After(testutil.WaitShort):
+		t.Fatal("timed out waiting for node")
+	}
+}
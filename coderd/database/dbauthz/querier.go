@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -192,6 +193,13 @@ func (q *querier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]dat
 	return q.db.GetGroupMembers(ctx, groupID)
 }
 
+func (q *querier) GetGroupMembershipsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]database.GetGroupMembershipsByUserIDsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceGroup.All()); err != nil {
+		return nil, err
+	}
+	return q.db.GetGroupMembershipsByUserIDs(ctx, userIDs)
+}
+
 func (q *querier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (database.Group, error) {
 	// This method creates a new group.
 	return insert(q.log, q.auth, rbac.ResourceGroup.InOrg(organizationID), q.db.InsertAllUsersGroup)(ctx, organizationID)
@@ -309,6 +317,15 @@ func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (data
 	return job, nil
 }
 
+func (q *querier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
+	// Authorized read on job lets the actor also read the timings.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobTimingsByJobID(ctx, jobID)
+}
+
 func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	// Authorized read on job lets the actor also read the logs.
 	_, err := q.GetProvisionerJobByID(ctx, arg.JobID)
@@ -326,6 +343,19 @@ func (q *querier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg dat
 	return q.db.GetWorkspaceAgentStartupLogsAfter(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentDebugLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentDebugLogsAfterParams) ([]database.WorkspaceAgentDebugLog, error) {
+	// Debug logs can contain details about the deployment, so reading them
+	// requires access to debug info in addition to the workspace.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceDebugInfo); err != nil {
+		return nil, err
+	}
+	_, err := q.GetWorkspaceAgentByID(ctx, arg.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentDebugLogsAfter(ctx, arg)
+}
+
 func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.License, error) {
 		return q.db.GetLicenses(ctx)
@@ -389,6 +419,16 @@ func (q *querier) UpsertAppSecurityKey(ctx context.Context, data string) error {
 	return q.db.UpsertAppSecurityKey(ctx, data)
 }
 
+func (q *querier) GetSSHCAKey(ctx context.Context) (string, error) {
+	// No authz checks
+	return q.db.GetSSHCAKey(ctx)
+}
+
+func (q *querier) UpsertSSHCAKey(ctx context.Context, data string) error {
+	// No authz checks as this is done during startup
+	return q.db.UpsertSSHCAKey(ctx, data)
+}
+
 func (q *querier) GetServiceBanner(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetServiceBanner(ctx)
@@ -401,6 +441,42 @@ func (q *querier) GetProvisionerDaemons(ctx context.Context) ([]database.Provisi
 	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
 }
 
+func (q *querier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
+}
+
+func (q *querier) GetRunningProvisionerJobsByWorkerIDs(ctx context.Context, workerIDs []uuid.UUID) ([]database.ProvisionerJob, error) {
+	// The jobs are only exposed as part of the provisioner daemons that
+	// run them.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceProvisionerDaemon); err != nil {
+		return nil, err
+	}
+	return q.db.GetRunningProvisionerJobsByWorkerIDs(ctx, workerIDs)
+}
+
+func (q *querier) InsertProvisionerKey(ctx context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	return insert(q.log, q.auth, rbac.ResourceProvisionerDaemon, q.db.InsertProvisionerKey)(ctx, arg)
+}
+
+func (q *querier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByID)(ctx, id)
+}
+
+func (q *querier) GetProvisionerKeyByName(ctx context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByName)(ctx, arg)
+}
+
+func (q *querier) GetProvisionerKeysByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetProvisionerKeysByOrganizationID)(ctx, organizationID)
+}
+
+func (q *querier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetProvisionerKeyByID, q.db.DeleteProvisionerKey)(ctx, id)
+}
+
 func (q *querier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.Group, error) {
 	return fetchWithPostFilter(q.auth, q.db.GetGroupsByOrganizationID)(ctx, organizationID)
 }
@@ -442,6 +518,13 @@ func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrg
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
 
+func (q *querier) UpdateOrganizationQuotaBudget(ctx context.Context, arg database.UpdateOrganizationQuotaBudgetParams) (database.Organization, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOrganizationQuotaBudgetParams) (database.Organization, error) {
+		return q.db.GetOrganizationByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOrganizationQuotaBudget)(ctx, arg)
+}
+
 func (q *querier) InsertOrganizationMember(ctx context.Context, arg database.InsertOrganizationMemberParams) (database.OrganizationMember, error) {
 	// All roles are added roles. Org member is always implied.
 	addedRoles := append(arg.Roles, rbac.RoleOrgMember(arg.OrganizationID))
@@ -489,6 +572,7 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	}
 
 	grantedRoles := append(added, removed...)
+	var customRoles []string
 	// Validate that the roles being assigned are valid.
 	for _, r := range grantedRoles {
 		_, isOrgRole := rbac.IsOrgRole(r)
@@ -501,7 +585,27 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 
 		// All roles should be valid roles
 		if _, err := rbac.RoleByName(r); err != nil {
-			return xerrors.Errorf("%q is not a supported role", r)
+			customRoles = append(customRoles, r)
+		}
+	}
+
+	if len(customRoles) > 0 {
+		// Any role that is not built-in must be an existing custom role.
+		found, err := q.db.CustomRoles(ctx, database.CustomRolesParams{
+			LookupRoles: customRoles,
+		})
+		if err != nil {
+			return xerrors.Errorf("fetch custom roles: %w", err)
+		}
+		// Custom roles are looked up regardless of case.
+		exists := make(map[string]bool, len(found))
+		for _, role := range found {
+			exists[strings.ToLower(role.RoleName())] = true
+		}
+		for _, r := range customRoles {
+			if !exists[strings.ToLower(r)] {
+				return xerrors.Errorf("%q is not a supported role", r)
+			}
 		}
 	}
 
@@ -526,6 +630,76 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	return nil
 }
 
+func (q *querier) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
+	return fetchWithPostFilter(q.auth, q.db.CustomRoles)(ctx, arg)
+}
+
+func (q *querier) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, customRoleObject(arg.OrganizationID)); err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.customRoleEscalationCheck(ctx, arg.OrganizationID, arg.SitePermissions, arg.OrgPermissions, arg.UserPermissions); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.InsertCustomRole(ctx, arg)
+}
+
+func (q *querier) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, customRoleObject(arg.OrganizationID)); err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.customRoleEscalationCheck(ctx, arg.OrganizationID, arg.SitePermissions, arg.OrgPermissions, arg.UserPermissions); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.UpdateCustomRole(ctx, arg)
+}
+
+func customRoleObject(orgID uuid.NullUUID) rbac.Object {
+	if orgID.Valid {
+		return rbac.ResourceCustomRole.InOrg(orgID.UUID)
+	}
+	return rbac.ResourceCustomRole
+}
+
+// customRoleEscalationCheck ensures the actor already has every permission
+// they are trying to grant with a custom role. Otherwise any user allowed to
+// edit roles could grant themselves any permission.
+func (q *querier) customRoleEscalationCheck(ctx context.Context, orgID uuid.NullUUID, site, org, user []rbac.Permission) error {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return NoActorError
+	}
+
+	check := func(perms []rbac.Permission, object func(resourceType string) rbac.Object) error {
+		for _, perm := range perms {
+			if perm.Negate {
+				// Negative permissions only take away, so they are always
+				// safe to grant.
+				continue
+			}
+			err := q.auth.Authorize(ctx, actor, perm.Action, object(perm.ResourceType))
+			if err != nil {
+				return logNotAuthorizedError(ctx, q.log, xerrors.Errorf("cannot grant %q on %q: %w", perm.Action, perm.ResourceType, err))
+			}
+		}
+		return nil
+	}
+
+	if err := check(site, func(resourceType string) rbac.Object {
+		return rbac.Object{Type: resourceType}
+	}); err != nil {
+		return err
+	}
+	if err := check(org, func(resourceType string) rbac.Object {
+		return rbac.Object{Type: resourceType}.InOrg(orgID.UUID)
+	}); err != nil {
+		return err
+	}
+	return check(user, func(resourceType string) rbac.Object {
+		return rbac.Object{Type: resourceType}.WithOwner(actor.ID)
+	})
+}
+
 func (q *querier) parameterRBACResource(ctx context.Context, scope database.ParameterScope, scopeID uuid.UUID) (rbac.Objecter, error) {
 	var resource rbac.Objecter
 	var err error
@@ -772,6 +946,18 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionPromotionsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVersionPromotion, error) {
+	// An actor can read the promotions of a template if they can read the template.
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionPromotionsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
 	if err != nil {
@@ -858,6 +1044,18 @@ func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.Insert
 	return q.db.InsertTemplateVersion(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionPromotion(ctx context.Context, arg database.InsertTemplateVersionPromotionParams) (database.TemplateVersionPromotion, error) {
+	// An actor is allowed to record a promotion if they are authorized to update the template.
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+	return q.db.InsertTemplateVersionPromotion(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 	// UpdateTemplateACL uses the ActionCreate action. Only users that can create the template
 	// may update the ACL.
@@ -948,6 +1146,35 @@ func (q *querier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, a
 	return q.db.UpdateTemplateVersionDescriptionByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionPromotedByID(ctx context.Context, arg database.UpdateTemplateVersionPromotedByIDParams) error {
+	// An actor is allowed to record a promotion if they are authorized to update the template.
+	tv, err := q.db.GetTemplateVersionByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+	var obj rbac.Objecter
+	if !tv.TemplateID.Valid {
+		obj = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		tpl, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+		if err != nil {
+			return err
+		}
+		obj = tpl
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, obj); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateVersionPromotedByID(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateVersionPromotionsRolledBack(ctx context.Context, arg database.UpdateTemplateVersionPromotionsRolledBackParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateVersionPromotionsRolledBackParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.TemplateID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateVersionPromotionsRolledBack)(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.Context, arg database.UpdateTemplateVersionGitAuthProvidersByJobIDParams) error {
 	// An actor is allowed to update the template version git auth providers if they are authorized to update the template.
 	tv, err := q.db.GetTemplateVersionByJobID(ctx, arg.JobID)
@@ -1022,6 +1249,14 @@ func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID
 	return q.db.GetQuotaAllowanceForUser(ctx, userID)
 }
 
+func (q *querier) GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOrganization.WithID(organizationID).InOrg(organizationID))
+	if err != nil {
+		return -1, err
+	}
+	return q.db.GetQuotaConsumedForOrganization(ctx, organizationID)
+}
+
 func (q *querier) GetQuotaConsumedForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
 	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUser.WithID(userID))
 	if err != nil {
@@ -1030,6 +1265,13 @@ func (q *querier) GetQuotaConsumedForUser(ctx context.Context, userID uuid.UUID)
 	return q.db.GetQuotaConsumedForUser(ctx, userID)
 }
 
+func (q *querier) GetQuotaSpendSegments(ctx context.Context, arg database.GetQuotaSpendSegmentsParams) ([]database.GetQuotaSpendSegmentsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWorkspace.All()); err != nil {
+		return nil, err
+	}
+	return q.db.GetQuotaSpendSegments(ctx, arg)
+}
+
 func (q *querier) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	return fetch(q.log, q.auth, q.db.GetUserByEmailOrUsername)(ctx, arg)
 }
@@ -1171,6 +1413,17 @@ func (q *querier) UpdateUserProfile(ctx context.Context, arg database.UpdateUser
 	return q.db.UpdateUserProfile(ctx, arg)
 }
 
+func (q *querier) UpdateUserQuietHoursSchedule(ctx context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
+	u, err := q.db.GetUserByID(ctx, arg.ID)
+	if err != nil {
+		return database.User{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.User{}, err
+	}
+	return q.db.UpdateUserQuietHoursSchedule(ctx, arg)
+}
+
 func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	fetch := func(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 		return q.db.GetUserByID(ctx, arg.ID)
@@ -1576,6 +1829,16 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentScript(ctx context.Context, arg database.InsertWorkspaceAgentScriptParams) (database.WorkspaceAgentScript, error) {
+	// Like agent metadata, scripts may belong to an orphaned agent used by a
+	// dry run build.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentScript(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database.UpdateWorkspaceAgentMetadataParams) error {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
 	if err != nil {
@@ -1590,6 +1853,20 @@ func (q *querier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentScriptStatus(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentScriptStatus(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentMetadatum, error) {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, workspaceAgentID)
 	if err != nil {
@@ -1669,6 +1946,13 @@ func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.Up
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceLastUsedAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceDormantAt(ctx context.Context, arg database.UpdateWorkspaceDormantAtParams) (database.Workspace, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceDormantAtParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceDormantAt)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTLToBeWithinTemplateMax(ctx context.Context, arg database.UpdateWorkspaceTTLToBeWithinTemplateMaxParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLToBeWithinTemplateMaxParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -1683,6 +1967,51 @@ func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWor
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceTTL)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceAutomaticUpdates)(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	// Authorized call to get the workspace. If we can read the workspace, we
+	// can read its port shares.
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+	return q.db.GetWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.ListWorkspaceAgentPortShares(ctx, workspaceID)
+}
+
+func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceAgentPortShare(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
@@ -1697,6 +2026,10 @@ func (q *querier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (data
 	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByID)(ctx, id)
 }
 
+func (q *querier) GetWorkspaceProxyByName(ctx context.Context, name string) (database.WorkspaceProxy, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByName)(ctx, name)
+}
+
 func (q *querier) GetWorkspaceProxyByHostname(ctx context.Context, hostname string) (database.WorkspaceProxy, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByHostname)(ctx, hostname)
 }
@@ -1719,6 +2052,53 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.RegisterWorkspaceProxy)(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceProxyHealth(ctx context.Context, arg database.UpdateWorkspaceProxyHealthParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyHealthParams) (database.WorkspaceProxy, error) {
+		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyHealth)(ctx, arg)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.Webhook, error) {
+		return q.db.GetWebhooks(ctx)
+	})(ctx, nil)
+}
+
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	return fetch(q.log, q.auth, q.db.GetWebhookByID)(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	// Deliveries are visible to anyone who can read the webhook.
+	if _, err := q.GetWebhookByID(ctx, arg.WebhookID); err != nil { // AuthZ check
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	return insert(q.log, q.auth, rbac.ResourceWebhook, q.db.InsertWebhook)(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+		return q.db.GetWebhookByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWebhookByID)(ctx, arg)
+}
+
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetWebhookByID, q.db.DeleteWebhookByID)(ctx, id)
+}
+
 func authorizedTemplateVersionFromJob(ctx context.Context, q *querier, job database.ProvisionerJob) (database.TemplateVersion, error) {
 	switch job.Type {
 	case database.ProvisionerJobTypeTemplateVersionDryRun:
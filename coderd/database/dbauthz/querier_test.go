@@ -2,6 +2,7 @@ package dbauthz_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"time"
 
@@ -144,6 +145,16 @@ func (s *MethodTestSuite) TestGroup() {
 		_ = dbgen.GroupMember(s.T(), db, database.GroupMember{})
 		check.Args(g.ID).Asserts(g, rbac.ActionRead)
 	}))
+	s.Run("GetGroupMembershipsByUserIDs", s.Subtest(func(db database.Store, check *expects) {
+		g := dbgen.Group(s.T(), db, database.Group{})
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.GroupMember(s.T(), db, database.GroupMember{GroupID: g.ID, UserID: u.ID})
+		check.Args([]uuid.UUID{u.ID}).Asserts(rbac.ResourceGroup.All(), rbac.ActionRead).Returns([]database.GetGroupMembershipsByUserIDsRow{{
+			UserID:    u.ID,
+			GroupID:   g.ID,
+			GroupName: g.Name,
+		}})
+	}))
 	s.Run("InsertAllUsersGroup", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		check.Args(o.ID).Asserts(rbac.ResourceGroup.InOrg(o.ID), rbac.ActionCreate)
@@ -287,6 +298,14 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 		b := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
 		check.Args([]uuid.UUID{a.ID, b.ID}).Asserts().Returns(slice.New(a, b))
 	}))
+	s.Run("GetProvisionerJobTimingsByJobID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: w.ID})
+		check.Args(j.ID).Asserts(w, rbac.ActionRead).Returns([]database.ProvisionerJobTiming{})
+	}))
 	s.Run("GetProvisionerLogsAfterID", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
 		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{
@@ -436,6 +455,17 @@ func (s *MethodTestSuite) TestOrganization() {
 			rbac.ResourceRoleAssignment.InOrg(o.ID), rbac.ActionDelete, // org-admin
 		).Returns(out)
 	}))
+	s.Run("UpdateOrganizationQuotaBudget", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpdateOrganizationQuotaBudgetParams{
+			ID:          o.ID,
+			QuotaBudget: 100,
+		}).Asserts(o, rbac.ActionUpdate)
+	}))
+	s.Run("GetQuotaConsumedForOrganization", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(o, rbac.ActionRead).Returns(int64(0))
+	}))
 }
 
 func (s *MethodTestSuite) TestWorkspaceProxy() {
@@ -454,6 +484,24 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args(p.ID).Asserts(p, rbac.ActionRead).Returns(p)
 	}))
+	s.Run("GetWorkspaceProxyByName", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		check.Args(p.Name).Asserts(p, rbac.ActionRead).Returns(p)
+	}))
+	s.Run("RegisterWorkspaceProxy", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		check.Args(database.RegisterWorkspaceProxyParams{
+			ID:      p.ID,
+			Version: "v1.0.0",
+		}).Asserts(p, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceProxyHealth", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		check.Args(database.UpdateWorkspaceProxyHealthParams{
+			ID:          p.ID,
+			HealthError: "unreachable",
+		}).Asserts(p, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceProxyDeleted", s.Subtest(func(db database.Store, check *expects) {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args(database.UpdateWorkspaceProxyDeletedParams{
@@ -468,6 +516,87 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWebhook() {
+	s.Run("InsertWebhook", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookParams{
+			ID:     uuid.New(),
+			Events: []database.WebhookEvent{database.WebhookEventUserCreated},
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("UpdateWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.UpdateWebhookByIDParams{
+			ID:     w.ID,
+			Name:   w.Name,
+			Events: w.Events,
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionRead).Returns(w)
+	}))
+	s.Run("GetWebhooks", s.Subtest(func(db database.Store, check *expects) {
+		w1 := dbgen.Webhook(s.T(), db, database.Webhook{Name: "a"})
+		w2 := dbgen.Webhook(s.T(), db, database.Webhook{Name: "b"})
+		check.Args().Asserts(w1, rbac.ActionRead, w2, rbac.ActionRead).Returns(slice.New(w1, w2))
+	}))
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		d := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: w.ID})
+		check.Args(database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: w.ID,
+		}).Asserts(w, rbac.ActionRead).Returns(slice.New(d))
+	}))
+}
+
+func (s *MethodTestSuite) TestCustomRole() {
+	s.Run("CustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		site := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "a"})
+		org := dbgen.CustomRole(s.T(), db, database.CustomRole{
+			Name:           "b",
+			OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true},
+		})
+		check.Args(database.CustomRolesParams{}).
+			Asserts(site, rbac.ActionRead, org, rbac.ActionRead).
+			Returns(slice.New(site, org))
+	}))
+	s.Run("InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertCustomRoleParams{
+			ID:   uuid.New(),
+			Name: "template-editor",
+			SitePermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionUpdate},
+				// Negated permissions are not checked for escalation.
+				{ResourceType: rbac.ResourceWorkspace.Type, Action: rbac.ActionRead, Negate: true},
+			},
+		}).Asserts(
+			rbac.ResourceCustomRole, rbac.ActionCreate,
+			rbac.ResourceTemplate, rbac.ActionUpdate,
+		)
+	}))
+	s.Run("UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		role := dbgen.CustomRole(s.T(), db, database.CustomRole{
+			OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true},
+		})
+		check.Args(database.UpdateCustomRoleParams{
+			Name:           role.Name,
+			OrganizationID: role.OrganizationID,
+			OrgPermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionCreate},
+			},
+		}).Asserts(
+			rbac.ResourceCustomRole.InOrg(o.ID), rbac.ActionUpdate,
+			rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionCreate,
+		)
+	}))
+}
+
 func (s *MethodTestSuite) TestParameters() {
 	s.Run("Workspace/InsertParameterValue", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -634,6 +763,20 @@ func (s *MethodTestSuite) TestTemplate() {
 		})
 		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionParameter{})
 	}))
+	s.Run("GetTemplateVersionPromotionsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		promotion, err := db.InsertTemplateVersionPromotion(context.Background(), database.InsertTemplateVersionPromotionParams{
+			ID:                uuid.New(),
+			TemplateID:        t1.ID,
+			TemplateVersionID: tv.ID,
+			PromotedAt:        database.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionPromotion{promotion})
+	}))
 	s.Run("GetTemplateVersionVariables", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
@@ -745,7 +888,8 @@ func (s *MethodTestSuite) TestTemplate() {
 	s.Run("UpdateTemplateMetaByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(database.UpdateTemplateMetaByIDParams{
-			ID: t1.ID,
+			ID:                  t1.ID,
+			MaxPortSharingLevel: database.AppSharingLevelOwner,
 		}).Asserts(t1, rbac.ActionUpdate)
 	}))
 	s.Run("UpdateTemplateVersionByID", s.Subtest(func(db database.Store, check *expects) {
@@ -784,6 +928,38 @@ func (s *MethodTestSuite) TestTemplate() {
 			GitAuthProviders: []string{},
 		}).Asserts(t1, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateTemplateVersionPromotedByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		u := dbgen.User(s.T(), db, database.User{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.UpdateTemplateVersionPromotedByIDParams{
+			ID:         tv.ID,
+			PromotedAt: sql.NullTime{Time: database.Now(), Valid: true},
+			PromotedBy: uuid.NullUUID{UUID: u.ID, Valid: true},
+		}).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("InsertTemplateVersionPromotion", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.InsertTemplateVersionPromotionParams{
+			ID:                uuid.New(),
+			TemplateID:        t1.ID,
+			TemplateVersionID: tv.ID,
+			PromotedAt:        database.Now(),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateVersionPromotionsRolledBack", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateVersionPromotionsRolledBackParams{
+			TemplateID:    t1.ID,
+			RolledBackAt:  sql.NullTime{Time: database.Now(), Valid: true},
+			PromotedAfter: database.Now().Add(-time.Hour),
+		}).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestUser() {
@@ -883,6 +1059,13 @@ func (s *MethodTestSuite) TestUser() {
 			UpdatedAt: u.UpdatedAt,
 		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns(u)
 	}))
+	s.Run("UpdateUserQuietHoursSchedule", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserQuietHoursScheduleParams{
+			ID:                 u.ID,
+			QuietHoursSchedule: u.QuietHoursSchedule,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns(u)
+	}))
 	s.Run("UpdateUserStatus", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.UpdateUserStatusParams{
@@ -973,6 +1156,12 @@ func (s *MethodTestSuite) TestWorkspace() {
 		// No asserts here because SQLFilter.
 		check.Args(database.GetWorkspacesParams{}).Asserts()
 	}))
+	s.Run("GetQuotaSpendSegments", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetQuotaSpendSegmentsParams{
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now(),
+		}).Asserts(rbac.ResourceWorkspace.All(), rbac.ActionRead).Returns([]database.GetQuotaSpendSegmentsRow{})
+	}))
 	s.Run("GetAuthorizedWorkspaces", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.Workspace(s.T(), db, database.Workspace{})
 		_ = dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1041,6 +1230,15 @@ func (s *MethodTestSuite) TestWorkspace() {
 			AgentID: agt.ID,
 		}).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentStartupLog{})
 	}))
+	s.Run("GetWorkspaceAgentDebugLogsAfter", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.GetWorkspaceAgentDebugLogsAfterParams{
+			AgentID: agt.ID,
+		}).Asserts(rbac.ResourceDebugInfo, rbac.ActionRead, ws, rbac.ActionRead).Returns([]database.WorkspaceAgentDebugLog{})
+	}))
 	s.Run("GetWorkspaceAppByAgentIDAndSlug", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1132,9 +1330,10 @@ func (s *MethodTestSuite) TestWorkspace() {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		check.Args(database.InsertWorkspaceParams{
-			ID:             uuid.New(),
-			OwnerID:        u.ID,
-			OrganizationID: o.ID,
+			ID:               uuid.New(),
+			OwnerID:          u.ID,
+			OrganizationID:   o.ID,
+			AutomaticUpdates: database.AutomaticUpdatesNever,
 		}).Asserts(rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID), rbac.ActionCreate)
 	}))
 	s.Run("Start/InsertWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
@@ -1227,6 +1426,83 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: ws.ID,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAutomaticUpdates", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceAutomaticUpdatesParams{
+			ID:               ws.ID,
+			AutomaticUpdates: database.AutomaticUpdatesAlways,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		share, err := db.UpsertWorkspaceAgentPortShare(context.Background(), database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelPublic,
+			Protocol:    database.PortShareProtocolHttp,
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   share.AgentName,
+			Port:        share.Port,
+		}).Asserts(ws, rbac.ActionRead).Returns(share)
+	}))
+	s.Run("ListWorkspaceAgentPortShares", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		share, err := db.UpsertWorkspaceAgentPortShare(context.Background(), database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelPublic,
+			Protocol:    database.PortShareProtocolHttp,
+		})
+		require.NoError(s.T(), err)
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentPortShare{share})
+	}))
+	s.Run("UpsertWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelAuthenticated,
+			Protocol:    database.PortShareProtocolHttps,
+		}).Asserts(ws, rbac.ActionUpdate).Returns(database.WorkspaceAgentPortShare{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelAuthenticated,
+			Protocol:    database.PortShareProtocolHttps,
+		})
+	}))
+	s.Run("DeleteWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.DeleteWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateWorkspaceAgentScriptStatus", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		script := dbgen.WorkspaceAgentScript(s.T(), db, database.WorkspaceAgentScript{WorkspaceAgentID: agt.ID})
+		check.Args(database.UpdateWorkspaceAgentScriptStatusParams{
+			ID:               script.ID,
+			WorkspaceAgentID: agt.ID,
+			Status:           database.WorkspaceAgentScriptStatusOk,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateWorkspaceDormantAt", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceDormantAtParams{
+			ID: ws.ID,
+		}).Asserts(ws, rbac.ActionUpdate).Returns(ws)
+	}))
 	s.Run("GetWorkspaceByWorkspaceAppID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1245,4 +1521,45 @@ func (s *MethodTestSuite) TestExtraMethods() {
 		s.NoError(err, "insert provisioner daemon")
 		check.Args().Asserts(d, rbac.ActionRead)
 	}))
+	s.Run("UpdateProvisionerDaemonLastSeenAt", s.Subtest(func(db database.Store, check *expects) {
+		d, err := db.InsertProvisionerDaemon(context.Background(), database.InsertProvisionerDaemonParams{
+			ID: uuid.New(),
+		})
+		s.NoError(err, "insert provisioner daemon")
+		check.Args(database.UpdateProvisionerDaemonLastSeenAtParams{
+			ID:         d.ID,
+			LastSeenAt: sql.NullTime{Time: database.Now(), Valid: true},
+		}).Asserts(rbac.ResourceProvisionerDaemon, rbac.ActionUpdate)
+	}))
+	s.Run("GetRunningProvisionerJobsByWorkerIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceProvisionerDaemon, rbac.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestProvisionerKey() {
+	s.Run("InsertProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertProvisionerKeyParams{
+			ID:   uuid.New(),
+			Name: "ci",
+		}).Asserts(rbac.ResourceProvisionerDaemon, rbac.ActionCreate)
+	}))
+	s.Run("GetProvisionerKeyByID", s.Subtest(func(db database.Store, check *expects) {
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{})
+		check.Args(k.ID).Asserts(k, rbac.ActionRead).Returns(k)
+	}))
+	s.Run("GetProvisionerKeyByName", s.Subtest(func(db database.Store, check *expects) {
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{})
+		check.Args(database.GetProvisionerKeyByNameParams{
+			OrganizationID: k.OrganizationID,
+			Name:           k.Name,
+		}).Asserts(k, rbac.ActionRead).Returns(k)
+	}))
+	s.Run("GetProvisionerKeysByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{})
+		check.Args(k.OrganizationID).Asserts(k, rbac.ActionRead).Returns([]database.ProvisionerKey{k})
+	}))
+	s.Run("DeleteProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{})
+		check.Args(k.ID).Asserts(k, rbac.ActionDelete).Returns()
+	}))
 }
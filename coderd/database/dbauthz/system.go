@@ -26,6 +26,15 @@ func (q *querier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUI
 	return q.db.GetWorkspaceAppsByAgentIDs(ctx, ids)
 }
 
+// GetWorkspaceAgentScriptsByAgentIDs
+// The workspace/job is already fetched.
+func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+}
+
 // GetWorkspaceAgentsByResourceIDs
 // The workspace/job is already fetched.
 func (q *querier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgent, error) {
@@ -294,6 +303,20 @@ func (q *querier) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error
 	return q.db.DeleteOldWorkspaceAgentStartupLogs(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceAgentDebugLogs(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentDebugLogs(ctx)
+}
+
+func (q *querier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWebhookDeliveries(ctx)
+}
+
 func (q *querier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
 	return q.db.GetDeploymentWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -306,12 +329,16 @@ func (q *querier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAf
 	return q.db.GetWorkspaceAgentStatsAndLabels(ctx, createdAfter)
 }
 
+func (q *querier) GetWorkspaceAgentResourceStatsAndLabels(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentResourceStatsAndLabelsRow, error) {
+	return q.db.GetWorkspaceAgentResourceStatsAndLabels(ctx, createdAfter)
+}
+
 func (q *querier) GetDeploymentWorkspaceStats(ctx context.Context) (database.GetDeploymentWorkspaceStatsRow, error) {
 	return q.db.GetDeploymentWorkspaceStats(ctx)
 }
 
-func (q *querier) GetWorkspacesEligibleForAutoStartStop(ctx context.Context, now time.Time) ([]database.Workspace, error) {
-	return q.db.GetWorkspacesEligibleForAutoStartStop(ctx, now)
+func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
+	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
 
 func (q *querier) GetParameterSchemasCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.ParameterSchema, error) {
@@ -399,10 +426,21 @@ func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.Ins
 	return q.db.InsertProvisionerJobLogs(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertProvisionerJobTimings(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	return q.db.InsertWorkspaceAgentStartupLogs(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentDebugLogs(ctx context.Context, arg database.InsertWorkspaceAgentDebugLogsParams) ([]database.WorkspaceAgentDebugLog, error) {
+	return q.db.InsertWorkspaceAgentDebugLogs(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerDaemon resource type
 func (q *querier) InsertProvisionerDaemon(ctx context.Context, arg database.InsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
@@ -438,3 +476,31 @@ func (q *querier) InsertParameterSchema(ctx context.Context, arg database.Insert
 	}
 	return q.db.InsertParameterSchema(ctx, arg)
 }
+
+func (q *querier) GetEnabledWebhooksByEvent(ctx context.Context, event database.WebhookEvent) ([]database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetEnabledWebhooksByEvent(ctx, event)
+}
+
+func (q *querier) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.InsertWebhookDelivery(ctx, arg)
+}
+
+func (q *querier) AcquireWebhookDelivery(ctx context.Context, arg database.AcquireWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.AcquireWebhookDelivery(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookDeliveryByID(ctx, arg)
+}
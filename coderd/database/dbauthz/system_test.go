@@ -175,6 +175,23 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Asserts(rbac.ResourceSystem, rbac.ActionRead).
 			Returns([]database.WorkspaceApp{a, b})
 	}))
+	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		script := dbgen.WorkspaceAgentScript(s.T(), db, database.WorkspaceAgentScript{WorkspaceAgentID: agt.ID})
+
+		check.Args([]uuid.UUID{agt.ID}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead).
+			Returns([]database.WorkspaceAgentScript{script})
+	}))
+	s.Run("InsertWorkspaceAgentScript", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentScriptParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
 	s.Run("GetWorkspaceResourcesByJobIDs", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		v := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, JobID: uuid.New()})
@@ -220,9 +237,10 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	}))
 	s.Run("InsertWorkspaceApp", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAppParams{
-			ID:           uuid.New(),
-			Health:       database.WorkspaceAppHealthDisabled,
-			SharingLevel: database.AppSharingLevelOwner,
+			ID:              uuid.New(),
+			Health:          database.WorkspaceAppHealthDisabled,
+			SharingLevel:    database.AppSharingLevelOwner,
+			HealthcheckType: database.WorkspaceAppHealthcheckTypeHTTP,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
 	s.Run("InsertWorkspaceResourceMetadata", s.Subtest(func(db database.Store, check *expects) {
@@ -271,6 +289,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionCreate*/ )
 	}))
+	s.Run("InsertProvisionerJobTimings", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobTimingsParams{
+			JobID: j.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
 	s.Run("InsertProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerJob resource
 		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
@@ -305,4 +329,38 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ValidationTypeSystem:     database.ParameterTypeSystemNone,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("DeleteOldWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetEnabledWebhooksByEvent", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{
+			Events: []database.WebhookEvent{database.WebhookEventUserCreated},
+		})
+		check.Args(database.WebhookEventUserCreated).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(slice.New(w))
+	}))
+	s.Run("InsertWebhookDelivery", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.InsertWebhookDeliveryParams{
+			ID:        uuid.New(),
+			WebhookID: w.ID,
+			Event:     database.WebhookEventWorkspaceBuildStarted,
+			Payload:   []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireWebhookDelivery", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		_ = dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: w.ID})
+		check.Args(database.AcquireWebhookDeliveryParams{
+			LeaseUntil: time.Now().Add(time.Minute),
+			Now:        time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		d := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: w.ID})
+		check.Args(database.UpdateWebhookDeliveryByIDParams{
+			ID:       d.ID,
+			Attempts: 1,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 }
@@ -56,6 +56,7 @@ func New() database.Store {
 			parameterSchemas:          make([]database.ParameterSchema, 0),
 			parameterValues:           make([]database.ParameterValue, 0),
 			provisionerDaemons:        make([]database.ProvisionerDaemon, 0),
+			provisionerKeys:           make([]database.ProvisionerKey, 0),
 			workspaceAgents:           make([]database.WorkspaceAgent, 0),
 			provisionerJobLogs:        make([]database.ProvisionerJobLog, 0),
 			workspaceResources:        make([]database.WorkspaceResource, 0),
@@ -70,6 +71,9 @@ func New() database.Store {
 			workspaces:                make([]database.Workspace, 0),
 			licenses:                  make([]database.License, 0),
 			workspaceProxies:          make([]database.WorkspaceProxy, 0),
+			webhooks:                  make([]database.Webhook, 0),
+			webhookDeliveries:         make([]database.WebhookDelivery, 0),
+			customRoles:               make([]database.CustomRole, 0),
 			locks:                     map[int64]struct{}{},
 		},
 	}
@@ -122,15 +126,21 @@ type data struct {
 	parameterValues           []database.ParameterValue
 	provisionerDaemons        []database.ProvisionerDaemon
 	provisionerJobLogs        []database.ProvisionerJobLog
+	provisionerJobTimings     []database.ProvisionerJobTiming
 	provisionerJobs           []database.ProvisionerJob
+	provisionerKeys           []database.ProvisionerKey
 	replicas                  []database.Replica
 	templateVersions          []database.TemplateVersion
 	templateVersionParameters []database.TemplateVersionParameter
+	templateVersionPromotions []database.TemplateVersionPromotion
 	templateVersionVariables  []database.TemplateVersionVariable
 	templates                 []database.Template
 	workspaceAgents           []database.WorkspaceAgent
 	workspaceAgentMetadata    []database.WorkspaceAgentMetadatum
 	workspaceAgentLogs        []database.WorkspaceAgentStartupLog
+	workspaceAgentDebugLogs   []database.WorkspaceAgentDebugLog
+	workspaceAgentPortShares  []database.WorkspaceAgentPortShare
+	workspaceAgentScripts     []database.WorkspaceAgentScript
 	workspaceApps             []database.WorkspaceApp
 	workspaceBuilds           []database.WorkspaceBuild
 	workspaceBuildParameters  []database.WorkspaceBuildParameter
@@ -138,6 +148,9 @@ type data struct {
 	workspaceResources        []database.WorkspaceResource
 	workspaces                []database.Workspace
 	workspaceProxies          []database.WorkspaceProxy
+	webhooks                  []database.Webhook
+	webhookDeliveries         []database.WebhookDelivery
+	customRoles               []database.CustomRole
 
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
@@ -148,6 +161,7 @@ type data struct {
 	serviceBanner   []byte
 	logoURL         string
 	appSecurityKey  string
+	sshCAKey        string
 	lastLicenseID   int32
 }
 
@@ -284,6 +298,9 @@ func (q *fakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !found {
 			continue
 		}
+		if arg.OrganizationID != uuid.Nil && provisionerJob.OrganizationID != arg.OrganizationID {
+			continue
+		}
 		tags := map[string]string{}
 		if arg.Tags != nil {
 			err := json.Unmarshal(arg.Tags, &tags)
@@ -429,6 +446,14 @@ func (q *fakeQuerier) InsertWorkspaceAgentStat(_ context.Context, p database.Ins
 		SessionCountReconnectingPTY: p.SessionCountReconnectingPTY,
 		SessionCountSSH:             p.SessionCountSSH,
 		ConnectionMedianLatencyMS:   p.ConnectionMedianLatencyMS,
+		CPUUsedCores:                p.CPUUsedCores,
+		CPULimitCores:               p.CPULimitCores,
+		MemoryUsedBytes:             p.MemoryUsedBytes,
+		MemoryLimitBytes:            p.MemoryLimitBytes,
+		DiskUsedBytes:               p.DiskUsedBytes,
+		DiskTotalBytes:              p.DiskTotalBytes,
+		NetworkRxBytes:              p.NetworkRxBytes,
+		NetworkTxBytes:              p.NetworkTxBytes,
 	}
 	q.workspaceAgentStats = append(q.workspaceAgentStats, stat)
 	return stat, nil
@@ -1264,6 +1289,10 @@ func (q *fakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 			}
 		}
 
+		if arg.Dormant && !workspace.DormantAt.Valid {
+			continue
+		}
+
 		if arg.HasAgent != "" {
 			build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
 			if err != nil {
@@ -1902,6 +1931,11 @@ func (q *fakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.Deprecated = arg.Deprecated
+		tpl.RequireActiveVersion = arg.RequireActiveVersion
+		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
+		tpl.SessionRecordingEnabled = arg.SessionRecordingEnabled
+		tpl.QuotaCostMultiplier = arg.QuotaCostMultiplier
 		q.templates[idx] = tpl
 		return tpl.DeepCopy(), nil
 	}
@@ -1926,6 +1960,11 @@ func (q *fakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database
 		tpl.UpdatedAt = database.Now()
 		tpl.DefaultTTL = arg.DefaultTTL
 		tpl.MaxTTL = arg.MaxTTL
+		tpl.InactivityTTL = arg.InactivityTTL
+		tpl.DormantAutoDeleteTTL = arg.DormantAutoDeleteTTL
+		tpl.FailureTTL = arg.FailureTTL
+		tpl.RestartRequirementDaysOfWeek = arg.RestartRequirementDaysOfWeek
+		tpl.RestartRequirementWeeks = arg.RestartRequirementWeeks
 		q.templates[idx] = tpl
 		return tpl.DeepCopy(), nil
 	}
@@ -1986,6 +2025,9 @@ func (q *fakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.G
 				continue
 			}
 		}
+		if arg.ExcludeDeprecated && template.Deprecated != "" {
+			continue
+		}
 		templates = append(templates, template.DeepCopy())
 	}
 	if len(templates) > 0 {
@@ -2111,6 +2153,23 @@ func (q *fakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVe
 	return parameters, nil
 }
 
+func (q *fakeQuerier) GetTemplateVersionPromotionsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateVersionPromotion, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	promotions := make([]database.TemplateVersionPromotion, 0)
+	for _, promotion := range q.templateVersionPromotions {
+		if promotion.TemplateID != templateID || promotion.RolledBackAt.Valid {
+			continue
+		}
+		promotions = append(promotions, promotion)
+	}
+	sort.SliceStable(promotions, func(i, j int) bool {
+		return promotions[i].PromotedAt.After(promotions[j].PromotedAt)
+	})
+	return promotions, nil
+}
+
 func (q *fakeQuerier) GetTemplateVersionVariables(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2436,6 +2495,26 @@ func (q *fakeQuerier) GetOrganizationMembershipsByUserID(_ context.Context, user
 	return memberships, nil
 }
 
+func (q *fakeQuerier) UpdateOrganizationQuotaBudget(_ context.Context, arg database.UpdateOrganizationQuotaBudgetParams) (database.Organization, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Organization{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, organization := range q.organizations {
+		if organization.ID != arg.ID {
+			continue
+		}
+		organization.QuotaBudget = arg.QuotaBudget
+		organization.UpdatedAt = arg.UpdatedAt
+		q.organizations[i] = organization
+		return organization, nil
+	}
+	return database.Organization{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.OrganizationMember{}, err
@@ -2678,6 +2757,23 @@ func (q *fakeQuerier) GetWorkspaceResourceMetadataByResourceIDs(_ context.Contex
 	return metadata, nil
 }
 
+func (q *fakeQuerier) GetProvisionerJobTimingsByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	timings := make([]database.ProvisionerJobTiming, 0)
+	for _, timing := range q.provisionerJobTimings {
+		if timing.JobID != jobID {
+			continue
+		}
+		timings = append(timings, timing)
+	}
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].StartedAt.Before(timings[j].StartedAt)
+	})
+	return timings, nil
+}
+
 func (q *fakeQuerier) GetProvisionerJobsByIDs(_ context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2698,6 +2794,22 @@ func (q *fakeQuerier) GetProvisionerJobsByIDs(_ context.Context, ids []uuid.UUID
 	return jobs, nil
 }
 
+func (q *fakeQuerier) GetRunningProvisionerJobsByWorkerIDs(_ context.Context, workerIDs []uuid.UUID) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if !job.WorkerID.Valid || !job.StartedAt.Valid || job.CompletedAt.Valid {
+			continue
+		}
+		if slices.Contains(workerIDs, job.WorkerID.UUID) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
 func (q *fakeQuerier) GetProvisionerJobsCreatedAfter(_ context.Context, after time.Time) ([]database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2926,6 +3038,8 @@ func (q *fakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		AllowUserCancelWorkspaceJobs: arg.AllowUserCancelWorkspaceJobs,
 		AllowUserAutostart:           true,
 		AllowUserAutostop:            true,
+		MaxPortSharingLevel:          database.AppSharingLevelOwner,
+		QuotaCostMultiplier:          1,
 	}
 	q.templates = append(q.templates, template)
 	return template.DeepCopy(), nil
@@ -2986,6 +3100,26 @@ func (q *fakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg data
 	return param, nil
 }
 
+func (q *fakeQuerier) InsertTemplateVersionPromotion(_ context.Context, arg database.InsertTemplateVersionPromotionParams) (database.TemplateVersionPromotion, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	promotion := database.TemplateVersionPromotion{
+		ID:                arg.ID,
+		TemplateID:        arg.TemplateID,
+		TemplateVersionID: arg.TemplateVersionID,
+		PromotedAt:        arg.PromotedAt,
+		PromotedBy:        arg.PromotedBy,
+	}
+	q.templateVersionPromotions = append(q.templateVersionPromotions, promotion)
+	return promotion, nil
+}
+
 func (q *fakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -3038,6 +3172,30 @@ func (q *fakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 	return logs, nil
 }
 
+func (q *fakeQuerier) InsertProvisionerJobTimings(_ context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	timings := make([]database.ProvisionerJobTiming, 0, len(arg.Stage))
+	for index, stage := range arg.Stage {
+		timings = append(timings, database.ProvisionerJobTiming{
+			JobID:     arg.JobID,
+			StartedAt: arg.StartedAt[index],
+			EndedAt:   arg.EndedAt[index],
+			Stage:     stage,
+			Source:    arg.Source[index],
+			Action:    arg.Action[index],
+			Resource:  arg.Resource[index],
+		})
+	}
+	q.provisionerJobTimings = append(q.provisionerJobTimings, timings...)
+	return timings, nil
+}
+
 func (q *fakeQuerier) InsertParameterSchema(_ context.Context, arg database.InsertParameterSchemaParams) (database.ParameterSchema, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ParameterSchema{}, err
@@ -3084,11 +3242,124 @@ func (q *fakeQuerier) InsertProvisionerDaemon(_ context.Context, arg database.In
 		Name:         arg.Name,
 		Provisioners: arg.Provisioners,
 		Tags:         arg.Tags,
+		LastSeenAt:   arg.LastSeenAt,
+		Version:      arg.Version,
+		KeyID:        arg.KeyID,
 	}
 	q.provisionerDaemons = append(q.provisionerDaemons, daemon)
 	return daemon, nil
 }
 
+func (q *fakeQuerier) UpdateProvisionerDaemonLastSeenAt(_ context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, daemon := range q.provisionerDaemons {
+		if daemon.ID == arg.ID {
+			daemon.LastSeenAt = arg.LastSeenAt
+			q.provisionerDaemons[i] = daemon
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) InsertProvisionerKey(_ context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name) {
+			return database.ProvisionerKey{}, errDuplicateKey
+		}
+	}
+
+	key := database.ProvisionerKey{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		OrganizationID: arg.OrganizationID,
+		Name:           arg.Name,
+		HashedSecret:   arg.HashedSecret,
+		Tags:           arg.Tags,
+	}
+	q.provisionerKeys = append(q.provisionerKeys, key)
+	return key, nil
+}
+
+func (q *fakeQuerier) GetProvisionerKeyByID(_ context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetProvisionerKeyByName(_ context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name) {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetProvisionerKeysByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	keys := make([]database.ProvisionerKey, 0)
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == organizationID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i].Name) < strings.ToLower(keys[j].Name)
+	})
+	return keys, nil
+}
+
+func (q *fakeQuerier) DeleteProvisionerKey(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.provisionerKeys {
+		if key.ID != id {
+			continue
+		}
+		q.provisionerKeys = append(q.provisionerKeys[:i], q.provisionerKeys[i+1:]...)
+		// Daemons keep running after their key is deleted, the same as
+		// the foreign key of the table.
+		for j, daemon := range q.provisionerDaemons {
+			if daemon.KeyID.Valid && daemon.KeyID.UUID == id {
+				daemon.KeyID = uuid.NullUUID{}
+				q.provisionerDaemons[j] = daemon
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
 func (q *fakeQuerier) InsertProvisionerJob(_ context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -3142,6 +3413,7 @@ func (q *fakeQuerier) InsertWorkspaceAgent(_ context.Context, arg database.Inser
 		MOTDFile:                 arg.MOTDFile,
 		LifecycleState:           database.WorkspaceAgentLifecycleStateCreated,
 		ShutdownScript:           arg.ShutdownScript,
+		ParentID:                 arg.ParentID,
 	}
 
 	q.workspaceAgents = append(q.workspaceAgents, agent)
@@ -3302,6 +3574,25 @@ func (q *fakeQuerier) UpdateUserProfile(_ context.Context, arg database.UpdateUs
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateUserQuietHoursSchedule(_ context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.User{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, user := range q.users {
+		if user.ID != arg.ID {
+			continue
+		}
+		user.QuietHoursSchedule = arg.QuietHoursSchedule
+		q.users[index] = user
+		return user, nil
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.User{}, err
@@ -3381,6 +3672,7 @@ func (q *fakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		AutostartSchedule: arg.AutostartSchedule,
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
+		AutomaticUpdates:  arg.AutomaticUpdates,
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -3441,24 +3733,32 @@ func (q *fakeQuerier) InsertWorkspaceApp(_ context.Context, arg database.InsertW
 	if arg.SharingLevel == "" {
 		arg.SharingLevel = database.AppSharingLevelOwner
 	}
+	if arg.HealthcheckExpectedExitCodes == nil {
+		arg.HealthcheckExpectedExitCodes = []int32{}
+	}
 
 	// nolint:gosimple
 	workspaceApp := database.WorkspaceApp{
-		ID:                   arg.ID,
-		AgentID:              arg.AgentID,
-		CreatedAt:            arg.CreatedAt,
-		Slug:                 arg.Slug,
-		DisplayName:          arg.DisplayName,
-		Icon:                 arg.Icon,
-		Command:              arg.Command,
-		Url:                  arg.Url,
-		External:             arg.External,
-		Subdomain:            arg.Subdomain,
-		SharingLevel:         arg.SharingLevel,
-		HealthcheckUrl:       arg.HealthcheckUrl,
-		HealthcheckInterval:  arg.HealthcheckInterval,
-		HealthcheckThreshold: arg.HealthcheckThreshold,
-		Health:               arg.Health,
+		ID:                           arg.ID,
+		AgentID:                      arg.AgentID,
+		CreatedAt:                    arg.CreatedAt,
+		Slug:                         arg.Slug,
+		DisplayName:                  arg.DisplayName,
+		Icon:                         arg.Icon,
+		Command:                      arg.Command,
+		Url:                          arg.Url,
+		External:                     arg.External,
+		Subdomain:                    arg.Subdomain,
+		SharingLevel:                 arg.SharingLevel,
+		HealthcheckUrl:               arg.HealthcheckUrl,
+		HealthcheckInterval:          arg.HealthcheckInterval,
+		HealthcheckThreshold:         arg.HealthcheckThreshold,
+		HealthcheckType:              arg.HealthcheckType,
+		HealthcheckAddress:           arg.HealthcheckAddress,
+		HealthcheckCommand:           arg.HealthcheckCommand,
+		HealthcheckExpectedExitCodes: arg.HealthcheckExpectedExitCodes,
+		HealthcheckOutputRegex:       arg.HealthcheckOutputRegex,
+		Health:                       arg.Health,
 	}
 	q.workspaceApps = append(q.workspaceApps, workspaceApp)
 	return workspaceApp, nil
@@ -3626,6 +3926,44 @@ func (q *fakeQuerier) UpdateTemplateVersionGitAuthProvidersByJobID(_ context.Con
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateTemplateVersionPromotedByID(_ context.Context, arg database.UpdateTemplateVersionPromotedByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, templateVersion := range q.templateVersions {
+		if templateVersion.ID != arg.ID {
+			continue
+		}
+		templateVersion.PromotedAt = arg.PromotedAt
+		templateVersion.PromotedBy = arg.PromotedBy
+		q.templateVersions[index] = templateVersion
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateTemplateVersionPromotionsRolledBack(_ context.Context, arg database.UpdateTemplateVersionPromotionsRolledBackParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, promotion := range q.templateVersionPromotions {
+		if promotion.TemplateID != arg.TemplateID || promotion.RolledBackAt.Valid || !promotion.PromotedAt.After(arg.PromotedAfter) {
+			continue
+		}
+		promotion.RolledBackAt = arg.RolledBackAt
+		q.templateVersionPromotions[index] = promotion
+	}
+	return nil
+}
+
 func (q *fakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -3712,6 +4050,7 @@ func (q *fakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg dat
 			CreatedAt: arg.CreatedAt[index],
 			Level:     arg.Level[index],
 			Output:    output,
+			ScriptID:  arg.ScriptID,
 		})
 		outputLength += int32(len(output))
 	}
@@ -3865,6 +4204,46 @@ func (q *fakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateW
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWorkspaceAutomaticUpdates(_ context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.AutomaticUpdates = arg.AutomaticUpdates
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceDormantAt(_ context.Context, arg database.UpdateWorkspaceDormantAtParams) (database.Workspace, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID != arg.ID {
+			continue
+		}
+		workspace.DormantAt = arg.DormantAt
+		q.workspaces[index] = workspace
+		return workspace, nil
+	}
+
+	return database.Workspace{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4073,7 +4452,7 @@ func (q *fakeQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, creat
 	return stats, nil
 }
 
-func (q *fakeQuerier) GetWorkspacesEligibleForAutoStartStop(ctx context.Context, now time.Time) ([]database.Workspace, error) {
+func (q *fakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
@@ -4093,6 +4472,39 @@ func (q *fakeQuerier) GetWorkspacesEligibleForAutoStartStop(ctx context.Context,
 			workspaces = append(workspaces, workspace)
 			continue
 		}
+
+		if workspace.Deleted {
+			continue
+		}
+		template, err := q.getTemplateByIDNoLock(ctx, workspace.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template by ID: %w", err)
+		}
+
+		if !workspace.DormantAt.Valid && template.InactivityTTL > 0 &&
+			workspace.LastUsedAt.Add(time.Duration(template.InactivityTTL)).Before(now) {
+			workspaces = append(workspaces, workspace)
+			continue
+		}
+
+		if workspace.DormantAt.Valid && template.DormantAutoDeleteTTL > 0 &&
+			build.Transition != database.WorkspaceTransitionDelete &&
+			workspace.DormantAt.Time.Add(time.Duration(template.DormantAutoDeleteTTL)).Before(now) {
+			workspaces = append(workspaces, workspace)
+			continue
+		}
+
+		if template.FailureTTL > 0 && build.Transition == database.WorkspaceTransitionStart {
+			job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+			if err != nil {
+				return nil, xerrors.Errorf("get provisioner job by ID: %w", err)
+			}
+			if job.CompletedAt.Valid && job.Error.Valid && job.Error.String != "" &&
+				job.CompletedAt.Time.Add(time.Duration(template.FailureTTL)).Before(now) {
+				workspaces = append(workspaces, workspace)
+				continue
+			}
+		}
 	}
 
 	return workspaces, nil
@@ -4825,7 +5237,40 @@ func (q *fakeQuerier) GetGroupMembers(_ context.Context, groupID uuid.UUID) ([]d
 	return users, nil
 }
 
-func (q *fakeQuerier) GetGroupsByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.Group, error) {
+func (q *fakeQuerier) GetGroupMembershipsByUserIDs(_ context.Context, userIDs []uuid.UUID) ([]database.GetGroupMembershipsByUserIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetGroupMembershipsByUserIDsRow, 0)
+	for _, member := range q.groupMembers {
+		if !slices.Contains(userIDs, member.UserID) {
+			continue
+		}
+		active := false
+		for _, user := range q.users {
+			if user.ID == member.UserID && user.Status == database.UserStatusActive && !user.Deleted {
+				active = true
+				break
+			}
+		}
+		if !active {
+			continue
+		}
+		for _, group := range q.groups {
+			if group.ID == member.GroupID {
+				rows = append(rows, database.GetGroupMembershipsByUserIDsRow{
+					UserID:    member.UserID,
+					GroupID:   group.ID,
+					GroupName: group.Name,
+				})
+				break
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (q *fakeQuerier) GetGroupsByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.Group, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
@@ -5037,6 +5482,87 @@ func (q *fakeQuerier) GetQuotaConsumedForUser(_ context.Context, userID uuid.UUI
 	return sum, nil
 }
 
+func (q *fakeQuerier) GetQuotaConsumedForOrganization(_ context.Context, organizationID uuid.UUID) (int64, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	var sum int64
+	for _, workspace := range q.workspaces {
+		if workspace.OrganizationID != organizationID {
+			continue
+		}
+		if workspace.Deleted {
+			continue
+		}
+
+		var lastBuild database.WorkspaceBuild
+		for _, build := range q.workspaceBuilds {
+			if build.WorkspaceID != workspace.ID {
+				continue
+			}
+			if build.CreatedAt.After(lastBuild.CreatedAt) {
+				lastBuild = build
+			}
+		}
+		sum += int64(lastBuild.DailyCost)
+	}
+	return sum, nil
+}
+
+func (q *fakeQuerier) GetQuotaSpendSegments(_ context.Context, arg database.GetQuotaSpendSegmentsParams) ([]database.GetQuotaSpendSegmentsRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	segments := make([]database.GetQuotaSpendSegmentsRow, 0)
+	for _, build := range q.workspaceBuilds {
+		if build.DailyCost <= 0 {
+			continue
+		}
+		var workspace database.Workspace
+		for _, w := range q.workspaces {
+			if w.ID == build.WorkspaceID {
+				workspace = w
+				break
+			}
+		}
+		if workspace.ID == uuid.Nil {
+			continue
+		}
+
+		// The segment ends when the next build of the workspace was created.
+		var endedAt time.Time
+		for _, next := range q.workspaceBuilds {
+			if next.WorkspaceID != build.WorkspaceID || !next.CreatedAt.After(build.CreatedAt) {
+				continue
+			}
+			if endedAt.IsZero() || next.CreatedAt.Before(endedAt) {
+				endedAt = next.CreatedAt
+			}
+		}
+		if endedAt.IsZero() {
+			endedAt = arg.EndTime
+		}
+		if !build.CreatedAt.Before(arg.EndTime) || !endedAt.After(arg.StartTime) {
+			continue
+		}
+		segments = append(segments, database.GetQuotaSpendSegmentsRow{
+			WorkspaceID: workspace.ID,
+			OwnerID:     workspace.OwnerID,
+			TemplateID:  workspace.TemplateID,
+			DailyCost:   build.DailyCost,
+			StartedAt:   build.CreatedAt,
+			EndedAt:     endedAt,
+		})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].StartedAt.Before(segments[j].StartedAt)
+	})
+	return segments, nil
+}
+
 func (q *fakeQuerier) UpdateWorkspaceAgentLifecycleStateByID(_ context.Context, arg database.UpdateWorkspaceAgentLifecycleStateByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5047,6 +5573,8 @@ func (q *fakeQuerier) UpdateWorkspaceAgentLifecycleStateByID(_ context.Context,
 	for i, agent := range q.workspaceAgents {
 		if agent.ID == arg.ID {
 			agent.LifecycleState = arg.LifecycleState
+			agent.StartedAt = arg.StartedAt
+			agent.ReadyAt = arg.ReadyAt
 			q.workspaceAgents[i] = agent
 			return nil
 		}
@@ -5097,6 +5625,21 @@ func (q *fakeQuerier) GetWorkspaceProxyByID(_ context.Context, id uuid.UUID) (da
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) GetWorkspaceProxyByName(_ context.Context, name string) (database.WorkspaceProxy, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, proxy := range q.workspaceProxies {
+		if proxy.Deleted {
+			continue
+		}
+		if proxy.Name == name {
+			return proxy, nil
+		}
+	}
+	return database.WorkspaceProxy{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) GetWorkspaceProxyByHostname(_ context.Context, hostname string) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5161,12 +5704,22 @@ func (q *fakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.Updat
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	for _, p := range q.workspaceProxies {
+		if !p.Deleted && p.Name == arg.Name && p.ID != arg.ID {
+			return database.WorkspaceProxy{}, errDuplicateKey
+		}
+	}
+
 	for i, p := range q.workspaceProxies {
 		if p.ID == arg.ID {
 			p.Name = arg.Name
+			p.DisplayName = arg.DisplayName
 			p.Icon = arg.Icon
 			p.Url = arg.Url
 			p.WildcardHostname = arg.WildcardHostname
+			if len(arg.TokenHashedSecret) > 0 {
+				p.TokenHashedSecret = arg.TokenHashedSecret
+			}
 			p.UpdatedAt = database.Now()
 			q.workspaceProxies[i] = p
 			return p, nil
@@ -5175,6 +5728,36 @@ func (q *fakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.Updat
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, p := range q.workspaceProxies {
+		if p.ID == arg.ID {
+			p.Version = arg.Version
+			p.LastHeartbeatAt = sql.NullTime{Time: database.Now(), Valid: true}
+			q.workspaceProxies[i] = p
+			return p, nil
+		}
+	}
+	return database.WorkspaceProxy{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceProxyHealth(_ context.Context, arg database.UpdateWorkspaceProxyHealthParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, p := range q.workspaceProxies {
+		if p.ID == arg.ID {
+			p.HealthCheckedAt = arg.HealthCheckedAt
+			p.HealthError = arg.HealthError
+			q.workspaceProxies[i] = p
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg database.UpdateWorkspaceProxyDeletedParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5189,3 +5772,584 @@ func (q *fakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	}
 	return sql.ErrNoRows
 }
+
+func (q *fakeQuerier) InsertWebhook(_ context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, w := range q.webhooks {
+		if w.Name == arg.Name {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	w := database.Webhook{
+		ID:        arg.ID,
+		Name:      arg.Name,
+		Url:       arg.Url,
+		Secret:    arg.Secret,
+		Events:    arg.Events,
+		Enabled:   arg.Enabled,
+		CreatedBy: arg.CreatedBy,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	q.webhooks = append(q.webhooks, w)
+	return w, nil
+}
+
+func (q *fakeQuerier) UpdateWebhookByID(_ context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, w := range q.webhooks {
+		if w.ID != arg.ID && w.Name == arg.Name {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+	for i, w := range q.webhooks {
+		if w.ID == arg.ID {
+			w.Name = arg.Name
+			w.Url = arg.Url
+			w.Events = arg.Events
+			w.Enabled = arg.Enabled
+			w.UpdatedAt = arg.UpdatedAt
+			q.webhooks[i] = w
+			return w, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteWebhookByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, w := range q.webhooks {
+		if w.ID == id {
+			q.webhooks = append(q.webhooks[:i], q.webhooks[i+1:]...)
+			// Deliveries are removed with ON DELETE CASCADE.
+			deliveries := make([]database.WebhookDelivery, 0, len(q.webhookDeliveries))
+			for _, d := range q.webhookDeliveries {
+				if d.WebhookID != id {
+					deliveries = append(deliveries, d)
+				}
+			}
+			q.webhookDeliveries = deliveries
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWebhookByID(_ context.Context, id uuid.UUID) (database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, w := range q.webhooks {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWebhooks(_ context.Context) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := slices.Clone(q.webhooks)
+	slices.SortFunc(webhooks, func(a, b database.Webhook) bool {
+		return a.Name < b.Name
+	})
+	return webhooks, nil
+}
+
+func (q *fakeQuerier) GetEnabledWebhooksByEvent(_ context.Context, event database.WebhookEvent) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := make([]database.Webhook, 0)
+	for _, w := range q.webhooks {
+		if w.Enabled && slices.Contains(w.Events, event) {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (q *fakeQuerier) InsertWebhookDelivery(_ context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	d := database.WebhookDelivery{
+		ID:            arg.ID,
+		WebhookID:     arg.WebhookID,
+		Event:         arg.Event,
+		Payload:       arg.Payload,
+		CreatedAt:     arg.CreatedAt,
+		NextAttemptAt: arg.NextAttemptAt,
+	}
+	q.webhookDeliveries = append(q.webhookDeliveries, d)
+	return d, nil
+}
+
+func (q *fakeQuerier) GetWebhookDeliveriesByWebhookID(_ context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deliveries := make([]database.WebhookDelivery, 0)
+	for _, d := range q.webhookDeliveries {
+		if d.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	slices.SortFunc(deliveries, func(a, b database.WebhookDelivery) bool {
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	if arg.LimitOpt > 0 && len(deliveries) > int(arg.LimitOpt) {
+		deliveries = deliveries[:arg.LimitOpt]
+	}
+	return deliveries, nil
+}
+
+func (q *fakeQuerier) AcquireWebhookDelivery(_ context.Context, arg database.AcquireWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	idx := -1
+	for i, d := range q.webhookDeliveries {
+		if !d.NextAttemptAt.Valid || d.NextAttemptAt.Time.After(arg.Now) {
+			continue
+		}
+		if idx == -1 || d.CreatedAt.Before(q.webhookDeliveries[idx].CreatedAt) {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return database.WebhookDelivery{}, sql.ErrNoRows
+	}
+	q.webhookDeliveries[idx].NextAttemptAt = sql.NullTime{Time: arg.LeaseUntil, Valid: true}
+	return q.webhookDeliveries[idx], nil
+}
+
+func (q *fakeQuerier) UpdateWebhookDeliveryByID(_ context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, d := range q.webhookDeliveries {
+		if d.ID == arg.ID {
+			d.Attempts = arg.Attempts
+			d.NextAttemptAt = arg.NextAttemptAt
+			d.DeliveredAt = arg.DeliveredAt
+			d.LastStatusCode = arg.LastStatusCode
+			d.LastError = arg.LastError
+			q.webhookDeliveries[i] = d
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteOldWebhookDeliveries(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	before := database.Now().Add(-30 * 24 * time.Hour)
+	deliveries := make([]database.WebhookDelivery, 0, len(q.webhookDeliveries))
+	for _, d := range q.webhookDeliveries {
+		if !d.CreatedAt.Before(before) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	q.webhookDeliveries = deliveries
+	return nil
+}
+
+func (q *fakeQuerier) CustomRoles(_ context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	found := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if len(arg.LookupRoles) > 0 {
+			if !slices.ContainsFunc(arg.LookupRoles, func(name string) bool {
+				return strings.EqualFold(name, role.RoleName())
+			}) {
+				continue
+			}
+		}
+		if arg.ExcludeOrgRoles && role.OrganizationID.Valid {
+			continue
+		}
+		if arg.OrganizationID != uuid.Nil && role.OrganizationID.UUID != arg.OrganizationID {
+			continue
+		}
+		found = append(found, role)
+	}
+	slices.SortFunc(found, func(a, b database.CustomRole) bool {
+		return a.Name < b.Name
+	})
+	return found, nil
+}
+
+func (q *fakeQuerier) InsertCustomRole(_ context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, role := range q.customRoles {
+		if strings.EqualFold(role.Name, arg.Name) && role.OrganizationID == arg.OrganizationID {
+			return database.CustomRole{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	role := database.CustomRole{
+		ID:              arg.ID,
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
+func (q *fakeQuerier) UpdateCustomRole(_ context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if strings.EqualFold(role.Name, arg.Name) && role.OrganizationID == arg.OrganizationID {
+			role.DisplayName = arg.DisplayName
+			role.SitePermissions = arg.SitePermissions
+			role.OrgPermissions = arg.OrgPermissions
+			role.UserPermissions = arg.UserPermissions
+			role.UpdatedAt = arg.UpdatedAt
+			q.customRoles[i] = role
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			return share, nil
+		}
+	}
+	return database.WorkspaceAgentPortShare{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) ListWorkspaceAgentPortShares(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	shares := make([]database.WorkspaceAgentPortShare, 0)
+	for _, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == workspaceID {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].AgentName != shares[j].AgentName {
+			return shares[i].AgentName < shares[j].AgentName
+		}
+		return shares[i].Port < shares[j].Port
+	})
+	return shares, nil
+}
+
+func (q *fakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	share := database.WorkspaceAgentPortShare{
+		WorkspaceID: arg.WorkspaceID,
+		AgentName:   arg.AgentName,
+		Port:        arg.Port,
+		ShareLevel:  arg.ShareLevel,
+		Protocol:    arg.Protocol,
+	}
+	for i, existing := range q.workspaceAgentPortShares {
+		if existing.WorkspaceID == arg.WorkspaceID && existing.AgentName == arg.AgentName && existing.Port == arg.Port {
+			q.workspaceAgentPortShares[i] = share
+			return share, nil
+		}
+	}
+	q.workspaceAgentPortShares = append(q.workspaceAgentPortShares, share)
+	return share, nil
+}
+
+func (q *fakeQuerier) DeleteWorkspaceAgentPortShare(_ context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			q.workspaceAgentPortShares = append(q.workspaceAgentPortShares[:i], q.workspaceAgentPortShares[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) InsertWorkspaceAgentScript(_ context.Context, arg database.InsertWorkspaceAgentScriptParams) (database.WorkspaceAgentScript, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	script := database.WorkspaceAgentScript{
+		ID:               arg.ID,
+		WorkspaceAgentID: arg.WorkspaceAgentID,
+		CreatedAt:        arg.CreatedAt,
+		DisplayName:      arg.DisplayName,
+		Script:           arg.Script,
+		Cron:             arg.Cron,
+		RunOrder:         arg.RunOrder,
+		StartBlocksLogin: arg.StartBlocksLogin,
+		RunOnStart:       arg.RunOnStart,
+		RunOnStop:        arg.RunOnStop,
+		TimeoutSeconds:   arg.TimeoutSeconds,
+		LogPath:          arg.LogPath,
+		Status:           database.WorkspaceAgentScriptStatusPending,
+	}
+	q.workspaceAgentScripts = append(q.workspaceAgentScripts, script)
+	return script, nil
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	scripts := make([]database.WorkspaceAgentScript, 0)
+	for _, script := range q.workspaceAgentScripts {
+		if slices.Contains(ids, script.WorkspaceAgentID) {
+			scripts = append(scripts, script)
+		}
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		if scripts[i].RunOrder != scripts[j].RunOrder {
+			return scripts[i].RunOrder < scripts[j].RunOrder
+		}
+		return scripts[i].DisplayName < scripts[j].DisplayName
+	})
+	return scripts, nil
+}
+
+func (q *fakeQuerier) UpdateWorkspaceAgentScriptStatus(_ context.Context, arg database.UpdateWorkspaceAgentScriptStatusParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, script := range q.workspaceAgentScripts {
+		if script.ID != arg.ID || script.WorkspaceAgentID != arg.WorkspaceAgentID {
+			continue
+		}
+		script.Status = arg.Status
+		script.ExitCode = arg.ExitCode
+		script.StartedAt = arg.StartedAt
+		script.CompletedAt = arg.CompletedAt
+		q.workspaceAgentScripts[i] = script
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentResourceStatsAndLabels(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentResourceStatsAndLabelsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	latestAgentStats := map[uuid.UUID]database.WorkspaceAgentStat{}
+	for _, agentStat := range q.workspaceAgentStats {
+		if !agentStat.CreatedAt.After(createdAfter) || agentStat.MemoryLimitBytes <= 0 {
+			continue
+		}
+		if latest, ok := latestAgentStats[agentStat.AgentID]; ok && latest.CreatedAt.After(agentStat.CreatedAt) {
+			continue
+		}
+		latestAgentStats[agentStat.AgentID] = agentStat
+	}
+
+	stats := make([]database.GetWorkspaceAgentResourceStatsAndLabelsRow, 0, len(latestAgentStats))
+	for _, agentStat := range latestAgentStats {
+		user, err := q.getUserByIDNoLock(agentStat.UserID)
+		if err != nil {
+			return nil, err
+		}
+		workspace, err := q.GetWorkspaceByID(ctx, agentStat.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		agent, err := q.GetWorkspaceAgentByID(ctx, agentStat.AgentID)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, database.GetWorkspaceAgentResourceStatsAndLabelsRow{
+			Username:         user.Username,
+			AgentName:        agent.Name,
+			WorkspaceName:    workspace.Name,
+			CPUUsedCores:     agentStat.CPUUsedCores,
+			CPULimitCores:    agentStat.CPULimitCores,
+			MemoryUsedBytes:  agentStat.MemoryUsedBytes,
+			MemoryLimitBytes: agentStat.MemoryLimitBytes,
+			DiskUsedBytes:    agentStat.DiskUsedBytes,
+			DiskTotalBytes:   agentStat.DiskTotalBytes,
+			NetworkRxBytes:   agentStat.NetworkRxBytes,
+			NetworkTxBytes:   agentStat.NetworkTxBytes,
+		})
+	}
+	return stats, nil
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentDebugLogsAfter(_ context.Context, arg database.GetWorkspaceAgentDebugLogsAfterParams) ([]database.WorkspaceAgentDebugLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := []database.WorkspaceAgentDebugLog{}
+	for _, log := range q.workspaceAgentDebugLogs {
+		if log.AgentID != arg.AgentID {
+			continue
+		}
+		if log.ID <= arg.CreatedAfter {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func (q *fakeQuerier) InsertWorkspaceAgentDebugLogs(_ context.Context, arg database.InsertWorkspaceAgentDebugLogsParams) ([]database.WorkspaceAgentDebugLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	id := int64(0)
+	if len(q.workspaceAgentDebugLogs) > 0 {
+		id = q.workspaceAgentDebugLogs[len(q.workspaceAgentDebugLogs)-1].ID
+	}
+	logs := []database.WorkspaceAgentDebugLog{}
+	for index, output := range arg.Output {
+		id++
+		logs = append(logs, database.WorkspaceAgentDebugLog{
+			ID:        id,
+			AgentID:   arg.AgentID,
+			CreatedAt: arg.CreatedAt[index],
+			Level:     arg.Level[index],
+			Output:    output,
+		})
+	}
+
+	// Only the most recent 10,000 logs of an agent are kept, same as the
+	// PostgreSQL query!
+	var existing int
+	for _, log := range q.workspaceAgentDebugLogs {
+		if log.AgentID == arg.AgentID {
+			existing++
+		}
+	}
+	remove := existing + len(logs) - 10000
+	kept := make([]database.WorkspaceAgentDebugLog, 0, len(q.workspaceAgentDebugLogs)+len(logs))
+	for _, log := range q.workspaceAgentDebugLogs {
+		if log.AgentID == arg.AgentID && remove > 0 {
+			remove--
+			continue
+		}
+		kept = append(kept, log)
+	}
+	q.workspaceAgentDebugLogs = append(kept, logs...)
+	return logs, nil
+}
+
+func (*fakeQuerier) DeleteOldWorkspaceAgentDebugLogs(_ context.Context) error {
+	// noop
+	return nil
+}
+
+func (q *fakeQuerier) GetSSHCAKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.sshCAKey == "" {
+		return "", sql.ErrNoRows
+	}
+	return q.sshCAKey, nil
+}
+
+func (q *fakeQuerier) UpsertSSHCAKey(_ context.Context, data string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.sshCAKey = data
+	return nil
+}
@@ -143,6 +143,7 @@ func WorkspaceAgent(t testing.TB, db database.Store, orig database.WorkspaceAgen
 		MOTDFile:                    takeFirst(orig.TroubleshootingURL, ""),
 		LoginBeforeReady:            takeFirst(orig.LoginBeforeReady, false),
 		StartupScriptTimeoutSeconds: takeFirst(orig.StartupScriptTimeoutSeconds, 3600),
+		ParentID:                    orig.ParentID,
 	})
 	require.NoError(t, err, "insert workspace agent")
 	return workspace
@@ -160,6 +161,7 @@ func Workspace(t testing.TB, db database.Store, orig database.Workspace) databas
 		Name:              takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
 		AutostartSchedule: orig.AutostartSchedule,
 		Ttl:               orig.Ttl,
+		AutomaticUpdates:  takeFirst(orig.AutomaticUpdates, database.AutomaticUpdatesNever),
 	})
 	require.NoError(t, err, "insert workspace")
 	return workspace
@@ -295,18 +297,42 @@ func WorkspaceApp(t testing.TB, db database.Store, orig database.WorkspaceApp) d
 			String: takeFirst(orig.Url.String),
 			Valid:  orig.Url.Valid,
 		},
-		External:             orig.External,
-		Subdomain:            orig.Subdomain,
-		SharingLevel:         takeFirst(orig.SharingLevel, database.AppSharingLevelOwner),
-		HealthcheckUrl:       takeFirst(orig.HealthcheckUrl, "https://localhost:8000"),
-		HealthcheckInterval:  takeFirst(orig.HealthcheckInterval, 60),
-		HealthcheckThreshold: takeFirst(orig.HealthcheckThreshold, 60),
-		Health:               takeFirst(orig.Health, database.WorkspaceAppHealthHealthy),
+		External:                     orig.External,
+		Subdomain:                    orig.Subdomain,
+		SharingLevel:                 takeFirst(orig.SharingLevel, database.AppSharingLevelOwner),
+		HealthcheckUrl:               takeFirst(orig.HealthcheckUrl, "https://localhost:8000"),
+		HealthcheckInterval:          takeFirst(orig.HealthcheckInterval, 60),
+		HealthcheckThreshold:         takeFirst(orig.HealthcheckThreshold, 60),
+		HealthcheckType:              takeFirst(orig.HealthcheckType, database.WorkspaceAppHealthcheckTypeHTTP),
+		HealthcheckAddress:           orig.HealthcheckAddress,
+		HealthcheckCommand:           orig.HealthcheckCommand,
+		HealthcheckExpectedExitCodes: takeFirstSlice(orig.HealthcheckExpectedExitCodes, []int32{}),
+		HealthcheckOutputRegex:       orig.HealthcheckOutputRegex,
+		Health:                       takeFirst(orig.Health, database.WorkspaceAppHealthHealthy),
 	})
 	require.NoError(t, err, "insert app")
 	return resource
 }
 
+func WorkspaceAgentScript(t testing.TB, db database.Store, orig database.WorkspaceAgentScript) database.WorkspaceAgentScript {
+	script, err := db.InsertWorkspaceAgentScript(context.Background(), database.InsertWorkspaceAgentScriptParams{
+		ID:               takeFirst(orig.ID, uuid.New()),
+		WorkspaceAgentID: takeFirst(orig.WorkspaceAgentID, uuid.New()),
+		CreatedAt:        takeFirst(orig.CreatedAt, database.Now()),
+		DisplayName:      takeFirst(orig.DisplayName, namesgenerator.GetRandomName(1)),
+		Script:           takeFirst(orig.Script, "true"),
+		Cron:             orig.Cron,
+		RunOrder:         orig.RunOrder,
+		StartBlocksLogin: orig.StartBlocksLogin,
+		RunOnStart:       takeFirst(orig.RunOnStart, true),
+		RunOnStop:        orig.RunOnStop,
+		TimeoutSeconds:   orig.TimeoutSeconds,
+		LogPath:          orig.LogPath,
+	})
+	require.NoError(t, err, "insert agent script")
+	return script
+}
+
 func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceResource) database.WorkspaceResource {
 	resource, err := db.InsertWorkspaceResource(context.Background(), database.InsertWorkspaceResourceParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -358,6 +384,75 @@ func WorkspaceProxy(t testing.TB, db database.Store, orig database.WorkspaceProx
 	return resource, secret
 }
 
+func ProvisionerKey(t testing.TB, db database.Store, orig database.ProvisionerKey) (database.ProvisionerKey, string) {
+	secret, err := cryptorand.HexString(64)
+	require.NoError(t, err, "generate secret")
+	hashedSecret := sha256.Sum256([]byte(secret))
+
+	tags := orig.Tags
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	key, err := db.InsertProvisionerKey(context.Background(), database.InsertProvisionerKeyParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		CreatedAt:      takeFirst(orig.CreatedAt, database.Now()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		Name:           takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		HashedSecret:   hashedSecret[:],
+		Tags:           tags,
+	})
+	require.NoError(t, err, "insert provisioner key")
+	return key, secret
+}
+
+func Webhook(t testing.TB, db database.Store, orig database.Webhook) database.Webhook {
+	secret, err := cryptorand.HexString(32)
+	require.NoError(t, err, "generate secret")
+
+	webhook, err := db.InsertWebhook(context.Background(), database.InsertWebhookParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		Name:      takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		Url:       takeFirst(orig.Url, fmt.Sprintf("https://%s.com/hook", namesgenerator.GetRandomName(1))),
+		Secret:    takeFirst(orig.Secret, secret),
+		Events:    takeFirstSlice(orig.Events, []database.WebhookEvent{database.WebhookEventWorkspaceBuildStarted}),
+		Enabled:   takeFirst(orig.Enabled, true),
+		CreatedBy: takeFirst(orig.CreatedBy, uuid.New()),
+		CreatedAt: takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt: takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert webhook")
+	return webhook
+}
+
+func CustomRole(t testing.TB, db database.Store, orig database.CustomRole) database.CustomRole {
+	role, err := db.InsertCustomRole(context.Background(), database.InsertCustomRoleParams{
+		ID:              takeFirst(orig.ID, uuid.New()),
+		Name:            takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		DisplayName:     orig.DisplayName,
+		OrganizationID:  orig.OrganizationID,
+		SitePermissions: takeFirstSlice(orig.SitePermissions, database.CustomRolePermissions{}),
+		OrgPermissions:  takeFirstSlice(orig.OrgPermissions, database.CustomRolePermissions{}),
+		UserPermissions: takeFirstSlice(orig.UserPermissions, database.CustomRolePermissions{}),
+		CreatedAt:       takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt:       takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert custom role")
+	return role
+}
+
+func WebhookDelivery(t testing.TB, db database.Store, orig database.WebhookDelivery) database.WebhookDelivery {
+	delivery, err := db.InsertWebhookDelivery(context.Background(), database.InsertWebhookDeliveryParams{
+		ID:            takeFirst(orig.ID, uuid.New()),
+		WebhookID:     takeFirst(orig.WebhookID, uuid.New()),
+		Event:         takeFirst(orig.Event, database.WebhookEventWorkspaceBuildStarted),
+		Payload:       takeFirstSlice(orig.Payload, json.RawMessage("{}")),
+		CreatedAt:     takeFirst(orig.CreatedAt, database.Now()),
+		NextAttemptAt: takeFirst(orig.NextAttemptAt, sql.NullTime{Time: database.Now(), Valid: true}),
+	})
+	require.NoError(t, err, "insert webhook delivery")
+	return delivery
+}
+
 func File(t testing.TB, db database.Store, orig database.File) database.File {
 	file, err := db.InsertFile(context.Background(), database.InsertFileParams{
 		ID:        takeFirst(orig.ID, uuid.New()),
@@ -493,6 +588,14 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 		SessionCountReconnectingPTY: takeFirst(orig.SessionCountReconnectingPTY, 0),
 		SessionCountSSH:             takeFirst(orig.SessionCountSSH, 0),
 		ConnectionMedianLatencyMS:   takeFirst(orig.ConnectionMedianLatencyMS, 0),
+		CPUUsedCores:                takeFirst(orig.CPUUsedCores, 0),
+		CPULimitCores:               takeFirst(orig.CPULimitCores, 0),
+		MemoryUsedBytes:             takeFirst(orig.MemoryUsedBytes, 0),
+		MemoryLimitBytes:            takeFirst(orig.MemoryLimitBytes, 0),
+		DiskUsedBytes:               takeFirst(orig.DiskUsedBytes, 0),
+		DiskTotalBytes:              takeFirst(orig.DiskTotalBytes, 0),
+		NetworkRxBytes:              takeFirst(orig.NetworkRxBytes, 0),
+		NetworkTxBytes:              takeFirst(orig.NetworkTxBytes, 0),
 	})
 	require.NoError(t, err, "insert workspace agent stat")
 	return scheme
@@ -83,6 +83,14 @@ func TestGenerator(t *testing.T) {
 		require.Equal(t, exp, must(db.GetWorkspaceProxyByID(context.Background(), exp.ID)))
 	})
 
+	t.Run("ProvisionerKey", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		exp, secret := dbgen.ProvisionerKey(t, db, database.ProvisionerKey{})
+		require.Len(t, secret, 64)
+		require.Equal(t, exp, must(db.GetProvisionerKeyByID(context.Background(), exp.ID)))
+	})
+
 	t.Run("Job", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
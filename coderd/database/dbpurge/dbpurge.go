@@ -42,9 +42,15 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 			eg.Go(func() error {
 				return db.DeleteOldWorkspaceAgentStartupLogs(ctx)
 			})
+			eg.Go(func() error {
+				return db.DeleteOldWorkspaceAgentDebugLogs(ctx)
+			})
 			eg.Go(func() error {
 				return db.DeleteOldWorkspaceAgentStats(ctx)
 			})
+			eg.Go(func() error {
+				return db.DeleteOldWebhookDeliveries(ctx)
+			})
 			err := eg.Wait()
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
@@ -43,3 +43,27 @@ func (t *TemplateACL) Scan(src interface{}) error {
 func (t TemplateACL) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
+
+// CustomRolePermissions is a list of permissions granted by a custom role at
+// a single level (site, org or user).
+type CustomRolePermissions []rbac.Permission
+
+func (p *CustomRolePermissions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &p)
+	case []byte, json.RawMessage:
+		//nolint
+		return json.Unmarshal(v.([]byte), &p)
+	}
+
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (p CustomRolePermissions) Value() (driver.Value, error) {
+	if p == nil {
+		// Never store null, the column is NOT NULL.
+		return json.Marshal([]rbac.Permission{})
+	}
+	return json.Marshal(p)
+}
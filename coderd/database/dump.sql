@@ -19,13 +19,22 @@ CREATE TYPE audit_action AS ENUM (
     'stop',
     'login',
     'logout',
-    'register'
+    'register',
+    'connect'
+);
+
+CREATE TYPE automatic_updates AS ENUM (
+    'always',
+    'never'
 );
 
 CREATE TYPE build_reason AS ENUM (
     'initiator',
     'autostart',
-    'autostop'
+    'autostop',
+    'dormancy',
+    'autodelete',
+    'failedstop'
 );
 
 CREATE TYPE log_level AS ENUM (
@@ -70,6 +79,18 @@ CREATE TYPE parameter_type_system AS ENUM (
     'hcl'
 );
 
+CREATE TYPE port_share_protocol AS ENUM (
+    'http',
+    'https'
+);
+
+CREATE TYPE provisioner_job_timing_stage AS ENUM (
+    'init',
+    'plan',
+    'graph',
+    'apply'
+);
+
 CREATE TYPE provisioner_job_type AS ENUM (
     'template_version_import',
     'workspace_build',
@@ -96,7 +117,10 @@ CREATE TYPE resource_type AS ENUM (
     'group',
     'workspace_build',
     'license',
-    'workspace_proxy'
+    'workspace_proxy',
+    'webhook',
+    'custom_role',
+    'provisioner_key'
 );
 
 CREATE TYPE user_status AS ENUM (
@@ -104,6 +128,15 @@ CREATE TYPE user_status AS ENUM (
     'suspended'
 );
 
+CREATE TYPE webhook_event AS ENUM (
+    'workspace_build_started',
+    'workspace_build_succeeded',
+    'workspace_build_failed',
+    'template_version_promoted',
+    'user_created',
+    'user_suspended'
+);
+
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'created',
     'starting',
@@ -116,6 +149,14 @@ CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'off'
 );
 
+CREATE TYPE workspace_agent_script_status AS ENUM (
+    'pending',
+    'running',
+    'ok',
+    'error',
+    'timed_out'
+);
+
 CREATE TYPE workspace_app_health AS ENUM (
     'disabled',
     'initializing',
@@ -123,6 +164,12 @@ CREATE TYPE workspace_app_health AS ENUM (
     'unhealthy'
 );
 
+CREATE TYPE workspace_app_healthcheck_type AS ENUM (
+    'http',
+    'tcp',
+    'command'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -164,6 +211,22 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE custom_roles (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text DEFAULT ''::text NOT NULL,
+    organization_id uuid,
+    site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    org_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    user_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Custom roles allow dynamic roles expanded at runtime alongside the built-in roles.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'Roles scoped to an organization can only be assigned to members of that organization. NULL for site wide roles.';
+
 CREATE TABLE files (
     hash character varying(64) NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -237,9 +300,12 @@ CREATE TABLE organizations (
     name text NOT NULL,
     description text NOT NULL,
     created_at timestamp with time zone NOT NULL,
-    updated_at timestamp with time zone NOT NULL
+    updated_at timestamp with time zone NOT NULL,
+    quota_budget bigint DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN organizations.quota_budget IS 'The total daily cost of all workspaces in the organization. Builds that would exceed it are rejected. 0 means the organization has no budget.';
+
 CREATE TABLE parameter_schemas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -279,9 +345,14 @@ CREATE TABLE provisioner_daemons (
     name character varying(64) NOT NULL,
     provisioners provisioner_type[] NOT NULL,
     replica_id uuid,
-    tags jsonb DEFAULT '{}'::jsonb NOT NULL
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL,
+    last_seen_at timestamp with time zone,
+    version text DEFAULT ''::text NOT NULL,
+    key_id uuid
 );
 
+COMMENT ON COLUMN provisioner_daemons.key_id IS 'The provisioner key the daemon authenticated with. Daemons authenticated with a user session have no key.';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -301,6 +372,18 @@ CREATE SEQUENCE provisioner_job_logs_id_seq
 
 ALTER SEQUENCE provisioner_job_logs_id_seq OWNED BY provisioner_job_logs.id;
 
+CREATE TABLE provisioner_job_timings (
+    job_id uuid NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone NOT NULL,
+    stage provisioner_job_timing_stage NOT NULL,
+    source text NOT NULL,
+    action text NOT NULL,
+    resource text NOT NULL
+);
+
+COMMENT ON COLUMN provisioner_job_timings.resource IS 'The address of the resource the timing is for. It is empty for the timing of a whole stage.';
+
 CREATE TABLE provisioner_jobs (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -321,6 +404,19 @@ CREATE TABLE provisioner_jobs (
     error_code text
 );
 
+CREATE TABLE provisioner_keys (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    organization_id uuid NOT NULL,
+    name character varying(64) NOT NULL,
+    hashed_secret bytea NOT NULL,
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL
+);
+
+COMMENT ON COLUMN provisioner_keys.hashed_secret IS 'The SHA256 hash of the secret of the key.';
+
+COMMENT ON COLUMN provisioner_keys.tags IS 'Provisioner daemons authenticated with the key serve jobs with these tags.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -390,6 +486,19 @@ COMMENT ON COLUMN template_version_parameters.legacy_variable_name IS 'Name of t
 
 COMMENT ON COLUMN template_version_parameters.display_name IS 'Display name of the rich parameter';
 
+CREATE TABLE template_version_promotions (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    promoted_at timestamp with time zone NOT NULL,
+    promoted_by uuid,
+    rolled_back_at timestamp with time zone
+);
+
+COMMENT ON TABLE template_version_promotions IS 'Every time a version was made the active version of its template. Rollbacks walk back through the promotions that were not rolled back.';
+
+COMMENT ON COLUMN template_version_promotions.rolled_back_at IS 'The time the promotion was undone by rolling back to an earlier promotion.';
+
 CREATE TABLE template_version_variables (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -425,11 +534,17 @@ CREATE TABLE template_versions (
     readme character varying(1048576) NOT NULL,
     job_id uuid NOT NULL,
     created_by uuid NOT NULL,
-    git_auth_providers text[]
+    git_auth_providers text[],
+    promoted_at timestamp with time zone,
+    promoted_by uuid
 );
 
 COMMENT ON COLUMN template_versions.git_auth_providers IS 'IDs of Git auth providers for a specific template version';
 
+COMMENT ON COLUMN template_versions.promoted_at IS 'The last time the version was made the active version of its template.';
+
+COMMENT ON COLUMN template_versions.promoted_by IS 'The user that last made the version the active version of its template.';
+
 CREATE TABLE templates (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -449,7 +564,17 @@ CREATE TABLE templates (
     allow_user_cancel_workspace_jobs boolean DEFAULT true NOT NULL,
     max_ttl bigint DEFAULT '0'::bigint NOT NULL,
     allow_user_autostart boolean DEFAULT true NOT NULL,
-    allow_user_autostop boolean DEFAULT true NOT NULL
+    allow_user_autostop boolean DEFAULT true NOT NULL,
+    inactivity_ttl bigint DEFAULT 0 NOT NULL,
+    dormant_autodelete_ttl bigint DEFAULT 0 NOT NULL,
+    failure_ttl bigint DEFAULT 0 NOT NULL,
+    restart_requirement_days_of_week smallint DEFAULT 0 NOT NULL,
+    restart_requirement_weeks bigint DEFAULT 0 NOT NULL,
+    deprecated text DEFAULT ''::text NOT NULL,
+    require_active_version boolean DEFAULT false NOT NULL,
+    max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
+    session_recording_enabled boolean DEFAULT false NOT NULL,
+    quota_cost_multiplier double precision DEFAULT 1 NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -462,6 +587,26 @@ COMMENT ON COLUMN templates.allow_user_autostart IS 'Allow users to specify an a
 
 COMMENT ON COLUMN templates.allow_user_autostop IS 'Allow users to specify custom autostop values for workspaces (enterprise).';
 
+COMMENT ON COLUMN templates.inactivity_ttl IS 'The duration of inactivity after which workspaces created from this template are marked dormant (enterprise).';
+
+COMMENT ON COLUMN templates.dormant_autodelete_ttl IS 'The duration a workspace may remain dormant before it is automatically deleted (enterprise).';
+
+COMMENT ON COLUMN templates.failure_ttl IS 'The duration after which workspaces whose start build failed are automatically stopped (enterprise).';
+
+COMMENT ON COLUMN templates.restart_requirement_days_of_week IS 'A bitmap of days of week to restart the workspace on, starting with Monday as the 0th bit, and Sunday as the 6th bit. The 7th bit is unused.';
+
+COMMENT ON COLUMN templates.restart_requirement_weeks IS 'The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.';
+
+COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.';
+
+COMMENT ON COLUMN templates.require_active_version IS 'Whether workspaces created from this template must be built against the active version every time they start.';
+
+COMMENT ON COLUMN templates.max_port_sharing_level IS 'The highest share level that workspace owners can grant to ports of workspaces created from this template.';
+
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Whether interactive terminal sessions in workspaces created from this template are recorded and linked from the audit log.';
+
+COMMENT ON COLUMN templates.quota_cost_multiplier IS 'The daily cost of workspaces created from this template is multiplied by this factor before it is counted against quotas.';
+
 CREATE TABLE user_links (
     user_id uuid NOT NULL,
     login_type login_type NOT NULL,
@@ -483,9 +628,64 @@ CREATE TABLE users (
     login_type login_type DEFAULT 'password'::login_type NOT NULL,
     avatar_url text,
     deleted boolean DEFAULT false NOT NULL,
-    last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL
+    last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
+    quiet_hours_schedule text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON COLUMN users.quiet_hours_schedule IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
+
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    event webhook_event NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone,
+    delivered_at timestamp with time zone,
+    last_status_code integer DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery should next be attempted. NULL once the delivery succeeded or ran out of attempts.';
+
+COMMENT ON COLUMN webhook_deliveries.last_status_code IS 'HTTP status code of the last attempt, 0 if no response was received.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events webhook_event[] NOT NULL,
+    enabled boolean DEFAULT true NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN webhooks.secret IS 'Secret used to compute the HMAC-SHA256 signature sent with every delivery. This MUST NOT be returned from the API after creation.';
+
+COMMENT ON COLUMN webhooks.events IS 'Events the webhook is subscribed to.';
+
+CREATE TABLE workspace_agent_debug_logs (
+    agent_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    level log_level DEFAULT 'info'::log_level NOT NULL,
+    output character varying(4096) NOT NULL,
+    id bigint NOT NULL
 );
 
+COMMENT ON TABLE workspace_agent_debug_logs IS 'Logs of the agent itself, uploaded for troubleshooting. Only the most recent logs of each agent are kept.';
+
+CREATE SEQUENCE workspace_agent_debug_logs_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE workspace_agent_debug_logs_id_seq OWNED BY workspace_agent_debug_logs.id;
+
 CREATE UNLOGGED TABLE workspace_agent_metadata (
     workspace_agent_id uuid NOT NULL,
     display_name character varying(127) NOT NULL,
@@ -498,12 +698,46 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
     collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_agent_port_shares (
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    port integer NOT NULL,
+    share_level app_sharing_level NOT NULL,
+    protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_port_shares IS 'Ports listening in a workspace agent that the workspace owner has shared beyond themselves.';
+
+CREATE TABLE workspace_agent_scripts (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    display_name text NOT NULL,
+    script text NOT NULL,
+    cron text DEFAULT ''::text NOT NULL,
+    run_order integer DEFAULT 0 NOT NULL,
+    start_blocks_login boolean DEFAULT false NOT NULL,
+    run_on_start boolean DEFAULT false NOT NULL,
+    run_on_stop boolean DEFAULT false NOT NULL,
+    timeout_seconds integer DEFAULT 0 NOT NULL,
+    log_path text DEFAULT ''::text NOT NULL,
+    status workspace_agent_script_status DEFAULT 'pending'::workspace_agent_script_status NOT NULL,
+    exit_code integer DEFAULT 0 NOT NULL,
+    started_at timestamp with time zone,
+    completed_at timestamp with time zone
+);
+
+COMMENT ON COLUMN workspace_agent_scripts.run_order IS 'Scripts that run on start are started in ascending order. A script waits for the login-blocking scripts with a lower order to finish first.';
+
+COMMENT ON COLUMN workspace_agent_scripts.status IS 'The status of the latest run of the script, as reported by the agent.';
+
 CREATE TABLE workspace_agent_startup_logs (
     agent_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
     output character varying(1024) NOT NULL,
     id bigint NOT NULL,
-    level log_level DEFAULT 'info'::log_level NOT NULL
+    level log_level DEFAULT 'info'::log_level NOT NULL,
+    script_id uuid DEFAULT '00000000-0000-0000-0000-000000000000'::uuid NOT NULL
 );
 
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
@@ -532,9 +766,21 @@ CREATE TABLE workspace_agent_stats (
     session_count_vscode bigint DEFAULT 0 NOT NULL,
     session_count_jetbrains bigint DEFAULT 0 NOT NULL,
     session_count_reconnecting_pty bigint DEFAULT 0 NOT NULL,
-    session_count_ssh bigint DEFAULT 0 NOT NULL
+    session_count_ssh bigint DEFAULT 0 NOT NULL,
+    cpu_used_cores double precision DEFAULT 0 NOT NULL,
+    cpu_limit_cores double precision DEFAULT 0 NOT NULL,
+    memory_used_bytes bigint DEFAULT 0 NOT NULL,
+    memory_limit_bytes bigint DEFAULT 0 NOT NULL,
+    disk_used_bytes bigint DEFAULT 0 NOT NULL,
+    disk_total_bytes bigint DEFAULT 0 NOT NULL,
+    network_rx_bytes bigint DEFAULT 0 NOT NULL,
+    network_tx_bytes bigint DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN workspace_agent_stats.cpu_limit_cores IS 'The CPU limit of the container the agent runs in, or the number of host CPUs. Zero if the agent did not report resource usage.';
+
+COMMENT ON COLUMN workspace_agent_stats.memory_limit_bytes IS 'The memory limit of the container the agent runs in, or the total host memory. Zero if the agent did not report resource usage.';
+
 CREATE TABLE workspace_agents (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -566,6 +812,9 @@ CREATE TABLE workspace_agents (
     shutdown_script_timeout_seconds integer DEFAULT 0 NOT NULL,
     startup_logs_length integer DEFAULT 0 NOT NULL,
     startup_logs_overflowed boolean DEFAULT false NOT NULL,
+    started_at timestamp with time zone,
+    ready_at timestamp with time zone,
+    parent_id uuid,
     CONSTRAINT max_startup_logs_length CHECK ((startup_logs_length <= 1048576))
 );
 
@@ -593,6 +842,12 @@ COMMENT ON COLUMN workspace_agents.startup_logs_length IS 'Total length of start
 
 COMMENT ON COLUMN workspace_agents.startup_logs_overflowed IS 'Whether the startup logs overflowed in length';
 
+COMMENT ON COLUMN workspace_agents.started_at IS 'The time the agent entered the starting lifecycle state.';
+
+COMMENT ON COLUMN workspace_agents.ready_at IS 'The time the agent entered the ready or start_error lifecycle state.';
+
+COMMENT ON COLUMN workspace_agents.parent_id IS 'The agent that registered this agent for a dev container it runs. Agents of the template have no parent.';
+
 CREATE TABLE workspace_apps (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -608,9 +863,18 @@ CREATE TABLE workspace_apps (
     subdomain boolean DEFAULT false NOT NULL,
     sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
     slug text NOT NULL,
-    external boolean DEFAULT false NOT NULL
+    external boolean DEFAULT false NOT NULL,
+    healthcheck_type workspace_app_healthcheck_type DEFAULT 'http'::workspace_app_healthcheck_type NOT NULL,
+    healthcheck_address text DEFAULT ''::text NOT NULL,
+    healthcheck_command text DEFAULT ''::text NOT NULL,
+    healthcheck_expected_exit_codes integer[] DEFAULT '{}'::integer[] NOT NULL,
+    healthcheck_output_regex text DEFAULT ''::text NOT NULL
 );
 
+COMMENT ON COLUMN workspace_apps.healthcheck_address IS 'The host:port to connect to for tcp health checks.';
+
+COMMENT ON COLUMN workspace_apps.healthcheck_expected_exit_codes IS 'The exit codes of a healthy command health check. Empty means only 0.';
+
 CREATE TABLE workspace_build_parameters (
     workspace_build_id uuid NOT NULL,
     name text NOT NULL,
@@ -648,7 +912,11 @@ CREATE TABLE workspace_proxies (
     created_at timestamp with time zone NOT NULL,
     updated_at timestamp with time zone NOT NULL,
     deleted boolean NOT NULL,
-    token_hashed_secret bytea NOT NULL
+    token_hashed_secret bytea NOT NULL,
+    version text DEFAULT ''::text NOT NULL,
+    last_heartbeat_at timestamp with time zone,
+    health_checked_at timestamp with time zone,
+    health_error text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN workspace_proxies.icon IS 'Expects an emoji character. (/emojis/1f1fa-1f1f8.png)';
@@ -661,6 +929,14 @@ COMMENT ON COLUMN workspace_proxies.deleted IS 'Boolean indicator of a deleted w
 
 COMMENT ON COLUMN workspace_proxies.token_hashed_secret IS 'Hashed secret is used to authenticate the workspace proxy using a session token.';
 
+COMMENT ON COLUMN workspace_proxies.version IS 'Version of the proxy, as reported by the proxy when it registers.';
+
+COMMENT ON COLUMN workspace_proxies.last_heartbeat_at IS 'Last time the proxy registered with coderd. Proxies register periodically while they run. NULL if the proxy never registered.';
+
+COMMENT ON COLUMN workspace_proxies.health_checked_at IS 'Last time coderd checked that the proxy is reachable at its url.';
+
+COMMENT ON COLUMN workspace_proxies.health_error IS 'Error of the last reachability check, empty if the proxy was reachable.';
+
 CREATE TABLE workspace_resource_metadata (
     workspace_resource_id uuid NOT NULL,
     key character varying(1024) NOT NULL,
@@ -702,13 +978,21 @@ CREATE TABLE workspaces (
     name character varying(64) NOT NULL,
     autostart_schedule text,
     ttl bigint,
-    last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL
+    last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
+    dormant_at timestamp with time zone,
+    automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.dormant_at IS 'The time at which the workspace was marked dormant due to inactivity. Dormant workspaces cannot be started until they are explicitly made active again.';
+
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Whether the workspace is built against the active version of its template every time it starts.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
 
+ALTER TABLE ONLY workspace_agent_debug_logs ALTER COLUMN id SET DEFAULT nextval('workspace_agent_debug_logs_id_seq'::regclass);
+
 ALTER TABLE ONLY workspace_agent_startup_logs ALTER COLUMN id SET DEFAULT nextval('workspace_agent_startup_logs_id_seq'::regclass);
 
 ALTER TABLE ONLY workspace_resource_metadata ALTER COLUMN id SET DEFAULT nextval('workspace_resource_metadata_id_seq'::regclass);
@@ -722,6 +1006,9 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY files
     ADD CONSTRAINT files_hash_created_by_key UNIQUE (hash, created_by);
 
@@ -779,12 +1066,18 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_keys
+    ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -803,9 +1096,27 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_name_key UNIQUE (name);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_debug_logs
+    ADD CONSTRAINT workspace_agent_debug_logs_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_port_shares
+    ADD CONSTRAINT workspace_agent_port_shares_pkey PRIMARY KEY (workspace_id, agent_name, port);
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_startup_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -861,6 +1172,8 @@ CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
+CREATE UNIQUE INDEX idx_custom_roles_name_lower_organization_id ON custom_roles USING btree (lower(name), COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
 
 CREATE INDEX idx_organization_member_user_id_uuid ON organization_members USING btree (user_id);
@@ -875,14 +1188,28 @@ CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (de
 
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
+CREATE INDEX provisioner_job_timings_job_id_idx ON provisioner_job_timings USING btree (job_id);
+
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
+CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+
+CREATE INDEX template_version_promotions_template_id_promoted_at_idx ON template_version_promotions USING btree (template_id, promoted_at DESC);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
+CREATE INDEX workspace_agent_debug_logs_id_agent_id_idx ON workspace_agent_debug_logs USING btree (agent_id, id);
+
+CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
+
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_startup_logs USING btree (agent_id, id);
 
 CREATE INDEX workspace_agents_auth_token_idx ON workspace_agents USING btree (auth_token);
@@ -898,6 +1225,9 @@ CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (own
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
@@ -919,15 +1249,33 @@ ALTER TABLE ONLY organization_members
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_daemons
+    ADD CONSTRAINT provisioner_daemons_key_id_fkey FOREIGN KEY (key_id) REFERENCES provisioner_keys(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_job_timings
+    ADD CONSTRAINT provisioner_job_timings_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_keys
+    ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_promoted_by_fkey FOREIGN KEY (promoted_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -937,6 +1285,9 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY template_versions
     ADD CONSTRAINT template_versions_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_versions
+    ADD CONSTRAINT template_versions_promoted_by_fkey FOREIGN KEY (promoted_by) REFERENCES users(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY template_versions
     ADD CONSTRAINT template_versions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 
@@ -949,12 +1300,30 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY workspace_agent_debug_logs
+    ADD CONSTRAINT workspace_agent_debug_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_port_shares
+    ADD CONSTRAINT workspace_agent_port_shares_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_startup_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agents
+    ADD CONSTRAINT workspace_agents_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_resource_id_fkey FOREIGN KEY (resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
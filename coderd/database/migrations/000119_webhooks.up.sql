@@ -0,0 +1,49 @@
+BEGIN;
+
+CREATE TYPE webhook_event AS ENUM (
+	'workspace_build_started',
+	'workspace_build_succeeded',
+	'workspace_build_failed',
+	'template_version_promoted',
+	'user_created',
+	'user_suspended'
+);
+
+CREATE TABLE webhooks (
+	id uuid NOT NULL,
+	name text NOT NULL,
+	url text NOT NULL,
+	secret text NOT NULL,
+	events webhook_event[] NOT NULL,
+	enabled boolean NOT NULL DEFAULT TRUE,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (name)
+);
+
+COMMENT ON COLUMN webhooks.secret IS 'Secret used to compute the HMAC-SHA256 signature sent with every delivery. This MUST NOT be returned from the API after creation.';
+COMMENT ON COLUMN webhooks.events IS 'Events the webhook is subscribed to.';
+
+CREATE TABLE webhook_deliveries (
+	id uuid NOT NULL,
+	webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	event webhook_event NOT NULL,
+	payload jsonb NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamp with time zone,
+	delivered_at timestamp with time zone,
+	last_status_code integer NOT NULL DEFAULT 0,
+	last_error text NOT NULL DEFAULT '',
+	PRIMARY KEY (id)
+);
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery should next be attempted. NULL once the delivery succeeded or ran out of attempts.';
+COMMENT ON COLUMN webhook_deliveries.last_status_code IS 'HTTP status code of the last attempt, 0 if no response was received.';
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE next_attempt_at IS NOT NULL;
+
+COMMIT;
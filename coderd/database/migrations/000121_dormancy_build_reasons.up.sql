@@ -0,0 +1,4 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'dormancy';
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'autodelete';
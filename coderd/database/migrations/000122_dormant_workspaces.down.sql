@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE workspaces
+	DROP COLUMN dormant_at;
+
+ALTER TABLE templates
+	DROP COLUMN dormant_autodelete_ttl,
+	DROP COLUMN inactivity_ttl;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE templates
+	ADD COLUMN inactivity_ttl bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN dormant_autodelete_ttl bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.inactivity_ttl
+	IS 'The duration of inactivity after which workspaces created from this template are marked dormant (enterprise).';
+
+COMMENT ON COLUMN templates.dormant_autodelete_ttl
+	IS 'The duration a workspace may remain dormant before it is automatically deleted (enterprise).';
+
+ALTER TABLE workspaces
+	ADD COLUMN dormant_at timestamp with time zone NULL;
+
+COMMENT ON COLUMN workspaces.dormant_at
+	IS 'The time at which the workspace was marked dormant due to inactivity. Dormant workspaces cannot be started until they are explicitly made active again.';
+
+COMMIT;
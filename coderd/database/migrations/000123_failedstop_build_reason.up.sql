@@ -0,0 +1,3 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'failedstop';
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE templates
+	ADD COLUMN failure_ttl bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.failure_ttl
+	IS 'The duration after which workspaces whose start build failed are automatically stopped (enterprise).';
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE templates
+	ADD COLUMN restart_requirement_days_of_week smallint DEFAULT 0 NOT NULL,
+	ADD COLUMN restart_requirement_weeks bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.restart_requirement_days_of_week
+	IS 'A bitmap of days of week to restart the workspace on, starting with Monday as the 0th bit, and Sunday as the 6th bit. The 7th bit is unused.';
+
+COMMENT ON COLUMN templates.restart_requirement_weeks
+	IS 'The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.';
+
+ALTER TABLE users
+	ADD COLUMN quiet_hours_schedule text DEFAULT '' NOT NULL;
+
+COMMENT ON COLUMN users.quiet_hours_schedule
+	IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE custom_roles (
+	id uuid NOT NULL,
+	name text NOT NULL,
+	display_name text NOT NULL DEFAULT '',
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	site_permissions jsonb NOT NULL DEFAULT '[]',
+	org_permissions jsonb NOT NULL DEFAULT '[]',
+	user_permissions jsonb NOT NULL DEFAULT '[]',
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE custom_roles IS 'Custom roles allow dynamic roles expanded at runtime alongside the built-in roles.';
+COMMENT ON COLUMN custom_roles.organization_id IS 'Roles scoped to an organization can only be assigned to members of that organization. NULL for site wide roles.';
+
+-- Role names are unique per organization, and site wide roles share a single
+-- namespace.
+CREATE UNIQUE INDEX idx_custom_roles_name_lower_organization_id ON custom_roles USING btree (lower(name), COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+COMMIT;
@@ -0,0 +1,3 @@
+ALTER TABLE template_versions
+	DROP COLUMN promoted_at,
+	DROP COLUMN promoted_by;
@@ -0,0 +1,6 @@
+ALTER TABLE template_versions
+	ADD COLUMN promoted_at timestamp with time zone,
+	ADD COLUMN promoted_by uuid REFERENCES users (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN template_versions.promoted_at IS 'The last time the version was made the active version of its template.';
+COMMENT ON COLUMN template_versions.promoted_by IS 'The user that last made the version the active version of its template.';
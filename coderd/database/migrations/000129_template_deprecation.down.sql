@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE templates
+	DROP COLUMN deprecated;
+
+COMMIT;
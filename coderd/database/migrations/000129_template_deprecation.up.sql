@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE templates
+	ADD COLUMN deprecated text DEFAULT '' NOT NULL;
+
+COMMENT ON COLUMN templates.deprecated
+	IS 'If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.';
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE templates
+	DROP COLUMN require_active_version;
+
+ALTER TABLE workspaces
+	DROP COLUMN automatic_updates;
+
+DROP TYPE automatic_updates;
+
+COMMIT;
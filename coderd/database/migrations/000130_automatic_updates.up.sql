@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TYPE automatic_updates AS ENUM (
+	'always',
+	'never'
+);
+
+ALTER TABLE workspaces
+	ADD COLUMN automatic_updates automatic_updates NOT NULL DEFAULT 'never'::automatic_updates;
+
+COMMENT ON COLUMN workspaces.automatic_updates
+	IS 'Whether the workspace is built against the active version of its template every time it starts.';
+
+ALTER TABLE templates
+	ADD COLUMN require_active_version boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.require_active_version
+	IS 'Whether workspaces created from this template must be built against the active version every time they start.';
+
+COMMIT;
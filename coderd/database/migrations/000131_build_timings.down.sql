@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE workspace_agents
+	DROP COLUMN started_at,
+	DROP COLUMN ready_at;
+
+DROP TABLE provisioner_job_timings;
+
+DROP TYPE provisioner_job_timing_stage;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TYPE provisioner_job_timing_stage AS ENUM (
+	'init',
+	'plan',
+	'graph',
+	'apply'
+);
+
+CREATE TABLE provisioner_job_timings (
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL,
+	stage provisioner_job_timing_stage NOT NULL,
+	source text NOT NULL,
+	action text NOT NULL,
+	resource text NOT NULL
+);
+
+COMMENT ON COLUMN provisioner_job_timings.resource
+	IS 'The address of the resource the timing is for. It is empty for the timing of a whole stage.';
+
+CREATE INDEX provisioner_job_timings_job_id_idx ON provisioner_job_timings (job_id);
+
+ALTER TABLE workspace_agents
+	ADD COLUMN started_at timestamp with time zone,
+	ADD COLUMN ready_at timestamp with time zone;
+
+COMMENT ON COLUMN workspace_agents.started_at
+	IS 'The time the agent entered the starting lifecycle state.';
+
+COMMENT ON COLUMN workspace_agents.ready_at
+	IS 'The time the agent entered the ready or start_error lifecycle state.';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE templates
+	DROP COLUMN max_port_sharing_level;
+
+DROP TABLE workspace_agent_port_shares;
+
+DROP TYPE port_share_protocol;
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TYPE port_share_protocol AS ENUM (
+	'http',
+	'https'
+);
+
+CREATE TABLE workspace_agent_port_shares (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	share_level app_sharing_level NOT NULL,
+	protocol port_share_protocol NOT NULL DEFAULT 'http'::port_share_protocol,
+	PRIMARY KEY (workspace_id, agent_name, port)
+);
+
+COMMENT ON TABLE workspace_agent_port_shares
+	IS 'Ports listening in a workspace agent that the workspace owner has shared beyond themselves.';
+
+ALTER TABLE templates
+	ADD COLUMN max_port_sharing_level app_sharing_level NOT NULL DEFAULT 'owner'::app_sharing_level;
+
+COMMENT ON COLUMN templates.max_port_sharing_level
+	IS 'The highest share level that workspace owners can grant to ports of workspaces created from this template.';
+
+COMMIT;
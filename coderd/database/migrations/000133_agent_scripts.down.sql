@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_startup_logs
+	DROP COLUMN script_id;
+
+DROP TABLE workspace_agent_scripts;
+
+DROP TYPE workspace_agent_script_status;
+
+COMMIT;
@@ -0,0 +1,41 @@
+BEGIN;
+
+CREATE TYPE workspace_agent_script_status AS ENUM (
+	'pending',
+	'running',
+	'ok',
+	'error',
+	'timed_out'
+);
+
+CREATE TABLE workspace_agent_scripts (
+	id uuid PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	display_name text NOT NULL,
+	script text NOT NULL,
+	cron text NOT NULL DEFAULT '',
+	run_order integer NOT NULL DEFAULT 0,
+	start_blocks_login boolean NOT NULL DEFAULT false,
+	run_on_start boolean NOT NULL DEFAULT false,
+	run_on_stop boolean NOT NULL DEFAULT false,
+	timeout_seconds integer NOT NULL DEFAULT 0,
+	log_path text NOT NULL DEFAULT '',
+	status workspace_agent_script_status NOT NULL DEFAULT 'pending'::workspace_agent_script_status,
+	exit_code integer NOT NULL DEFAULT 0,
+	started_at timestamp with time zone,
+	completed_at timestamp with time zone
+);
+
+COMMENT ON COLUMN workspace_agent_scripts.run_order IS 'Scripts that run on start are started in ascending order. A script waits for the login-blocking scripts with a lower order to finish first.';
+
+COMMENT ON COLUMN workspace_agent_scripts.status IS 'The status of the latest run of the script, as reported by the agent.';
+
+CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
+
+-- Logs from before named scripts, such as the legacy startup script, use the
+-- nil UUID.
+ALTER TABLE workspace_agent_startup_logs
+	ADD COLUMN script_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'::uuid;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_stats
+	DROP COLUMN cpu_used_cores,
+	DROP COLUMN cpu_limit_cores,
+	DROP COLUMN memory_used_bytes,
+	DROP COLUMN memory_limit_bytes,
+	DROP COLUMN disk_used_bytes,
+	DROP COLUMN disk_total_bytes,
+	DROP COLUMN network_rx_bytes,
+	DROP COLUMN network_tx_bytes;
+
+COMMIT;
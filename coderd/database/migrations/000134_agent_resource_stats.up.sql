@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_stats
+	ADD COLUMN cpu_used_cores double precision DEFAULT 0 NOT NULL,
+	ADD COLUMN cpu_limit_cores double precision DEFAULT 0 NOT NULL,
+	ADD COLUMN memory_used_bytes bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN memory_limit_bytes bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN disk_used_bytes bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN disk_total_bytes bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN network_rx_bytes bigint DEFAULT 0 NOT NULL,
+	ADD COLUMN network_tx_bytes bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN workspace_agent_stats.cpu_limit_cores IS 'The CPU limit of the container the agent runs in, or the number of host CPUs. Zero if the agent did not report resource usage.';
+
+COMMENT ON COLUMN workspace_agent_stats.memory_limit_bytes IS 'The memory limit of the container the agent runs in, or the total host memory. Zero if the agent did not report resource usage.';
+
+COMMIT;
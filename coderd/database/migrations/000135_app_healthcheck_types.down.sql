@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE workspace_apps
+	DROP COLUMN healthcheck_type,
+	DROP COLUMN healthcheck_address,
+	DROP COLUMN healthcheck_command,
+	DROP COLUMN healthcheck_expected_exit_codes,
+	DROP COLUMN healthcheck_output_regex;
+
+DROP TYPE workspace_app_healthcheck_type;
+
+COMMIT;
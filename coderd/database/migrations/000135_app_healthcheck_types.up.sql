@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TYPE workspace_app_healthcheck_type AS ENUM ('http', 'tcp', 'command');
+
+ALTER TABLE workspace_apps
+	ADD COLUMN healthcheck_type workspace_app_healthcheck_type DEFAULT 'http' NOT NULL,
+	ADD COLUMN healthcheck_address text DEFAULT '' NOT NULL,
+	ADD COLUMN healthcheck_command text DEFAULT '' NOT NULL,
+	ADD COLUMN healthcheck_expected_exit_codes integer[] DEFAULT '{}' NOT NULL,
+	ADD COLUMN healthcheck_output_regex text DEFAULT '' NOT NULL;
+
+COMMENT ON COLUMN workspace_apps.healthcheck_address IS 'The host:port to connect to for tcp health checks.';
+
+COMMENT ON COLUMN workspace_apps.healthcheck_expected_exit_codes IS 'The exit codes of a healthy command health check. Empty means only 0.';
+
+COMMIT;
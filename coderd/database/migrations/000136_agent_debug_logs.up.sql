@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS workspace_agent_debug_logs (
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	level log_level NOT NULL DEFAULT 'info',
+	output varchar(4096) NOT NULL,
+	id BIGSERIAL PRIMARY KEY
+);
+CREATE INDEX workspace_agent_debug_logs_id_agent_id_idx ON workspace_agent_debug_logs USING btree (agent_id, id ASC);
+
+COMMENT ON TABLE workspace_agent_debug_logs IS 'Logs of the agent itself, uploaded for troubleshooting. Only the most recent logs of each agent are kept.';
+
+COMMIT;
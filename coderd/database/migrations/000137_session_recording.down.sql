@@ -0,0 +1,4 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TABLE templates
+	DROP COLUMN session_recording_enabled;
@@ -0,0 +1,8 @@
+ALTER TYPE audit_action
+	ADD VALUE IF NOT EXISTS 'connect';
+
+ALTER TABLE templates
+	ADD COLUMN session_recording_enabled boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.session_recording_enabled
+	IS 'Whether interactive terminal sessions in workspaces created from this template are recorded and linked from the audit log.';
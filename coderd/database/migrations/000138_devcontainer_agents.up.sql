@@ -0,0 +1,5 @@
+ALTER TABLE workspace_agents
+	ADD COLUMN parent_id uuid REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN workspace_agents.parent_id
+	IS 'The agent that registered this agent for a dev container it runs. Agents of the template have no parent.';
@@ -0,0 +1,5 @@
+ALTER TABLE workspace_proxies
+	DROP COLUMN version,
+	DROP COLUMN last_heartbeat_at,
+	DROP COLUMN health_checked_at,
+	DROP COLUMN health_error;
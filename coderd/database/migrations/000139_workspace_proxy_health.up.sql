@@ -0,0 +1,14 @@
+ALTER TABLE workspace_proxies
+	ADD COLUMN version text NOT NULL DEFAULT '',
+	ADD COLUMN last_heartbeat_at timestamp with time zone,
+	ADD COLUMN health_checked_at timestamp with time zone,
+	ADD COLUMN health_error text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN workspace_proxies.version
+	IS 'Version of the proxy, as reported by the proxy when it registers.';
+COMMENT ON COLUMN workspace_proxies.last_heartbeat_at
+	IS 'Last time the proxy registered with coderd. Proxies register periodically while they run. NULL if the proxy never registered.';
+COMMENT ON COLUMN workspace_proxies.health_checked_at
+	IS 'Last time coderd checked that the proxy is reachable at its url.';
+COMMENT ON COLUMN workspace_proxies.health_error
+	IS 'Error of the last reachability check, empty if the proxy was reachable.';
@@ -0,0 +1,5 @@
+ALTER TABLE templates
+	DROP COLUMN quota_cost_multiplier;
+
+ALTER TABLE organizations
+	DROP COLUMN quota_budget;
@@ -0,0 +1,11 @@
+ALTER TABLE organizations
+	ADD COLUMN quota_budget bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN organizations.quota_budget
+	IS 'The total daily cost of all workspaces in the organization. Builds that would exceed it are rejected. 0 means the organization has no budget.';
+
+ALTER TABLE templates
+	ADD COLUMN quota_cost_multiplier double precision NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN templates.quota_cost_multiplier
+	IS 'The daily cost of workspaces created from this template is multiplied by this factor before it is counted against quotas.';
@@ -0,0 +1,6 @@
+ALTER TABLE provisioner_daemons
+	DROP COLUMN key_id,
+	DROP COLUMN version,
+	DROP COLUMN last_seen_at;
+
+DROP TABLE IF EXISTS provisioner_keys;
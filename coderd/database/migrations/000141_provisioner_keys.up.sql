@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS provisioner_keys (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	name varchar(64) NOT NULL,
+	hashed_secret bytea NOT NULL,
+	tags jsonb NOT NULL DEFAULT '{}'::jsonb,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON COLUMN provisioner_keys.hashed_secret IS 'The SHA256 hash of the secret of the key.';
+COMMENT ON COLUMN provisioner_keys.tags IS 'Provisioner daemons authenticated with the key serve jobs with these tags.';
+
+CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+
+ALTER TABLE provisioner_daemons
+	ADD COLUMN last_seen_at timestamp with time zone,
+	ADD COLUMN version text NOT NULL DEFAULT '',
+	ADD COLUMN key_id uuid REFERENCES provisioner_keys (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN provisioner_daemons.key_id IS 'The provisioner key the daemon authenticated with. Daemons authenticated with a user session have no key.';
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS template_version_promotions (
+	id uuid NOT NULL,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	promoted_at timestamp with time zone NOT NULL,
+	promoted_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	rolled_back_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE template_version_promotions IS 'Every time a version was made the active version of its template. Rollbacks walk back through the promotions that were not rolled back.';
+COMMENT ON COLUMN template_version_promotions.rolled_back_at IS 'The time the promotion was undone by rolling back to an earlier promotion.';
+
+CREATE INDEX template_version_promotions_template_id_promoted_at_idx ON template_version_promotions USING btree (template_id, promoted_at DESC);
+
+-- Versions only remember their last promotion, which is the best history
+-- that's available for existing templates.
+INSERT INTO template_version_promotions (id, template_id, template_version_id, promoted_at, promoted_by)
+SELECT
+	gen_random_uuid(),
+	template_id,
+	id,
+	promoted_at,
+	promoted_by
+FROM
+	template_versions
+WHERE
+	template_id IS NOT NULL
+	AND promoted_at IS NOT NULL;
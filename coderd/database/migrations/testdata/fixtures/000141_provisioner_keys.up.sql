@@ -0,0 +1,11 @@
+INSERT INTO provisioner_keys
+	(id, created_at, organization_id, name, hashed_secret, tags)
+VALUES
+	(
+		'f2a0a0f1-1c5e-4b7a-9f57-7d2c1b5e4a10',
+		'2023-07-17 12:00:00.000+02',
+		'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+		'ci',
+		'abc123'::bytea,
+		'{"environment": "ci"}'::jsonb
+	);
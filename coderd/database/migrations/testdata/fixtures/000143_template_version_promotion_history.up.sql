@@ -0,0 +1,19 @@
+INSERT INTO template_version_promotions
+	(id, template_id, template_version_id, promoted_at, promoted_by, rolled_back_at)
+VALUES
+	(
+		'a1b4c8f2-5d0e-4c7b-8f3a-2e6d9b1c7a54',
+		'4cc1f466-f326-477e-8762-9d0c6781fc56',
+		'920baba5-4c64-4686-8b7d-d1bef5683eae',
+		'2022-11-02 13:04:00.000+02',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		NULL
+	),
+	(
+		'6f2e9d7a-3b1c-4e8f-a5d0-9c7b2e4f1a38',
+		'4cc1f466-f326-477e-8762-9d0c6781fc56',
+		'4e681a60-83da-42c2-902e-6535376ebb77',
+		'2022-11-02 13:08:00.000+02',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		NULL
+	);
@@ -57,6 +57,23 @@ func (s WorkspaceAgentStatus) Valid() bool {
 	}
 }
 
+// appSharingLevelRank orders sharing levels from the most restrictive to the
+// least restrictive.
+var appSharingLevelRank = map[AppSharingLevel]int{
+	AppSharingLevelOwner:         0,
+	AppSharingLevelAuthenticated: 1,
+	AppSharingLevelPublic:        2,
+}
+
+// MostRestrictive returns whichever of the two sharing levels grants access to
+// fewer users.
+func (a AppSharingLevel) MostRestrictive(b AppSharingLevel) AppSharingLevel {
+	if appSharingLevelRank[b] < appSharingLevelRank[a] {
+		return b
+	}
+	return a
+}
+
 type AuditableGroup struct {
 	Group
 	Members []GroupMember `json:"members"`
@@ -181,11 +198,39 @@ func (p ProvisionerDaemon) RBACObject() rbac.Object {
 	return rbac.ResourceProvisionerDaemon.WithID(p.ID)
 }
 
+func (k ProvisionerKey) RBACObject() rbac.Object {
+	// Keys are site wide like the daemons they create, organization admins
+	// can't create organization scoped daemons either.
+	return rbac.ResourceProvisionerDaemon.WithID(k.ID)
+}
+
 func (w WorkspaceProxy) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspaceProxy.
 		WithID(w.ID)
 }
 
+func (w Webhook) RBACObject() rbac.Object {
+	return rbac.ResourceWebhook.
+		WithID(w.ID)
+}
+
+func (r CustomRole) RBACObject() rbac.Object {
+	obj := rbac.ResourceCustomRole.WithID(r.ID)
+	if r.OrganizationID.Valid {
+		obj = obj.InOrg(r.OrganizationID.UUID)
+	}
+	return obj
+}
+
+// RoleName returns the name the role is assigned by. Organization roles are
+// suffixed with the organization ID, the same as the built-in roles.
+func (r CustomRole) RoleName() string {
+	if r.OrganizationID.Valid {
+		return r.Name + ":" + r.OrganizationID.UUID.String()
+	}
+	return r.Name
+}
+
 func (f File) RBACObject() rbac.Object {
 	return rbac.ResourceFile.
 		WithID(f.ID).
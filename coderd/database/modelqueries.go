@@ -52,6 +52,7 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 		arg.OrganizationID,
 		arg.ExactName,
 		pq.Array(arg.IDs),
+		arg.ExcludeDeprecated,
 	)
 	if err != nil {
 		return nil, err
@@ -80,6 +81,16 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.MaxTTL,
 			&i.AllowUserAutostart,
 			&i.AllowUserAutostop,
+			&i.InactivityTTL,
+			&i.DormantAutoDeleteTTL,
+			&i.FailureTTL,
+			&i.RestartRequirementDaysOfWeek,
+			&i.RestartRequirementWeeks,
+			&i.Deprecated,
+			&i.RequireActiveVersion,
+			&i.MaxPortSharingLevel,
+			&i.SessionRecordingEnabled,
+			&i.QuotaCostMultiplier,
 		); err != nil {
 			return nil, err
 		}
@@ -208,6 +219,7 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		arg.TemplateName,
 		pq.Array(arg.TemplateIds),
 		arg.Name,
+		arg.Dormant,
 		arg.HasAgent,
 		arg.AgentInactiveDisconnectTimeoutSeconds,
 		arg.Offset,
@@ -232,6 +244,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.AutostartSchedule,
 			&i.Ttl,
 			&i.LastUsedAt,
+			&i.DormantAt,
+			&i.AutomaticUpdates,
 			&i.Count,
 		); err != nil {
 			return nil, err
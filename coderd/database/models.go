@@ -147,6 +147,7 @@ const (
 	AuditActionLogin    AuditAction = "login"
 	AuditActionLogout   AuditAction = "logout"
 	AuditActionRegister AuditAction = "register"
+	AuditActionConnect  AuditAction = "connect"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -193,7 +194,8 @@ func (e AuditAction) Valid() bool {
 		AuditActionStop,
 		AuditActionLogin,
 		AuditActionLogout,
-		AuditActionRegister:
+		AuditActionRegister,
+		AuditActionConnect:
 		return true
 	}
 	return false
@@ -209,15 +211,77 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionLogin,
 		AuditActionLogout,
 		AuditActionRegister,
+		AuditActionConnect,
+	}
+}
+
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
+
+func (e *AutomaticUpdates) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AutomaticUpdates(s)
+	case string:
+		*e = AutomaticUpdates(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AutomaticUpdates: %T", src)
+	}
+	return nil
+}
+
+type NullAutomaticUpdates struct {
+	AutomaticUpdates AutomaticUpdates
+	Valid            bool // Valid is true if AutomaticUpdates is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAutomaticUpdates) Scan(value interface{}) error {
+	if value == nil {
+		ns.AutomaticUpdates, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AutomaticUpdates.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAutomaticUpdates) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AutomaticUpdates), nil
+}
+
+func (e AutomaticUpdates) Valid() bool {
+	switch e {
+	case AutomaticUpdatesAlways,
+		AutomaticUpdatesNever:
+		return true
+	}
+	return false
+}
+
+func AllAutomaticUpdatesValues() []AutomaticUpdates {
+	return []AutomaticUpdates{
+		AutomaticUpdatesAlways,
+		AutomaticUpdatesNever,
 	}
 }
 
 type BuildReason string
 
 const (
-	BuildReasonInitiator BuildReason = "initiator"
-	BuildReasonAutostart BuildReason = "autostart"
-	BuildReasonAutostop  BuildReason = "autostop"
+	BuildReasonInitiator  BuildReason = "initiator"
+	BuildReasonAutostart  BuildReason = "autostart"
+	BuildReasonAutostop   BuildReason = "autostop"
+	BuildReasonDormancy   BuildReason = "dormancy"
+	BuildReasonAutodelete BuildReason = "autodelete"
+	BuildReasonFailedstop BuildReason = "failedstop"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -259,7 +323,10 @@ func (e BuildReason) Valid() bool {
 	switch e {
 	case BuildReasonInitiator,
 		BuildReasonAutostart,
-		BuildReasonAutostop:
+		BuildReasonAutostop,
+		BuildReasonDormancy,
+		BuildReasonAutodelete,
+		BuildReasonFailedstop:
 		return true
 	}
 	return false
@@ -270,6 +337,9 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonInitiator,
 		BuildReasonAutostart,
 		BuildReasonAutostop,
+		BuildReasonDormancy,
+		BuildReasonAutodelete,
+		BuildReasonFailedstop,
 	}
 }
 
@@ -700,6 +770,128 @@ func AllParameterTypeSystemValues() []ParameterTypeSystem {
 	}
 }
 
+type PortShareProtocol string
+
+const (
+	PortShareProtocolHttp  PortShareProtocol = "http"
+	PortShareProtocolHttps PortShareProtocol = "https"
+)
+
+func (e *PortShareProtocol) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PortShareProtocol(s)
+	case string:
+		*e = PortShareProtocol(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PortShareProtocol: %T", src)
+	}
+	return nil
+}
+
+type NullPortShareProtocol struct {
+	PortShareProtocol PortShareProtocol
+	Valid             bool // Valid is true if PortShareProtocol is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPortShareProtocol) Scan(value interface{}) error {
+	if value == nil {
+		ns.PortShareProtocol, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PortShareProtocol.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPortShareProtocol) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PortShareProtocol), nil
+}
+
+func (e PortShareProtocol) Valid() bool {
+	switch e {
+	case PortShareProtocolHttp,
+		PortShareProtocolHttps:
+		return true
+	}
+	return false
+}
+
+func AllPortShareProtocolValues() []PortShareProtocol {
+	return []PortShareProtocol{
+		PortShareProtocolHttp,
+		PortShareProtocolHttps,
+	}
+}
+
+type ProvisionerJobTimingStage string
+
+const (
+	ProvisionerJobTimingStageInit  ProvisionerJobTimingStage = "init"
+	ProvisionerJobTimingStagePlan  ProvisionerJobTimingStage = "plan"
+	ProvisionerJobTimingStageGraph ProvisionerJobTimingStage = "graph"
+	ProvisionerJobTimingStageApply ProvisionerJobTimingStage = "apply"
+)
+
+func (e *ProvisionerJobTimingStage) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProvisionerJobTimingStage(s)
+	case string:
+		*e = ProvisionerJobTimingStage(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProvisionerJobTimingStage: %T", src)
+	}
+	return nil
+}
+
+type NullProvisionerJobTimingStage struct {
+	ProvisionerJobTimingStage ProvisionerJobTimingStage
+	Valid                     bool // Valid is true if ProvisionerJobTimingStage is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProvisionerJobTimingStage) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProvisionerJobTimingStage, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProvisionerJobTimingStage.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProvisionerJobTimingStage) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProvisionerJobTimingStage), nil
+}
+
+func (e ProvisionerJobTimingStage) Valid() bool {
+	switch e {
+	case ProvisionerJobTimingStageInit,
+		ProvisionerJobTimingStagePlan,
+		ProvisionerJobTimingStageGraph,
+		ProvisionerJobTimingStageApply:
+		return true
+	}
+	return false
+}
+
+func AllProvisionerJobTimingStageValues() []ProvisionerJobTimingStage {
+	return []ProvisionerJobTimingStage{
+		ProvisionerJobTimingStageInit,
+		ProvisionerJobTimingStagePlan,
+		ProvisionerJobTimingStageGraph,
+		ProvisionerJobTimingStageApply,
+	}
+}
+
 type ProvisionerJobType string
 
 const (
@@ -888,6 +1080,9 @@ const (
 	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
 	ResourceTypeLicense         ResourceType = "license"
 	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
+	ResourceTypeWebhook         ResourceType = "webhook"
+	ResourceTypeCustomRole      ResourceType = "custom_role"
+	ResourceTypeProvisionerKey  ResourceType = "provisioner_key"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -937,7 +1132,10 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeGroup,
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
-		ResourceTypeWorkspaceProxy:
+		ResourceTypeWorkspaceProxy,
+		ResourceTypeWebhook,
+		ResourceTypeCustomRole,
+		ResourceTypeProvisionerKey:
 		return true
 	}
 	return false
@@ -956,6 +1154,9 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
+		ResourceTypeWebhook,
+		ResourceTypeCustomRole,
+		ResourceTypeProvisionerKey,
 	}
 }
 
@@ -1017,6 +1218,76 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted   WebhookEvent = "workspace_build_started"
+	WebhookEventWorkspaceBuildSucceeded WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build_failed"
+	WebhookEventTemplateVersionPromoted WebhookEvent = "template_version_promoted"
+	WebhookEventUserCreated             WebhookEvent = "user_created"
+	WebhookEventUserSuspended           WebhookEvent = "user_suspended"
+)
+
+func (e *WebhookEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookEvent(s)
+	case string:
+		*e = WebhookEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookEvent: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookEvent struct {
+	WebhookEvent WebhookEvent
+	Valid        bool // Valid is true if WebhookEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookEvent), nil
+}
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventWorkspaceBuildStarted,
+		WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventTemplateVersionPromoted,
+		WebhookEventUserCreated,
+		WebhookEventUserSuspended:
+		return true
+	}
+	return false
+}
+
+func AllWebhookEventValues() []WebhookEvent {
+	return []WebhookEvent{
+		WebhookEventWorkspaceBuildStarted,
+		WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventTemplateVersionPromoted,
+		WebhookEventUserCreated,
+		WebhookEventUserSuspended,
+	}
+}
+
 type WorkspaceAgentLifecycleState string
 
 const (
@@ -1096,6 +1367,73 @@ func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
 	}
 }
 
+type WorkspaceAgentScriptStatus string
+
+const (
+	WorkspaceAgentScriptStatusPending  WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning  WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusOk       WorkspaceAgentScriptStatus = "ok"
+	WorkspaceAgentScriptStatusError    WorkspaceAgentScriptStatus = "error"
+	WorkspaceAgentScriptStatusTimedOut WorkspaceAgentScriptStatus = "timed_out"
+)
+
+func (e *WorkspaceAgentScriptStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptStatus(s)
+	case string:
+		*e = WorkspaceAgentScriptStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptStatus struct {
+	WorkspaceAgentScriptStatus WorkspaceAgentScriptStatus
+	Valid                      bool // Valid is true if WorkspaceAgentScriptStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptStatus), nil
+}
+
+func (e WorkspaceAgentScriptStatus) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusOk,
+		WorkspaceAgentScriptStatusError,
+		WorkspaceAgentScriptStatusTimedOut:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptStatusValues() []WorkspaceAgentScriptStatus {
+	return []WorkspaceAgentScriptStatus{
+		WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusOk,
+		WorkspaceAgentScriptStatusError,
+		WorkspaceAgentScriptStatusTimedOut,
+	}
+}
+
 type WorkspaceAppHealth string
 
 const (
@@ -1160,6 +1498,67 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceAppHealthcheckType string
+
+const (
+	WorkspaceAppHealthcheckTypeHTTP    WorkspaceAppHealthcheckType = "http"
+	WorkspaceAppHealthcheckTypeTCP     WorkspaceAppHealthcheckType = "tcp"
+	WorkspaceAppHealthcheckTypeCommand WorkspaceAppHealthcheckType = "command"
+)
+
+func (e *WorkspaceAppHealthcheckType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAppHealthcheckType(s)
+	case string:
+		*e = WorkspaceAppHealthcheckType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAppHealthcheckType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAppHealthcheckType struct {
+	WorkspaceAppHealthcheckType WorkspaceAppHealthcheckType
+	Valid                       bool // Valid is true if WorkspaceAppHealthcheckType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAppHealthcheckType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAppHealthcheckType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAppHealthcheckType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAppHealthcheckType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAppHealthcheckType), nil
+}
+
+func (e WorkspaceAppHealthcheckType) Valid() bool {
+	switch e {
+	case WorkspaceAppHealthcheckTypeHTTP,
+		WorkspaceAppHealthcheckTypeTCP,
+		WorkspaceAppHealthcheckTypeCommand:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAppHealthcheckTypeValues() []WorkspaceAppHealthcheckType {
+	return []WorkspaceAppHealthcheckType{
+		WorkspaceAppHealthcheckTypeHTTP,
+		WorkspaceAppHealthcheckTypeTCP,
+		WorkspaceAppHealthcheckTypeCommand,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1255,6 +1654,20 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Custom roles allow dynamic roles expanded at runtime alongside the built-in roles.
+type CustomRole struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	// Roles scoped to an organization can only be assigned to members of that organization. NULL for site wide roles.
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+}
+
 type File struct {
 	Hash      string    `db:"hash" json:"hash"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
@@ -1310,6 +1723,8 @@ type Organization struct {
 	Description string    `db:"description" json:"description"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	// The total daily cost of all workspaces in the organization. Builds that would exceed it are rejected. 0 means the organization has no budget.
+	QuotaBudget int64 `db:"quota_budget" json:"quota_budget"`
 }
 
 type OrganizationMember struct {
@@ -1360,6 +1775,10 @@ type ProvisionerDaemon struct {
 	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
 	ReplicaID    uuid.NullUUID     `db:"replica_id" json:"replica_id"`
 	Tags         dbtype.StringMap  `db:"tags" json:"tags"`
+	LastSeenAt   sql.NullTime      `db:"last_seen_at" json:"last_seen_at"`
+	Version      string            `db:"version" json:"version"`
+	// The provisioner key the daemon authenticated with. Daemons authenticated with a user session have no key.
+	KeyID uuid.NullUUID `db:"key_id" json:"key_id"`
 }
 
 type ProvisionerJob struct {
@@ -1392,6 +1811,28 @@ type ProvisionerJobLog struct {
 	ID        int64     `db:"id" json:"id"`
 }
 
+type ProvisionerJobTiming struct {
+	JobID     uuid.UUID                 `db:"job_id" json:"job_id"`
+	StartedAt time.Time                 `db:"started_at" json:"started_at"`
+	EndedAt   time.Time                 `db:"ended_at" json:"ended_at"`
+	Stage     ProvisionerJobTimingStage `db:"stage" json:"stage"`
+	Source    string                    `db:"source" json:"source"`
+	Action    string                    `db:"action" json:"action"`
+	// The address of the resource the timing is for. It is empty for the timing of a whole stage.
+	Resource string `db:"resource" json:"resource"`
+}
+
+type ProvisionerKey struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	// The SHA256 hash of the secret of the key.
+	HashedSecret []byte `db:"hashed_secret" json:"hashed_secret"`
+	// Provisioner daemons authenticated with the key serve jobs with these tags.
+	Tags dbtype.StringMap `db:"tags" json:"tags"`
+}
+
 type Replica struct {
 	ID              uuid.UUID    `db:"id" json:"id"`
 	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
@@ -1436,6 +1877,26 @@ type Template struct {
 	AllowUserAutostart bool `db:"allow_user_autostart" json:"allow_user_autostart"`
 	// Allow users to specify custom autostop values for workspaces (enterprise).
 	AllowUserAutostop bool `db:"allow_user_autostop" json:"allow_user_autostop"`
+	// The duration of inactivity after which workspaces created from this template are marked dormant (enterprise).
+	InactivityTTL int64 `db:"inactivity_ttl" json:"inactivity_ttl"`
+	// The duration a workspace may remain dormant before it is automatically deleted (enterprise).
+	DormantAutoDeleteTTL int64 `db:"dormant_autodelete_ttl" json:"dormant_autodelete_ttl"`
+	// The duration after which workspaces whose start build failed are automatically stopped (enterprise).
+	FailureTTL int64 `db:"failure_ttl" json:"failure_ttl"`
+	// A bitmap of days of week to restart the workspace on, starting with Monday as the 0th bit, and Sunday as the 6th bit. The 7th bit is unused.
+	RestartRequirementDaysOfWeek int16 `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
+	// The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.
+	RestartRequirementWeeks int64 `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
+	// If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.
+	Deprecated string `db:"deprecated" json:"deprecated"`
+	// Whether workspaces created from this template must be built against the active version every time they start.
+	RequireActiveVersion bool `db:"require_active_version" json:"require_active_version"`
+	// The highest share level that workspace owners can grant to ports of workspaces created from this template.
+	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	// Whether interactive terminal sessions in workspaces created from this template are recorded and linked from the audit log.
+	SessionRecordingEnabled bool `db:"session_recording_enabled" json:"session_recording_enabled"`
+	// The daily cost of workspaces created from this template is multiplied by this factor before it is counted against quotas.
+	QuotaCostMultiplier float64 `db:"quota_cost_multiplier" json:"quota_cost_multiplier"`
 }
 
 type TemplateVersion struct {
@@ -1450,6 +1911,10 @@ type TemplateVersion struct {
 	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
 	// IDs of Git auth providers for a specific template version
 	GitAuthProviders []string `db:"git_auth_providers" json:"git_auth_providers"`
+	// The last time the version was made the active version of its template.
+	PromotedAt sql.NullTime `db:"promoted_at" json:"promoted_at"`
+	// The user that last made the version the active version of its template.
+	PromotedBy uuid.NullUUID `db:"promoted_by" json:"promoted_by"`
 }
 
 type TemplateVersionParameter struct {
@@ -1486,6 +1951,17 @@ type TemplateVersionParameter struct {
 	DisplayName string `db:"display_name" json:"display_name"`
 }
 
+// Every time a version was made the active version of its template. Rollbacks walk back through the promotions that were not rolled back.
+type TemplateVersionPromotion struct {
+	ID                uuid.UUID     `db:"id" json:"id"`
+	TemplateID        uuid.UUID     `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID     `db:"template_version_id" json:"template_version_id"`
+	PromotedAt        time.Time     `db:"promoted_at" json:"promoted_at"`
+	PromotedBy        uuid.NullUUID `db:"promoted_by" json:"promoted_by"`
+	// The time the promotion was undone by rolling back to an earlier promotion.
+	RolledBackAt sql.NullTime `db:"rolled_back_at" json:"rolled_back_at"`
+}
+
 type TemplateVersionVariable struct {
 	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
 	// Variable name
@@ -1517,6 +1993,8 @@ type User struct {
 	AvatarURL      sql.NullString `db:"avatar_url" json:"avatar_url"`
 	Deleted        bool           `db:"deleted" json:"deleted"`
 	LastSeenAt     time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	// Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user's quiet hours. If empty, the default quiet hours on the instance is used instead.
+	QuietHoursSchedule string `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
 }
 
 type UserLink struct {
@@ -1528,6 +2006,35 @@ type UserLink struct {
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
 }
 
+type Webhook struct {
+	ID   uuid.UUID `db:"id" json:"id"`
+	Name string    `db:"name" json:"name"`
+	Url  string    `db:"url" json:"url"`
+	// Secret used to compute the HMAC-SHA256 signature sent with every delivery. This MUST NOT be returned from the API after creation.
+	Secret string `db:"secret" json:"secret"`
+	// Events the webhook is subscribed to.
+	Events    []WebhookEvent `db:"events" json:"events"`
+	Enabled   bool           `db:"enabled" json:"enabled"`
+	CreatedBy uuid.UUID      `db:"created_by" json:"created_by"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	WebhookID uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event     WebhookEvent    `db:"event" json:"event"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	Attempts  int32           `db:"attempts" json:"attempts"`
+	// When the delivery should next be attempted. NULL once the delivery succeeded or ran out of attempts.
+	NextAttemptAt sql.NullTime `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt   sql.NullTime `db:"delivered_at" json:"delivered_at"`
+	// HTTP status code of the last attempt, 0 if no response was received.
+	LastStatusCode int32  `db:"last_status_code" json:"last_status_code"`
+	LastError      string `db:"last_error" json:"last_error"`
+}
+
 type Workspace struct {
 	ID                uuid.UUID      `db:"id" json:"id"`
 	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
@@ -1540,6 +2047,10 @@ type Workspace struct {
 	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
 	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	// The time at which the workspace was marked dormant due to inactivity. Dormant workspaces cannot be started until they are explicitly made active again.
+	DormantAt sql.NullTime `db:"dormant_at" json:"dormant_at"`
+	// Whether the workspace is built against the active version of its template every time it starts.
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 type WorkspaceAgent struct {
@@ -1585,6 +2096,21 @@ type WorkspaceAgent struct {
 	StartupLogsLength int32 `db:"startup_logs_length" json:"startup_logs_length"`
 	// Whether the startup logs overflowed in length
 	StartupLogsOverflowed bool `db:"startup_logs_overflowed" json:"startup_logs_overflowed"`
+	// The time the agent entered the starting lifecycle state.
+	StartedAt sql.NullTime `db:"started_at" json:"started_at"`
+	// The time the agent entered the ready or start_error lifecycle state.
+	ReadyAt sql.NullTime `db:"ready_at" json:"ready_at"`
+	// The agent that registered this agent for a dev container it runs. Agents of the template have no parent.
+	ParentID uuid.NullUUID `db:"parent_id" json:"parent_id"`
+}
+
+// Logs of the agent itself, uploaded for troubleshooting. Only the most recent logs of each agent are kept.
+type WorkspaceAgentDebugLog struct {
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Level     LogLevel  `db:"level" json:"level"`
+	Output    string    `db:"output" json:"output"`
+	ID        int64     `db:"id" json:"id"`
 }
 
 type WorkspaceAgentMetadatum struct {
@@ -1599,12 +2125,43 @@ type WorkspaceAgentMetadatum struct {
 	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
 }
 
+// Ports listening in a workspace agent that the workspace owner has shared beyond themselves.
+type WorkspaceAgentPortShare struct {
+	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
+	AgentName   string            `db:"agent_name" json:"agent_name"`
+	Port        int32             `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
+	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
+}
+
+type WorkspaceAgentScript struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Script           string    `db:"script" json:"script"`
+	Cron             string    `db:"cron" json:"cron"`
+	// Scripts that run on start are started in ascending order. A script waits for the login-blocking scripts with a lower order to finish first.
+	RunOrder         int32  `db:"run_order" json:"run_order"`
+	StartBlocksLogin bool   `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       bool   `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        bool   `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   int32  `db:"timeout_seconds" json:"timeout_seconds"`
+	LogPath          string `db:"log_path" json:"log_path"`
+	// The status of the latest run of the script, as reported by the agent.
+	Status      WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	ExitCode    int32                      `db:"exit_code" json:"exit_code"`
+	StartedAt   sql.NullTime               `db:"started_at" json:"started_at"`
+	CompletedAt sql.NullTime               `db:"completed_at" json:"completed_at"`
+}
+
 type WorkspaceAgentStartupLog struct {
 	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	Output    string    `db:"output" json:"output"`
 	ID        int64     `db:"id" json:"id"`
 	Level     LogLevel  `db:"level" json:"level"`
+	ScriptID  uuid.UUID `db:"script_id" json:"script_id"`
 }
 
 type WorkspaceAgentStat struct {
@@ -1625,24 +2182,41 @@ type WorkspaceAgentStat struct {
 	SessionCountJetBrains       int64           `db:"session_count_jetbrains" json:"session_count_jetbrains"`
 	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
 	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
+	CPUUsedCores                float64         `db:"cpu_used_cores" json:"cpu_used_cores"`
+	// The CPU limit of the container the agent runs in, or the number of host CPUs. Zero if the agent did not report resource usage.
+	CPULimitCores   float64 `db:"cpu_limit_cores" json:"cpu_limit_cores"`
+	MemoryUsedBytes int64   `db:"memory_used_bytes" json:"memory_used_bytes"`
+	// The memory limit of the container the agent runs in, or the total host memory. Zero if the agent did not report resource usage.
+	MemoryLimitBytes int64 `db:"memory_limit_bytes" json:"memory_limit_bytes"`
+	DiskUsedBytes    int64 `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes   int64 `db:"disk_total_bytes" json:"disk_total_bytes"`
+	NetworkRxBytes   int64 `db:"network_rx_bytes" json:"network_rx_bytes"`
+	NetworkTxBytes   int64 `db:"network_tx_bytes" json:"network_tx_bytes"`
 }
 
 type WorkspaceApp struct {
-	ID                   uuid.UUID          `db:"id" json:"id"`
-	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
-	AgentID              uuid.UUID          `db:"agent_id" json:"agent_id"`
-	DisplayName          string             `db:"display_name" json:"display_name"`
-	Icon                 string             `db:"icon" json:"icon"`
-	Command              sql.NullString     `db:"command" json:"command"`
-	Url                  sql.NullString     `db:"url" json:"url"`
-	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
-	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
-	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
-	Health               WorkspaceAppHealth `db:"health" json:"health"`
-	Subdomain            bool               `db:"subdomain" json:"subdomain"`
-	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
-	Slug                 string             `db:"slug" json:"slug"`
-	External             bool               `db:"external" json:"external"`
+	ID                   uuid.UUID                   `db:"id" json:"id"`
+	CreatedAt            time.Time                   `db:"created_at" json:"created_at"`
+	AgentID              uuid.UUID                   `db:"agent_id" json:"agent_id"`
+	DisplayName          string                      `db:"display_name" json:"display_name"`
+	Icon                 string                      `db:"icon" json:"icon"`
+	Command              sql.NullString              `db:"command" json:"command"`
+	Url                  sql.NullString              `db:"url" json:"url"`
+	HealthcheckUrl       string                      `db:"healthcheck_url" json:"healthcheck_url"`
+	HealthcheckInterval  int32                       `db:"healthcheck_interval" json:"healthcheck_interval"`
+	HealthcheckThreshold int32                       `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	Health               WorkspaceAppHealth          `db:"health" json:"health"`
+	Subdomain            bool                        `db:"subdomain" json:"subdomain"`
+	SharingLevel         AppSharingLevel             `db:"sharing_level" json:"sharing_level"`
+	Slug                 string                      `db:"slug" json:"slug"`
+	External             bool                        `db:"external" json:"external"`
+	HealthcheckType      WorkspaceAppHealthcheckType `db:"healthcheck_type" json:"healthcheck_type"`
+	// The host:port to connect to for tcp health checks.
+	HealthcheckAddress string `db:"healthcheck_address" json:"healthcheck_address"`
+	HealthcheckCommand string `db:"healthcheck_command" json:"healthcheck_command"`
+	// The exit codes of a healthy command health check. Empty means only 0.
+	HealthcheckExpectedExitCodes []int32 `db:"healthcheck_expected_exit_codes" json:"healthcheck_expected_exit_codes"`
+	HealthcheckOutputRegex       string  `db:"healthcheck_output_regex" json:"healthcheck_output_regex"`
 }
 
 type WorkspaceBuild struct {
@@ -1686,6 +2260,14 @@ type WorkspaceProxy struct {
 	Deleted bool `db:"deleted" json:"deleted"`
 	// Hashed secret is used to authenticate the workspace proxy using a session token.
 	TokenHashedSecret []byte `db:"token_hashed_secret" json:"token_hashed_secret"`
+	// Version of the proxy, as reported by the proxy when it registers.
+	Version string `db:"version" json:"version"`
+	// Last time the proxy registered with coderd. Proxies register periodically while they run. NULL if the proxy never registered.
+	LastHeartbeatAt sql.NullTime `db:"last_heartbeat_at" json:"last_heartbeat_at"`
+	// Last time coderd checked that the proxy is reachable at its url.
+	HealthCheckedAt sql.NullTime `db:"health_checked_at" json:"health_checked_at"`
+	// Error of the last reachability check, empty if the proxy was reachable.
+	HealthError string `db:"health_error" json:"health_error"`
 }
 
 type WorkspaceResource struct {
@@ -1059,7 +1059,7 @@ func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg Del
 
 const getGroupMembers = `-- name: GetGroupMembers :many
 SELECT
-	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule
 FROM
 	users
 JOIN
@@ -1096,6 +1096,7 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 			&i.AvatarURL,
 			&i.Deleted,
 			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
 		); err != nil {
 			return nil, err
 		}
@@ -1110,6 +1111,60 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 	return items, nil
 }
 
+const getGroupMembershipsByUserIDs = `-- name: GetGroupMembershipsByUserIDs :many
+SELECT
+	group_members.user_id,
+	groups.id AS group_id,
+	groups.name AS group_name
+FROM
+	group_members
+JOIN
+	groups
+ON
+	groups.id = group_members.group_id
+JOIN
+	users
+ON
+	users.id = group_members.user_id
+WHERE
+	group_members.user_id = ANY($1 :: uuid [ ])
+AND
+	users.status = 'active'
+AND
+	users.deleted = 'false'
+`
+
+type GetGroupMembershipsByUserIDsRow struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	GroupName string    `db:"group_name" json:"group_name"`
+}
+
+// GetGroupMembershipsByUserIDs returns the groups of the active users in
+// user_ids, with the names of the groups.
+func (q *sqlQuerier) GetGroupMembershipsByUserIDs(ctx context.Context, userIds []uuid.UUID) ([]GetGroupMembershipsByUserIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembershipsByUserIDs, pq.Array(userIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGroupMembershipsByUserIDsRow
+	for rows.Next() {
+		var i GetGroupMembershipsByUserIDsRow
+		if err := rows.Scan(&i.UserID, &i.GroupID, &i.GroupName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertGroupMember = `-- name: InsertGroupMember :exec
 INSERT INTO
     group_members (user_id, group_id)
@@ -1724,7 +1779,7 @@ func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRole
 
 const getOrganizationByID = `-- name: GetOrganizationByID :one
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, quota_budget
 FROM
 	organizations
 WHERE
@@ -1740,13 +1795,14 @@ func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Org
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.QuotaBudget,
 	)
 	return i, err
 }
 
 const getOrganizationByName = `-- name: GetOrganizationByName :one
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, quota_budget
 FROM
 	organizations
 WHERE
@@ -1764,13 +1820,14 @@ func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, name string) (Or
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.QuotaBudget,
 	)
 	return i, err
 }
 
 const getOrganizations = `-- name: GetOrganizations :many
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, quota_budget
 FROM
 	organizations
 `
@@ -1790,6 +1847,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, erro
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.QuotaBudget,
 		); err != nil {
 			return nil, err
 		}
@@ -1806,7 +1864,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, erro
 
 const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, quota_budget
 FROM
 	organizations
 WHERE
@@ -1835,6 +1893,7 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.U
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.QuotaBudget,
 		); err != nil {
 			return nil, err
 		}
@@ -1853,7 +1912,7 @@ const insertOrganization = `-- name: InsertOrganization :one
 INSERT INTO
 	organizations (id, "name", description, created_at, updated_at)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at
+	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at, quota_budget
 `
 
 type InsertOrganizationParams struct {
@@ -1879,6 +1938,38 @@ func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizat
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.QuotaBudget,
+	)
+	return i, err
+}
+
+const updateOrganizationQuotaBudget = `-- name: UpdateOrganizationQuotaBudget :one
+UPDATE
+	organizations
+SET
+	quota_budget = $1,
+	updated_at = $2
+WHERE
+	id = $3
+RETURNING id, name, description, created_at, updated_at, quota_budget
+`
+
+type UpdateOrganizationQuotaBudgetParams struct {
+	QuotaBudget int64     `db:"quota_budget" json:"quota_budget"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateOrganizationQuotaBudget(ctx context.Context, arg UpdateOrganizationQuotaBudgetParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, updateOrganizationQuotaBudget, arg.QuotaBudget, arg.UpdatedAt, arg.ID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.QuotaBudget,
 	)
 	return i, err
 }
@@ -2289,7 +2380,7 @@ func (q *sqlQuerier) ParameterValues(ctx context.Context, arg ParameterValuesPar
 
 const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
 SELECT
-	id, created_at, updated_at, name, provisioners, replica_id, tags
+	id, created_at, updated_at, name, provisioners, replica_id, tags, last_seen_at, version, key_id
 FROM
 	provisioner_daemons
 `
@@ -2311,6 +2402,9 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 			pq.Array(&i.Provisioners),
 			&i.ReplicaID,
 			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.KeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -2332,10 +2426,13 @@ INSERT INTO
 		created_at,
 		"name",
 		provisioners,
-		tags
+		tags,
+		last_seen_at,
+		version,
+		key_id
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags, last_seen_at, version, key_id
 `
 
 type InsertProvisionerDaemonParams struct {
@@ -2344,6 +2441,9 @@ type InsertProvisionerDaemonParams struct {
 	Name         string            `db:"name" json:"name"`
 	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
 	Tags         dbtype.StringMap  `db:"tags" json:"tags"`
+	LastSeenAt   sql.NullTime      `db:"last_seen_at" json:"last_seen_at"`
+	Version      string            `db:"version" json:"version"`
+	KeyID        uuid.NullUUID     `db:"key_id" json:"key_id"`
 }
 
 func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
@@ -2353,6 +2453,9 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 		arg.Name,
 		pq.Array(arg.Provisioners),
 		arg.Tags,
+		arg.LastSeenAt,
+		arg.Version,
+		arg.KeyID,
 	)
 	var i ProvisionerDaemon
 	err := row.Scan(
@@ -2363,10 +2466,32 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 		pq.Array(&i.Provisioners),
 		&i.ReplicaID,
 		&i.Tags,
+		&i.LastSeenAt,
+		&i.Version,
+		&i.KeyID,
 	)
 	return i, err
 }
 
+const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE
+	provisioner_daemons
+SET
+	last_seen_at = $1
+WHERE
+	id = $2
+`
+
+type UpdateProvisionerDaemonLastSeenAtParams struct {
+	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt, arg.LastSeenAt, arg.ID)
+	return err
+}
+
 const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
 SELECT
 	job_id, created_at, source, level, stage, output, id
@@ -2493,6 +2618,12 @@ WHERE
 			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
 			-- Ensure the caller satisfies all job tags.
 			AND nested.tags <@ $4 :: jsonb
+			-- Daemons of provisioner keys only serve the organization of the key.
+			AND CASE
+				WHEN $5 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+					nested.organization_id = $5
+				ELSE true
+			END
 		ORDER BY
 			nested.created_at
 		FOR UPDATE
@@ -2503,10 +2634,11 @@ WHERE
 `
 
 type AcquireProvisionerJobParams struct {
-	StartedAt sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID  uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	Types     []ProvisionerType `db:"types" json:"types"`
-	Tags      json.RawMessage   `db:"tags" json:"tags"`
+	StartedAt      sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID       uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	Types          []ProvisionerType `db:"types" json:"types"`
+	Tags           json.RawMessage   `db:"tags" json:"tags"`
+	OrganizationID uuid.UUID         `db:"organization_id" json:"organization_id"`
 }
 
 // Acquires the lock for a single job that isn't started, completed,
@@ -2521,6 +2653,7 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		arg.WorkerID,
 		pq.Array(arg.Types),
 		arg.Tags,
+		arg.OrganizationID,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -2674,6 +2807,58 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 	return items, nil
 }
 
+const getRunningProvisionerJobsByWorkerIDs = `-- name: GetRunningProvisionerJobsByWorkerIDs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code
+FROM
+	provisioner_jobs
+WHERE
+	worker_id = ANY($1 :: uuid [ ])
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL
+`
+
+func (q *sqlQuerier) GetRunningProvisionerJobsByWorkerIDs(ctx context.Context, workerIds []uuid.UUID) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getRunningProvisionerJobsByWorkerIDs, pq.Array(workerIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertProvisionerJob = `-- name: InsertProvisionerJob :one
 INSERT INTO
 	provisioner_jobs (
@@ -2815,35 +3000,34 @@ func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, a
 	return err
 }
 
-const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
+const getProvisionerJobTimingsByJobID = `-- name: GetProvisionerJobTimingsByJobID :many
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret
+	job_id, started_at, ended_at, stage, source, action, resource
 FROM
-	workspace_proxies
+	provisioner_job_timings
 WHERE
-	deleted = false
+	job_id = $1
+ORDER BY
+	started_at ASC
 `
 
-func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
+func (q *sqlQuerier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobTiming, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobTimingsByJobID, jobID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceProxy
+	var items []ProvisionerJobTiming
 	for rows.Next() {
-		var i WorkspaceProxy
+		var i ProvisionerJobTiming
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Url,
-			&i.WildcardHostname,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Deleted,
-			&i.TokenHashedSecret,
+			&i.JobID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Stage,
+			&i.Source,
+			&i.Action,
+			&i.Resource,
 		); err != nil {
 			return nil, err
 		}
@@ -2858,83 +3042,383 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 	return items, nil
 }
 
-const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
+const insertProvisionerJobTimings = `-- name: InsertProvisionerJobTimings :many
+INSERT INTO
+	provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret
-FROM
-	workspace_proxies
+	$1 :: uuid AS job_id,
+	unnest($2 :: timestamptz [ ]) AS started_at,
+	unnest($3 :: timestamptz [ ]) AS ended_at,
+	unnest($4 :: provisioner_job_timing_stage [ ]) AS stage,
+	unnest($5 :: text [ ]) AS source,
+	unnest($6 :: text [ ]) AS action,
+	unnest($7 :: text [ ]) AS resource RETURNING job_id, started_at, ended_at, stage, source, action, resource
+`
+
+type InsertProvisionerJobTimingsParams struct {
+	JobID     uuid.UUID                   `db:"job_id" json:"job_id"`
+	StartedAt []time.Time                 `db:"started_at" json:"started_at"`
+	EndedAt   []time.Time                 `db:"ended_at" json:"ended_at"`
+	Stage     []ProvisionerJobTimingStage `db:"stage" json:"stage"`
+	Source    []string                    `db:"source" json:"source"`
+	Action    []string                    `db:"action" json:"action"`
+	Resource  []string                    `db:"resource" json:"resource"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error) {
+	rows, err := q.db.QueryContext(ctx, insertProvisionerJobTimings,
+		arg.JobID,
+		pq.Array(arg.StartedAt),
+		pq.Array(arg.EndedAt),
+		pq.Array(arg.Stage),
+		pq.Array(arg.Source),
+		pq.Array(arg.Action),
+		pq.Array(arg.Resource),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobTiming
+	for rows.Next() {
+		var i ProvisionerJobTiming
+		if err := rows.Scan(
+			&i.JobID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Stage,
+			&i.Source,
+			&i.Action,
+			&i.Resource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteProvisionerKey = `-- name: DeleteProvisionerKey :exec
+DELETE FROM
+	provisioner_keys
 WHERE
-	-- Validate that the @hostname has been sanitized and is not empty. This
-	-- doesn't prevent SQL injection (already prevented by using prepared
-	-- queries), but it does prevent carefully crafted hostnames from matching
-	-- when they shouldn't.
-	--
-	-- Periods don't need to be escaped because they're not special characters
-	-- in SQL matches unlike regular expressions.
-	$1 :: text SIMILAR TO '[a-zA-Z0-9.-]+' AND
-	deleted = false AND
+	id = $1
+`
 
-	-- Validate that the hostname matches either the wildcard hostname or the
-	-- access URL (ignoring scheme, port and path).
-	(
-		url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*' OR
-		$1 :: text LIKE replace(wildcard_hostname, '*', '%')
-	)
-LIMIT
-	1
+func (q *sqlQuerier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerKey, id)
+	return err
+}
+
+const getProvisionerKeyByID = `-- name: GetProvisionerKeyByID :one
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags
+FROM
+	provisioner_keys
+WHERE
+	id = $1
 `
 
-// Finds a workspace proxy that has an access URL or app hostname that matches
-// the provided hostname. This is to check if a hostname matches any workspace
-// proxy.
-//
-// The hostname must be sanitized to only contain [a-zA-Z0-9.-] before calling
-// this query. The scheme, port and path should be stripped.
-func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, hostname string) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByHostname, hostname)
-	var i WorkspaceProxy
+func (q *sqlQuerier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByID, id)
+	var i ProvisionerKey
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
 	)
 	return i, err
 }
 
-const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
+const getProvisionerKeyByName = `-- name: GetProvisionerKeyByName :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret
+	id, created_at, organization_id, name, hashed_secret, tags
 FROM
-	workspace_proxies
+	provisioner_keys
 WHERE
-	id = $1
-LIMIT
-	1
+	organization_id = $1
+	AND lower("name") = lower($2)
 `
 
-func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByID, id)
-	var i WorkspaceProxy
+type GetProvisionerKeyByNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByName, arg.OrganizationID, arg.Name)
+	var i ProvisionerKey
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-	)
-	return i, err
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getProvisionerKeysByOrganizationID = `-- name: GetProvisionerKeysByOrganizationID :many
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+ORDER BY
+	lower("name") ASC
+`
+
+func (q *sqlQuerier) GetProvisionerKeysByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerKeysByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerKey
+	for rows.Next() {
+		var i ProvisionerKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.OrganizationID,
+			&i.Name,
+			&i.HashedSecret,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertProvisionerKey = `-- name: InsertProvisionerKey :one
+INSERT INTO
+	provisioner_keys (
+		id,
+		created_at,
+		organization_id,
+		"name",
+		hashed_secret,
+		tags
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, created_at, organization_id, name, hashed_secret, tags
+`
+
+type InsertProvisionerKeyParams struct {
+	ID             uuid.UUID        `db:"id" json:"id"`
+	CreatedAt      time.Time        `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID        `db:"organization_id" json:"organization_id"`
+	Name           string           `db:"name" json:"name"`
+	HashedSecret   []byte           `db:"hashed_secret" json:"hashed_secret"`
+	Tags           dbtype.StringMap `db:"tags" json:"tags"`
+}
+
+func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerKey,
+		arg.ID,
+		arg.CreatedAt,
+		arg.OrganizationID,
+		arg.Name,
+		arg.HashedSecret,
+		arg.Tags,
+	)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
+FROM
+	workspace_proxies
+WHERE
+	deleted = false
+`
+
+func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceProxy
+	for rows.Next() {
+		var i WorkspaceProxy
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Url,
+			&i.WildcardHostname,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.TokenHashedSecret,
+			&i.Version,
+			&i.LastHeartbeatAt,
+			&i.HealthCheckedAt,
+			&i.HealthError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
+FROM
+	workspace_proxies
+WHERE
+	-- Validate that the @hostname has been sanitized and is not empty. This
+	-- doesn't prevent SQL injection (already prevented by using prepared
+	-- queries), but it does prevent carefully crafted hostnames from matching
+	-- when they shouldn't.
+	--
+	-- Periods don't need to be escaped because they're not special characters
+	-- in SQL matches unlike regular expressions.
+	$1 :: text SIMILAR TO '[a-zA-Z0-9.-]+' AND
+	deleted = false AND
+
+	-- Validate that the hostname matches either the wildcard hostname or the
+	-- access URL (ignoring scheme, port and path).
+	(
+		url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*' OR
+		$1 :: text LIKE replace(wildcard_hostname, '*', '%')
+	)
+LIMIT
+	1
+`
+
+// Finds a workspace proxy that has an access URL or app hostname that matches
+// the provided hostname. This is to check if a hostname matches any workspace
+// proxy.
+//
+// The hostname must be sanitized to only contain [a-zA-Z0-9.-] before calling
+// this query. The scheme, port and path should be stripped.
+func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, hostname string) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByHostname, hostname)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.Version,
+		&i.LastHeartbeatAt,
+		&i.HealthCheckedAt,
+		&i.HealthError,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
+FROM
+	workspace_proxies
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByID, id)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.Version,
+		&i.LastHeartbeatAt,
+		&i.HealthCheckedAt,
+		&i.HealthError,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
+FROM
+	workspace_proxies
+WHERE
+	name = $1
+	AND deleted = false
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByName, name)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.Version,
+		&i.LastHeartbeatAt,
+		&i.HealthCheckedAt,
+		&i.HealthError,
+	)
+	return i, err
 }
 
 const insertWorkspaceProxy = `-- name: InsertWorkspaceProxy :one
@@ -2952,7 +3436,7 @@ INSERT INTO
 		deleted
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
 `
 
 type InsertWorkspaceProxyParams struct {
@@ -2991,43 +3475,32 @@ func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspa
 		&i.UpdatedAt,
 		&i.Deleted,
 		&i.TokenHashedSecret,
+		&i.Version,
+		&i.LastHeartbeatAt,
+		&i.HealthCheckedAt,
+		&i.HealthError,
 	)
 	return i, err
 }
 
-const updateWorkspaceProxy = `-- name: UpdateWorkspaceProxy :one
+const registerWorkspaceProxy = `-- name: RegisterWorkspaceProxy :one
 UPDATE
 	workspace_proxies
 SET
-	name = $1,
-	display_name = $2,
-	url = $3,
-	wildcard_hostname = $4,
-	icon = $5,
-	updated_at = Now()
+	version = $1,
+	last_heartbeat_at = Now()
 WHERE
-	id = $6
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret
+	id = $2
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
 `
 
-type UpdateWorkspaceProxyParams struct {
-	Name             string    `db:"name" json:"name"`
-	DisplayName      string    `db:"display_name" json:"display_name"`
-	Url              string    `db:"url" json:"url"`
-	WildcardHostname string    `db:"wildcard_hostname" json:"wildcard_hostname"`
-	Icon             string    `db:"icon" json:"icon"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type RegisterWorkspaceProxyParams struct {
+	Version string    `db:"version" json:"version"`
+	ID      uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceProxy,
-		arg.Name,
-		arg.DisplayName,
-		arg.Url,
-		arg.WildcardHostname,
-		arg.Icon,
-		arg.ID,
-	)
+func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, registerWorkspaceProxy, arg.Version, arg.ID)
 	var i WorkspaceProxy
 	err := row.Scan(
 		&i.ID,
@@ -3040,23 +3513,88 @@ func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspa
 		&i.UpdatedAt,
 		&i.Deleted,
 		&i.TokenHashedSecret,
+		&i.Version,
+		&i.LastHeartbeatAt,
+		&i.HealthCheckedAt,
+		&i.HealthError,
 	)
 	return i, err
 }
 
-const updateWorkspaceProxyDeleted = `-- name: UpdateWorkspaceProxyDeleted :exec
+const updateWorkspaceProxy = `-- name: UpdateWorkspaceProxy :one
 UPDATE
 	workspace_proxies
 SET
-	updated_at = Now(),
-	deleted = $1
+	name = $1,
+	display_name = $2,
+	url = $3,
+	wildcard_hostname = $4,
+	icon = $5,
+	-- Only update the token if a new one is provided.
+	-- So this is an optional field.
+	token_hashed_secret = CASE
+		WHEN length($6 :: bytea) > 0 THEN $6 :: bytea
+		ELSE workspace_proxies.token_hashed_secret
+	END,
+	updated_at = Now()
 WHERE
-	id = $2
+	id = $7
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, version, last_heartbeat_at, health_checked_at, health_error
 `
 
-type UpdateWorkspaceProxyDeletedParams struct {
-	Deleted bool      `db:"deleted" json:"deleted"`
-	ID      uuid.UUID `db:"id" json:"id"`
+type UpdateWorkspaceProxyParams struct {
+	Name              string    `db:"name" json:"name"`
+	DisplayName       string    `db:"display_name" json:"display_name"`
+	Url               string    `db:"url" json:"url"`
+	WildcardHostname  string    `db:"wildcard_hostname" json:"wildcard_hostname"`
+	Icon              string    `db:"icon" json:"icon"`
+	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
+	ID                uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceProxy,
+		arg.Name,
+		arg.DisplayName,
+		arg.Url,
+		arg.WildcardHostname,
+		arg.Icon,
+		arg.TokenHashedSecret,
+		arg.ID,
+	)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.Version,
+		&i.LastHeartbeatAt,
+		&i.HealthCheckedAt,
+		&i.HealthError,
+	)
+	return i, err
+}
+
+const updateWorkspaceProxyDeleted = `-- name: UpdateWorkspaceProxyDeleted :exec
+UPDATE
+	workspace_proxies
+SET
+	updated_at = Now(),
+	deleted = $1
+WHERE
+	id = $2
+`
+
+type UpdateWorkspaceProxyDeletedParams struct {
+	Deleted bool      `db:"deleted" json:"deleted"`
+	ID      uuid.UUID `db:"id" json:"id"`
 }
 
 func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error {
@@ -3064,6 +3602,27 @@ func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg Update
 	return err
 }
 
+const updateWorkspaceProxyHealth = `-- name: UpdateWorkspaceProxyHealth :exec
+UPDATE
+	workspace_proxies
+SET
+	health_checked_at = $1,
+	health_error = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceProxyHealthParams struct {
+	HealthCheckedAt sql.NullTime `db:"health_checked_at" json:"health_checked_at"`
+	HealthError     string       `db:"health_error" json:"health_error"`
+	ID              uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceProxyHealth(ctx context.Context, arg UpdateWorkspaceProxyHealthParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceProxyHealth, arg.HealthCheckedAt, arg.HealthError, arg.ID)
+	return err
+}
+
 const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
 SELECT
 	coalesce(SUM(quota_allowance), 0)::BIGINT
@@ -3082,6 +3641,35 @@ func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.U
 	return column_1, err
 }
 
+const getQuotaConsumedForOrganization = `-- name: GetQuotaConsumedForOrganization :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.organization_id = $1
+`
+
+func (q *sqlQuerier) GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForOrganization, organizationID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
 WITH latest_builds AS (
 SELECT
@@ -3111,6 +3699,87 @@ func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.U
 	return column_1, err
 }
 
+const getQuotaSpendSegments = `-- name: GetQuotaSpendSegments :many
+WITH segments AS (
+SELECT
+	wb.workspace_id,
+	w.owner_id,
+	w.template_id,
+	wb.daily_cost,
+	wb.created_at AS started_at,
+	coalesce(
+		lead(wb.created_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.created_at),
+		$1::timestamptz
+	)::timestamptz AS ended_at
+FROM
+	workspace_builds wb
+JOIN workspaces w ON
+	w.id = wb.workspace_id
+)
+SELECT
+	workspace_id,
+	owner_id,
+	template_id,
+	daily_cost,
+	started_at,
+	ended_at
+FROM
+	segments
+WHERE
+	daily_cost > 0
+	AND started_at < $1::timestamptz
+	AND ended_at > $2::timestamptz
+ORDER BY
+	started_at ASC
+`
+
+type GetQuotaSpendSegmentsParams struct {
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
+}
+
+type GetQuotaSpendSegmentsRow struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OwnerID     uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	DailyCost   int32     `db:"daily_cost" json:"daily_cost"`
+	StartedAt   time.Time `db:"started_at" json:"started_at"`
+	EndedAt     time.Time `db:"ended_at" json:"ended_at"`
+}
+
+// A workspace build consumes its daily cost from the time it was created until
+// the next build of the workspace was created. Segments that are still in
+// effect end at @end_time.
+func (q *sqlQuerier) GetQuotaSpendSegments(ctx context.Context, arg GetQuotaSpendSegmentsParams) ([]GetQuotaSpendSegmentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuotaSpendSegments, arg.EndTime, arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuotaSpendSegmentsRow
+	for rows.Next() {
+		var i GetQuotaSpendSegmentsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.OwnerID,
+			&i.TemplateID,
+			&i.DailyCost,
+			&i.StartedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
 DELETE FROM replicas WHERE updated_at < $1
 `
@@ -3271,6 +3940,181 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 	return i, err
 }
 
+const customRoles = `-- name: CustomRoles :many
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	true
+	-- Site wide roles are looked up by their name, organization roles by
+	-- "<name>:<organization_id>", matching how they are stored on users and
+	-- organization members.
+	AND CASE WHEN array_length($1 :: text[], 1) > 0 THEN
+		CASE WHEN organization_id IS NULL THEN
+			name ILIKE ANY($1 :: text[])
+		ELSE
+			(name || ':' || organization_id :: text) ILIKE ANY($1 :: text[])
+		END
+	ELSE true
+	END
+	AND CASE WHEN $2 :: boolean THEN
+		organization_id IS NULL
+	ELSE true
+	END
+	AND CASE WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+		organization_id = $3
+	ELSE true
+	END
+ORDER BY
+	name ASC
+`
+
+type CustomRolesParams struct {
+	LookupRoles     []string  `db:"lookup_roles" json:"lookup_roles"`
+	ExcludeOrgRoles bool      `db:"exclude_org_roles" json:"exclude_org_roles"`
+	OrganizationID  uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, customRoles, pq.Array(arg.LookupRoles), arg.ExcludeOrgRoles, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomRole
+	for rows.Next() {
+		var i CustomRole
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertCustomRole = `-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type InsertCustomRoleParams struct {
+	ID              uuid.UUID             `db:"id" json:"id"`
+	Name            string                `db:"name" json:"name"`
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, insertCustomRole,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateCustomRole = `-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
+SET
+	display_name = $1,
+	site_permissions = $2,
+	org_permissions = $3,
+	user_permissions = $4,
+	updated_at = $5
+WHERE
+	lower(name) = lower($6)
+	AND organization_id IS NOT DISTINCT FROM $7
+RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type UpdateCustomRoleParams struct {
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+	Name            string                `db:"name" json:"name"`
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, updateCustomRole,
+		arg.DisplayName,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.OrganizationID,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getAppSecurityKey = `-- name: GetAppSecurityKey :one
 SELECT value FROM site_configs WHERE key = 'app_signing_key'
 `
@@ -3326,6 +4170,17 @@ func (q *sqlQuerier) GetLogoURL(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const getSSHCAKey = `-- name: GetSSHCAKey :one
+SELECT value FROM site_configs WHERE key = 'ssh_ca_key'
+`
+
+func (q *sqlQuerier) GetSSHCAKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getSSHCAKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getServiceBanner = `-- name: GetServiceBanner :one
 SELECT value FROM site_configs WHERE key = 'service_banner'
 `
@@ -3385,6 +4240,16 @@ func (q *sqlQuerier) UpsertLogoURL(ctx context.Context, value string) error {
 	return err
 }
 
+const upsertSSHCAKey = `-- name: UpsertSSHCAKey :exec
+INSERT INTO site_configs (key, value) VALUES ('ssh_ca_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'ssh_ca_key'
+`
+
+func (q *sqlQuerier) UpsertSSHCAKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertSSHCAKey, value)
+	return err
+}
+
 const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
 INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
@@ -3457,7 +4322,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 FROM
 	templates
 WHERE
@@ -3489,13 +4354,23 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.MaxTTL,
 		&i.AllowUserAutostart,
 		&i.AllowUserAutostop,
+		&i.InactivityTTL,
+		&i.DormantAutoDeleteTTL,
+		&i.FailureTTL,
+		&i.RestartRequirementDaysOfWeek,
+		&i.RestartRequirementWeeks,
+		&i.Deprecated,
+		&i.RequireActiveVersion,
+		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.QuotaCostMultiplier,
 	)
 	return i, err
 }
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 FROM
 	templates
 WHERE
@@ -3535,12 +4410,22 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.MaxTTL,
 		&i.AllowUserAutostart,
 		&i.AllowUserAutostop,
+		&i.InactivityTTL,
+		&i.DormantAutoDeleteTTL,
+		&i.FailureTTL,
+		&i.RestartRequirementDaysOfWeek,
+		&i.RestartRequirementWeeks,
+		&i.Deprecated,
+		&i.RequireActiveVersion,
+		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.QuotaCostMultiplier,
 	)
 	return i, err
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop FROM templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier FROM templates
 ORDER BY (name, id) ASC
 `
 
@@ -3573,6 +4458,16 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.MaxTTL,
 			&i.AllowUserAutostart,
 			&i.AllowUserAutostop,
+			&i.InactivityTTL,
+			&i.DormantAutoDeleteTTL,
+			&i.FailureTTL,
+			&i.RestartRequirementDaysOfWeek,
+			&i.RestartRequirementWeeks,
+			&i.Deprecated,
+			&i.RequireActiveVersion,
+			&i.MaxPortSharingLevel,
+			&i.SessionRecordingEnabled,
+			&i.QuotaCostMultiplier,
 		); err != nil {
 			return nil, err
 		}
@@ -3589,7 +4484,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 FROM
 	templates
 WHERE
@@ -3613,16 +4508,23 @@ WHERE
 			id = ANY($4)
 		ELSE true
 	END
+	-- Optionally exclude deprecated templates
+	AND CASE
+		WHEN $5 :: boolean THEN
+			deprecated = ''
+		ELSE true
+	END
   -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
   -- @authorize_filter
 ORDER BY (name, id) ASC
 `
 
 type GetTemplatesWithFilterParams struct {
-	Deleted        bool        `db:"deleted" json:"deleted"`
-	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
-	ExactName      string      `db:"exact_name" json:"exact_name"`
-	IDs            []uuid.UUID `db:"ids" json:"ids"`
+	Deleted           bool        `db:"deleted" json:"deleted"`
+	OrganizationID    uuid.UUID   `db:"organization_id" json:"organization_id"`
+	ExactName         string      `db:"exact_name" json:"exact_name"`
+	IDs               []uuid.UUID `db:"ids" json:"ids"`
+	ExcludeDeprecated bool        `db:"exclude_deprecated" json:"exclude_deprecated"`
 }
 
 func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
@@ -3631,6 +4533,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 		arg.OrganizationID,
 		arg.ExactName,
 		pq.Array(arg.IDs),
+		arg.ExcludeDeprecated,
 	)
 	if err != nil {
 		return nil, err
@@ -3659,6 +4562,16 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.MaxTTL,
 			&i.AllowUserAutostart,
 			&i.AllowUserAutostop,
+			&i.InactivityTTL,
+			&i.DormantAutoDeleteTTL,
+			&i.FailureTTL,
+			&i.RestartRequirementDaysOfWeek,
+			&i.RestartRequirementWeeks,
+			&i.Deprecated,
+			&i.RequireActiveVersion,
+			&i.MaxPortSharingLevel,
+			&i.SessionRecordingEnabled,
+			&i.QuotaCostMultiplier,
 		); err != nil {
 			return nil, err
 		}
@@ -3692,7 +4605,7 @@ INSERT INTO
 		allow_user_cancel_workspace_jobs
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 `
 
 type InsertTemplateParams struct {
@@ -3750,6 +4663,16 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		&i.MaxTTL,
 		&i.AllowUserAutostart,
 		&i.AllowUserAutostop,
+		&i.InactivityTTL,
+		&i.DormantAutoDeleteTTL,
+		&i.FailureTTL,
+		&i.RestartRequirementDaysOfWeek,
+		&i.RestartRequirementWeeks,
+		&i.Deprecated,
+		&i.RequireActiveVersion,
+		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.QuotaCostMultiplier,
 	)
 	return i, err
 }
@@ -3763,7 +4686,7 @@ SET
 WHERE
 	id = $3
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 `
 
 type UpdateTemplateACLByIDParams struct {
@@ -3795,6 +4718,16 @@ func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTempla
 		&i.MaxTTL,
 		&i.AllowUserAutostart,
 		&i.AllowUserAutostop,
+		&i.InactivityTTL,
+		&i.DormantAutoDeleteTTL,
+		&i.FailureTTL,
+		&i.RestartRequirementDaysOfWeek,
+		&i.RestartRequirementWeeks,
+		&i.Deprecated,
+		&i.RequireActiveVersion,
+		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.QuotaCostMultiplier,
 	)
 	return i, err
 }
@@ -3850,21 +4783,31 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	deprecated = $8,
+	require_active_version = $9,
+	max_port_sharing_level = $10,
+	session_recording_enabled = $11,
+	quota_cost_multiplier = $12
 WHERE
 	id = $1
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
-	Description                  string    `db:"description" json:"description"`
-	Name                         string    `db:"name" json:"name"`
-	Icon                         string    `db:"icon" json:"icon"`
-	DisplayName                  string    `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool      `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	ID                           uuid.UUID       `db:"id" json:"id"`
+	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
+	Description                  string          `db:"description" json:"description"`
+	Name                         string          `db:"name" json:"name"`
+	Icon                         string          `db:"icon" json:"icon"`
+	DisplayName                  string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	Deprecated                   string          `db:"deprecated" json:"deprecated"`
+	RequireActiveVersion         bool            `db:"require_active_version" json:"require_active_version"`
+	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	SessionRecordingEnabled      bool            `db:"session_recording_enabled" json:"session_recording_enabled"`
+	QuotaCostMultiplier          float64         `db:"quota_cost_multiplier" json:"quota_cost_multiplier"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) (Template, error) {
@@ -3876,6 +4819,11 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.Icon,
 		arg.DisplayName,
 		arg.AllowUserCancelWorkspaceJobs,
+		arg.Deprecated,
+		arg.RequireActiveVersion,
+		arg.MaxPortSharingLevel,
+		arg.SessionRecordingEnabled,
+		arg.QuotaCostMultiplier,
 	)
 	var i Template
 	err := row.Scan(
@@ -3898,6 +4846,16 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		&i.MaxTTL,
 		&i.AllowUserAutostart,
 		&i.AllowUserAutostop,
+		&i.InactivityTTL,
+		&i.DormantAutoDeleteTTL,
+		&i.FailureTTL,
+		&i.RestartRequirementDaysOfWeek,
+		&i.RestartRequirementWeeks,
+		&i.Deprecated,
+		&i.RequireActiveVersion,
+		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.QuotaCostMultiplier,
 	)
 	return i, err
 }
@@ -3910,20 +4868,30 @@ SET
 	allow_user_autostart = $3,
 	allow_user_autostop = $4,
 	default_ttl = $5,
-	max_ttl = $6
+	max_ttl = $6,
+	inactivity_ttl = $7,
+	dormant_autodelete_ttl = $8,
+	failure_ttl = $9,
+	restart_requirement_days_of_week = $10,
+	restart_requirement_weeks = $11
 WHERE
 	id = $1
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, inactivity_ttl, dormant_autodelete_ttl, failure_ttl, restart_requirement_days_of_week, restart_requirement_weeks, deprecated, require_active_version, max_port_sharing_level, session_recording_enabled, quota_cost_multiplier
 `
 
 type UpdateTemplateScheduleByIDParams struct {
-	ID                 uuid.UUID `db:"id" json:"id"`
-	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
-	AllowUserAutostart bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop  bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
-	DefaultTTL         int64     `db:"default_ttl" json:"default_ttl"`
-	MaxTTL             int64     `db:"max_ttl" json:"max_ttl"`
+	ID                           uuid.UUID `db:"id" json:"id"`
+	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
+	AllowUserAutostart           bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop            bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
+	DefaultTTL                   int64     `db:"default_ttl" json:"default_ttl"`
+	MaxTTL                       int64     `db:"max_ttl" json:"max_ttl"`
+	InactivityTTL                int64     `db:"inactivity_ttl" json:"inactivity_ttl"`
+	DormantAutoDeleteTTL         int64     `db:"dormant_autodelete_ttl" json:"dormant_autodelete_ttl"`
+	FailureTTL                   int64     `db:"failure_ttl" json:"failure_ttl"`
+	RestartRequirementDaysOfWeek int16     `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
+	RestartRequirementWeeks      int64     `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
 }
 
 func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) (Template, error) {
@@ -3934,6 +4902,11 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 		arg.AllowUserAutostop,
 		arg.DefaultTTL,
 		arg.MaxTTL,
+		arg.InactivityTTL,
+		arg.DormantAutoDeleteTTL,
+		arg.FailureTTL,
+		arg.RestartRequirementDaysOfWeek,
+		arg.RestartRequirementWeeks,
 	)
 	var i Template
 	err := row.Scan(
@@ -3956,6 +4929,16 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 		&i.MaxTTL,
 		&i.AllowUserAutostart,
 		&i.AllowUserAutostop,
+		&i.InactivityTTL,
+		&i.DormantAutoDeleteTTL,
+		&i.FailureTTL,
+		&i.RestartRequirementDaysOfWeek,
+		&i.RestartRequirementWeeks,
+		&i.Deprecated,
+		&i.RequireActiveVersion,
+		&i.MaxPortSharingLevel,
+		&i.SessionRecordingEnabled,
+		&i.QuotaCostMultiplier,
 	)
 	return i, err
 }
@@ -4105,21 +5088,130 @@ func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg Ins
 	return i, err
 }
 
-const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+const getTemplateVersionPromotionsByTemplateID = `-- name: GetTemplateVersionPromotionsByTemplateID :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id, template_id, template_version_id, promoted_at, promoted_by, rolled_back_at
 FROM
-	template_versions
+	template_version_promotions
 WHERE
-	created_at < (
-		SELECT created_at
-		FROM template_versions AS tv
-		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
-	)
-	AND organization_id = $1
-	AND template_id = $3
-ORDER BY created_at DESC
-LIMIT 1
+	template_id = $1
+	AND rolled_back_at IS NULL
+ORDER BY
+	promoted_at DESC
+`
+
+// Returns the promotions of the template that weren't rolled back, most
+// recent first.
+func (q *sqlQuerier) GetTemplateVersionPromotionsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateVersionPromotion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPromotionsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPromotion
+	for rows.Next() {
+		var i TemplateVersionPromotion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.RolledBackAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionPromotion = `-- name: InsertTemplateVersionPromotion :one
+INSERT INTO
+	template_version_promotions (
+		id,
+		template_id,
+		template_version_id,
+		promoted_at,
+		promoted_by
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, template_id, template_version_id, promoted_at, promoted_by, rolled_back_at
+`
+
+type InsertTemplateVersionPromotionParams struct {
+	ID                uuid.UUID     `db:"id" json:"id"`
+	TemplateID        uuid.UUID     `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID     `db:"template_version_id" json:"template_version_id"`
+	PromotedAt        time.Time     `db:"promoted_at" json:"promoted_at"`
+	PromotedBy        uuid.NullUUID `db:"promoted_by" json:"promoted_by"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionPromotion(ctx context.Context, arg InsertTemplateVersionPromotionParams) (TemplateVersionPromotion, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionPromotion,
+		arg.ID,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.PromotedAt,
+		arg.PromotedBy,
+	)
+	var i TemplateVersionPromotion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.PromotedAt,
+		&i.PromotedBy,
+		&i.RolledBackAt,
+	)
+	return i, err
+}
+
+const updateTemplateVersionPromotionsRolledBack = `-- name: UpdateTemplateVersionPromotionsRolledBack :exec
+UPDATE
+	template_version_promotions
+SET
+	rolled_back_at = $1
+WHERE
+	template_id = $2
+	AND rolled_back_at IS NULL
+	AND promoted_at > $3
+`
+
+type UpdateTemplateVersionPromotionsRolledBackParams struct {
+	RolledBackAt  sql.NullTime `db:"rolled_back_at" json:"rolled_back_at"`
+	TemplateID    uuid.UUID    `db:"template_id" json:"template_id"`
+	PromotedAfter time.Time    `db:"promoted_after" json:"promoted_after"`
+}
+
+// Rolls back the promotions of the template that are more recent than
+// promoted_after.
+func (q *sqlQuerier) UpdateTemplateVersionPromotionsRolledBack(ctx context.Context, arg UpdateTemplateVersionPromotionsRolledBackParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionPromotionsRolledBack, arg.RolledBackAt, arg.TemplateID, arg.PromotedAfter)
+	return err
+}
+
+const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
+FROM
+	template_versions
+WHERE
+	created_at < (
+		SELECT created_at
+		FROM template_versions AS tv
+		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
+	)
+	AND organization_id = $1
+	AND template_id = $3
+ORDER BY created_at DESC
+LIMIT 1
 `
 
 type GetPreviousTemplateVersionParams struct {
@@ -4142,13 +5234,15 @@ func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPrev
 		&i.JobID,
 		&i.CreatedBy,
 		pq.Array(&i.GitAuthProviders),
+		&i.PromotedAt,
+		&i.PromotedBy,
 	)
 	return i, err
 }
 
 const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 FROM
 	template_versions
 WHERE
@@ -4169,13 +5263,15 @@ func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (
 		&i.JobID,
 		&i.CreatedBy,
 		pq.Array(&i.GitAuthProviders),
+		&i.PromotedAt,
+		&i.PromotedBy,
 	)
 	return i, err
 }
 
 const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 FROM
 	template_versions
 WHERE
@@ -4196,13 +5292,15 @@ func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.U
 		&i.JobID,
 		&i.CreatedBy,
 		pq.Array(&i.GitAuthProviders),
+		&i.PromotedAt,
+		&i.PromotedBy,
 	)
 	return i, err
 }
 
 const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 FROM
 	template_versions
 WHERE
@@ -4229,13 +5327,15 @@ func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context,
 		&i.JobID,
 		&i.CreatedBy,
 		pq.Array(&i.GitAuthProviders),
+		&i.PromotedAt,
+		&i.PromotedBy,
 	)
 	return i, err
 }
 
 const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 FROM
 	template_versions
 WHERE
@@ -4262,6 +5362,8 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 			&i.JobID,
 			&i.CreatedBy,
 			pq.Array(&i.GitAuthProviders),
+			&i.PromotedAt,
+			&i.PromotedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -4278,7 +5380,7 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 
 const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 FROM
 	template_versions
 WHERE
@@ -4343,6 +5445,8 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 			&i.JobID,
 			&i.CreatedBy,
 			pq.Array(&i.GitAuthProviders),
+			&i.PromotedAt,
+			&i.PromotedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -4358,7 +5462,7 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 }
 
 const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers FROM template_versions WHERE created_at > $1
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by FROM template_versions WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
@@ -4381,6 +5485,8 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 			&i.JobID,
 			&i.CreatedBy,
 			pq.Array(&i.GitAuthProviders),
+			&i.PromotedAt,
+			&i.PromotedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -4409,7 +5515,7 @@ INSERT INTO
 		created_by
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 `
 
 type InsertTemplateVersionParams struct {
@@ -4448,6 +5554,8 @@ func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTempla
 		&i.JobID,
 		&i.CreatedBy,
 		pq.Array(&i.GitAuthProviders),
+		&i.PromotedAt,
+		&i.PromotedBy,
 	)
 	return i, err
 }
@@ -4460,7 +5568,7 @@ SET
 	updated_at = $3,
 	name = $4
 WHERE
-	id = $1 RETURNING id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
+	id = $1 RETURNING id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, promoted_at, promoted_by
 `
 
 type UpdateTemplateVersionByIDParams struct {
@@ -4489,6 +5597,8 @@ func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTe
 		&i.JobID,
 		&i.CreatedBy,
 		pq.Array(&i.GitAuthProviders),
+		&i.PromotedAt,
+		&i.PromotedBy,
 	)
 	return i, err
 }
@@ -4535,6 +5645,27 @@ func (q *sqlQuerier) UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.Co
 	return err
 }
 
+const updateTemplateVersionPromotedByID = `-- name: UpdateTemplateVersionPromotedByID :exec
+UPDATE
+	template_versions
+SET
+	promoted_at = $2,
+	promoted_by = $3
+WHERE
+	id = $1
+`
+
+type UpdateTemplateVersionPromotedByIDParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	PromotedAt sql.NullTime  `db:"promoted_at" json:"promoted_at"`
+	PromotedBy uuid.NullUUID `db:"promoted_by" json:"promoted_by"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionPromotedByID(ctx context.Context, arg UpdateTemplateVersionPromotedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionPromotedByID, arg.ID, arg.PromotedAt, arg.PromotedBy)
+	return err
+}
+
 const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
 SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
 `
@@ -4925,7 +6056,7 @@ func (q *sqlQuerier) GetFilteredUserCount(ctx context.Context, arg GetFilteredUs
 
 const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
 FROM
 	users
 WHERE
@@ -4956,174 +6087,707 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.AvatarURL,
 		&i.Deleted,
 		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+FROM
+	users
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const getUserCount = `-- name: GetUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	deleted = false
+`
+
+func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, COUNT(*) OVER() AS count
+FROM
+	users
+WHERE
+	users.deleted = false
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the created_at field, so select all
+			-- rows after the cursor.
+			(created_at, id) > (
+				SELECT
+					created_at, id
+				FROM
+					users
+				WHERE
+					id = $1
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by name, email or username
+	AND CASE
+		WHEN $2 :: text != '' THEN (
+			email ILIKE concat('%', $2, '%')
+			OR username ILIKE concat('%', $2, '%')
+		)
+		ELSE true
+	END
+	-- Filter by status
+	AND CASE
+		-- @status needs to be a text because it can be empty, If it was
+		-- user_status enum, it would not.
+		WHEN cardinality($3 :: user_status[]) > 0 THEN
+			status = ANY($3 :: user_status[])
+		ELSE true
+	END
+	-- Filter by rbac_roles
+	AND CASE
+		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
+	    -- everyone is a member.
+		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
+		    rbac_roles && $4 :: text[]
+		ELSE true
+	END
+	-- End of filters
+ORDER BY
+	-- Deterministic and consistent ordering of all users, even if they share
+	-- a timestamp. This is to ensure consistent pagination.
+	(created_at, id) ASC OFFSET $5
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($6 :: int, 0)
+`
+
+type GetUsersParams struct {
+	AfterID   uuid.UUID    `db:"after_id" json:"after_id"`
+	Search    string       `db:"search" json:"search"`
+	Status    []UserStatus `db:"status" json:"status"`
+	RbacRole  []string     `db:"rbac_role" json:"rbac_role"`
+	OffsetOpt int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt  int32        `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetUsersRow struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	Email              string         `db:"email" json:"email"`
+	Username           string         `db:"username" json:"username"`
+	HashedPassword     []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+	Status             UserStatus     `db:"status" json:"status"`
+	RBACRoles          pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType          LoginType      `db:"login_type" json:"login_type"`
+	AvatarURL          sql.NullString `db:"avatar_url" json:"avatar_url"`
+	Deleted            bool           `db:"deleted" json:"deleted"`
+	LastSeenAt         time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	Count              int64          `db:"count" json:"count"`
+}
+
+// This will never return deleted users.
+func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers,
+		arg.AfterID,
+		arg.Search,
+		pq.Array(arg.Status),
+		pq.Array(arg.RbacRole),
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule FROM users WHERE id = ANY($1 :: uuid [ ])
+`
+
+// This shouldn't check for deleted, because it's frequently used
+// to look up references to actions. eg. a user could build a workspace
+// for another user, then be deleted... we still want them to appear!
+func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUser = `-- name: InsertUser :one
+INSERT INTO
+	users (
+		id,
+		email,
+		username,
+		hashed_password,
+		created_at,
+		updated_at,
+		rbac_roles,
+		login_type
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+`
+
+type InsertUserParams struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	Email          string         `db:"email" json:"email"`
+	Username       string         `db:"username" json:"username"`
+	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType      LoginType      `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, insertUser,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.HashedPassword,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.RBACRoles,
+		arg.LoginType,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
+UPDATE
+	users
+SET
+	deleted = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserDeletedByIDParams struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	Deleted bool      `db:"deleted" json:"deleted"`
+}
+
+func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, arg.ID, arg.Deleted)
+	return err
+}
+
+const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
+UPDATE
+	users
+SET
+	hashed_password = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserHashedPasswordParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
+UPDATE
+	users
+SET
+	last_seen_at = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+`
+
+type UpdateUserLastSeenAtParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE
+	users
+SET
+	email = $2,
+	username = $3,
+	avatar_url = $4,
+	updated_at = $5
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+`
+
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	Email     string         `db:"email" json:"email"`
+	Username  string         `db:"username" json:"username"`
+	AvatarURL sql.NullString `db:"avatar_url" json:"avatar_url"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.AvatarURL,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
+UPDATE
+	users
+SET
+	quiet_hours_schedule = $2
+WHERE
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+`
+
+type UpdateUserQuietHoursScheduleParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+}
+
+func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE
+	users
+SET
+	-- Remove all duplicates from the roles.
+	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+WHERE
+	id = $2
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+`
+
+type UpdateUserRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	ID           uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
+	users
+SET
+	status = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+`
+
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
 	)
 	return i, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
-FROM
-	users
+const acquireWebhookDelivery = `-- name: AcquireWebhookDelivery :one
+UPDATE
+	webhook_deliveries
+SET
+	next_attempt_at = $1 :: timestamptz
 WHERE
-	id = $1
-LIMIT
-	1
+	id = (
+		SELECT
+			id
+		FROM
+			webhook_deliveries AS nested
+		WHERE
+			nested.next_attempt_at IS NOT NULL AND
+			nested.next_attempt_at <= $2 :: timestamptz
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			1
+	)
+RETURNING id, webhook_id, event, payload, created_at, attempts, next_attempt_at, delivered_at, last_status_code, last_error
 `
 
-func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
-	var i User
+type AcquireWebhookDeliveryParams struct {
+	LeaseUntil time.Time `db:"lease_until" json:"lease_until"`
+	Now        time.Time `db:"now" json:"now"`
+}
+
+// Acquires the oldest pending delivery that is due. The delivery is leased by
+// pushing next_attempt_at to @lease_until, so that other replicas don't pick it
+// up while it's being attempted. SKIP LOCKED prevents replicas from blocking on
+// each other.
+func (q *sqlQuerier) AcquireWebhookDelivery(ctx context.Context, arg AcquireWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, acquireWebhookDelivery, arg.LeaseUntil, arg.Now)
+	var i WebhookDelivery
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.LastStatusCode,
+		&i.LastError,
 	)
 	return i, err
 }
 
-const getUserCount = `-- name: GetUserCount :one
+const deleteOldWebhookDeliveries = `-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM webhook_deliveries WHERE created_at < NOW() - INTERVAL '30 days'
+`
+
+func (q *sqlQuerier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWebhookDeliveries)
+	return err
+}
+
+const deleteWebhookByID = `-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookByID, id)
+	return err
+}
+
+const getEnabledWebhooksByEvent = `-- name: GetEnabledWebhooksByEvent :many
 SELECT
-	COUNT(*)
+	id, name, url, secret, events, enabled, created_by, created_at, updated_at
 FROM
-	users
+	webhooks
 WHERE
-	deleted = false
+	enabled = true AND
+	$1 :: webhook_event = ANY(events)
 `
 
-func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getUserCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+func (q *sqlQuerier) GetEnabledWebhooksByEvent(ctx context.Context, event WebhookEvent) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getEnabledWebhooksByEvent, event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.Enabled,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUsers = `-- name: GetUsers :many
+const getWebhookByID = `-- name: GetWebhookByID :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, COUNT(*) OVER() AS count
+	id, name, url, secret, events, enabled, created_by, created_at, updated_at
 FROM
-	users
+	webhooks
 WHERE
-	users.deleted = false
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the created_at field, so select all
-			-- rows after the cursor.
-			(created_at, id) > (
-				SELECT
-					created_at, id
-				FROM
-					users
-				WHERE
-					id = $1
-			)
-		)
-		ELSE true
-	END
-	-- Start filters
-	-- Filter by name, email or username
-	AND CASE
-		WHEN $2 :: text != '' THEN (
-			email ILIKE concat('%', $2, '%')
-			OR username ILIKE concat('%', $2, '%')
-		)
-		ELSE true
-	END
-	-- Filter by status
-	AND CASE
-		-- @status needs to be a text because it can be empty, If it was
-		-- user_status enum, it would not.
-		WHEN cardinality($3 :: user_status[]) > 0 THEN
-			status = ANY($3 :: user_status[])
-		ELSE true
-	END
-	-- Filter by rbac_roles
-	AND CASE
-		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
-	    -- everyone is a member.
-		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
-		    rbac_roles && $4 :: text[]
-		ELSE true
-	END
-	-- End of filters
-ORDER BY
-	-- Deterministic and consistent ordering of all users, even if they share
-	-- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $5
+	id = $1
 LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($6 :: int, 0)
+	1
 `
 
-type GetUsersParams struct {
-	AfterID   uuid.UUID    `db:"after_id" json:"after_id"`
-	Search    string       `db:"search" json:"search"`
-	Status    []UserStatus `db:"status" json:"status"`
-	RbacRole  []string     `db:"rbac_role" json:"rbac_role"`
-	OffsetOpt int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt  int32        `db:"limit_opt" json:"limit_opt"`
+func (q *sqlQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Enabled,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-type GetUsersRow struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	Status         UserStatus     `db:"status" json:"status"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
-	AvatarURL      sql.NullString `db:"avatar_url" json:"avatar_url"`
-	Deleted        bool           `db:"deleted" json:"deleted"`
-	LastSeenAt     time.Time      `db:"last_seen_at" json:"last_seen_at"`
-	Count          int64          `db:"count" json:"count"`
+const getWebhookDeliveriesByWebhookID = `-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	id, webhook_id, event, payload, created_at, attempts, next_attempt_at, delivered_at, last_status_code, last_error
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($2 :: int, 0)
+`
+
+type GetWebhookDeliveriesByWebhookIDParams struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
 }
 
-// This will never return deleted users.
-func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUsers,
-		arg.AfterID,
-		arg.Search,
-		pq.Array(arg.Status),
-		pq.Array(arg.RbacRole),
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+func (q *sqlQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveriesByWebhookID, arg.WebhookID, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUsersRow
+	var items []WebhookDelivery
 	for rows.Next() {
-		var i GetUsersRow
+		var i WebhookDelivery
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.Count,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.LastStatusCode,
+			&i.LastError,
 		); err != nil {
 			return nil, err
 		}
@@ -5138,35 +6802,34 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 	return items, nil
 }
 
-const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at FROM users WHERE id = ANY($1 :: uuid [ ])
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT
+	id, name, url, secret, events, enabled, created_by, created_at, updated_at
+FROM
+	webhooks
+ORDER BY
+	name ASC
 `
 
-// This shouldn't check for deleted, because it's frequently used
-// to look up references to actions. eg. a user could build a workspace
-// for another user, then be deleted... we still want them to appear!
-func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+func (q *sqlQuerier) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []Webhook
 	for rows.Next() {
-		var i User
+		var i Webhook
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.Enabled,
+			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -5181,254 +6844,342 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 	return items, nil
 }
 
-const insertUser = `-- name: InsertUser :one
+const insertWebhook = `-- name: InsertWebhook :one
 INSERT INTO
-	users (
+	webhooks (
 		id,
-		email,
-		username,
-		hashed_password,
+		name,
+		url,
+		secret,
+		events,
+		enabled,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, name, url, secret, events, enabled, created_by, created_at, updated_at
+`
+
+type InsertWebhookParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	Name      string         `db:"name" json:"name"`
+	Url       string         `db:"url" json:"url"`
+	Secret    string         `db:"secret" json:"secret"`
+	Events    []WebhookEvent `db:"events" json:"events"`
+	Enabled   bool           `db:"enabled" json:"enabled"`
+	CreatedBy uuid.UUID      `db:"created_by" json:"created_by"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.Enabled,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Enabled,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertWebhookDelivery = `-- name: InsertWebhookDelivery :one
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
 		created_at,
-		updated_at,
-		rbac_roles,
-		login_type
+		next_attempt_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+	($1, $2, $3, $4, $5, $6) RETURNING id, webhook_id, event, payload, created_at, attempts, next_attempt_at, delivered_at, last_status_code, last_error
 `
 
-type InsertUserParams struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
+type InsertWebhookDeliveryParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	WebhookID     uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event         WebhookEvent    `db:"event" json:"event"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	NextAttemptAt sql.NullTime    `db:"next_attempt_at" json:"next_attempt_at"`
 }
 
-func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, insertUser,
+func (q *sqlQuerier) InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhookDelivery,
 		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.HashedPassword,
+		arg.WebhookID,
+		arg.Event,
+		arg.Payload,
 		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.RBACRoles,
-		arg.LoginType,
+		arg.NextAttemptAt,
 	)
-	var i User
+	var i WebhookDelivery
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.LastStatusCode,
+		&i.LastError,
 	)
 	return i, err
 }
 
-const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
+const updateWebhookByID = `-- name: UpdateWebhookByID :one
 UPDATE
-	users
+	webhooks
 SET
-	deleted = $2
+	name = $1,
+	url = $2,
+	events = $3,
+	enabled = $4,
+	updated_at = $5
 WHERE
-	id = $1
+	id = $6
+RETURNING id, name, url, secret, events, enabled, created_by, created_at, updated_at
 `
 
-type UpdateUserDeletedByIDParams struct {
-	ID      uuid.UUID `db:"id" json:"id"`
-	Deleted bool      `db:"deleted" json:"deleted"`
+type UpdateWebhookByIDParams struct {
+	Name      string         `db:"name" json:"name"`
+	Url       string         `db:"url" json:"url"`
+	Events    []WebhookEvent `db:"events" json:"events"`
+	Enabled   bool           `db:"enabled" json:"enabled"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID      `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, arg.ID, arg.Deleted)
-	return err
+func (q *sqlQuerier) UpdateWebhookByID(ctx context.Context, arg UpdateWebhookByIDParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhookByID,
+		arg.Name,
+		arg.Url,
+		pq.Array(arg.Events),
+		arg.Enabled,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Enabled,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
+const updateWebhookDeliveryByID = `-- name: UpdateWebhookDeliveryByID :exec
 UPDATE
-	users
+	webhook_deliveries
 SET
-	hashed_password = $2
+	attempts = $1,
+	next_attempt_at = $2,
+	delivered_at = $3,
+	last_status_code = $4,
+	last_error = $5
 WHERE
-	id = $1
+	id = $6
 `
 
-type UpdateUserHashedPasswordParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+type UpdateWebhookDeliveryByIDParams struct {
+	Attempts       int32        `db:"attempts" json:"attempts"`
+	NextAttemptAt  sql.NullTime `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt    sql.NullTime `db:"delivered_at" json:"delivered_at"`
+	LastStatusCode int32        `db:"last_status_code" json:"last_status_code"`
+	LastError      string       `db:"last_error" json:"last_error"`
+	ID             uuid.UUID    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+func (q *sqlQuerier) UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDeliveryByID,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.DeliveredAt,
+		arg.LastStatusCode,
+		arg.LastError,
+		arg.ID,
+	)
 	return err
 }
 
-const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
-UPDATE
-	users
-SET
-	last_seen_at = $2,
-	updated_at = $3
+const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_shares
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
 `
 
-type UpdateUserLastSeenAtParams struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+type DeleteWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
 }
 
-func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-	)
-	return i, err
+func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	return err
 }
 
-const updateUserProfile = `-- name: UpdateUserProfile :one
-UPDATE
-	users
-SET
-	email = $2,
-	username = $3,
-	avatar_url = $4,
-	updated_at = $5
+const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level, protocol
+FROM
+	workspace_agent_port_shares
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
 `
 
-type UpdateUserProfileParams struct {
-	ID        uuid.UUID      `db:"id" json:"id"`
-	Email     string         `db:"email" json:"email"`
-	Username  string         `db:"username" json:"username"`
-	AvatarURL sql.NullString `db:"avatar_url" json:"avatar_url"`
-	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+type GetWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
 }
 
-func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile,
-		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.AvatarURL,
-		arg.UpdatedAt,
-	)
-	var i User
+func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	var i WorkspaceAgentPortShare
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+		&i.Protocol,
 	)
 	return i, err
 }
 
-const updateUserRoles = `-- name: UpdateUserRoles :one
-UPDATE
-	users
-SET
-	-- Remove all duplicates from the roles.
-	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+const listWorkspaceAgentPortShares = `-- name: ListWorkspaceAgentPortShares :many
+SELECT
+	workspace_id, agent_name, port, share_level, protocol
+FROM
+	workspace_agent_port_shares
 WHERE
-	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+	workspace_id = $1
+ORDER BY
+	agent_name ASC,
+	port ASC
 `
 
-type UpdateUserRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	ID           uuid.UUID `db:"id" json:"id"`
+func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkspaceAgentPortShares, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPortShare
+	for rows.Next() {
+		var i WorkspaceAgentPortShare
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.ShareLevel,
+			&i.Protocol,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+const upsertWorkspaceAgentPortShare = `-- name: UpsertWorkspaceAgentPortShare :one
+INSERT INTO
+	workspace_agent_port_shares (
+		workspace_id,
+		agent_name,
+		port,
+		share_level,
+		protocol
 	)
-	return i, err
-}
-
-const updateUserStatus = `-- name: UpdateUserStatus :one
-UPDATE
-	users
-SET
-	status = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (
+	workspace_id,
+	agent_name,
+	port
+)
+DO UPDATE SET
+	share_level = $4,
+	protocol = $5
+RETURNING workspace_id, agent_name, port, share_level, protocol
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpsertWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
+	AgentName   string            `db:"agent_name" json:"agent_name"`
+	Port        int32             `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
+	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentPortShare,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.ShareLevel,
+		arg.Protocol,
+	)
+	var i WorkspaceAgentPortShare
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+		&i.Protocol,
 	)
 	return i, err
 }
 
+const deleteOldWorkspaceAgentDebugLogs = `-- name: DeleteOldWorkspaceAgentDebugLogs :exec
+DELETE FROM workspace_agent_debug_logs WHERE agent_id IN
+	(SELECT id FROM workspace_agents WHERE last_connected_at IS NOT NULL
+		AND last_connected_at < NOW() - INTERVAL '7 day')
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentDebugLogs(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentDebugLogs)
+	return err
+}
+
 const deleteOldWorkspaceAgentStartupLogs = `-- name: DeleteOldWorkspaceAgentStartupLogs :exec
 DELETE FROM workspace_agent_startup_logs WHERE agent_id IN
 	(SELECT id FROM workspace_agents WHERE last_connected_at IS NOT NULL
@@ -5444,7 +7195,7 @@ func (q *sqlQuerier) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) err
 
 const getWorkspaceAgentByAuthToken = `-- name: GetWorkspaceAgentByAuthToken :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, started_at, ready_at, parent_id
 FROM
 	workspace_agents
 WHERE
@@ -5487,13 +7238,16 @@ func (q *sqlQuerier) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken
 		&i.ShutdownScriptTimeoutSeconds,
 		&i.StartupLogsLength,
 		&i.StartupLogsOverflowed,
+		&i.StartedAt,
+		&i.ReadyAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, started_at, ready_at, parent_id
 FROM
 	workspace_agents
 WHERE
@@ -5534,13 +7288,16 @@ func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (W
 		&i.ShutdownScriptTimeoutSeconds,
 		&i.StartupLogsLength,
 		&i.StartupLogsOverflowed,
+		&i.StartedAt,
+		&i.ReadyAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByInstanceID = `-- name: GetWorkspaceAgentByInstanceID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, started_at, ready_at, parent_id
 FROM
 	workspace_agents
 WHERE
@@ -5583,10 +7340,58 @@ func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInst
 		&i.ShutdownScriptTimeoutSeconds,
 		&i.StartupLogsLength,
 		&i.StartupLogsOverflowed,
+		&i.StartedAt,
+		&i.ReadyAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
+const getWorkspaceAgentDebugLogsAfter = `-- name: GetWorkspaceAgentDebugLogsAfter :many
+SELECT
+	agent_id, created_at, level, output, id
+FROM
+	workspace_agent_debug_logs
+WHERE
+	agent_id = $1
+	AND id > $2
+ORDER BY id ASC
+`
+
+type GetWorkspaceAgentDebugLogsAfterParams struct {
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAfter int64     `db:"created_after" json:"created_after"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentDebugLogsAfter(ctx context.Context, arg GetWorkspaceAgentDebugLogsAfterParams) ([]WorkspaceAgentDebugLog, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentDebugLogsAfter, arg.AgentID, arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentDebugLog
+	for rows.Next() {
+		var i WorkspaceAgentDebugLog
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.CreatedAt,
+			&i.Level,
+			&i.Output,
+			&i.ID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentMetadata = `-- name: GetWorkspaceAgentMetadata :many
 SELECT
 	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at
@@ -5631,7 +7436,7 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAge
 
 const getWorkspaceAgentStartupLogsAfter = `-- name: GetWorkspaceAgentStartupLogsAfter :many
 SELECT
-	agent_id, created_at, output, id, level
+	agent_id, created_at, output, id, level, script_id
 FROM
 	workspace_agent_startup_logs
 WHERE
@@ -5661,6 +7466,7 @@ func (q *sqlQuerier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg
 			&i.Output,
 			&i.ID,
 			&i.Level,
+			&i.ScriptID,
 		); err != nil {
 			return nil, err
 		}
@@ -5677,7 +7483,7 @@ func (q *sqlQuerier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg
 
 const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, started_at, ready_at, parent_id
 FROM
 	workspace_agents
 WHERE
@@ -5724,6 +7530,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 			&i.ShutdownScriptTimeoutSeconds,
 			&i.StartupLogsLength,
 			&i.StartupLogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -5739,7 +7548,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 }
 
 const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
-SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed FROM workspace_agents WHERE created_at > $1
+SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, started_at, ready_at, parent_id FROM workspace_agents WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error) {
@@ -5782,6 +7591,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 			&i.ShutdownScriptTimeoutSeconds,
 			&i.StartupLogsLength,
 			&i.StartupLogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -5798,7 +7610,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 
 const getWorkspaceAgentsInLatestBuildByWorkspaceID = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.login_before_ready, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.startup_logs_length, workspace_agents.startup_logs_overflowed
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.login_before_ready, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.startup_logs_length, workspace_agents.startup_logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.parent_id
 FROM
 	workspace_agents
 JOIN
@@ -5857,6 +7669,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Co
 			&i.ShutdownScriptTimeoutSeconds,
 			&i.StartupLogsLength,
 			&i.StartupLogsOverflowed,
+			&i.StartedAt,
+			&i.ReadyAt,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -5894,10 +7709,11 @@ INSERT INTO
 		login_before_ready,
 		startup_script_timeout_seconds,
 		shutdown_script,
-		shutdown_script_timeout_seconds
+		shutdown_script_timeout_seconds,
+		parent_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, login_before_ready, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, startup_logs_length, startup_logs_overflowed, started_at, ready_at, parent_id
 `
 
 type InsertWorkspaceAgentParams struct {
@@ -5922,6 +7738,7 @@ type InsertWorkspaceAgentParams struct {
 	StartupScriptTimeoutSeconds  int32                 `db:"startup_script_timeout_seconds" json:"startup_script_timeout_seconds"`
 	ShutdownScript               sql.NullString        `db:"shutdown_script" json:"shutdown_script"`
 	ShutdownScriptTimeoutSeconds int32                 `db:"shutdown_script_timeout_seconds" json:"shutdown_script_timeout_seconds"`
+	ParentID                     uuid.NullUUID         `db:"parent_id" json:"parent_id"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error) {
@@ -5947,6 +7764,7 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		arg.StartupScriptTimeoutSeconds,
 		arg.ShutdownScript,
 		arg.ShutdownScriptTimeoutSeconds,
+		arg.ParentID,
 	)
 	var i WorkspaceAgent
 	err := row.Scan(
@@ -5980,10 +7798,73 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		&i.ShutdownScriptTimeoutSeconds,
 		&i.StartupLogsLength,
 		&i.StartupLogsOverflowed,
+		&i.StartedAt,
+		&i.ReadyAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
+const insertWorkspaceAgentDebugLogs = `-- name: InsertWorkspaceAgentDebugLogs :many
+WITH trimmed AS (
+	DELETE FROM workspace_agent_debug_logs WHERE agent_id = $1 :: uuid AND id <= (
+		SELECT id FROM workspace_agent_debug_logs WHERE agent_id = $1 :: uuid
+		ORDER BY id DESC OFFSET GREATEST(10000 - cardinality($3 :: VARCHAR(4096) [ ]), 0) LIMIT 1
+	)
+)
+INSERT INTO
+		workspace_agent_debug_logs (agent_id, created_at, output, level)
+	SELECT
+		$1 :: uuid AS agent_id,
+		unnest($2 :: timestamptz [ ]) AS created_at,
+		unnest($3 :: VARCHAR(4096) [ ]) AS output,
+		unnest($4 :: log_level [ ]) AS level
+	RETURNING workspace_agent_debug_logs.agent_id, workspace_agent_debug_logs.created_at, workspace_agent_debug_logs.level, workspace_agent_debug_logs.output, workspace_agent_debug_logs.id
+`
+
+type InsertWorkspaceAgentDebugLogsParams struct {
+	AgentID   uuid.UUID   `db:"agent_id" json:"agent_id"`
+	CreatedAt []time.Time `db:"created_at" json:"created_at"`
+	Output    []string    `db:"output" json:"output"`
+	Level     []LogLevel  `db:"level" json:"level"`
+}
+
+// Only the most recent 10,000 debug logs of an agent are kept, older logs are
+// deleted as new logs are inserted.
+func (q *sqlQuerier) InsertWorkspaceAgentDebugLogs(ctx context.Context, arg InsertWorkspaceAgentDebugLogsParams) ([]WorkspaceAgentDebugLog, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentDebugLogs,
+		arg.AgentID,
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.Output),
+		pq.Array(arg.Level),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentDebugLog
+	for rows.Next() {
+		var i WorkspaceAgentDebugLog
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.CreatedAt,
+			&i.Level,
+			&i.Output,
+			&i.ID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertWorkspaceAgentMetadata = `-- name: InsertWorkspaceAgentMetadata :exec
 INSERT INTO
 	workspace_agent_metadata (
@@ -6022,20 +7903,22 @@ func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg Inser
 const insertWorkspaceAgentStartupLogs = `-- name: InsertWorkspaceAgentStartupLogs :many
 WITH new_length AS (
 	UPDATE workspace_agents SET
-	startup_logs_length = startup_logs_length + $5 WHERE workspace_agents.id = $1
+	startup_logs_length = startup_logs_length + $6 WHERE workspace_agents.id = $1
 )
 INSERT INTO
-		workspace_agent_startup_logs (agent_id, created_at, output, level)
+		workspace_agent_startup_logs (agent_id, script_id, created_at, output, level)
 	SELECT
 		$1 :: uuid AS agent_id,
-		unnest($2 :: timestamptz [ ]) AS created_at,
-		unnest($3 :: VARCHAR(1024) [ ]) AS output,
-		unnest($4 :: log_level [ ]) AS level
-	RETURNING workspace_agent_startup_logs.agent_id, workspace_agent_startup_logs.created_at, workspace_agent_startup_logs.output, workspace_agent_startup_logs.id, workspace_agent_startup_logs.level
+		$2 :: uuid AS script_id,
+		unnest($3 :: timestamptz [ ]) AS created_at,
+		unnest($4 :: VARCHAR(1024) [ ]) AS output,
+		unnest($5 :: log_level [ ]) AS level
+	RETURNING workspace_agent_startup_logs.agent_id, workspace_agent_startup_logs.created_at, workspace_agent_startup_logs.output, workspace_agent_startup_logs.id, workspace_agent_startup_logs.level, workspace_agent_startup_logs.script_id
 `
 
 type InsertWorkspaceAgentStartupLogsParams struct {
 	AgentID      uuid.UUID   `db:"agent_id" json:"agent_id"`
+	ScriptID     uuid.UUID   `db:"script_id" json:"script_id"`
 	CreatedAt    []time.Time `db:"created_at" json:"created_at"`
 	Output       []string    `db:"output" json:"output"`
 	Level        []LogLevel  `db:"level" json:"level"`
@@ -6045,6 +7928,7 @@ type InsertWorkspaceAgentStartupLogsParams struct {
 func (q *sqlQuerier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg InsertWorkspaceAgentStartupLogsParams) ([]WorkspaceAgentStartupLog, error) {
 	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentStartupLogs,
 		arg.AgentID,
+		arg.ScriptID,
 		pq.Array(arg.CreatedAt),
 		pq.Array(arg.Output),
 		pq.Array(arg.Level),
@@ -6063,6 +7947,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg In
 			&i.Output,
 			&i.ID,
 			&i.Level,
+			&i.ScriptID,
 		); err != nil {
 			return nil, err
 		}
@@ -6115,7 +8000,9 @@ const updateWorkspaceAgentLifecycleStateByID = `-- name: UpdateWorkspaceAgentLif
 UPDATE
 	workspace_agents
 SET
-	lifecycle_state = $2
+	lifecycle_state = $2,
+	started_at = $3,
+	ready_at = $4
 WHERE
 	id = $1
 `
@@ -6123,10 +8010,17 @@ WHERE
 type UpdateWorkspaceAgentLifecycleStateByIDParams struct {
 	ID             uuid.UUID                    `db:"id" json:"id"`
 	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+	StartedAt      sql.NullTime                 `db:"started_at" json:"started_at"`
+	ReadyAt        sql.NullTime                 `db:"ready_at" json:"ready_at"`
 }
 
 func (q *sqlQuerier) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLifecycleStateByID, arg.ID, arg.LifecycleState)
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentLifecycleStateByID,
+		arg.ID,
+		arg.LifecycleState,
+		arg.StartedAt,
+		arg.ReadyAt,
+	)
 	return err
 }
 
@@ -6201,6 +8095,164 @@ func (q *sqlQuerier) UpdateWorkspaceAgentStartupLogOverflowByID(ctx context.Cont
 	return err
 }
 
+const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
+SELECT
+	id, workspace_agent_id, created_at, display_name, script, cron, run_order, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, log_path, status, exit_code, started_at, completed_at
+FROM
+	workspace_agent_scripts
+WHERE
+	workspace_agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	run_order ASC,
+	display_name ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentScript
+	for rows.Next() {
+		var i WorkspaceAgentScript
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.DisplayName,
+			&i.Script,
+			&i.Cron,
+			&i.RunOrder,
+			&i.StartBlocksLogin,
+			&i.RunOnStart,
+			&i.RunOnStop,
+			&i.TimeoutSeconds,
+			&i.LogPath,
+			&i.Status,
+			&i.ExitCode,
+			&i.StartedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentScript = `-- name: InsertWorkspaceAgentScript :one
+INSERT INTO
+	workspace_agent_scripts (
+		id,
+		workspace_agent_id,
+		created_at,
+		display_name,
+		script,
+		cron,
+		run_order,
+		start_blocks_login,
+		run_on_start,
+		run_on_stop,
+		timeout_seconds,
+		log_path
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, workspace_agent_id, created_at, display_name, script, cron, run_order, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, log_path, status, exit_code, started_at, completed_at
+`
+
+type InsertWorkspaceAgentScriptParams struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Script           string    `db:"script" json:"script"`
+	Cron             string    `db:"cron" json:"cron"`
+	RunOrder         int32     `db:"run_order" json:"run_order"`
+	StartBlocksLogin bool      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       bool      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        bool      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	LogPath          string    `db:"log_path" json:"log_path"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentScript(ctx context.Context, arg InsertWorkspaceAgentScriptParams) (WorkspaceAgentScript, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentScript,
+		arg.ID,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		arg.DisplayName,
+		arg.Script,
+		arg.Cron,
+		arg.RunOrder,
+		arg.StartBlocksLogin,
+		arg.RunOnStart,
+		arg.RunOnStop,
+		arg.TimeoutSeconds,
+		arg.LogPath,
+	)
+	var i WorkspaceAgentScript
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceAgentID,
+		&i.CreatedAt,
+		&i.DisplayName,
+		&i.Script,
+		&i.Cron,
+		&i.RunOrder,
+		&i.StartBlocksLogin,
+		&i.RunOnStart,
+		&i.RunOnStop,
+		&i.TimeoutSeconds,
+		&i.LogPath,
+		&i.Status,
+		&i.ExitCode,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceAgentScriptStatus = `-- name: UpdateWorkspaceAgentScriptStatus :exec
+UPDATE
+	workspace_agent_scripts
+SET
+	status = $3,
+	exit_code = $4,
+	started_at = $5,
+	completed_at = $6
+WHERE
+	id = $1
+	AND workspace_agent_id = $2
+`
+
+type UpdateWorkspaceAgentScriptStatusParams struct {
+	ID               uuid.UUID                  `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID                  `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Status           WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	ExitCode         int32                      `db:"exit_code" json:"exit_code"`
+	StartedAt        sql.NullTime               `db:"started_at" json:"started_at"`
+	CompletedAt      sql.NullTime               `db:"completed_at" json:"completed_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentScriptStatus(ctx context.Context, arg UpdateWorkspaceAgentScriptStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentScriptStatus,
+		arg.ID,
+		arg.WorkspaceAgentID,
+		arg.Status,
+		arg.ExitCode,
+		arg.StartedAt,
+		arg.CompletedAt,
+	)
+	return err
+}
+
 const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
 DELETE FROM workspace_agent_stats WHERE created_at < NOW() - INTERVAL '30 days'
 `
@@ -6269,7 +8321,7 @@ WITH agent_stats AS (
 		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
 		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
 	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes, disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
 		FROM workspace_agent_stats WHERE created_at > $1
 	) AS a WHERE a.rn = 1
 )
@@ -6346,6 +8398,92 @@ func (q *sqlQuerier) GetTemplateDAUs(ctx context.Context, templateID uuid.UUID)
 	return items, nil
 }
 
+const getWorkspaceAgentResourceStatsAndLabels = `-- name: GetWorkspaceAgentResourceStatsAndLabels :many
+WITH latest_resource_stats AS (
+	SELECT
+		DISTINCT ON (agent_id)
+		agent_id, user_id, workspace_id,
+		cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes,
+		disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes
+	FROM
+		workspace_agent_stats
+	WHERE
+		workspace_agent_stats.created_at > $1
+		-- The limits are zero for agents that don't report resource usage.
+		AND memory_limit_bytes > 0
+	ORDER BY
+		agent_id, workspace_agent_stats.created_at DESC
+)
+SELECT
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name,
+	cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes,
+	disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes
+FROM
+	latest_resource_stats
+JOIN
+	users
+ON
+	users.id = latest_resource_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = latest_resource_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = latest_resource_stats.workspace_id
+`
+
+type GetWorkspaceAgentResourceStatsAndLabelsRow struct {
+	Username         string  `db:"username" json:"username"`
+	AgentName        string  `db:"agent_name" json:"agent_name"`
+	WorkspaceName    string  `db:"workspace_name" json:"workspace_name"`
+	CPUUsedCores     float64 `db:"cpu_used_cores" json:"cpu_used_cores"`
+	CPULimitCores    float64 `db:"cpu_limit_cores" json:"cpu_limit_cores"`
+	MemoryUsedBytes  int64   `db:"memory_used_bytes" json:"memory_used_bytes"`
+	MemoryLimitBytes int64   `db:"memory_limit_bytes" json:"memory_limit_bytes"`
+	DiskUsedBytes    int64   `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes   int64   `db:"disk_total_bytes" json:"disk_total_bytes"`
+	NetworkRxBytes   int64   `db:"network_rx_bytes" json:"network_rx_bytes"`
+	NetworkTxBytes   int64   `db:"network_tx_bytes" json:"network_tx_bytes"`
+}
+
+// Returns the latest resource usage reported by each agent.
+func (q *sqlQuerier) GetWorkspaceAgentResourceStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentResourceStatsAndLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentResourceStatsAndLabels, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentResourceStatsAndLabelsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentResourceStatsAndLabelsRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.AgentName,
+			&i.WorkspaceName,
+			&i.CPUUsedCores,
+			&i.CPULimitCores,
+			&i.MemoryUsedBytes,
+			&i.MemoryLimitBytes,
+			&i.DiskUsedBytes,
+			&i.DiskTotalBytes,
+			&i.NetworkRxBytes,
+			&i.NetworkTxBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
 WITH agent_stats AS (
 	SELECT
@@ -6369,7 +8507,7 @@ WITH agent_stats AS (
 		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
 		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
 	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes, disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
 		FROM workspace_agent_stats WHERE created_at > $1
 	) AS a WHERE a.rn = 1 GROUP BY a.user_id, a.agent_id, a.workspace_id, a.template_id
 )
@@ -6452,7 +8590,7 @@ WITH agent_stats AS (
 		coalesce(SUM(connection_count), 0)::bigint AS connection_count,
 		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
 	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes, disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
 		FROM workspace_agent_stats
 		-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
 		WHERE created_at > $1 AND connection_median_latency_ms > 0
@@ -6552,10 +8690,18 @@ INSERT INTO
 		session_count_jetbrains,
 		session_count_reconnecting_pty,
 		session_count_ssh,
-		connection_median_latency_ms
+		connection_median_latency_ms,
+		cpu_used_cores,
+		cpu_limit_cores,
+		memory_used_bytes,
+		memory_limit_bytes,
+		disk_used_bytes,
+		disk_total_bytes,
+		network_rx_bytes,
+		network_tx_bytes
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25) RETURNING id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes, disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes
 `
 
 type InsertWorkspaceAgentStatParams struct {
@@ -6576,6 +8722,14 @@ type InsertWorkspaceAgentStatParams struct {
 	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
 	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
 	ConnectionMedianLatencyMS   float64         `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+	CPUUsedCores                float64         `db:"cpu_used_cores" json:"cpu_used_cores"`
+	CPULimitCores               float64         `db:"cpu_limit_cores" json:"cpu_limit_cores"`
+	MemoryUsedBytes             int64           `db:"memory_used_bytes" json:"memory_used_bytes"`
+	MemoryLimitBytes            int64           `db:"memory_limit_bytes" json:"memory_limit_bytes"`
+	DiskUsedBytes               int64           `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes              int64           `db:"disk_total_bytes" json:"disk_total_bytes"`
+	NetworkRxBytes              int64           `db:"network_rx_bytes" json:"network_rx_bytes"`
+	NetworkTxBytes              int64           `db:"network_tx_bytes" json:"network_tx_bytes"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgentStat(ctx context.Context, arg InsertWorkspaceAgentStatParams) (WorkspaceAgentStat, error) {
@@ -6597,6 +8751,14 @@ func (q *sqlQuerier) InsertWorkspaceAgentStat(ctx context.Context, arg InsertWor
 		arg.SessionCountReconnectingPTY,
 		arg.SessionCountSSH,
 		arg.ConnectionMedianLatencyMS,
+		arg.CPUUsedCores,
+		arg.CPULimitCores,
+		arg.MemoryUsedBytes,
+		arg.MemoryLimitBytes,
+		arg.DiskUsedBytes,
+		arg.DiskTotalBytes,
+		arg.NetworkRxBytes,
+		arg.NetworkTxBytes,
 	)
 	var i WorkspaceAgentStat
 	err := row.Scan(
@@ -6617,12 +8779,20 @@ func (q *sqlQuerier) InsertWorkspaceAgentStat(ctx context.Context, arg InsertWor
 		&i.SessionCountJetBrains,
 		&i.SessionCountReconnectingPTY,
 		&i.SessionCountSSH,
+		&i.CPUUsedCores,
+		&i.CPULimitCores,
+		&i.MemoryUsedBytes,
+		&i.MemoryLimitBytes,
+		&i.DiskUsedBytes,
+		&i.DiskTotalBytes,
+		&i.NetworkRxBytes,
+		&i.NetworkTxBytes,
 	)
 	return i, err
 }
 
 const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 AND slug = $2
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_type, healthcheck_address, healthcheck_command, healthcheck_expected_exit_codes, healthcheck_output_regex FROM workspace_apps WHERE agent_id = $1 AND slug = $2
 `
 
 type GetWorkspaceAppByAgentIDAndSlugParams struct {
@@ -6649,12 +8819,17 @@ func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg Ge
 		&i.SharingLevel,
 		&i.Slug,
 		&i.External,
+		&i.HealthcheckType,
+		&i.HealthcheckAddress,
+		&i.HealthcheckCommand,
+		pq.Array(&i.HealthcheckExpectedExitCodes),
+		&i.HealthcheckOutputRegex,
 	)
 	return i, err
 }
 
 const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_type, healthcheck_address, healthcheck_command, healthcheck_expected_exit_codes, healthcheck_output_regex FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
@@ -6682,6 +8857,11 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.HealthcheckType,
+			&i.HealthcheckAddress,
+			&i.HealthcheckCommand,
+			pq.Array(&i.HealthcheckExpectedExitCodes),
+			&i.HealthcheckOutputRegex,
 		); err != nil {
 			return nil, err
 		}
@@ -6697,7 +8877,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 }
 
 const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_type, healthcheck_address, healthcheck_command, healthcheck_expected_exit_codes, healthcheck_output_regex FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
@@ -6725,6 +8905,11 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.HealthcheckType,
+			&i.HealthcheckAddress,
+			&i.HealthcheckCommand,
+			pq.Array(&i.HealthcheckExpectedExitCodes),
+			&i.HealthcheckOutputRegex,
 		); err != nil {
 			return nil, err
 		}
@@ -6740,7 +8925,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 }
 
 const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_type, healthcheck_address, healthcheck_command, healthcheck_expected_exit_codes, healthcheck_output_regex FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
@@ -6768,6 +8953,11 @@ func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.HealthcheckType,
+			&i.HealthcheckAddress,
+			&i.HealthcheckCommand,
+			pq.Array(&i.HealthcheckExpectedExitCodes),
+			&i.HealthcheckOutputRegex,
 		); err != nil {
 			return nil, err
 		}
@@ -6799,28 +8989,38 @@ INSERT INTO
         healthcheck_url,
         healthcheck_interval,
         healthcheck_threshold,
+        healthcheck_type,
+        healthcheck_address,
+        healthcheck_command,
+        healthcheck_expected_exit_codes,
+        healthcheck_output_regex,
         health
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_type, healthcheck_address, healthcheck_command, healthcheck_expected_exit_codes, healthcheck_output_regex
 `
 
 type InsertWorkspaceAppParams struct {
-	ID                   uuid.UUID          `db:"id" json:"id"`
-	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
-	AgentID              uuid.UUID          `db:"agent_id" json:"agent_id"`
-	Slug                 string             `db:"slug" json:"slug"`
-	DisplayName          string             `db:"display_name" json:"display_name"`
-	Icon                 string             `db:"icon" json:"icon"`
-	Command              sql.NullString     `db:"command" json:"command"`
-	Url                  sql.NullString     `db:"url" json:"url"`
-	External             bool               `db:"external" json:"external"`
-	Subdomain            bool               `db:"subdomain" json:"subdomain"`
-	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
-	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
-	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
-	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
-	Health               WorkspaceAppHealth `db:"health" json:"health"`
+	ID                           uuid.UUID                   `db:"id" json:"id"`
+	CreatedAt                    time.Time                   `db:"created_at" json:"created_at"`
+	AgentID                      uuid.UUID                   `db:"agent_id" json:"agent_id"`
+	Slug                         string                      `db:"slug" json:"slug"`
+	DisplayName                  string                      `db:"display_name" json:"display_name"`
+	Icon                         string                      `db:"icon" json:"icon"`
+	Command                      sql.NullString              `db:"command" json:"command"`
+	Url                          sql.NullString              `db:"url" json:"url"`
+	External                     bool                        `db:"external" json:"external"`
+	Subdomain                    bool                        `db:"subdomain" json:"subdomain"`
+	SharingLevel                 AppSharingLevel             `db:"sharing_level" json:"sharing_level"`
+	HealthcheckUrl               string                      `db:"healthcheck_url" json:"healthcheck_url"`
+	HealthcheckInterval          int32                       `db:"healthcheck_interval" json:"healthcheck_interval"`
+	HealthcheckThreshold         int32                       `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	HealthcheckType              WorkspaceAppHealthcheckType `db:"healthcheck_type" json:"healthcheck_type"`
+	HealthcheckAddress           string                      `db:"healthcheck_address" json:"healthcheck_address"`
+	HealthcheckCommand           string                      `db:"healthcheck_command" json:"healthcheck_command"`
+	HealthcheckExpectedExitCodes []int32                     `db:"healthcheck_expected_exit_codes" json:"healthcheck_expected_exit_codes"`
+	HealthcheckOutputRegex       string                      `db:"healthcheck_output_regex" json:"healthcheck_output_regex"`
+	Health                       WorkspaceAppHealth          `db:"health" json:"health"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error) {
@@ -6839,6 +9039,11 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		arg.HealthcheckUrl,
 		arg.HealthcheckInterval,
 		arg.HealthcheckThreshold,
+		arg.HealthcheckType,
+		arg.HealthcheckAddress,
+		arg.HealthcheckCommand,
+		pq.Array(arg.HealthcheckExpectedExitCodes),
+		arg.HealthcheckOutputRegex,
 		arg.Health,
 	)
 	var i WorkspaceApp
@@ -6858,6 +9063,11 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		&i.SharingLevel,
 		&i.Slug,
 		&i.External,
+		&i.HealthcheckType,
+		&i.HealthcheckAddress,
+		&i.HealthcheckCommand,
+		pq.Array(&i.HealthcheckExpectedExitCodes),
+		&i.HealthcheckOutputRegex,
 	)
 	return i, err
 }
@@ -7875,7 +10085,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -7918,13 +10128,15 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -7948,13 +10160,15 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -7985,13 +10199,15 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -8041,13 +10257,15 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, COUNT(*) OVER () as count
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.automatic_updates, COUNT(*) OVER () as count
 FROM
 	workspaces
 LEFT JOIN LATERAL (
@@ -8170,10 +10388,16 @@ WHERE
 			name ILIKE '%' || $7 || '%'
 		ELSE true
 	END
+	-- Filter by dormancy
+	AND CASE
+		WHEN $8 :: boolean THEN
+			dormant_at IS NOT NULL
+		ELSE true
+	END
 	-- Filter by agent status
 	-- has-agent: is only applicable for workspaces in "start" transition. Stopped and deleted workspaces don't have agents.
 	AND CASE
-		WHEN $8 :: text != '' THEN
+		WHEN $9 :: text != '' THEN
 			(
 				SELECT COUNT(*)
 				FROM
@@ -8185,7 +10409,7 @@ WHERE
 				WHERE
 					workspace_resources.job_id = latest_build.provisioner_job_id AND
 					latest_build.transition = 'start'::workspace_transition AND
-					$8 = (
+					$9 = (
 						CASE
 							WHEN workspace_agents.first_connected_at IS NULL THEN
 								CASE
@@ -8196,7 +10420,7 @@ WHERE
 								END
 							WHEN workspace_agents.disconnected_at > workspace_agents.last_connected_at THEN
 								'disconnected'
-							WHEN NOW() - workspace_agents.last_connected_at > INTERVAL '1 second' * $9 :: bigint THEN
+							WHEN NOW() - workspace_agents.last_connected_at > INTERVAL '1 second' * $10 :: bigint THEN
 								'disconnected'
 							WHEN workspace_agents.last_connected_at IS NOT NULL THEN
 								'connected'
@@ -8213,11 +10437,11 @@ ORDER BY
 	last_used_at DESC
 LIMIT
 	CASE
-		WHEN $11 :: integer > 0 THEN
-			$11
+		WHEN $12 :: integer > 0 THEN
+			$12
 	END
 OFFSET
-	$10
+	$11
 `
 
 type GetWorkspacesParams struct {
@@ -8228,6 +10452,7 @@ type GetWorkspacesParams struct {
 	TemplateName                          string      `db:"template_name" json:"template_name"`
 	TemplateIds                           []uuid.UUID `db:"template_ids" json:"template_ids"`
 	Name                                  string      `db:"name" json:"name"`
+	Dormant                               bool        `db:"dormant" json:"dormant"`
 	HasAgent                              string      `db:"has_agent" json:"has_agent"`
 	AgentInactiveDisconnectTimeoutSeconds int64       `db:"agent_inactive_disconnect_timeout_seconds" json:"agent_inactive_disconnect_timeout_seconds"`
 	Offset                                int32       `db:"offset_" json:"offset_"`
@@ -8235,18 +10460,20 @@ type GetWorkspacesParams struct {
 }
 
 type GetWorkspacesRow struct {
-	ID                uuid.UUID      `db:"id" json:"id"`
-	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID           uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID    uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
-	Deleted           bool           `db:"deleted" json:"deleted"`
-	Name              string         `db:"name" json:"name"`
-	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
-	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
-	Count             int64          `db:"count" json:"count"`
+	ID                uuid.UUID        `db:"id" json:"id"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID           uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID        `db:"template_id" json:"template_id"`
+	Deleted           bool             `db:"deleted" json:"deleted"`
+	Name              string           `db:"name" json:"name"`
+	AutostartSchedule sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
+	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
+	DormantAt         sql.NullTime     `db:"dormant_at" json:"dormant_at"`
+	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	Count             int64            `db:"count" json:"count"`
 }
 
 func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]GetWorkspacesRow, error) {
@@ -8258,6 +10485,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.TemplateName,
 		pq.Array(arg.TemplateIds),
 		arg.Name,
+		arg.Dormant,
 		arg.HasAgent,
 		arg.AgentInactiveDisconnectTimeoutSeconds,
 		arg.Offset,
@@ -8282,6 +10510,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.AutostartSchedule,
 			&i.Ttl,
 			&i.LastUsedAt,
+			&i.DormantAt,
+			&i.AutomaticUpdates,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -8297,13 +10527,17 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 	return items, nil
 }
 
-const getWorkspacesEligibleForAutoStartStop = `-- name: GetWorkspacesEligibleForAutoStartStop :many
+const getWorkspacesEligibleForTransition = `-- name: GetWorkspacesEligibleForTransition :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.automatic_updates
 FROM
 	workspaces
 LEFT JOIN
 	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+INNER JOIN
+	templates ON templates.id = workspaces.template_id
 WHERE
 	workspace_builds.build_number = (
 		SELECT
@@ -8333,12 +10567,44 @@ WHERE
 		(
 			workspace_builds.transition = 'stop'::workspace_transition AND
 			workspaces.autostart_schedule IS NOT NULL
+		) OR
+
+		-- If the workspace has not been used for longer than the template's
+		-- inactivity TTL, it is eligible to be marked dormant. The caller must
+		-- check the TTL in a license-aware fashion as the value stored here is
+		-- only honored with advanced template scheduling.
+		(
+			workspaces.deleted = false AND
+			workspaces.dormant_at IS NULL AND
+			templates.inactivity_ttl > 0 AND
+			workspaces.last_used_at + INTERVAL '1 microsecond' * (templates.inactivity_ttl / 1000) < $1 :: timestamptz
+		) OR
+
+		-- If the workspace has been dormant for longer than the template's
+		-- dormant autodelete TTL, it is eligible for deletion.
+		(
+			workspaces.deleted = false AND
+			workspaces.dormant_at IS NOT NULL AND
+			templates.dormant_autodelete_ttl > 0 AND
+			workspace_builds.transition != 'delete'::workspace_transition AND
+			workspaces.dormant_at + INTERVAL '1 microsecond' * (templates.dormant_autodelete_ttl / 1000) < $1 :: timestamptz
+		) OR
+
+		-- If the start build failed longer than the template's failure TTL
+		-- ago, the workspace is eligible to be stopped.
+		(
+			templates.failure_ttl > 0 AND
+			workspace_builds.transition = 'start'::workspace_transition AND
+			provisioner_jobs.completed_at IS NOT NULL AND
+			provisioner_jobs.error IS NOT NULL AND
+			provisioner_jobs.error != '' AND
+			provisioner_jobs.completed_at + INTERVAL '1 microsecond' * (templates.failure_ttl / 1000) < $1 :: timestamptz
 		)
 	)
 `
 
-func (q *sqlQuerier) GetWorkspacesEligibleForAutoStartStop(ctx context.Context, now time.Time) ([]Workspace, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspacesEligibleForAutoStartStop, now)
+func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]Workspace, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesEligibleForTransition, now)
 	if err != nil {
 		return nil, err
 	}
@@ -8358,6 +10624,8 @@ func (q *sqlQuerier) GetWorkspacesEligibleForAutoStartStop(ctx context.Context,
 			&i.AutostartSchedule,
 			&i.Ttl,
 			&i.LastUsedAt,
+			&i.DormantAt,
+			&i.AutomaticUpdates,
 		); err != nil {
 			return nil, err
 		}
@@ -8384,23 +10652,25 @@ INSERT INTO
 		name,
 		autostart_schedule,
 		ttl,
-		last_used_at
+		last_used_at,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
 `
 
 type InsertWorkspaceParams struct {
-	ID                uuid.UUID      `db:"id" json:"id"`
-	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID           uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID    uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
-	Name              string         `db:"name" json:"name"`
-	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
-	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	ID                uuid.UUID        `db:"id" json:"id"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID           uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID        `db:"template_id" json:"template_id"`
+	Name              string           `db:"name" json:"name"`
+	AutostartSchedule sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
+	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
+	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error) {
@@ -8415,6 +10685,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.AutostartSchedule,
 		arg.Ttl,
 		arg.LastUsedAt,
+		arg.AutomaticUpdates,
 	)
 	var i Workspace
 	err := row.Scan(
@@ -8429,6 +10700,8 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -8441,7 +10714,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
 `
 
 type UpdateWorkspaceParams struct {
@@ -8464,10 +10737,31 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
+const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAutomaticUpdatesParams struct {
+	ID               uuid.UUID        `db:"id" json:"id"`
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAutomaticUpdates, arg.ID, arg.AutomaticUpdates)
+	return err
+}
+
 const updateWorkspaceAutostart = `-- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -8506,6 +10800,43 @@ func (q *sqlQuerier) UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateW
 	return err
 }
 
+const updateWorkspaceDormantAt = `-- name: UpdateWorkspaceDormantAt :one
+UPDATE
+	workspaces
+SET
+	dormant_at = $2
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, automatic_updates
+`
+
+type UpdateWorkspaceDormantAtParams struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	DormantAt sql.NullTime `db:"dormant_at" json:"dormant_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceDormantAt(ctx context.Context, arg UpdateWorkspaceDormantAtParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceDormantAt, arg.ID, arg.DormantAt)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.AutomaticUpdates,
+	)
+	return i, err
+}
+
 const updateWorkspaceLastUsedAt = `-- name: UpdateWorkspaceLastUsedAt :exec
 UPDATE
 	workspaces
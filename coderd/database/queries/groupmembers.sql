@@ -14,6 +14,30 @@ AND
 AND
 	users.deleted = 'false';
 
+-- GetGroupMembershipsByUserIDs returns the groups of the active users in
+-- user_ids, with the names of the groups.
+-- name: GetGroupMembershipsByUserIDs :many
+SELECT
+	group_members.user_id,
+	groups.id AS group_id,
+	groups.name AS group_name
+FROM
+	group_members
+JOIN
+	groups
+ON
+	groups.id = group_members.group_id
+JOIN
+	users
+ON
+	users.id = group_members.user_id
+WHERE
+	group_members.user_id = ANY(@user_ids :: uuid [ ])
+AND
+	users.status = 'active'
+AND
+	users.deleted = 'false';
+
 -- InsertUserGroupsByName adds a user to all provided groups, if they exist.
 -- name: InsertUserGroupsByName :exec
 WITH groups AS (
@@ -42,3 +42,13 @@ INSERT INTO
 	organizations (id, "name", description, created_at, updated_at)
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateOrganizationQuotaBudget :one
+UPDATE
+	organizations
+SET
+	quota_budget = @quota_budget,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
@@ -11,7 +11,18 @@ INSERT INTO
 		created_at,
 		"name",
 		provisioners,
-		tags
+		tags,
+		last_seen_at,
+		version,
+		key_id
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE
+	provisioner_daemons
+SET
+	last_seen_at = @last_seen_at
+WHERE
+	id = @id;
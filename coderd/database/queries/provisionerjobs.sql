@@ -23,6 +23,12 @@ WHERE
 			AND nested.provisioner = ANY(@types :: provisioner_type [ ])
 			-- Ensure the caller satisfies all job tags.
 			AND nested.tags <@ @tags :: jsonb
+			-- Daemons of provisioner keys only serve the organization of the key.
+			AND CASE
+				WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+					nested.organization_id = @organization_id
+				ELSE true
+			END
 		ORDER BY
 			nested.created_at
 		FOR UPDATE
@@ -47,6 +53,16 @@ FROM
 WHERE
 	id = ANY(@ids :: uuid [ ]);
 
+-- name: GetRunningProvisionerJobsByWorkerIDs :many
+SELECT
+	*
+FROM
+	provisioner_jobs
+WHERE
+	worker_id = ANY(@worker_ids :: uuid [ ])
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL;
+
 -- name: GetProvisionerJobsCreatedAfter :many
 SELECT * FROM provisioner_jobs WHERE created_at > $1;
 
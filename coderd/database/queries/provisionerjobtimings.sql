@@ -0,0 +1,21 @@
+-- name: InsertProvisionerJobTimings :many
+INSERT INTO
+	provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
+SELECT
+	@job_id :: uuid AS job_id,
+	unnest(@started_at :: timestamptz [ ]) AS started_at,
+	unnest(@ended_at :: timestamptz [ ]) AS ended_at,
+	unnest(@stage :: provisioner_job_timing_stage [ ]) AS stage,
+	unnest(@source :: text [ ]) AS source,
+	unnest(@action :: text [ ]) AS action,
+	unnest(@resource :: text [ ]) AS resource RETURNING *;
+
+-- name: GetProvisionerJobTimingsByJobID :many
+SELECT
+	*
+FROM
+	provisioner_job_timings
+WHERE
+	job_id = @job_id
+ORDER BY
+	started_at ASC;
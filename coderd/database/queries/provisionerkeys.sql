@@ -0,0 +1,45 @@
+-- name: InsertProvisionerKey :one
+INSERT INTO
+	provisioner_keys (
+		id,
+		created_at,
+		organization_id,
+		"name",
+		hashed_secret,
+		tags
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetProvisionerKeyByID :one
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	id = $1;
+
+-- name: GetProvisionerKeyByName :one
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	organization_id = @organization_id
+	AND lower("name") = lower(@name);
+
+-- name: GetProvisionerKeysByOrganizationID :many
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+ORDER BY
+	lower("name") ASC;
+
+-- name: DeleteProvisionerKey :exec
+DELETE FROM
+	provisioner_keys
+WHERE
+	id = $1;
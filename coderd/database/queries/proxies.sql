@@ -24,11 +24,36 @@ SET
 	url = @url,
 	wildcard_hostname = @wildcard_hostname,
 	icon = @icon,
+	-- Only update the token if a new one is provided.
+	-- So this is an optional field.
+	token_hashed_secret = CASE
+		WHEN length(@token_hashed_secret :: bytea) > 0 THEN @token_hashed_secret :: bytea
+		ELSE workspace_proxies.token_hashed_secret
+	END,
 	updated_at = Now()
 WHERE
 	id = @id
 RETURNING *;
 
+-- name: RegisterWorkspaceProxy :one
+UPDATE
+	workspace_proxies
+SET
+	version = @version,
+	last_heartbeat_at = Now()
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: UpdateWorkspaceProxyHealth :exec
+UPDATE
+	workspace_proxies
+SET
+	health_checked_at = @health_checked_at,
+	health_error = @health_error
+WHERE
+	id = @id;
+
 
 -- name: UpdateWorkspaceProxyDeleted :exec
 UPDATE
@@ -49,6 +74,17 @@ WHERE
 LIMIT
 	1;
 
+-- name: GetWorkspaceProxyByName :one
+SELECT
+	*
+FROM
+	workspace_proxies
+WHERE
+	name = $1
+	AND deleted = false
+LIMIT
+	1;
+
 -- Finds a workspace proxy that has an access URL or app hostname that matches
 -- the provided hostname. This is to check if a hostname matches any workspace
 -- proxy.
@@ -28,3 +28,60 @@ FROM
 JOIN latest_builds ON
 	latest_builds.workspace_id = workspaces.id
 WHERE NOT deleted AND workspaces.owner_id = $1;
+
+-- name: GetQuotaConsumedForOrganization :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.organization_id = $1;
+
+-- name: GetQuotaSpendSegments :many
+-- A workspace build consumes its daily cost from the time it was created until
+-- the next build of the workspace was created. Segments that are still in
+-- effect end at @end_time.
+WITH segments AS (
+SELECT
+	wb.workspace_id,
+	w.owner_id,
+	w.template_id,
+	wb.daily_cost,
+	wb.created_at AS started_at,
+	coalesce(
+		lead(wb.created_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.created_at),
+		@end_time::timestamptz
+	)::timestamptz AS ended_at
+FROM
+	workspace_builds wb
+JOIN workspaces w ON
+	w.id = wb.workspace_id
+)
+SELECT
+	workspace_id,
+	owner_id,
+	template_id,
+	daily_cost,
+	started_at,
+	ended_at
+FROM
+	segments
+WHERE
+	daily_cost > 0
+	AND started_at < @end_time::timestamptz
+	AND ended_at > @start_time::timestamptz
+ORDER BY
+	started_at ASC;
@@ -0,0 +1,58 @@
+-- name: CustomRoles :many
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	true
+	-- Site wide roles are looked up by their name, organization roles by
+	-- "<name>:<organization_id>", matching how they are stored on users and
+	-- organization members.
+	AND CASE WHEN array_length(@lookup_roles :: text[], 1) > 0 THEN
+		CASE WHEN organization_id IS NULL THEN
+			name ILIKE ANY(@lookup_roles :: text[])
+		ELSE
+			(name || ':' || organization_id :: text) ILIKE ANY(@lookup_roles :: text[])
+		END
+	ELSE true
+	END
+	AND CASE WHEN @exclude_org_roles :: boolean THEN
+		organization_id IS NULL
+	ELSE true
+	END
+	AND CASE WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+		organization_id = @organization_id
+	ELSE true
+	END
+ORDER BY
+	name ASC;
+
+-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
+SET
+	display_name = @display_name,
+	site_permissions = @site_permissions,
+	org_permissions = @org_permissions,
+	user_permissions = @user_permissions,
+	updated_at = @updated_at
+WHERE
+	lower(name) = lower(@name)
+	AND organization_id IS NOT DISTINCT FROM @organization_id
+RETURNING *;
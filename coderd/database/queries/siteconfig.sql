@@ -37,3 +37,10 @@ SELECT value FROM site_configs WHERE key = 'app_signing_key';
 -- name: UpsertAppSecurityKey :exec
 INSERT INTO site_configs (key, value) VALUES ('app_signing_key', $1)
 ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'app_signing_key';
+
+-- name: GetSSHCAKey :one
+SELECT value FROM site_configs WHERE key = 'ssh_ca_key';
+
+-- name: UpsertSSHCAKey :exec
+INSERT INTO site_configs (key, value) VALUES ('ssh_ca_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'ssh_ca_key';
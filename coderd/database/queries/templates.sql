@@ -34,6 +34,12 @@ WHERE
 			id = ANY(@ids)
 		ELSE true
 	END
+	-- Optionally exclude deprecated templates
+	AND CASE
+		WHEN @exclude_deprecated :: boolean THEN
+			deprecated = ''
+		ELSE true
+	END
   -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
   -- @authorize_filter
 ORDER BY (name, id) ASC
@@ -104,7 +110,12 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	deprecated = $8,
+	require_active_version = $9,
+	max_port_sharing_level = $10,
+	session_recording_enabled = $11,
+	quota_cost_multiplier = $12
 WHERE
 	id = $1
 RETURNING
@@ -118,7 +129,12 @@ SET
 	allow_user_autostart = $3,
 	allow_user_autostop = $4,
 	default_ttl = $5,
-	max_ttl = $6
+	max_ttl = $6,
+	inactivity_ttl = $7,
+	dormant_autodelete_ttl = $8,
+	failure_ttl = $9,
+	restart_requirement_days_of_week = $10,
+	restart_requirement_weeks = $11
 WHERE
 	id = $1
 RETURNING
@@ -0,0 +1,36 @@
+-- name: InsertTemplateVersionPromotion :one
+INSERT INTO
+	template_version_promotions (
+		id,
+		template_id,
+		template_version_id,
+		promoted_at,
+		promoted_by
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: GetTemplateVersionPromotionsByTemplateID :many
+-- Returns the promotions of the template that weren't rolled back, most
+-- recent first.
+SELECT
+	*
+FROM
+	template_version_promotions
+WHERE
+	template_id = $1
+	AND rolled_back_at IS NULL
+ORDER BY
+	promoted_at DESC;
+
+-- name: UpdateTemplateVersionPromotionsRolledBack :exec
+-- Rolls back the promotions of the template that are more recent than
+-- promoted_after.
+UPDATE
+	template_version_promotions
+SET
+	rolled_back_at = @rolled_back_at
+WHERE
+	template_id = @template_id
+	AND rolled_back_at IS NULL
+	AND promoted_at > @promoted_after;
@@ -112,6 +112,15 @@ SET
 WHERE
 	job_id = $1;
 
+-- name: UpdateTemplateVersionPromotedByID :exec
+UPDATE
+	template_versions
+SET
+	promoted_at = $2,
+	promoted_by = $3
+WHERE
+	id = $1;
+
 -- name: GetPreviousTemplateVersion :one
 SELECT
 	*
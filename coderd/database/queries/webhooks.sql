@@ -0,0 +1,130 @@
+-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (
+		id,
+		name,
+		url,
+		secret,
+		events,
+		enabled,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateWebhookByID :one
+UPDATE
+	webhooks
+SET
+	name = @name,
+	url = @url,
+	events = @events,
+	enabled = @enabled,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1;
+
+-- name: GetWebhookByID :one
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetWebhooks :many
+SELECT
+	*
+FROM
+	webhooks
+ORDER BY
+	name ASC;
+
+-- name: GetEnabledWebhooksByEvent :many
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	enabled = true AND
+	@event :: webhook_event = ANY(events);
+
+-- name: InsertWebhookDelivery :one
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
+		created_at,
+		next_attempt_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- Acquires the oldest pending delivery that is due. The delivery is leased by
+-- pushing next_attempt_at to @lease_until, so that other replicas don't pick it
+-- up while it's being attempted. SKIP LOCKED prevents replicas from blocking on
+-- each other.
+--
+-- name: AcquireWebhookDelivery :one
+UPDATE
+	webhook_deliveries
+SET
+	next_attempt_at = @lease_until :: timestamptz
+WHERE
+	id = (
+		SELECT
+			id
+		FROM
+			webhook_deliveries AS nested
+		WHERE
+			nested.next_attempt_at IS NOT NULL AND
+			nested.next_attempt_at <= @now :: timestamptz
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			1
+	)
+RETURNING *;
+
+-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	attempts = @attempts,
+	next_attempt_at = @next_attempt_at,
+	delivered_at = @delivered_at,
+	last_status_code = @last_status_code,
+	last_error = @last_error
+WHERE
+	id = @id;
+
+-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM webhook_deliveries WHERE created_at < NOW() - INTERVAL '30 days';
@@ -60,10 +60,11 @@ INSERT INTO
 		login_before_ready,
 		startup_script_timeout_seconds,
 		shutdown_script,
-		shutdown_script_timeout_seconds
+		shutdown_script_timeout_seconds,
+		parent_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING *;
 
 -- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
@@ -90,7 +91,9 @@ WHERE
 UPDATE
 	workspace_agents
 SET
-	lifecycle_state = $2
+	lifecycle_state = $2,
+	started_at = $3,
+	ready_at = $4
 WHERE
 	id = $1;
 
@@ -151,9 +154,10 @@ WITH new_length AS (
 	startup_logs_length = startup_logs_length + @output_length WHERE workspace_agents.id = @agent_id
 )
 INSERT INTO
-		workspace_agent_startup_logs (agent_id, created_at, output, level)
+		workspace_agent_startup_logs (agent_id, script_id, created_at, output, level)
 	SELECT
 		@agent_id :: uuid AS agent_id,
+		@script_id :: uuid AS script_id,
 		unnest(@created_at :: timestamptz [ ]) AS created_at,
 		unnest(@output :: VARCHAR(1024) [ ]) AS output,
 		unnest(@level :: log_level [ ]) AS level
@@ -166,6 +170,39 @@ DELETE FROM workspace_agent_startup_logs WHERE agent_id IN
 	(SELECT id FROM workspace_agents WHERE last_connected_at IS NOT NULL
 		AND last_connected_at < NOW() - INTERVAL '7 day');
 
+-- name: GetWorkspaceAgentDebugLogsAfter :many
+SELECT
+	*
+FROM
+	workspace_agent_debug_logs
+WHERE
+	agent_id = $1
+	AND id > @created_after
+ORDER BY id ASC;
+
+-- Only the most recent 10,000 debug logs of an agent are kept, older logs are
+-- deleted as new logs are inserted.
+-- name: InsertWorkspaceAgentDebugLogs :many
+WITH trimmed AS (
+	DELETE FROM workspace_agent_debug_logs WHERE agent_id = @agent_id :: uuid AND id <= (
+		SELECT id FROM workspace_agent_debug_logs WHERE agent_id = @agent_id :: uuid
+		ORDER BY id DESC OFFSET GREATEST(10000 - cardinality(@output :: VARCHAR(4096) [ ]), 0) LIMIT 1
+	)
+)
+INSERT INTO
+		workspace_agent_debug_logs (agent_id, created_at, output, level)
+	SELECT
+		@agent_id :: uuid AS agent_id,
+		unnest(@created_at :: timestamptz [ ]) AS created_at,
+		unnest(@output :: VARCHAR(4096) [ ]) AS output,
+		unnest(@level :: log_level [ ]) AS level
+	RETURNING workspace_agent_debug_logs.*;
+
+-- name: DeleteOldWorkspaceAgentDebugLogs :exec
+DELETE FROM workspace_agent_debug_logs WHERE agent_id IN
+	(SELECT id FROM workspace_agents WHERE last_connected_at IS NOT NULL
+		AND last_connected_at < NOW() - INTERVAL '7 day');
+
 -- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
 SELECT
 	workspace_agents.*
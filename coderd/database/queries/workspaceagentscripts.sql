@@ -0,0 +1,41 @@
+-- name: InsertWorkspaceAgentScript :one
+INSERT INTO
+	workspace_agent_scripts (
+		id,
+		workspace_agent_id,
+		created_at,
+		display_name,
+		script,
+		cron,
+		run_order,
+		start_blocks_login,
+		run_on_start,
+		run_on_stop,
+		timeout_seconds,
+		log_path
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+
+-- name: GetWorkspaceAgentScriptsByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_scripts
+WHERE
+	workspace_agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	run_order ASC,
+	display_name ASC;
+
+-- name: UpdateWorkspaceAgentScriptStatus :exec
+UPDATE
+	workspace_agent_scripts
+SET
+	status = $3,
+	exit_code = $4,
+	started_at = $5,
+	completed_at = $6
+WHERE
+	id = $1
+	AND workspace_agent_id = $2;
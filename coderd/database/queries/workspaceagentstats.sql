@@ -17,10 +17,18 @@ INSERT INTO
 		session_count_jetbrains,
 		session_count_reconnecting_pty,
 		session_count_ssh,
-		connection_median_latency_ms
+		connection_median_latency_ms,
+		cpu_used_cores,
+		cpu_limit_cores,
+		memory_used_bytes,
+		memory_limit_bytes,
+		disk_used_bytes,
+		disk_total_bytes,
+		network_rx_bytes,
+		network_tx_bytes
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25) RETURNING *;
 
 -- name: GetTemplateDAUs :many
 SELECT
@@ -155,3 +163,39 @@ JOIN
 	workspaces
 ON
 	workspaces.id = agent_stats.workspace_id;
+
+-- name: GetWorkspaceAgentResourceStatsAndLabels :many
+-- Returns the latest resource usage reported by each agent.
+WITH latest_resource_stats AS (
+	SELECT
+		DISTINCT ON (agent_id)
+		agent_id, user_id, workspace_id,
+		cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes,
+		disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes
+	FROM
+		workspace_agent_stats
+	WHERE
+		workspace_agent_stats.created_at > $1
+		-- The limits are zero for agents that don't report resource usage.
+		AND memory_limit_bytes > 0
+	ORDER BY
+		agent_id, workspace_agent_stats.created_at DESC
+)
+SELECT
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name,
+	cpu_used_cores, cpu_limit_cores, memory_used_bytes, memory_limit_bytes,
+	disk_used_bytes, disk_total_bytes, network_rx_bytes, network_tx_bytes
+FROM
+	latest_resource_stats
+JOIN
+	users
+ON
+	users.id = latest_resource_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = latest_resource_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = latest_resource_stats.workspace_id;
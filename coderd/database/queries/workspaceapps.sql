@@ -27,10 +27,15 @@ INSERT INTO
         healthcheck_url,
         healthcheck_interval,
         healthcheck_threshold,
+        healthcheck_type,
+        healthcheck_address,
+        healthcheck_command,
+        healthcheck_expected_exit_codes,
+        healthcheck_output_regex,
         health
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING *;
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING *;
 
 -- name: UpdateWorkspaceAppHealthByID :exec
 UPDATE
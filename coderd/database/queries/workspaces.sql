@@ -198,6 +198,12 @@ WHERE
 			name ILIKE '%' || @name || '%'
 		ELSE true
 	END
+	-- Filter by dormancy
+	AND CASE
+		WHEN @dormant :: boolean THEN
+			dormant_at IS NOT NULL
+		ELSE true
+	END
 	-- Filter by agent status
 	-- has-agent: is only applicable for workspaces in "start" transition. Stopped and deleted workspaces don't have agents.
 	AND CASE
@@ -271,10 +277,11 @@ INSERT INTO
 		name,
 		autostart_schedule,
 		ttl,
-		last_used_at
+		last_used_at,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
 
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
@@ -310,6 +317,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceLastUsedAt :exec
 UPDATE
 	workspaces
@@ -318,6 +333,16 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceDormantAt :one
+UPDATE
+	workspaces
+SET
+	dormant_at = $2
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING *;
+
 -- name: UpdateWorkspaceTTLToBeWithinTemplateMax :exec
 UPDATE
 	workspaces
@@ -394,13 +419,17 @@ SELECT
 	stopped_workspaces.count AS stopped_workspaces
 FROM pending_workspaces, building_workspaces, running_workspaces, failed_workspaces, stopped_workspaces;
 
--- name: GetWorkspacesEligibleForAutoStartStop :many
+-- name: GetWorkspacesEligibleForTransition :many
 SELECT
 	workspaces.*
 FROM
 	workspaces
 LEFT JOIN
 	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+INNER JOIN
+	templates ON templates.id = workspaces.template_id
 WHERE
 	workspace_builds.build_number = (
 		SELECT
@@ -430,5 +459,37 @@ WHERE
 		(
 			workspace_builds.transition = 'stop'::workspace_transition AND
 			workspaces.autostart_schedule IS NOT NULL
+		) OR
+
+		-- If the workspace has not been used for longer than the template's
+		-- inactivity TTL, it is eligible to be marked dormant. The caller must
+		-- check the TTL in a license-aware fashion as the value stored here is
+		-- only honored with advanced template scheduling.
+		(
+			workspaces.deleted = false AND
+			workspaces.dormant_at IS NULL AND
+			templates.inactivity_ttl > 0 AND
+			workspaces.last_used_at + INTERVAL '1 microsecond' * (templates.inactivity_ttl / 1000) < @now :: timestamptz
+		) OR
+
+		-- If the workspace has been dormant for longer than the template's
+		-- dormant autodelete TTL, it is eligible for deletion.
+		(
+			workspaces.deleted = false AND
+			workspaces.dormant_at IS NOT NULL AND
+			templates.dormant_autodelete_ttl > 0 AND
+			workspace_builds.transition != 'delete'::workspace_transition AND
+			workspaces.dormant_at + INTERVAL '1 microsecond' * (templates.dormant_autodelete_ttl / 1000) < @now :: timestamptz
+		) OR
+
+		-- If the start build failed longer than the template's failure TTL
+		-- ago, the workspace is eligible to be stopped.
+		(
+			templates.failure_ttl > 0 AND
+			workspace_builds.transition = 'start'::workspace_transition AND
+			provisioner_jobs.completed_at IS NOT NULL AND
+			provisioner_jobs.error IS NOT NULL AND
+			provisioner_jobs.error != '' AND
+			provisioner_jobs.completed_at + INTERVAL '1 microsecond' * (templates.failure_ttl / 1000) < @now :: timestamptz
 		)
 	);
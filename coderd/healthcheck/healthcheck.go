@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"tailscale.com/tailcfg"
+
+	"github.com/coder/coder/codersdk"
 )
 
 type Report struct {
@@ -16,8 +18,9 @@ type Report struct {
 	// Healthy is true if the report returns no errors.
 	Healthy bool `json:"pass"`
 
-	DERP      DERPReport      `json:"derp"`
-	AccessURL AccessURLReport `json:"access_url"`
+	DERP           DERPReport           `json:"derp"`
+	AccessURL      AccessURLReport      `json:"access_url"`
+	WorkspaceProxy WorkspaceProxyReport `json:"workspace_proxy"`
 
 	// TODO:
 	// Websocket WebsocketReport `json:"websocket"`
@@ -28,6 +31,9 @@ type ReportOptions struct {
 	DERPMap   *tailcfg.DERPMap
 	AccessURL *url.URL
 	Client    *http.Client
+	// WorkspaceProxies returns the workspace proxies to report on. It's nil
+	// if the deployment doesn't support workspace proxies.
+	WorkspaceProxies func(ctx context.Context) ([]codersdk.WorkspaceProxy, error)
 }
 
 func Run(ctx context.Context, opts *ReportOptions) (*Report, error) {
@@ -52,6 +58,14 @@ func Run(ctx context.Context, opts *ReportOptions) (*Report, error) {
 		})
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		report.WorkspaceProxy.Run(ctx, &WorkspaceProxyReportOptions{
+			WorkspaceProxies: opts.WorkspaceProxies,
+		})
+	}()
+
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
@@ -60,6 +74,6 @@ func Run(ctx context.Context, opts *ReportOptions) (*Report, error) {
 
 	wg.Wait()
 	report.Time = time.Now()
-	report.Healthy = report.DERP.Healthy
+	report.Healthy = report.DERP.Healthy && report.WorkspaceProxy.Healthy
 	return &report, nil
 }
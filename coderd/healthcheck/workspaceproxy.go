@@ -0,0 +1,45 @@
+package healthcheck
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+type WorkspaceProxyReport struct {
+	Healthy bool `json:"healthy"`
+	// Unhealthy are the workspace proxies that aren't healthy. Users can't
+	// connect to workspaces through them.
+	Unhealthy []codersdk.WorkspaceProxy `json:"unhealthy"`
+	Error     *string                   `json:"error"`
+}
+
+type WorkspaceProxyReportOptions struct {
+	// WorkspaceProxies returns the workspace proxies of the deployment. There
+	// are no proxies to check if it's nil.
+	WorkspaceProxies func(ctx context.Context) ([]codersdk.WorkspaceProxy, error)
+}
+
+func (r *WorkspaceProxyReport) Run(ctx context.Context, opts *WorkspaceProxyReportOptions) {
+	r.Healthy = true
+	r.Unhealthy = []codersdk.WorkspaceProxy{}
+	if opts.WorkspaceProxies == nil {
+		return
+	}
+
+	proxies, err := opts.WorkspaceProxies(ctx)
+	if err != nil {
+		r.Healthy = false
+		msg := xerrors.Errorf("get workspace proxies: %w", err).Error()
+		r.Error = &msg
+		return
+	}
+	for _, proxy := range proxies {
+		if proxy.Status.Status != codersdk.ProxyHealthy {
+			r.Unhealthy = append(r.Unhealthy, proxy)
+		}
+	}
+	r.Healthy = len(r.Unhealthy) == 0
+}
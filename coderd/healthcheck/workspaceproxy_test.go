@@ -0,0 +1,70 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/healthcheck"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestWorkspaceProxies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotSupported", func(t *testing.T) {
+		t.Parallel()
+
+		var report healthcheck.WorkspaceProxyReport
+		report.Run(context.Background(), &healthcheck.WorkspaceProxyReportOptions{})
+
+		assert.True(t, report.Healthy)
+		assert.Empty(t, report.Unhealthy)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("Unhealthy", func(t *testing.T) {
+		t.Parallel()
+
+		healthy := codersdk.WorkspaceProxy{
+			Name:   "healthy",
+			Status: codersdk.WorkspaceProxyStatus{Status: codersdk.ProxyHealthy},
+		}
+		unreachable := codersdk.WorkspaceProxy{
+			Name: "unreachable",
+			Status: codersdk.WorkspaceProxyStatus{
+				Status: codersdk.ProxyUnreachable,
+				Error:  "connection refused",
+			},
+		}
+
+		var report healthcheck.WorkspaceProxyReport
+		report.Run(context.Background(), &healthcheck.WorkspaceProxyReportOptions{
+			WorkspaceProxies: func(context.Context) ([]codersdk.WorkspaceProxy, error) {
+				return []codersdk.WorkspaceProxy{healthy, unreachable}, nil
+			},
+		})
+
+		assert.False(t, report.Healthy)
+		assert.Equal(t, []codersdk.WorkspaceProxy{unreachable}, report.Unhealthy)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		var report healthcheck.WorkspaceProxyReport
+		report.Run(context.Background(), &healthcheck.WorkspaceProxyReportOptions{
+			WorkspaceProxies: func(context.Context) ([]codersdk.WorkspaceProxy, error) {
+				return nil, xerrors.New("database is down")
+			},
+		})
+
+		assert.False(t, report.Healthy)
+		if assert.NotNil(t, report.Error) {
+			assert.Contains(t, *report.Error, "database is down")
+		}
+	})
+}
@@ -195,6 +195,42 @@ func TestParseQueryParams(t *testing.T) {
 		testQueryParams(t, expParams, parser, parser.Int)
 	})
 
+	t.Run("Boolean", func(t *testing.T) {
+		t.Parallel()
+		expParams := []queryParamTestCase[bool]{
+			{
+				QueryParam: "valid_true",
+				Value:      "true",
+				Expected:   true,
+			},
+			{
+				QueryParam: "casing",
+				Value:      "True",
+				Expected:   true,
+			},
+			{
+				QueryParam: "valid_false",
+				Value:      "false",
+				Expected:   false,
+			},
+			{
+				QueryParam: "no_value_true_def",
+				NoSet:      true,
+				Default:    true,
+				Expected:   true,
+			},
+			{
+				QueryParam:            "invalid_boolean",
+				Value:                 "yes",
+				Expected:              false,
+				ExpectedErrorContains: "must be a valid boolean",
+			},
+		}
+
+		parser := httpapi.NewQueryParamParser()
+		testQueryParams(t, expParams, parser, parser.Boolean)
+	})
+
 	t.Run("UInt", func(t *testing.T) {
 		t.Parallel()
 		expParams := []queryParamTestCase[uint64]{
@@ -22,6 +22,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -371,12 +372,20 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	rbacRoles, err := rolestore.Expand(ctx, cfg.DB, roles.Roles)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding user's roles. %s", err.Error()),
+		})
+	}
+
 	// Actor is the user's authorization context.
 	authz := Authorization{
 		ActorName: roles.Username,
 		Actor: rbac.Subject{
 			ID:     key.UserID.String(),
-			Roles:  rbac.RoleNames(roles.Roles),
+			Roles:  rbacRoles,
 			Groups: roles.Groups,
 			Scope:  rbac.ScopeName(key.Scope),
 		},
@@ -0,0 +1,126 @@
+package httpmw
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+type provisionerKeyContextKey struct{}
+
+// ProvisionerKeyOptional may return the provisioner key from the
+// ExtractProvisionerKey middleware.
+func ProvisionerKeyOptional(r *http.Request) (database.ProvisionerKey, bool) {
+	key, ok := r.Context().Value(provisionerKeyContextKey{}).(database.ProvisionerKey)
+	return key, ok
+}
+
+type ExtractProvisionerKeyConfig struct {
+	DB database.Store
+	// Optional indicates whether the middleware should be optional. If true,
+	// any requests without the provisioner daemon key header will be allowed
+	// to continue and no provisioner key will be set on the request context.
+	Optional bool
+}
+
+// ExtractProvisionerKey extracts the provisioner key from the request using
+// the provisioner daemon key header.
+//
+// The format of a provisioner daemon key is:
+//
+//	<key id>:<key secret>
+func ExtractProvisionerKey(opts ExtractProvisionerKeyConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			token := r.Header.Get(codersdk.ProvisionerDaemonKeyHeader)
+			if token == "" {
+				if opts.Optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+					Message: "Missing required provisioner key",
+				})
+				return
+			}
+
+			parts := strings.Split(token, ":")
+			if len(parts) != 2 {
+				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+					Message: "Invalid provisioner key",
+				})
+				return
+			}
+			keyID, err := uuid.Parse(parts[0])
+			if err != nil {
+				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+					Message: "Invalid provisioner key",
+				})
+				return
+			}
+			secret := parts[1]
+			if len(secret) != 64 {
+				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+					Message: "Invalid provisioner key",
+				})
+				return
+			}
+
+			// nolint:gocritic // Get key by ID to check the secret.
+			key, err := opts.DB.GetProvisionerKeyByID(dbauthz.AsSystemRestricted(ctx), keyID)
+			if xerrors.Is(err, sql.ErrNoRows) {
+				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+					Message: "Invalid provisioner key",
+					Detail:  "Provisioner key not found.",
+				})
+				return
+			}
+			if err != nil {
+				httpapi.InternalServerError(w, err)
+				return
+			}
+
+			// Do a subtle constant time comparison of the hash of the secret.
+			hashedSecret := sha256.Sum256([]byte(secret))
+			if subtle.ConstantTimeCompare(key.HashedSecret, hashedSecret[:]) != 1 {
+				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+					Message: "Invalid provisioner key",
+					Detail:  "Invalid provisioner key secret.",
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, provisionerKeyContextKey{}, key)
+			//nolint:gocritic // Daemons authenticated with a key act as
+			// provisionerd. The middleware is only mounted to the routes
+			// daemons connect to.
+			ctx = dbauthz.AsProvisionerd(ctx)
+			subj, ok := dbauthz.ActorFromContext(ctx)
+			if !ok {
+				// This should never happen
+				httpapi.InternalServerError(w, xerrors.New("developer error: ExtractProvisionerKey missing rbac actor"))
+				return
+			}
+			ctx = context.WithValue(ctx, userAuthKey{}, Authorization{
+				Actor:     subj,
+				ActorName: "provisioner_key_" + key.Name,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
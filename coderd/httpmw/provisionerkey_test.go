@@ -0,0 +1,151 @@
+package httpmw_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+func TestExtractProvisionerKey(t *testing.T) {
+	t.Parallel()
+
+	successHandler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Only called if the provisioner key passes through the handler.
+		httpapi.Write(context.Background(), rw, http.StatusOK, codersdk.Response{
+			Message: "It worked!",
+		})
+	})
+
+	t.Run("NoHeader", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+		)
+		httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+			DB: db,
+		})(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("NoHeaderOptional", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+		)
+		httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+			DB:       db,
+			Optional: true,
+		})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			_, ok := httpmw.ProvisionerKeyOptional(r)
+			require.False(t, ok)
+			successHandler.ServeHTTP(rw, r)
+		})).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+		)
+		r.Header.Set(codersdk.ProvisionerDaemonKeyHeader, "test:wow-hello")
+
+		httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+			DB: db,
+		})(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+		)
+
+		secret, err := cryptorand.HexString(64)
+		require.NoError(t, err)
+		r.Header.Set(codersdk.ProvisionerDaemonKeyHeader, fmt.Sprintf("%s:%s", uuid.NewString(), secret))
+
+		httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+			DB: db,
+		})(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("InvalidSecret", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+
+			key, _ = dbgen.ProvisionerKey(t, db, database.ProvisionerKey{})
+		)
+
+		// Use a different secret so they don't match!
+		secret, err := cryptorand.HexString(64)
+		require.NoError(t, err)
+		r.Header.Set(codersdk.ProvisionerDaemonKeyHeader, fmt.Sprintf("%s:%s", key.ID.String(), secret))
+
+		httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+			DB: db,
+		})(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+
+			key, secret = dbgen.ProvisionerKey(t, db, database.ProvisionerKey{})
+		)
+		r.Header.Set(codersdk.ProvisionerDaemonKeyHeader, fmt.Sprintf("%s:%s", key.ID.String(), secret))
+
+		httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+			DB: db,
+		})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			// Checks that it exists on the context!
+			found, ok := httpmw.ProvisionerKeyOptional(r)
+			require.True(t, ok)
+			require.Equal(t, key.ID, found.ID)
+			successHandler.ServeHTTP(rw, r)
+		})).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
@@ -0,0 +1,49 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+type webhookParamContextKey struct{}
+
+// WebhookParam returns the webhook from the ExtractWebhookParam handler.
+func WebhookParam(r *http.Request) database.Webhook {
+	webhook, ok := r.Context().Value(webhookParamContextKey{}).(database.Webhook)
+	if !ok {
+		panic("developer error: webhook param middleware not provided")
+	}
+	return webhook
+}
+
+// ExtractWebhookParam grabs a webhook from the "webhook" URL parameter.
+func ExtractWebhookParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			webhookID, parsed := parseUUID(rw, r, "webhook")
+			if !parsed {
+				return
+			}
+			webhook, err := db.GetWebhookByID(ctx, webhookID)
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching webhook.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, webhookParamContextKey{}, webhook)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/httpmw"
+)
+
+func TestWebhookParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func() (*http.Request, *httptest.ResponseRecorder) {
+		r := httptest.NewRequest("GET", "/", nil)
+		ctx := chi.NewRouteContext()
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+		return r, httptest.NewRecorder()
+	}
+
+	t.Run("None", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractWebhookParam(db))
+		rtr.Get("/", nil)
+		r, rw := setup()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractWebhookParam(db))
+		rtr.Get("/", nil)
+		r, rw := setup()
+		chi.RouteContext(r.Context()).URLParams.Add("webhook", uuid.NewString())
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		webhook := dbgen.Webhook(t, db, database.Webhook{})
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractWebhookParam(db))
+		rtr.Get("/", func(rw http.ResponseWriter, r *http.Request) {
+			require.Equal(t, webhook.ID, httpmw.WebhookParam(r).ID)
+			rw.WriteHeader(http.StatusOK)
+		})
+		r, rw := setup()
+		chi.RouteContext(r.Context()).URLParams.Add("webhook", webhook.ID.String())
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
@@ -156,3 +157,51 @@ func ExtractWorkspaceProxy(opts ExtractWorkspaceProxyConfig) func(http.Handler)
 		})
 	}
 }
+
+type workspaceProxyParamContextKey struct{}
+
+// WorkspaceProxyParam returns the workspace proxy from the ExtractWorkspaceProxyParam handler.
+func WorkspaceProxyParam(r *http.Request) database.WorkspaceProxy {
+	proxy, ok := r.Context().Value(workspaceProxyParamContextKey{}).(database.WorkspaceProxy)
+	if !ok {
+		panic("developer error: workspace proxy parameter middleware not provided")
+	}
+	return proxy
+}
+
+// ExtractWorkspaceProxyParam extracts the workspace proxy from the
+// "workspaceproxy" URL parameter, which is the ID or the name of the proxy.
+func ExtractWorkspaceProxyParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			proxyQuery := chi.URLParam(r, "workspaceproxy")
+			if proxyQuery == "" {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "\"workspaceproxy\" must be provided.",
+				})
+				return
+			}
+
+			var proxy database.WorkspaceProxy
+			var dbErr error
+			if proxyID, err := uuid.Parse(proxyQuery); err == nil {
+				proxy, dbErr = db.GetWorkspaceProxyByID(ctx, proxyID)
+			} else {
+				proxy, dbErr = db.GetWorkspaceProxyByName(ctx, proxyQuery)
+			}
+			if httpapi.Is404Error(dbErr) || (dbErr == nil && proxy.Deleted) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if dbErr != nil {
+				httpapi.InternalServerError(rw, dbErr)
+				return
+			}
+
+			ctx = context.WithValue(ctx, workspaceProxyParamContextKey{}, proxy)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
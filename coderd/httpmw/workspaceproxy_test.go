@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
@@ -161,3 +162,81 @@ func TestExtractWorkspaceProxy(t *testing.T) {
 		require.Equal(t, http.StatusOK, res.StatusCode)
 	})
 }
+
+func TestExtractWorkspaceProxyParam(t *testing.T) {
+	t.Parallel()
+
+	successHandler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Only called if the proxy is found.
+		httpapi.Write(context.Background(), rw, http.StatusOK, codersdk.Response{
+			Message: httpmw.WorkspaceProxyParam(r).Name,
+		})
+	})
+
+	request := func(param string) *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		routeContext := chi.NewRouteContext()
+		routeContext.URLParams.Add("workspaceproxy", param)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeContext))
+	}
+
+	t.Run("OKName", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db       = dbfake.New()
+			proxy, _ = dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+			rw       = httptest.NewRecorder()
+		)
+
+		httpmw.ExtractWorkspaceProxyParam(db)(successHandler).ServeHTTP(rw, request(proxy.Name))
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("OKID", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db       = dbfake.New()
+			proxy, _ = dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+			rw       = httptest.NewRecorder()
+		)
+
+		httpmw.ExtractWorkspaceProxyParam(db)(successHandler).ServeHTTP(rw, request(proxy.ID.String()))
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db = dbfake.New()
+			rw = httptest.NewRecorder()
+		)
+
+		httpmw.ExtractWorkspaceProxyParam(db)(successHandler).ServeHTTP(rw, request(uuid.NewString()))
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db       = dbfake.New()
+			proxy, _ = dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+			rw       = httptest.NewRecorder()
+		)
+		err := db.UpdateWorkspaceProxyDeleted(context.Background(), database.UpdateWorkspaceProxyDeletedParams{
+			ID:      proxy.ID,
+			Deleted: true,
+		})
+		require.NoError(t, err)
+
+		httpmw.ExtractWorkspaceProxyParam(db)(successHandler).ServeHTTP(rw, request(proxy.ID.String()))
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
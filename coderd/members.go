@@ -80,10 +80,9 @@ func (api *API) updateOrganizationMemberRoles(ctx context.Context, args database
 		if roleOrg != args.OrgID {
 			return database.OrganizationMember{}, xerrors.Errorf("Must only pass roles for org %q", args.OrgID.String())
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.OrganizationMember{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	}
+	if err := api.validateRoleNames(ctx, args.GrantedRoles); err != nil {
+		return database.OrganizationMember{}, err
 	}
 
 	updatedUser, err := api.Database.UpdateMemberRoles(ctx, args)
@@ -103,8 +102,7 @@ func convertOrganizationMember(mem database.OrganizationMember) codersdk.Organiz
 	}
 
 	for _, roleName := range mem.Roles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedMember.Roles = append(convertedMember.Roles, convertRole(rbacRole))
+		convertedMember.Roles = append(convertedMember.Roles, convertRoleName(roleName))
 	}
 	return convertedMember
 }
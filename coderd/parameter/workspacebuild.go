@@ -0,0 +1,192 @@
+package parameter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	sdkproto "github.com/coder/coder/provisionersdk/proto"
+)
+
+// ValidationError is returned when the rich parameter values of a workspace
+// build are invalid for the template version it is built against.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// WorkspaceBuildScope targets the workspace build that rich parameter values
+// are resolved for.
+type WorkspaceBuildScope struct {
+	WorkspaceID       uuid.UUID
+	TemplateVersionID uuid.UUID
+	// PriorBuildID is the latest build of the workspace. It is uuid.Nil for
+	// the first build of a workspace.
+	PriorBuildID uuid.UUID
+}
+
+// WorkspaceBuildParameters resolves the rich parameter values of a new
+// workspace build. Values in the request take precedence over the values of
+// the prior build, and legacy parameter values of the workspace are migrated
+// to the rich parameters that replace them. A *ValidationError is returned if
+// the resulting values are invalid for the template version.
+func WorkspaceBuildParameters(ctx context.Context, db database.Store, scope WorkspaceBuildScope, requested []codersdk.WorkspaceBuildParameter) ([]codersdk.WorkspaceBuildParameter, error) {
+	dbTemplateVersionParameters, err := db.GetTemplateVersionParameters(ctx, scope.TemplateVersionID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	templateVersionParameters, err := ConvertTemplateVersionParameters(dbTemplateVersionParameters)
+	if err != nil {
+		return nil, xerrors.Errorf("convert template version parameters: %w", err)
+	}
+
+	dbLastBuildParameters, err := db.GetWorkspaceBuildParameters(ctx, scope.PriorBuildID)
+	if err != nil {
+		return nil, xerrors.Errorf("get prior workspace build parameters: %w", err)
+	}
+	lastBuildParameters := make([]codersdk.WorkspaceBuildParameter, 0, len(dbLastBuildParameters))
+	for _, param := range dbLastBuildParameters {
+		lastBuildParameters = append(lastBuildParameters, codersdk.WorkspaceBuildParameter{
+			Name:  param.Name,
+			Value: param.Value,
+		})
+	}
+
+	legacyParameters, err := db.ParameterValues(ctx, database.ParameterValuesParams{
+		Scopes:   []database.ParameterScope{database.ParameterScopeWorkspace},
+		ScopeIds: []uuid.UUID{scope.WorkspaceID},
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get legacy parameters: %w", err)
+	}
+
+	// Rich parameters migration: include legacy variables to the last build parameters
+	for _, templateVersionParameter := range templateVersionParameters {
+		// Check if parameter is defined in previous build
+		if _, found := findWorkspaceBuildParameter(lastBuildParameters, templateVersionParameter.Name); found {
+			continue
+		}
+
+		// Check if legacy variable is defined
+		for _, legacyParameter := range legacyParameters {
+			if legacyParameter.Name != templateVersionParameter.LegacyVariableName {
+				continue
+			}
+
+			lastBuildParameters = append(lastBuildParameters, codersdk.WorkspaceBuildParameter{
+				Name:  templateVersionParameter.Name,
+				Value: legacyParameter.SourceValue,
+			})
+			break
+		}
+	}
+
+	err = codersdk.ValidateWorkspaceBuildParameters(templateVersionParameters, requested, lastBuildParameters)
+	if err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+
+	var parameters []codersdk.WorkspaceBuildParameter
+	for _, templateVersionParameter := range templateVersionParameters {
+		// Check if parameter value is in request
+		if buildParameter, found := findWorkspaceBuildParameter(requested, templateVersionParameter.Name); found {
+			if !templateVersionParameter.Mutable {
+				if _, found := findWorkspaceBuildParameter(lastBuildParameters, templateVersionParameter.Name); found {
+					return nil, &ValidationError{
+						Err: xerrors.Errorf("parameter %q is not mutable, so it can't be updated after creating a workspace", templateVersionParameter.Name),
+					}
+				}
+			}
+			parameters = append(parameters, *buildParameter)
+			continue
+		}
+
+		// Check if parameter is defined in previous build
+		if buildParameter, found := findWorkspaceBuildParameter(lastBuildParameters, templateVersionParameter.Name); found {
+			parameters = append(parameters, *buildParameter)
+		}
+	}
+	return parameters, nil
+}
+
+// ConvertTemplateVersionParameters converts rich parameters of a template
+// version from their database representation.
+func ConvertTemplateVersionParameters(dbParams []database.TemplateVersionParameter) ([]codersdk.TemplateVersionParameter, error) {
+	params := make([]codersdk.TemplateVersionParameter, 0)
+	for _, dbParameter := range dbParams {
+		param, err := ConvertTemplateVersionParameter(dbParameter)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// ConvertTemplateVersionParameter converts a rich parameter of a template
+// version from its database representation.
+func ConvertTemplateVersionParameter(param database.TemplateVersionParameter) (codersdk.TemplateVersionParameter, error) {
+	var protoOptions []*sdkproto.RichParameterOption
+	err := json.Unmarshal(param.Options, &protoOptions)
+	if err != nil {
+		return codersdk.TemplateVersionParameter{}, err
+	}
+	options := make([]codersdk.TemplateVersionParameterOption, 0)
+	for _, option := range protoOptions {
+		options = append(options, codersdk.TemplateVersionParameterOption{
+			Name:        option.Name,
+			Description: option.Description,
+			Value:       option.Value,
+			Icon:        option.Icon,
+		})
+	}
+
+	descriptionPlaintext, err := Plaintext(param.Description)
+	if err != nil {
+		return codersdk.TemplateVersionParameter{}, err
+	}
+	return codersdk.TemplateVersionParameter{
+		Name:                 param.Name,
+		DisplayName:          param.DisplayName,
+		Description:          param.Description,
+		DescriptionPlaintext: descriptionPlaintext,
+		Type:                 param.Type,
+		Mutable:              param.Mutable,
+		DefaultValue:         param.DefaultValue,
+		Icon:                 param.Icon,
+		Options:              options,
+		ValidationRegex:      param.ValidationRegex,
+		ValidationMin:        param.ValidationMin,
+		ValidationMax:        param.ValidationMax,
+		ValidationError:      param.ValidationError,
+		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
+		Required:             param.Required,
+		LegacyVariableName:   param.LegacyVariableName,
+	}, nil
+}
+
+func findWorkspaceBuildParameter(params []codersdk.WorkspaceBuildParameter, parameterName string) (*codersdk.WorkspaceBuildParameter, bool) {
+	if params == nil {
+		return nil, false
+	}
+
+	for _, p := range params {
+		if p.Name == parameterName {
+			return &p, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,115 @@
+package parameter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/parameter"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestWorkspaceBuildParameters(t *testing.T) {
+	t.Parallel()
+
+	// setup inserts a template version with the given rich parameters, and
+	// a prior build of a workspace with the given parameter values.
+	setup := func(t *testing.T, db database.Store, params []database.InsertTemplateVersionParameterParams, prior map[string]string) parameter.WorkspaceBuildScope {
+		t.Helper()
+		scope := parameter.WorkspaceBuildScope{
+			WorkspaceID:       uuid.New(),
+			TemplateVersionID: uuid.New(),
+			PriorBuildID:      uuid.New(),
+		}
+		for _, param := range params {
+			param.TemplateVersionID = scope.TemplateVersionID
+			param.Type = "string"
+			param.Options = []byte("[]")
+			_, err := db.InsertTemplateVersionParameter(context.Background(), param)
+			require.NoError(t, err)
+		}
+		names := make([]string, 0, len(prior))
+		values := make([]string, 0, len(prior))
+		for name, value := range prior {
+			names = append(names, name)
+			values = append(values, value)
+		}
+		err := db.InsertWorkspaceBuildParameters(context.Background(), database.InsertWorkspaceBuildParametersParams{
+			WorkspaceBuildID: scope.PriorBuildID,
+			Name:             names,
+			Value:            values,
+		})
+		require.NoError(t, err)
+		return scope
+	}
+
+	t.Run("UsePriorValues", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		scope := setup(t, db, []database.InsertTemplateVersionParameterParams{
+			{Name: "region", Mutable: true, Required: true},
+			{Name: "size", Mutable: true, DefaultValue: "small"},
+		}, map[string]string{"region": "eu", "removed": "value"})
+
+		params, err := parameter.WorkspaceBuildParameters(context.Background(), db, scope, []codersdk.WorkspaceBuildParameter{
+			{Name: "size", Value: "large"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{
+			{Name: "region", Value: "eu"},
+			{Name: "size", Value: "large"},
+		}, params)
+	})
+
+	t.Run("MigrateLegacyParameters", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		scope := setup(t, db, []database.InsertTemplateVersionParameterParams{
+			{Name: "region", Mutable: true, Required: true, LegacyVariableName: "legacy_region"},
+		}, nil)
+		_ = dbgen.ParameterValue(t, db, database.ParameterValue{
+			Name:        "legacy_region",
+			Scope:       database.ParameterScopeWorkspace,
+			ScopeID:     scope.WorkspaceID,
+			SourceValue: "us",
+		})
+
+		params, err := parameter.WorkspaceBuildParameters(context.Background(), db, scope, nil)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{
+			{Name: "region", Value: "us"},
+		}, params)
+	})
+
+	t.Run("MissingRequiredParameter", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		scope := setup(t, db, []database.InsertTemplateVersionParameterParams{
+			{Name: "region", Mutable: true, Required: true},
+		}, nil)
+
+		_, err := parameter.WorkspaceBuildParameters(context.Background(), db, scope, nil)
+		var validationErr *parameter.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("ImmutableParameterChanged", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		scope := setup(t, db, []database.InsertTemplateVersionParameterParams{
+			{Name: "region", Mutable: false},
+		}, map[string]string{"region": "eu"})
+
+		_, err := parameter.WorkspaceBuildParameters(context.Background(), db, scope, []codersdk.WorkspaceBuildParameter{
+			{Name: "region", Value: "us"},
+		})
+		var validationErr *parameter.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.ErrorContains(t, err, "is not mutable")
+	})
+}
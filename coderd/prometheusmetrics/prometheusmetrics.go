@@ -416,6 +416,94 @@ func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.R
 		return nil, err
 	}
 
+	agentStatsCPUUsedCoresGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "cpu_used_cores",
+		Help:      "The number of CPU cores used by the workspace",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsCPUUsedCoresGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsCPULimitCoresGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "cpu_limit_cores",
+		Help:      "The number of CPU cores available to the workspace",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsCPULimitCoresGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsMemoryUsedBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "memory_used_bytes",
+		Help:      "The memory used by the workspace in bytes",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsMemoryUsedBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsMemoryLimitBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "memory_limit_bytes",
+		Help:      "The memory available to the workspace in bytes",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsMemoryLimitBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsDiskUsedBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "disk_used_bytes",
+		Help:      "The disk space used by the workspace in bytes",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsDiskUsedBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsDiskTotalBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "disk_total_bytes",
+		Help:      "The total disk space of the workspace in bytes",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsDiskTotalBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsNetworkRxBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "network_rx_bytes",
+		Help:      "The bytes received by the network interfaces of the workspace",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsNetworkRxBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsNetworkTxBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "network_tx_bytes",
+		Help:      "The bytes transmitted by the network interfaces of the workspace",
+	}, []string{"agent_name", "username", "workspace_name"}))
+	err = registerer.Register(agentStatsNetworkTxBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancelFunc := context.WithCancel(ctx)
 	done := make(chan struct{})
 
@@ -466,6 +554,33 @@ func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.R
 				}
 			}
 
+			resourceStats, err := db.GetWorkspaceAgentResourceStatsAndLabels(ctx, createdAfter)
+			if err != nil {
+				logger.Error(ctx, "can't get agent resource stats", slog.Error(err))
+			} else {
+				for _, resourceStat := range resourceStats {
+					agentStatsCPUUsedCoresGauge.WithLabelValues(VectorOperationSet, resourceStat.CPUUsedCores, resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsCPULimitCoresGauge.WithLabelValues(VectorOperationSet, resourceStat.CPULimitCores, resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsMemoryUsedBytesGauge.WithLabelValues(VectorOperationSet, float64(resourceStat.MemoryUsedBytes), resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsMemoryLimitBytesGauge.WithLabelValues(VectorOperationSet, float64(resourceStat.MemoryLimitBytes), resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsDiskUsedBytesGauge.WithLabelValues(VectorOperationSet, float64(resourceStat.DiskUsedBytes), resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsDiskTotalBytesGauge.WithLabelValues(VectorOperationSet, float64(resourceStat.DiskTotalBytes), resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsNetworkRxBytesGauge.WithLabelValues(VectorOperationSet, float64(resourceStat.NetworkRxBytes), resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+					agentStatsNetworkTxBytesGauge.WithLabelValues(VectorOperationSet, float64(resourceStat.NetworkTxBytes), resourceStat.AgentName, resourceStat.Username, resourceStat.WorkspaceName)
+				}
+
+				if len(resourceStats) > 0 {
+					agentStatsCPUUsedCoresGauge.Commit()
+					agentStatsCPULimitCoresGauge.Commit()
+					agentStatsMemoryUsedBytesGauge.Commit()
+					agentStatsMemoryLimitBytesGauge.Commit()
+					agentStatsDiskUsedBytesGauge.Commit()
+					agentStatsDiskTotalBytesGauge.Commit()
+					agentStatsNetworkRxBytesGauge.Commit()
+					agentStatsNetworkTxBytesGauge.Commit()
+				}
+			}
+
 			logger.Debug(ctx, "Agent metrics collection is done")
 			metricsCollectorAgentStats.Observe(timer.ObserveDuration().Seconds())
 
@@ -389,6 +389,12 @@ func TestAgentStats(t *testing.T) {
 			SessionCountVSCode: 3 + i, SessionCountJetBrains: 4 + i, SessionCountReconnectingPTY: 5 + i, SessionCountSSH: 6 + i,
 			ConnectionCount: 7 + i, ConnectionMedianLatencyMS: 8000,
 			ConnectionsByProto: map[string]int64{"TCP": 1},
+			Resources: &agentsdk.ResourceUsage{
+				CPUUsedCores: float64(1 + i), CPULimitCores: 4,
+				MemoryUsedBytes: 100 + i, MemoryLimitBytes: 1000,
+				DiskUsedBytes: 200 + i, DiskTotalBytes: 2000,
+				NetworkRxBytes: 300 + i, NetworkTxBytes: 400 + i,
+			},
 		})
 		require.NoError(t, err)
 
@@ -397,6 +403,12 @@ func TestAgentStats(t *testing.T) {
 			SessionCountVSCode: 6 + i, SessionCountJetBrains: 8 + i, SessionCountReconnectingPTY: 10 + i, SessionCountSSH: 12 + i,
 			ConnectionCount: 8 + i, ConnectionMedianLatencyMS: 10000,
 			ConnectionsByProto: map[string]int64{"TCP": 1},
+			Resources: &agentsdk.ResourceUsage{
+				CPUUsedCores: float64(2 + i), CPULimitCores: 8,
+				MemoryUsedBytes: 500 + i, MemoryLimitBytes: 5000,
+				DiskUsedBytes: 600 + i, DiskTotalBytes: 6000,
+				NetworkRxBytes: 700 + i, NetworkTxBytes: 800 + i,
+			},
 		})
 		require.NoError(t, err)
 
@@ -447,7 +459,15 @@ func TestAgentStats(t *testing.T) {
 				"coderd_agentstats_session_count_jetbrains",
 				"coderd_agentstats_session_count_reconnecting_pty",
 				"coderd_agentstats_session_count_ssh",
-				"coderd_agentstats_session_count_vscode":
+				"coderd_agentstats_session_count_vscode",
+				"coderd_agentstats_cpu_used_cores",
+				"coderd_agentstats_cpu_limit_cores",
+				"coderd_agentstats_memory_used_bytes",
+				"coderd_agentstats_memory_limit_bytes",
+				"coderd_agentstats_disk_used_bytes",
+				"coderd_agentstats_disk_total_bytes",
+				"coderd_agentstats_network_rx_bytes",
+				"coderd_agentstats_network_tx_bytes":
 				for _, m := range metric.Metric {
 					// username:workspace:agent:metric = value
 					collected[m.Label[1].GetValue()+":"+m.Label[2].GetValue()+":"+m.Label[0].GetValue()+":"+metric.GetName()] = int(m.Gauge.GetValue())
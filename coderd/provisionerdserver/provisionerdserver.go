@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
 	"github.com/tabbed/pqtype"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
@@ -33,6 +35,7 @@ import (
 	"github.com/coder/coder/coderd/parameter"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisioner"
 	"github.com/coder/coder/provisionerd/proto"
@@ -46,27 +49,95 @@ var (
 )
 
 type Server struct {
-	AccessURL             *url.URL
-	ID                    uuid.UUID
-	Logger                slog.Logger
-	Provisioners          []database.ProvisionerType
-	GitAuthConfigs        []*gitauth.Config
-	Tags                  json.RawMessage
-	Database              database.Store
-	Pubsub                database.Pubsub
-	Telemetry             telemetry.Reporter
-	QuotaCommitter        *atomic.Pointer[proto.QuotaCommitter]
-	Auditor               *atomic.Pointer[audit.Auditor]
-	TemplateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
+	AccessURL                   *url.URL
+	ID                          uuid.UUID
+	Logger                      slog.Logger
+	Provisioners                []database.ProvisionerType
+	GitAuthConfigs              []*gitauth.Config
+	Tags                        json.RawMessage
+	Database                    database.Store
+	Pubsub                      database.Pubsub
+	Telemetry                   telemetry.Reporter
+	QuotaCommitter              *atomic.Pointer[proto.QuotaCommitter]
+	Auditor                     *atomic.Pointer[audit.Auditor]
+	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
+	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 
 	AcquireJobDebounce time.Duration
 	OIDCConfig         httpmw.OAuth2Config
+
+	// ProvisionerKeyID is the key the daemon authenticated with, if any.
+	// Jobs are only acquired while the key exists.
+	ProvisionerKeyID uuid.NullUUID
+	// OrganizationID limits the acquired jobs to an organization. Jobs of
+	// every organization are acquired if it's nil.
+	OrganizationID uuid.UUID
+
+	lastSeenMutex sync.Mutex
+	lastSeenAt    time.Time
+	keyCheckedAt  time.Time
+	keyDeleted    bool
+}
+
+// lastSeenInterval is how often the last seen time of a daemon is written
+// to the database, and how often its provisioner key is checked.
+const lastSeenInterval = 30 * time.Second
+
+// updateLastSeen records that the daemon is still connected. Writes are
+// throttled to lastSeenInterval, because jobs are polled far more often.
+func (server *Server) updateLastSeen(ctx context.Context) {
+	now := database.Now()
+	server.lastSeenMutex.Lock()
+	if now.Sub(server.lastSeenAt) < lastSeenInterval {
+		server.lastSeenMutex.Unlock()
+		return
+	}
+	server.lastSeenAt = now
+	server.lastSeenMutex.Unlock()
+
+	err := server.Database.UpdateProvisionerDaemonLastSeenAt(ctx, database.UpdateProvisionerDaemonLastSeenAtParams{
+		ID:         server.ID,
+		LastSeenAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		server.Logger.Warn(ctx, "update provisioner daemon last seen at", slog.Error(err))
+	}
+}
+
+// checkProvisionerKey fails once the provisioner key of the daemon has been
+// deleted. The key is checked at most every lastSeenInterval, since jobs are
+// polled far more often.
+func (server *Server) checkProvisionerKey(ctx context.Context) error {
+	if !server.ProvisionerKeyID.Valid {
+		return nil
+	}
+	now := database.Now()
+	server.lastSeenMutex.Lock()
+	defer server.lastSeenMutex.Unlock()
+	if server.keyDeleted {
+		return xerrors.New("the provisioner key of the daemon has been deleted")
+	}
+	if now.Sub(server.keyCheckedAt) < lastSeenInterval {
+		return nil
+	}
+
+	_, err := server.Database.GetProvisionerKeyByID(ctx, server.ProvisionerKeyID.UUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		server.keyDeleted = true
+		return xerrors.New("the provisioner key of the daemon has been deleted")
+	}
+	if err != nil {
+		return xerrors.Errorf("get provisioner key: %w", err)
+	}
+	server.keyCheckedAt = now
+	return nil
 }
 
 // AcquireJob queries the database to lock a job.
 func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
 	//nolint:gocritic // Provisionerd has specific authz rules.
 	ctx = dbauthz.AsProvisionerd(ctx)
+	server.updateLastSeen(ctx)
 	// This prevents loads of provisioner daemons from consistently
 	// querying the database when no jobs are available.
 	//
@@ -78,6 +149,12 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		return &proto.AcquiredJob{}, nil
 	}
 	lastAcquireMutex.RUnlock()
+
+	err := server.checkProvisionerKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// This marks the job as locked in the database.
 	job, err := server.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
 		StartedAt: sql.NullTime{
@@ -88,8 +165,9 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 			UUID:  server.ID,
 			Valid: true,
 		},
-		Types: server.Provisioners,
-		Tags:  server.Tags,
+		Types:          server.Provisioners,
+		Tags:           server.Tags,
+		OrganizationID: server.OrganizationID,
 	})
 	if errors.Is(err, sql.ErrNoRows) {
 		// The provisioner daemon assumes no jobs are available if
@@ -170,6 +248,7 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("publish workspace update: %s", err))
 		}
+		server.publishWorkspaceBuildWebhook(ctx, database.WebhookEventWorkspaceBuildStarted, workspaceBuild, workspace, "")
 
 		var workspaceOwnerOIDCAccessToken string
 		if server.OIDCConfig != nil {
@@ -434,8 +513,9 @@ func (server *Server) CommitQuota(ctx context.Context, request *proto.CommitQuot
 	if q == nil {
 		// We're probably in community edition or a test.
 		return &proto.CommitQuotaResponse{
-			Budget: -1,
-			Ok:     true,
+			Budget:    -1,
+			Ok:        true,
+			DailyCost: request.DailyCost,
 		}, nil
 	}
 	return (*q).CommitQuota(ctx, request)
@@ -444,6 +524,7 @@ func (server *Server) CommitQuota(ctx context.Context, request *proto.CommitQuot
 func (server *Server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
 	//nolint:gocritic // Provisionerd has specific authz rules.
 	ctx = dbauthz.AsProvisionerd(ctx)
+	server.updateLastSeen(ctx)
 	parsedID, err := uuid.Parse(request.JobId)
 	if err != nil {
 		return nil, xerrors.Errorf("parse job id: %w", err)
@@ -802,6 +883,8 @@ func (server *Server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*p
 					Status:           http.StatusInternalServerError,
 					AdditionalFields: wriBytes,
 				})
+
+				server.publishWorkspaceBuildWebhook(ctx, database.WebhookEventWorkspaceBuildFailed, build, workspace, failJob.Error)
 			}
 		}
 	}
@@ -959,15 +1042,7 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 		var getWorkspaceError error
 
 		err = server.Database.InTx(func(db database.Store) error {
-			var (
-				now = database.Now()
-				// deadline is the time when the workspace will be stopped. The
-				// value can be bumped by user activity or manually by the user
-				// via the UI.
-				deadline time.Time
-				// maxDeadline is the maximum value for deadline.
-				maxDeadline time.Time
-			)
+			now := database.Now()
 
 			workspace, getWorkspaceError = db.GetWorkspaceByID(ctx, workspaceBuild.WorkspaceID)
 			if getWorkspaceError != nil {
@@ -978,31 +1053,16 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 				)
 				return getWorkspaceError
 			}
-			if workspace.Ttl.Valid {
-				deadline = now.Add(time.Duration(workspace.Ttl.Int64))
-			}
 
-			templateSchedule, err := (*server.TemplateScheduleStore.Load()).GetTemplateScheduleOptions(ctx, db, workspace.TemplateID)
+			autoStop, err := schedule.CalculateAutostop(ctx, schedule.CalculateAutostopParams{
+				Database:                    db,
+				TemplateScheduleStore:       *server.TemplateScheduleStore.Load(),
+				UserQuietHoursScheduleStore: *server.UserQuietHoursScheduleStore.Load(),
+				Now:                         now,
+				Workspace:                   workspace,
+			})
 			if err != nil {
-				return xerrors.Errorf("get template schedule options: %w", err)
-			}
-			if !templateSchedule.UserAutostopEnabled {
-				// The user is not permitted to set their own TTL, so use the
-				// template default.
-				deadline = time.Time{}
-				if templateSchedule.DefaultTTL > 0 {
-					deadline = now.Add(templateSchedule.DefaultTTL)
-				}
-			}
-			if templateSchedule.MaxTTL > 0 {
-				maxDeadline = now.Add(templateSchedule.MaxTTL)
-
-				if deadline.IsZero() || maxDeadline.Before(deadline) {
-					// If the workspace doesn't have a deadline or the max
-					// deadline is sooner than the workspace deadline, use the
-					// max deadline as the actual deadline.
-					deadline = maxDeadline
-				}
+				return xerrors.Errorf("calculate auto stop: %w", err)
 			}
 
 			err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
@@ -1018,8 +1078,8 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 			}
 			_, err = db.UpdateWorkspaceBuildByID(ctx, database.UpdateWorkspaceBuildByIDParams{
 				ID:               workspaceBuild.ID,
-				Deadline:         deadline,
-				MaxDeadline:      maxDeadline,
+				Deadline:         autoStop.Deadline,
+				MaxDeadline:      autoStop.MaxDeadline,
 				ProvisionerState: jobType.WorkspaceBuild.State,
 				UpdatedAt:        now,
 			})
@@ -1040,6 +1100,11 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 				}
 			}
 
+			err = InsertProvisionerJobTimings(ctx, db, job.ID, jobType.WorkspaceBuild.Timings)
+			if err != nil {
+				return xerrors.Errorf("insert provisioner job timings: %w", err)
+			}
+
 			// On start, we want to ensure that workspace agents timeout statuses
 			// are propagated. This method is simple and does not protect against
 			// notifying in edge cases like when a workspace is stopped soon
@@ -1135,6 +1200,8 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 				Status:           http.StatusOK,
 				AdditionalFields: wriBytes,
 			})
+
+			server.publishWorkspaceBuildWebhook(ctx, database.WebhookEventWorkspaceBuildSucceeded, workspaceBuild, workspace, "")
 		}
 
 		err = server.Pubsub.Publish(codersdk.WorkspaceNotifyChannel(workspaceBuild.WorkspaceID), []byte{})
@@ -1192,6 +1259,31 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 	return &proto.Empty{}, nil
 }
 
+// InsertProvisionerJobTimings persists the timings that a provisioner reported
+// for a job. Timings of stages that are unknown to coderd are dropped.
+func InsertProvisionerJobTimings(ctx context.Context, db database.Store, jobID uuid.UUID, timings []*sdkproto.Timing) error {
+	params := database.InsertProvisionerJobTimingsParams{
+		JobID: jobID,
+	}
+	for _, timing := range timings {
+		stage := database.ProvisionerJobTimingStage(timing.Stage)
+		if !stage.Valid() || timing.Start == nil || timing.End == nil {
+			continue
+		}
+		params.StartedAt = append(params.StartedAt, timing.Start.AsTime())
+		params.EndedAt = append(params.EndedAt, timing.End.AsTime())
+		params.Stage = append(params.Stage, stage)
+		params.Source = append(params.Source, timing.Source)
+		params.Action = append(params.Action, timing.Action)
+		params.Resource = append(params.Resource, timing.Resource)
+	}
+	if len(params.Stage) == 0 {
+		return nil
+	}
+	_, err := db.InsertProvisionerJobTimings(ctx, params)
+	return err
+}
+
 func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.UUID, transition database.WorkspaceTransition, protoResource *sdkproto.Resource, snapshot *telemetry.Snapshot) error {
 	resource, err := db.InsertWorkspaceResource(ctx, database.InsertWorkspaceResourceParams{
 		ID:         uuid.New(),
@@ -1297,6 +1389,32 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			}
 		}
 
+		for _, script := range prAgent.Scripts {
+			if script.Cron != "" {
+				_, err := cron.ParseStandard(script.Cron)
+				if err != nil {
+					return xerrors.Errorf("parse cron schedule of script %q: %w", script.DisplayName, err)
+				}
+			}
+			_, err := db.InsertWorkspaceAgentScript(ctx, database.InsertWorkspaceAgentScriptParams{
+				ID:               uuid.New(),
+				WorkspaceAgentID: agentID,
+				CreatedAt:        database.Now(),
+				DisplayName:      script.DisplayName,
+				Script:           script.Script,
+				Cron:             script.Cron,
+				RunOrder:         script.RunOrder,
+				StartBlocksLogin: script.StartBlocksLogin,
+				RunOnStart:       script.RunOnStart,
+				RunOnStop:        script.RunOnStop,
+				TimeoutSeconds:   script.TimeoutSeconds,
+				LogPath:          script.LogPath,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent script %q: %w", script.DisplayName, err)
+			}
+		}
+
 		for _, app := range prAgent.Apps {
 			slug := app.Slug
 			if slug == "" {
@@ -1314,8 +1432,33 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			if app.Healthcheck == nil {
 				app.Healthcheck = &sdkproto.Healthcheck{}
 			}
-			if app.Healthcheck.Url != "" {
-				health = database.WorkspaceAppHealthInitializing
+			healthcheckType := database.WorkspaceAppHealthcheckTypeHTTP
+			switch app.Healthcheck.Type {
+			case sdkproto.HealthcheckType_HTTP:
+				if app.Healthcheck.Url != "" {
+					health = database.WorkspaceAppHealthInitializing
+				}
+			case sdkproto.HealthcheckType_TCP:
+				healthcheckType = database.WorkspaceAppHealthcheckTypeTCP
+				if app.Healthcheck.Address != "" {
+					health = database.WorkspaceAppHealthInitializing
+				}
+			case sdkproto.HealthcheckType_COMMAND:
+				healthcheckType = database.WorkspaceAppHealthcheckTypeCommand
+				if app.Healthcheck.Command != "" {
+					health = database.WorkspaceAppHealthInitializing
+				}
+			default:
+				return xerrors.Errorf("app %q has unknown healthcheck type %q", slug, app.Healthcheck.Type)
+			}
+			if app.Healthcheck.OutputRegex != "" {
+				if _, err := regexp.Compile(app.Healthcheck.OutputRegex); err != nil {
+					return xerrors.Errorf("app %q has invalid healthcheck output regex: %w", slug, err)
+				}
+			}
+			expectedExitCodes := app.Healthcheck.ExpectedExitCodes
+			if expectedExitCodes == nil {
+				expectedExitCodes = []int32{}
 			}
 
 			sharingLevel := database.AppSharingLevelOwner
@@ -1341,13 +1484,18 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 					String: app.Url,
 					Valid:  app.Url != "",
 				},
-				External:             app.External,
-				Subdomain:            app.Subdomain,
-				SharingLevel:         sharingLevel,
-				HealthcheckUrl:       app.Healthcheck.Url,
-				HealthcheckInterval:  app.Healthcheck.Interval,
-				HealthcheckThreshold: app.Healthcheck.Threshold,
-				Health:               health,
+				External:                     app.External,
+				Subdomain:                    app.Subdomain,
+				SharingLevel:                 sharingLevel,
+				HealthcheckUrl:               app.Healthcheck.Url,
+				HealthcheckInterval:          app.Healthcheck.Interval,
+				HealthcheckThreshold:         app.Healthcheck.Threshold,
+				HealthcheckType:              healthcheckType,
+				HealthcheckAddress:           app.Healthcheck.Address,
+				HealthcheckCommand:           app.Healthcheck.Command,
+				HealthcheckExpectedExitCodes: expectedExitCodes,
+				HealthcheckOutputRegex:       app.Healthcheck.OutputRegex,
+				Health:                       health,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert app: %w", err)
@@ -1549,6 +1697,40 @@ func convertWorkspaceTransition(transition database.WorkspaceTransition) (sdkpro
 	}
 }
 
+// publishWorkspaceBuildWebhook enqueues a workspace build webhook event.
+// Failures are logged and never fail the job.
+func (server *Server) publishWorkspaceBuildWebhook(ctx context.Context, event database.WebhookEvent, build database.WorkspaceBuild, workspace database.Workspace, buildError string) {
+	logger := server.Logger.With(slog.F("workspace_build_id", build.ID), slog.F("event", event))
+	owner, err := server.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		logger.Warn(ctx, "webhook - get workspace owner", slog.Error(err))
+		return
+	}
+	template, err := server.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		logger.Warn(ctx, "webhook - get template", slog.Error(err))
+		return
+	}
+
+	err = webhooks.Enqueue(ctx, server.Database, server.Pubsub, event, codersdk.WebhookDataWorkspaceBuild{
+		WorkspaceID:        workspace.ID,
+		WorkspaceName:      workspace.Name,
+		WorkspaceOwnerID:   owner.ID,
+		WorkspaceOwnerName: owner.Username,
+		TemplateID:         template.ID,
+		TemplateName:       template.Name,
+		TemplateVersionID:  build.TemplateVersionID,
+		BuildID:            build.ID,
+		BuildNumber:        build.BuildNumber,
+		Transition:         codersdk.WorkspaceTransition(build.Transition),
+		Reason:             codersdk.BuildReason(build.Reason),
+		Error:              buildError,
+	})
+	if err != nil {
+		logger.Warn(ctx, "webhook - enqueue", slog.Error(err))
+	}
+}
+
 func auditActionFromTransition(transition database.WorkspaceTransition) database.AuditAction {
 	switch transition {
 	case database.WorkspaceTransitionStart:
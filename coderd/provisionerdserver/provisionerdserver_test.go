@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/coderd/audit"
@@ -42,6 +43,13 @@ func testTemplateScheduleStore() *atomic.Pointer[schedule.TemplateScheduleStore]
 	return ptr
 }
 
+func testUserQuietHoursScheduleStore() *atomic.Pointer[schedule.UserQuietHoursScheduleStore] {
+	ptr := &atomic.Pointer[schedule.UserQuietHoursScheduleStore]{}
+	store := schedule.NewAGPLUserQuietHoursScheduleStore()
+	ptr.Store(&store)
+	return ptr
+}
+
 func TestAcquireJob(t *testing.T) {
 	t.Parallel()
 	t.Run("Debounce", func(t *testing.T) {
@@ -49,16 +57,17 @@ func TestAcquireJob(t *testing.T) {
 		db := dbfake.New()
 		pubsub := database.NewPubsubInMemory()
 		srv := &provisionerdserver.Server{
-			ID:                    uuid.New(),
-			Logger:                slogtest.Make(t, nil),
-			AccessURL:             &url.URL{},
-			Provisioners:          []database.ProvisionerType{database.ProvisionerTypeEcho},
-			Database:              db,
-			Pubsub:                pubsub,
-			Telemetry:             telemetry.NewNoop(),
-			AcquireJobDebounce:    time.Hour,
-			Auditor:               mockAuditor(),
-			TemplateScheduleStore: testTemplateScheduleStore(),
+			ID:                          uuid.New(),
+			Logger:                      slogtest.Make(t, nil),
+			AccessURL:                   &url.URL{},
+			Provisioners:                []database.ProvisionerType{database.ProvisionerTypeEcho},
+			Database:                    db,
+			Pubsub:                      pubsub,
+			Telemetry:                   telemetry.NewNoop(),
+			AcquireJobDebounce:          time.Hour,
+			Auditor:                     mockAuditor(),
+			TemplateScheduleStore:       testTemplateScheduleStore(),
+			UserQuietHoursScheduleStore: testUserQuietHoursScheduleStore(),
 		}
 		job, err := srv.AcquireJob(context.Background(), nil)
 		require.NoError(t, err)
@@ -82,6 +91,48 @@ func TestAcquireJob(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, &proto.AcquiredJob{}, job)
 	})
+	t.Run("LastSeen", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		_, err := srv.Database.InsertProvisionerDaemon(context.Background(), database.InsertProvisionerDaemonParams{
+			ID: srv.ID,
+		})
+		require.NoError(t, err)
+		_, err = srv.AcquireJob(context.Background(), nil)
+		require.NoError(t, err)
+		daemons, err := srv.Database.GetProvisionerDaemons(context.Background())
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.True(t, daemons[0].LastSeenAt.Valid)
+	})
+	t.Run("ProvisionerKeyDeleted", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		key, _ := dbgen.ProvisionerKey(t, srv.Database, database.ProvisionerKey{})
+		srv.ProvisionerKeyID = uuid.NullUUID{UUID: key.ID, Valid: true}
+		err := srv.Database.DeleteProvisionerKey(context.Background(), key.ID)
+		require.NoError(t, err)
+		_, err = srv.AcquireJob(context.Background(), nil)
+		require.ErrorContains(t, err, "provisioner key of the daemon has been deleted")
+	})
+	t.Run("OtherOrganization", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		srv.OrganizationID = uuid.New()
+		_, err := srv.Database.InsertProvisionerJob(context.Background(), database.InsertProvisionerJobParams{
+			ID:             uuid.New(),
+			OrganizationID: uuid.New(),
+			InitiatorID:    uuid.New(),
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
+		})
+		require.NoError(t, err)
+		// The job of the other organization is left for other daemons.
+		job, err := srv.AcquireJob(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, &proto.AcquiredJob{}, job)
+	})
 	t.Run("InitiatorNotFound", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
@@ -669,7 +720,8 @@ func TestFailJob(t *testing.T) {
 		ignoreLogErrors := true
 		srv := setup(t, ignoreLogErrors)
 		workspace, err := srv.Database.InsertWorkspace(ctx, database.InsertWorkspaceParams{
-			ID: uuid.New(),
+			ID:               uuid.New(),
+			AutomaticUpdates: database.AutomaticUpdatesNever,
 		})
 		require.NoError(t, err)
 		build, err := srv.Database.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
@@ -1028,6 +1080,19 @@ func TestCompleteJob(t *testing.T) {
 								Name: "example",
 								Type: "aws_instance",
 							}},
+							Timings: []*sdkproto.Timing{{
+								Start:    timestamppb.New(time.Now().Add(-time.Minute)),
+								End:      timestamppb.New(time.Now()),
+								Stage:    "apply",
+								Source:   "aws",
+								Action:   "create",
+								Resource: "aws_instance.example",
+							}, {
+								// Timings of unknown stages are dropped.
+								Start: timestamppb.New(time.Now()),
+								End:   timestamppb.New(time.Now()),
+								Stage: "unknown",
+							}},
 						},
 					},
 				})
@@ -1040,6 +1105,12 @@ func TestCompleteJob(t *testing.T) {
 				require.NoError(t, err)
 				require.Equal(t, c.transition == database.WorkspaceTransitionDelete, workspace.Deleted)
 
+				timings, err := srv.Database.GetProvisionerJobTimingsByJobID(ctx, job.ID)
+				require.NoError(t, err)
+				require.Len(t, timings, 1)
+				require.Equal(t, database.ProvisionerJobTimingStageApply, timings[0].Stage)
+				require.Equal(t, "aws_instance.example", timings[0].Resource)
+
 				workspaceBuild, err := srv.Database.GetWorkspaceBuildByID(ctx, build.ID)
 				require.NoError(t, err)
 
@@ -1138,6 +1209,97 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		})
 		require.ErrorContains(t, err, "duplicate app slug")
 	})
+	t.Run("InvalidHealthcheckOutputRegex", func(t *testing.T) {
+		t.Parallel()
+		err := insert(dbfake.New(), uuid.New(), &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Apps: []*sdkproto.App{{
+					Slug: "db",
+					Healthcheck: &sdkproto.Healthcheck{
+						Type:        sdkproto.HealthcheckType_COMMAND,
+						Command:     "pg_isready",
+						OutputRegex: "accepting (",
+					},
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, "invalid healthcheck output regex")
+	})
+	t.Run("HealthcheckTypes", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Apps: []*sdkproto.App{{
+					Slug: "tcp",
+					Healthcheck: &sdkproto.Healthcheck{
+						Type:      sdkproto.HealthcheckType_TCP,
+						Address:   "localhost:5432",
+						Interval:  5,
+						Threshold: 6,
+					},
+				}, {
+					Slug: "command",
+					Healthcheck: &sdkproto.Healthcheck{
+						Type:              sdkproto.HealthcheckType_COMMAND,
+						Command:           "pg_isready",
+						ExpectedExitCodes: []int32{0, 1},
+						OutputRegex:       "accepting connections",
+						Interval:          5,
+						Threshold:         6,
+					},
+				}, {
+					Slug: "disabled",
+					Healthcheck: &sdkproto.Healthcheck{
+						Type: sdkproto.HealthcheckType_TCP,
+					},
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		apps, err := db.GetWorkspaceAppsByAgentID(ctx, agents[0].ID)
+		require.NoError(t, err)
+		require.Len(t, apps, 3)
+		bySlug := map[string]database.WorkspaceApp{}
+		for _, app := range apps {
+			bySlug[app.Slug] = app
+		}
+		require.Equal(t, database.WorkspaceAppHealthcheckTypeTCP, bySlug["tcp"].HealthcheckType)
+		require.Equal(t, "localhost:5432", bySlug["tcp"].HealthcheckAddress)
+		require.Equal(t, database.WorkspaceAppHealthInitializing, bySlug["tcp"].Health)
+		require.Equal(t, database.WorkspaceAppHealthcheckTypeCommand, bySlug["command"].HealthcheckType)
+		require.Equal(t, "pg_isready", bySlug["command"].HealthcheckCommand)
+		require.Equal(t, []int32{0, 1}, bySlug["command"].HealthcheckExpectedExitCodes)
+		require.Equal(t, "accepting connections", bySlug["command"].HealthcheckOutputRegex)
+		require.Equal(t, database.WorkspaceAppHealthInitializing, bySlug["command"].Health)
+		require.Equal(t, database.WorkspaceAppHealthDisabled, bySlug["disabled"].Health)
+	})
+	t.Run("InvalidScriptCron", func(t *testing.T) {
+		t.Parallel()
+		err := insert(dbfake.New(), uuid.New(), &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "refresh",
+					Cron:        "every hour",
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, "parse cron schedule of script \"refresh\"")
+	})
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
@@ -1161,6 +1323,15 @@ func TestInsertWorkspaceResource(t *testing.T) {
 					Slug: "a",
 				}},
 				ShutdownScript: "shutdown",
+				Scripts: []*sdkproto.Script{{
+					DisplayName:      "refresh credentials",
+					Script:           "refresh",
+					Cron:             "@hourly",
+					RunOrder:         1,
+					StartBlocksLogin: true,
+					RunOnStart:       true,
+					TimeoutSeconds:   60,
+				}},
 			}},
 		})
 		require.NoError(t, err)
@@ -1176,6 +1347,15 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.Equal(t, "linux", agent.OperatingSystem)
 		require.Equal(t, "value", agent.StartupScript.String)
 		require.Equal(t, "shutdown", agent.ShutdownScript.String)
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agent.ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 1)
+		require.Equal(t, "refresh credentials", scripts[0].DisplayName)
+		require.Equal(t, "@hourly", scripts[0].Cron)
+		require.EqualValues(t, 1, scripts[0].RunOrder)
+		require.True(t, scripts[0].StartBlocksLogin)
+		require.EqualValues(t, 60, scripts[0].TimeoutSeconds)
+		require.Equal(t, database.WorkspaceAgentScriptStatusPending, scripts[0].Status)
 		want, err := json.Marshal(map[string]string{
 			"something": "test",
 		})
@@ -1192,16 +1372,17 @@ func setup(t *testing.T, ignoreLogErrors bool) *provisionerdserver.Server {
 	pubsub := database.NewPubsubInMemory()
 
 	return &provisionerdserver.Server{
-		ID:                    uuid.New(),
-		Logger:                slogtest.Make(t, &slogtest.Options{IgnoreErrors: ignoreLogErrors}),
-		OIDCConfig:            &oauth2.Config{},
-		AccessURL:             &url.URL{},
-		Provisioners:          []database.ProvisionerType{database.ProvisionerTypeEcho},
-		Database:              db,
-		Pubsub:                pubsub,
-		Telemetry:             telemetry.NewNoop(),
-		Auditor:               mockAuditor(),
-		TemplateScheduleStore: testTemplateScheduleStore(),
+		ID:                          uuid.New(),
+		Logger:                      slogtest.Make(t, &slogtest.Options{IgnoreErrors: ignoreLogErrors}),
+		OIDCConfig:                  &oauth2.Config{},
+		AccessURL:                   &url.URL{},
+		Provisioners:                []database.ProvisionerType{database.ProvisionerTypeEcho},
+		Database:                    db,
+		Pubsub:                      pubsub,
+		Telemetry:                   telemetry.NewNoop(),
+		Auditor:                     mockAuditor(),
+		TemplateScheduleStore:       testTemplateScheduleStore(),
+		UserQuietHoursScheduleStore: testUserQuietHoursScheduleStore(),
 	}
 }
 
@@ -169,6 +169,23 @@ var (
 		Type: "debug_info",
 	}
 
+	// ResourceWebhook is an outbound webhook subscribed to deployment events.
+	// ResourceWebhook is site wide.
+	//	create/delete = add or remove webhooks
+	//	read = view webhooks and their delivery log
+	//	update = edit the url, events or enabled state of a webhook
+	ResourceWebhook = Object{
+		Type: "webhook",
+	}
+
+	// ResourceCustomRole is a user defined role stored in the database.
+	// Custom roles are either site wide, or scoped to an organization.
+	//	create/update = define new roles or edit the permissions of existing ones
+	//	read = view the permissions granted by custom roles
+	ResourceCustomRole = Object{
+		Type: "custom_role",
+	}
+
 	// ResourceSystem is a pseudo-resource only used for system-level actions.
 	ResourceSystem = Object{
 		Type: "system",
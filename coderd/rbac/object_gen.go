@@ -5,6 +5,7 @@ func AllResources() []Object {
 	return []Object{
 		ResourceAPIKey,
 		ResourceAuditLog,
+		ResourceCustomRole,
 		ResourceDebugInfo,
 		ResourceDeploymentStats,
 		ResourceDeploymentValues,
@@ -21,6 +22,7 @@ func AllResources() []Object {
 		ResourceTemplate,
 		ResourceUser,
 		ResourceUserData,
+		ResourceWebhook,
 		ResourceWildcard,
 		ResourceWorkspace,
 		ResourceWorkspaceApplicationConnect,
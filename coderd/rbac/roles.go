@@ -18,6 +18,12 @@ const (
 
 	orgAdmin  string = "organization-admin"
 	orgMember string = "organization-member"
+
+	// customSiteRole and customOrganizationRole are not real roles. They are
+	// used as keys in assignRoles to represent any user defined role that is
+	// not one of the built-in roles above.
+	customSiteRole         string = "custom-site-role"
+	customOrganizationRole string = "custom-organization-role"
 )
 
 func init() {
@@ -151,6 +157,9 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 					ResourceRoleAssignment.Type: {ActionRead},
 					// All users can see the provisioner daemons.
 					ResourceProvisionerDaemon.Type: {ActionRead},
+					// All users can see the workspace proxies to connect
+					// through them.
+					ResourceWorkspaceProxy.Type: {ActionRead},
 				}),
 				Org:  map[string][]Permission{},
 				User: allPermsExcept(),
@@ -253,6 +262,11 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 							ResourceType: ResourceGroup.Type,
 							Action:       ActionRead,
 						},
+						{
+							// Can read the custom roles of the organization.
+							ResourceType: ResourceCustomRole.Type,
+							Action:       ActionRead,
+						},
 					},
 				},
 				User: []Permission{},
@@ -281,6 +295,9 @@ var assignRoles = map[string]map[string]bool{
 		orgMember:     true,
 		templateAdmin: true,
 		userAdmin:     true,
+
+		customSiteRole:         true,
+		customOrganizationRole: true,
 	},
 	userAdmin: {
 		member:    true,
@@ -289,6 +306,8 @@ var assignRoles = map[string]map[string]bool{
 	orgAdmin: {
 		orgAdmin:  true,
 		orgMember: true,
+
+		customOrganizationRole: true,
 	},
 }
 
@@ -344,7 +363,7 @@ func (roles Roles) Expand() ([]Role, error) {
 func (roles Roles) Names() []string {
 	names := make([]string, 0, len(roles))
 	for _, r := range roles {
-		return append(names, r.Name)
+		names = append(names, r.Name)
 	}
 	return names
 }
@@ -360,6 +379,14 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 	if err != nil {
 		return false
 	}
+	if _, ok := builtInRoles[assigned]; !ok {
+		// Any role that is not built-in is a custom role. Whether the
+		// custom role actually exists is up to the caller to check.
+		assigned = customSiteRole
+		if assignedOrg != "" {
+			assigned = customOrganizationRole
+		}
+	}
 
 	for _, longRole := range roles {
 		role, orgID, err := roleSplit(longRole)
@@ -384,6 +411,14 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 	return false
 }
 
+// IsBuiltInRoleName returns true if the role name, without any organization
+// suffix, is used by one of the built-in roles. Custom roles cannot use these
+// names.
+func IsBuiltInRoleName(name string) bool {
+	_, ok := builtInRoles[name]
+	return ok
+}
+
 // RoleByName returns the permissions associated with a given role name.
 // This allows just the role names to be stored and expanded when required.
 //
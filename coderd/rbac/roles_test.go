@@ -242,6 +242,24 @@ func TestRolePermissions(t *testing.T) {
 				false: {},
 			},
 		},
+		{
+			Name:     "WorkspaceProxy",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+			Resource: rbac.ResourceWorkspaceProxy,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {orgAdmin, orgMemberMe, otherOrgAdmin, otherOrgMember, memberMe, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "ReadWorkspaceProxy",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceWorkspaceProxy,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, orgMemberMe, otherOrgAdmin, otherOrgMember, memberMe, templateAdmin, userAdmin},
+				false: {},
+			},
+		},
 		{
 			Name:     "OrgRoleAssignment",
 			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
@@ -318,6 +336,33 @@ func TestRolePermissions(t *testing.T) {
 				false: {memberMe, otherOrgAdmin, otherOrgMember, templateAdmin},
 			},
 		},
+		{
+			Name:     "SiteCustomRole",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate},
+			Resource: rbac.ResourceCustomRole,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "ReadOrgCustomRole",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceCustomRole.InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, orgMemberMe},
+				false: {memberMe, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "CreateUpdateOrgCustomRole",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate},
+			Resource: rbac.ResourceCustomRole.InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin},
+				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
 	}
 
 	for _, c := range testCases {
@@ -408,6 +453,37 @@ func TestIsOrgRole(t *testing.T) {
 	}
 }
 
+func TestCanAssignCustomRole(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	otherOrg := uuid.New()
+
+	owner := rbac.RoleNames{rbac.RoleMember(), rbac.RoleOwner()}
+	userAdmin := rbac.RoleNames{rbac.RoleMember(), rbac.RoleUserAdmin()}
+	orgAdmin := rbac.RoleNames{rbac.RoleMember(), rbac.RoleOrgMember(orgID), rbac.RoleOrgAdmin(orgID)}
+
+	siteRole := "template-editor"
+	orgRole := "template-editor:" + orgID.String()
+	otherOrgRole := "template-editor:" + otherOrg.String()
+
+	require.True(t, rbac.CanAssignRole(owner, siteRole))
+	require.True(t, rbac.CanAssignRole(owner, orgRole))
+
+	require.False(t, rbac.CanAssignRole(userAdmin, siteRole))
+	require.False(t, rbac.CanAssignRole(userAdmin, orgRole))
+
+	require.False(t, rbac.CanAssignRole(orgAdmin, siteRole))
+	require.True(t, rbac.CanAssignRole(orgAdmin, orgRole))
+	require.False(t, rbac.CanAssignRole(orgAdmin, otherOrgRole))
+
+	// Expanded roles, as used for subjects with custom roles, must also be
+	// able to assign roles.
+	expanded, err := owner.Expand()
+	require.NoError(t, err)
+	require.True(t, rbac.CanAssignRole(rbac.Roles(expanded), siteRole))
+}
+
 func TestListRoles(t *testing.T) {
 	t.Parallel()
 
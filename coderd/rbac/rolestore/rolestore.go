@@ -0,0 +1,80 @@
+// Package rolestore expands role names into their permissions, including the
+// user defined roles stored in the database.
+package rolestore
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/rbac"
+)
+
+// Expand converts the given role names into their rbac.Role representation.
+// Built-in roles are resolved in memory, every other role name is looked up
+// in the database as a custom role. Names that do not match any role are
+// ignored, as the custom role may have been removed from the organization
+// after it was assigned.
+func Expand(ctx context.Context, db database.Store, names []string) (rbac.Roles, error) {
+	roles := make(rbac.Roles, 0, len(names))
+	var lookup []string
+	for _, name := range names {
+		role, err := rbac.RoleByName(name)
+		if err == nil {
+			roles = append(roles, role)
+			continue
+		}
+		lookup = append(lookup, name)
+	}
+
+	if len(lookup) == 0 {
+		return roles, nil
+	}
+
+	//nolint:gocritic // Expanding roles builds the caller's authorization
+	// context, so the caller cannot be used to read the roles.
+	customRoles, err := db.CustomRoles(dbauthz.AsSystemRestricted(ctx), database.CustomRolesParams{
+		LookupRoles: lookup,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("fetch custom roles: %w", err)
+	}
+
+	for _, customRole := range customRoles {
+		roles = append(roles, ConvertDBRole(customRole))
+	}
+	return roles, nil
+}
+
+// ConvertDBRole converts a custom role from the database into the rbac.Role
+// passed to the authorizer.
+func ConvertDBRole(dbRole database.CustomRole) rbac.Role {
+	role := rbac.Role{
+		Name:        dbRole.RoleName(),
+		DisplayName: dbRole.DisplayName,
+		Site:        []rbac.Permission(dbRole.SitePermissions),
+		Org:         map[string][]rbac.Permission{},
+		User:        []rbac.Permission(dbRole.UserPermissions),
+	}
+	if role.DisplayName == "" {
+		// Roles without a display name are hidden from the UI, but custom
+		// roles should always be shown.
+		role.DisplayName = dbRole.Name
+	}
+	if role.Site == nil {
+		role.Site = []rbac.Permission{}
+	}
+	if role.User == nil {
+		role.User = []rbac.Permission{}
+	}
+	if dbRole.OrganizationID.Valid {
+		orgPerms := []rbac.Permission(dbRole.OrgPermissions)
+		if orgPerms == nil {
+			orgPerms = []rbac.Permission{}
+		}
+		role.Org[dbRole.OrganizationID.UUID.String()] = orgPerms
+	}
+	return role
+}
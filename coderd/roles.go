@@ -1,13 +1,24 @@
 package coderd
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/codersdk"
 
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 )
 
 // assignableSiteRoles returns all site wide roles that can be assigned.
@@ -28,6 +39,19 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.SiteRoles()
+	customRoles, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		ExcludeOrgRoles: true,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, customRole := range customRoles {
+		roles = append(roles, rolestore.ConvertDBRole(customRole))
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
@@ -52,6 +76,19 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.OrganizationRoles(organization.ID)
+	customRoles, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		OrganizationID: organization.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, customRole := range customRoles {
+		roles = append(roles, rolestore.ConvertDBRole(customRole))
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
@@ -62,6 +99,40 @@ func convertRole(role rbac.Role) codersdk.Role {
 	}
 }
 
+// convertRoleName converts a role name assigned to a user into a
+// codersdk.Role. Custom roles are not looked up, so they are returned with
+// their name as the display name.
+func convertRoleName(roleName string) codersdk.Role {
+	rbacRole, err := rbac.RoleByName(roleName)
+	if err != nil {
+		return codersdk.Role{
+			Name:        roleName,
+			DisplayName: roleName,
+		}
+	}
+	return convertRole(rbacRole)
+}
+
+// validateRoleNames ensures every role name is either a built-in role or an
+// existing custom role. Custom roles are looked up regardless of case, so
+// names are compared in lower case.
+func (api *API) validateRoleNames(ctx context.Context, roleNames []string) error {
+	roles, err := rolestore.Expand(ctx, api.Database, roleNames)
+	if err != nil {
+		return xerrors.Errorf("expand roles: %w", err)
+	}
+	found := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		found[strings.ToLower(role.Name)] = true
+	}
+	for _, roleName := range roleNames {
+		if !found[strings.ToLower(roleName)] {
+			return xerrors.Errorf("%q is not a supported role", roleName)
+		}
+	}
+	return nil
+}
+
 func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []codersdk.AssignableRoles {
 	assignable := make([]codersdk.AssignableRoles, 0)
 	for _, role := range roles {
@@ -78,3 +149,338 @@ func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []coder
 	}
 	return assignable
 }
+
+// @Summary Get site custom roles
+// @ID get-site-custom-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Success 200 {array} codersdk.CustomRole
+// @Router /roles [get]
+func (api *API) customSiteRoles(rw http.ResponseWriter, r *http.Request) {
+	api.listCustomRoles(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Get organization custom roles
+// @ID get-organization-custom-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.CustomRole
+// @Router /organizations/{organization}/roles [get]
+func (api *API) customOrganizationRoles(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.listCustomRoles(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+func (api *API) listCustomRoles(rw http.ResponseWriter, r *http.Request, orgID uuid.NullUUID) {
+	ctx := r.Context()
+
+	roles, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		ExcludeOrgRoles: !orgID.Valid,
+		OrganizationID:  orgID.UUID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.CustomRole, 0, len(roles))
+	for _, role := range roles {
+		converted = append(converted, convertCustomRole(role))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Create site custom role
+// @ID create-site-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /roles [post]
+func (api *API) postCustomSiteRole(rw http.ResponseWriter, r *http.Request) {
+	api.createCustomRole(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Create organization custom role
+// @ID create-organization-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /organizations/{organization}/roles [post]
+func (api *API) postCustomOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.createCustomRole(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+func (api *API) createCustomRole(rw http.ResponseWriter, r *http.Request, orgID uuid.NullUUID) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if rbac.IsBuiltInRoleName(req.Name) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Role name %q is reserved for a built-in role.", req.Name),
+		})
+		return
+	}
+	site, org, user, err := convertCustomRolePermissions(orgID, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid role permissions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	role, err := api.Database.InsertCustomRole(ctx, database.InsertCustomRoleParams{
+		ID:              uuid.New(),
+		Name:            req.Name,
+		DisplayName:     req.DisplayName,
+		OrganizationID:  orgID,
+		SitePermissions: site,
+		OrgPermissions:  org,
+		UserPermissions: user,
+		CreatedAt:       database.Now(),
+		UpdatedAt:       database.Now(),
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Role with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Not authorized to create this role.",
+			Detail:  "A role can only grant permissions you already have.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = role
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertCustomRole(role))
+}
+
+// @Summary Update site custom role
+// @ID update-site-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role name"
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [patch]
+func (api *API) patchCustomSiteRole(rw http.ResponseWriter, r *http.Request) {
+	api.updateCustomRole(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Update organization custom role
+// @ID update-organization-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param role path string true "Role name"
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /organizations/{organization}/roles/{role} [patch]
+func (api *API) patchCustomOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.updateCustomRole(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+func (api *API) updateCustomRole(rw http.ResponseWriter, r *http.Request, orgID uuid.NullUUID) {
+	var (
+		ctx               = r.Context()
+		roleName          = chi.URLParam(r, "role")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	lookup := roleName
+	if orgID.Valid {
+		lookup = roleName + ":" + orgID.UUID.String()
+	}
+	existing, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		LookupRoles:     []string{lookup},
+		ExcludeOrgRoles: !orgID.Valid,
+		OrganizationID:  orgID.UUID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(existing) == 0 {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = existing[0]
+
+	site, org, user, err := convertCustomRolePermissions(orgID, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid role permissions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	role, err := api.Database.UpdateCustomRole(ctx, database.UpdateCustomRoleParams{
+		Name:            existing[0].Name,
+		OrganizationID:  orgID,
+		DisplayName:     req.DisplayName,
+		SitePermissions: site,
+		OrgPermissions:  org,
+		UserPermissions: user,
+		UpdatedAt:       database.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Not authorized to update this role.",
+			Detail:  "A role can only grant permissions you already have.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = role
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertCustomRole(role))
+}
+
+// convertCustomRolePermissions validates the permissions of a custom role
+// and converts them to their database representation. Site wide roles cannot
+// have organization permissions, and organization roles can only have
+// organization permissions.
+func convertCustomRolePermissions(orgID uuid.NullUUID, site, org, user []codersdk.Permission) (siteP, orgP, userP database.CustomRolePermissions, err error) {
+	if orgID.Valid && (len(site) > 0 || len(user) > 0) {
+		return nil, nil, nil, xerrors.New("organization roles can only have organization permissions")
+	}
+	if !orgID.Valid && len(org) > 0 {
+		return nil, nil, nil, xerrors.New("site wide roles cannot have organization permissions")
+	}
+
+	siteP, err = convertPermissions(site)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("site permissions: %w", err)
+	}
+	orgP, err = convertPermissions(org)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("organization permissions: %w", err)
+	}
+	userP, err = convertPermissions(user)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("user permissions: %w", err)
+	}
+	return siteP, orgP, userP, nil
+}
+
+func convertPermissions(perms []codersdk.Permission) (database.CustomRolePermissions, error) {
+	validResources := make(map[string]bool)
+	for _, resource := range rbac.AllResources() {
+		if resource.Type == rbac.ResourceWildcard.Type {
+			continue
+		}
+		validResources[resource.Type] = true
+	}
+
+	converted := make(database.CustomRolePermissions, 0, len(perms))
+	for _, perm := range perms {
+		if !validResources[string(perm.ResourceType)] {
+			return nil, xerrors.Errorf("unknown resource type %q", perm.ResourceType)
+		}
+		action := rbac.Action(perm.Action)
+		switch action {
+		case rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete, rbac.WildcardSymbol:
+		default:
+			return nil, xerrors.Errorf("unknown action %q", perm.Action)
+		}
+		converted = append(converted, rbac.Permission{
+			Negate:       perm.Negate,
+			ResourceType: string(perm.ResourceType),
+			Action:       action,
+		})
+	}
+	return converted, nil
+}
+
+func convertCustomRole(role database.CustomRole) codersdk.CustomRole {
+	convertPerms := func(perms database.CustomRolePermissions) []codersdk.Permission {
+		converted := make([]codersdk.Permission, 0, len(perms))
+		for _, perm := range perms {
+			converted = append(converted, codersdk.Permission{
+				Negate:       perm.Negate,
+				ResourceType: codersdk.RBACResource(perm.ResourceType),
+				Action:       string(perm.Action),
+			})
+		}
+		return converted
+	}
+
+	converted := codersdk.CustomRole{
+		Name:                    role.Name,
+		DisplayName:             role.DisplayName,
+		SitePermissions:         convertPerms(role.SitePermissions),
+		OrganizationPermissions: convertPerms(role.OrgPermissions),
+		UserPermissions:         convertPerms(role.UserPermissions),
+		CreatedAt:               role.CreatedAt,
+		UpdatedAt:               role.UpdatedAt,
+	}
+	if role.OrganizationID.Valid {
+		converted.OrganizationID = &role.OrganizationID.UUID
+	}
+	return converted
+}
@@ -143,6 +143,208 @@ func TestListRoles(t *testing.T) {
 	}
 }
 
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Site", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        "log-reader",
+			DisplayName: "Log Reader",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceAuditLog, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "log-reader", role.Name)
+		require.Nil(t, role.OrganizationID)
+
+		roles, err := client.CustomRoles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.CustomRole{role}, roles)
+
+		// The role can be assigned like any built-in role.
+		assignable, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, assignable, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: "log-reader", DisplayName: "Log Reader"},
+			Assignable: true,
+		})
+
+		check := codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"readAuditLogs": {
+					Object: codersdk.AuthorizationObject{ResourceType: codersdk.ResourceAuditLog},
+					Action: "read",
+				},
+			},
+		}
+		resp, err := member.AuthCheck(ctx, check)
+		require.NoError(t, err)
+		require.False(t, resp["readAuditLogs"])
+
+		user, err := client.UpdateUserRoles(ctx, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{"log-reader"},
+		})
+		require.NoError(t, err)
+		require.Contains(t, user.Roles, codersdk.Role{Name: "log-reader", DisplayName: "log-reader"})
+
+		resp, err = member.AuthCheck(ctx, check)
+		require.NoError(t, err)
+		require.True(t, resp["readAuditLogs"])
+
+		// Custom role names are matched regardless of case.
+		_, err = client.UpdateUserRoles(ctx, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{"Log-Reader"},
+		})
+		require.NoError(t, err)
+		resp, err = member.AuthCheck(ctx, check)
+		require.NoError(t, err)
+		require.True(t, resp["readAuditLogs"])
+
+		// Revoke the permission by editing the role.
+		role, err = client.UpdateCustomRole(ctx, "log-reader", codersdk.UpdateCustomRoleRequest{
+			DisplayName: "Log Reader",
+		})
+		require.NoError(t, err)
+		require.Empty(t, role.SitePermissions)
+
+		resp, err = member.AuthCheck(ctx, check)
+		require.NoError(t, err)
+		require.False(t, resp["readAuditLogs"])
+	})
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID, rbac.RoleOrgAdmin(admin.OrganizationID))
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := orgAdmin.CreateOrganizationCustomRole(ctx, admin.OrganizationID, codersdk.CreateCustomRoleRequest{
+			Name: "workspace-viewer",
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, role.OrganizationID)
+		require.Equal(t, admin.OrganizationID, *role.OrganizationID)
+
+		// Members can see the roles of their organization.
+		roles, err := member.OrganizationCustomRoles(ctx, admin.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.CustomRole{role}, roles)
+
+		roleName := "workspace-viewer:" + admin.OrganizationID.String()
+		mem, err := orgAdmin.UpdateOrganizationMemberRoles(ctx, admin.OrganizationID, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{roleName},
+		})
+		require.NoError(t, err)
+		require.Contains(t, mem.Roles, codersdk.Role{Name: roleName, DisplayName: roleName})
+
+		resp, err := member.AuthCheck(ctx, codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"readOrgWorkspaces": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType:   codersdk.ResourceWorkspace,
+						OrganizationID: admin.OrganizationID.String(),
+					},
+					Action: "read",
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, resp["readOrgWorkspaces"])
+	})
+
+	t.Run("Escalation", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID, rbac.RoleOrgAdmin(admin.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Organization admins cannot exec into workspaces, so they cannot
+		// grant it either.
+		_, err := orgAdmin.CreateOrganizationCustomRole(ctx, admin.OrganizationID, codersdk.CreateCustomRoleRequest{
+			Name: "exec",
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspaceExecution, Action: "create"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Nor can they create site wide roles.
+		_, err = orgAdmin.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "nothing",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		_, memberUser := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for name, req := range map[string]codersdk.CreateCustomRoleRequest{
+			"BuiltInName": {Name: "owner"},
+			"InvalidName": {Name: "not:valid"},
+			"UnknownResource": {Name: "unknown", SitePermissions: []codersdk.Permission{
+				{ResourceType: "unknown", Action: "read"},
+			}},
+			"UnknownAction": {Name: "unknown", SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "execute"},
+			}},
+			"OrgPermissionsOnSiteRole": {Name: "org", OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+			}},
+		} {
+			_, err := client.CreateCustomRole(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), name)
+		}
+
+		_, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "dupe"})
+		require.NoError(t, err)
+		_, err = client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "dupe"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		_, err = client.UpdateCustomRole(ctx, "missing", codersdk.UpdateCustomRoleRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Roles that do not exist cannot be assigned.
+		_, err = client.UpdateUserRoles(ctx, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{"missing"},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 func convertRole(roleName string) codersdk.Role {
 	role, _ := rbac.RoleByName(roleName)
 	return codersdk.Role{
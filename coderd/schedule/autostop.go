@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+const (
+	// RestartRequirementBuffer is the minimum amount of time between a build
+	// completing and the restart requirement taking effect. If the owner's next
+	// quiet hours window starts sooner than this, the next applicable window is
+	// used instead so workspaces aren't stopped moments after being started.
+	RestartRequirementBuffer = time.Hour
+)
+
+// restartRequirementEpoch is the date that restart requirement weeks are
+// counted from. It is the first Monday of 2023, which ensures that workspaces
+// with the same requirement restart on the same n-week cycle everywhere.
+var restartRequirementEpoch = time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+type CalculateAutostopParams struct {
+	Database                    database.Store
+	TemplateScheduleStore       TemplateScheduleStore
+	UserQuietHoursScheduleStore UserQuietHoursScheduleStore
+
+	Now       time.Time
+	Workspace database.Workspace
+}
+
+type AutostopTime struct {
+	// Deadline is the time when the workspace will be stopped. The value can
+	// be bumped by user activity or manually by the user via the UI.
+	Deadline time.Time
+	// MaxDeadline is the maximum value for deadline.
+	MaxDeadline time.Time
+}
+
+// CalculateAutostop calculates the deadline and max deadline for a workspace
+// build that completes at params.Now.
+//
+// The deadline is derived from the workspace TTL (or the template default TTL
+// if users may not set their own). The max deadline is the earliest of the
+// template max TTL and the next time the template's restart requirement
+// applies, which is always at the start of the owner's quiet hours. The
+// deadline is never later than the max deadline.
+func CalculateAutostop(ctx context.Context, params CalculateAutostopParams) (AutostopTime, error) {
+	var (
+		db        = params.Database
+		workspace = params.Workspace
+		now       = params.Now
+
+		autostop AutostopTime
+	)
+
+	if workspace.Ttl.Valid {
+		autostop.Deadline = now.Add(time.Duration(workspace.Ttl.Int64))
+	}
+
+	templateSchedule, err := params.TemplateScheduleStore.GetTemplateScheduleOptions(ctx, db, workspace.TemplateID)
+	if err != nil {
+		return autostop, xerrors.Errorf("get template schedule options: %w", err)
+	}
+	if !templateSchedule.UserAutostopEnabled {
+		// The user is not permitted to set their own TTL, so use the template
+		// default.
+		autostop.Deadline = time.Time{}
+		if templateSchedule.DefaultTTL > 0 {
+			autostop.Deadline = now.Add(templateSchedule.DefaultTTL)
+		}
+	}
+	if templateSchedule.MaxTTL > 0 {
+		autostop.MaxDeadline = now.Add(templateSchedule.MaxTTL)
+	}
+
+	if templateSchedule.RestartRequirement.Enabled() {
+		userQuietHoursSchedule, err := params.UserQuietHoursScheduleStore.Get(ctx, db, workspace.OwnerID)
+		if err != nil {
+			return autostop, xerrors.Errorf("get user quiet hours schedule options: %w", err)
+		}
+
+		// A nil schedule means quiet hours are disabled, in which case the
+		// restart requirement can't be enforced.
+		if userQuietHoursSchedule.Schedule != nil {
+			restartAt, err := nextRestartRequirement(now, userQuietHoursSchedule.Schedule, templateSchedule.RestartRequirement)
+			if err != nil {
+				return autostop, xerrors.Errorf("calculate next restart requirement: %w", err)
+			}
+			if autostop.MaxDeadline.IsZero() || restartAt.Before(autostop.MaxDeadline) {
+				autostop.MaxDeadline = restartAt
+			}
+		}
+	}
+
+	if !autostop.MaxDeadline.IsZero() && (autostop.Deadline.IsZero() || autostop.MaxDeadline.Before(autostop.Deadline)) {
+		// If the workspace doesn't have a deadline or the max deadline is
+		// sooner than the workspace deadline, use the max deadline as the
+		// actual deadline.
+		autostop.Deadline = autostop.MaxDeadline
+	}
+
+	return autostop, nil
+}
+
+// nextRestartRequirement returns the start of the first quiet hours window
+// after now (plus RestartRequirementBuffer) that falls on one of the required
+// days of the week, in a week that matches the required week interval.
+func nextRestartRequirement(now time.Time, quietHours *Schedule, requirement TemplateRestartRequirement) (time.Time, error) {
+	var (
+		loc   = quietHours.Location()
+		days  = requirement.DaysMap()
+		weeks = requirement.Weeks
+	)
+	if weeks < 1 {
+		weeks = 1
+	}
+
+	// Quiet hours schedules are daily, so each iteration advances by a day.
+	// Every applicable day is guaranteed to be found within weeks*7 windows,
+	// plus one in case the first window is skipped by the buffer.
+	t := now.Add(RestartRequirementBuffer)
+	for i := int64(0); i <= weeks*7; i++ {
+		t = quietHours.Next(t)
+		local := t.In(loc)
+		if !days[local.Weekday()] {
+			continue
+		}
+		if weeksSinceEpoch(local)%weeks != 0 {
+			continue
+		}
+		return t, nil
+	}
+
+	return time.Time{}, xerrors.Errorf("no quiet hours window matches the restart requirement within %d weeks", weeks)
+}
+
+// weeksSinceEpoch returns the number of whole weeks between the restart
+// requirement epoch and the calendar date of t (in t's location). Weeks start
+// on Monday.
+func weeksSinceEpoch(t time.Time) int64 {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	days := int64(date.Sub(restartRequirementEpoch) / (24 * time.Hour))
+	week := days / 7
+	if days < 0 && days%7 != 0 {
+		// Round towards negative infinity for dates before the epoch.
+		week--
+	}
+	return week
+}
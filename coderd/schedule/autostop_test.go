@@ -0,0 +1,185 @@
+package schedule_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/schedule"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCalculateAutoStop(t *testing.T) {
+	t.Parallel()
+
+	// Monday, the second week after the restart requirement epoch.
+	now := time.Date(2023, time.January, 9, 10, 0, 0, 0, time.UTC)
+	utcQuietHours := mustDaily(t, "CRON_TZ=UTC 0 0 * * *")
+	sydneyQuietHours := mustDaily(t, "CRON_TZ=Australia/Sydney 0 0 * * *")
+
+	const (
+		everyDay uint8 = 0b01111111
+		saturday uint8 = 0b00100000
+	)
+
+	cases := []struct {
+		name               string
+		now                time.Time
+		workspaceTTL       time.Duration
+		templateMaxTTL     time.Duration
+		restartRequirement schedule.TemplateRestartRequirement
+		quietHours         *schedule.Schedule
+
+		expectedDeadline    time.Time
+		expectedMaxDeadline time.Time
+	}{
+		{
+			name:                "WorkspaceTTL",
+			now:                 now,
+			workspaceTTL:        8 * time.Hour,
+			expectedDeadline:    now.Add(8 * time.Hour),
+			expectedMaxDeadline: time.Time{},
+		},
+		{
+			name:                "TemplateMaxTTL",
+			now:                 now,
+			workspaceTTL:        8 * time.Hour,
+			templateMaxTTL:      2 * time.Hour,
+			expectedDeadline:    now.Add(2 * time.Hour),
+			expectedMaxDeadline: now.Add(2 * time.Hour),
+		},
+		{
+			name:                "RestartRequirementDaily",
+			now:                 now,
+			workspaceTTL:        24 * time.Hour,
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: everyDay},
+			quietHours:          utcQuietHours,
+			expectedDeadline:    time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC),
+			expectedMaxDeadline: time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                "RestartRequirementKeepsEarlierDeadline",
+			now:                 now,
+			workspaceTTL:        time.Hour,
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: everyDay},
+			quietHours:          utcQuietHours,
+			expectedDeadline:    now.Add(time.Hour),
+			expectedMaxDeadline: time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                "RestartRequirementBuffer",
+			now:                 time.Date(2023, time.January, 9, 23, 30, 0, 0, time.UTC),
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: everyDay},
+			quietHours:          utcQuietHours,
+			expectedDeadline:    time.Date(2023, time.January, 11, 0, 0, 0, 0, time.UTC),
+			expectedMaxDeadline: time.Date(2023, time.January, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                "RestartRequirementSaturday",
+			now:                 now,
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: saturday},
+			quietHours:          utcQuietHours,
+			expectedDeadline:    time.Date(2023, time.January, 14, 0, 0, 0, 0, time.UTC),
+			expectedMaxDeadline: time.Date(2023, time.January, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "RestartRequirementEverySecondSaturday",
+			now:  now,
+			restartRequirement: schedule.TemplateRestartRequirement{
+				DaysOfWeek: saturday,
+				Weeks:      2,
+			},
+			quietHours: utcQuietHours,
+			// January 14th is in an odd week since the epoch, so it's skipped.
+			expectedDeadline:    time.Date(2023, time.January, 21, 0, 0, 0, 0, time.UTC),
+			expectedMaxDeadline: time.Date(2023, time.January, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                "RestartRequirementTimezone",
+			now:                 now,
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: saturday},
+			quietHours:          sydneyQuietHours,
+			expectedDeadline:    time.Date(2023, time.January, 13, 13, 0, 0, 0, time.UTC),
+			expectedMaxDeadline: time.Date(2023, time.January, 13, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                "RestartRequirementBeforeMaxTTL",
+			now:                 now,
+			templateMaxTTL:      7 * 24 * time.Hour,
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: everyDay},
+			quietHours:          utcQuietHours,
+			expectedDeadline:    time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC),
+			expectedMaxDeadline: time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                "RestartRequirementNoQuietHours",
+			now:                 now,
+			workspaceTTL:        8 * time.Hour,
+			restartRequirement:  schedule.TemplateRestartRequirement{DaysOfWeek: everyDay},
+			quietHours:          nil,
+			expectedDeadline:    now.Add(8 * time.Hour),
+			expectedMaxDeadline: time.Time{},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+
+			templateScheduleStore := schedule.MockTemplateScheduleStore{
+				GetFn: func(_ context.Context, _ database.Store, _ uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					return schedule.TemplateScheduleOptions{
+						UserAutostartEnabled: true,
+						UserAutostopEnabled:  true,
+						MaxTTL:               c.templateMaxTTL,
+						RestartRequirement:   c.restartRequirement,
+					}, nil
+				},
+			}
+			userQuietHoursScheduleStore := schedule.MockUserQuietHoursScheduleStore{
+				GetFn: func(_ context.Context, _ database.Store, _ uuid.UUID) (schedule.UserQuietHoursScheduleOptions, error) {
+					return schedule.UserQuietHoursScheduleOptions{
+						Schedule: c.quietHours,
+						UserSet:  false,
+					}, nil
+				},
+			}
+
+			workspace := database.Workspace{
+				ID:         uuid.New(),
+				OwnerID:    uuid.New(),
+				TemplateID: uuid.New(),
+			}
+			if c.workspaceTTL > 0 {
+				workspace.Ttl = sql.NullInt64{Int64: int64(c.workspaceTTL), Valid: true}
+			}
+
+			autostop, err := schedule.CalculateAutostop(ctx, schedule.CalculateAutostopParams{
+				TemplateScheduleStore:       templateScheduleStore,
+				UserQuietHoursScheduleStore: userQuietHoursScheduleStore,
+				Now:                         c.now,
+				Workspace:                   workspace,
+			})
+			require.NoError(t, err)
+			require.Equal(t, c.expectedDeadline.UTC(), autostop.Deadline.UTC(), "deadline")
+			require.Equal(t, c.expectedMaxDeadline.UTC(), autostop.MaxDeadline.UTC(), "max deadline")
+		})
+	}
+}
+
+func mustDaily(t *testing.T, s string) *schedule.Schedule {
+	t.Helper()
+	sched, err := schedule.Daily(s)
+	require.NoError(t, err)
+	return sched
+}
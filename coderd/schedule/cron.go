@@ -76,6 +76,23 @@ func Weekly(raw string) (*Schedule, error) {
 	return cronSched, nil
 }
 
+// Daily parses a Schedule from spec scoped to a recurring daily event.
+// The spec format is the same as Weekly, except that the day-of-week field
+// must be *.
+//
+// Example Usage:
+//
+//	sched, _ := schedule.Daily("CRON_TZ=Europe/Dublin 0 2 * * *")
+//	fmt.Println(sched.Next(time.Now()).Format(time.RFC3339))
+//	// Output: 2022-04-05T01:00:00Z
+func Daily(raw string) (*Schedule, error) {
+	if err := validateDailySpec(raw); err != nil {
+		return nil, xerrors.Errorf("validate daily schedule: %w", err)
+	}
+
+	return Weekly(raw)
+}
+
 // Schedule represents a cron schedule.
 // It's essentially a wrapper for robfig/cron/v3 that has additional
 // convenience methods.
@@ -191,3 +208,19 @@ func validateWeeklySpec(spec string) error {
 	}
 	return nil
 }
+
+// validateDailySpec ensures that the day-of-month, month and day-of-week
+// options of spec are all set to *
+func validateDailySpec(spec string) error {
+	parts := strings.Fields(spec)
+	if len(parts) < 5 {
+		return xerrors.Errorf("expected schedule to consist of 5 fields with an optional CRON_TZ=<timezone> prefix")
+	}
+	if len(parts) == 6 {
+		parts = parts[1:]
+	}
+	if parts[2] != "*" || parts[3] != "*" || parts[4] != "*" {
+		return xerrors.Errorf("expected month, dom and dow to be *")
+	}
+	return nil
+}
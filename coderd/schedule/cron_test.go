@@ -163,6 +163,25 @@ func Test_Weekly(t *testing.T) {
 	}
 }
 
+func Test_Daily(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		sched, err := schedule.Daily("CRON_TZ=Europe/Dublin 30 2 * * *")
+		require.NoError(t, err)
+		require.Equal(t, "CRON_TZ=Europe/Dublin 30 2 * * *", sched.String())
+		require.Equal(t, "daily", sched.DaysOfWeek())
+		require.Equal(t, 24*time.Hour, sched.Min())
+	})
+
+	t.Run("DayOfWeek", func(t *testing.T) {
+		t.Parallel()
+		_, err := schedule.Daily("CRON_TZ=Europe/Dublin 30 2 * * 1-5")
+		require.ErrorContains(t, err, "expected month, dom and dow to be *")
+	})
+}
+
 func mustLocation(t *testing.T, s string) *time.Location {
 	t.Helper()
 	loc, err := time.LoadLocation(s)
@@ -30,3 +30,26 @@ func (m MockTemplateScheduleStore) SetTemplateScheduleOptions(ctx context.Contex
 
 	return NewAGPLTemplateScheduleStore().SetTemplateScheduleOptions(ctx, db, template, options)
 }
+
+type MockUserQuietHoursScheduleStore struct {
+	GetFn func(ctx context.Context, db database.Store, userID uuid.UUID) (UserQuietHoursScheduleOptions, error)
+	SetFn func(ctx context.Context, db database.Store, userID uuid.UUID, schedule string) (UserQuietHoursScheduleOptions, error)
+}
+
+var _ UserQuietHoursScheduleStore = MockUserQuietHoursScheduleStore{}
+
+func (m MockUserQuietHoursScheduleStore) Get(ctx context.Context, db database.Store, userID uuid.UUID) (UserQuietHoursScheduleOptions, error) {
+	if m.GetFn != nil {
+		return m.GetFn(ctx, db, userID)
+	}
+
+	return NewAGPLUserQuietHoursScheduleStore().Get(ctx, db, userID)
+}
+
+func (m MockUserQuietHoursScheduleStore) Set(ctx context.Context, db database.Store, userID uuid.UUID, schedule string) (UserQuietHoursScheduleOptions, error) {
+	if m.SetFn != nil {
+		return m.SetFn(ctx, db, userID, schedule)
+	}
+
+	return NewAGPLUserQuietHoursScheduleStore().Set(ctx, db, userID, schedule)
+}
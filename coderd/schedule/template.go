@@ -5,10 +5,68 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/database"
 )
 
+// DaysOfWeek intentionally starts on Monday as opposed to Sunday so the weekend
+// days are contiguous in the bitmap. This matters when restarting every second
+// week or more, so that workspaces restart at the end of the week rather than
+// the start.
+var DaysOfWeek = []time.Weekday{
+	time.Monday,
+	time.Tuesday,
+	time.Wednesday,
+	time.Thursday,
+	time.Friday,
+	time.Saturday,
+	time.Sunday,
+}
+
+type TemplateRestartRequirement struct {
+	// DaysOfWeek is a bitmap of which days of the week the workspace must be
+	// restarted. If fully zero, the workspace is not required to be restarted
+	// ever.
+	//
+	// First bit is Monday, ..., seventh bit is Sunday, eighth bit is unused.
+	DaysOfWeek uint8
+	// Weeks is the amount of weeks between restarts. If 0 or 1, the workspace
+	// is restarted weekly in accordance with DaysOfWeek. If 2, the workspace is
+	// restarted every other week. And so forth.
+	Weeks int64
+}
+
+// Enabled returns true if the template has a restart requirement.
+func (r TemplateRestartRequirement) Enabled() bool {
+	return r.DaysOfWeek != 0
+}
+
+// DaysMap returns a map of the days of the week that the workspace must be
+// restarted on.
+func (r TemplateRestartRequirement) DaysMap() map[time.Weekday]bool {
+	days := make(map[time.Weekday]bool)
+	for i, day := range DaysOfWeek {
+		days[day] = r.DaysOfWeek&(1<<uint(i)) != 0
+	}
+	return days
+}
+
+// VerifyTemplateRestartRequirement returns an error if the restart requirement
+// is invalid.
+func VerifyTemplateRestartRequirement(days uint8, weeks int64) error {
+	if days&0b10000000 != 0 {
+		return xerrors.New("invalid restart requirement days, last bit is set")
+	}
+	if weeks < 0 {
+		return xerrors.New("invalid restart requirement weeks, negative")
+	}
+	if weeks > 16 {
+		return xerrors.New("invalid restart requirement weeks, too large")
+	}
+	return nil
+}
+
 type TemplateScheduleOptions struct {
 	UserAutostartEnabled bool          `json:"user_autostart_enabled"`
 	UserAutostopEnabled  bool          `json:"user_autostop_enabled"`
@@ -18,6 +76,19 @@ type TemplateScheduleOptions struct {
 	//
 	// If set, users cannot disable automatic workspace shutdown.
 	MaxTTL time.Duration `json:"max_ttl"`
+	// If InactivityTTL is set, workspaces that have not been used for longer
+	// than this duration are marked dormant. Dormant workspaces are stopped
+	// and cannot be started until they are explicitly made active again.
+	InactivityTTL time.Duration `json:"inactivity_ttl"`
+	// If DormantAutoDeleteTTL is set, workspaces that have been dormant for
+	// longer than this duration are deleted automatically.
+	DormantAutoDeleteTTL time.Duration `json:"dormant_autodelete_ttl"`
+	// If FailureTTL is set, workspaces whose start build failed longer than
+	// this duration ago are stopped automatically.
+	FailureTTL time.Duration `json:"failure_ttl"`
+	// RestartRequirement dictates when the workspace must be restarted. The
+	// restart happens during the workspace owner's quiet hours.
+	RestartRequirement TemplateRestartRequirement `json:"restart_requirement"`
 }
 
 // TemplateScheduleStore provides an interface for retrieving template
@@ -47,9 +118,16 @@ func (*agplTemplateScheduleStore) GetTemplateScheduleOptions(ctx context.Context
 		UserAutostartEnabled: true,
 		UserAutostopEnabled:  true,
 		DefaultTTL:           time.Duration(tpl.DefaultTTL),
-		// Disregard the value in the database, since MaxTTL is an enterprise
-		// feature.
-		MaxTTL: 0,
+		// Disregard the values in the database, since MaxTTL, dormancy,
+		// FailureTTL and RestartRequirement are enterprise features.
+		MaxTTL:               0,
+		InactivityTTL:        0,
+		DormantAutoDeleteTTL: 0,
+		FailureTTL:           0,
+		RestartRequirement: TemplateRestartRequirement{
+			DaysOfWeek: 0,
+			Weeks:      0,
+		},
 	}, nil
 }
 
@@ -65,8 +143,13 @@ func (*agplTemplateScheduleStore) SetTemplateScheduleOptions(ctx context.Context
 		DefaultTTL: int64(opts.DefaultTTL),
 		// Don't allow changing it, but keep the value in the DB (to avoid
 		// clearing settings if the license has an issue).
-		AllowUserAutostart: tpl.AllowUserAutostart,
-		AllowUserAutostop:  tpl.AllowUserAutostop,
-		MaxTTL:             tpl.MaxTTL,
+		AllowUserAutostart:           tpl.AllowUserAutostart,
+		AllowUserAutostop:            tpl.AllowUserAutostop,
+		MaxTTL:                       tpl.MaxTTL,
+		InactivityTTL:                tpl.InactivityTTL,
+		DormantAutoDeleteTTL:         tpl.DormantAutoDeleteTTL,
+		FailureTTL:                   tpl.FailureTTL,
+		RestartRequirementDaysOfWeek: tpl.RestartRequirementDaysOfWeek,
+		RestartRequirementWeeks:      tpl.RestartRequirementWeeks,
 	})
 }
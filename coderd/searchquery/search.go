@@ -104,6 +104,7 @@ func Workspaces(query string, page codersdk.Pagination, agentInactiveDisconnectT
 	filter.Name = parser.String(values, "", "name")
 	filter.Status = string(httpapi.ParseCustom(parser, values, "", "status", httpapi.ParseEnum[database.WorkspaceStatus]))
 	filter.HasAgent = parser.String(values, "", "has-agent")
+	filter.Dormant = parser.Boolean(values, false, "dormant")
 	parser.ErrorExcessParams(values)
 	return filter, parser.Errors
 }
@@ -115,6 +115,13 @@ func TestSearchWorkspace(t *testing.T) {
 				OwnerUsername: "foo",
 			},
 		},
+		{
+			Name:  "Dormant",
+			Query: `dormant:true`,
+			Expected: database.GetWorkspacesParams{
+				Dormant: true,
+			},
+		},
 
 		// Failures
 		{
@@ -142,6 +149,11 @@ func TestSearchWorkspace(t *testing.T) {
 			Query:                 `foo:bar`,
 			ExpectedErrorContains: `Query param "foo" is not a valid query param`,
 		},
+		{
+			Name:                  "InvalidDormant",
+			Query:                 `dormant:maybe`,
+			ExpectedErrorContains: "must be a valid boolean",
+		},
 	}
 
 	for _, c := range testCases {
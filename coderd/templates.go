@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -25,6 +27,10 @@ import (
 	"github.com/coder/coder/examples"
 )
 
+// maxQuotaCostMultiplier keeps multiplied workspace costs well within the
+// range of the int32 costs stored for builds.
+const maxQuotaCostMultiplier = 1000
+
 // Returns a single template.
 //
 // @Summary Get template metadata by ID
@@ -199,8 +205,12 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	}
 
 	var (
-		defaultTTL time.Duration
-		maxTTL     time.Duration
+		defaultTTL           time.Duration
+		maxTTL               time.Duration
+		inactivityTTL        time.Duration
+		dormantAutoDeleteTTL time.Duration
+		failureTTL           time.Duration
+		restartRequirement   schedule.TemplateRestartRequirement
 	)
 	if createTemplate.DefaultTTLMillis != nil {
 		defaultTTL = time.Duration(*createTemplate.DefaultTTLMillis) * time.Millisecond
@@ -208,6 +218,15 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if createTemplate.MaxTTLMillis != nil {
 		maxTTL = time.Duration(*createTemplate.MaxTTLMillis) * time.Millisecond
 	}
+	if createTemplate.InactivityTTLMillis != nil {
+		inactivityTTL = time.Duration(*createTemplate.InactivityTTLMillis) * time.Millisecond
+	}
+	if createTemplate.DormantAutoDeleteTTLMillis != nil {
+		dormantAutoDeleteTTL = time.Duration(*createTemplate.DormantAutoDeleteTTLMillis) * time.Millisecond
+	}
+	if createTemplate.FailureTTLMillis != nil {
+		failureTTL = time.Duration(*createTemplate.FailureTTLMillis) * time.Millisecond
+	}
 
 	var validErrs []codersdk.ValidationError
 	if defaultTTL < 0 {
@@ -219,6 +238,18 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if maxTTL != 0 && defaultTTL > maxTTL {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: "Must be less than or equal to max_ttl_ms if max_ttl_ms is set."})
 	}
+	if inactivityTTL < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "inactivity_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if dormantAutoDeleteTTL < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "dormant_autodelete_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if failureTTL < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "failure_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if createTemplate.RestartRequirement != nil {
+		restartRequirement, validErrs = validateRestartRequirement(*createTemplate.RestartRequirement, validErrs)
+	}
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid create template request.",
@@ -273,6 +304,10 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 			UserAutostopEnabled:  allowUserAutostop,
 			DefaultTTL:           defaultTTL,
 			MaxTTL:               maxTTL,
+			InactivityTTL:        inactivityTTL,
+			DormantAutoDeleteTTL: dormantAutoDeleteTTL,
+			FailureTTL:           failureTTL,
+			RestartRequirement:   restartRequirement,
 		})
 		if err != nil {
 			return xerrors.Errorf("set template schedule options: %s", err)
@@ -292,6 +327,10 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 		if err != nil {
 			return xerrors.Errorf("insert template version: %s", err)
 		}
+		err = recordTemplateVersionPromotion(ctx, tx, dbTemplate.ID, templateVersion.ID, apiKey.UserID, now)
+		if err != nil {
+			return err
+		}
 		newTemplateVersion := templateVersion
 		newTemplateVersion.TemplateID = uuid.NullUUID{
 			UUID:  dbTemplate.ID,
@@ -346,12 +385,23 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 // @Produce json
 // @Tags Templates
 // @Param organization path string true "Organization ID" format(uuid)
+// @Param include_deprecated query bool false "Include deprecated templates"
 // @Success 200 {array} codersdk.Template
 // @Router /organizations/{organization}/templates [get]
 func (api *API) templatesByOrganization(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	organization := httpmw.OrganizationParam(r)
 
+	parser := httpapi.NewQueryParamParser()
+	includeDeprecated := parser.Boolean(r.URL.Query(), false, "include_deprecated")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
 	prepared, err := api.HTTPAuth.AuthorizeSQLFilter(r, rbac.ActionRead, rbac.ResourceTemplate.Type)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -363,7 +413,8 @@ func (api *API) templatesByOrganization(rw http.ResponseWriter, r *http.Request)
 
 	// Filter templates based on rbac permissions
 	templates, err := api.Database.GetAuthorizedTemplates(ctx, database.GetTemplatesWithFilterParams{
-		OrganizationID: organization.ID,
+		OrganizationID:    organization.ID,
+		ExcludeDeprecated: !includeDeprecated,
 	}, prepared)
 	if errors.Is(err, sql.ErrNoRows) {
 		err = nil
@@ -469,6 +520,48 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.MaxTTLMillis != 0 && req.DefaultTTLMillis > req.MaxTTLMillis {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: "Must be less than or equal to max_ttl_ms if max_ttl_ms is set."})
 	}
+	if req.InactivityTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "inactivity_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if req.DormantAutoDeleteTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "dormant_autodelete_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if req.FailureTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "failure_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	restartRequirement := schedule.TemplateRestartRequirement{
+		DaysOfWeek: uint8(template.RestartRequirementDaysOfWeek),
+		Weeks:      template.RestartRequirementWeeks,
+	}
+	if req.RestartRequirement != nil {
+		restartRequirement, validErrs = validateRestartRequirement(*req.RestartRequirement, validErrs)
+	}
+	deprecationMessage := template.Deprecated
+	if req.DeprecationMessage != nil {
+		deprecationMessage = strings.TrimSpace(*req.DeprecationMessage)
+	}
+	requireActiveVersion := template.RequireActiveVersion
+	if req.RequireActiveVersion != nil {
+		requireActiveVersion = *req.RequireActiveVersion
+	}
+	maxPortSharingLevel := template.MaxPortSharingLevel
+	if req.MaxPortShareLevel != nil {
+		maxPortSharingLevel = database.AppSharingLevel(*req.MaxPortShareLevel)
+		if !maxPortSharingLevel.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_port_share_level", Detail: fmt.Sprintf("Must be one of %q.", database.AllAppSharingLevelValues())})
+		}
+	}
+	sessionRecording := template.SessionRecordingEnabled
+	if req.SessionRecording != nil {
+		sessionRecording = *req.SessionRecording
+	}
+	quotaCostMultiplier := template.QuotaCostMultiplier
+	if req.QuotaCostMultiplier != nil {
+		quotaCostMultiplier = *req.QuotaCostMultiplier
+		if quotaCostMultiplier < 0 || quotaCostMultiplier > maxQuotaCostMultiplier || math.IsNaN(quotaCostMultiplier) {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "quota_cost_multiplier", Detail: fmt.Sprintf("Must be between 0 and %d.", maxQuotaCostMultiplier)})
+		}
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -488,7 +581,17 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.AllowUserAutostop == template.AllowUserAutostop &&
 			req.AllowUserCancelWorkspaceJobs == template.AllowUserCancelWorkspaceJobs &&
 			req.DefaultTTLMillis == time.Duration(template.DefaultTTL).Milliseconds() &&
-			req.MaxTTLMillis == time.Duration(template.MaxTTL).Milliseconds() {
+			req.MaxTTLMillis == time.Duration(template.MaxTTL).Milliseconds() &&
+			req.InactivityTTLMillis == time.Duration(template.InactivityTTL).Milliseconds() &&
+			req.DormantAutoDeleteTTLMillis == time.Duration(template.DormantAutoDeleteTTL).Milliseconds() &&
+			req.FailureTTLMillis == time.Duration(template.FailureTTL).Milliseconds() &&
+			int16(restartRequirement.DaysOfWeek) == template.RestartRequirementDaysOfWeek &&
+			restartRequirement.Weeks == template.RestartRequirementWeeks &&
+			deprecationMessage == template.Deprecated &&
+			requireActiveVersion == template.RequireActiveVersion &&
+			maxPortSharingLevel == template.MaxPortSharingLevel &&
+			sessionRecording == template.SessionRecordingEnabled &&
+			quotaCostMultiplier == template.QuotaCostMultiplier {
 			return nil
 		}
 
@@ -516,6 +619,11 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			Description:                  desc,
 			Icon:                         icon,
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
+			Deprecated:                   deprecationMessage,
+			RequireActiveVersion:         requireActiveVersion,
+			MaxPortSharingLevel:          maxPortSharingLevel,
+			SessionRecordingEnabled:      sessionRecording,
+			QuotaCostMultiplier:          quotaCostMultiplier,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -523,8 +631,16 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 
 		defaultTTL := time.Duration(req.DefaultTTLMillis) * time.Millisecond
 		maxTTL := time.Duration(req.MaxTTLMillis) * time.Millisecond
+		inactivityTTL := time.Duration(req.InactivityTTLMillis) * time.Millisecond
+		dormantAutoDeleteTTL := time.Duration(req.DormantAutoDeleteTTLMillis) * time.Millisecond
+		failureTTL := time.Duration(req.FailureTTLMillis) * time.Millisecond
 		if defaultTTL != time.Duration(template.DefaultTTL) ||
 			maxTTL != time.Duration(template.MaxTTL) ||
+			inactivityTTL != time.Duration(template.InactivityTTL) ||
+			dormantAutoDeleteTTL != time.Duration(template.DormantAutoDeleteTTL) ||
+			failureTTL != time.Duration(template.FailureTTL) ||
+			int16(restartRequirement.DaysOfWeek) != template.RestartRequirementDaysOfWeek ||
+			restartRequirement.Weeks != template.RestartRequirementWeeks ||
 			req.AllowUserAutostart != template.AllowUserAutostart ||
 			req.AllowUserAutostop != template.AllowUserAutostop {
 			updated, err = (*api.TemplateScheduleStore.Load()).SetTemplateScheduleOptions(ctx, tx, updated, schedule.TemplateScheduleOptions{
@@ -535,6 +651,10 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 				UserAutostopEnabled:  req.AllowUserAutostop,
 				DefaultTTL:           defaultTTL,
 				MaxTTL:               maxTTL,
+				InactivityTTL:        inactivityTTL,
+				DormantAutoDeleteTTL: dormantAutoDeleteTTL,
+				FailureTTL:           failureTTL,
+				RestartRequirement:   restartRequirement,
 			})
 			if err != nil {
 				return xerrors.Errorf("set template schedule options: %w", err)
@@ -655,24 +775,54 @@ func (api *API) convertTemplate(
 	buildTimeStats := api.metricsCache.TemplateBuildTimeStats(template.ID)
 
 	return codersdk.Template{
-		ID:                           template.ID,
-		CreatedAt:                    template.CreatedAt,
-		UpdatedAt:                    template.UpdatedAt,
-		OrganizationID:               template.OrganizationID,
-		Name:                         template.Name,
-		DisplayName:                  template.DisplayName,
-		Provisioner:                  codersdk.ProvisionerType(template.Provisioner),
-		ActiveVersionID:              template.ActiveVersionID,
-		ActiveUserCount:              activeCount,
-		BuildTimeStats:               buildTimeStats,
-		Description:                  template.Description,
-		Icon:                         template.Icon,
-		DefaultTTLMillis:             time.Duration(template.DefaultTTL).Milliseconds(),
-		MaxTTLMillis:                 time.Duration(template.MaxTTL).Milliseconds(),
+		ID:                         template.ID,
+		CreatedAt:                  template.CreatedAt,
+		UpdatedAt:                  template.UpdatedAt,
+		OrganizationID:             template.OrganizationID,
+		Name:                       template.Name,
+		DisplayName:                template.DisplayName,
+		Provisioner:                codersdk.ProvisionerType(template.Provisioner),
+		ActiveVersionID:            template.ActiveVersionID,
+		ActiveUserCount:            activeCount,
+		BuildTimeStats:             buildTimeStats,
+		Description:                template.Description,
+		Icon:                       template.Icon,
+		DefaultTTLMillis:           time.Duration(template.DefaultTTL).Milliseconds(),
+		MaxTTLMillis:               time.Duration(template.MaxTTL).Milliseconds(),
+		InactivityTTLMillis:        time.Duration(template.InactivityTTL).Milliseconds(),
+		DormantAutoDeleteTTLMillis: time.Duration(template.DormantAutoDeleteTTL).Milliseconds(),
+		FailureTTLMillis:           time.Duration(template.FailureTTL).Milliseconds(),
+		RestartRequirement: codersdk.TemplateRestartRequirement{
+			DaysOfWeek: codersdk.BitmapToWeekdays(uint8(template.RestartRequirementDaysOfWeek)),
+			Weeks:      template.RestartRequirementWeeks,
+		},
 		CreatedByID:                  template.CreatedBy,
 		CreatedByName:                createdByName,
 		AllowUserAutostart:           template.AllowUserAutostart,
 		AllowUserAutostop:            template.AllowUserAutostop,
 		AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+		Deprecated:                   template.Deprecated != "",
+		DeprecationMessage:           template.Deprecated,
+		RequireActiveVersion:         template.RequireActiveVersion,
+		MaxPortShareLevel:            codersdk.WorkspaceAppSharingLevel(template.MaxPortSharingLevel),
+		SessionRecording:             template.SessionRecordingEnabled,
+		QuotaCostMultiplier:          template.QuotaCostMultiplier,
+	}
+}
+
+// validateRestartRequirement converts the restart requirement to its schedule
+// representation, appending any validation errors to validErrs.
+func validateRestartRequirement(req codersdk.TemplateRestartRequirement, validErrs []codersdk.ValidationError) (schedule.TemplateRestartRequirement, []codersdk.ValidationError) {
+	daysOfWeek, err := codersdk.WeekdaysToBitmap(req.DaysOfWeek)
+	if err != nil {
+		return schedule.TemplateRestartRequirement{}, append(validErrs, codersdk.ValidationError{Field: "restart_requirement.days_of_week", Detail: err.Error()})
+	}
+	err = schedule.VerifyTemplateRestartRequirement(daysOfWeek, req.Weeks)
+	if err != nil {
+		return schedule.TemplateRestartRequirement{}, append(validErrs, codersdk.ValidationError{Field: "restart_requirement", Detail: err.Error()})
 	}
+	return schedule.TemplateRestartRequirement{
+		DaysOfWeek: daysOfWeek,
+		Weeks:      req.Weeks,
+	}, validErrs
 }
@@ -357,6 +357,44 @@ func TestTemplatesByOrganization(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, templates, 2)
 	})
+	t.Run("ListDeprecated", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		active := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		deprecated := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, deprecated.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Use another template"),
+		})
+		require.NoError(t, err)
+
+		// Deprecated templates are hidden by default.
+		templates, err := client.TemplatesByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, active.ID, templates[0].ID)
+
+		templates, err = client.TemplatesByOrganizationWithFilter(ctx, user.OrganizationID, codersdk.TemplateFilter{
+			IncludeDeprecated: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, templates, 2)
+
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/organizations/"+user.OrganizationID.String()+"/templates", nil,
+			codersdk.WithQueryParam("include_deprecated", "maybe"))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		err = codersdk.ReadBodyAsError(res)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "include_deprecated", apiErr.Validations[0].Field)
+	})
 }
 
 func TestTemplateByOrganizationAndName(t *testing.T) {
@@ -811,6 +849,43 @@ func TestPatchTemplateMeta(t *testing.T) {
 		require.NoError(t, err)
 		require.EqualValues(t, 0, template.MaxTTLMillis)
 	})
+
+	t.Run("Deprecated", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.False(t, template.Deprecated)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+			DeprecationMessage:           ptr.Ref("  Use the new template instead.  "),
+		})
+		require.NoError(t, err)
+		require.True(t, updated.Deprecated)
+		require.Equal(t, "Use the new template instead.", updated.DeprecationMessage)
+
+		// Omitting the message leaves the template deprecated.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description:                  "new description",
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+		})
+		require.NoError(t, err)
+		require.True(t, updated.Deprecated)
+
+		// An empty message un-deprecates the template.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+			DeprecationMessage:           ptr.Ref(""),
+		})
+		require.NoError(t, err)
+		require.False(t, updated.Deprecated)
+		require.Empty(t, updated.DeprecationMessage)
+	})
 }
 
 func TestDeleteTemplate(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -27,7 +28,6 @@ import (
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/examples"
-	sdkproto "github.com/coder/coder/provisionersdk/proto"
 )
 
 // @Summary Get template version by ID
@@ -60,7 +60,16 @@ func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(job), user))
+	promoter, err := templateVersionPromoter(ctx, api.Database, templateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(job), user, promoter))
 }
 
 // @Summary Patch template version by ID
@@ -154,7 +163,16 @@ func (api *API) patchTemplateVersion(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(updatedTemplateVersion, convertProvisionerJob(job), user))
+	promoter, err := templateVersionPromoter(ctx, api.Database, updatedTemplateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(updatedTemplateVersion, convertProvisionerJob(job), user, promoter))
 }
 
 // @Summary Cancel template version by ID
@@ -300,7 +318,7 @@ func (api *API) templateVersionRichParameters(rw http.ResponseWriter, r *http.Re
 		return
 	}
 
-	templateVersionParameters, err := convertTemplateVersionParameters(dbTemplateVersionParameters)
+	templateVersionParameters, err := parameter.ConvertTemplateVersionParameters(dbTemplateVersionParameters)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error converting template version parameter.",
@@ -872,7 +890,15 @@ func (api *API) templateVersionsByTemplate(rw http.ResponseWriter, r *http.Reque
 				})
 				return err
 			}
-			apiVersions = append(apiVersions, convertTemplateVersion(version, convertProvisionerJob(job), user))
+			promoter, err := templateVersionPromoter(ctx, store, version)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error on fetching user.",
+					Detail:  err.Error(),
+				})
+				return err
+			}
+			apiVersions = append(apiVersions, convertTemplateVersion(version, convertProvisionerJob(job), user, promoter))
 		}
 
 		return nil
@@ -936,7 +962,16 @@ func (api *API) templateVersionByName(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(job), user))
+	promoter, err := templateVersionPromoter(ctx, api.Database, templateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(job), user, promoter))
 }
 
 // @Summary Get template version by organization, template, and name
@@ -1010,7 +1045,16 @@ func (api *API) templateVersionByOrganizationTemplateAndName(rw http.ResponseWri
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(job), user))
+	promoter, err := templateVersionPromoter(ctx, api.Database, templateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(job), user, promoter))
 }
 
 // @Summary Get previous template version by organization, template, and name
@@ -1105,7 +1149,16 @@ func (api *API) previousTemplateVersionByOrganizationTemplateAndName(rw http.Res
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(previousTemplateVersion, convertProvisionerJob(job), user))
+	promoter, err := templateVersionPromoter(ctx, api.Database, previousTemplateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(previousTemplateVersion, convertProvisionerJob(job), user, promoter))
 }
 
 // @Summary Update active template version by template ID
@@ -1159,15 +1212,16 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	}
 
 	err = api.Database.InTx(func(store database.Store) error {
+		now := database.Now()
 		err = store.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
 			ID:              template.ID,
 			ActiveVersionID: req.ID,
-			UpdatedAt:       database.Now(),
+			UpdatedAt:       now,
 		})
 		if err != nil {
 			return xerrors.Errorf("update active version: %w", err)
 		}
-		return nil
+		return recordTemplateVersionPromotion(ctx, store, template.ID, req.ID, httpmw.APIKey(r).UserID, now)
 	}, nil)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -1181,12 +1235,177 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	aReq.New = newTemplate
 
 	api.publishTemplateUpdate(ctx, template.ID)
+	api.PublishWebhookEvent(ctx, database.WebhookEventTemplateVersionPromoted, codersdk.WebhookDataTemplateVersionPromoted{
+		OrganizationID:            template.OrganizationID,
+		TemplateID:                template.ID,
+		TemplateName:              template.Name,
+		TemplateVersionID:         version.ID,
+		TemplateVersionName:       version.Name,
+		PreviousTemplateVersionID: template.ActiveVersionID,
+		PromotedBy:                httpmw.APIKey(r).UserID,
+	})
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Updated the active template version!",
 	})
 }
 
+// @Summary Roll back active template version by template ID
+// @ID roll-back-active-template-version-by-template-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersion
+// @Router /templates/{template}/versions/rollback [post]
+func (api *API) postRollbackActiveTemplateVersion(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Template](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = template
+
+	errNothingToRollBack := xerrors.New("nothing to roll back")
+	var version database.TemplateVersion
+	err := api.Database.InTx(func(store database.Store) error {
+		promotions, err := store.GetTemplateVersionPromotionsByTemplateID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get template version promotions: %w", err)
+		}
+		// Promotions that were rolled back aren't returned, so the most
+		// recent promotion of another version is the one that was undone by
+		// the promotion of the active version.
+		var previous *database.TemplateVersionPromotion
+		for i := range promotions {
+			if promotions[i].TemplateVersionID != template.ActiveVersionID {
+				previous = &promotions[i]
+				break
+			}
+		}
+		if previous == nil {
+			return errNothingToRollBack
+		}
+
+		now := database.Now()
+		err = store.UpdateTemplateVersionPromotionsRolledBack(ctx, database.UpdateTemplateVersionPromotionsRolledBackParams{
+			TemplateID:    template.ID,
+			RolledBackAt:  sql.NullTime{Time: now, Valid: true},
+			PromotedAfter: previous.PromotedAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("roll back template version promotions: %w", err)
+		}
+		err = store.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
+			ID:              template.ID,
+			ActiveVersionID: previous.TemplateVersionID,
+			UpdatedAt:       now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update active version: %w", err)
+		}
+		err = store.UpdateTemplateVersionPromotedByID(ctx, database.UpdateTemplateVersionPromotedByIDParams{
+			ID:         previous.TemplateVersionID,
+			PromotedAt: sql.NullTime{Time: now, Valid: true},
+			PromotedBy: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("update template version promoted: %w", err)
+		}
+		version, err = store.GetTemplateVersionByID(ctx, previous.TemplateVersionID)
+		if err != nil {
+			return xerrors.Errorf("get template version: %w", err)
+		}
+		return nil
+	}, nil)
+	if errors.Is(err, errNothingToRollBack) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The template has no previously promoted version to roll back to.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rolling back the active template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	newTemplate := template
+	newTemplate.ActiveVersionID = version.ID
+	aReq.New = newTemplate
+
+	api.publishTemplateUpdate(ctx, template.ID)
+	api.PublishWebhookEvent(ctx, database.WebhookEventTemplateVersionPromoted, codersdk.WebhookDataTemplateVersionPromoted{
+		OrganizationID:            template.OrganizationID,
+		TemplateID:                template.ID,
+		TemplateName:              template.Name,
+		TemplateVersionID:         version.ID,
+		TemplateVersionName:       version.Name,
+		PreviousTemplateVersionID: template.ActiveVersionID,
+		PromotedBy:                apiKey.UserID,
+	})
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	user, err := api.Database.GetUserByID(ctx, version.CreatedBy)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	promoter, err := templateVersionPromoter(ctx, api.Database, version)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(version, convertProvisionerJob(job), user, promoter))
+}
+
+// recordTemplateVersionPromotion records that the user made the version the
+// active version of the template, so it can be rolled back later.
+func recordTemplateVersionPromotion(ctx context.Context, store database.Store, templateID, versionID, userID uuid.UUID, now time.Time) error {
+	err := store.UpdateTemplateVersionPromotedByID(ctx, database.UpdateTemplateVersionPromotedByIDParams{
+		ID:         versionID,
+		PromotedAt: sql.NullTime{Time: now, Valid: true},
+		PromotedBy: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		return xerrors.Errorf("update template version promoted: %w", err)
+	}
+	_, err = store.InsertTemplateVersionPromotion(ctx, database.InsertTemplateVersionPromotionParams{
+		ID:                uuid.New(),
+		TemplateID:        templateID,
+		TemplateVersionID: versionID,
+		PromotedAt:        now,
+		PromotedBy:        uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		return xerrors.Errorf("insert template version promotion: %w", err)
+	}
+	return nil
+}
+
 // postTemplateVersionsByOrganization creates a new version of a template. An import job is queued to parse the storage method provided.
 //
 // @Summary Create template version by organization
@@ -1471,7 +1690,16 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateVersion(templateVersion, convertProvisionerJob(provisionerJob), user))
+	promoter, err := templateVersionPromoter(ctx, api.Database, templateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error on fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateVersion(templateVersion, convertProvisionerJob(provisionerJob), user, promoter))
 }
 
 // templateVersionResources returns the workspace agent resources associated
@@ -1538,18 +1766,30 @@ func (api *API) templateVersionLogs(rw http.ResponseWriter, r *http.Request) {
 	api.provisionerJobLogs(rw, r, job)
 }
 
-func convertTemplateVersion(version database.TemplateVersion, job codersdk.ProvisionerJob, user database.User) codersdk.TemplateVersion {
-	createdBy := codersdk.User{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		Status:    codersdk.UserStatus(user.Status),
-		Roles:     []codersdk.Role{},
-		AvatarURL: user.AvatarURL.String,
+// templateVersionPromoter returns the user that last made the template version
+// the active version of its template. The zero value is returned if the
+// version was never promoted.
+func templateVersionPromoter(ctx context.Context, db database.Store, version database.TemplateVersion) (database.User, error) {
+	if !version.PromotedBy.Valid {
+		return database.User{}, nil
 	}
+	return db.GetUserByID(ctx, version.PromotedBy.UUID)
+}
 
-	return codersdk.TemplateVersion{
+func convertTemplateVersion(version database.TemplateVersion, job codersdk.ProvisionerJob, user database.User, promoter database.User) codersdk.TemplateVersion {
+	convertUser := func(user database.User) codersdk.User {
+		return codersdk.User{
+			ID:        user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			CreatedAt: user.CreatedAt,
+			Status:    codersdk.UserStatus(user.Status),
+			Roles:     []codersdk.Role{},
+			AvatarURL: user.AvatarURL.String,
+		}
+	}
+
+	templateVersion := codersdk.TemplateVersion{
 		ID:             version.ID,
 		TemplateID:     &version.TemplateID.UUID,
 		OrganizationID: version.OrganizationID,
@@ -1558,60 +1798,16 @@ func convertTemplateVersion(version database.TemplateVersion, job codersdk.Provi
 		Name:           version.Name,
 		Job:            job,
 		Readme:         version.Readme,
-		CreatedBy:      createdBy,
-	}
-}
-
-func convertTemplateVersionParameters(dbParams []database.TemplateVersionParameter) ([]codersdk.TemplateVersionParameter, error) {
-	params := make([]codersdk.TemplateVersionParameter, 0)
-	for _, dbParameter := range dbParams {
-		param, err := convertTemplateVersionParameter(dbParameter)
-		if err != nil {
-			return nil, err
-		}
-		params = append(params, param)
+		CreatedBy:      convertUser(user),
 	}
-	return params, nil
-}
-
-func convertTemplateVersionParameter(param database.TemplateVersionParameter) (codersdk.TemplateVersionParameter, error) {
-	var protoOptions []*sdkproto.RichParameterOption
-	err := json.Unmarshal(param.Options, &protoOptions)
-	if err != nil {
-		return codersdk.TemplateVersionParameter{}, err
+	if version.PromotedAt.Valid {
+		templateVersion.PromotedAt = &version.PromotedAt.Time
 	}
-	options := make([]codersdk.TemplateVersionParameterOption, 0)
-	for _, option := range protoOptions {
-		options = append(options, codersdk.TemplateVersionParameterOption{
-			Name:        option.Name,
-			Description: option.Description,
-			Value:       option.Value,
-			Icon:        option.Icon,
-		})
+	if version.PromotedBy.Valid {
+		promotedBy := convertUser(promoter)
+		templateVersion.PromotedBy = &promotedBy
 	}
-
-	descriptionPlaintext, err := parameter.Plaintext(param.Description)
-	if err != nil {
-		return codersdk.TemplateVersionParameter{}, err
-	}
-	return codersdk.TemplateVersionParameter{
-		Name:                 param.Name,
-		DisplayName:          param.DisplayName,
-		Description:          param.Description,
-		DescriptionPlaintext: descriptionPlaintext,
-		Type:                 param.Type,
-		Mutable:              param.Mutable,
-		DefaultValue:         param.DefaultValue,
-		Icon:                 param.Icon,
-		Options:              options,
-		ValidationRegex:      param.ValidationRegex,
-		ValidationMin:        param.ValidationMin,
-		ValidationMax:        param.ValidationMax,
-		ValidationError:      param.ValidationError,
-		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
-		Required:             param.Required,
-		LegacyVariableName:   param.LegacyVariableName,
-	}, nil
+	return templateVersion
 }
 
 func convertTemplateVersionVariables(dbVariables []database.TemplateVersionVariable) []codersdk.TemplateVersionVariable {
@@ -718,6 +718,113 @@ func TestPatchActiveTemplateVersion(t *testing.T) {
 		require.Len(t, auditor.AuditLogs(), 5)
 		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[4].Action)
 	})
+
+	t.Run("RecordsPromotion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Creating the template promotes its first version.
+		version, err := client.TemplateVersion(ctx, version.ID)
+		require.NoError(t, err)
+		require.NotNil(t, version.PromotedAt)
+		require.NotNil(t, version.PromotedBy)
+		require.Equal(t, user.UserID, version.PromotedBy.ID)
+
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+		require.Nil(t, version2.PromotedAt)
+		require.Nil(t, version2.PromotedBy)
+
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version2.ID,
+		})
+		require.NoError(t, err)
+
+		version2, err = client.TemplateVersion(ctx, version2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, version2.PromotedAt)
+		require.False(t, version2.PromotedAt.Before(*version.PromotedAt))
+		require.NotNil(t, version2.PromotedBy)
+		require.Equal(t, user.UserID, version2.PromotedBy.ID)
+	})
+}
+
+func TestRollbackActiveTemplateVersion(t *testing.T) {
+	t.Parallel()
+	t.Run("WalksBackThroughPromotions", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version1 := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version1.ID)
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+		version3 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version3.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		promote := func(version codersdk.TemplateVersion) {
+			err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+				ID: version.ID,
+			})
+			require.NoError(t, err)
+		}
+		rollback := func(want codersdk.TemplateVersion) {
+			version, err := client.RollbackActiveTemplateVersion(ctx, template.ID)
+			require.NoError(t, err)
+			require.Equal(t, want.ID, version.ID)
+			require.NotNil(t, version.PromotedBy)
+			require.Equal(t, user.UserID, version.PromotedBy.ID)
+			template, err := client.Template(ctx, template.ID)
+			require.NoError(t, err)
+			require.Equal(t, want.ID, template.ActiveVersionID)
+		}
+
+		promote(version2)
+		promote(version3)
+		rollback(version2)
+		// Promoting a version again adds it back to the history.
+		promote(version3)
+		rollback(version2)
+		rollback(version1)
+
+		_, err := client.RollbackActiveTemplateVersion(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version1 := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version1.ID)
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version2.ID,
+		})
+		require.NoError(t, err)
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err = member.RollbackActiveTemplateVersion(ctx, template.ID)
+		require.Error(t, err)
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version2.ID, template.ActiveVersionID)
+	})
 }
 
 func TestTemplateVersionDryRun(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/codersdk"
 )
@@ -121,9 +122,17 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rbacRoles, err := rolestore.Expand(ctx, api.Database, roles.Roles)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
+		Roles:  rbacRoles,
 		Groups: roles.Groups,
 		Scope:  rbac.ScopeAll,
 	}
@@ -835,8 +844,9 @@ func (e httpError) Error() string {
 
 func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cookie, database.APIKey, error) {
 	var (
-		ctx  = r.Context()
-		user database.User
+		ctx     = r.Context()
+		user    database.User
+		created bool
 	)
 
 	err := api.Database.InTx(func(tx database.Store) error {
@@ -924,6 +934,7 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 			if err != nil {
 				return xerrors.Errorf("create user: %w", err)
 			}
+			created = true
 		}
 
 		if link.UserID == uuid.Nil {
@@ -1009,6 +1020,9 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 	if err != nil {
 		return nil, database.APIKey{}, xerrors.Errorf("in tx: %w", err)
 	}
+	if created {
+		api.PublishUserCreated(ctx, user)
+	}
 
 	//nolint:gocritic
 	cookie, key, err := api.createAPIKey(dbauthz.AsSystemRestricted(ctx), createAPIKeyParams{
@@ -137,6 +137,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.PublishUserCreated(ctx, user)
 
 	telemetryUser := telemetry.ConvertUser(user)
 	// Send the initial users email address!
@@ -357,6 +358,7 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.PublishUserCreated(ctx, user)
 
 	aReq.New = user
 
@@ -621,6 +623,14 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			return
 		}
 		aReq.New = suspendedUser
+		if status == database.UserStatusSuspended && user.Status != database.UserStatusSuspended {
+			api.PublishWebhookEvent(ctx, database.WebhookEventUserSuspended, codersdk.WebhookDataUser{
+				UserID:   suspendedUser.ID,
+				Username: suspendedUser.Username,
+				Email:    suspendedUser.Email,
+				Status:   codersdk.UserStatus(suspendedUser.Status),
+			})
+		}
 
 		organizations, err := userOrganizationIDs(ctx, api, user)
 		if err != nil {
@@ -867,10 +877,9 @@ func (api *API) updateSiteUserRoles(ctx context.Context, args database.UpdateUse
 		if _, ok := rbac.IsOrgRole(r); ok {
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	}
+	if err := api.validateRoleNames(ctx, args.GrantedRoles); err != nil {
+		return database.User{}, err
 	}
 
 	updatedUser, err := api.Database.UpdateUserRoles(ctx, args)
@@ -960,7 +969,7 @@ type CreateUserRequest struct {
 
 func (api *API) CreateUser(ctx context.Context, store database.Store, req CreateUserRequest) (database.User, uuid.UUID, error) {
 	var user database.User
-	return user, req.OrganizationID, store.InTx(func(tx database.Store) error {
+	err := store.InTx(func(tx database.Store) error {
 		orgRoles := make([]string, 0)
 		// If no organization is provided, create a new one for the user.
 		if req.OrganizationID == uuid.Nil {
@@ -1038,24 +1047,37 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		}
 		return nil
 	}, nil)
+	return user, req.OrganizationID, err
+}
+
+// PublishUserCreated notifies webhooks of a new user. Callers of CreateUser
+// call it once the transaction that created the user has committed, so
+// webhooks are only notified of users that exist.
+func (api *API) PublishUserCreated(ctx context.Context, user database.User) {
+	api.PublishWebhookEvent(ctx, database.WebhookEventUserCreated, codersdk.WebhookDataUser{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Status:   codersdk.UserStatus(user.Status),
+	})
 }
 
 func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	convertedUser := codersdk.User{
-		ID:              user.ID,
-		Email:           user.Email,
-		CreatedAt:       user.CreatedAt,
-		LastSeenAt:      user.LastSeenAt,
-		Username:        user.Username,
-		Status:          codersdk.UserStatus(user.Status),
-		OrganizationIDs: organizationIDs,
-		Roles:           make([]codersdk.Role, 0, len(user.RBACRoles)),
-		AvatarURL:       user.AvatarURL.String,
+		ID:                 user.ID,
+		Email:              user.Email,
+		CreatedAt:          user.CreatedAt,
+		LastSeenAt:         user.LastSeenAt,
+		Username:           user.Username,
+		Status:             codersdk.UserStatus(user.Status),
+		OrganizationIDs:    organizationIDs,
+		Roles:              make([]codersdk.Role, 0, len(user.RBACRoles)),
+		AvatarURL:          user.AvatarURL.String,
+		QuietHoursSchedule: user.QuietHoursSchedule,
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, convertRole(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, convertRoleName(roleName))
 	}
 
 	return convertedUser
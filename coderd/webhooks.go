@@ -0,0 +1,388 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/webhooks"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+// webhookDeliveriesLimit is the number of most recent deliveries returned
+// from the delivery log.
+const webhookDeliveriesLimit = 100
+
+// @Summary Get webhooks
+// @ID get-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Success 200 {array} codersdk.Webhook
+// @Router /webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWebhook) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	hooks, err := api.Database.GetWebhooks(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhooks(hooks))
+}
+
+// @Summary Get webhook by ID
+// @ID get-webhook-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [get]
+func (api *API) webhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(httpmw.WebhookParam(r)))
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param request body codersdk.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} codersdk.CreateWebhookResponse
+// @Router /webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "URL is invalid.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	events, err := convertWebhookEventsToDatabase(req.Events)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret, err = cryptorand.HexString(32)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	hook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Url:       req.URL,
+		Secret:    secret,
+		Events:    events,
+		Enabled:   true,
+		CreatedBy: apiKey.UserID,
+		CreatedAt: database.Now(),
+		UpdatedAt: database.Now(),
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = hook
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWebhookResponse{
+		Webhook: convertWebhook(hook),
+		Secret:  secret,
+	})
+}
+
+// @Summary Update webhook
+// @ID update-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param request body codersdk.UpdateWebhookRequest true "Update webhook request"
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [patch]
+func (api *API) patchWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		hook              = httpmw.WebhookParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = hook
+
+	var req codersdk.UpdateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	params := database.UpdateWebhookByIDParams{
+		ID:        hook.ID,
+		Name:      hook.Name,
+		Url:       hook.Url,
+		Events:    hook.Events,
+		Enabled:   hook.Enabled,
+		UpdatedAt: database.Now(),
+	}
+	if req.Name != nil {
+		params.Name = *req.Name
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "URL is invalid.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		params.Url = *req.URL
+	}
+	if req.Events != nil {
+		events, err := convertWebhookEventsToDatabase(*req.Events)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid events.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		params.Events = events
+	}
+	if req.Enabled != nil {
+		params.Enabled = *req.Enabled
+	}
+
+	updated, err := api.Database.UpdateWebhookByID(ctx, params)
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", params.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(updated))
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		hook              = httpmw.WebhookParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = hook
+
+	err := api.Database.DeleteWebhookByID(ctx, hook.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Webhook has been deleted!",
+	})
+}
+
+// @Summary Get webhook deliveries
+// @ID get-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		hook = httpmw.WebhookParam(r)
+	)
+
+	deliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: hook.ID,
+		LimitOpt:  webhookDeliveriesLimit,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook deliveries.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiDeliveries := make([]codersdk.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		apiDeliveries = append(apiDeliveries, convertWebhookDelivery(delivery))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiDeliveries)
+}
+
+// PublishWebhookEvent enqueues a delivery of the event to every subscribed
+// webhook. Failures are logged and never fail the caller.
+func (api *API) PublishWebhookEvent(ctx context.Context, event database.WebhookEvent, data any) {
+	err := webhooks.Enqueue(ctx, api.Database, api.Pubsub, event, data)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to enqueue webhook event",
+			slog.F("event", event), slog.Error(err))
+	}
+}
+
+func validateWebhookURL(u string) error {
+	p, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if p.Scheme != "http" && p.Scheme != "https" {
+		return xerrors.New("scheme must be http or https")
+	}
+	if p.Host == "" {
+		return xerrors.New("host must not be empty")
+	}
+	return nil
+}
+
+func convertWebhookEventsToDatabase(events []codersdk.WebhookEvent) ([]database.WebhookEvent, error) {
+	if len(events) == 0 {
+		return nil, xerrors.New("at least one event is required")
+	}
+	dbEvents := make([]database.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		dbEvent := database.WebhookEvent(event)
+		if !dbEvent.Valid() {
+			return nil, xerrors.Errorf("unknown event %q", event)
+		}
+		if slices.Contains(dbEvents, dbEvent) {
+			continue
+		}
+		dbEvents = append(dbEvents, dbEvent)
+	}
+	return dbEvents, nil
+}
+
+func convertWebhooks(hooks []database.Webhook) []codersdk.Webhook {
+	converted := make([]codersdk.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		converted = append(converted, convertWebhook(hook))
+	}
+	return converted
+}
+
+func convertWebhook(hook database.Webhook) codersdk.Webhook {
+	events := make([]codersdk.WebhookEvent, 0, len(hook.Events))
+	for _, event := range hook.Events {
+		events = append(events, codersdk.WebhookEvent(event))
+	}
+	return codersdk.Webhook{
+		ID:        hook.ID,
+		Name:      hook.Name,
+		URL:       hook.Url,
+		Events:    events,
+		Enabled:   hook.Enabled,
+		CreatedBy: hook.CreatedBy,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+	}
+}
+
+func convertWebhookDelivery(delivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	converted := codersdk.WebhookDelivery{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          codersdk.WebhookEvent(delivery.Event),
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+	}
+	if delivery.DeliveredAt.Valid {
+		converted.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	if delivery.NextAttemptAt.Valid {
+		converted.NextAttemptAt = &delivery.NextAttemptAt.Time
+	}
+	return converted
+}
@@ -0,0 +1,306 @@
+// Package webhooks delivers signed JSON payloads to admin-configured URLs
+// when workspace, template and user lifecycle events occur.
+//
+// Events are persisted as one delivery per subscribed webhook so they survive
+// restarts and can be retried. Every coderd replica runs a Dispatcher that
+// acquires due deliveries from the database and POSTs them.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/codersdk"
+)
+
+// PubsubEvent is published whenever new deliveries are enqueued, so
+// dispatchers pick them up without waiting for the next poll.
+const PubsubEvent = "webhook_deliveries"
+
+// maxRetryBackoff caps the exponential backoff between attempts.
+const maxRetryBackoff = 6 * time.Hour
+
+// Enqueue creates a pending delivery of the event for every enabled webhook
+// subscribed to it. data must be one of the codersdk.WebhookData* types.
+func Enqueue(ctx context.Context, db database.Store, ps database.Pubsub, event database.WebhookEvent, data any) error {
+	//nolint:gocritic // Webhook deliveries are created by the system, not the actor.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	webhooks, err := db.GetEnabledWebhooksByEvent(ctx, event)
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return xerrors.Errorf("marshal data: %w", err)
+	}
+	now := database.Now()
+	payload, err := json.Marshal(codersdk.WebhookPayload{
+		ID:        uuid.New(),
+		Event:     codersdk.WebhookEvent(event),
+		Timestamp: now,
+		Data:      rawData,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		_, err = db.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Payload:       payload,
+			CreatedAt:     now,
+			NextAttemptAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("insert delivery for webhook %q: %w", webhook.Name, err)
+		}
+	}
+
+	err = ps.Publish(PubsubEvent, []byte{})
+	if err != nil {
+		return xerrors.Errorf("publish: %w", err)
+	}
+	return nil
+}
+
+// Sign returns the value of the codersdk.WebhookSignatureHeader for the
+// body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Options configures the Dispatcher.
+type Options struct {
+	// Client is the HTTP client used to POST deliveries,
+	// if omitted, http.DefaultClient will be used.
+	Client *http.Client
+	// MaxAttempts is the number of times a delivery is attempted
+	// before it is marked as failed, default 5.
+	MaxAttempts int
+	// Timeout is the time to wait for a single attempt, default 10s.
+	Timeout time.Duration
+	// RetryBackoff is the time to wait before the first retry. It
+	// doubles after every failed attempt, default 30s.
+	RetryBackoff time.Duration
+	// PollInterval is the interval at which due deliveries are checked
+	// for when no new deliveries are published, default 10s.
+	PollInterval time.Duration
+}
+
+// Dispatcher delivers pending webhook deliveries.
+type Dispatcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	db     database.Store
+	log    slog.Logger
+	opts   Options
+	notify chan struct{}
+	closed chan struct{}
+}
+
+// New starts a Dispatcher. It is the caller's responsibility to call Close
+// on the returned instance.
+func New(ctx context.Context, log slog.Logger, db database.Store, ps database.Pubsub, opts Options) *Dispatcher {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = 30 * time.Second
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	//nolint:gocritic // The dispatcher delivers webhooks without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	d := &Dispatcher{
+		ctx:    ctx,
+		cancel: cancel,
+		db:     db,
+		log:    log,
+		opts:   opts,
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+
+	unsubscribe, err := ps.Subscribe(PubsubEvent, func(_ context.Context, _ []byte) {
+		select {
+		case d.notify <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		// Deliveries are still picked up by polling.
+		log.Warn(ctx, "subscribe to webhook deliveries", slog.Error(err))
+		unsubscribe = func() {}
+	}
+
+	go func() {
+		defer close(d.closed)
+		defer unsubscribe()
+		d.run()
+	}()
+	return d
+}
+
+func (d *Dispatcher) run() {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		d.dispatchDue()
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.notify:
+		}
+	}
+}
+
+// dispatchDue attempts deliveries until none are due.
+func (d *Dispatcher) dispatchDue() {
+	for {
+		now := database.Now()
+		delivery, err := d.db.AcquireWebhookDelivery(d.ctx, database.AcquireWebhookDeliveryParams{
+			// The lease outlives the attempt so no other replica can pick
+			// up the delivery while it's in flight.
+			LeaseUntil: now.Add(2 * d.opts.Timeout),
+			Now:        now,
+		})
+		if err != nil {
+			if !xerrors.Is(err, sql.ErrNoRows) && !xerrors.Is(err, context.Canceled) {
+				d.log.Error(d.ctx, "acquire webhook delivery", slog.Error(err))
+			}
+			return
+		}
+		err = d.attempt(delivery)
+		if err != nil {
+			if xerrors.Is(err, context.Canceled) {
+				return
+			}
+			d.log.Error(d.ctx, "attempt webhook delivery", slog.F("delivery_id", delivery.ID), slog.Error(err))
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(delivery database.WebhookDelivery) error {
+	webhook, err := d.db.GetWebhookByID(d.ctx, delivery.WebhookID)
+	if err != nil {
+		return xerrors.Errorf("get webhook: %w", err)
+	}
+
+	update := database.UpdateWebhookDeliveryByIDParams{
+		ID:       delivery.ID,
+		Attempts: delivery.Attempts,
+	}
+	if !webhook.Enabled {
+		// Webhooks disabled after the event was enqueued are not
+		// retried.
+		update.LastError = "webhook is disabled"
+		return d.db.UpdateWebhookDeliveryByID(d.ctx, update)
+	}
+
+	update.Attempts++
+	statusCode, err := d.send(webhook, delivery)
+	update.LastStatusCode = int32(statusCode)
+	now := database.Now()
+	switch {
+	case err == nil:
+		update.DeliveredAt = sql.NullTime{Time: now, Valid: true}
+	case xerrors.Is(err, context.Canceled) && d.ctx.Err() != nil:
+		// Shutting down, the lease expires and another replica
+		// picks it up.
+		return err
+	default:
+		update.LastError = err.Error()
+		if int(update.Attempts) < d.opts.MaxAttempts {
+			update.NextAttemptAt = sql.NullTime{Time: now.Add(d.backoff(update.Attempts)), Valid: true}
+		}
+		d.log.Debug(d.ctx, "webhook delivery failed",
+			slog.F("webhook_id", webhook.ID),
+			slog.F("delivery_id", delivery.ID),
+			slog.F("attempts", update.Attempts),
+			slog.Error(err),
+		)
+	}
+	return d.db.UpdateWebhookDeliveryByID(d.ctx, update)
+}
+
+func (d *Dispatcher) backoff(attempts int32) time.Duration {
+	backoff := d.opts.RetryBackoff
+	for i := int32(1); i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff
+}
+
+// send POSTs the delivery and returns the response status code, or 0 if no
+// response was received.
+func (d *Dispatcher) send(webhook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(codersdk.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(codersdk.WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(codersdk.WebhookSignatureHeader, Sign(webhook.Secret, delivery.Payload))
+
+	res, err := d.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// Drain a bounded amount of the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// Close stops the dispatcher and waits for in-flight deliveries.
+func (d *Dispatcher) Close() error {
+	d.cancel()
+	<-d.closed
+	return nil
+}
@@ -0,0 +1,214 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/webhooks"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+
+	db := dbfake.New()
+	ps := database.NewPubsubInMemory()
+	user := dbgen.User(t, db, database.User{})
+	subscribed := dbgen.Webhook(t, db, database.Webhook{
+		CreatedBy: user.ID,
+		Events:    []database.WebhookEvent{database.WebhookEventUserCreated},
+	})
+	_ = dbgen.Webhook(t, db, database.Webhook{
+		CreatedBy: user.ID,
+		Events:    []database.WebhookEvent{database.WebhookEventUserSuspended},
+	})
+	disabled := dbgen.Webhook(t, db, database.Webhook{
+		CreatedBy: user.ID,
+		Events:    []database.WebhookEvent{database.WebhookEventUserCreated},
+	})
+	_, err := db.UpdateWebhookByID(context.Background(), database.UpdateWebhookByIDParams{
+		ID:        disabled.ID,
+		Name:      disabled.Name,
+		Url:       disabled.Url,
+		Events:    disabled.Events,
+		Enabled:   false,
+		UpdatedAt: database.Now(),
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	published := make(chan struct{}, 1)
+	unsubscribe, err := ps.Subscribe(webhooks.PubsubEvent, func(_ context.Context, _ []byte) {
+		published <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	err = webhooks.Enqueue(ctx, db, ps, database.WebhookEventUserCreated, codersdk.WebhookDataUser{
+		UserID:   user.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-published:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for publish")
+	}
+
+	deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: subscribed.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	var payload codersdk.WebhookPayload
+	require.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+	require.Equal(t, codersdk.WebhookEventUserCreated, payload.Event)
+	var data codersdk.WebhookDataUser
+	require.NoError(t, json.Unmarshal(payload.Data, &data))
+	require.Equal(t, user.ID, data.UserID)
+
+	deliveries, err = db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: disabled.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int64
+		var signed atomic.Bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			signed.Store(r.Header.Get(codersdk.WebhookSignatureHeader) == webhooks.Sign("secret", body))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		db := dbfake.New()
+		ps := database.NewPubsubInMemory()
+		user := dbgen.User(t, db, database.User{})
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			CreatedBy: user.ID,
+			Url:       srv.URL,
+			Secret:    "secret",
+			Events:    []database.WebhookEvent{database.WebhookEventUserCreated},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		dispatcher := webhooks.New(ctx, slogtest.Make(t, nil), db, ps, webhooks.Options{
+			RetryBackoff: time.Millisecond,
+			PollInterval: testutil.IntervalFast,
+		})
+		defer dispatcher.Close()
+
+		err := webhooks.Enqueue(ctx, db, ps, database.WebhookEventUserCreated, codersdk.WebhookDataUser{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			if !assert.NoError(t, err) || !assert.Len(t, deliveries, 1) {
+				return false
+			}
+			return deliveries[0].DeliveredAt.Valid
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, deliveries[0].Attempts)
+		require.EqualValues(t, http.StatusOK, deliveries[0].LastStatusCode)
+		require.False(t, deliveries[0].NextAttemptAt.Valid)
+		require.EqualValues(t, 2, calls.Load())
+		require.True(t, signed.Load())
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		db := dbfake.New()
+		ps := database.NewPubsubInMemory()
+		user := dbgen.User(t, db, database.User{})
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			CreatedBy: user.ID,
+			Url:       srv.URL,
+			Events:    []database.WebhookEvent{database.WebhookEventUserSuspended},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		dispatcher := webhooks.New(ctx, slogtest.Make(t, nil), db, ps, webhooks.Options{
+			MaxAttempts:  3,
+			RetryBackoff: time.Millisecond,
+			PollInterval: testutil.IntervalFast,
+		})
+		defer dispatcher.Close()
+
+		err := webhooks.Enqueue(ctx, db, ps, database.WebhookEventUserSuspended, codersdk.WebhookDataUser{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			if !assert.NoError(t, err) || !assert.Len(t, deliveries, 1) {
+				return false
+			}
+			return deliveries[0].Attempts == 3 && !deliveries[0].NextAttemptAt.Valid
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+		})
+		require.NoError(t, err)
+		require.False(t, deliveries[0].DeliveredAt.Valid)
+		require.EqualValues(t, http.StatusBadGateway, deliveries[0].LastStatusCode)
+		require.NotEmpty(t, deliveries[0].LastError)
+		require.EqualValues(t, 3, calls.Load())
+	})
+}
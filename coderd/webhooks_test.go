@@ -0,0 +1,168 @@
+package coderd_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/webhooks"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		created, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "builds",
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Secret)
+		require.True(t, created.Webhook.Enabled)
+
+		hooks, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, hooks, 1)
+		require.Equal(t, created.Webhook.ID, hooks[0].ID)
+
+		enabled := false
+		events := []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated}
+		updated, err := client.UpdateWebhook(ctx, created.Webhook.ID, codersdk.UpdateWebhookRequest{
+			Enabled: &enabled,
+			Events:  &events,
+		})
+		require.NoError(t, err)
+		require.False(t, updated.Enabled)
+		require.Equal(t, events, updated.Events)
+		require.Equal(t, created.Webhook.URL, updated.URL)
+
+		err = client.DeleteWebhook(ctx, created.Webhook.ID)
+		require.NoError(t, err)
+		_, err = client.Webhook(ctx, created.Webhook.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "bad-url",
+			URL:    "ftp://example.com",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "bad-event",
+			URL:    "https://example.com",
+			Events: []codersdk.WebhookEvent{"unknown"},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "builds",
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("DeliverUserCreated", func(t *testing.T) {
+		t.Parallel()
+
+		type delivery struct {
+			header http.Header
+			body   []byte
+		}
+		received := make(chan delivery, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- delivery{header: r.Header.Clone(), body: body}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		created, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "users",
+			URL:    srv.URL,
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+			Secret: "shhh",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "shhh", created.Secret)
+
+		_, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		var got delivery
+		select {
+		case got = <-received:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for webhook delivery")
+		}
+		require.Equal(t, string(codersdk.WebhookEventUserCreated), got.header.Get(codersdk.WebhookEventHeader))
+		require.Equal(t, webhooks.Sign("shhh", got.body), got.header.Get(codersdk.WebhookSignatureHeader))
+
+		var payload codersdk.WebhookPayload
+		require.NoError(t, json.Unmarshal(got.body, &payload))
+		require.Equal(t, codersdk.WebhookEventUserCreated, payload.Event)
+		var data codersdk.WebhookDataUser
+		require.NoError(t, json.Unmarshal(payload.Data, &data))
+		require.Equal(t, user.ID, data.UserID)
+		require.Equal(t, user.Username, data.Username)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := client.WebhookDeliveries(ctx, created.Webhook.ID)
+			if !assert.NoError(t, err) || len(deliveries) != 1 {
+				return false
+			}
+			return deliveries[0].DeliveredAt != nil &&
+				deliveries[0].LastStatusCode == http.StatusNoContent &&
+				deliveries[0].ID.String() == got.header.Get(codersdk.WebhookDeliveryHeader)
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+}
@@ -0,0 +1,206 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// maxDebugLogOutputLength matches the length of the output column of
+// workspace_agent_debug_logs.
+const maxDebugLogOutputLength = 4096
+
+// @Summary Patch workspace agent debug logs
+// @ID patch-workspace-agent-debug-logs
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PatchDebugLogs true "Debug logs"
+// @Success 200 {object} codersdk.Response
+// @Router /workspaceagents/me/debug-logs [patch]
+// @x-apidocgen {"skip": true}
+func (api *API) patchWorkspaceAgentDebugLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PatchDebugLogs
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.Logs) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "No logs provided.",
+		})
+		return
+	}
+	createdAt := make([]time.Time, 0, len(req.Logs))
+	output := make([]string, 0, len(req.Logs))
+	level := make([]database.LogLevel, 0, len(req.Logs))
+	for _, log := range req.Logs {
+		if utf8.RuneCountInString(log.Output) > maxDebugLogOutputLength {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Log output too long.",
+				Detail:  fmt.Sprintf("log output must be at most %d characters", maxDebugLogOutputLength),
+			})
+			return
+		}
+		parsedLevel := database.LogLevel(log.Level)
+		if !parsedLevel.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid log level provided.",
+				Detail:  fmt.Sprintf("invalid log level: %q", log.Level),
+			})
+			return
+		}
+		createdAt = append(createdAt, log.CreatedAt)
+		output = append(output, log.Output)
+		level = append(level, parsedLevel)
+	}
+	_, err := api.Database.InsertWorkspaceAgentDebugLogs(ctx, database.InsertWorkspaceAgentDebugLogsParams{
+		AgentID:   workspaceAgent.ID,
+		CreatedAt: createdAt,
+		Output:    output,
+		Level:     level,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to upload debug logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, nil)
+}
+
+// workspaceAgentDebugLogs returns the logs of the agent itself. They can
+// contain details about the deployment, so only users with access to debug
+// info can read them.
+//
+// @Summary Get debug logs by workspace agent
+// @ID get-debug-logs-by-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param after query int false "After log id"
+// @Success 200 {array} codersdk.WorkspaceAgentDebugLog
+// @Router /workspaceagents/{workspaceagent}/debug-logs [get]
+func (api *API) workspaceAgentDebugLogs(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspaceAgent = httpmw.WorkspaceAgentParam(r)
+		afterRaw       = r.URL.Query().Get("after")
+	)
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceDebugInfo) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var after int64
+	if afterRaw != "" {
+		var err error
+		after, err = strconv.ParseInt(afterRaw, 10, 64)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query param \"after\" must be an integer.",
+				Validations: []codersdk.ValidationError{
+					{Field: "after", Detail: "Must be an integer"},
+				},
+			})
+			return
+		}
+	}
+
+	logs, err := api.Database.GetWorkspaceAgentDebugLogsAfter(ctx, database.GetWorkspaceAgentDebugLogsAfterParams{
+		AgentID:      workspaceAgent.ID,
+		CreatedAfter: after,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching debug logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceAgentDebugLogs(logs))
+}
+
+// @Summary Flush debug logs of workspace agent
+// @ID flush-debug-logs-of-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/debug-logs/flush [post]
+func (api *API) postWorkspaceAgentDebugLogsFlush(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceDebugInfo) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	apiAgent, err := convertWorkspaceAgent(
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.workspaceAgentCache.Acquire(workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	err = agentConn.FlushDebugLogs(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error flushing debug logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertWorkspaceAgentDebugLogs(logs []database.WorkspaceAgentDebugLog) []codersdk.WorkspaceAgentDebugLog {
+	sdk := make([]codersdk.WorkspaceAgentDebugLog, 0, len(logs))
+	for _, log := range logs {
+		sdk = append(sdk, codersdk.WorkspaceAgentDebugLog{
+			ID:        log.ID,
+			CreatedAt: log.CreatedAt,
+			Output:    log.Output,
+			Level:     codersdk.LogLevel(log.Level),
+		})
+	}
+	return sdk
+}
@@ -0,0 +1,155 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceAgentDebugLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		agentID := build.Resources[0].Agents[0].ID
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		err := agentClient.PatchDebugLogs(ctx, agentsdk.PatchDebugLogs{
+			Logs: []agentsdk.DebugLog{{
+				CreatedAt: database.Now(),
+				Output:    "first",
+				Level:     codersdk.LogLevelInfo,
+			}, {
+				CreatedAt: database.Now(),
+				Output:    "second",
+				Level:     codersdk.LogLevelError,
+			}},
+		})
+		require.NoError(t, err)
+
+		logs, err := client.WorkspaceAgentDebugLogs(ctx, agentID, 0)
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		require.Equal(t, "first", logs[0].Output)
+		require.Equal(t, codersdk.LogLevelInfo, logs[0].Level)
+		require.Equal(t, "second", logs[1].Output)
+		require.Equal(t, codersdk.LogLevelError, logs[1].Level)
+
+		logs, err = client.WorkspaceAgentDebugLogs(ctx, agentID, logs[0].ID)
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, "second", logs[0].Output)
+
+		// The logs can contain deployment details, so even the owner of
+		// the workspace can't read them.
+		_, err = member.WorkspaceAgentDebugLogs(ctx, agentID, 0)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidLevel", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		err := agentClient.PatchDebugLogs(ctx, agentsdk.PatchDebugLogs{
+			Logs: []agentsdk.DebugLog{{
+				CreatedAt: database.Now(),
+				Output:    "testing",
+				Level:     "verbose",
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		sink := agent.NewDebugLogSink(1000)
+		agentCloser := agent.New(agent.Options{
+			Client:    agentClient,
+			Logger:    slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug).AppendSinks(sink),
+			DebugLogs: sink,
+		})
+		defer func() {
+			_ = agentCloser.Close()
+		}()
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		agentID := resources[0].Agents[0].ID
+
+		// Nothing is uploaded until the logs are flushed.
+		logs, err := client.WorkspaceAgentDebugLogs(ctx, agentID, 0)
+		require.NoError(t, err)
+		require.Empty(t, logs)
+
+		err = client.FlushWorkspaceAgentDebugLogs(ctx, agentID)
+		require.NoError(t, err)
+		logs, err = client.WorkspaceAgentDebugLogs(ctx, agentID, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, logs)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/netip"
@@ -22,6 +23,7 @@ import (
 	"github.com/bep/debounce"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
 	"golang.org/x/xerrors"
@@ -61,8 +63,17 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	// nolint:gocritic // The workspace agent is already fetched.
+	dbScripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), convertWorkspaceAgentScripts(dbScripts), api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -87,7 +98,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -115,6 +126,16 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// nolint:gocritic // Agents can read their own scripts.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -147,6 +168,20 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	// nolint:gocritic // The agent scope doesn't include the template.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var sshCAPublicKey string
+	if api.SSHCAKey != nil {
+		sshCAPublicKey = string(gossh.MarshalAuthorizedKey(api.SSHCAKey.PublicKey()))
+	}
 
 	vscodeProxyURI := strings.ReplaceAll(api.AppHostname, "*",
 		fmt.Sprintf("%s://{{port}}--%s--%s--%s",
@@ -172,6 +207,10 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		ShutdownScript:        apiAgent.ShutdownScript,
 		ShutdownScriptTimeout: time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
 		Metadata:              convertWorkspaceAgentMetadataDesc(metadata),
+		Scripts:               convertWorkspaceAgentScripts(scripts),
+		SessionRecording:      template.SessionRecordingEnabled,
+		WorkspaceID:           workspace.ID,
+		SSHCAPublicKey:        sshCAPublicKey,
 	})
 }
 
@@ -189,7 +228,7 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -254,6 +293,12 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 		})
 		return
 	}
+	if req.ScriptID != uuid.Nil {
+		_, ok := api.workspaceAgentScript(ctx, rw, workspaceAgent.ID, req.ScriptID)
+		if !ok {
+			return
+		}
+	}
 	createdAt := make([]time.Time, 0)
 	output := make([]string, 0)
 	level := make([]database.LogLevel, 0)
@@ -278,6 +323,7 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 	}
 	logs, err := api.Database.InsertWorkspaceAgentStartupLogs(ctx, database.InsertWorkspaceAgentStartupLogsParams{
 		AgentID:      workspaceAgent.ID,
+		ScriptID:     req.ScriptID,
 		CreatedAt:    createdAt,
 		Output:       output,
 		Level:        level,
@@ -465,7 +511,7 @@ func (api *API) workspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Reques
 	if err != nil {
 		return
 	}
-	if workspaceAgent.LifecycleState == database.WorkspaceAgentLifecycleStateReady {
+	if !codersdk.WorkspaceAgentLifecycle(workspaceAgent.LifecycleState).Starting() {
 		// The startup script has finished running, so we can close the connection.
 		return
 	}
@@ -475,8 +521,15 @@ func (api *API) workspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Reques
 		endOfLogs     atomic.Bool
 		lastSentLogID atomic.Int64
 	)
+	if len(logs) > 0 {
+		lastSentLogID.Store(logs[len(logs)-1].ID)
+	}
+	lastEncodedLogID := lastSentLogID.Load()
 
 	sendLogs := func(logs []database.WorkspaceAgentStartupLog) {
+		if len(logs) == 0 {
+			return
+		}
 		select {
 		case bufferedLogs <- logs:
 			lastSentLogID.Store(logs[len(logs)-1].ID)
@@ -510,8 +563,7 @@ func (api *API) workspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Reques
 				sendLogs(logs)
 			}
 
-			if jlMsg.EndOfLogs {
-				endOfLogs.Store(true)
+			if jlMsg.EndOfLogs && endOfLogs.CompareAndSwap(false, true) {
 				logs, err := api.Database.GetWorkspaceAgentStartupLogsAfter(dbauthz.As(ctx, actor), database.GetWorkspaceAgentStartupLogsAfterParams{
 					AgentID:      workspaceAgent.ID,
 					CreatedAfter: lastSentLogID.Load(),
@@ -534,6 +586,26 @@ func (api *API) workspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Reques
 	}
 	defer closeSubscribe()
 
+	// The startup script may have finished before we subscribed, in which
+	// case the end of logs message was missed.
+	workspaceAgent, err = api.Database.GetWorkspaceAgentByID(ctx, workspaceAgent.ID)
+	if err != nil {
+		logger.Warn(ctx, "failed to get workspace agent", slog.Error(err))
+		return
+	}
+	if !codersdk.WorkspaceAgentLifecycle(workspaceAgent.LifecycleState).Starting() && endOfLogs.CompareAndSwap(false, true) {
+		logs, err := api.Database.GetWorkspaceAgentStartupLogsAfter(ctx, database.GetWorkspaceAgentStartupLogsAfterParams{
+			AgentID:      workspaceAgent.ID,
+			CreatedAfter: lastSentLogID.Load(),
+		})
+		if err != nil {
+			logger.Warn(ctx, "get workspace agent startup logs after", slog.Error(err))
+			return
+		}
+		sendLogs(logs)
+		bufferedLogs <- nil
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -545,7 +617,19 @@ func (api *API) workspaceAgentStartupLogs(rw http.ResponseWriter, r *http.Reques
 				logger.Debug(context.Background(), "reached the end of published logs")
 				return
 			}
-			err = encoder.Encode(convertWorkspaceAgentStartupLogs(logs))
+			// Notifications are handled concurrently, so the same logs
+			// may be fetched more than once.
+			unsent := make([]database.WorkspaceAgentStartupLog, 0, len(logs))
+			for _, log := range logs {
+				if log.ID > lastEncodedLogID {
+					unsent = append(unsent, log)
+					lastEncodedLogID = log.ID
+				}
+			}
+			if len(unsent) == 0 {
+				continue
+			}
+			err = encoder.Encode(convertWorkspaceAgentStartupLogs(unsent))
 			if err != nil {
 				return
 			}
@@ -566,7 +650,7 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -719,6 +803,64 @@ func (api *API) dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.Workspac
 	return agentConn, nil
 }
 
+// @Summary Get reconnecting PTY sessions of workspace agent
+// @ID get-reconnecting-pty-sessions-of-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentReconnectingPTYsResponse
+// @Router /workspaceagents/{workspaceagent}/reconnecting-ptys [get]
+func (api *API) workspaceAgentReconnectingPTYs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	workspace := httpmw.WorkspaceParam(r)
+	// Anyone who can list the sessions can attach to them.
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	apiAgent, err := convertWorkspaceAgent(
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.workspaceAgentCache.Acquire(workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	sessions, err := agentConn.ReconnectingPTYs(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching reconnecting PTY sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sessions)
+}
+
 // @Summary Get connection info for workspace agent
 // @ID get-connection-info-for-workspace-agent
 // @Security CoderSessionToken
@@ -1042,13 +1184,64 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	go httpapi.Heartbeat(ctx, conn)
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	var clientConn net.Conn = wsNetConn
+	// Workspace proxies coordinate on behalf of many users, so only the
+	// addresses of users are remembered.
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		nodesConn := newClientNodesConn(wsNetConn, func(node tailnet.Node) {
+			api.connectionActors.rememberAddrs(workspaceAgent.ID, apiKey.UserID, node.Addresses)
+		})
+		defer nodesConn.Close()
+		clientConn = nodesConn
+	}
+	err = (*api.TailnetCoordinator.Load()).ServeClient(clientConn, uuid.New(), workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
 	}
 }
 
+// clientNodesConn passes every node a client sends to the coordinator to a
+// callback as well.
+type clientNodesConn struct {
+	net.Conn
+	writer *io.PipeWriter
+}
+
+func newClientNodesConn(conn net.Conn, onNode func(node tailnet.Node)) *clientNodesConn {
+	reader, writer := io.Pipe()
+	go func() {
+		decoder := json.NewDecoder(reader)
+		for {
+			var node tailnet.Node
+			err := decoder.Decode(&node)
+			if err != nil {
+				// Unblocks reads of the connection if decoding failed.
+				_ = reader.CloseWithError(err)
+				return
+			}
+			onNode(node)
+		}
+	}()
+	return &clientNodesConn{
+		Conn:   conn,
+		writer: writer,
+	}
+}
+
+func (c *clientNodesConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		_, _ = c.writer.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *clientNodesConn) Close() error {
+	_ = c.writer.Close()
+	return c.Conn.Close()
+}
+
 func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 	apps := make([]codersdk.WorkspaceApp, 0)
 	for _, dbApp := range dbApps {
@@ -1063,9 +1256,14 @@ func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 			Subdomain:    dbApp.Subdomain,
 			SharingLevel: codersdk.WorkspaceAppSharingLevel(dbApp.SharingLevel),
 			Healthcheck: codersdk.Healthcheck{
-				URL:       dbApp.HealthcheckUrl,
-				Interval:  dbApp.HealthcheckInterval,
-				Threshold: dbApp.HealthcheckThreshold,
+				Type:              codersdk.WorkspaceAppHealthcheckType(dbApp.HealthcheckType),
+				URL:               dbApp.HealthcheckUrl,
+				Address:           dbApp.HealthcheckAddress,
+				Command:           dbApp.HealthcheckCommand,
+				ExpectedExitCodes: dbApp.HealthcheckExpectedExitCodes,
+				OutputRegex:       dbApp.HealthcheckOutputRegex,
+				Interval:          dbApp.HealthcheckInterval,
+				Threshold:         dbApp.HealthcheckThreshold,
 			},
 			Health: codersdk.WorkspaceAppHealth(dbApp.Health),
 		})
@@ -1087,7 +1285,38 @@ func convertWorkspaceAgentMetadataDesc(mds []database.WorkspaceAgentMetadatum) [
 	return metadata
 }
 
-func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
+func convertWorkspaceAgentScripts(dbScripts []database.WorkspaceAgentScript) []codersdk.WorkspaceAgentScript {
+	// An empty array is easier for clients to handle than a null.
+	scripts := make([]codersdk.WorkspaceAgentScript, 0, len(dbScripts))
+	for _, script := range dbScripts {
+		converted := codersdk.WorkspaceAgentScript{
+			ID:               script.ID,
+			DisplayName:      script.DisplayName,
+			Script:           script.Script,
+			Cron:             script.Cron,
+			RunOrder:         script.RunOrder,
+			StartBlocksLogin: script.StartBlocksLogin,
+			RunOnStart:       script.RunOnStart,
+			RunOnStop:        script.RunOnStop,
+			TimeoutSeconds:   script.TimeoutSeconds,
+			LogPath:          script.LogPath,
+			Status:           codersdk.WorkspaceAgentScriptStatus(script.Status),
+			ExitCode:         script.ExitCode,
+		}
+		if script.StartedAt.Valid {
+			startedAt := script.StartedAt.Time
+			converted.StartedAt = &startedAt
+		}
+		if script.CompletedAt.Valid {
+			completedAt := script.CompletedAt.Time
+			converted.CompletedAt = &completedAt
+		}
+		scripts = append(scripts, converted)
+	}
+	return scripts
+}
+
+func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, scripts []codersdk.WorkspaceAgentScript, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
 	var envs map[string]string
 	if dbAgent.EnvironmentVariables.Valid {
 		err := json.Unmarshal(dbAgent.EnvironmentVariables.RawMessage, &envs)
@@ -1116,6 +1345,7 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		Directory:                    dbAgent.Directory,
 		ExpandedDirectory:            dbAgent.ExpandedDirectory,
 		Apps:                         apps,
+		Scripts:                      scripts,
 		ConnectionTimeoutSeconds:     dbAgent.ConnectionTimeoutSeconds,
 		TroubleshootingURL:           troubleshootingURL,
 		LifecycleState:               codersdk.WorkspaceAgentLifecycle(dbAgent.LifecycleState),
@@ -1124,6 +1354,9 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		ShutdownScript:               dbAgent.ShutdownScript.String,
 		ShutdownScriptTimeoutSeconds: dbAgent.ShutdownScriptTimeoutSeconds,
 	}
+	if dbAgent.ParentID.Valid {
+		workspaceAgent.ParentID = &dbAgent.ParentID.UUID
+	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
 		workspaceAgent.DERPLatency = map[string]codersdk.DERPRegion{}
@@ -1212,6 +1445,11 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		payload = json.RawMessage("{}")
 	}
 
+	var resources agentsdk.ResourceUsage
+	if req.Resources != nil {
+		resources = *req.Resources
+	}
+
 	now := database.Now()
 	_, err = api.Database.InsertWorkspaceAgentStat(ctx, database.InsertWorkspaceAgentStatParams{
 		ID:                          uuid.New(),
@@ -1231,6 +1469,14 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		SessionCountReconnectingPTY: req.SessionCountReconnectingPTY,
 		SessionCountSSH:             req.SessionCountSSH,
 		ConnectionMedianLatencyMS:   req.ConnectionMedianLatencyMS,
+		CPUUsedCores:                resources.CPUUsedCores,
+		CPULimitCores:               resources.CPULimitCores,
+		MemoryUsedBytes:             resources.MemoryUsedBytes,
+		MemoryLimitBytes:            resources.MemoryLimitBytes,
+		DiskUsedBytes:               resources.DiskUsedBytes,
+		DiskTotalBytes:              resources.DiskTotalBytes,
+		NetworkRxBytes:              resources.NetworkRxBytes,
+		NetworkTxBytes:              resources.NetworkTxBytes,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -1336,6 +1582,216 @@ func (api *API) workspaceAgentPostMetadata(rw http.ResponseWriter, r *http.Reque
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Submit workspace agent script status
+// @ID submit-workspace-agent-script-status
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostScriptStatusRequest true "Script status request"
+// @Param script path string true "Script ID" format(uuid)
+// @Success 204 "Success"
+// @Router /workspaceagents/me/scripts/{script}/status [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostScriptStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostScriptStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	status := database.WorkspaceAgentScriptStatus(req.Status)
+	if !status.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script status provided.",
+			Detail:  fmt.Sprintf("invalid status: %q", req.Status),
+		})
+		return
+	}
+	scriptID, err := uuid.Parse(chi.URLParam(r, "script"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	script, ok := api.workspaceAgentScript(ctx, rw, workspaceAgent.ID, scriptID)
+	if !ok {
+		return
+	}
+
+	var startedAt, completedAt sql.NullTime
+	if req.StartedAt != nil {
+		startedAt = sql.NullTime{Time: *req.StartedAt, Valid: true}
+	}
+	if req.CompletedAt != nil {
+		completedAt = sql.NullTime{Time: *req.CompletedAt, Valid: true}
+	}
+	err = api.Database.UpdateWorkspaceAgentScriptStatus(ctx, database.UpdateWorkspaceAgentScriptStatusParams{
+		ID:               script.ID,
+		WorkspaceAgentID: workspaceAgent.ID,
+		Status:           status,
+		ExitCode:         req.ExitCode,
+		StartedAt:        startedAt,
+		CompletedAt:      completedAt,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// workspaceAgentScript fetches a script of the agent, writing a 404 if the
+// script belongs to a different agent.
+func (api *API) workspaceAgentScript(ctx context.Context, rw http.ResponseWriter, agentID, scriptID uuid.UUID) (database.WorkspaceAgentScript, bool) {
+	// nolint:gocritic // Agents can read their own scripts.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{agentID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceAgentScript{}, false
+	}
+	for _, script := range scripts {
+		if script.ID == scriptID {
+			return script, true
+		}
+	}
+	httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+		Message: fmt.Sprintf("Script %q not found.", scriptID),
+	})
+	return database.WorkspaceAgentScript{}, false
+}
+
+// @Summary Register workspace agent dev container
+// @ID register-workspace-agent-dev-container
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostDevcontainerRequest true "Dev container"
+// @Success 200 {object} agentsdk.PostDevcontainerResponse
+// @Router /workspaceagents/me/devcontainers [post]
+func (api *API) workspaceAgentPostDevcontainer(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostDevcontainerRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if workspaceAgent.ParentID.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Agents of dev containers can't register dev containers.",
+		})
+		return
+	}
+
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resource.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// nolint:gocritic // Agents can read the agents of their own build.
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(dbauthz.AsSystemRestricted(ctx), resource.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	// nolint:gocritic // Agents can read the agents of their own build.
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Agents restart their dev containers with the agent they registered
+	// before, since the name of an agent is unique within the workspace.
+	for _, agent := range agents {
+		if agent.Name != req.Name {
+			continue
+		}
+		if agent.ParentID.Valid && agent.ParentID.UUID == workspaceAgent.ID {
+			httpapi.Write(ctx, rw, http.StatusOK, agentsdk.PostDevcontainerResponse{
+				AgentID:   agent.ID,
+				AuthToken: agent.AuthToken,
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An agent named %q already exists in the workspace.", req.Name),
+		})
+		return
+	}
+
+	// nolint:gocritic // Agents can't create agents, the dev container
+	// agent belongs to the same resource as its parent.
+	agent, err := api.Database.InsertWorkspaceAgent(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceAgentParams{
+		ID:                       uuid.New(),
+		CreatedAt:                database.Now(),
+		UpdatedAt:                database.Now(),
+		Name:                     req.Name,
+		ResourceID:               workspaceAgent.ResourceID,
+		AuthToken:                uuid.New(),
+		Architecture:             workspaceAgent.Architecture,
+		EnvironmentVariables:     workspaceAgent.EnvironmentVariables,
+		OperatingSystem:          workspaceAgent.OperatingSystem,
+		Directory:                req.Directory,
+		ConnectionTimeoutSeconds: workspaceAgent.ConnectionTimeoutSeconds,
+		TroubleshootingURL:       workspaceAgent.TroubleshootingURL,
+		LoginBeforeReady:         workspaceAgent.LoginBeforeReady,
+		ParentID:                 uuid.NullUUID{UUID: workspaceAgent.ID, Valid: true},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting dev container agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.PostDevcontainerResponse{
+		AgentID:   agent.ID,
+		AuthToken: agent.AuthToken,
+	})
+}
+
 // @Summary Watch for workspace agent metadata updates
 // @ID watch-for-workspace-agent-metadata-updates
 // @Security CoderSessionToken
@@ -1513,9 +1969,22 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// The time the agent starts and finishes running the startup script is
+	// recorded, for the timings of the workspace build.
+	startedAt, readyAt := workspaceAgent.StartedAt, workspaceAgent.ReadyAt
+	switch lifecycleState {
+	case database.WorkspaceAgentLifecycleStateStarting:
+		startedAt = sql.NullTime{Time: database.Now(), Valid: true}
+		readyAt = sql.NullTime{}
+	case database.WorkspaceAgentLifecycleStateReady, database.WorkspaceAgentLifecycleStateStartError:
+		readyAt = sql.NullTime{Time: database.Now(), Valid: true}
+	}
+
 	err = api.Database.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
 		ID:             workspaceAgent.ID,
 		LifecycleState: lifecycleState,
+		StartedAt:      startedAt,
+		ReadyAt:        readyAt,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -1523,6 +1992,22 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 	}
 	api.publishWorkspaceUpdate(ctx, workspace.ID)
 
+	// The agent flushes the startup logs before reporting that the startup
+	// script has finished, so followers can stop waiting for more logs.
+	if !codersdk.WorkspaceAgentLifecycle(lifecycleState).Starting() {
+		data, err := json.Marshal(agentsdk.StartupLogsNotifyMessage{
+			EndOfLogs: true,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		err = api.Pubsub.Publish(agentsdk.StartupLogsNotifyChannel(workspaceAgent.ID), data)
+		if err != nil {
+			api.Logger.Warn(ctx, "failed to publish end of startup logs", slog.Error(err))
+		}
+	}
+
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
@@ -1578,7 +2063,7 @@ func (api *API) postWorkspaceAppHealth(rw http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		if old.HealthcheckUrl == "" {
+		if old.Health == database.WorkspaceAppHealthDisabled {
 			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
 				Message: "Error setting workspace app health",
 				Detail:  xerrors.Errorf("health checking is disabled for workspace app %s", id).Error(),
@@ -1985,5 +2470,6 @@ func convertWorkspaceAgentStartupLog(log database.WorkspaceAgentStartupLog) code
 		CreatedAt: log.CreatedAt,
 		Output:    log.Output,
 		Level:     codersdk.LogLevel(log.Level),
+		ScriptID:  log.ScriptID,
 	}
 }
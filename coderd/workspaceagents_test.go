@@ -16,6 +16,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
 
 	"cdr.dev/slog"
@@ -232,6 +233,73 @@ func TestWorkspaceAgentStartupLogs(t *testing.T) {
 		require.Len(t, logChunk, 1)
 		require.Equal(t, "testing", logChunk[0].Output)
 	})
+	t.Run("ClosesWhenReady", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		err := agentClient.PostLifecycle(ctx, agentsdk.PostLifecycleRequest{
+			State: codersdk.WorkspaceAgentLifecycleStarting,
+		})
+		require.NoError(t, err)
+
+		logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, build.Resources[0].Agents[0].ID, 0)
+		require.NoError(t, err)
+		defer func() {
+			_ = closer.Close()
+		}()
+		// The logs that exist when following starts are sent first.
+		select {
+		case <-ctx.Done():
+		case <-logs:
+		}
+		require.NoError(t, ctx.Err())
+
+		err = agentClient.PatchStartupLogs(ctx, agentsdk.PatchStartupLogs{
+			Logs: []agentsdk.StartupLog{{
+				CreatedAt: database.Now(),
+				Output:    "testing",
+			}},
+		})
+		require.NoError(t, err)
+		err = agentClient.PostLifecycle(ctx, agentsdk.PostLifecycleRequest{
+			State: codersdk.WorkspaceAgentLifecycleReady,
+		})
+		require.NoError(t, err)
+
+		var output []string
+		for {
+			var logChunk []codersdk.WorkspaceAgentStartupLog
+			var ok bool
+			select {
+			case <-ctx.Done():
+			case logChunk, ok = <-logs:
+			}
+			require.NoError(t, ctx.Err())
+			if !ok {
+				break
+			}
+			for _, log := range logChunk {
+				output = append(output, log.Output)
+			}
+		}
+		require.Equal(t, []string{"testing"}, output)
+	})
 	t.Run("PublishesOnOverflow", func(t *testing.T) {
 		t.Parallel()
 		ctx := testutil.Context(t, testutil.WaitMedium)
@@ -296,6 +364,242 @@ func TestWorkspaceAgentStartupLogs(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentScripts(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Scripts: []*proto.Script{{
+								DisplayName:      "Install",
+								Script:           "echo install",
+								RunOrder:         1,
+								RunOnStart:       true,
+								StartBlocksLogin: true,
+								TimeoutSeconds:   60,
+							}, {
+								DisplayName: "Backup",
+								Script:      "echo backup",
+								Cron:        "0 * * * *",
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	manifest, err := agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.Len(t, manifest.Scripts, 2)
+	// Scripts are ordered by run order.
+	require.Equal(t, "Backup", manifest.Scripts[0].DisplayName)
+	require.Equal(t, "0 * * * *", manifest.Scripts[0].Cron)
+	install := manifest.Scripts[1]
+	require.Equal(t, "Install", install.DisplayName)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusPending, install.Status)
+
+	startedAt := database.Now()
+	completedAt := startedAt.Add(time.Second)
+	err = agentClient.PostScriptStatus(ctx, install.ID, agentsdk.PostScriptStatusRequest{
+		Status:      codersdk.WorkspaceAgentScriptStatusError,
+		ExitCode:    2,
+		StartedAt:   &startedAt,
+		CompletedAt: &completedAt,
+	})
+	require.NoError(t, err)
+
+	err = agentClient.PatchStartupLogs(ctx, agentsdk.PatchStartupLogs{
+		ScriptID: install.ID,
+		Logs: []agentsdk.StartupLog{{
+			CreatedAt: database.Now(),
+			Output:    "install",
+		}},
+	})
+	require.NoError(t, err)
+
+	workspaceAgent, err := client.WorkspaceAgent(ctx, build.Resources[0].Agents[0].ID)
+	require.NoError(t, err)
+	require.Len(t, workspaceAgent.Scripts, 2)
+	got := workspaceAgent.Scripts[1]
+	require.Equal(t, install.ID, got.ID)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusError, got.Status)
+	require.EqualValues(t, 2, got.ExitCode)
+	require.NotNil(t, got.CompletedAt)
+
+	logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, workspaceAgent.ID, 0)
+	require.NoError(t, err)
+	defer func() {
+		_ = closer.Close()
+	}()
+	var logChunk []codersdk.WorkspaceAgentStartupLog
+	select {
+	case <-ctx.Done():
+	case logChunk = <-logs:
+	}
+	require.NoError(t, ctx.Err())
+	require.Len(t, logChunk, 1)
+	require.Equal(t, install.ID, logChunk[0].ScriptID)
+
+	t.Run("UnknownScript", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := agentClient.PostScriptStatus(ctx, uuid.New(), agentsdk.PostScriptStatusRequest{
+			Status: codersdk.WorkspaceAgentScriptStatusRunning,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		err = agentClient.PatchStartupLogs(ctx, agentsdk.PatchStartupLogs{
+			ScriptID: uuid.New(),
+			Logs: []agentsdk.StartupLog{{
+				CreatedAt: database.Now(),
+				Output:    "unknown",
+			}},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+	t.Run("InvalidStatus", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := agentClient.PostScriptStatus(ctx, install.ID, agentsdk.PostScriptStatusRequest{
+			Status: "bananas",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceAgentDevcontainers(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: "main",
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	parentID := build.Resources[0].Agents[0].ID
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	devcontainer, err := agentClient.PostDevcontainer(ctx, agentsdk.PostDevcontainerRequest{
+		Name:      "frontend",
+		Directory: "/workspaces/frontend",
+	})
+	require.NoError(t, err)
+
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	agents := workspace.LatestBuild.Resources[0].Agents
+	require.Len(t, agents, 2)
+	idx := slices.IndexFunc(agents, func(agent codersdk.WorkspaceAgent) bool { return agent.Name == "frontend" })
+	require.NotEqual(t, -1, idx)
+	require.Equal(t, devcontainer.AgentID, agents[idx].ID)
+	require.Equal(t, "/workspaces/frontend", agents[idx].Directory)
+	require.NotNil(t, agents[idx].ParentID)
+	require.Equal(t, parentID, *agents[idx].ParentID)
+
+	// The dev container agent authenticates with its own token.
+	devcontainerClient := agentsdk.New(client.URL)
+	devcontainerClient.SetSessionToken(devcontainer.AuthToken.String())
+	manifest, err := devcontainerClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "/workspaces/frontend", manifest.Directory)
+
+	t.Run("Existing", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		again, err := agentClient.PostDevcontainer(ctx, agentsdk.PostDevcontainerRequest{
+			Name:      "frontend",
+			Directory: "/workspaces/frontend",
+		})
+		require.NoError(t, err)
+		require.Equal(t, devcontainer, again)
+	})
+	t.Run("NameConflict", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := agentClient.PostDevcontainer(ctx, agentsdk.PostDevcontainerRequest{
+			Name: "main",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+	t.Run("InvalidName", func(t *testing.T) {
+		t.Parallel()
+		// The name becomes part of log paths and agent names, so the server
+		// rejects names that don't pass httpapi.NameValid.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := agentClient.PostDevcontainer(ctx, agentsdk.PostDevcontainerRequest{
+			Name: "../../etc",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+	t.Run("Nested", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := devcontainerClient.PostDevcontainer(ctx, agentsdk.PostDevcontainerRequest{
+			Name: "nested",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceAgentListen(t *testing.T) {
 	t.Parallel()
 
@@ -689,6 +993,62 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentReconnectingPTYs(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	res, err := client.WorkspaceAgentReconnectingPTYs(ctx, agentID)
+	require.NoError(t, err)
+	require.Empty(t, res.Sessions)
+
+	// Sessions of the web terminal are listed while they are attached and
+	// after they were detached.
+	id := uuid.New()
+	conn, err := client.WorkspaceAgentReconnectingPTY(ctx, agentID, id, 80, 80, "sleep 60")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		res, err = client.WorkspaceAgentReconnectingPTYs(ctx, agentID)
+		return err == nil && len(res.Sessions) == 1 && res.Sessions[0].ActiveConnections == 1
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, codersdk.WorkspaceAgentReconnectingPTY{
+		ID:                id,
+		Backend:           "buffered",
+		Command:           "sleep 60",
+		ActiveConnections: 1,
+	}, res.Sessions[0])
+
+	_ = conn.Close()
+	require.Eventually(t, func() bool {
+		res, err = client.WorkspaceAgentReconnectingPTYs(ctx, agentID)
+		return err == nil && len(res.Sessions) == 1 && res.Sessions[0].ActiveConnections == 0
+	}, testutil.WaitLong, testutil.IntervalFast)
+}
+
 func TestWorkspaceAgentAppHealth(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{
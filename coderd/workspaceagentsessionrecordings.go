@@ -0,0 +1,177 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// sessionRecordingMimeType is the media type of asciicast v2 recordings.
+const sessionRecordingMimeType = "application/x-asciicast"
+
+// postWorkspaceAgentSessionRecording stores the recording of an interactive
+// session in the file store, owned by the workspace owner, and audits the
+// connection with a link to the recording.
+//
+// @Summary Submit workspace agent session recording
+// @ID submit-workspace-agent-session-recording
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostSessionRecordingRequest true "Session recording"
+// @Success 201 {object} agentsdk.PostSessionRecordingResponse
+// @Router /workspaceagents/me/session-recordings [post]
+// @x-apidocgen {"skip": true}
+func (api *API) postWorkspaceAgentSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	// The recording is base64 encoded in the request body.
+	r.Body = http.MaxBytesReader(rw, r.Body, 2*agentsdk.MaxSessionRecordingSize)
+	var req agentsdk.PostSessionRecordingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	switch req.Type {
+	case agentsdk.SessionRecordingTypeSSH, agentsdk.SessionRecordingTypeReconnectingPTY:
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid session type.",
+			Detail:  fmt.Sprintf("unknown session type %q", req.Type),
+		})
+		return
+	}
+	if len(req.Recording) == 0 || len(req.Recording) > agentsdk.MaxSessionRecordingSize {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Recording must be between 1 and %d bytes.", agentsdk.MaxSessionRecordingSize),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// nolint:gocritic // The agent scope doesn't include the template.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !template.SessionRecordingEnabled {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Session recording is disabled for the template of this workspace.",
+		})
+		return
+	}
+
+	owner, err := api.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace owner.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	file, err := api.insertSessionRecording(ctx, workspace.OwnerID, req.Recording)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	additionalFields, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName:             workspace.Name,
+		WorkspaceOwner:            owner.Username,
+		AgentName:                 workspaceAgent.Name,
+		SessionType:               string(req.Type),
+		SessionRecordingID:        file.ID.String(),
+		SessionRecordingTruncated: req.Truncated,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error marshaling audit fields.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The agent only knows the address or reconnecting PTY of the session,
+	// so the user is resolved from the connections brokered by coderd.
+	// Connections that weren't brokered by this replica are attributed to
+	// the workspace owner.
+	userID := workspace.OwnerID
+	if actorID, ok := api.connectionActors.resolve(workspaceAgent.ID, req.RemoteAddr, req.ReconnectingPTYID); ok {
+		userID = actorID
+	}
+	audit.BuildAudit(ctx, &audit.BuildAuditParams[database.Workspace]{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		UserID:           userID,
+		JobID:            httpmw.RequestID(r),
+		Status:           http.StatusOK,
+		Action:           database.AuditActionConnect,
+		AdditionalFields: additionalFields,
+		New:              workspace,
+		Old:              workspace,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, agentsdk.PostSessionRecordingResponse{
+		ID: file.ID,
+	})
+}
+
+func (api *API) insertSessionRecording(ctx context.Context, ownerID uuid.UUID, data []byte) (database.File, error) {
+	hashBytes := sha256.Sum256(data)
+	hash := hex.EncodeToString(hashBytes[:])
+	// nolint:gocritic // Agents can't create files, the recording is owned by
+	// the workspace owner.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	file, err := api.Database.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+		Hash:      hash,
+		CreatedBy: ownerID,
+	})
+	if err == nil {
+		return file, nil
+	}
+	if !xerrors.Is(err, sql.ErrNoRows) {
+		return database.File{}, xerrors.Errorf("get file: %w", err)
+	}
+	file, err = api.Database.InsertFile(ctx, database.InsertFileParams{
+		ID:        uuid.New(),
+		Hash:      hash,
+		CreatedBy: ownerID,
+		CreatedAt: database.Now(),
+		Mimetype:  sessionRecordingMimeType,
+		Data:      data,
+	})
+	if err != nil {
+		return database.File{}, xerrors.Errorf("insert file: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,98 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceAgentSessionRecording(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	recording := []byte(`{"version":2,"width":80,"height":24,"timestamp":1680000000}` + "\n" + `[0.5,"o","hello"]` + "\n")
+	req := agentsdk.PostSessionRecordingRequest{
+		Type:      agentsdk.SessionRecordingTypeSSH,
+		StartedAt: database.Now().Add(-time.Second),
+		EndedAt:   database.Now(),
+		Recording: recording,
+		Truncated: true,
+		// No connection from this address was brokered, so the session
+		// is attributed to the workspace owner.
+		RemoteAddr: "[fd7a:115c:a1e0::1]:52000",
+	}
+
+	// Recordings are rejected unless the template enables them.
+	manifest, err := agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.False(t, manifest.SessionRecording)
+	_, err = agentClient.PostSessionRecording(ctx, req)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	enabled := true
+	_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		SessionRecording: &enabled,
+	})
+	require.NoError(t, err)
+	manifest, err = agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.True(t, manifest.SessionRecording)
+
+	resp, err := agentClient.PostSessionRecording(ctx, req)
+	require.NoError(t, err)
+
+	data, contentType, err := client.Download(ctx, resp.ID)
+	require.NoError(t, err)
+	require.Equal(t, "application/x-asciicast", contentType)
+	require.Equal(t, recording, data)
+
+	var connect *database.AuditLog
+	for _, alog := range auditor.AuditLogs() {
+		alog := alog
+		if alog.Action == database.AuditActionConnect {
+			connect = &alog
+		}
+	}
+	require.NotNil(t, connect, "connect audit log")
+	require.Equal(t, workspace.ID, connect.ResourceID)
+	require.Equal(t, user.UserID, connect.UserID)
+	var fields audit.AdditionalFields
+	require.NoError(t, json.Unmarshal(connect.AdditionalFields, &fields))
+	require.Equal(t, resp.ID.String(), fields.SessionRecordingID)
+	require.Equal(t, string(agentsdk.SessionRecordingTypeSSH), fields.SessionType)
+	require.Equal(t, workspace.Name, fields.WorkspaceName)
+	require.True(t, fields.SessionRecordingTruncated)
+}
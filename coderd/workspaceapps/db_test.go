@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -20,7 +21,10 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -630,6 +634,78 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "http://127.0.0.1:9090", token.AppURL)
 	})
 
+	t.Run("PortSubdomainShared", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		resolve := func(t *testing.T, port string) (*workspaceapps.SignedToken, bool) {
+			req := workspaceapps.Request{
+				AccessMethod:      workspaceapps.AccessMethodSubdomain,
+				BasePath:          "/",
+				UsernameOrID:      me.Username,
+				WorkspaceNameOrID: workspace.Name,
+				AgentNameOrID:     agentName,
+				AppSlugOrPort:     port,
+			}
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			token, ok := workspaceapps.ResolveRequest(rw, r, workspaceapps.ResolveRequestOptions{
+				Logger:              api.Logger,
+				SignedTokenProvider: api.WorkspaceAppsProvider,
+				DashboardURL:        api.AccessURL,
+				PathAppBaseURL:      api.AccessURL,
+				AppHostname:         api.AppHostname,
+				AppRequest:          req,
+			})
+			_ = rw.Result().Body.Close()
+			return token, ok
+		}
+
+		// Unshared ports are only accessible by the owner.
+		_, ok := resolve(t, "9091")
+		require.False(t, ok)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAppSharingLevelPublic),
+		})
+		require.NoError(t, err)
+		_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+			AgentName:  agentName,
+			Port:       9091,
+			ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+			Protocol:   codersdk.WorkspacePortShareProtocolHTTPS,
+		})
+		require.NoError(t, err)
+
+		token, ok := resolve(t, "9091")
+		require.True(t, ok)
+		require.Equal(t, "https://127.0.0.1:9091", token.AppURL)
+
+		// Shares of ports reserved by the agent are never honored, even if
+		// one made it into the database.
+		reserved := strconv.Itoa(codersdk.WorkspaceAgentHTTPAPIServerPort)
+		//nolint:gocritic // The API refuses to create this share.
+		_, err = api.Database.UpsertWorkspaceAgentPortShare(dbauthz.AsSystemRestricted(ctx), database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: workspace.ID,
+			AgentName:   agentName,
+			Port:        codersdk.WorkspaceAgentHTTPAPIServerPort,
+			ShareLevel:  database.AppSharingLevelPublic,
+			Protocol:    database.PortShareProtocolHttp,
+		})
+		require.NoError(t, err)
+		_, ok = resolve(t, reserved)
+		require.False(t, ok)
+
+		// Lowering the template maximum caps existing shares.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAppSharingLevelAuthenticated),
+		})
+		require.NoError(t, err)
+		_, ok = resolve(t, "9091")
+		require.False(t, ok)
+	})
+
 	t.Run("Terminal", func(t *testing.T) {
 		t.Parallel()
 
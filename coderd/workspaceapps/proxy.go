@@ -94,6 +94,10 @@ type Server struct {
 	DisablePathApps  bool
 	SecureAuthCookie bool
 
+	// ReconnectingPTYOpened is called with the user who opened a reconnecting
+	// PTY on an agent, if set.
+	ReconnectingPTYOpened func(agentID, userID, reconnectID uuid.UUID)
+
 	websocketWaitMutex sync.Mutex
 	websocketWaitGroup sync.WaitGroup
 }
@@ -541,6 +545,11 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	}
 	defer release()
 	proxy.Transport = conn.HTTPTransport()
+	// Only ports shared over HTTPS skip certificate verification. Apps
+	// defined by templates keep it.
+	if _, err := strconv.ParseUint(appToken.AppSlugOrPort, 10, 16); err == nil && appURL.Scheme == "https" {
+		proxy.Transport = conn.InsecureHTTPTransport()
+	}
 
 	// This strips the session token from a workspace app request.
 	cookieHeaders := r.Header.Values("Cookie")[:]
@@ -637,6 +646,9 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer release()
+	if s.ReconnectingPTYOpened != nil {
+		s.ReconnectingPTYOpened(appToken.AgentID, appToken.UserID, reconnect)
+	}
 	ptNetConn, err := agentConn.ReconnectingPTY(ctx, reconnect, uint16(height), uint16(width), r.URL.Query().Get("command"))
 	if err != nil {
 		s.Logger.Debug(ctx, "dial reconnecting pty server in workspace agent", slog.Error(err))
@@ -281,7 +281,9 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		}
 
 		// If the app slug is a port number, then route to the port as an
-		// "anonymous app". We only support HTTP for port-based URLs.
+		// "anonymous app". Ports are only accessible by the owner over HTTP
+		// unless the port has been shared, which is checked once the agent
+		// is known below.
 		//
 		// This is only supported for subdomain-based applications.
 		appURL = fmt.Sprintf("http://127.0.0.1:%d", portUint)
@@ -335,6 +337,29 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		}
 	}
 
+	// Shares of ports reserved by the agent are ignored, so those ports stay
+	// owner-only even if a share was stored for them.
+	if portUintErr == nil && portUint >= codersdk.WorkspaceAgentMinimumListeningPort {
+		share, err := db.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+			WorkspaceID: workspace.ID,
+			AgentName:   agent.Name,
+			Port:        int32(portUint),
+		})
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get workspace agent port share: %w", err)
+		}
+		if err == nil {
+			// The template may have lowered its maximum share level after
+			// the port was shared, so the share level is capped here.
+			template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+			if err != nil {
+				return nil, xerrors.Errorf("get template %q: %w", workspace.TemplateID, err)
+			}
+			appSharingLevel = share.ShareLevel.MostRestrictive(template.MaxPortSharingLevel)
+			appURL = fmt.Sprintf("%s://127.0.0.1:%d", share.Protocol, portUint)
+		}
+	}
+
 	appURLParsed, err := url.Parse(appURL)
 	if err != nil {
 		return nil, xerrors.Errorf("parse app URL %q: %w", appURL, err)
@@ -19,6 +19,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/parameter"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
@@ -73,6 +74,7 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.scripts,
 		data.templateVersions[0],
 	)
 	if err != nil {
@@ -187,6 +189,7 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.scripts,
 		data.templateVersions,
 	)
 	if err != nil {
@@ -275,6 +278,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.scripts,
 		data.templateVersions[0],
 	)
 	if err != nil {
@@ -331,16 +335,73 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if workspace.DormantAt.Valid && createBuild.Transition == codersdk.WorkspaceTransitionStart {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Cannot start a dormant workspace.",
+			Detail:  "The workspace was marked dormant due to inactivity. Make it active again before starting it.",
+		})
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get template",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	latestBuild, latestBuildErr := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if latestBuildErr != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching the latest workspace build.",
+			Detail:  latestBuildErr.Error(),
+		})
+		return
+	}
 	if createBuild.TemplateVersionID == uuid.Nil {
-		latestBuild, latestBuildErr := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
-		if latestBuildErr != nil {
-			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-				Message: "Internal error fetching the latest workspace build.",
-				Detail:  latestBuildErr.Error(),
+		createBuild.TemplateVersionID = latestBuild.TemplateVersionID
+	}
+
+	// Workspaces that update automatically are started on the active version
+	// of their template. If the parameters of the workspace are invalid for
+	// the active version, the workspace is started on its current version and
+	// the reason is recorded in the build logs.
+	var automaticUpdateErr error
+	if createBuild.Transition == codersdk.WorkspaceTransitionStart &&
+		(workspace.AutomaticUpdates == database.AutomaticUpdatesAlways || template.RequireActiveVersion) &&
+		createBuild.TemplateVersionID != template.ActiveVersionID {
+		switch {
+		case createBuild.TemplateVersionID == latestBuild.TemplateVersionID:
+			_, err := parameter.WorkspaceBuildParameters(ctx, api.Database, parameter.WorkspaceBuildScope{
+				WorkspaceID:       workspace.ID,
+				TemplateVersionID: template.ActiveVersionID,
+				PriorBuildID:      latestBuild.ID,
+			}, createBuild.RichParameterValues)
+			var validationErr *parameter.ValidationError
+			switch {
+			case err == nil:
+				createBuild.TemplateVersionID = template.ActiveVersionID
+			case errors.As(err, &validationErr):
+				automaticUpdateErr = validationErr
+			default:
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error resolving workspace build parameters for the active template version.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		case template.RequireActiveVersion && !api.Authorize(r, rbac.ActionUpdate, template):
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "The template requires workspaces to be started on its active version.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_version_id",
+					Detail: "template version is not the active version of the template",
+				}},
 			})
 			return
 		}
-		createBuild.TemplateVersionID = latestBuild.TemplateVersionID
 	}
 
 	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, createBuild.TemplateVersionID)
@@ -362,15 +423,6 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get template",
-			Detail:  err.Error(),
-		})
-		return
-	}
-
 	var state []byte
 	// If custom state, deny request since user could be corrupting or leaking
 	// cloud state.
@@ -456,33 +508,6 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		state = priorHistory.ProvisionerState
 	}
 
-	dbTemplateVersionParameters, err := api.Database.GetTemplateVersionParameters(ctx, createBuild.TemplateVersionID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching template version parameters.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-	templateVersionParameters, err := convertTemplateVersionParameters(dbTemplateVersionParameters)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error converting template version parameters.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-
-	lastBuildParameters, err := api.Database.GetWorkspaceBuildParameters(ctx, priorHistory.ID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching prior workspace build parameters.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-	apiLastBuildParameters := convertWorkspaceBuildParameters(lastBuildParameters)
-
 	legacyParameters, err := api.Database.ParameterValues(ctx, database.ParameterValuesParams{
 		Scopes:   []database.ParameterScope{database.ParameterScopeWorkspace},
 		ScopeIds: []uuid.UUID{workspace.ID},
@@ -495,58 +520,27 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Rich parameters migration: include legacy variables to the last build parameters
-	for _, templateVersionParameter := range templateVersionParameters {
-		// Check if parameter is defined in previous build
-		if _, found := findWorkspaceBuildParameter(apiLastBuildParameters, templateVersionParameter.Name); found {
-			continue
-		}
-
-		// Check if legacy variable is defined
-		for _, legacyParameter := range legacyParameters {
-			if legacyParameter.Name != templateVersionParameter.LegacyVariableName {
-				continue
-			}
-
-			apiLastBuildParameters = append(apiLastBuildParameters, codersdk.WorkspaceBuildParameter{
-				Name:  templateVersionParameter.Name,
-				Value: legacyParameter.SourceValue,
+	parameters, err := parameter.WorkspaceBuildParameters(ctx, api.Database, parameter.WorkspaceBuildScope{
+		WorkspaceID:       workspace.ID,
+		TemplateVersionID: templateVersion.ID,
+		PriorBuildID:      priorHistory.ID,
+	}, createBuild.RichParameterValues)
+	if err != nil {
+		var validationErr *parameter.ValidationError
+		if errors.As(err, &validationErr) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Error validating workspace build parameters.",
+				Detail:  validationErr.Error(),
 			})
-			break
+			return
 		}
-	}
-
-	err = codersdk.ValidateWorkspaceBuildParameters(templateVersionParameters, createBuild.RichParameterValues, apiLastBuildParameters)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Error validating workspace build parameters.",
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resolving workspace build parameters.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	var parameters []codersdk.WorkspaceBuildParameter
-	for _, templateVersionParameter := range templateVersionParameters {
-		// Check if parameter value is in request
-		if buildParameter, found := findWorkspaceBuildParameter(createBuild.RichParameterValues, templateVersionParameter.Name); found {
-			if !templateVersionParameter.Mutable {
-				if _, found := findWorkspaceBuildParameter(apiLastBuildParameters, templateVersionParameter.Name); found {
-					httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-						Message: fmt.Sprintf("Parameter %q is not mutable, so it can't be updated after creating a workspace.", templateVersionParameter.Name),
-					})
-					return
-				}
-			}
-			parameters = append(parameters, *buildParameter)
-			continue
-		}
-
-		// Check if parameter is defined in previous build
-		if buildParameter, found := findWorkspaceBuildParameter(apiLastBuildParameters, templateVersionParameter.Name); found {
-			parameters = append(parameters, *buildParameter)
-		}
-	}
-
 	if createBuild.LogLevel != "" && !api.Authorize(r, rbac.ActionUpdate, template) {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Workspace builds with a custom log level are restricted to template authors only.",
@@ -612,6 +606,19 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
 		}
+		if automaticUpdateErr != nil {
+			_, err = db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+				JobID:     provisionerJob.ID,
+				CreatedAt: []time.Time{now},
+				Source:    []database.LogSource{database.LogSourceProvisionerDaemon},
+				Level:     []database.LogLevel{database.LogLevelWarn},
+				Stage:     []string{"Updating workspace"},
+				Output:    []string{fmt.Sprintf("The workspace could not be updated to the active template version, so its current version is used: %s", automaticUpdateErr)},
+			})
+			if err != nil {
+				return xerrors.Errorf("insert automatic update log: %w", err)
+			}
+		}
 
 		workspaceBuild, err = db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
 			ID:                workspaceBuildID,
@@ -676,6 +683,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		[]database.WorkspaceResourceMetadatum{},
 		[]database.WorkspaceAgent{},
 		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -836,6 +844,52 @@ func (api *API) workspaceBuildParameters(rw http.ResponseWriter, r *http.Request
 	httpapi.Write(ctx, rw, http.StatusOK, apiParameters)
 }
 
+// @Summary Get workspace build timings
+// @ID get-workspace-build-timings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildTimings
+// @Router /workspacebuilds/{workspacebuild}/timings [get]
+func (api *API) workspaceBuildTimings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+
+	provisionerTimings, err := api.Database.GetProvisionerJobTimingsByJobID(ctx, workspaceBuild.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job timings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(ctx, workspaceBuild.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	// nolint:gocritic // Getting workspace agents by resource IDs is a system function.
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceBuildTimings(provisionerTimings, agents))
+}
+
 // @Summary Get workspace build logs
 // @ID get-workspace-build-logs
 // @Security CoderSessionToken
@@ -901,6 +955,45 @@ func (api *API) workspaceBuildState(rw http.ResponseWriter, r *http.Request) {
 	_, _ = rw.Write(workspaceBuild.ProvisionerState)
 }
 
+func convertWorkspaceBuildTimings(provisionerTimings []database.ProvisionerJobTiming, agents []database.WorkspaceAgent) codersdk.WorkspaceBuildTimings {
+	timings := codersdk.WorkspaceBuildTimings{
+		ProvisionerTimings: make([]codersdk.ProvisionerTiming, 0, len(provisionerTimings)),
+		AgentTimings:       make([]codersdk.AgentTiming, 0),
+	}
+	for _, timing := range provisionerTimings {
+		timings.ProvisionerTimings = append(timings.ProvisionerTimings, codersdk.ProvisionerTiming{
+			JobID:     timing.JobID,
+			StartedAt: timing.StartedAt,
+			EndedAt:   timing.EndedAt,
+			Stage:     codersdk.WorkspaceBuildTimingStage(timing.Stage),
+			Source:    timing.Source,
+			Action:    timing.Action,
+			Resource:  timing.Resource,
+		})
+	}
+	for _, agent := range agents {
+		if agent.FirstConnectedAt.Valid {
+			timings.AgentTimings = append(timings.AgentTimings, codersdk.AgentTiming{
+				WorkspaceAgentID:   agent.ID,
+				WorkspaceAgentName: agent.Name,
+				StartedAt:          agent.CreatedAt,
+				EndedAt:            agent.FirstConnectedAt.Time,
+				Stage:              codersdk.WorkspaceBuildTimingStageConnect,
+			})
+		}
+		if agent.StartedAt.Valid && agent.ReadyAt.Valid {
+			timings.AgentTimings = append(timings.AgentTimings, codersdk.AgentTiming{
+				WorkspaceAgentID:   agent.ID,
+				WorkspaceAgentName: agent.Name,
+				StartedAt:          agent.StartedAt.Time,
+				EndedAt:            agent.ReadyAt.Time,
+				Stage:              codersdk.WorkspaceBuildTimingStageStartupScript,
+			})
+		}
+	}
+	return timings
+}
+
 type workspaceBuildsData struct {
 	users            []database.User
 	jobs             []database.ProvisionerJob
@@ -909,6 +1002,7 @@ type workspaceBuildsData struct {
 	metadata         []database.WorkspaceResourceMetadatum
 	agents           []database.WorkspaceAgent
 	apps             []database.WorkspaceApp
+	scripts          []database.WorkspaceAgentScript
 }
 
 func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.Workspace, workspaceBuilds []database.WorkspaceBuild) (workspaceBuildsData, error) {
@@ -996,6 +1090,12 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		return workspaceBuildsData{}, xerrors.Errorf("fetching workspace apps: %w", err)
 	}
 
+	// nolint:gocritic // Getting workspace agent scripts by agent IDs is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil {
+		return workspaceBuildsData{}, xerrors.Errorf("fetching workspace agent scripts: %w", err)
+	}
+
 	return workspaceBuildsData{
 		users:            users,
 		jobs:             jobs,
@@ -1004,6 +1104,7 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		metadata:         metadata,
 		agents:           agents,
 		apps:             apps,
+		scripts:          scripts,
 	}, nil
 }
 
@@ -1016,6 +1117,7 @@ func (api *API) convertWorkspaceBuilds(
 	resourceMetadata []database.WorkspaceResourceMetadatum,
 	resourceAgents []database.WorkspaceAgent,
 	agentApps []database.WorkspaceApp,
+	agentScripts []database.WorkspaceAgentScript,
 	templateVersions []database.TemplateVersion,
 ) ([]codersdk.WorkspaceBuild, error) {
 	workspaceByID := map[uuid.UUID]database.Workspace{}
@@ -1056,6 +1158,7 @@ func (api *API) convertWorkspaceBuilds(
 			resourceMetadata,
 			resourceAgents,
 			agentApps,
+			agentScripts,
 			templateVersion,
 		)
 		if err != nil {
@@ -1077,6 +1180,7 @@ func (api *API) convertWorkspaceBuild(
 	resourceMetadata []database.WorkspaceResourceMetadatum,
 	resourceAgents []database.WorkspaceAgent,
 	agentApps []database.WorkspaceApp,
+	agentScripts []database.WorkspaceAgentScript,
 	templateVersion database.TemplateVersion,
 ) (codersdk.WorkspaceBuild, error) {
 	userByID := map[uuid.UUID]database.User{}
@@ -1099,6 +1203,10 @@ func (api *API) convertWorkspaceBuild(
 	for _, app := range agentApps {
 		appsByAgentID[app.AgentID] = append(appsByAgentID[app.AgentID], app)
 	}
+	scriptsByAgentID := map[uuid.UUID][]database.WorkspaceAgentScript{}
+	for _, script := range agentScripts {
+		scriptsByAgentID[script.WorkspaceAgentID] = append(scriptsByAgentID[script.WorkspaceAgentID], script)
+	}
 
 	owner, exists := userByID[workspace.OwnerID]
 	if !exists {
@@ -1116,8 +1224,9 @@ func (api *API) convertWorkspaceBuild(
 		apiAgents := make([]codersdk.WorkspaceAgent, 0)
 		for _, agent := range agents {
 			apps := appsByAgentID[agent.ID]
+			scripts := scriptsByAgentID[agent.ID]
 			apiAgent, err := convertWorkspaceAgent(
-				api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(apps), api.AgentInactiveDisconnectTimeout,
+				api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(apps), convertWorkspaceAgentScripts(scripts), api.AgentInactiveDisconnectTimeout,
 				api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 			)
 			if err != nil {
@@ -1225,12 +1334,3 @@ func convertWorkspaceBuildParameters(parameters []database.WorkspaceBuildParamet
 	}
 	return apiParameters
 }
-
-func findWorkspaceBuildParameter(params []codersdk.WorkspaceBuildParameter, parameterName string) (*codersdk.WorkspaceBuildParameter, bool) {
-	for _, p := range params {
-		if p.Name == parameterName {
-			return &p, true
-		}
-	}
-	return nil, false
-}
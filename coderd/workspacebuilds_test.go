@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,12 +14,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/provisioner/echo"
 	"github.com/coder/coder/provisionersdk/proto"
 	"github.com/coder/coder/testutil"
@@ -541,6 +545,66 @@ func TestWorkspaceBuildLogs(t *testing.T) {
 	require.Fail(t, "example message never happened")
 }
 
+func TestWorkspaceBuildTimings(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	apply := echo.ProvisionApplyWithAgent(authToken)
+	apply[0].GetComplete().Timings = []*proto.Timing{{
+		Start:  timestamppb.New(start),
+		End:    timestamppb.New(start.Add(30 * time.Second)),
+		Stage:  "apply",
+		Source: "terraform",
+	}, {
+		Start:    timestamppb.New(start),
+		End:      timestamppb.New(start.Add(20 * time.Second)),
+		Stage:    "apply",
+		Source:   "aws",
+		Action:   "create",
+		Resource: "aws_instance.example",
+	}}
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: apply,
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	for _, state := range []codersdk.WorkspaceAgentLifecycle{codersdk.WorkspaceAgentLifecycleStarting, codersdk.WorkspaceAgentLifecycleReady} {
+		err := agentClient.PostLifecycle(ctx, agentsdk.PostLifecycleRequest{State: state})
+		require.NoError(t, err)
+	}
+
+	timings, err := client.WorkspaceBuildTimings(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Len(t, timings.ProvisionerTimings, 2)
+	var resourceTiming codersdk.ProvisionerTiming
+	for _, timing := range timings.ProvisionerTimings {
+		require.Equal(t, workspace.LatestBuild.Job.ID, timing.JobID)
+		require.Equal(t, codersdk.WorkspaceBuildTimingStageApply, timing.Stage)
+		require.True(t, timing.StartedAt.Equal(start))
+		if timing.Resource != "" {
+			resourceTiming = timing
+		}
+	}
+	require.Equal(t, "aws_instance.example", resourceTiming.Resource)
+	require.Equal(t, 20*time.Second, resourceTiming.EndedAt.Sub(resourceTiming.StartedAt))
+
+	// The agent never connected, so only the startup script has a timing.
+	require.Len(t, timings.AgentTimings, 1)
+	require.Equal(t, "example", timings.AgentTimings[0].WorkspaceAgentName)
+	require.Equal(t, codersdk.WorkspaceBuildTimingStageStartupScript, timings.AgentTimings[0].Stage)
+	require.False(t, timings.AgentTimings[0].EndedAt.Before(timings.AgentTimings[0].StartedAt))
+}
+
 func TestWorkspaceBuildState(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -1467,3 +1531,146 @@ func TestWorkspaceBuildDebugMode(t *testing.T) {
 		require.Len(t, echoResponses.ProvisionApply, logsProcessed)
 	})
 }
+
+func TestWorkspaceBuildAutomaticUpdates(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a workspace on the first version of a template, then
+	// pushes a second version that is made active if activate is true.
+	setup := func(t *testing.T, automaticUpdates codersdk.AutomaticUpdates, activate bool, res *echo.Responses) (*codersdk.Client, codersdk.Workspace, codersdk.TemplateVersion, codersdk.TemplateVersion) {
+		t.Helper()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version1 := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version1.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutomaticUpdates = automaticUpdates
+		})
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, automaticUpdates, coderdtest.MustWorkspace(t, client, workspace.ID).AutomaticUpdates)
+
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, res, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+		if activate {
+			err := client.UpdateActiveTemplateVersion(context.Background(), template.ID, codersdk.UpdateActiveTemplateVersion{
+				ID: version2.ID,
+			})
+			require.NoError(t, err)
+		}
+
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+		return client, workspace, version1, version2
+	}
+
+	t.Run("Always", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, _, version2 := setup(t, codersdk.AutomaticUpdatesAlways, true, nil)
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+		require.Equal(t, version2.ID, build.TemplateVersionID)
+		build = coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+	})
+
+	t.Run("Never", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, version1, _ := setup(t, codersdk.AutomaticUpdatesNever, true, nil)
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+		require.Equal(t, version1.ID, build.TemplateVersionID)
+	})
+
+	t.Run("TemplateRequiresActiveVersion", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, _, version2 := setup(t, codersdk.AutomaticUpdatesNever, true, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+			RequireActiveVersion: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		require.True(t, template.RequireActiveVersion)
+
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+		require.Equal(t, version2.ID, build.TemplateVersionID)
+	})
+
+	t.Run("MemberCannotUseInactiveVersion", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version1 := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version1.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequireActiveVersion: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+
+		// The new version is not made active.
+		version2 := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version2.ID)
+
+		_, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: version2.ID,
+			Transition:        codersdk.WorkspaceTransitionStart,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Template admins can still use other versions.
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: version2.ID,
+			Transition:        codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Equal(t, version2.ID, build.TemplateVersionID)
+	})
+
+	t.Run("InvalidParametersFallback", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, version1, _ := setup(t, codersdk.AutomaticUpdatesAlways, true, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{
+							{Name: "new_parameter", Description: "Required parameter without a value", Required: true},
+						},
+					},
+				},
+			}},
+			ProvisionApply: echo.ProvisionComplete,
+		})
+
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+		require.Equal(t, version1.ID, build.TemplateVersionID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		logs, closer, err := client.WorkspaceBuildLogsAfter(ctx, build.ID, 0)
+		require.NoError(t, err)
+		defer closer.Close()
+		for {
+			log, ok := <-logs
+			if !ok {
+				break
+			}
+			if strings.Contains(log.Output, "could not be updated to the active template version") {
+				require.Contains(t, log.Output, "new_parameter")
+				return
+			}
+		}
+		require.Fail(t, "automatic update log never happened")
+	})
+}
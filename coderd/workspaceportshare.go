@@ -0,0 +1,211 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get workspace agent port shares
+// @ID get-workspace-agent-port-shares
+// @Security CoderSessionToken
+// @Produce json
+// @Tags PortSharing
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspacePortShares
+// @Router /workspaces/{workspace}/port-share [get]
+func (api *API) workspacePortShares(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	shares, err := api.Database.ListWorkspaceAgentPortShares(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace port shares.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspacePortShares{
+		Shares: convertPortShares(shares),
+	})
+}
+
+// @Summary Upsert workspace agent port share
+// @ID upsert-workspace-agent-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags PortSharing
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpsertWorkspacePortShareRequest true "Upsert port sharing level request"
+// @Success 200 {object} codersdk.WorkspacePortShare
+// @Router /workspaces/{workspace}/port-share [post]
+func (api *API) postWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.UpsertWorkspacePortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Protocol == "" {
+		req.Protocol = codersdk.WorkspacePortShareProtocolHTTP
+	}
+
+	var validErrs []codersdk.ValidationError
+	// Ports below the minimum are used by the agent itself, e.g. for its
+	// HTTP API, and must never be shared.
+	if req.Port < codersdk.WorkspaceAgentMinimumListeningPort || req.Port > 65535 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "port", Detail: fmt.Sprintf("Must be between %d and 65535.", codersdk.WorkspaceAgentMinimumListeningPort)})
+	}
+	shareLevel := database.AppSharingLevel(req.ShareLevel)
+	if !shareLevel.Valid() {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "share_level", Detail: fmt.Sprintf("Must be one of %q.", database.AllAppSharingLevelValues())})
+	}
+	protocol := database.PortShareProtocol(req.Protocol)
+	if !protocol.Valid() {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "protocol", Detail: fmt.Sprintf("Must be one of %q.", database.AllPortShareProtocolValues())})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid port share request.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if shareLevel.MostRestrictive(template.MaxPortSharingLevel) != shareLevel {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The template only allows ports to be shared up to the %q level.", template.MaxPortSharingLevel),
+			Validations: []codersdk.ValidationError{{
+				Field:  "share_level",
+				Detail: fmt.Sprintf("Must not exceed %q.", template.MaxPortSharingLevel),
+			}},
+		})
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	found := false
+	for _, agent := range agents {
+		if agent.Name == req.AgentName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent %q not found in the latest build of the workspace.", req.AgentName),
+		})
+		return
+	}
+
+	share, err := api.Database.UpsertWorkspaceAgentPortShare(ctx, database.UpsertWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+		ShareLevel:  shareLevel,
+		Protocol:    protocol,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error sharing workspace port.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertPortShare(share))
+}
+
+// @Summary Delete workspace agent port share
+// @ID delete-workspace-agent-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Tags PortSharing
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.DeleteWorkspacePortShareRequest true "Delete port sharing level request"
+// @Success 204
+// @Router /workspaces/{workspace}/port-share [delete]
+func (api *API) deleteWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.DeleteWorkspacePortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	_, err := api.Database.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Port %d of agent %q is not shared.", req.Port, req.AgentName),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace port share.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteWorkspaceAgentPortShare(ctx, database.DeleteWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting workspace port share.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertPortShares(shares []database.WorkspaceAgentPortShare) []codersdk.WorkspacePortShare {
+	converted := make([]codersdk.WorkspacePortShare, 0, len(shares))
+	for _, share := range shares {
+		converted = append(converted, convertPortShare(share))
+	}
+	return converted
+}
+
+func convertPortShare(share database.WorkspaceAgentPortShare) codersdk.WorkspacePortShare {
+	return codersdk.WorkspacePortShare{
+		WorkspaceID: share.WorkspaceID,
+		AgentName:   share.AgentName,
+		Port:        share.Port,
+		ShareLevel:  codersdk.WorkspaceAppSharingLevel(share.ShareLevel),
+		Protocol:    codersdk.WorkspacePortShareProtocol(share.Protocol),
+	}
+}
@@ -0,0 +1,120 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspacePortShare(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	workspace, err := client.Workspace(testutil.Context(t, testutil.WaitShort), workspace.ID)
+	require.NoError(t, err)
+	agentName := workspace.LatestBuild.Resources[0].Agents[0].Name
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Templates only allow the owner to access ports by default.
+	require.Equal(t, codersdk.WorkspaceAppSharingLevelOwner, template.MaxPortShareLevel)
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  agentName,
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAppSharingLevelPublic),
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAppSharingLevelPublic, template.MaxPortShareLevel)
+
+	share, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  agentName,
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspacePortShareProtocolHTTP, share.Protocol)
+
+	// Sharing the same port again updates the existing share.
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  agentName,
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+		Protocol:   codersdk.WorkspacePortShareProtocolHTTPS,
+	})
+	require.NoError(t, err)
+	shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspacePortShare{{
+		WorkspaceID: workspace.ID,
+		AgentName:   agentName,
+		Port:        8080,
+		ShareLevel:  codersdk.WorkspaceAppSharingLevelAuthenticated,
+		Protocol:    codersdk.WorkspacePortShareProtocolHTTPS,
+	}}, shares.Shares)
+
+	// Unknown agents and invalid ports are rejected.
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  "unknown",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  agentName,
+		Port:       70000,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	// Ports used by the agent itself can't be shared.
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  agentName,
+		Port:       codersdk.WorkspaceAgentHTTPAPIServerPort,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+		AgentName: agentName,
+		Port:      8080,
+	})
+	require.NoError(t, err)
+	shares, err = client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, shares.Shares)
+
+	err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+		AgentName: agentName,
+		Port:      8080,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/parameter"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
@@ -106,6 +107,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 // @Param name query string false "Filter with partial-match by workspace name"
 // @Param status query string false "Filter by workspace status" Enums(pending,running,stopping,stopped,failed,canceling,canceled,deleted,deleting)
 // @Param has_agent query string false "Filter by agent status" Enums(connected,connecting,disconnected,timeout)
+// @Param dormant query bool false "Filter to only dormant workspaces"
 // @Success 200 {object} codersdk.WorkspacesResponse
 // @Router /workspaces [get]
 func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
@@ -335,6 +337,13 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		})
 		return
 	}
+	if template.Deprecated != "" {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Template %q has been deprecated, and cannot be used to create a new workspace.", template.Name),
+			Detail:  template.Deprecated,
+		})
+		return
+	}
 
 	if organization.ID != template.OrganizationID {
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
@@ -370,6 +379,15 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	automaticUpdates, err := validWorkspaceAutomaticUpdates(createWorkspace.AutomaticUpdates)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace automatic updates setting.",
+			Validations: []codersdk.ValidationError{{Field: "automatic_updates", Detail: err.Error()}},
+		})
+		return
+	}
+
 	// TODO: This should be a system call as the actor might not be able to
 	// read other workspaces. Ideally we check the error on create and look for
 	// a postgres conflict error.
@@ -414,7 +432,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
-	templateVersionParameters, err := convertTemplateVersionParameters(dbTemplateVersionParameters)
+	templateVersionParameters, err := parameter.ConvertTemplateVersionParameters(dbTemplateVersionParameters)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error converting template version parameters.",
@@ -478,6 +496,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 			Name:              createWorkspace.Name,
 			AutostartSchedule: dbAutostartSchedule,
 			Ttl:               dbTTL,
+			AutomaticUpdates:  automaticUpdates,
 			// The workspaces page will sort by last used at, and it's useful to
 			// have the newly created workspace at the top of the list!
 			LastUsedAt: database.Now(),
@@ -596,6 +615,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		[]database.WorkspaceResourceMetadatum{},
 		[]database.WorkspaceAgent{},
 		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -943,6 +963,146 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, code, resp)
 }
 
+// @Summary Update workspace dormancy by ID
+// @ID update-workspace-dormancy-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceDormancy true "Workspace dormancy update request"
+// @Success 204
+// @Router /workspaces/{workspace}/dormant [put]
+func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceDormancy
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Nothing to do if the workspace is already in the requested state.
+	if req.Dormant == workspace.DormantAt.Valid {
+		aReq.New = workspace
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var newWorkspace database.Workspace
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := database.Now()
+		dormantAt := sql.NullTime{}
+		if req.Dormant {
+			dormantAt = sql.NullTime{Time: now, Valid: true}
+		} else {
+			// Reset the inactivity timer, otherwise the workspace would be
+			// marked dormant again on the next autobuild tick.
+			err := tx.UpdateWorkspaceLastUsedAt(ctx, database.UpdateWorkspaceLastUsedAtParams{
+				ID:         workspace.ID,
+				LastUsedAt: now,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace last used at: %w", err)
+			}
+		}
+
+		var err error
+		newWorkspace, err = tx.UpdateWorkspaceDormantAt(ctx, database.UpdateWorkspaceDormantAtParams{
+			ID:        workspace.ID,
+			DormantAt: dormantAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace dormant at: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace dormancy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	aReq.New = newWorkspace
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Update workspace automatic updates by ID
+// @ID update-workspace-automatic-updates-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceAutomaticUpdatesRequest true "Automatic updates request"
+// @Success 204
+// @Router /workspaces/{workspace}/autoupdates [put]
+func (api *API) putWorkspaceAutoupdates(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceAutomaticUpdatesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	automaticUpdates, err := validWorkspaceAutomaticUpdates(req.AutomaticUpdates)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace automatic updates setting.",
+			Validations: []codersdk.ValidationError{{Field: "automatic_updates", Detail: err.Error()}},
+		})
+		return
+	}
+
+	err = api.Database.UpdateWorkspaceAutomaticUpdates(ctx, database.UpdateWorkspaceAutomaticUpdatesParams{
+		ID:               workspace.ID,
+		AutomaticUpdates: automaticUpdates,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace automatic updates setting.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	newWorkspace := workspace
+	newWorkspace.AutomaticUpdates = automaticUpdates
+	aReq.New = newWorkspace
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Watch workspace by ID
 // @ID watch-workspace-by-id
 // @Security CoderSessionToken
@@ -1087,6 +1247,7 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.scripts,
 		data.templateVersions,
 	)
 	if err != nil {
@@ -1159,6 +1320,15 @@ func convertWorkspace(
 		autostartSchedule = &workspace.AutostartSchedule.String
 	}
 
+	var dormantAt, deletingAt *time.Time
+	if workspace.DormantAt.Valid {
+		dormantAt = &workspace.DormantAt.Time
+		if template.DormantAutoDeleteTTL > 0 {
+			t := workspace.DormantAt.Time.Add(time.Duration(template.DormantAutoDeleteTTL))
+			deletingAt = &t
+		}
+	}
+
 	ttlMillis := convertWorkspaceTTLMillis(workspace.Ttl)
 	return codersdk.Workspace{
 		ID:                                   workspace.ID,
@@ -1178,6 +1348,11 @@ func convertWorkspace(
 		AutostartSchedule:                    autostartSchedule,
 		TTLMillis:                            ttlMillis,
 		LastUsedAt:                           workspace.LastUsedAt,
+		DormantAt:                            dormantAt,
+		DeletingAt:                           deletingAt,
+		TemplateDeprecationMessage:           template.Deprecated,
+		AutomaticUpdates:                     codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
+		TemplateRequireActiveVersion:         template.RequireActiveVersion,
 	}
 }
 
@@ -1263,6 +1438,17 @@ func validWorkspaceSchedule(s *string) (sql.NullString, error) {
 	}, nil
 }
 
+func validWorkspaceAutomaticUpdates(updates codersdk.AutomaticUpdates) (database.AutomaticUpdates, error) {
+	switch updates {
+	case "":
+		return database.AutomaticUpdatesNever, nil
+	case codersdk.AutomaticUpdatesAlways, codersdk.AutomaticUpdatesNever:
+		return database.AutomaticUpdates(updates), nil
+	default:
+		return "", xerrors.Errorf("invalid automatic updates setting %q, must be %q or %q", updates, codersdk.AutomaticUpdatesAlways, codersdk.AutomaticUpdatesNever)
+	}
+}
+
 func watchWorkspaceChannel(id uuid.UUID) string {
 	return fmt.Sprintf("workspace:%s", id)
 }
@@ -0,0 +1,120 @@
+package coderd
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net/http"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Issue SSH certificate for workspace
+// @ID issue-ssh-certificate-for-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PostWorkspaceSSHCertificateRequest true "Public key to certify"
+// @Success 201 {object} codersdk.WorkspaceSSHCertificate
+// @Router /workspaces/{workspace}/ssh-certificate [post]
+func (api *API) postWorkspaceSSHCertificate(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	apiKey := httpmw.APIKey(r)
+
+	// Certificates grant the same access as connecting to the workspace
+	// over the Coder network.
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if api.SSHCAKey == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SSH certificates are not enabled on this deployment.",
+		})
+		return
+	}
+
+	var req codersdk.PostWorkspaceSSHCertificateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	publicKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(req.PublicKey))
+	_, isCert := publicKey.(*gossh.Certificate)
+	if err != nil || isCert {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid public key.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "public_key",
+				Detail: "Must be a public key in the authorized_keys format.",
+			}},
+		})
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	cert, err := api.signSSHCertificate(publicKey, user, workspace)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error signing SSH certificate.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.WorkspaceSSHCertificate{
+		Certificate: string(gossh.MarshalAuthorizedKey(cert)),
+		ExpiresAt:   time.Unix(int64(cert.ValidBefore), 0),
+	})
+}
+
+// signSSHCertificate issues a user certificate for the key that is only valid
+// for the workspace. The key ID identifies the user in the agent logs.
+func (api *API) signSSHCertificate(publicKey gossh.PublicKey, user database.User, workspace database.Workspace) (*gossh.Certificate, error) {
+	var serial [8]byte
+	_, err := rand.Read(serial[:])
+	if err != nil {
+		return nil, err
+	}
+	now := database.Now()
+	cert := &gossh.Certificate{
+		Key:             publicKey,
+		Serial:          binary.BigEndian.Uint64(serial[:]),
+		CertType:        gossh.UserCert,
+		KeyId:           user.Username,
+		ValidPrincipals: []string{codersdk.WorkspaceSSHCertificatePrincipal(workspace.ID)},
+		// Allow for some clock skew between coderd and the workspace.
+		ValidAfter:  uint64(now.Add(-time.Minute).Unix()),
+		ValidBefore: uint64(now.Add(codersdk.WorkspaceSSHCertificateLifetime).Unix()),
+		Permissions: gossh.Permissions{
+			Extensions: map[string]string{
+				"permit-X11-forwarding":   "",
+				"permit-agent-forwarding": "",
+				"permit-port-forwarding":  "",
+				"permit-pty":              "",
+				"permit-user-rc":          "",
+			},
+		},
+	}
+	err = cert.SignCert(rand.Reader, api.SSHCAKey)
+	if err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
@@ -0,0 +1,99 @@
+package coderd_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestPostWorkspaceSSHCertificate(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := gossh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+	publicKey := string(gossh.MarshalAuthorizedKey(signer.PublicKey()))
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.PostWorkspaceSSHCertificate(ctx, workspace.ID, codersdk.PostWorkspaceSSHCertificateRequest{
+			PublicKey: publicKey,
+		})
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(codersdk.WorkspaceSSHCertificateLifetime), res.ExpiresAt, time.Minute)
+
+		parsed, _, _, _, err := gossh.ParseAuthorizedKey([]byte(res.Certificate))
+		require.NoError(t, err)
+		cert, ok := parsed.(*gossh.Certificate)
+		require.True(t, ok)
+		require.Equal(t, signer.PublicKey().Marshal(), cert.Key.Marshal())
+		require.Equal(t, coderdtest.SSHCAKey.PublicKey().Marshal(), cert.SignatureKey.Marshal())
+		require.Equal(t, uint32(gossh.UserCert), cert.CertType)
+		require.Equal(t, coderdtest.FirstUserParams.Username, cert.KeyId)
+		checker := &gossh.CertChecker{}
+		require.NoError(t, checker.CheckCert(codersdk.WorkspaceSSHCertificatePrincipal(workspace.ID), cert))
+		require.Error(t, checker.CheckCert(codersdk.WorkspaceSSHCertificatePrincipal(uuid.New()), cert))
+
+		// The agent validates certificates with the authority from the
+		// manifest.
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, manifest.WorkspaceID)
+		require.Equal(t, string(gossh.MarshalAuthorizedKey(coderdtest.SSHCAKey.PublicKey())), manifest.SSHCAPublicKey)
+	})
+
+	t.Run("InvalidPublicKey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.PostWorkspaceSSHCertificate(ctx, workspace.ID, codersdk.PostWorkspaceSSHCertificateRequest{
+			PublicKey: "not a key",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.PostWorkspaceSSHCertificate(ctx, workspace.ID, codersdk.PostWorkspaceSSHCertificateRequest{
+			PublicKey: publicKey,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -3,6 +3,7 @@ package wsconncache
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"sync"
 	"time"
@@ -43,17 +44,29 @@ type Conn struct {
 	timeout       *time.Timer
 	timeoutCancel context.CancelFunc
 	transport     *http.Transport
+	// insecureTransport skips TLS verification. It's only used for shared
+	// ports, whose HTTPS servers commonly use self-signed certificates.
+	insecureTransport *http.Transport
 }
 
 func (c *Conn) HTTPTransport() *http.Transport {
 	return c.transport
 }
 
-// Close ends the HTTP transport if exists, and closes the agent.
+// InsecureHTTPTransport is like HTTPTransport, but doesn't verify the TLS
+// certificates of servers. Only use it for shared ports served over HTTPS.
+func (c *Conn) InsecureHTTPTransport() *http.Transport {
+	return c.insecureTransport
+}
+
+// Close ends the HTTP transports if they exist, and closes the agent.
 func (c *Conn) Close() error {
 	if c.transport != nil {
 		c.transport.CloseIdleConnections()
 	}
+	if c.insecureTransport != nil {
+		c.insecureTransport.CloseIdleConnections()
+	}
 	c.timeoutMutex.Lock()
 	defer c.timeoutMutex.Unlock()
 	if c.timeout != nil {
@@ -107,10 +120,16 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 			}
 			transport := defaultTransport.Clone()
 			transport.DialContext = agentConn.DialContext
+			// Shared ports are reached on 127.0.0.1 through the encrypted
+			// agent connection, so their certificates can't be verified.
+			insecureTransport := transport.Clone()
+			//nolint:gosec
+			insecureTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 			conn := &Conn{
 				WorkspaceAgentConn: agentConn,
 				timeoutCancel:      timeoutCancelFunc,
 				transport:          transport,
+				insecureTransport:  insecureTransport,
 			}
 			go func() {
 				defer c.closeGroup.Done()
@@ -122,6 +141,7 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 				c.connMap.Delete(id.String())
 				c.connGroup.Forget(id.String())
 				transport.CloseIdleConnections()
+				insecureTransport.CloseIdleConnections()
 				_ = conn.Close()
 			}()
 			return conn, nil
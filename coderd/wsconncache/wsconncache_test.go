@@ -152,6 +152,38 @@ func TestCache(t *testing.T) {
 		}
 		wg.Wait()
 	})
+	t.Run("TLSVerification", func(t *testing.T) {
+		t.Parallel()
+		// httptest uses a certificate that isn't trusted by default.
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		cache := wsconncache.New(func(id uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
+			return setupAgent(t, agentsdk.Manifest{}, 0), nil
+		}, 0)
+		defer func() {
+			_ = cache.Close()
+		}()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		conn, release, err := cache.Acquire(uuid.Nil)
+		require.NoError(t, err)
+		defer release()
+		require.True(t, conn.AwaitReachable(ctx))
+
+		get := func(transport *http.Transport) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			res, err := transport.RoundTrip(req)
+			if err != nil {
+				return err
+			}
+			return res.Body.Close()
+		}
+		require.ErrorContains(t, get(conn.HTTPTransport()), "certificate")
+		require.NoError(t, get(conn.InsecureHTTPTransport()))
+	})
 }
 
 func setupAgent(t *testing.T, manifest agentsdk.Manifest, ptyTimeout time.Duration) *codersdk.WorkspaceAgentConn {
@@ -257,3 +289,19 @@ func (*client) PostStartup(_ context.Context, _ agentsdk.PostStartupRequest) err
 func (*client) PatchStartupLogs(_ context.Context, _ agentsdk.PatchStartupLogs) error {
 	return nil
 }
+
+func (*client) PostScriptStatus(_ context.Context, _ uuid.UUID, _ agentsdk.PostScriptStatusRequest) error {
+	return nil
+}
+
+func (*client) PatchDebugLogs(_ context.Context, _ agentsdk.PatchDebugLogs) error {
+	return nil
+}
+
+func (*client) PostSessionRecording(_ context.Context, _ agentsdk.PostSessionRecordingRequest) (agentsdk.PostSessionRecordingResponse, error) {
+	return agentsdk.PostSessionRecordingResponse{}, nil
+}
+
+func (*client) PostDevcontainer(_ context.Context, _ agentsdk.PostDevcontainerRequest) (agentsdk.PostDevcontainerResponse, error) {
+	return agentsdk.PostDevcontainerResponse{}, nil
+}
@@ -99,6 +99,15 @@ type Manifest struct {
 	ShutdownScript        string                                       `json:"shutdown_script"`
 	ShutdownScriptTimeout time.Duration                                `json:"shutdown_script_timeout"`
 	Metadata              []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	Scripts               []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	// SessionRecording enables recording of interactive sessions.
+	SessionRecording bool `json:"session_recording"`
+	// WorkspaceID is the ID of the workspace of the agent.
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	// SSHCAPublicKey is the key of the certificate authority that signs the
+	// certificates for plain SSH access in the authorized_keys format. It's
+	// empty if the deployment doesn't issue certificates.
+	SSHCAPublicKey string `json:"ssh_ca_public_key"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -483,6 +492,31 @@ type Stats struct {
 	// SessionCountSSH is the number of connections received by an agent
 	// that are normal, non-tagged SSH sessions.
 	SessionCountSSH int64 `json:"session_count_ssh"`
+
+	// Resources is the resource usage of the workspace. It is nil if the
+	// agent can't collect resource usage on its platform.
+	Resources *ResourceUsage `json:"resources,omitempty"`
+}
+
+// ResourceUsage is the resource usage of the workspace the agent runs in.
+// Limits are the cgroup limits when the agent runs in a container, and the
+// capacity of the host otherwise.
+type ResourceUsage struct {
+	// CPUUsedCores is the average number of cores used since the previous
+	// report.
+	CPUUsedCores  float64 `json:"cpu_used_cores"`
+	CPULimitCores float64 `json:"cpu_limit_cores"`
+	// MemoryUsedBytes excludes the page cache that can be reclaimed.
+	MemoryUsedBytes  int64 `json:"memory_used_bytes"`
+	MemoryLimitBytes int64 `json:"memory_limit_bytes"`
+	// DiskUsedBytes and DiskTotalBytes are for the filesystem of the agent
+	// directory.
+	DiskUsedBytes  int64 `json:"disk_used_bytes"`
+	DiskTotalBytes int64 `json:"disk_total_bytes"`
+	// NetworkRxBytes and NetworkTxBytes are the totals for all network
+	// interfaces except loopback.
+	NetworkRxBytes int64 `json:"network_rx_bytes"`
+	NetworkTxBytes int64 `json:"network_tx_bytes"`
 }
 
 type StatsResponse struct {
@@ -551,11 +585,14 @@ type StartupLog struct {
 }
 
 type PatchStartupLogs struct {
-	Logs []StartupLog `json:"logs"`
+	// ScriptID is the script that produced the logs, or the nil UUID for the
+	// legacy startup script.
+	ScriptID uuid.UUID    `json:"script_id" format:"uuid"`
+	Logs     []StartupLog `json:"logs"`
 }
 
-// PatchStartupLogs writes log messages to the agent startup script.
-// Log messages are limited to 1MB in total.
+// PatchStartupLogs writes log messages from an agent script. Log messages are
+// limited to 1MB in total across all scripts.
 func (c *Client) PatchStartupLogs(ctx context.Context, req PatchStartupLogs) error {
 	res, err := c.SDK.Request(ctx, http.MethodPatch, "/api/v2/workspaceagents/me/startup-logs", req)
 	if err != nil {
@@ -568,6 +605,125 @@ func (c *Client) PatchStartupLogs(ctx context.Context, req PatchStartupLogs) err
 	return nil
 }
 
+type DebugLog struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Output    string            `json:"output"`
+	Level     codersdk.LogLevel `json:"level"`
+}
+
+type PatchDebugLogs struct {
+	Logs []DebugLog `json:"logs"`
+}
+
+// PatchDebugLogs uploads logs of the agent itself. Only the most recent logs
+// of an agent are kept.
+func (c *Client) PatchDebugLogs(ctx context.Context, req PatchDebugLogs) error {
+	res, err := c.SDK.Request(ctx, http.MethodPatch, "/api/v2/workspaceagents/me/debug-logs", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// MaxSessionRecordingSize is the maximum size of a session recording. Output
+// beyond this size isn't recorded.
+const MaxSessionRecordingSize = 10 << 20
+
+type SessionRecordingType string
+
+const (
+	SessionRecordingTypeSSH             SessionRecordingType = "ssh"
+	SessionRecordingTypeReconnectingPTY SessionRecordingType = "reconnecting_pty"
+)
+
+type PostSessionRecordingRequest struct {
+	Type      SessionRecordingType `json:"type"`
+	StartedAt time.Time            `json:"started_at" format:"date-time"`
+	EndedAt   time.Time            `json:"ended_at" format:"date-time"`
+	// Truncated is true when output was left out because the recording
+	// reached its maximum size.
+	Truncated bool `json:"truncated"`
+	// RemoteAddr is the tailnet address of the connection that opened the
+	// session. It's used to attribute the session to the user who connected.
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	// ReconnectingPTYID is the ID of the reconnecting PTY the session was
+	// recorded in, if any.
+	ReconnectingPTYID uuid.UUID `json:"reconnecting_pty_id" format:"uuid"`
+	// Recording is the session in the asciicast v2 format.
+	Recording []byte `json:"recording"`
+}
+
+type PostSessionRecordingResponse struct {
+	// ID is the ID of the file the recording is stored in.
+	ID uuid.UUID `json:"id" format:"uuid"`
+}
+
+// PostSessionRecording uploads the recording of an interactive session and
+// audits the connection.
+func (c *Client) PostSessionRecording(ctx context.Context, req PostSessionRecordingRequest) (PostSessionRecordingResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/session-recordings", req)
+	if err != nil {
+		return PostSessionRecordingResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return PostSessionRecordingResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp PostSessionRecordingResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+type PostScriptStatusRequest struct {
+	Status      codersdk.WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode    int32                               `json:"exit_code"`
+	StartedAt   *time.Time                          `json:"started_at,omitempty" format:"date-time"`
+	CompletedAt *time.Time                          `json:"completed_at,omitempty" format:"date-time"`
+}
+
+// PostScriptStatus reports the status of the latest run of a script.
+func (c *Client) PostScriptStatus(ctx context.Context, scriptID uuid.UUID, req PostScriptStatusRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/me/scripts/%s/status", scriptID), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+type PostDevcontainerRequest struct {
+	// Name is the name of the agent of the dev container.
+	Name string `json:"name" validate:"required,username"`
+	// Directory is the workspace folder inside of the dev container.
+	Directory string `json:"directory"`
+}
+
+type PostDevcontainerResponse struct {
+	AgentID   uuid.UUID `json:"agent_id" format:"uuid"`
+	AuthToken uuid.UUID `json:"auth_token" format:"uuid"`
+}
+
+// PostDevcontainer registers a dev container run by the agent. It returns the
+// agent of the dev container, which is created on the first call for a name.
+func (c *Client) PostDevcontainer(ctx context.Context, req PostDevcontainerRequest) (PostDevcontainerResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/devcontainers", req)
+	if err != nil {
+		return PostDevcontainerResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return PostDevcontainerResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp PostDevcontainerResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type GitAuthResponse struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -23,6 +23,9 @@ const (
 	ResourceTypeAPIKey          ResourceType = "api_key"
 	ResourceTypeGroup           ResourceType = "group"
 	ResourceTypeLicense         ResourceType = "license"
+	ResourceTypeWebhook         ResourceType = "webhook"
+	ResourceTypeCustomRole      ResourceType = "custom_role"
+	ResourceTypeProvisionerKey  ResourceType = "provisioner_key"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -47,6 +50,12 @@ func (r ResourceType) FriendlyString() string {
 		return "group"
 	case ResourceTypeLicense:
 		return "license"
+	case ResourceTypeWebhook:
+		return "webhook"
+	case ResourceTypeCustomRole:
+		return "custom role"
+	case ResourceTypeProvisionerKey:
+		return "provisioner key"
 	default:
 		return "unknown"
 	}
@@ -63,6 +72,7 @@ const (
 	AuditActionLogin    AuditAction = "login"
 	AuditActionLogout   AuditAction = "logout"
 	AuditActionRegister AuditAction = "register"
+	AuditActionConnect  AuditAction = "connect"
 )
 
 func (a AuditAction) Friendly() string {
@@ -83,6 +93,8 @@ func (a AuditAction) Friendly() string {
 		return "logged out"
 	case AuditActionRegister:
 		return "registered"
+	case AuditActionConnect:
+		return "connected to"
 	default:
 		return "unknown"
 	}
@@ -135,7 +147,7 @@ type CreateTestAuditLogRequest struct {
 	ResourceID       uuid.UUID       `json:"resource_id,omitempty" format:"uuid"`
 	AdditionalFields json.RawMessage `json:"additional_fields,omitempty"`
 	Time             time.Time       `json:"time,omitempty" format:"date-time"`
-	BuildReason      BuildReason     `json:"build_reason,omitempty" enums:"autostart,autostop,initiator"`
+	BuildReason      BuildReason     `json:"build_reason,omitempty" enums:"autostart,autostop,initiator,dormancy,autodelete,failedstop"`
 }
 
 // AuditLogs retrieves audit logs from the given page.
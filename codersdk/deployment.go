@@ -163,6 +163,8 @@ type DeploymentValues struct {
 	SSHConfig                       SSHConfig                       `json:"config_ssh,omitempty" typescript:",notnull"`
 	WgtunnelHost                    clibase.String                  `json:"wgtunnel_host,omitempty" typescript:",notnull"`
 	DisableOwnerWorkspaceExec       clibase.Bool                    `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	Webhooks                        WebhooksConfig                  `json:"webhooks,omitempty" typescript:",notnull"`
+	UserQuietHoursSchedule          UserQuietHoursScheduleConfig    `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -312,6 +314,16 @@ type ProvisionerConfig struct {
 	ForceCancelInterval clibase.Duration `json:"force_cancel_interval" typescript:",notnull"`
 }
 
+type WebhooksConfig struct {
+	MaxAttempts  clibase.Int64    `json:"max_attempts" typescript:",notnull"`
+	Timeout      clibase.Duration `json:"timeout" typescript:",notnull"`
+	RetryBackoff clibase.Duration `json:"retry_backoff" typescript:",notnull"`
+}
+
+type UserQuietHoursScheduleConfig struct {
+	DefaultSchedule clibase.String `json:"default_schedule" typescript:",notnull"`
+}
+
 type RateLimitConfig struct {
 	DisableAll clibase.Bool  `json:"disable_all" typescript:",notnull"`
 	API        clibase.Int64 `json:"api" typescript:",notnull"`
@@ -445,6 +457,16 @@ when required by your organization's security policy.`,
 			Description: `Tune the behavior of the provisioner, which is responsible for creating, updating, and deleting workspace resources.`,
 			YAML:        "provisioning",
 		}
+		deploymentGroupWebhooks = clibase.Group{
+			Name:        "Webhooks",
+			Description: `Tune how outbound webhooks are delivered to the URLs configured by administrators.`,
+			YAML:        "webhooks",
+		}
+		deploymentGroupUserQuietHoursSchedule = clibase.Group{
+			Name:        "User Quiet Hours Schedule",
+			Description: "Allow users to set quiet hours schedules each day for workspaces to avoid workspaces stopping during the day due to template restart requirements.",
+			YAML:        "userQuietHoursSchedule",
+		}
 		deploymentGroupDangerous = clibase.Group{
 			Name: "⚠️ Dangerous",
 			YAML: "dangerous",
@@ -1067,6 +1089,47 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupProvisioning,
 			YAML:        "forceCancelInterval",
 		},
+		// Webhook settings
+		{
+			Name:        "Webhook Max Attempts",
+			Description: "Maximum number of times a webhook delivery is attempted before it is marked as failed.",
+			Flag:        "webhook-max-attempts",
+			Env:         "CODER_WEBHOOK_MAX_ATTEMPTS",
+			Default:     "5",
+			Value:       &c.Webhooks.MaxAttempts,
+			Group:       &deploymentGroupWebhooks,
+			YAML:        "maxAttempts",
+		},
+		{
+			Name:        "Webhook Timeout",
+			Description: "Time to wait for a webhook endpoint to respond before the attempt is considered failed.",
+			Flag:        "webhook-timeout",
+			Env:         "CODER_WEBHOOK_TIMEOUT",
+			Default:     (10 * time.Second).String(),
+			Value:       &c.Webhooks.Timeout,
+			Group:       &deploymentGroupWebhooks,
+			YAML:        "timeout",
+		},
+		{
+			Name:        "Webhook Retry Backoff",
+			Description: "Time to wait before retrying a failed webhook delivery. The wait doubles after every failed attempt.",
+			Flag:        "webhook-retry-backoff",
+			Env:         "CODER_WEBHOOK_RETRY_BACKOFF",
+			Default:     (30 * time.Second).String(),
+			Value:       &c.Webhooks.RetryBackoff,
+			Group:       &deploymentGroupWebhooks,
+			YAML:        "retryBackoff",
+		},
+		{
+			Name:        "Default Quiet Hours Schedule",
+			Description: "The default daily cron schedule applied to users that haven't set a custom quiet hours schedule themselves. The quiet hours schedule determines when workspaces will be force stopped due to the template's restart requirement. This schedule must be daily with a single time, and should have a timezone specified via a CRON_TZ prefix (otherwise UTC will be used). If no default schedule is set, users must set their own schedule for restart requirements to apply.",
+			Flag:        "default-quiet-hours-schedule",
+			Env:         "CODER_QUIET_HOURS_DEFAULT_SCHEDULE",
+			Default:     "CRON_TZ=UTC 0 0 * * *",
+			Value:       &c.UserQuietHoursSchedule.DefaultSchedule,
+			Group:       &deploymentGroupUserQuietHoursSchedule,
+			YAML:        "defaultQuietHoursSchedule",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -0,0 +1,81 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// InsightsReportInterval is the length of the intervals a report is split
+// into.
+type InsightsReportInterval string
+
+const (
+	InsightsReportIntervalDay  InsightsReportInterval = "day"
+	InsightsReportIntervalWeek InsightsReportInterval = "week"
+)
+
+// QuotaInsightsRequest selects the time range of a quota report. Spend is
+// reported from the start time up to the end time, or the current time if the
+// end time is in the future.
+type QuotaInsightsRequest struct {
+	StartTime time.Time              `json:"start_time" format:"date-time"`
+	EndTime   time.Time              `json:"end_time" format:"date-time"`
+	Interval  InsightsReportInterval `json:"interval" enums:"day,week"`
+}
+
+// QuotaInsightsResponse reports the credits spent by workspaces over time. A
+// workspace spends the daily cost of its latest build for every day it has
+// been in effect.
+type QuotaInsightsResponse struct {
+	StartTime    time.Time               `json:"start_time" format:"date-time"`
+	EndTime      time.Time               `json:"end_time" format:"date-time"`
+	Interval     InsightsReportInterval  `json:"interval" enums:"day,week"`
+	CreditsSpent float64                 `json:"credits_spent"`
+	Intervals    []QuotaInsightsInterval `json:"intervals"`
+}
+
+// QuotaInsightsInterval is the spend within a single interval of a quota
+// report. Spend of users that are members of several groups counts towards
+// each of them.
+type QuotaInsightsInterval struct {
+	StartTime    time.Time    `json:"start_time" format:"date-time"`
+	EndTime      time.Time    `json:"end_time" format:"date-time"`
+	CreditsSpent float64      `json:"credits_spent"`
+	Users        []QuotaSpend `json:"users"`
+	Groups       []QuotaSpend `json:"groups"`
+	Templates    []QuotaSpend `json:"templates"`
+}
+
+// QuotaSpend is the credits spent by the workspaces of a user, the members of
+// a group or the workspaces of a template.
+type QuotaSpend struct {
+	ID           uuid.UUID `json:"id" format:"uuid"`
+	Name         string    `json:"name"`
+	CreditsSpent float64   `json:"credits_spent"`
+}
+
+func (c *Client) QuotaInsights(ctx context.Context, req QuotaInsightsRequest) (QuotaInsightsResponse, error) {
+	q := url.Values{}
+	q.Set("start_time", req.StartTime.Format(time.RFC3339))
+	q.Set("end_time", req.EndTime.Format(time.RFC3339))
+	if req.Interval != "" {
+		q.Set("interval", string(req.Interval))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/insights/quota?%s", q.Encode()), nil)
+	if err != nil {
+		return QuotaInsightsResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return QuotaInsightsResponse{}, ReadBodyAsError(res)
+	}
+	var resp QuotaInsightsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
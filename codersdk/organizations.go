@@ -91,6 +91,19 @@ type CreateTemplateRequest struct {
 	// MaxTTLMillis allows optionally specifying the max lifetime for
 	// workspaces created from this template.
 	MaxTTLMillis *int64 `json:"max_ttl_ms,omitempty"`
+	// InactivityTTLMillis allows optionally specifying the duration of
+	// inactivity after which workspaces created from this template are
+	// marked dormant.
+	InactivityTTLMillis *int64 `json:"inactivity_ttl_ms,omitempty"`
+	// DormantAutoDeleteTTLMillis allows optionally specifying the duration
+	// a workspace may remain dormant before it is deleted.
+	DormantAutoDeleteTTLMillis *int64 `json:"dormant_autodelete_ttl_ms,omitempty"`
+	// FailureTTLMillis allows optionally specifying the duration after which
+	// workspaces whose start build failed are stopped automatically.
+	FailureTTLMillis *int64 `json:"failure_ttl_ms,omitempty"`
+	// RestartRequirement allows optionally specifying the restart requirement
+	// for workspaces created from this template. This is an enterprise feature.
+	RestartRequirement *TemplateRestartRequirement `json:"restart_requirement,omitempty"`
 
 	// Allow users to cancel in-progress workspace jobs.
 	// *bool as the default value is "true".
@@ -118,6 +131,8 @@ type CreateWorkspaceRequest struct {
 	// during the initial provision.
 	ParameterValues     []CreateParameterRequest  `json:"parameter_values,omitempty"`
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+	// AutomaticUpdates defaults to never.
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates,omitempty" enums:"always,never"`
 }
 
 func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -135,7 +150,7 @@ func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization,
 	return organization, json.NewDecoder(res.Body).Decode(&organization)
 }
 
-// ProvisionerDaemonsByOrganization returns provisioner daemons available for an organization.
+// ProvisionerDaemons returns the provisioner daemons of the deployment.
 func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
 	res, err := c.Request(ctx, http.MethodGet,
 		"/api/v2/provisionerdaemons",
@@ -211,11 +226,37 @@ func (c *Client) CreateTemplate(ctx context.Context, organizationID uuid.UUID, r
 	return template, json.NewDecoder(res.Body).Decode(&template)
 }
 
-// TemplatesByOrganization lists all templates inside of an organization.
+// TemplatesByOrganization lists all templates inside of an organization that
+// aren't deprecated.
 func (c *Client) TemplatesByOrganization(ctx context.Context, organizationID uuid.UUID) ([]Template, error) {
+	return c.TemplatesByOrganizationWithFilter(ctx, organizationID, TemplateFilter{})
+}
+
+// TemplateFilter filters the templates returned by
+// TemplatesByOrganizationWithFilter.
+type TemplateFilter struct {
+	// IncludeDeprecated includes deprecated templates, which are hidden by
+	// default.
+	IncludeDeprecated bool `json:"include_deprecated"`
+}
+
+// asRequestOption returns a function that can be used in (*Client).Request.
+// It modifies the request query parameters.
+func (f TemplateFilter) asRequestOption() RequestOption {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("include_deprecated", fmt.Sprintf("%t", f.IncludeDeprecated))
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+// TemplatesByOrganizationWithFilter lists the templates inside the
+// organization that match the filter.
+func (c *Client) TemplatesByOrganizationWithFilter(ctx context.Context, organizationID uuid.UUID, filter TemplateFilter) ([]Template, error) {
 	res, err := c.Request(ctx, http.MethodGet,
 		fmt.Sprintf("/api/v2/organizations/%s/templates", organizationID.String()),
 		nil,
+		filter.asRequestOption(),
 	)
 	if err != nil {
 		return nil, xerrors.Errorf("execute request: %w", err)
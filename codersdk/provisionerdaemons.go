@@ -16,6 +16,7 @@ import (
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 
+	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/provisionerd/proto"
 	"github.com/coder/coder/provisionersdk"
 )
@@ -39,9 +40,21 @@ type ProvisionerDaemon struct {
 	ID           uuid.UUID         `json:"id" format:"uuid"`
 	CreatedAt    time.Time         `json:"created_at" format:"date-time"`
 	UpdatedAt    sql.NullTime      `json:"updated_at" format:"date-time"`
+	LastSeenAt   sql.NullTime      `json:"last_seen_at" format:"date-time"`
 	Name         string            `json:"name"`
+	Version      string            `json:"version"`
 	Provisioners []ProvisionerType `json:"provisioners"`
 	Tags         map[string]string `json:"tags"`
+	// KeyID is the provisioner key the daemon authenticated with. It is
+	// unset for daemons authenticated with a user session.
+	KeyID      *uuid.UUID            `json:"key_id,omitempty" format:"uuid"`
+	CurrentJob *ProvisionerDaemonJob `json:"current_job,omitempty"`
+}
+
+// ProvisionerDaemonJob is the job a provisioner daemon is running.
+type ProvisionerDaemonJob struct {
+	ID     uuid.UUID            `json:"id" format:"uuid"`
+	Status ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
 }
 
 // ProvisionerJobStatus represents the at-time state of a job.
@@ -158,36 +171,70 @@ func (c *Client) provisionerJobLogsAfter(ctx context.Context, path string, after
 	}), nil
 }
 
+// ProvisionerDaemonKeyHeader authenticates a provisioner daemon with a
+// provisioner key instead of a session token.
+const ProvisionerDaemonKeyHeader = "Coder-Provisioner-Daemon-Key"
+
+// ServeProvisionerDaemonRequest are the parameters to connect a provisioner
+// daemon with.
+// @typescript-ignore ServeProvisionerDaemonRequest
+type ServeProvisionerDaemonRequest struct {
+	// Organization is the organization the daemon serves jobs for. It's
+	// ignored when the daemon authenticates with a provisioner key, the
+	// organization of the key is used instead.
+	Organization uuid.UUID
+	// Provisioners are the types of provisioners the daemon runs.
+	Provisioners []ProvisionerType
+	// Tags are the tags of the jobs the daemon acquires. They are ignored
+	// when the daemon authenticates with a provisioner key, the tags of the
+	// key are used instead.
+	Tags map[string]string
+	// ProvisionerKey authenticates the daemon instead of the session token
+	// of the client.
+	ProvisionerKey string
+}
+
 // ListenProvisionerDaemon returns the gRPC service for a provisioner daemon
 // implementation. The context is during dial, not during the lifetime of the
 // client. Client should be closed after use.
-func (c *Client) ServeProvisionerDaemon(ctx context.Context, organization uuid.UUID, provisioners []ProvisionerType, tags map[string]string) (proto.DRPCProvisionerDaemonClient, error) {
-	serverURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons/serve", organization))
+func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisionerDaemonRequest) (proto.DRPCProvisionerDaemonClient, error) {
+	path := fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons/serve", req.Organization)
+	if req.ProvisionerKey != "" {
+		path = "/api/v2/provisionerdaemons/serve"
+	}
+	serverURL, err := c.URL.Parse(path)
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
 	query := serverURL.Query()
-	for _, provisioner := range provisioners {
+	for _, provisioner := range req.Provisioners {
 		query.Add("provisioner", string(provisioner))
 	}
-	for key, value := range tags {
+	for key, value := range req.Tags {
 		query.Add("tag", fmt.Sprintf("%s=%s", key, value))
 	}
+	query.Set("version", buildinfo.Version())
 	serverURL.RawQuery = query.Encode()
-	jar, err := cookiejar.New(nil)
-	if err != nil {
-		return nil, xerrors.Errorf("create cookie jar: %w", err)
-	}
-	jar.SetCookies(serverURL, []*http.Cookie{{
-		Name:  SessionTokenCookie,
-		Value: c.SessionToken(),
-	}})
 	httpClient := &http.Client{
-		Jar:       jar,
 		Transport: c.HTTPClient.Transport,
 	}
+	headers := http.Header{}
+	if req.ProvisionerKey != "" {
+		headers.Set(ProvisionerDaemonKeyHeader, req.ProvisionerKey)
+	} else {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, xerrors.Errorf("create cookie jar: %w", err)
+		}
+		jar.SetCookies(serverURL, []*http.Cookie{{
+			Name:  SessionTokenCookie,
+			Value: c.SessionToken(),
+		}})
+		httpClient.Jar = jar
+	}
 	conn, res, err := websocket.Dial(ctx, serverURL.String(), &websocket.DialOptions{
 		HTTPClient: httpClient,
+		HTTPHeader: headers,
 		// Need to disable compression to avoid a data-race.
 		CompressionMode: websocket.CompressionDisabled,
 	})
@@ -0,0 +1,88 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// ProvisionerKey authenticates external provisioner daemons of an
+// organization. Daemons authenticated with a key only acquire jobs that
+// match the tags of the key.
+type ProvisionerKey struct {
+	ID             uuid.UUID         `json:"id" format:"uuid"`
+	CreatedAt      time.Time         `json:"created_at" format:"date-time"`
+	OrganizationID uuid.UUID         `json:"organization_id" format:"uuid"`
+	Name           string            `json:"name"`
+	Tags           map[string]string `json:"tags"`
+}
+
+type CreateProvisionerKeyRequest struct {
+	Name string            `json:"name" validate:"required,username"`
+	Tags map[string]string `json:"tags"`
+}
+
+type CreateProvisionerKeyResponse struct {
+	ProvisionerKey ProvisionerKey `json:"provisioner_key"`
+	// Key is only returned once, it can't be retrieved later.
+	Key string `json:"key"`
+}
+
+// CreateProvisionerKey creates a provisioner key for the organization.
+func (c *Client) CreateProvisionerKey(ctx context.Context, organizationID uuid.UUID, req CreateProvisionerKeyRequest) (CreateProvisionerKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys", organizationID.String()),
+		req,
+	)
+	if err != nil {
+		return CreateProvisionerKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return CreateProvisionerKeyResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateProvisionerKeyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ProvisionerKeys returns the provisioner keys of the organization.
+func (c *Client) ProvisionerKeys(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var keys []ProvisionerKey
+	return keys, json.NewDecoder(res.Body).Decode(&keys)
+}
+
+// DeleteProvisionerKey deletes a provisioner key by name. Daemons
+// authenticated with the key stop acquiring jobs.
+func (c *Client) DeleteProvisionerKey(ctx context.Context, organizationID uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys/%s", organizationID.String(), name),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -24,6 +24,8 @@ const (
 	ResourceDeploymentStats             RBACResource = "deployment_stats"
 	ResourceReplicas                    RBACResource = "replicas"
 	ResourceDebugInfo                   RBACResource = "debug_info"
+	ResourceWebhook                     RBACResource = "webhook"
+	ResourceCustomRole                  RBACResource = "custom_role"
 	ResourceSystem                      RBACResource = "system"
 )
 
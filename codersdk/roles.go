@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
 type Role struct {
@@ -46,3 +48,108 @@ func (c *Client) ListOrganizationRoles(ctx context.Context, org uuid.UUID) ([]As
 	var roles []AssignableRoles
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// Permission is a single permission granted (or, if negated, revoked) by a
+// custom role.
+type Permission struct {
+	// Negate revokes the permission instead of granting it.
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	// Action is one of "create", "read", "update", "delete" or "*" for all
+	// actions.
+	Action string `json:"action" enums:"create,read,update,delete,*"`
+}
+
+// CustomRole is a user defined role. Site wide roles grant permissions on
+// the whole deployment, organization roles only within their organization.
+type CustomRole struct {
+	// Name of the role. Organization roles are assigned to members as
+	// "<name>:<organization_id>".
+	Name           string     `json:"name"`
+	DisplayName    string     `json:"display_name"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	// SitePermissions apply to every resource on the deployment.
+	SitePermissions []Permission `json:"site_permissions"`
+	// OrganizationPermissions apply to every resource in the role's
+	// organization.
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	// UserPermissions apply to resources owned by the user with the role.
+	UserPermissions []Permission `json:"user_permissions"`
+	CreatedAt       time.Time    `json:"created_at" format:"date-time"`
+	UpdatedAt       time.Time    `json:"updated_at" format:"date-time"`
+}
+
+type CreateCustomRoleRequest struct {
+	Name                    string       `json:"name" validate:"required,username"`
+	DisplayName             string       `json:"display_name"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+}
+
+// UpdateCustomRoleRequest replaces the display name and all permissions of a
+// custom role.
+type UpdateCustomRoleRequest struct {
+	DisplayName             string       `json:"display_name"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+}
+
+// CustomRoles lists the site wide custom roles.
+func (c *Client) CustomRoles(ctx context.Context) ([]CustomRole, error) {
+	return c.customRoles(ctx, "/api/v2/roles")
+}
+
+// OrganizationCustomRoles lists the custom roles of an organization.
+func (c *Client) OrganizationCustomRoles(ctx context.Context, org uuid.UUID) ([]CustomRole, error) {
+	return c.customRoles(ctx, fmt.Sprintf("/api/v2/organizations/%s/roles", org.String()))
+}
+
+func (c *Client) customRoles(ctx context.Context, path string) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+// CreateCustomRole creates a site wide custom role.
+func (c *Client) CreateCustomRole(ctx context.Context, req CreateCustomRoleRequest) (CustomRole, error) {
+	return c.customRole(ctx, http.MethodPost, "/api/v2/roles", req, http.StatusCreated)
+}
+
+// CreateOrganizationCustomRole creates a custom role in an organization.
+func (c *Client) CreateOrganizationCustomRole(ctx context.Context, org uuid.UUID, req CreateCustomRoleRequest) (CustomRole, error) {
+	return c.customRole(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/roles", org.String()), req, http.StatusCreated)
+}
+
+// UpdateCustomRole updates a site wide custom role.
+func (c *Client) UpdateCustomRole(ctx context.Context, name string, req UpdateCustomRoleRequest) (CustomRole, error) {
+	return c.customRole(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/roles/%s", name), req, http.StatusOK)
+}
+
+// UpdateOrganizationCustomRole updates a custom role in an organization.
+func (c *Client) UpdateOrganizationCustomRole(ctx context.Context, org uuid.UUID, name string, req UpdateCustomRoleRequest) (CustomRole, error) {
+	return c.customRole(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s/roles/%s", org.String(), name), req, http.StatusOK)
+}
+
+func (c *Client) customRole(ctx context.Context, method, path string, req interface{}, expectedStatus int) (CustomRole, error) {
+	res, err := c.Request(ctx, method, path, req)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expectedStatus {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
@@ -30,9 +30,19 @@ type Template struct {
 	DefaultTTLMillis int64                  `json:"default_ttl_ms"`
 	// MaxTTLMillis is an enterprise feature. It's value is only used if your
 	// license is entitled to use the advanced template scheduling feature.
-	MaxTTLMillis  int64     `json:"max_ttl_ms"`
-	CreatedByID   uuid.UUID `json:"created_by_id" format:"uuid"`
-	CreatedByName string    `json:"created_by_name"`
+	MaxTTLMillis int64 `json:"max_ttl_ms"`
+	// InactivityTTLMillis, DormantAutoDeleteTTLMillis and FailureTTLMillis are
+	// enterprise-only. Their values are only used if your license is entitled
+	// to use the advanced template scheduling feature.
+	InactivityTTLMillis        int64     `json:"inactivity_ttl_ms"`
+	DormantAutoDeleteTTLMillis int64     `json:"dormant_autodelete_ttl_ms"`
+	FailureTTLMillis           int64     `json:"failure_ttl_ms"`
+	CreatedByID                uuid.UUID `json:"created_by_id" format:"uuid"`
+	CreatedByName              string    `json:"created_by_name"`
+
+	// RestartRequirement is an enterprise feature. Its value is only used if
+	// your license is entitled to use the advanced template scheduling feature.
+	RestartRequirement TemplateRestartRequirement `json:"restart_requirement"`
 
 	// AllowUserAutostart and AllowUserAutostop are enterprise-only. Their
 	// values are only used if your license is entitled to use the advanced
@@ -40,6 +50,88 @@ type Template struct {
 	AllowUserAutostart           bool `json:"allow_user_autostart"`
 	AllowUserAutostop            bool `json:"allow_user_autostop"`
 	AllowUserCancelWorkspaceJobs bool `json:"allow_user_cancel_workspace_jobs"`
+
+	// Deprecated templates can't be used to create new workspaces. The
+	// DeprecationMessage is displayed to users of existing workspaces.
+	Deprecated         bool   `json:"deprecated"`
+	DeprecationMessage string `json:"deprecation_message"`
+	// RequireActiveVersion starts workspaces of the template on its active
+	// version, regardless of their automatic updates setting.
+	RequireActiveVersion bool `json:"require_active_version"`
+	// MaxPortShareLevel is the highest share level that workspace owners can
+	// grant to ports of workspaces created from this template.
+	MaxPortShareLevel WorkspaceAppSharingLevel `json:"max_port_share_level" enums:"owner,authenticated,public"`
+	// SessionRecording records interactive terminal sessions in workspaces
+	// created from the template and links them from the audit log.
+	SessionRecording bool `json:"session_recording"`
+	// QuotaCostMultiplier multiplies the daily cost of workspaces created
+	// from the template before it's counted against quotas.
+	QuotaCostMultiplier float64 `json:"quota_cost_multiplier"`
+}
+
+// AllDaysOfWeek is the list of valid days of the week for template restart
+// requirements, in the order they are stored in the bitmap.
+var AllDaysOfWeek = []string{
+	"monday",
+	"tuesday",
+	"wednesday",
+	"thursday",
+	"friday",
+	"saturday",
+	"sunday",
+}
+
+type TemplateRestartRequirement struct {
+	// DaysOfWeek is a list of days of the week on which restarts are required.
+	// Restarts happen within the user's quiet hours (in their configured
+	// timezone). If no days are specified, restarts are not required. Weekdays
+	// cannot be specified twice.
+	//
+	// Restarts will only happen on weekdays in this list on weeks which line up
+	// with Weeks.
+	DaysOfWeek []string `json:"days_of_week" enums:"monday,tuesday,wednesday,thursday,friday,saturday,sunday"`
+	// Weeks is the number of weeks between required restarts. Weeks are synced
+	// across all workspaces (and Coder deployments) using modulo math on a
+	// hardcoded epoch week of January 2nd, 2023 (the first Monday of 2023).
+	// Values of 0 or 1 indicate weekly restarts. Values of 2 indicate
+	// fortnightly restarts, etc.
+	Weeks int64 `json:"weeks"`
+}
+
+// WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
+// the schedule package's rules. The 0th bit is Monday, ..., the 6th bit is
+// Sunday. The 7th bit is unused.
+func WeekdaysToBitmap(days []string) (uint8, error) {
+	var bitmap uint8
+	for _, day := range days {
+		found := false
+		for i, d := range AllDaysOfWeek {
+			if d == day {
+				if bitmap&(1<<uint(i)) != 0 {
+					return 0, xerrors.Errorf("duplicate day of week %q", day)
+				}
+				bitmap |= 1 << uint(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, xerrors.Errorf("invalid day of week %q", day)
+		}
+	}
+	return bitmap, nil
+}
+
+// BitmapToWeekdays converts a bitmap to a list of weekdays in accordance with
+// the schedule package's rules (see above).
+func BitmapToWeekdays(bitmap uint8) []string {
+	days := []string{}
+	for i, day := range AllDaysOfWeek {
+		if bitmap&(1<<uint(i)) != 0 {
+			days = append(days, day)
+		}
+	}
+	return days
 }
 
 type TransitionStats struct {
@@ -91,10 +183,40 @@ type UpdateTemplateMeta struct {
 	// MaxTTLMillis can only be set if your license includes the advanced
 	// template scheduling feature. If you attempt to set this value while
 	// unlicensed, it will be ignored.
-	MaxTTLMillis                 int64 `json:"max_ttl_ms,omitempty"`
+	MaxTTLMillis int64 `json:"max_ttl_ms,omitempty"`
+	// InactivityTTLMillis, DormantAutoDeleteTTLMillis and FailureTTLMillis can
+	// only be set if your license includes the advanced template scheduling
+	// feature. If you attempt to set these values while unlicensed, they will
+	// be ignored.
+	InactivityTTLMillis          int64 `json:"inactivity_ttl_ms,omitempty"`
+	DormantAutoDeleteTTLMillis   int64 `json:"dormant_autodelete_ttl_ms,omitempty"`
+	FailureTTLMillis             int64 `json:"failure_ttl_ms,omitempty"`
 	AllowUserAutostart           bool  `json:"allow_user_autostart,omitempty"`
 	AllowUserAutostop            bool  `json:"allow_user_autostop,omitempty"`
 	AllowUserCancelWorkspaceJobs bool  `json:"allow_user_cancel_workspace_jobs,omitempty"`
+	// RestartRequirement can only be set if your license includes the advanced
+	// template scheduling feature. If you attempt to set this value while
+	// unlicensed, it will be ignored. If nil, the current restart requirement
+	// is left unchanged.
+	RestartRequirement *TemplateRestartRequirement `json:"restart_requirement,omitempty"`
+	// DeprecationMessage deprecates the template if set to a non-empty
+	// string, which blocks new workspaces from being created from it. An
+	// empty string un-deprecates the template. If nil, the deprecation is
+	// left unchanged.
+	DeprecationMessage *string `json:"deprecation_message,omitempty"`
+	// RequireActiveVersion requires workspaces to be started on the active
+	// version of the template. If nil, the setting is left unchanged.
+	RequireActiveVersion *bool `json:"require_active_version,omitempty"`
+	// MaxPortShareLevel caps the share level of ports of workspaces created
+	// from this template. If nil, the setting is left unchanged.
+	MaxPortShareLevel *WorkspaceAppSharingLevel `json:"max_port_share_level,omitempty" enums:"owner,authenticated,public"`
+	// SessionRecording enables recording of interactive terminal sessions.
+	// If nil, the setting is left unchanged.
+	SessionRecording *bool `json:"session_recording,omitempty"`
+	// QuotaCostMultiplier multiplies the daily cost of workspaces created
+	// from the template. Must be between 0 and 1000. If nil, the multiplier
+	// is left unchanged.
+	QuotaCostMultiplier *float64 `json:"quota_cost_multiplier,omitempty"`
 }
 
 type TemplateExample struct {
@@ -188,6 +310,22 @@ func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.
 	return nil
 }
 
+// RollbackActiveTemplateVersion makes the version that was promoted before the
+// active version the active version of the template again. Repeated rollbacks
+// walk back through the promotion history. The new active version is returned.
+func (c *Client) RollbackActiveTemplateVersion(ctx context.Context, template uuid.UUID) (TemplateVersion, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/versions/rollback", template), nil)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersion{}, ReadBodyAsError(res)
+	}
+	var templateVersion TemplateVersion
+	return templateVersion, json.NewDecoder(res.Body).Decode(&templateVersion)
+}
+
 // TemplateVersionsByTemplateRequest defines the request parameters for
 // TemplateVersionsByTemplate.
 type TemplateVersionsByTemplateRequest struct {
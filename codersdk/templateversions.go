@@ -22,6 +22,12 @@ type TemplateVersion struct {
 	Job            ProvisionerJob `json:"job"`
 	Readme         string         `json:"readme"`
 	CreatedBy      User           `json:"created_by"`
+	// PromotedAt is the last time the version was made the active version of
+	// its template.
+	PromotedAt *time.Time `json:"promoted_at,omitempty" format:"date-time"`
+	// PromotedBy is the user that last made the version the active version
+	// of its template.
+	PromotedBy *User `json:"promoted_by,omitempty"`
 }
 
 type TemplateVersionGitAuth struct {
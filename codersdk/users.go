@@ -45,6 +45,10 @@ type User struct {
 	OrganizationIDs []uuid.UUID `json:"organization_ids" format:"uuid"`
 	Roles           []Role      `json:"roles"`
 	AvatarURL       string      `json:"avatar_url" format:"uri"`
+	// QuietHoursSchedule is the user's custom quiet hours schedule. If empty,
+	// the deployment default is used instead. Quiet hours are when workspaces
+	// are restarted to satisfy template restart requirements.
+	QuietHoursSchedule string `json:"quiet_hours_schedule"`
 }
 
 type GetUsersResponse struct {
@@ -194,6 +198,62 @@ func (c *Client) UpdateUserProfile(ctx context.Context, user string, req UpdateU
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+type UserQuietHoursScheduleResponse struct {
+	RawSchedule string `json:"raw_schedule"`
+	// UserSet is true if the user has set their own quiet hours schedule. If
+	// false, the user is using the default schedule.
+	UserSet bool `json:"user_set"`
+	// Time is the time of day that the quiet hours window starts in the given
+	// Timezone each day.
+	Time     string `json:"time"`     // HH:mm (24-hour)
+	Timezone string `json:"timezone"` // raw format from the cron expression, UTC if unspecified
+	// Next is the next time that the quiet hours window will start.
+	Next time.Time `json:"next" format:"date-time"`
+}
+
+type UpdateUserQuietHoursScheduleRequest struct {
+	// Schedule is a cron expression that defines when the user's quiet hours
+	// window starts. Workspaces are restarted during quiet hours to satisfy
+	// template restart requirements.
+	//
+	// The schedule must be daily with a single time, and should have a timezone
+	// specified via a CRON_TZ prefix (otherwise UTC will be used).
+	//
+	// If the schedule is empty, the user will be updated to use the default
+	// schedule.
+	Schedule string `json:"schedule"`
+}
+
+// UserQuietHoursSchedule returns the quiet hours settings for the user. This
+// endpoint only exists in enterprise editions.
+func (c *Client) UserQuietHoursSchedule(ctx context.Context, userIdent string) (UserQuietHoursScheduleResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/quiet-hours", userIdent), nil)
+	if err != nil {
+		return UserQuietHoursScheduleResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserQuietHoursScheduleResponse{}, ReadBodyAsError(res)
+	}
+	var resp UserQuietHoursScheduleResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateUserQuietHoursSchedule updates the quiet hours settings for the user.
+// This endpoint only exists in enterprise editions.
+func (c *Client) UpdateUserQuietHoursSchedule(ctx context.Context, userIdent string, req UpdateUserQuietHoursScheduleRequest) (UserQuietHoursScheduleResponse, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/quiet-hours", userIdent), req)
+	if err != nil {
+		return UserQuietHoursScheduleResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserQuietHoursScheduleResponse{}, ReadBodyAsError(res)
+	}
+	var resp UserQuietHoursScheduleResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // UpdateUserStatus sets the user status to the given status
 func (c *Client) UpdateUserStatus(ctx context.Context, user string, status UserStatus) (User, error) {
 	path := fmt.Sprintf("/api/v2/users/%s/status/", user)
@@ -0,0 +1,217 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted   WebhookEvent = "workspace_build_started"
+	WebhookEventWorkspaceBuildSucceeded WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build_failed"
+	WebhookEventTemplateVersionPromoted WebhookEvent = "template_version_promoted"
+	WebhookEventUserCreated             WebhookEvent = "user_created"
+	WebhookEventUserSuspended           WebhookEvent = "user_suspended"
+)
+
+const (
+	// WebhookEventHeader contains the WebhookEvent of a delivery.
+	WebhookEventHeader = "X-Coder-Event"
+	// WebhookDeliveryHeader contains the unique ID of a delivery. It is
+	// stable across retries.
+	WebhookDeliveryHeader = "X-Coder-Delivery"
+	// WebhookSignatureHeader contains the hex-encoded HMAC-SHA256 of the
+	// request body keyed with the webhook secret, prefixed with "sha256=".
+	WebhookSignatureHeader = "X-Coder-Signature"
+)
+
+// Webhook is an admin-configured URL that receives a signed POST request
+// whenever one of the subscribed events occurs.
+type Webhook struct {
+	ID        uuid.UUID      `json:"id" format:"uuid"`
+	Name      string         `json:"name"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Enabled   bool           `json:"enabled"`
+	CreatedBy uuid.UUID      `json:"created_by" format:"uuid"`
+	CreatedAt time.Time      `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time      `json:"updated_at" format:"date-time"`
+}
+
+type CreateWebhookRequest struct {
+	Name   string         `json:"name" validate:"required"`
+	URL    string         `json:"url" validate:"required"`
+	Events []WebhookEvent `json:"events" validate:"required"`
+	// Secret is used to sign deliveries. A random secret is generated if
+	// empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+type CreateWebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+	// Secret is only returned when the webhook is created.
+	Secret string `json:"secret"`
+}
+
+type UpdateWebhookRequest struct {
+	Name    *string         `json:"name,omitempty"`
+	URL     *string         `json:"url,omitempty"`
+	Events  *[]WebhookEvent `json:"events,omitempty"`
+	Enabled *bool           `json:"enabled,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID          uuid.UUID       `json:"id" format:"uuid"`
+	WebhookID   uuid.UUID       `json:"webhook_id" format:"uuid"`
+	Event       WebhookEvent    `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at" format:"date-time"`
+	Attempts    int32           `json:"attempts"`
+	DeliveredAt *time.Time      `json:"delivered_at,omitempty" format:"date-time"`
+	// NextAttemptAt is nil once the delivery succeeded or ran out of
+	// attempts.
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" format:"date-time"`
+	LastStatusCode int32      `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+}
+
+// WebhookPayload is the body POSTed to webhook URLs. Data contains one of
+// the WebhookData* types depending on the Event.
+type WebhookPayload struct {
+	// ID identifies the event. Every webhook subscribed to the event
+	// receives the same ID.
+	ID        uuid.UUID       `json:"id" format:"uuid"`
+	Event     WebhookEvent    `json:"event"`
+	Timestamp time.Time       `json:"timestamp" format:"date-time"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookDataWorkspaceBuild is sent with the workspace_build_* events.
+type WebhookDataWorkspaceBuild struct {
+	WorkspaceID        uuid.UUID           `json:"workspace_id" format:"uuid"`
+	WorkspaceName      string              `json:"workspace_name"`
+	WorkspaceOwnerID   uuid.UUID           `json:"workspace_owner_id" format:"uuid"`
+	WorkspaceOwnerName string              `json:"workspace_owner_name"`
+	TemplateID         uuid.UUID           `json:"template_id" format:"uuid"`
+	TemplateName       string              `json:"template_name"`
+	TemplateVersionID  uuid.UUID           `json:"template_version_id" format:"uuid"`
+	BuildID            uuid.UUID           `json:"build_id" format:"uuid"`
+	BuildNumber        int32               `json:"build_number"`
+	Transition         WorkspaceTransition `json:"transition"`
+	Reason             BuildReason         `json:"reason"`
+	// Error is only set for workspace_build_failed.
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookDataTemplateVersionPromoted is sent with the
+// template_version_promoted event.
+type WebhookDataTemplateVersionPromoted struct {
+	OrganizationID            uuid.UUID `json:"organization_id" format:"uuid"`
+	TemplateID                uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName              string    `json:"template_name"`
+	TemplateVersionID         uuid.UUID `json:"template_version_id" format:"uuid"`
+	TemplateVersionName       string    `json:"template_version_name"`
+	PreviousTemplateVersionID uuid.UUID `json:"previous_template_version_id" format:"uuid"`
+	PromotedBy                uuid.UUID `json:"promoted_by" format:"uuid"`
+}
+
+// WebhookDataUser is sent with the user_* events.
+type WebhookDataUser struct {
+	UserID   uuid.UUID  `json:"user_id" format:"uuid"`
+	Username string     `json:"username"`
+	Email    string     `json:"email"`
+	Status   UserStatus `json:"status"`
+}
+
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var webhooks []Webhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+func (c *Client) Webhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (CreateWebhookResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return CreateWebhookResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return CreateWebhookResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateWebhookResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/webhooks/%s", id), req)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var deliveries []WebhookDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
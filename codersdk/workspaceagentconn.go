@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -159,6 +160,37 @@ type WorkspaceAgentReconnectingPTYInit struct {
 	Height  uint16
 	Width   uint16
 	Command string
+	// Name is set for named sessions, see ReconnectingPTYSessionID.
+	Name string `json:",omitempty"`
+}
+
+// reconnectingPTYNamespace derives the IDs of named reconnecting PTY sessions.
+var reconnectingPTYNamespace = uuid.MustParse("5ec0a1b4-1a6e-4f4a-9d8a-2d3f5a7a6c01")
+
+// ReconnectingPTYSessionID returns the ID of the reconnecting PTY session with
+// the given name. The ID is the same for every client, so a named session can
+// be reattached from anywhere, even after the agent restarted.
+func ReconnectingPTYSessionID(name string) uuid.UUID {
+	return uuid.NewSHA1(reconnectingPTYNamespace, []byte(name))
+}
+
+// WorkspaceAgentReconnectingPTY is a session of the web terminal or of
+// "coder ssh --session".
+type WorkspaceAgentReconnectingPTY struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// Name is empty for sessions of the web terminal.
+	Name string `json:"name,omitempty"`
+	// Backend is "buffered" for sessions kept in the memory of the agent, or
+	// "tmux" or "screen" for sessions that keep running when the agent
+	// restarts.
+	Backend string `json:"backend"`
+	// Command is empty for sessions that run the shell of the user.
+	Command           string `json:"command"`
+	ActiveConnections int    `json:"active_connections"`
+}
+
+type WorkspaceAgentReconnectingPTYsResponse struct {
+	Sessions []WorkspaceAgentReconnectingPTY `json:"sessions"`
 }
 
 // ReconnectingPTYRequest is sent from the client to the server
@@ -174,6 +206,27 @@ type ReconnectingPTYRequest struct {
 // `ReconnectingPTYRequest` should be JSON marshaled and written to the returned net.Conn.
 // Raw terminal output will be read from the returned net.Conn.
 func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string) (net.Conn, error) {
+	return c.reconnectingPTY(ctx, WorkspaceAgentReconnectingPTYInit{
+		ID:      id,
+		Height:  height,
+		Width:   width,
+		Command: command,
+	})
+}
+
+// NamedReconnectingPTY attaches to the reconnecting terminal session with the
+// given name, and starts it with the command if it doesn't exist.
+func (c *WorkspaceAgentConn) NamedReconnectingPTY(ctx context.Context, name string, height, width uint16, command string) (net.Conn, error) {
+	return c.reconnectingPTY(ctx, WorkspaceAgentReconnectingPTYInit{
+		ID:      ReconnectingPTYSessionID(name),
+		Height:  height,
+		Width:   width,
+		Command: command,
+		Name:    name,
+	})
+}
+
+func (c *WorkspaceAgentConn) reconnectingPTY(ctx context.Context, init WorkspaceAgentReconnectingPTYInit) (net.Conn, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 	if !c.AwaitReachable(ctx) {
@@ -183,12 +236,7 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	data, err := json.Marshal(WorkspaceAgentReconnectingPTYInit{
-		ID:      id,
-		Height:  height,
-		Width:   width,
-		Command: command,
-	})
+	data, err := json.Marshal(init)
 	if err != nil {
 		_ = conn.Close()
 		return nil, err
@@ -305,6 +353,179 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// ReconnectingPTYs lists the reconnecting terminal sessions of the agent.
+func (c *WorkspaceAgentConn) ReconnectingPTYs(ctx context.Context) (WorkspaceAgentReconnectingPTYsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/reconnecting-ptys", nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYsResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYsResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentReconnectingPTYsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// FlushDebugLogs makes the agent upload all of its buffered debug logs to
+// coderd. It returns after the logs have been uploaded.
+func (c *WorkspaceAgentConn) FlushDebugLogs(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/debug/logs/flush", nil)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceAgentFileInfo describes a file or directory in a workspace.
+type WorkspaceAgentFileInfo struct {
+	// Path is the absolute path of the file in the workspace.
+	Path    string    `json:"path"`
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	Mode    uint32    `json:"mode"` // permission bits only
+	ModTime time.Time `json:"mod_time" format:"date-time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+type WorkspaceAgentListFilesResponse struct {
+	Files []WorkspaceAgentFileInfo `json:"files"`
+}
+
+type WorkspaceAgentFileHashResponse struct {
+	// Length is the number of bytes that were hashed, which is less than the
+	// requested length if the file is shorter.
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// StatFile returns information about a file or directory in the workspace.
+// Relative paths are resolved against the home directory of the agent's user.
+func (c *WorkspaceAgentConn) StatFile(ctx context.Context, path string) (WorkspaceAgentFileInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("stat", path, nil), nil)
+	if err != nil {
+		return WorkspaceAgentFileInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFileInfo{}, ReadBodyAsError(res)
+	}
+
+	var info WorkspaceAgentFileInfo
+	return info, json.NewDecoder(res.Body).Decode(&info)
+}
+
+// ListFiles lists the direct children of a directory in the workspace.
+func (c *WorkspaceAgentConn) ListFiles(ctx context.Context, path string) (WorkspaceAgentListFilesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("list", path, nil), nil)
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// HashFile returns the SHA-256 of the first length bytes of a file in the
+// workspace.
+func (c *WorkspaceAgentConn) HashFile(ctx context.Context, path string, length int64) (WorkspaceAgentFileHashResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("hash", path, url.Values{
+		"length": {strconv.FormatInt(length, 10)},
+	}), nil)
+	if err != nil {
+		return WorkspaceAgentFileHashResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFileHashResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentFileHashResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ReadFile streams the contents of a file in the workspace, starting at the
+// given offset. The caller must close the returned reader.
+func (c *WorkspaceAgentConn) ReadFile(ctx context.Context, path string, offset int64) (io.ReadCloser, int64, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesAPIPath("read", path, url.Values{
+		"offset": {strconv.FormatInt(offset, 10)},
+	}), nil)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, 0, ReadBodyAsError(res)
+	}
+	return res.Body, res.ContentLength, nil
+}
+
+// WriteFile writes the contents of r to a file in the workspace, starting at
+// the given offset. Content past the offset is discarded, and the file's
+// permission bits are set to mode. Parent directories are created as needed.
+func (c *WorkspaceAgentConn) WriteFile(ctx context.Context, path string, offset int64, mode os.FileMode, r io.Reader) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, filesAPIPath("write", path, url.Values{
+		"offset": {strconv.FormatInt(offset, 10)},
+		"mode":   {strconv.FormatUint(uint64(mode.Perm()), 8)},
+	}), r)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// MakeDirectory creates a directory and any missing parents in the workspace.
+func (c *WorkspaceAgentConn) MakeDirectory(ctx context.Context, path string, mode os.FileMode) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, filesAPIPath("mkdir", path, url.Values{
+		"mode": {strconv.FormatUint(uint64(mode.Perm()), 8)},
+	}), nil)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func filesAPIPath(endpoint, path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("path", path)
+	return fmt.Sprintf("/api/v0/files/%s?%s", endpoint, query.Encode())
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
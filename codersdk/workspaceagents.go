@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/netip"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -58,6 +59,17 @@ const (
 	WorkspaceAgentLifecycleOff             WorkspaceAgentLifecycle = "off"
 )
 
+// Starting returns true if the agent has not yet finished running the
+// startup script.
+func (l WorkspaceAgentLifecycle) Starting() bool {
+	switch l {
+	case WorkspaceAgentLifecycleCreated, WorkspaceAgentLifecycleStarting, WorkspaceAgentLifecycleStartTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
 // WorkspaceAgentLifecycleOrder is the order in which workspace agent
 // lifecycle states are expected to be reported during the lifetime of
 // the agent process. For instance, the agent can go from starting to
@@ -101,6 +113,45 @@ type WorkspaceAgentMetadata struct {
 	Description WorkspaceAgentMetadataDescription `json:"description"`
 }
 
+type WorkspaceAgentScriptStatus string
+
+const (
+	WorkspaceAgentScriptStatusPending  WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning  WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusOK       WorkspaceAgentScriptStatus = "ok"
+	WorkspaceAgentScriptStatusError    WorkspaceAgentScriptStatus = "error"
+	WorkspaceAgentScriptStatusTimedOut WorkspaceAgentScriptStatus = "timed_out"
+)
+
+// WorkspaceAgentScript is a named script run by the agent. It is provided via
+// `coder_script` resources that reference the agent. Each script streams its
+// output as a separate log source, identified by the script ID.
+type WorkspaceAgentScript struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	DisplayName string    `json:"display_name"`
+	Script      string    `json:"script"`
+	// Cron is a cron expression on which the script runs in addition to
+	// RunOnStart and RunOnStop.
+	Cron string `json:"cron,omitempty"`
+	// RunOrder orders scripts that run on start. A script waits for the
+	// StartBlocksLogin scripts with a lower order to finish first.
+	RunOrder int32 `json:"run_order"`
+	// StartBlocksLogin scripts must finish before the agent is ready.
+	StartBlocksLogin bool  `json:"start_blocks_login"`
+	RunOnStart       bool  `json:"run_on_start"`
+	RunOnStop        bool  `json:"run_on_stop"`
+	TimeoutSeconds   int32 `json:"timeout_seconds"`
+	// LogPath is the path of the log file in the workspace, relative to the
+	// agent log directory.
+	LogPath string `json:"log_path,omitempty"`
+	// Status, ExitCode, StartedAt and CompletedAt describe the latest run of
+	// the script.
+	Status      WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode    int32                      `json:"exit_code"`
+	StartedAt   *time.Time                 `json:"started_at,omitempty" format:"date-time"`
+	CompletedAt *time.Time                 `json:"completed_at,omitempty" format:"date-time"`
+}
+
 type WorkspaceAgent struct {
 	ID                    uuid.UUID               `json:"id" format:"uuid"`
 	CreatedAt             time.Time               `json:"created_at" format:"date-time"`
@@ -123,6 +174,7 @@ type WorkspaceAgent struct {
 	ExpandedDirectory     string                  `json:"expanded_directory,omitempty"`
 	Version               string                  `json:"version"`
 	Apps                  []WorkspaceApp          `json:"apps"`
+	Scripts               []WorkspaceAgentScript  `json:"scripts"`
 	// DERPLatency is mapped by region name (e.g. "New York City", "Seattle").
 	DERPLatency              map[string]DERPRegion `json:"latency,omitempty"`
 	ConnectionTimeoutSeconds int32                 `json:"connection_timeout_seconds"`
@@ -133,6 +185,9 @@ type WorkspaceAgent struct {
 	StartupScriptTimeoutSeconds  int32  `json:"startup_script_timeout_seconds"`
 	ShutdownScript               string `json:"shutdown_script,omitempty"`
 	ShutdownScriptTimeoutSeconds int32  `json:"shutdown_script_timeout_seconds"`
+	// ParentID is the agent that runs the dev container of this agent. It's
+	// empty for agents of the template.
+	ParentID *uuid.UUID `json:"parent_id,omitempty" format:"uuid"`
 }
 
 type DERPRegion struct {
@@ -152,6 +207,10 @@ type DialWorkspaceAgentOptions struct {
 	Logger slog.Logger
 	// BlockEndpoints forced a direct connection through DERP.
 	BlockEndpoints bool
+	// ProxyURL is the URL of a workspace proxy that relays the connection
+	// to the DERP server embedded in coderd. The primary access URL is used
+	// when it's nil.
+	ProxyURL *url.URL
 }
 
 func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
@@ -171,6 +230,12 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	if err != nil {
 		return nil, xerrors.Errorf("decode conn info: %w", err)
 	}
+	if options.ProxyURL != nil {
+		err = relayThroughProxy(connInfo.DERPMap, options.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	ip := tailnet.IP()
 	var header http.Header
@@ -348,6 +413,51 @@ func (c *Client) WatchWorkspaceAgentMetadata(ctx context.Context, id uuid.UUID)
 	return metadataChan, errorChan
 }
 
+// relayThroughProxy converts all built-in DERPs to connect through the
+// workspace proxy, which forwards the connections to the DERP server of
+// coderd. The proxy doesn't serve STUN, so nodes that do keep a STUN-only
+// copy that points at coderd.
+func relayThroughProxy(derpMap *tailcfg.DERPMap, proxyURL *url.URL) error {
+	proxyPort := proxyURL.Port()
+	if proxyPort == "" {
+		proxyPort = "80"
+		if proxyURL.Scheme == "https" {
+			proxyPort = "443"
+		}
+	}
+	port, err := strconv.Atoi(proxyPort)
+	if err != nil {
+		return xerrors.Errorf("convert proxy port %q: %w", proxyPort, err)
+	}
+	for _, region := range derpMap.Regions {
+		if !region.EmbeddedRelay {
+			continue
+		}
+		nodes := make([]*tailcfg.DERPNode, 0, len(region.Nodes))
+		for _, node := range region.Nodes {
+			if node.STUNOnly {
+				nodes = append(nodes, node)
+				continue
+			}
+			if node.STUNPort >= 0 {
+				stunNode := node.Clone()
+				stunNode.Name += "stun"
+				stunNode.STUNOnly = true
+				nodes = append(nodes, stunNode)
+			}
+			node.HostName = proxyURL.Hostname()
+			node.IPv4 = ""
+			node.IPv6 = ""
+			node.DERPPort = port
+			node.STUNPort = -1
+			node.ForceHTTP = proxyURL.Scheme == "http"
+			nodes = append(nodes, node)
+		}
+		region.Nodes = nodes
+	}
+	return nil
+}
+
 // WorkspaceAgent returns an agent by ID.
 func (c *Client) WorkspaceAgent(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s", id), nil)
@@ -416,6 +526,50 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentReconnectingPTYs lists the sessions of the web terminal and
+// of "coder ssh --session" that can be reattached.
+func (c *Client) WorkspaceAgentReconnectingPTYs(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentReconnectingPTYsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/reconnecting-ptys", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYsResponse{}, ReadBodyAsError(res)
+	}
+	var sessions WorkspaceAgentReconnectingPTYsResponse
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
+
+// WorkspaceAgentDebugLogs returns the debug logs uploaded by an agent with an
+// ID greater than after, oldest first.
+func (c *Client) WorkspaceAgentDebugLogs(ctx context.Context, agentID uuid.UUID, after int64) ([]WorkspaceAgentDebugLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/debug-logs?after=%d", agentID, after), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []WorkspaceAgentDebugLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
+// FlushWorkspaceAgentDebugLogs asks a connected agent to upload all of the
+// debug logs it has buffered.
+func (c *Client) FlushWorkspaceAgentDebugLogs(ctx context.Context, agentID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/debug-logs/flush", agentID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 func (c *Client) WorkspaceAgentStartupLogsAfter(ctx context.Context, agentID uuid.UUID, after int64) (<-chan []WorkspaceAgentStartupLog, io.Closer, error) {
 	afterQuery := ""
 	if after != 0 {
@@ -506,4 +660,16 @@ type WorkspaceAgentStartupLog struct {
 	CreatedAt time.Time `json:"created_at" format:"date-time"`
 	Output    string    `json:"output"`
 	Level     LogLevel  `json:"level"`
+	// ScriptID is the script that produced the log. It is the nil UUID for
+	// the legacy startup script.
+	ScriptID uuid.UUID `json:"script_id" format:"uuid"`
+}
+
+// WorkspaceAgentDebugLog is a log of the agent itself, as opposed to the
+// output of its scripts.
+type WorkspaceAgentDebugLog struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	Output    string    `json:"output"`
+	Level     LogLevel  `json:"level"`
 }
@@ -0,0 +1,75 @@
+package codersdk
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
+)
+
+func TestRelayThroughProxy(t *testing.T) {
+	t.Parallel()
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1: {
+				EmbeddedRelay: true,
+				RegionID:      1,
+				Nodes: []*tailcfg.DERPNode{{
+					Name:     "1stun0",
+					RegionID: 1,
+					HostName: "stun.example.com",
+					STUNOnly: true,
+					STUNPort: 3478,
+				}, {
+					Name:     "1a",
+					RegionID: 1,
+					HostName: "coder.example.com",
+					IPv4:     "10.0.0.1",
+					DERPPort: 443,
+					STUNPort: 3479,
+				}},
+			},
+			2: {
+				RegionID: 2,
+				Nodes: []*tailcfg.DERPNode{{
+					Name:     "2a",
+					RegionID: 2,
+					HostName: "derp.example.com",
+					DERPPort: 443,
+				}},
+			},
+		},
+	}
+	proxyURL, err := url.Parse("http://proxy.example.com:8080")
+	require.NoError(t, err)
+
+	err = relayThroughProxy(derpMap, proxyURL)
+	require.NoError(t, err)
+
+	nodes := derpMap.Regions[1].Nodes
+	require.Len(t, nodes, 3)
+	require.Equal(t, "stun.example.com", nodes[0].HostName)
+	// STUN keeps pointing at coderd, since the proxy only relays DERP.
+	require.Equal(t, &tailcfg.DERPNode{
+		Name:     "1astun",
+		RegionID: 1,
+		HostName: "coder.example.com",
+		IPv4:     "10.0.0.1",
+		DERPPort: 443,
+		STUNPort: 3479,
+		STUNOnly: true,
+	}, nodes[1])
+	require.Equal(t, &tailcfg.DERPNode{
+		Name:      "1a",
+		RegionID:  1,
+		HostName:  "proxy.example.com",
+		DERPPort:  8080,
+		STUNPort:  -1,
+		ForceHTTP: true,
+	}, nodes[2])
+
+	// Regions that aren't embedded in coderd aren't relayed.
+	require.Equal(t, "derp.example.com", derpMap.Regions[2].Nodes[0].HostName)
+}
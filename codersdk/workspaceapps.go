@@ -21,6 +21,14 @@ const (
 	WorkspaceAppSharingLevelPublic        WorkspaceAppSharingLevel = "public"
 )
 
+type WorkspaceAppHealthcheckType string
+
+const (
+	WorkspaceAppHealthcheckTypeHTTP    WorkspaceAppHealthcheckType = "http"
+	WorkspaceAppHealthcheckTypeTCP     WorkspaceAppHealthcheckType = "tcp"
+	WorkspaceAppHealthcheckTypeCommand WorkspaceAppHealthcheckType = "command"
+)
+
 type WorkspaceApp struct {
 	ID uuid.UUID `json:"id" format:"uuid"`
 	// URL is the address being proxied to inside the workspace.
@@ -49,8 +57,23 @@ type WorkspaceApp struct {
 }
 
 type Healthcheck struct {
+	// Type specifies how the app health is checked. HTTP checks succeed
+	// when URL responds with a non-5xx status, TCP checks when a connection
+	// to Address can be established, and command checks when Command exits
+	// with one of ExpectedExitCodes.
+	Type WorkspaceAppHealthcheckType `json:"type" enums:"http,tcp,command"`
 	// URL specifies the endpoint to check for the app health.
 	URL string `json:"url"`
+	// Address specifies the host and port to connect to for TCP checks.
+	Address string `json:"address,omitempty"`
+	// Command specifies the script to run for command checks.
+	Command string `json:"command,omitempty"`
+	// ExpectedExitCodes specifies the exit codes of Command that are
+	// considered healthy. Defaults to 0.
+	ExpectedExitCodes []int32 `json:"expected_exit_codes,omitempty"`
+	// OutputRegex, if set, must match the output of Command for the app to
+	// be considered healthy.
+	OutputRegex string `json:"output_regex,omitempty"`
 	// Interval specifies the seconds between each health check.
 	Interval int32 `json:"interval"`
 	// Threshold specifies the number of consecutive failed health checks before returning "unhealthy".
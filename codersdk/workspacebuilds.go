@@ -46,6 +46,18 @@ const (
 	// "autostop" is used when a build to stop a workspace is triggered by Autostop.
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonAutostop BuildReason = "autostop"
+	// "dormancy" is used when a build to stop a workspace is triggered because
+	// the workspace was marked dormant after a period of inactivity.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonDormancy BuildReason = "dormancy"
+	// "autodelete" is used when a build to delete a workspace is triggered
+	// because the workspace was dormant for longer than the template allows.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonAutodelete BuildReason = "autodelete"
+	// "failedstop" is used when a build to stop a workspace is triggered
+	// because its start build failed longer ago than the template's failure TTL.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonFailedStop BuildReason = "failedstop"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
@@ -65,7 +77,7 @@ type WorkspaceBuild struct {
 	InitiatorID         uuid.UUID           `json:"initiator_id" format:"uuid"`
 	InitiatorUsername   string              `json:"initiator_name"`
 	Job                 ProvisionerJob      `json:"job"`
-	Reason              BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
+	Reason              BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop,dormancy,autodelete,failedstop"`
 	Resources           []WorkspaceResource `json:"resources"`
 	Deadline            NullTime            `json:"deadline,omitempty" format:"date-time"`
 	MaxDeadline         NullTime            `json:"max_deadline,omitempty" format:"date-time"`
@@ -102,6 +114,52 @@ type WorkspaceBuildParameter struct {
 	Value string `json:"value"`
 }
 
+// WorkspaceBuildTimingStage is a stage of a workspace build that timings are
+// recorded for.
+type WorkspaceBuildTimingStage string
+
+const (
+	// Stages run by the provisioner.
+	WorkspaceBuildTimingStageInit  WorkspaceBuildTimingStage = "init"
+	WorkspaceBuildTimingStagePlan  WorkspaceBuildTimingStage = "plan"
+	WorkspaceBuildTimingStageGraph WorkspaceBuildTimingStage = "graph"
+	WorkspaceBuildTimingStageApply WorkspaceBuildTimingStage = "apply"
+	// Stages run by the workspace agents.
+	WorkspaceBuildTimingStageConnect       WorkspaceBuildTimingStage = "connect"
+	WorkspaceBuildTimingStageStartupScript WorkspaceBuildTimingStage = "startup_script"
+)
+
+// ProvisionerTiming is the time the provisioner spent on a stage of a
+// workspace build, or on a resource within the stage.
+type ProvisionerTiming struct {
+	JobID     uuid.UUID                 `json:"job_id" format:"uuid"`
+	StartedAt time.Time                 `json:"started_at" format:"date-time"`
+	EndedAt   time.Time                 `json:"ended_at" format:"date-time"`
+	Stage     WorkspaceBuildTimingStage `json:"stage" enums:"init,plan,graph,apply"`
+	// Source is the Terraform provider of the resource.
+	Source string `json:"source"`
+	Action string `json:"action"`
+	// Resource is the address of the resource. It is empty for the timing of
+	// the whole stage.
+	Resource string `json:"resource"`
+}
+
+// AgentTiming is the time a workspace agent spent on a stage of starting.
+type AgentTiming struct {
+	WorkspaceAgentID   uuid.UUID                 `json:"workspace_agent_id" format:"uuid"`
+	WorkspaceAgentName string                    `json:"workspace_agent_name"`
+	StartedAt          time.Time                 `json:"started_at" format:"date-time"`
+	EndedAt            time.Time                 `json:"ended_at" format:"date-time"`
+	Stage              WorkspaceBuildTimingStage `json:"stage" enums:"connect,startup_script"`
+}
+
+// WorkspaceBuildTimings breaks down where the time of a workspace build was
+// spent. Stages that did not complete are omitted.
+type WorkspaceBuildTimings struct {
+	ProvisionerTimings []ProvisionerTiming `json:"provisioner_timings"`
+	AgentTimings       []AgentTiming       `json:"agent_timings"`
+}
+
 // WorkspaceBuild returns a single workspace build for a workspace.
 // If history is "", the latest version is returned.
 func (c *Client) WorkspaceBuild(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
@@ -161,6 +219,20 @@ func (c *Client) WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx cont
 	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
 }
 
+// WorkspaceBuildTimings returns the timings of the stages of a workspace build.
+func (c *Client) WorkspaceBuildTimings(ctx context.Context, build uuid.UUID) (WorkspaceBuildTimings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/timings", build), nil)
+	if err != nil {
+		return WorkspaceBuildTimings{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildTimings{}, ReadBodyAsError(res)
+	}
+	var timings WorkspaceBuildTimings
+	return timings, json.NewDecoder(res.Body).Decode(&timings)
+}
+
 func (c *Client) WorkspaceBuildParameters(ctx context.Context, build uuid.UUID) ([]WorkspaceBuildParameter, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/parameters", build), nil)
 	if err != nil {
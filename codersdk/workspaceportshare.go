@@ -0,0 +1,88 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type WorkspacePortShareProtocol string
+
+const (
+	WorkspacePortShareProtocolHTTP  WorkspacePortShareProtocol = "http"
+	WorkspacePortShareProtocolHTTPS WorkspacePortShareProtocol = "https"
+)
+
+// WorkspacePortShare allows users other than the workspace owner to access a
+// port listening in a workspace agent through a subdomain app URL.
+type WorkspacePortShare struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	AgentName   string    `json:"agent_name"`
+	Port        int32     `json:"port"`
+	// ShareLevel is capped by the template's max port sharing level when the
+	// port is accessed.
+	ShareLevel WorkspaceAppSharingLevel `json:"share_level" enums:"owner,authenticated,public"`
+	// Protocol is the scheme used to proxy requests to the port.
+	Protocol WorkspacePortShareProtocol `json:"protocol" enums:"http,https"`
+}
+
+type WorkspacePortShares struct {
+	Shares []WorkspacePortShare `json:"shares"`
+}
+
+type UpsertWorkspacePortShareRequest struct {
+	AgentName  string                     `json:"agent_name" validate:"required"`
+	Port       int32                      `json:"port" validate:"required"`
+	ShareLevel WorkspaceAppSharingLevel   `json:"share_level" validate:"required" enums:"owner,authenticated,public"`
+	Protocol   WorkspacePortShareProtocol `json:"protocol" enums:"http,https"`
+}
+
+type DeleteWorkspacePortShareRequest struct {
+	AgentName string `json:"agent_name" validate:"required"`
+	Port      int32  `json:"port" validate:"required"`
+}
+
+// WorkspacePortShares returns the ports shared by the given workspace.
+func (c *Client) WorkspacePortShares(ctx context.Context, workspaceID uuid.UUID) (WorkspacePortShares, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), nil)
+	if err != nil {
+		return WorkspacePortShares{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspacePortShares{}, ReadBodyAsError(res)
+	}
+	var shares WorkspacePortShares
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// UpsertWorkspacePortShare shares a port of a workspace agent, or updates the
+// existing share of the port.
+func (c *Client) UpsertWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req UpsertWorkspacePortShareRequest) (WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return WorkspacePortShare{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspacePortShare{}, ReadBodyAsError(res)
+	}
+	var share WorkspacePortShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspacePortShare stops sharing a port of a workspace agent.
+func (c *Client) DeleteWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req DeleteWorkspacePortShareRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -3,7 +3,12 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"sync"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -11,10 +16,39 @@ import (
 	"github.com/google/uuid"
 )
 
+type ProxyHealthStatus string
+
+const (
+	// ProxyHealthy means the proxy registers with coderd and its URL is
+	// reachable.
+	ProxyHealthy ProxyHealthStatus = "ok"
+	// ProxyUnregistered means the proxy never registered with coderd, e.g.
+	// because it was never started with its token.
+	ProxyUnregistered ProxyHealthStatus = "unregistered"
+	// ProxyUnresponsive means the proxy stopped registering with coderd, e.g.
+	// because it was shut down.
+	ProxyUnresponsive ProxyHealthStatus = "unresponsive"
+	// ProxyUnreachable means the proxy registers with coderd, but coderd
+	// can't reach it at its URL.
+	ProxyUnreachable ProxyHealthStatus = "unreachable"
+)
+
+type WorkspaceProxyStatus struct {
+	Status ProxyHealthStatus `json:"status" enums:"ok,unregistered,unresponsive,unreachable"`
+	// Error explains why the proxy isn't healthy. It's only returned to
+	// users who can update the proxy.
+	Error string `json:"error,omitempty"`
+	// LastHeartbeatAt is the last time the proxy registered with coderd.
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty" format:"date-time"`
+	// CheckedAt is the last time coderd checked that the proxy is reachable.
+	CheckedAt *time.Time `json:"checked_at,omitempty" format:"date-time"`
+}
+
 type WorkspaceProxy struct {
-	ID   uuid.UUID `db:"id" json:"id" format:"uuid"`
-	Name string    `db:"name" json:"name"`
-	Icon string    `db:"icon" json:"icon"`
+	ID          uuid.UUID `db:"id" json:"id" format:"uuid"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	Icon        string    `db:"icon" json:"icon"`
 	// Full url including scheme of the proxy api url: https://us.example.com
 	URL string `db:"url" json:"url"`
 	// WildcardHostname with the wildcard for subdomain based app hosting: *.us.example.com
@@ -22,6 +56,11 @@ type WorkspaceProxy struct {
 	CreatedAt        time.Time `db:"created_at" json:"created_at" format:"date-time"`
 	UpdatedAt        time.Time `db:"updated_at" json:"updated_at" format:"date-time"`
 	Deleted          bool      `db:"deleted" json:"deleted"`
+	// Version is the version of the proxy, as reported by the proxy when it
+	// registers with coderd. It's only returned to users who can update the
+	// proxy.
+	Version string               `db:"version" json:"version"`
+	Status  WorkspaceProxyStatus `json:"status"`
 }
 
 type CreateWorkspaceProxyRequest struct {
@@ -54,6 +93,23 @@ func (c *Client) CreateWorkspaceProxy(ctx context.Context, req CreateWorkspacePr
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+type PatchWorkspaceProxy struct {
+	Name             string  `json:"name,omitempty"`
+	DisplayName      string  `json:"display_name,omitempty"`
+	Icon             *string `json:"icon,omitempty"`
+	URL              string  `json:"url,omitempty"`
+	WildcardHostname *string `json:"wildcard_hostname,omitempty"`
+	// RegenerateToken replaces the token of the proxy. The proxy must be
+	// restarted with the new token.
+	RegenerateToken bool `json:"regenerate_token,omitempty"`
+}
+
+type UpdateWorkspaceProxyResponse struct {
+	Proxy WorkspaceProxy `json:"proxy"`
+	// ProxyToken is only set if the token was regenerated.
+	ProxyToken string `json:"proxy_token,omitempty"`
+}
+
 func (c *Client) WorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
 	res, err := c.Request(ctx, http.MethodGet,
 		"/api/v2/workspaceproxies",
@@ -71,3 +127,152 @@ func (c *Client) WorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	var proxies []WorkspaceProxy
 	return proxies, json.NewDecoder(res.Body).Decode(&proxies)
 }
+
+// WorkspaceProxyByName returns the workspace proxy with the name or ID.
+func (c *Client) WorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/workspaceproxies/%s", name),
+		nil,
+	)
+	if err != nil {
+		return WorkspaceProxy{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceProxy{}, ReadBodyAsError(res)
+	}
+	var proxy WorkspaceProxy
+	return proxy, json.NewDecoder(res.Body).Decode(&proxy)
+}
+
+func (c *Client) PatchWorkspaceProxy(ctx context.Context, id uuid.UUID, req PatchWorkspaceProxy) (UpdateWorkspaceProxyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPatch,
+		fmt.Sprintf("/api/v2/workspaceproxies/%s", id.String()),
+		req,
+	)
+	if err != nil {
+		return UpdateWorkspaceProxyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return UpdateWorkspaceProxyResponse{}, ReadBodyAsError(res)
+	}
+	var resp UpdateWorkspaceProxyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) DeleteWorkspaceProxy(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/workspaceproxies/%s", id.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceProxyPrimary selects the primary access URL instead of a workspace
+// proxy.
+const WorkspaceProxyPrimary = "primary"
+
+// workspaceProxyProbes is the number of round trips that are measured for
+// each proxy. The first one includes the handshakes of the connection.
+const workspaceProxyProbes = 3
+
+// WorkspaceProxyLatency is the round trip time to the DERP server of the
+// primary, either directly or relayed through a workspace proxy.
+// @typescript-ignore WorkspaceProxyLatency
+type WorkspaceProxyLatency struct {
+	// Proxy is nil for the primary access URL.
+	Proxy *WorkspaceProxy
+	URL   *url.URL
+	// Latency is the lowest round trip time of the probes.
+	Latency time.Duration
+	Error   error
+}
+
+// Name is the name of the proxy, or "primary" for the primary access URL.
+func (l WorkspaceProxyLatency) Name() string {
+	if l.Proxy == nil {
+		return WorkspaceProxyPrimary
+	}
+	return l.Proxy.Name
+}
+
+// WorkspaceProxyLatencies measures the latency to the DERP server of the
+// primary, directly and relayed through each of the healthy proxies. Proxies
+// forward connections to the primary, so this is the latency of the whole
+// path rather than the latency to the proxy. The results are sorted by
+// latency, followed by the proxies that couldn't be reached.
+func (c *Client) WorkspaceProxyLatencies(ctx context.Context, proxies []WorkspaceProxy) []WorkspaceProxyLatency {
+	latencies := []WorkspaceProxyLatency{{URL: c.URL}}
+	for i := range proxies {
+		proxy := proxies[i]
+		if proxy.Status.Status != ProxyHealthy {
+			continue
+		}
+		latency := WorkspaceProxyLatency{Proxy: &proxy}
+		latency.URL, latency.Error = url.Parse(proxy.URL)
+		latencies = append(latencies, latency)
+	}
+
+	var wg sync.WaitGroup
+	for i := range latencies {
+		if latencies[i].Error != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(latency *WorkspaceProxyLatency) {
+			defer wg.Done()
+			latency.Latency, latency.Error = c.probeLatency(ctx, latency.URL)
+		}(&latencies[i])
+	}
+	wg.Wait()
+
+	sort.SliceStable(latencies, func(i, j int) bool {
+		if (latencies[i].Error == nil) != (latencies[j].Error == nil) {
+			return latencies[i].Error == nil
+		}
+		return latencies[i].Latency < latencies[j].Latency
+	})
+	return latencies
+}
+
+// probeLatency returns the lowest round trip time of requests to the DERP
+// latency check of the server.
+func (c *Client) probeLatency(ctx context.Context, serverURL *url.URL) (time.Duration, error) {
+	checkURL, err := serverURL.Parse("/derp/latency-check")
+	if err != nil {
+		return 0, xerrors.Errorf("parse url: %w", err)
+	}
+	var lowest time.Duration
+	for i := 0; i < workspaceProxyProbes; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL.String(), nil)
+		if err != nil {
+			return 0, xerrors.Errorf("create request: %w", err)
+		}
+		start := time.Now()
+		res, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return 0, xerrors.Errorf("probe %s: %w", serverURL.Host, err)
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		latency := time.Since(start)
+		if res.StatusCode != http.StatusOK {
+			return 0, xerrors.Errorf("probe %s: unexpected status code %d", serverURL.Host, res.StatusCode)
+		}
+		if i == 0 || latency < lowest {
+			lowest = latency
+		}
+	}
+	return lowest, nil
+}
@@ -34,8 +34,38 @@ type Workspace struct {
 	AutostartSchedule                    *string        `json:"autostart_schedule,omitempty"`
 	TTLMillis                            *int64         `json:"ttl_ms,omitempty"`
 	LastUsedAt                           time.Time      `json:"last_used_at" format:"date-time"`
+	// DormantAt being non-nil indicates that the workspace was marked dormant
+	// after a period of inactivity. Dormant workspaces cannot be started until
+	// they are made active again.
+	DormantAt *time.Time `json:"dormant_at,omitempty" format:"date-time"`
+	// DeletingAt indicates the time at which the dormant workspace will be
+	// deleted if it is not made active again. It is only set if the template
+	// has a dormant autodelete TTL.
+	DeletingAt *time.Time `json:"deleting_at,omitempty" format:"date-time"`
+	// TemplateDeprecationMessage is set if the workspace's template has been
+	// deprecated. New workspaces can't be created from deprecated templates.
+	TemplateDeprecationMessage string `json:"template_deprecation_message,omitempty"`
+	// AutomaticUpdates controls whether the workspace is started on the
+	// active version of its template.
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" enums:"always,never"`
+	// TemplateRequireActiveVersion is set if the workspace's template
+	// requires workspaces to be started on its active version.
+	TemplateRequireActiveVersion bool `json:"template_require_active_version"`
 }
 
+// AutomaticUpdates controls whether a workspace is started on the active
+// version of its template.
+type AutomaticUpdates string
+
+const (
+	// AutomaticUpdatesAlways starts the workspace on the active version of
+	// its template every time it starts.
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	// AutomaticUpdatesNever starts the workspace on the template version of
+	// its latest build.
+	AutomaticUpdatesNever AutomaticUpdates = "never"
+)
+
 type WorkspacesRequest struct {
 	SearchQuery string `json:"q,omitempty"`
 	Pagination
@@ -253,6 +283,48 @@ func (c *Client) UpdateWorkspaceTTL(ctx context.Context, id uuid.UUID, req Updat
 	return nil
 }
 
+// UpdateWorkspaceDormancy is a request to mark a workspace dormant or to
+// make a dormant workspace active again.
+type UpdateWorkspaceDormancy struct {
+	Dormant bool `json:"dormant"`
+}
+
+// UpdateWorkspaceDormancy marks a workspace dormant or active. Dormant
+// workspaces cannot be started until they are made active again.
+func (c *Client) UpdateWorkspaceDormancy(ctx context.Context, id uuid.UUID, req UpdateWorkspaceDormancy) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/dormant", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace dormancy: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UpdateWorkspaceAutomaticUpdatesRequest is a request to update a
+// workspace's automatic updates setting.
+type UpdateWorkspaceAutomaticUpdatesRequest struct {
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" validate:"required" enums:"always,never"`
+}
+
+// UpdateWorkspaceAutomaticUpdates sets whether the workspace is started on
+// the active version of its template.
+func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UUID, req UpdateWorkspaceAutomaticUpdatesRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/autoupdates", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace automatic updates: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // PutExtendWorkspaceRequest is a request to extend the deadline of
 // the active workspace build.
 type PutExtendWorkspaceRequest struct {
@@ -378,6 +450,46 @@ func (c *Client) WorkspaceQuota(ctx context.Context, userID string) (WorkspaceQu
 	return quota, json.NewDecoder(res.Body).Decode(&quota)
 }
 
+// OrganizationQuota is the budget of an organization, shared by the
+// workspaces of all of its members.
+type OrganizationQuota struct {
+	CreditsConsumed int `json:"credits_consumed"`
+	// Budget is 0 if the organization doesn't have a budget.
+	Budget int `json:"budget"`
+}
+
+type UpdateOrganizationQuotaRequest struct {
+	// Budget limits the total daily cost of the workspaces of the
+	// organization. 0 removes the budget.
+	Budget int `json:"budget" validate:"min=0"`
+}
+
+func (c *Client) OrganizationQuota(ctx context.Context, organizationID uuid.UUID) (OrganizationQuota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/quota", organizationID.String()), nil)
+	if err != nil {
+		return OrganizationQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OrganizationQuota{}, ReadBodyAsError(res)
+	}
+	var quota OrganizationQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
+func (c *Client) UpdateOrganizationQuota(ctx context.Context, organizationID uuid.UUID, req UpdateOrganizationQuotaRequest) (OrganizationQuota, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/quota", organizationID.String()), req)
+	if err != nil {
+		return OrganizationQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OrganizationQuota{}, ReadBodyAsError(res)
+	}
+	var quota OrganizationQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
 // WorkspaceNotifyChannel is the PostgreSQL NOTIFY
 // channel to listen for updates on. The payload is empty,
 // because the size of a workspace payload can be very large.
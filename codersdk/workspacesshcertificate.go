@@ -0,0 +1,51 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceSSHCertificateLifetime is how long certificates for plain SSH
+// access to a workspace are valid.
+const WorkspaceSSHCertificateLifetime = 8 * time.Hour
+
+// WorkspaceSSHCertificatePrincipal returns the principal of certificates
+// issued for the workspace. Agents only accept certificates that are valid
+// for the principal of their own workspace.
+func WorkspaceSSHCertificatePrincipal(workspaceID uuid.UUID) string {
+	return "workspace-" + workspaceID.String()
+}
+
+type PostWorkspaceSSHCertificateRequest struct {
+	// PublicKey is the key to certify in the authorized_keys format.
+	PublicKey string `json:"public_key" validate:"required"`
+}
+
+// WorkspaceSSHCertificate is a user certificate signed by the SSH certificate
+// authority of the deployment.
+type WorkspaceSSHCertificate struct {
+	// Certificate is in the authorized_keys format, so it can be written to
+	// a file and passed to ssh with -o CertificateFile.
+	Certificate string    `json:"certificate"`
+	ExpiresAt   time.Time `json:"expires_at" format:"date-time"`
+}
+
+// PostWorkspaceSSHCertificate issues a short-lived certificate for the public
+// key that grants SSH access to the given workspace.
+func (c *Client) PostWorkspaceSSHCertificate(ctx context.Context, workspaceID uuid.UUID, req PostWorkspaceSSHCertificateRequest) (WorkspaceSSHCertificate, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/ssh-certificate", workspaceID), req)
+	if err != nil {
+		return WorkspaceSSHCertificate{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceSSHCertificate{}, ReadBodyAsError(res)
+	}
+	var cert WorkspaceSSHCertificate
+	return cert, json.NewDecoder(res.Body).Decode(&cert)
+}
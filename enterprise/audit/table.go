@@ -18,11 +18,14 @@ var AuditActionMap = map[string][]codersdk.AuditAction{
 	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
 	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionConnect},
 	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
 	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"Webhook":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"CustomRole":      {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"ProvisionerKey":  {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -75,6 +78,16 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"allow_user_autostop":              ActionTrack,
 		"allow_user_cancel_workspace_jobs": ActionTrack,
 		"max_ttl":                          ActionTrack,
+		"inactivity_ttl":                   ActionTrack,
+		"dormant_autodelete_ttl":           ActionTrack,
+		"failure_ttl":                      ActionTrack,
+		"restart_requirement_days_of_week": ActionTrack,
+		"restart_requirement_weeks":        ActionTrack,
+		"deprecated":                       ActionTrack,
+		"require_active_version":           ActionTrack,
+		"max_port_sharing_level":           ActionTrack,
+		"session_recording_enabled":        ActionTrack,
+		"quota_cost_multiplier":            ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                 ActionTrack,
@@ -87,20 +100,23 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"job_id":             ActionIgnore, // Not helpful in a diff because jobs aren't tracked in audit logs.
 		"created_by":         ActionTrack,
 		"git_auth_providers": ActionIgnore, // Not helpful because this can only change when new versions are added.
+		"promoted_at":        ActionIgnore, // Promotions are audited as changes to the template's active version.
+		"promoted_by":        ActionIgnore, // Promotions are audited as changes to the template's active version.
 	},
 	&database.User{}: {
-		"id":              ActionTrack,
-		"email":           ActionTrack,
-		"username":        ActionTrack,
-		"hashed_password": ActionSecret, // Do not expose a users hashed password.
-		"created_at":      ActionIgnore, // Never changes.
-		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
-		"status":          ActionTrack,
-		"rbac_roles":      ActionTrack,
-		"login_type":      ActionIgnore,
-		"avatar_url":      ActionIgnore,
-		"last_seen_at":    ActionIgnore,
-		"deleted":         ActionTrack,
+		"id":                   ActionTrack,
+		"email":                ActionTrack,
+		"username":             ActionTrack,
+		"hashed_password":      ActionSecret, // Do not expose a users hashed password.
+		"created_at":           ActionIgnore, // Never changes.
+		"updated_at":           ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"status":               ActionTrack,
+		"rbac_roles":           ActionTrack,
+		"login_type":           ActionIgnore,
+		"avatar_url":           ActionIgnore,
+		"last_seen_at":         ActionIgnore,
+		"deleted":              ActionTrack,
+		"quiet_hours_schedule": ActionTrack,
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -114,6 +130,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"autostart_schedule": ActionTrack,
 		"ttl":                ActionTrack,
 		"last_used_at":       ActionIgnore,
+		"dormant_at":         ActionTrack,
+		"automatic_updates":  ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                  ActionIgnore,
@@ -173,6 +191,40 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"updated_at":          ActionIgnore,
 		"deleted":             ActionIgnore,
 		"token_hashed_secret": ActionSecret,
+		"version":             ActionIgnore,
+		"last_heartbeat_at":   ActionIgnore,
+		"health_checked_at":   ActionIgnore,
+		"health_error":        ActionIgnore,
+	},
+	&database.Webhook{}: {
+		"id":         ActionTrack,
+		"name":       ActionTrack,
+		"url":        ActionTrack,
+		"secret":     ActionSecret,
+		"events":     ActionTrack,
+		"enabled":    ActionTrack,
+		"created_by": ActionTrack,
+		"created_at": ActionIgnore,
+		"updated_at": ActionIgnore,
+	},
+	&database.CustomRole{}: {
+		"id":               ActionTrack,
+		"name":             ActionTrack,
+		"display_name":     ActionTrack,
+		"organization_id":  ActionTrack,
+		"site_permissions": ActionTrack,
+		"org_permissions":  ActionTrack,
+		"user_permissions": ActionTrack,
+		"created_at":       ActionIgnore,
+		"updated_at":       ActionIgnore,
+	},
+	&database.ProvisionerKey{}: {
+		"id":              ActionTrack,
+		"created_at":      ActionIgnore,
+		"organization_id": ActionIgnore,
+		"name":            ActionTrack,
+		"hashed_secret":   ActionSecret,
+		"tags":            ActionTrack,
 	},
 }
 
@@ -29,6 +29,7 @@ func (r *RootCmd) provisionerDaemons() *clibase.Cmd {
 		Short: "Manage provisioner daemons",
 		Children: []*clibase.Cmd{
 			r.provisionerDaemonStart(),
+			r.provisionerKeys(),
 		},
 	}
 
@@ -37,17 +38,19 @@ func (r *RootCmd) provisionerDaemons() *clibase.Cmd {
 
 func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 	var (
-		cacheDir     string
-		rawTags      []string
-		pollInterval time.Duration
-		pollJitter   time.Duration
+		cacheDir       string
+		rawTags        []string
+		pollInterval   time.Duration
+		pollJitter     time.Duration
+		provisionerKey string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Use:   "start",
 		Short: "Run a provisioner daemon",
 		Middleware: clibase.Chain(
-			r.InitClient(client),
+			// Daemons started with a provisioner key don't need a session.
+			r.InitClientMissingTokenOK(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
 			ctx, cancel := context.WithCancel(inv.Context())
@@ -56,16 +59,28 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 			notifyCtx, notifyStop := signal.NotifyContext(ctx, agpl.InterruptSignals...)
 			defer notifyStop()
 
-			org, err := agpl.CurrentOrganization(inv, client)
-			if err != nil {
-				return xerrors.Errorf("get current organization: %w", err)
-			}
-
 			tags, err := agpl.ParseProvisionerTags(rawTags)
 			if err != nil {
 				return err
 			}
 
+			// Daemons started with a provisioner key serve the jobs of the
+			// organization of the key that match its tags.
+			var org codersdk.Organization
+			if provisionerKey != "" {
+				if len(tags) > 0 {
+					return xerrors.New("tags can't be set when starting with a provisioner key, the tags of the key are used")
+				}
+			} else {
+				if client.SessionToken() == "" {
+					return xerrors.New("you must be logged in or start with a provisioner key using --key")
+				}
+				org, err = agpl.CurrentOrganization(inv, client)
+				if err != nil {
+					return xerrors.Errorf("get current organization: %w", err)
+				}
+			}
+
 			err = os.MkdirAll(cacheDir, 0o700)
 			if err != nil {
 				return xerrors.Errorf("mkdir %q: %w", cacheDir, err)
@@ -109,9 +124,14 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				string(database.ProvisionerTypeTerraform): proto.NewDRPCProvisionerClient(terraformClient),
 			}
 			srv := provisionerd.New(func(ctx context.Context) (provisionerdproto.DRPCProvisionerDaemonClient, error) {
-				return client.ServeProvisionerDaemon(ctx, org.ID, []codersdk.ProvisionerType{
-					codersdk.ProvisionerTypeTerraform,
-				}, tags)
+				return client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+					Organization: org.ID,
+					Provisioners: []codersdk.ProvisionerType{
+						codersdk.ProvisionerTypeTerraform,
+					},
+					Tags:           tags,
+					ProvisionerKey: provisionerKey,
+				})
 			}, &provisionerd.Options{
 				Logger:          logger,
 				JobPollInterval: pollInterval,
@@ -165,6 +185,12 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 			Description:   "Tags to filter provisioner jobs by.",
 			Value:         clibase.StringArrayOf(&rawTags),
 		},
+		{
+			Flag:        "key",
+			Env:         "CODER_PROVISIONER_DAEMON_KEY",
+			Description: "Provisioner key to authenticate with. The daemon serves the jobs of the organization of the key that match its tags.",
+			Value:       clibase.StringOf(&provisionerKey),
+		},
 		{
 			Flag:        "poll-interval",
 			Env:         "CODER_PROVISIONERD_POLL_INTERVAL",
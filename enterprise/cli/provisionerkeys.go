@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) provisionerKeys() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "keys",
+		Short:   "Manage provisioner keys",
+		Long:    "Provisioner keys authenticate provisioner daemons for an organization, and set the tags of the jobs they run.",
+		Aliases: []string{"key"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.provisionerKeyCreate(),
+			r.provisionerKeyList(),
+			r.provisionerKeyDelete(),
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyCreate() *clibase.Cmd {
+	var rawTags []string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a provisioner key",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			tags, err := agpl.ParseProvisionerTags(rawTags)
+			if err != nil {
+				return err
+			}
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+
+			res, err := client.CreateProvisionerKey(ctx, org.ID, codersdk.CreateProvisionerKeyRequest{
+				Name: inv.Args[0],
+				Tags: tags,
+			})
+			if err != nil {
+				return xerrors.Errorf("create provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Successfully created provisioner key %s! Start a daemon with it:\n\n", cliui.Styles.Keyword.Render(res.ProvisionerKey.Name))
+			_, _ = fmt.Fprintln(inv.Stderr, color.HiMagentaString("  $ coder provisionerd start --key <key>\n"))
+			_, _ = fmt.Fprintln(inv.Stdout, res.Key)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "tag",
+			FlagShorthand: "t",
+			Description:   "Tags of the jobs that daemons using the key run, in the key=value format.",
+			Value:         clibase.StringArrayOf(&rawTags),
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]provisionerKeyTableRow{}, []string{"name", "tags", "created at"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Short:   "List provisioner keys",
+		Aliases: []string{"ls"},
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+
+			keys, err := client.ProvisionerKeys(ctx, org.ID)
+			if err != nil {
+				return xerrors.Errorf("get provisioner keys: %w", err)
+			}
+
+			if len(keys) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "%s No provisioner keys found! Create one:\n\n", agpl.Caret)
+				_, _ = fmt.Fprintln(inv.Stderr, color.HiMagentaString("  $ coder provisionerd keys create <name>\n"))
+				return nil
+			}
+
+			out, err := formatter.Format(ctx, provisionerKeysToRows(keys...))
+			if err != nil {
+				return xerrors.Errorf("display provisioner keys: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyDelete() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a provisioner key",
+		Long:  "Daemons using the key stop acquiring jobs once it's deleted.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+
+			err = client.DeleteProvisionerKey(ctx, org.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("delete provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully deleted provisioner key %s!\n", cliui.Styles.Keyword.Render(inv.Args[0]))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+type provisionerKeyTableRow struct {
+	// For json output:
+	Key codersdk.ProvisionerKey `table:"-"`
+
+	// For table output:
+	Name      string    `json:"-" table:"name,default_sort"`
+	Tags      string    `json:"-" table:"tags"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+}
+
+func provisionerKeysToRows(keys ...codersdk.ProvisionerKey) []provisionerKeyTableRow {
+	rows := make([]provisionerKeyTableRow, 0, len(keys))
+	for _, key := range keys {
+		tags := make([]string, 0, len(key.Tags))
+		for k, v := range key.Tags {
+			tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(tags)
+		rows = append(rows, provisionerKeyTableRow{
+			Key:       key,
+			Name:      key.Name,
+			Tags:      strings.Join(tags, " "),
+			CreatedAt: key.CreatedAt,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,106 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/testutil"
+)
+
+func TestProvisionerKeys(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse) {
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		})
+		return client, user
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := newClient(t)
+		inv, conf := newCLI(t,
+			"provisionerd", "keys", "create", "production",
+			"--tag", "environment=production",
+		)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err := inv.Run()
+		require.NoError(t, err)
+		require.NotEmpty(t, strings.TrimSpace(stdout.String()))
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		keys, err := client.ProvisionerKeys(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, "production", keys[0].Name)
+		require.Equal(t, map[string]string{"environment": "production"}, keys[0].Tags)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := newClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		key, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t, "provisionerd", "keys", "list", "--output", "json")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+
+		var rows []struct {
+			Key codersdk.ProvisionerKey
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &rows))
+		require.Len(t, rows, 1)
+		require.Equal(t, key.ProvisionerKey.ID, rows[0].Key.ID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := newClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t, "provisionerd", "keys", "delete", "production")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Successfully deleted provisioner key")
+
+		keys, err := client.ProvisionerKeys(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"github.com/coder/coder/cli/clibase"
+)
+
+func (r *RootCmd) proxies() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "proxy",
+		Short:   "Manage workspace proxies",
+		Aliases: []string{"proxies"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.proxyCreate(),
+			r.proxyList(),
+			r.proxyEdit(),
+			r.proxyDelete(),
+			r.proxyLatency(),
+			r.proxyUse(),
+		},
+	}
+
+	return cmd
+}
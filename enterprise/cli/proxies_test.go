@@ -0,0 +1,255 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestProxies(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) *codersdk.Client {
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureWorkspaceProxy: 1,
+			},
+		})
+		return client
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		inv, conf := newCLI(t,
+			"proxy", "create", "us",
+			"--display-name", "United States",
+			"--url", "https://us.example.com",
+			"--wildcard-hostname", "*.us.example.com",
+		)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Successfully created workspace proxy")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		proxy, err := client.WorkspaceProxyByName(ctx, "us")
+		require.NoError(t, err)
+		require.Equal(t, "United States", proxy.DisplayName)
+		require.Equal(t, "https://us.example.com", proxy.URL)
+		require.Equal(t, "*.us.example.com", proxy.WildcardHostname)
+		// The token is printed on the last line.
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		require.True(t, strings.HasPrefix(lines[len(lines)-1], proxy.ID.String()+":"))
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name:             "us",
+			URL:              "https://us.example.com",
+			WildcardHostname: "*.us.example.com",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t,
+			"proxy", "edit", "us",
+			"--name", "eu",
+			"--wildcard-hostname", "",
+			"--regenerate-token",
+		)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Restart the proxy with the new token")
+
+		proxy, err := client.WorkspaceProxyByName(ctx, "eu")
+		require.NoError(t, err)
+		require.Equal(t, "https://us.example.com", proxy.URL)
+		require.Empty(t, proxy.WildcardHostname)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		proxyRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "us",
+			URL:  "https://us.example.com",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t, "proxy", "delete", proxyRes.Proxy.ID.String())
+		pty := ptytest.New(t)
+		inv.Stdout = pty.Output()
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+		pty.ExpectMatch("Successfully deleted workspace proxy")
+
+		proxies, err := client.WorkspaceProxies(ctx)
+		require.NoError(t, err)
+		require.Empty(t, proxies)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "us",
+			URL:  "https://us.example.com",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t, "proxy", "ls")
+		pty := ptytest.New(t)
+		inv.Stdout = pty.Output()
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+
+		matches := []string{
+			"NAME", "URL", "STATUS", "VERSION", "LAST HEARTBEAT", "ERROR",
+			"us", "https://us.example.com", string(codersdk.ProxyUnregistered), "never",
+		}
+		for _, match := range matches {
+			pty.ExpectMatch(match)
+		}
+	})
+
+	t.Run("ListEmpty", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		inv, conf := newCLI(t, "proxy", "ls")
+		pty := ptytest.New(t)
+		inv.Stderr = pty.Output()
+		clitest.SetupConfig(t, client, conf)
+
+		err := inv.Run()
+		require.NoError(t, err)
+		pty.ExpectMatch("No workspace proxies found")
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+		client, _, api := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureWorkspaceProxy: 1,
+			},
+		})
+		_ = coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+			Name: "us",
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "eu",
+			URL:  "https://eu.example.com",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t, "proxy", "latency", "--output", "json")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+
+		var rows []struct {
+			Name      string  `json:"name"`
+			LatencyMS float64 `json:"latency_ms"`
+			Selected  bool    `json:"selected"`
+			Error     string  `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &rows))
+		require.Len(t, rows, 3)
+		byName := map[string]int{}
+		for i, row := range rows {
+			byName[row.Name] = i
+		}
+		primary := rows[byName[codersdk.WorkspaceProxyPrimary]]
+		require.Empty(t, primary.Error)
+		require.Positive(t, primary.LatencyMS)
+		require.True(t, primary.Selected)
+		us := rows[byName["us"]]
+		require.Empty(t, us.Error)
+		require.Positive(t, us.LatencyMS)
+		require.False(t, us.Selected)
+		require.Equal(t, "proxy is unregistered", rows[byName["eu"]].Error)
+	})
+
+	t.Run("Use", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "us",
+			URL:  "https://us.example.com",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(t, "proxy", "use", "us")
+		clitest.SetupConfig(t, client, conf)
+		err = inv.Run()
+		require.NoError(t, err)
+		pinned, err := conf.Proxy().Read()
+		require.NoError(t, err)
+		require.Equal(t, "us", pinned)
+
+		inv, conf = newCLI(t, "proxy", "use", "eu")
+		clitest.SetupConfig(t, client, conf)
+		err = inv.Run()
+		require.ErrorContains(t, err, "get workspace proxy \"eu\"")
+	})
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) proxyCreate() *clibase.Cmd {
+	var (
+		displayName      string
+		icon             string
+		proxyURL         string
+		wildcardHostname string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a workspace proxy",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			if proxyURL == "" {
+				return xerrors.New("--url is required")
+			}
+
+			resp, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+				Name:             inv.Args[0],
+				DisplayName:      displayName,
+				Icon:             icon,
+				URL:              proxyURL,
+				WildcardHostname: wildcardHostname,
+			})
+			if err != nil {
+				return xerrors.Errorf("create workspace proxy: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully created workspace proxy %s! Start the proxy with this token, it won't be shown again:\n\n", cliui.Styles.Keyword.Render(resp.Proxy.Name))
+			_, _ = fmt.Fprintln(inv.Stdout, resp.ProxyToken)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "Display name of the proxy.",
+			Value:       clibase.StringOf(&displayName),
+		},
+		{
+			Flag:        "icon",
+			Description: "Icon of the proxy, e.g. an emoji like /emojis/1f1fa-1f1f8.png.",
+			Value:       clibase.StringOf(&icon),
+		},
+		{
+			Flag:        "url",
+			Description: "URL of the proxy, including the scheme, e.g. https://us.example.com.",
+			Value:       clibase.StringOf(&proxyURL),
+		},
+		{
+			Flag:        "wildcard-hostname",
+			Description: "Wildcard hostname of the proxy for subdomain based apps, e.g. *.us.example.com.",
+			Value:       clibase.StringOf(&wildcardHostname),
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) proxyDelete() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name|id>",
+		Short: "Delete a workspace proxy",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			proxy, err := client.WorkspaceProxyByName(ctx, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace proxy: %w", err)
+			}
+
+			err = client.DeleteWorkspaceProxy(ctx, proxy.ID)
+			if err != nil {
+				return xerrors.Errorf("delete workspace proxy: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully deleted workspace proxy %s!\n", cliui.Styles.Keyword.Render(proxy.Name))
+			return nil
+		},
+	}
+
+	return cmd
+}
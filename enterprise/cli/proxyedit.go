@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) proxyEdit() *clibase.Cmd {
+	var (
+		name             string
+		displayName      string
+		icon             string
+		proxyURL         string
+		wildcardHostname string
+		regenerateToken  bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name|id>",
+		Short: "Edit a workspace proxy",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			proxy, err := client.WorkspaceProxyByName(ctx, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace proxy: %w", err)
+			}
+
+			req := codersdk.PatchWorkspaceProxy{
+				Name:            name,
+				DisplayName:     displayName,
+				URL:             proxyURL,
+				RegenerateToken: regenerateToken,
+			}
+			// The icon and the wildcard hostname can be removed, so they're
+			// updated whenever the flag is set.
+			if inv.ParsedFlags().Changed("icon") {
+				req.Icon = &icon
+			}
+			if inv.ParsedFlags().Changed("wildcard-hostname") {
+				req.WildcardHostname = &wildcardHostname
+			}
+
+			resp, err := client.PatchWorkspaceProxy(ctx, proxy.ID, req)
+			if err != nil {
+				return xerrors.Errorf("patch workspace proxy: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully patched workspace proxy %s!\n", cliui.Styles.Keyword.Render(resp.Proxy.Name))
+			if resp.ProxyToken != "" {
+				_, _ = fmt.Fprintf(inv.Stdout, "Restart the proxy with the new token, it won't be shown again:\n\n%s\n", resp.ProxyToken)
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "name",
+			FlagShorthand: "n",
+			Description:   "Update the proxy name.",
+			Value:         clibase.StringOf(&name),
+		},
+		{
+			Flag:        "display-name",
+			Description: "Update the display name of the proxy.",
+			Value:       clibase.StringOf(&displayName),
+		},
+		{
+			Flag:        "icon",
+			Description: "Update the icon of the proxy.",
+			Value:       clibase.StringOf(&icon),
+		},
+		{
+			Flag:        "url",
+			Description: "Update the URL of the proxy.",
+			Value:       clibase.StringOf(&proxyURL),
+		},
+		{
+			Flag:        "wildcard-hostname",
+			Description: "Update the wildcard hostname of the proxy. An empty value disables subdomain based apps.",
+			Value:       clibase.StringOf(&wildcardHostname),
+		},
+		{
+			Flag:        "regenerate-token",
+			Description: "Replace the token of the proxy. The proxy stops working until it's restarted with the new token.",
+			Value:       clibase.BoolOf(&regenerateToken),
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) proxyLatency() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]proxyLatencyTableRow{}, []string{"name", "url", "latency", "selected", "error"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "latency",
+		Short: "Measure the round trip time to the primary through each workspace proxy",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			proxies, err := client.WorkspaceProxies(ctx)
+			if err != nil {
+				return xerrors.Errorf("get workspace proxies: %w", err)
+			}
+			selected, err := r.WorkspaceProxyName()
+			if err != nil {
+				return err
+			}
+
+			latencies := client.WorkspaceProxyLatencies(ctx, proxies)
+			// Connections fall back to the primary access URL if the
+			// selected proxy isn't healthy.
+			healthy := false
+			for _, latency := range latencies {
+				healthy = healthy || latency.Name() == selected
+			}
+			if !healthy {
+				selected = codersdk.WorkspaceProxyPrimary
+			}
+			rows := make([]proxyLatencyTableRow, 0, len(proxies)+1)
+			for _, latency := range latencies {
+				row := proxyLatencyTableRow{
+					Name:     latency.Name(),
+					URL:      latency.URL.String(),
+					Selected: latency.Name() == selected,
+				}
+				if latency.Error != nil {
+					row.Error = latency.Error.Error()
+				} else {
+					row.Latency = latency.Latency.Round(100 * time.Microsecond).String()
+					row.LatencyMS = float64(latency.Latency.Microseconds()) / 1000
+				}
+				rows = append(rows, row)
+			}
+			// Unhealthy proxies aren't probed, but are listed to explain why
+			// they can't be selected.
+			for _, proxy := range proxies {
+				if proxy.Status.Status == codersdk.ProxyHealthy {
+					continue
+				}
+				rows = append(rows, proxyLatencyTableRow{
+					Name:  proxy.Name,
+					URL:   proxy.URL,
+					Error: fmt.Sprintf("proxy is %s", proxy.Status.Status),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("display latencies: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type proxyLatencyTableRow struct {
+	Name      string  `json:"name" table:"name,default_sort"`
+	URL       string  `json:"url" table:"url"`
+	Latency   string  `json:"-" table:"latency"`
+	LatencyMS float64 `json:"latency_ms" table:"-"`
+	Selected  bool    `json:"selected" table:"selected"`
+	Error     string  `json:"error,omitempty" table:"error"`
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) proxyList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]proxyTableRow{}, []string{"name", "url", "status", "version", "last heartbeat", "error"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Short:   "List workspace proxies",
+		Aliases: []string{"ls"},
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			proxies, err := client.WorkspaceProxies(ctx)
+			if err != nil {
+				return xerrors.Errorf("get workspace proxies: %w", err)
+			}
+
+			if len(proxies) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "%s No workspace proxies found! Create one:\n\n", agpl.Caret)
+				_, _ = fmt.Fprintln(inv.Stderr, color.HiMagentaString("  $ coder proxy create <name> --url <url>\n"))
+				return nil
+			}
+
+			out, err := formatter.Format(ctx, proxiesToRows(proxies...))
+			if err != nil {
+				return xerrors.Errorf("display workspace proxies: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type proxyTableRow struct {
+	// For json output:
+	Proxy codersdk.WorkspaceProxy `table:"-"`
+
+	// For table output:
+	Name             string    `json:"-" table:"name,default_sort"`
+	DisplayName      string    `json:"-" table:"display name"`
+	URL              string    `json:"-" table:"url"`
+	WildcardHostname string    `json:"-" table:"wildcard hostname"`
+	Status           string    `json:"-" table:"status"`
+	Version          string    `json:"-" table:"version"`
+	LastHeartbeat    string    `json:"-" table:"last heartbeat"`
+	Error            string    `json:"-" table:"error"`
+	CreatedAt        time.Time `json:"-" table:"created at"`
+}
+
+func proxiesToRows(proxies ...codersdk.WorkspaceProxy) []proxyTableRow {
+	rows := make([]proxyTableRow, 0, len(proxies))
+	for _, proxy := range proxies {
+		lastHeartbeat := "never"
+		if proxy.Status.LastHeartbeatAt != nil {
+			lastHeartbeat = time.Since(*proxy.Status.LastHeartbeatAt).Truncate(time.Second).String() + " ago"
+		}
+		rows = append(rows, proxyTableRow{
+			Proxy:            proxy,
+			Name:             proxy.Name,
+			DisplayName:      proxy.DisplayName,
+			URL:              proxy.URL,
+			WildcardHostname: proxy.WildcardHostname,
+			Status:           string(proxy.Status.Status),
+			Version:          proxy.Version,
+			LastHeartbeat:    lastHeartbeat,
+			Error:            proxy.Status.Error,
+			CreatedAt:        proxy.CreatedAt,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) proxyUse() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "use <name|primary>",
+		Short: "Pin the workspace proxy that connections to workspaces are relayed through",
+		Long: "Use \"primary\" to connect through the primary access URL. Proxies forward connections to the primary, " +
+			"so they don't shorten the path to workspaces. The --proxy flag overrides the pinned proxy.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			name := inv.Args[0]
+			if name != codersdk.WorkspaceProxyPrimary {
+				proxy, err := client.WorkspaceProxyByName(inv.Context(), name)
+				if err != nil {
+					return xerrors.Errorf("get workspace proxy %q: %w", name, err)
+				}
+				name = proxy.Name
+			}
+
+			err := r.PinWorkspaceProxy(name)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Connections to workspaces are relayed through %s.\n", cliui.Styles.Keyword.Render(name))
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) quota() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		quotaTableFormat{
+			OutputFormat: cliui.TableFormat([]quotaWorkspaceRow{}, nil),
+		},
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "quota [user]",
+		Short: "Show the quota credits consumed by a user and the budget that remains",
+		Long:  "Credits are consumed by the daily cost of each workspace of the user. The budget is the sum of the quota allowances of the groups the user is a member of.",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			user := codersdk.Me
+			if len(inv.Args) > 0 {
+				user = inv.Args[0]
+			}
+
+			quota, err := client.WorkspaceQuota(ctx, user)
+			if err != nil {
+				return xerrors.Errorf("get workspace quota: %w", err)
+			}
+			workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+				Owner: user,
+			})
+			if err != nil {
+				return xerrors.Errorf("get workspaces: %w", err)
+			}
+
+			out := quotaOutput{
+				CreditsConsumed: quota.CreditsConsumed,
+				Budget:          quota.Budget,
+				Remaining:       quota.Budget - quota.CreditsConsumed,
+				Workspaces:      make([]quotaWorkspaceRow, 0, len(workspaces.Workspaces)),
+			}
+			if out.Remaining < 0 {
+				out.Remaining = 0
+			}
+			for _, workspace := range workspaces.Workspaces {
+				out.Workspaces = append(out.Workspaces, quotaWorkspaceRow{
+					Workspace: workspace.Name,
+					Template:  workspace.TemplateName,
+					Status:    string(workspace.LatestBuild.Status),
+					DailyCost: workspace.LatestBuild.DailyCost,
+				})
+			}
+
+			res, err := formatter.Format(ctx, out)
+			if err != nil {
+				return xerrors.Errorf("display quota: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, res)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type quotaOutput struct {
+	CreditsConsumed int                 `json:"credits_consumed"`
+	Budget          int                 `json:"budget"`
+	Remaining       int                 `json:"remaining"`
+	Workspaces      []quotaWorkspaceRow `json:"workspaces"`
+}
+
+type quotaWorkspaceRow struct {
+	Workspace string `json:"workspace" table:"workspace,default_sort"`
+	Template  string `json:"template" table:"template"`
+	Status    string `json:"status" table:"status"`
+	DailyCost int32  `json:"daily_cost" table:"daily cost"`
+}
+
+// quotaTableFormat prints a summary of the quota above the table of
+// workspaces.
+type quotaTableFormat struct {
+	cliui.OutputFormat
+}
+
+func (f quotaTableFormat) Format(ctx context.Context, data any) (string, error) {
+	out, ok := data.(quotaOutput)
+	if !ok {
+		return "", xerrors.Errorf("expected quota output, got %T", data)
+	}
+	table, err := f.OutputFormat.Format(ctx, out.Workspaces)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Credits consumed: %d\n", out.CreditsConsumed)
+	_, _ = fmt.Fprintf(&sb, "Budget:           %d\n", out.Budget)
+	_, _ = fmt.Fprintf(&sb, "Remaining:        %d\n\n", out.Remaining)
+	sb.WriteString(table)
+	return sb.String(), nil
+}
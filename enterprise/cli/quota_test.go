@@ -0,0 +1,117 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestQuota(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Workspace) {
+		t.Helper()
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		admin := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+			Name:           "developers",
+			QuotaAllowance: 10,
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{admin.UserID.String()},
+		})
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Resources: []*proto.Resource{{
+							Name:      "example",
+							Type:      "aws_instance",
+							DailyCost: 4,
+						}},
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		workspace.TemplateName = template.Name
+		return client, workspace
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		inv, conf := newCLI(t, "quota")
+		clitest.SetupConfig(t, client, conf)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+
+		pty.ExpectMatch("Credits consumed: 4")
+		pty.ExpectMatch("Budget:           10")
+		pty.ExpectMatch("Remaining:        6")
+		pty.ExpectMatch("DAILY COST")
+		pty.ExpectMatch(workspace.Name)
+		pty.ExpectMatch(workspace.TemplateName)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		inv, conf := newCLI(t, "quota", "--output", "json")
+		clitest.SetupConfig(t, client, conf)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.Run()
+		require.NoError(t, err)
+
+		var quota struct {
+			CreditsConsumed int `json:"credits_consumed"`
+			Budget          int `json:"budget"`
+			Remaining       int `json:"remaining"`
+			Workspaces      []struct {
+				Workspace string `json:"workspace"`
+				DailyCost int32  `json:"daily_cost"`
+			} `json:"workspaces"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &quota))
+		require.Equal(t, 4, quota.CreditsConsumed)
+		require.Equal(t, 10, quota.Budget)
+		require.Equal(t, 6, quota.Remaining)
+		require.Len(t, quota.Workspaces, 1)
+		require.Equal(t, workspace.Name, quota.Workspaces[0].Workspace)
+		require.EqualValues(t, 4, quota.Workspaces[0].DailyCost)
+	})
+}
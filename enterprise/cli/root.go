@@ -15,7 +15,9 @@ func (r *RootCmd) enterpriseOnly() []*clibase.Cmd {
 		r.features(),
 		r.licenses(),
 		r.groups(),
+		r.proxies(),
 		r.provisionerDaemons(),
+		r.quota(),
 	}
 }
 
@@ -18,6 +18,7 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/coderd"
 	agplaudit "github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/healthcheck"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
@@ -38,6 +39,9 @@ func New(ctx context.Context, options *Options) (*API, error) {
 	if options.EntitlementsUpdateInterval == 0 {
 		options.EntitlementsUpdateInterval = 10 * time.Minute
 	}
+	if options.ProxyHealthInterval == 0 {
+		options.ProxyHealthInterval = 30 * time.Second
+	}
 	if options.Keys == nil {
 		options.Keys = Keys
 	}
@@ -50,12 +54,28 @@ func New(ctx context.Context, options *Options) (*API, error) {
 	if options.Options.Authorizer == nil {
 		options.Options.Authorizer = rbac.NewCachingAuthorizer(options.PrometheusRegistry)
 	}
+	// Validate the default quiet hours schedule up front so a bad value fails
+	// startup instead of every workspace build.
+	userQuietHoursScheduleStore, err := newEnterpriseUserQuietHoursScheduleStore(options.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value())
+	if err != nil {
+		return nil, xerrors.Errorf("create user quiet hours schedule store: %w", err)
+	}
+
 	ctx, cancelFunc := context.WithCancel(ctx)
 	api := &API{
-		AGPL:                   coderd.New(options.Options),
-		Options:                options,
-		cancelEntitlementsLoop: cancelFunc,
+		Options:                     options,
+		userQuietHoursScheduleStore: userQuietHoursScheduleStore,
+		cancelEntitlementsLoop:      cancelFunc,
+	}
+	if options.Options.HealthcheckFunc == nil {
+		options.Options.HealthcheckFunc = func(ctx context.Context) (*healthcheck.Report, error) {
+			return healthcheck.Run(ctx, &healthcheck.ReportOptions{
+				DERPMap:          options.DERPMap.Clone(),
+				WorkspaceProxies: api.healthcheckWorkspaceProxies,
+			})
+		}
 	}
+	api.AGPL = coderd.New(options.Options)
 
 	api.AGPL.Options.SetUserGroups = api.setUserGroups
 
@@ -100,15 +120,18 @@ func New(ctx context.Context, options *Options) (*API, error) {
 					}),
 				)
 				r.Post("/issue-signed-app-token", api.workspaceProxyIssueSignedAppToken)
+				r.Post("/register", api.workspaceProxyRegister)
+			})
+			r.Route("/{workspaceproxy}", func(r chi.Router) {
+				r.Use(
+					apiKeyMiddleware,
+					httpmw.ExtractWorkspaceProxyParam(api.Database),
+				)
+
+				r.Get("/", api.workspaceProxy)
+				r.Patch("/", api.patchWorkspaceProxy)
+				r.Delete("/", api.deleteWorkspaceProxy)
 			})
-			// TODO: Add specific workspace proxy endpoints.
-			// r.Route("/{proxyName}", func(r chi.Router) {
-			//	r.Use(
-			//		httpmw.ExtractWorkspaceProxyByNameParam(api.Database),
-			//	)
-			//
-			//	r.Get("/", api.workspaceProxyByName)
-			// })
 		})
 		r.Route("/organizations/{organization}/groups", func(r chi.Router) {
 			r.Use(
@@ -126,15 +149,41 @@ func New(ctx context.Context, options *Options) (*API, error) {
 				r.Get("/", api.groupByOrganization)
 			})
 		})
+		r.Route("/organizations/{organization}/quota", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.templateRBACEnabledMW,
+				httpmw.ExtractOrganizationParam(api.Database),
+			)
+			r.Get("/", api.organizationQuota)
+			r.Put("/", api.putOrganizationQuota)
+		})
+		r.Route("/provisionerdaemons", func(r chi.Router) {
+			r.Use(api.provisionerDaemonsEnabledMW)
+			r.With(apiKeyMiddleware).Get("/", api.provisionerDaemons)
+			r.With(httpmw.ExtractProvisionerKey(httpmw.ExtractProvisionerKeyConfig{
+				DB: options.Database,
+			})).Get("/serve", api.provisionerDaemonServeWithKey)
+		})
 		r.Route("/organizations/{organization}/provisionerdaemons", func(r chi.Router) {
 			r.Use(
 				api.provisionerDaemonsEnabledMW,
 				apiKeyMiddleware,
 				httpmw.ExtractOrganizationParam(api.Database),
 			)
-			r.Get("/", api.provisionerDaemons)
+			r.Get("/", api.provisionerDaemonsByOrganization)
 			r.Get("/serve", api.provisionerDaemonServe)
 		})
+		r.Route("/organizations/{organization}/provisionerkeys", func(r chi.Router) {
+			r.Use(
+				api.provisionerDaemonsEnabledMW,
+				apiKeyMiddleware,
+				httpmw.ExtractOrganizationParam(api.Database),
+			)
+			r.Post("/", api.postProvisionerKey)
+			r.Get("/", api.provisionerKeys)
+			r.Delete("/{provisionerkey}", api.deleteProvisionerKey)
+		})
 		r.Route("/templates/{template}/acl", func(r chi.Router) {
 			r.Use(
 				api.templateRBACEnabledMW,
@@ -163,6 +212,23 @@ func New(ctx context.Context, options *Options) (*API, error) {
 				r.Get("/", api.workspaceQuota)
 			})
 		})
+		r.Route("/insights/quota", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.templateRBACEnabledMW,
+			)
+			r.Get("/", api.quotaInsights)
+		})
+		r.Route("/users/{user}/quiet-hours", func(r chi.Router) {
+			r.Use(
+				api.restartRequirementEnabledMW,
+				apiKeyMiddleware,
+				httpmw.ExtractUserParam(options.Database, false),
+			)
+
+			r.Get("/", api.userQuietHoursSchedule)
+			r.Put("/", api.putUserQuietHoursSchedule)
+		})
 		r.Route("/appearance", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -182,8 +248,24 @@ func New(ctx context.Context, options *Options) (*API, error) {
 				r.Get("/", api.scimGetUsers)
 				r.Post("/", api.scimPostUser)
 				r.Get("/{id}", api.scimGetUser)
+				r.Put("/{id}", api.scimPutUser)
 				r.Patch("/{id}", api.scimPatchUser)
+				r.Delete("/{id}", api.scimDeleteUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Use(api.templateRBACEnabledMW)
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
+			r.Get("/ServiceProviderConfig", api.scimGetServiceProviderConfig)
+			r.Get("/ResourceTypes", api.scimGetResourceTypes)
+			r.Get("/ResourceTypes/{id}", api.scimGetResourceType)
+			r.Get("/Schemas", api.scimGetSchemas)
+			r.Get("/Schemas/{id}", api.scimGetSchema)
 		})
 	}
 
@@ -209,7 +291,6 @@ func New(ctx context.Context, options *Options) (*API, error) {
 		RootCAs:      meshRootCA,
 		ServerName:   options.AccessURL.Hostname(),
 	}
-	var err error
 	api.replicaManager, err = replicasync.New(ctx, options.Logger, options.Database, options.Pubsub, &replicasync.Options{
 		ID:           api.AGPL.ID,
 		RelayAddress: options.DERPServerRelayAddress,
@@ -226,6 +307,7 @@ func New(ctx context.Context, options *Options) (*API, error) {
 		return nil, xerrors.Errorf("update entitlements: %w", err)
 	}
 	go api.runEntitlementsLoop(ctx)
+	go api.runWorkspaceProxyHealthLoop(ctx)
 
 	return api, nil
 }
@@ -245,6 +327,10 @@ type Options struct {
 
 	EntitlementsUpdateInterval time.Duration
 	Keys                       map[string]ed25519.PublicKey
+
+	// ProxyHealthInterval is how often workspace proxies register with
+	// coderd, and how often coderd checks that they're reachable.
+	ProxyHealthInterval time.Duration
 }
 
 type API struct {
@@ -256,6 +342,10 @@ type API struct {
 	// Meshes DERP connections from multiple replicas.
 	derpMesh *derpmesh.Mesh
 
+	// Enforces user quiet hours when advanced template scheduling is
+	// entitled.
+	userQuietHoursScheduleStore *enterpriseUserQuietHoursScheduleStore
+
 	cancelEntitlementsLoop func()
 	entitlementsMu         sync.RWMutex
 	entitlements           codersdk.Entitlements
@@ -345,9 +435,15 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 			store := &enterpriseTemplateScheduleStore{}
 			ptr := schedule.TemplateScheduleStore(store)
 			api.AGPL.TemplateScheduleStore.Store(&ptr)
+
+			userStore := schedule.UserQuietHoursScheduleStore(api.userQuietHoursScheduleStore)
+			api.AGPL.UserQuietHoursScheduleStore.Store(&userStore)
 		} else {
 			store := schedule.NewAGPLTemplateScheduleStore()
 			api.AGPL.TemplateScheduleStore.Store(&store)
+
+			userStore := schedule.NewAGPLUserQuietHoursScheduleStore()
+			api.AGPL.UserQuietHoursScheduleStore.Store(&userStore)
 		}
 	}
 
@@ -46,6 +46,7 @@ type Options struct {
 	AuditLogging               bool
 	BrowserOnly                bool
 	EntitlementsUpdateInterval time.Duration
+	ProxyHealthInterval        time.Duration
 	SCIMAPIKey                 []byte
 	UserWorkspaceQuota         int
 }
@@ -73,6 +74,7 @@ func NewWithAPI(t *testing.T, options *Options) (*codersdk.Client, io.Closer, *c
 		DERPServerRegionID:         oop.DERPMap.RegionIDs()[0],
 		Options:                    oop,
 		EntitlementsUpdateInterval: options.EntitlementsUpdateInterval,
+		ProxyHealthInterval:        options.ProxyHealthInterval,
 		Keys:                       Keys,
 	})
 	assert.NoError(t, err)
@@ -0,0 +1,245 @@
+package coderd
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// quotaInsightsMaxRange caps the range of quota reports, which are computed
+// from every build in the range for each interval.
+const quotaInsightsMaxRange = 365 * 24 * time.Hour
+
+// @Summary Get quota insights
+// @ID get-quota-insights
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param interval query string false "Interval" Enums(day,week)
+// @Success 200 {object} codersdk.QuotaInsightsResponse
+// @Router /insights/quota [get]
+func (api *API) quotaInsights(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, rbac.ActionRead, rbac.ResourceDeploymentValues) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser().Required("start_time").Required("end_time")
+	startTime := p.Time(vals, time.Time{}, "start_time", time.RFC3339)
+	endTime := p.Time(vals, time.Time{}, "end_time", time.RFC3339)
+	interval := codersdk.InsightsReportInterval(p.String(vals, string(codersdk.InsightsReportIntervalDay), "interval"))
+	p.ErrorExcessParams(vals)
+
+	intervalLength := 24 * time.Hour
+	switch interval {
+	case codersdk.InsightsReportIntervalDay:
+	case codersdk.InsightsReportIntervalWeek:
+		intervalLength = 7 * 24 * time.Hour
+	default:
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "interval",
+			Detail: `Query param "interval" must be "day" or "week".`,
+		})
+	}
+	// Spend can't be reported for the future.
+	now := database.Now()
+	if endTime.After(now) {
+		endTime = now
+	}
+	if len(p.Errors) == 0 && !startTime.Before(endTime) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "start_time",
+			Detail: "Must be before end_time and in the past.",
+		})
+	}
+	if len(p.Errors) == 0 && endTime.Sub(startTime) > quotaInsightsMaxRange {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "start_time",
+			Detail: "Must be at most a year before end_time.",
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	segments, err := api.Database.GetQuotaSpendSegments(ctx, database.GetQuotaSpendSegmentsParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota spend.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	report := quotaReport{
+		userNames:     map[uuid.UUID]string{},
+		groupNames:    map[uuid.UUID]string{},
+		templateNames: map[uuid.UUID]string{},
+		userGroups:    map[uuid.UUID][]uuid.UUID{},
+	}
+	var (
+		userIDs     []uuid.UUID
+		templateIDs []uuid.UUID
+	)
+	for _, segment := range segments {
+		if _, ok := report.userNames[segment.OwnerID]; !ok {
+			report.userNames[segment.OwnerID] = ""
+			userIDs = append(userIDs, segment.OwnerID)
+		}
+		if _, ok := report.templateNames[segment.TemplateID]; !ok {
+			report.templateNames[segment.TemplateID] = ""
+			templateIDs = append(templateIDs, segment.TemplateID)
+		}
+	}
+	if len(userIDs) > 0 {
+		users, err := api.Database.GetUsersByIDs(ctx, userIDs)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching users.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, user := range users {
+			report.userNames[user.ID] = user.Username
+		}
+
+		templates, err := api.Database.GetTemplatesWithFilter(ctx, database.GetTemplatesWithFilterParams{
+			IDs: templateIDs,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching templates.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, template := range templates {
+			report.templateNames[template.ID] = template.Name
+		}
+
+		memberships, err := api.Database.GetGroupMembershipsByUserIDs(ctx, userIDs)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group memberships.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, membership := range memberships {
+			report.groupNames[membership.GroupID] = membership.GroupName
+			report.userGroups[membership.UserID] = append(report.userGroups[membership.UserID], membership.GroupID)
+		}
+	}
+
+	resp := codersdk.QuotaInsightsResponse{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Interval:  interval,
+		Intervals: []codersdk.QuotaInsightsInterval{},
+	}
+	for intervalStart := startTime; intervalStart.Before(endTime); intervalStart = intervalStart.Add(intervalLength) {
+		intervalEnd := intervalStart.Add(intervalLength)
+		if intervalEnd.After(endTime) {
+			intervalEnd = endTime
+		}
+		entry := report.interval(segments, intervalStart, intervalEnd)
+		resp.CreditsSpent += entry.CreditsSpent
+		resp.Intervals = append(resp.Intervals, entry)
+	}
+	resp.CreditsSpent = roundCredits(resp.CreditsSpent)
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// quotaReport attributes the spend of workspace builds to the users, groups
+// and templates of a quota report.
+type quotaReport struct {
+	userNames     map[uuid.UUID]string
+	groupNames    map[uuid.UUID]string
+	templateNames map[uuid.UUID]string
+	// userGroups are the groups each user is a member of.
+	userGroups map[uuid.UUID][]uuid.UUID
+}
+
+func (q quotaReport) interval(segments []database.GetQuotaSpendSegmentsRow, start, end time.Time) codersdk.QuotaInsightsInterval {
+	var (
+		total     float64
+		users     = map[uuid.UUID]float64{}
+		groups    = map[uuid.UUID]float64{}
+		templates = map[uuid.UUID]float64{}
+	)
+	for _, segment := range segments {
+		segmentStart, segmentEnd := segment.StartedAt, segment.EndedAt
+		if segmentStart.Before(start) {
+			segmentStart = start
+		}
+		if segmentEnd.After(end) {
+			segmentEnd = end
+		}
+		if !segmentStart.Before(segmentEnd) {
+			continue
+		}
+		// Daily costs are spent continuously over the day.
+		credits := float64(segment.DailyCost) * segmentEnd.Sub(segmentStart).Hours() / 24
+		total += credits
+		users[segment.OwnerID] += credits
+		templates[segment.TemplateID] += credits
+		for _, groupID := range q.userGroups[segment.OwnerID] {
+			groups[groupID] += credits
+		}
+	}
+
+	return codersdk.QuotaInsightsInterval{
+		StartTime:    start,
+		EndTime:      end,
+		CreditsSpent: roundCredits(total),
+		Users:        convertQuotaSpend(users, q.userNames),
+		Groups:       convertQuotaSpend(groups, q.groupNames),
+		Templates:    convertQuotaSpend(templates, q.templateNames),
+	}
+}
+
+// convertQuotaSpend sorts spend with the highest spender first.
+func convertQuotaSpend(spend map[uuid.UUID]float64, names map[uuid.UUID]string) []codersdk.QuotaSpend {
+	converted := make([]codersdk.QuotaSpend, 0, len(spend))
+	for id, credits := range spend {
+		converted = append(converted, codersdk.QuotaSpend{
+			ID:           id,
+			Name:         names[id],
+			CreditsSpent: roundCredits(credits),
+		})
+	}
+	sort.Slice(converted, func(i, j int) bool {
+		if converted[i].CreditsSpent != converted[j].CreditsSpent {
+			return converted[i].CreditsSpent > converted[j].CreditsSpent
+		}
+		return converted[i].Name < converted[j].Name
+	})
+	return converted
+}
+
+// roundCredits rounds credits to hundredths, spend is reported per second
+// otherwise.
+func roundCredits(credits float64) float64 {
+	return math.Round(credits*100) / 100
+}
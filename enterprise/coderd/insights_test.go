@@ -0,0 +1,178 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/testutil"
+)
+
+func TestQuotaInsights(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user.UserID.String()},
+		})
+		require.NoError(t, err)
+
+		template := dbgen.Template(t, db, database.Template{
+			OrganizationID: user.OrganizationID,
+			CreatedBy:      user.UserID,
+		})
+		workspace := dbgen.Workspace(t, db, database.Workspace{
+			OwnerID:        user.UserID,
+			OrganizationID: user.OrganizationID,
+			TemplateID:     template.ID,
+		})
+		// The workspace costs 24 credits a day on the first day, and 48
+		// credits a day from then on.
+		startTime := time.Now().Add(-72 * time.Hour).Truncate(time.Hour).UTC()
+		for i, cost := range []int32{24, 48} {
+			build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				WorkspaceID: workspace.ID,
+				BuildNumber: int32(i) + 1,
+				CreatedAt:   startTime.Add(time.Duration(i) * 24 * time.Hour),
+			})
+			_, err := db.UpdateWorkspaceBuildCostByID(context.Background(), database.UpdateWorkspaceBuildCostByIDParams{
+				ID:        build.ID,
+				DailyCost: cost,
+			})
+			require.NoError(t, err)
+		}
+
+		report, err := client.QuotaInsights(ctx, codersdk.QuotaInsightsRequest{
+			StartTime: startTime,
+			EndTime:   startTime.Add(48 * time.Hour),
+			Interval:  codersdk.InsightsReportIntervalDay,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 72.0, report.CreditsSpent)
+		require.Len(t, report.Intervals, 2)
+		for i, credits := range []float64{24, 48} {
+			interval := report.Intervals[i]
+			require.Equal(t, startTime.Add(time.Duration(i)*24*time.Hour), interval.StartTime.UTC())
+			require.Equal(t, credits, interval.CreditsSpent)
+			require.Equal(t, []codersdk.QuotaSpend{{
+				ID:           user.UserID,
+				Name:         coderdtest.FirstUserParams.Username,
+				CreditsSpent: credits,
+			}}, interval.Users)
+			require.Equal(t, []codersdk.QuotaSpend{{
+				ID:           template.ID,
+				Name:         template.Name,
+				CreditsSpent: credits,
+			}}, interval.Templates)
+			require.Contains(t, interval.Groups, codersdk.QuotaSpend{
+				ID:           group.ID,
+				Name:         group.Name,
+				CreditsSpent: credits,
+			})
+		}
+
+		// A single week covers both days, and the build that is still in
+		// effect spends until now.
+		report, err = client.QuotaInsights(ctx, codersdk.QuotaInsightsRequest{
+			StartTime: startTime,
+			EndTime:   startTime.Add(7 * 24 * time.Hour),
+			Interval:  codersdk.InsightsReportIntervalWeek,
+		})
+		require.NoError(t, err)
+		require.Len(t, report.Intervals, 1)
+		require.Greater(t, report.CreditsSpent, 24.0+2*48)
+		require.Less(t, report.CreditsSpent, 24.0+3*48)
+	})
+
+	t.Run("InvalidInterval", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+
+		_, err := client.QuotaInsights(ctx, codersdk.QuotaInsightsRequest{
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now(),
+			Interval:  "month",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("RangeTooLarge", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+
+		_, err := client.QuotaInsights(ctx, codersdk.QuotaInsightsRequest{
+			StartTime: time.Unix(0, 0),
+			EndTime:   time.Now(),
+			Interval:  codersdk.InsightsReportIntervalDay,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err := member.QuotaInsights(ctx, codersdk.QuotaInsightsRequest{
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/yamux"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/exp/maps"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 	"storj.io/drpc/drpcmux"
@@ -54,9 +55,8 @@ func (api *API) provisionerDaemonsEnabledMW(next http.Handler) http.Handler {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Enterprise
-// @Param organization path string true "Organization ID" format(uuid)
 // @Success 200 {array} codersdk.ProvisionerDaemon
-// @Router /organizations/{organization}/provisionerdaemons [get]
+// @Router /provisionerdaemons [get]
 func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	daemons, err := api.Database.GetProvisionerDaemons(ctx)
@@ -81,13 +81,65 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	daemonIDs := make([]uuid.UUID, 0, len(daemons))
+	for _, daemon := range daemons {
+		daemonIDs = append(daemonIDs, daemon.ID)
+	}
+	jobs, err := api.Database.GetRunningProvisionerJobsByWorkerIDs(ctx, daemonIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	currentJobs := make(map[uuid.UUID]database.ProvisionerJob, len(jobs))
+	for _, job := range jobs {
+		currentJobs[job.WorkerID.UUID] = job
+	}
+
 	apiDaemons := make([]codersdk.ProvisionerDaemon, 0)
 	for _, daemon := range daemons {
-		apiDaemons = append(apiDaemons, convertProvisionerDaemon(daemon))
+		apiDaemon := convertProvisionerDaemon(daemon)
+		if job, ok := currentJobs[daemon.ID]; ok {
+			apiDaemon.CurrentJob = &codersdk.ProvisionerDaemonJob{
+				ID:     job.ID,
+				Status: coderd.ConvertProvisionerJobStatus(job),
+			}
+		}
+		apiDaemons = append(apiDaemons, apiDaemon)
 	}
 	httpapi.Write(ctx, rw, http.StatusOK, apiDaemons)
 }
 
+// @Summary Get provisioner daemons by organization
+// @ID get-provisioner-daemons-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerDaemon
+// @Router /organizations/{organization}/provisionerdaemons [get]
+func (api *API) provisionerDaemonsByOrganization(rw http.ResponseWriter, r *http.Request) {
+	// Daemons aren't stored per organization, they serve the jobs of every
+	// organization their tags match.
+	api.provisionerDaemons(rw, r)
+}
+
+// @Summary Serve provisioner daemon with provisioner key
+// @ID serve-provisioner-daemon-with-provisioner-key
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param provisioner query []string true "Provisioner types" collectionFormat(multi)
+// @Param version query string false "Version of the daemon"
+// @Success 101
+// @Router /provisionerdaemons/serve [get]
+func (api *API) provisionerDaemonServeWithKey(rw http.ResponseWriter, r *http.Request) {
+	// Daemons authenticated with a key serve the jobs of the organization of
+	// the key that match its tags.
+	api.provisionerDaemonServe(rw, r)
+}
+
 // Serves the provisioner daemon protobuf API over a WebSocket.
 //
 // @Summary Serve provisioner daemon
@@ -95,6 +147,9 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 // @Security CoderSessionToken
 // @Tags Enterprise
 // @Param organization path string true "Organization ID" format(uuid)
+// @Param provisioner query []string true "Provisioner types" collectionFormat(multi)
+// @Param tag query []string false "Tags in the key=value format" collectionFormat(multi)
+// @Param version query string false "Version of the daemon"
 // @Success 101
 // @Router /organizations/{organization}/provisionerdaemons/serve [get]
 func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request) {
@@ -135,18 +190,31 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	// Any authenticated user can create provisioner daemons scoped
-	// for jobs that they own, but only authorized users can create
-	// globally scoped provisioners that attach to all jobs.
-	apiKey := httpmw.APIKey(r)
-	tags = provisionerdserver.MutateTags(apiKey.UserID, tags)
-
-	if tags[provisionerdserver.TagScope] == provisionerdserver.ScopeOrganization {
-		if !api.AGPL.Authorize(r, rbac.ActionCreate, rbac.ResourceProvisionerDaemon) {
-			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: "You aren't allowed to create provisioner daemons for the organization.",
-			})
-			return
+	var (
+		keyID          uuid.NullUUID
+		organizationID uuid.UUID
+	)
+	if key, ok := httpmw.ProvisionerKeyOptional(r); ok {
+		// Keys were created by users that are allowed to create
+		// organization scoped daemons, and their tags can't be changed.
+		// Their daemons only serve the organization of the key.
+		tags = provisionerdserver.MutateTags(uuid.Nil, maps.Clone(key.Tags))
+		keyID = uuid.NullUUID{UUID: key.ID, Valid: true}
+		organizationID = key.OrganizationID
+	} else {
+		// Any authenticated user can create provisioner daemons scoped
+		// for jobs that they own, but only authorized users can create
+		// globally scoped provisioners that attach to all jobs.
+		apiKey := httpmw.APIKey(r)
+		tags = provisionerdserver.MutateTags(apiKey.UserID, tags)
+
+		if tags[provisionerdserver.TagScope] == provisionerdserver.ScopeOrganization {
+			if !api.AGPL.Authorize(r, rbac.ActionCreate, rbac.ResourceProvisionerDaemon) {
+				httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+					Message: "You aren't allowed to create provisioner daemons for the organization.",
+				})
+				return
+			}
 		}
 	}
 
@@ -167,6 +235,9 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		Name:         name,
 		Provisioners: provisioners,
 		Tags:         tags,
+		LastSeenAt:   sql.NullTime{Time: database.Now(), Valid: true},
+		Version:      r.URL.Query().Get("version"),
+		KeyID:        keyID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -218,18 +289,21 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 	}
 	mux := drpcmux.New()
 	err = proto.DRPCRegisterProvisionerDaemon(mux, &provisionerdserver.Server{
-		AccessURL:             api.AccessURL,
-		GitAuthConfigs:        api.GitAuthConfigs,
-		OIDCConfig:            api.OIDCConfig,
-		ID:                    daemon.ID,
-		Database:              api.Database,
-		Pubsub:                api.Pubsub,
-		Provisioners:          daemon.Provisioners,
-		Telemetry:             api.Telemetry,
-		Auditor:               &api.AGPL.Auditor,
-		TemplateScheduleStore: api.AGPL.TemplateScheduleStore,
-		Logger:                api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
-		Tags:                  rawTags,
+		AccessURL:                   api.AccessURL,
+		GitAuthConfigs:              api.GitAuthConfigs,
+		OIDCConfig:                  api.OIDCConfig,
+		ID:                          daemon.ID,
+		Database:                    api.Database,
+		Pubsub:                      api.Pubsub,
+		Provisioners:                daemon.Provisioners,
+		Telemetry:                   api.Telemetry,
+		Auditor:                     &api.AGPL.Auditor,
+		TemplateScheduleStore:       api.AGPL.TemplateScheduleStore,
+		UserQuietHoursScheduleStore: api.AGPL.UserQuietHoursScheduleStore,
+		Logger:                      api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
+		Tags:                        rawTags,
+		ProvisionerKeyID:            keyID,
+		OrganizationID:              organizationID,
 	})
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("drpc register provisioner daemon: %s", err))
@@ -254,11 +328,16 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 
 func convertProvisionerDaemon(daemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
 	result := codersdk.ProvisionerDaemon{
-		ID:        daemon.ID,
-		CreatedAt: daemon.CreatedAt,
-		UpdatedAt: daemon.UpdatedAt,
-		Name:      daemon.Name,
-		Tags:      daemon.Tags,
+		ID:         daemon.ID,
+		CreatedAt:  daemon.CreatedAt,
+		UpdatedAt:  daemon.UpdatedAt,
+		LastSeenAt: daemon.LastSeenAt,
+		Name:       daemon.Name,
+		Version:    daemon.Version,
+		Tags:       daemon.Tags,
+	}
+	if daemon.KeyID.Valid {
+		result.KeyID = &daemon.KeyID.UUID
 	}
 	for _, provisionerType := range daemon.Provisioners {
 		result.Provisioners = append(result.Provisioners, codersdk.ProvisionerType(provisionerType))
@@ -322,12 +401,24 @@ func (*enterpriseTemplateScheduleStore) GetTemplateScheduleOptions(ctx context.C
 		UserAutostopEnabled:  tpl.AllowUserAutostop,
 		DefaultTTL:           time.Duration(tpl.DefaultTTL),
 		MaxTTL:               time.Duration(tpl.MaxTTL),
+		InactivityTTL:        time.Duration(tpl.InactivityTTL),
+		DormantAutoDeleteTTL: time.Duration(tpl.DormantAutoDeleteTTL),
+		FailureTTL:           time.Duration(tpl.FailureTTL),
+		RestartRequirement: schedule.TemplateRestartRequirement{
+			DaysOfWeek: uint8(tpl.RestartRequirementDaysOfWeek),
+			Weeks:      tpl.RestartRequirementWeeks,
+		},
 	}, nil
 }
 
 func (*enterpriseTemplateScheduleStore) SetTemplateScheduleOptions(ctx context.Context, db database.Store, tpl database.Template, opts schedule.TemplateScheduleOptions) (database.Template, error) {
 	if int64(opts.DefaultTTL) == tpl.DefaultTTL &&
 		int64(opts.MaxTTL) == tpl.MaxTTL &&
+		int64(opts.InactivityTTL) == tpl.InactivityTTL &&
+		int64(opts.DormantAutoDeleteTTL) == tpl.DormantAutoDeleteTTL &&
+		int64(opts.FailureTTL) == tpl.FailureTTL &&
+		int16(opts.RestartRequirement.DaysOfWeek) == tpl.RestartRequirementDaysOfWeek &&
+		opts.RestartRequirement.Weeks == tpl.RestartRequirementWeeks &&
 		opts.UserAutostartEnabled == tpl.AllowUserAutostart &&
 		opts.UserAutostopEnabled == tpl.AllowUserAutostop {
 		// Avoid updating the UpdatedAt timestamp if nothing will be changed.
@@ -335,12 +426,17 @@ func (*enterpriseTemplateScheduleStore) SetTemplateScheduleOptions(ctx context.C
 	}
 
 	template, err := db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
-		ID:                 tpl.ID,
-		UpdatedAt:          database.Now(),
-		AllowUserAutostart: opts.UserAutostartEnabled,
-		AllowUserAutostop:  opts.UserAutostopEnabled,
-		DefaultTTL:         int64(opts.DefaultTTL),
-		MaxTTL:             int64(opts.MaxTTL),
+		ID:                           tpl.ID,
+		UpdatedAt:                    database.Now(),
+		AllowUserAutostart:           opts.UserAutostartEnabled,
+		AllowUserAutostop:            opts.UserAutostopEnabled,
+		DefaultTTL:                   int64(opts.DefaultTTL),
+		MaxTTL:                       int64(opts.MaxTTL),
+		InactivityTTL:                int64(opts.InactivityTTL),
+		DormantAutoDeleteTTL:         int64(opts.DormantAutoDeleteTTL),
+		FailureTTL:                   int64(opts.FailureTTL),
+		RestartRequirementDaysOfWeek: int16(opts.RestartRequirement.DaysOfWeek),
+		RestartRequirementWeeks:      opts.RestartRequirement.Weeks,
 	})
 	if err != nil {
 		return database.Template{}, xerrors.Errorf("update template schedule: %w", err)
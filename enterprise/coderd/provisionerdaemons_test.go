@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
@@ -16,7 +17,9 @@ import (
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/enterprise/coderd/license"
 	"github.com/coder/coder/provisioner/echo"
+	provisionerdproto "github.com/coder/coder/provisionerd/proto"
 	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
 )
 
 func TestProvisionerDaemonServe(t *testing.T) {
@@ -30,20 +33,139 @@ func TestProvisionerDaemonServe(t *testing.T) {
 				codersdk.FeatureExternalProvisionerDaemons: 1,
 			},
 		})
-		srv, err := client.ServeProvisionerDaemon(context.Background(), user.OrganizationID, []codersdk.ProvisionerType{
-			codersdk.ProvisionerTypeEcho,
-		}, map[string]string{})
+		srv, err := client.ServeProvisionerDaemon(context.Background(), codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{},
+		})
 		require.NoError(t, err)
 		srv.DRPCConn().Close()
 	})
 
+	t.Run("ProvisionerKey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		})
+		key, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+			Tags: map[string]string{
+				"environment": "production",
+			},
+		})
+		require.NoError(t, err)
+
+		// The tags of the daemon come from the key.
+		srv, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				"environment": "staging",
+			},
+			ProvisionerKey: key.Key,
+		})
+		require.NoError(t, err)
+		defer srv.DRPCConn().Close()
+
+		daemons, err := client.ProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.Equal(t, buildinfo.Version(), daemons[0].Version)
+		require.True(t, daemons[0].LastSeenAt.Valid)
+		require.NotNil(t, daemons[0].KeyID)
+		require.Equal(t, key.ProvisionerKey.ID, *daemons[0].KeyID)
+		require.Equal(t, map[string]string{
+			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+			"environment":               "production",
+		}, daemons[0].Tags)
+		require.Nil(t, daemons[0].CurrentJob)
+	})
+
+	t.Run("ProvisionerKeyOrganization", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		})
+		other, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "other",
+		})
+		require.NoError(t, err)
+		key, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+		})
+		require.NoError(t, err)
+
+		// The job of the other organization is older, so it would be
+		// acquired first if the daemon served every organization.
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, other.ID, nil)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		srv, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: key.Key,
+		})
+		require.NoError(t, err)
+		defer srv.DRPCConn().Close()
+
+		job, err := srv.AcquireJob(ctx, &provisionerdproto.Empty{})
+		require.NoError(t, err)
+		require.Equal(t, version.Job.ID.String(), job.JobId)
+		job, err = srv.AcquireJob(ctx, &provisionerdproto.Empty{})
+		require.NoError(t, err)
+		require.Empty(t, job.JobId)
+
+		otherVersion, err = client.TemplateVersion(ctx, otherVersion.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobPending, otherVersion.Job.Status)
+	})
+
+	t.Run("ProvisionerKeyInvalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		})
+		_, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: uuid.NewString() + ":invalid",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusUnauthorized, apiError.StatusCode())
+	})
+
 	t.Run("NoLicense", func(t *testing.T) {
 		t.Parallel()
 		client := coderdenttest.New(t, nil)
 		user := coderdtest.CreateFirstUser(t, client)
-		_, err := client.ServeProvisionerDaemon(context.Background(), user.OrganizationID, []codersdk.ProvisionerType{
-			codersdk.ProvisionerTypeEcho,
-		}, map[string]string{})
+		_, err := client.ServeProvisionerDaemon(context.Background(), codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{},
+		})
 		require.Error(t, err)
 		var apiError *codersdk.Error
 		require.ErrorAs(t, err, &apiError)
@@ -60,10 +182,14 @@ func TestProvisionerDaemonServe(t *testing.T) {
 			},
 		})
 		another, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleOrgAdmin(user.OrganizationID))
-		_, err := another.ServeProvisionerDaemon(context.Background(), user.OrganizationID, []codersdk.ProvisionerType{
-			codersdk.ProvisionerTypeEcho,
-		}, map[string]string{
-			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+		_, err := another.ServeProvisionerDaemon(context.Background(), codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+			},
 		})
 		require.Error(t, err)
 		var apiError *codersdk.Error
@@ -81,10 +207,14 @@ func TestProvisionerDaemonServe(t *testing.T) {
 			},
 		})
 		another, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
-		_, err := another.ServeProvisionerDaemon(context.Background(), user.OrganizationID, []codersdk.ProvisionerType{
-			codersdk.ProvisionerTypeEcho,
-		}, map[string]string{
-			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+		_, err := another.ServeProvisionerDaemon(context.Background(), codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+			},
 		})
 		require.Error(t, err)
 		var apiError *codersdk.Error
@@ -0,0 +1,202 @@
+package coderd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/provisionerdserver"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+// @Summary Create provisioner key
+// @ID create-provisioner-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateProvisionerKeyRequest true "Create provisioner key request"
+// @Success 201 {object} codersdk.CreateProvisionerKeyResponse
+// @Router /organizations/{organization}/provisionerkeys [post]
+func (api *API) postProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.ProvisionerKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateProvisionerKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Tags == nil {
+		req.Tags = map[string]string{}
+	}
+	// Keys create daemons for the whole organization, the tags of user
+	// scoped daemons are set from the session of the user instead.
+	if scope, ok := req.Tags[provisionerdserver.TagScope]; ok && scope != provisionerdserver.ScopeOrganization {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Provisioner keys can only have the %q scope.", provisionerdserver.ScopeOrganization),
+		})
+		return
+	}
+	if _, ok := req.Tags[provisionerdserver.TagOwner]; ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Provisioner keys can't have the %q tag.", provisionerdserver.TagOwner),
+		})
+		return
+	}
+
+	id := uuid.New()
+	fullKey, hashedSecret, err := generateProvisionerKey(id)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	key, err := api.Database.InsertProvisionerKey(ctx, database.InsertProvisionerKeyParams{
+		ID:             id,
+		CreatedAt:      database.Now(),
+		OrganizationID: organization.ID,
+		Name:           req.Name,
+		HashedSecret:   hashedSecret,
+		Tags:           req.Tags,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Provisioner key with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = key
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateProvisionerKeyResponse{
+		ProvisionerKey: convertProvisionerKey(key),
+		Key:            fullKey,
+	})
+}
+
+// @Summary List provisioner keys
+// @ID list-provisioner-keys
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerKey
+// @Router /organizations/{organization}/provisionerkeys [get]
+func (api *API) provisionerKeys(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	keys, err := api.Database.GetProvisionerKeysByOrganizationID(ctx, organization.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.ProvisionerKey, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, convertProvisionerKey(key))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Delete provisioner key
+// @ID delete-provisioner-key
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param provisionerkey path string true "Provisioner key name"
+// @Success 204
+// @Router /organizations/{organization}/provisionerkeys/{provisionerkey} [delete]
+func (api *API) deleteProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.ProvisionerKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	key, err := api.Database.GetProvisionerKeyByName(ctx, database.GetProvisionerKeyByNameParams{
+		OrganizationID: organization.ID,
+		Name:           chi.URLParam(r, "provisionerkey"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.Old = key
+
+	// Daemons check their key before acquiring each job, so deleting the key
+	// stops them from running new jobs.
+	err = api.Database.DeleteProvisionerKey(ctx, key.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// generateProvisionerKey returns a new key for provisioner daemons, and the
+// hash of its secret that's stored in the database.
+func generateProvisionerKey(id uuid.UUID) (key string, hashedSecret []byte, err error) {
+	secret, err := cryptorand.HexString(64)
+	if err != nil {
+		return "", nil, xerrors.Errorf("generate key: %w", err)
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("%s:%s", id, secret), hashed[:], nil
+}
+
+func convertProvisionerKey(key database.ProvisionerKey) codersdk.ProvisionerKey {
+	return codersdk.ProvisionerKey{
+		ID:             key.ID,
+		CreatedAt:      key.CreatedAt,
+		OrganizationID: key.OrganizationID,
+		Name:           key.Name,
+		Tags:           key.Tags,
+	}
+}
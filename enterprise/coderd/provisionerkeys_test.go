@@ -0,0 +1,120 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/provisionerdserver"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/testutil"
+)
+
+func TestProvisionerKeys(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse) {
+		t.Helper()
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		})
+		return client, user
+	}
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, user := setup(t)
+
+		res, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+			Tags: map[string]string{
+				"environment": "production",
+			},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Key)
+		require.Equal(t, "production", res.ProvisionerKey.Name)
+		require.Equal(t, user.OrganizationID, res.ProvisionerKey.OrganizationID)
+
+		keys, err := client.ProvisionerKeys(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, res.ProvisionerKey.ID, keys[0].ID)
+		require.Equal(t, map[string]string{"environment": "production"}, keys[0].Tags)
+
+		err = client.DeleteProvisionerKey(ctx, user.OrganizationID, "production")
+		require.NoError(t, err)
+
+		keys, err = client.ProvisionerKeys(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, user := setup(t)
+
+		_, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "Production",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("UserScope", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, user := setup(t)
+
+		_, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+			Tags: map[string]string{
+				provisionerdserver.TagScope: provisionerdserver.ScopeUser,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("DeleteNotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, user := setup(t)
+
+		err := client.DeleteProvisionerKey(ctx, user.OrganizationID, "production")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("OrganizationAdminForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, user := setup(t)
+		another, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleOrgAdmin(user.OrganizationID))
+
+		_, err := another.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "production",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
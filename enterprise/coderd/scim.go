@@ -1,24 +1,37 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
+	"database/sql"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/crud/expr"
 	"github.com/imulab/go-scim/pkg/v2/handlerutil"
-	scimjson "github.com/imulab/go-scim/pkg/v2/json"
-	"github.com/imulab/go-scim/pkg/v2/service"
 	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
 
 	agpl "github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 )
 
+const (
+	scimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	scimSchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+)
+
 func (api *API) scimEnabledMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		api.entitlementsMu.RLock()
@@ -40,58 +53,106 @@ func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
 }
 
-// scimGetUsers intentionally always returns no users. This is done to always force
-// Okta to try and create each user individually, this way we don't need to
-// implement fetching users twice.
+// scimGetUsers returns the users of the deployment. IdPs use the userName
+// filter to check whether a user exists before they create it.
 //
 // @Summary SCIM 2.0: Get users
 // @ID scim-get-users
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
+// @Param filter query string false "Filter, only userName eq is supported"
+// @Param startIndex query int false "1-based index of the first user"
+// @Param count query int false "Maximum number of users"
 // @Success 200
 // @Router /scim/v2/Users [get]
 //
 //nolint:revive
 func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	if !api.scimVerifyAuthHeader(r) {
 		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
 		return
 	}
 
-	_ = handlerutil.WriteSearchResultToResponse(rw, &service.QueryResponse{
-		TotalResults: 0,
-		StartIndex:   1,
-		ItemsPerPage: 0,
-		Resources:    []scimjson.Serializable{},
-	})
+	query, err := handlerutil.QueryRequestFromGet(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	if query.Filter != "" {
+		username, err := scimFilterValue(query.Filter, "userName")
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		//nolint:gocritic // needed for SCIM
+		user, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+			Username: username,
+		})
+		// The username may match the email of another user.
+		if xerrors.Is(err, sql.ErrNoRows) || (err == nil && !strings.EqualFold(user.Username, username)) {
+			httpapi.Write(ctx, rw, http.StatusOK, scimList([]SCIMUser{}, 0, 1))
+			return
+		}
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, scimList([]SCIMUser{scimUser(user)}, 1, 1))
+		return
+	}
+
+	startIndex := 1
+	params := database.GetUsersParams{}
+	if query.Pagination != nil {
+		startIndex = query.Pagination.StartIndex
+		params.OffsetOpt = int32(startIndex - 1)
+		params.LimitOpt = int32(query.Pagination.Count)
+	}
+	//nolint:gocritic // needed for SCIM
+	rows, err := api.Database.GetUsers(dbauthz.AsSystemRestricted(ctx), params)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	total := 0
+	if len(rows) > 0 {
+		total = int(rows[0].Count)
+	}
+	users := make([]SCIMUser, 0, len(rows))
+	for _, user := range database.ConvertUserRows(rows) {
+		users = append(users, scimUser(user))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, scimList(users, total, startIndex))
 }
 
-// scimGetUser intentionally always returns an error saying the user wasn't found.
-// This is done to always force Okta to try and create the user, this way we
-// don't need to implement fetching users twice.
-//
-// scimGetUsers intentionally always returns no users. This is done to always force
-// Okta to try and create each user individually, this way we don't need to
-// implement fetching users twice.
-//
 // @Summary SCIM 2.0: Get user by ID
 // @ID scim-get-user-by-id
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
 // @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} coderd.SCIMUser
 // @Failure 404
 // @Router /scim/v2/Users/{id} [get]
 //
 //nolint:revive
 func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	if !api.scimVerifyAuthHeader(r) {
 		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
 		return
 	}
 
-	_ = handlerutil.WriteError(rw, spec.ErrNotFound)
+	user, err := api.scimUserByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimUser(user))
 }
 
 // We currently use our own struct instead of using the SCIM package. This was
@@ -112,7 +173,9 @@ type SCIMUser struct {
 		Type    string `json:"type"`
 		Display string `json:"display"`
 	} `json:"emails"`
-	Active bool          `json:"active"`
+	// Active is nil when a request omits it, which leaves the user's status
+	// unchanged.
+	Active *bool         `json:"active"`
 	Groups []interface{} `json:"groups"`
 	Meta   struct {
 		ResourceType string `json:"resourceType"`
@@ -143,27 +206,37 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	email := ""
-	for _, e := range sUser.Emails {
-		if e.Primary {
-			email = e.Value
-			break
-		}
-	}
-
+	email := sUser.primaryEmail()
 	if email == "" {
 		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidEmail"})
 		return
 	}
 
 	//nolint:gocritic // needed for SCIM
-	user, _, err := api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
-		CreateUserRequest: codersdk.CreateUserRequest{
-			Username: sUser.UserName,
-			Email:    email,
-		},
-		LoginType: database.LoginTypeOIDC,
+	user, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+		Username: sUser.UserName,
+		Email:    email,
 	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		var organization database.Organization
+		organization, err = api.scimOrganization(ctx)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		//nolint:gocritic // needed for SCIM
+		user, _, err = api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
+			CreateUserRequest: codersdk.CreateUserRequest{
+				Username:       sUser.UserName,
+				Email:          email,
+				OrganizationID: organization.ID,
+			},
+			LoginType: database.LoginTypeOIDC,
+		})
+		if err == nil {
+			api.AGPL.PublishUserCreated(ctx, user)
+		}
+	}
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
@@ -203,36 +276,583 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 	}
 	sUser.ID = id
 
-	uid, err := uuid.Parse(id)
+	dbUser, err := api.scimUserByID(ctx, id)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	if sUser.Active != nil {
+		_, err = api.scimSetUserActive(ctx, dbUser, *sUser.Active)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sUser)
+}
+
+// scimPutUser replaces the username, the email and the status of a user.
+//
+// @Summary SCIM 2.0: Replace user account
+// @ID scim-replace-user
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Param request body coderd.SCIMUser true "Replace user request"
+// @Success 200 {object} coderd.SCIMUser
+// @Router /scim/v2/Users/{id} [put]
+func (api *API) scimPutUser(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var sUser SCIMUser
+	err := json.NewDecoder(r.Body).Decode(&sUser)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	dbUser, err := api.scimUserByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	params := database.UpdateUserProfileParams{
+		ID:        dbUser.ID,
+		Email:     dbUser.Email,
+		Username:  dbUser.Username,
+		AvatarURL: dbUser.AvatarURL,
+		UpdatedAt: database.Now(),
+	}
+	if email := sUser.primaryEmail(); email != "" {
+		params.Email = email
+	}
+	if sUser.UserName != "" {
+		if err := httpapi.NameValid(sUser.UserName); err != nil {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("username %q: %w", sUser.UserName, spec.ErrInvalidValue))
+			return
+		}
+		params.Username = sUser.UserName
+	}
+	if params.Email != dbUser.Email || params.Username != dbUser.Username {
+		//nolint:gocritic // needed for SCIM
+		existing, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+			Username: params.Username,
+			Email:    params.Email,
+		})
+		if err == nil && existing.ID != dbUser.ID {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("username or email is taken: %w", spec.ErrUniqueness))
+			return
+		}
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+
+		//nolint:gocritic // needed for SCIM
+		dbUser, err = api.Database.UpdateUserProfile(dbauthz.AsSystemRestricted(ctx), params)
+		if database.IsUniqueViolation(err) {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("username or email is taken: %w", spec.ErrUniqueness))
+			return
+		}
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	if sUser.Active != nil {
+		dbUser, err = api.scimSetUserActive(ctx, dbUser, *sUser.Active)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimUser(dbUser))
+}
+
+// scimDeleteUser deprovisions a user. The user is suspended right away, so
+// they lose access even if they can't be deleted because they still own
+// workspaces.
+//
+// @Summary SCIM 2.0: Delete user account
+// @ID scim-delete-user
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Success 204
+// @Failure 412
+// @Router /scim/v2/Users/{id} [delete]
+func (api *API) scimDeleteUser(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	dbUser, err := api.scimUserByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	dbUser, err = api.scimSetUserActive(ctx, dbUser, false)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidId"})
+		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
 	//nolint:gocritic // needed for SCIM
-	dbUser, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), uid)
+	workspaces, err := api.Database.GetWorkspaces(dbauthz.AsSystemRestricted(ctx), database.GetWorkspacesParams{
+		OwnerID: dbUser.ID,
+	})
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	if len(workspaces) > 0 {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("user %q was suspended, but can't be deleted while they own workspaces: %w", dbUser.Username, spec.ErrConflict))
+		return
+	}
 
-	var status database.UserStatus
-	if sUser.Active {
+	//nolint:gocritic // needed for SCIM
+	err = api.Database.UpdateUserDeletedByID(dbauthz.AsSystemRestricted(ctx), database.UpdateUserDeletedByIDParams{
+		ID:      dbUser.ID,
+		Deleted: true,
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimUserByID returns the user with the ID of a SCIM request. Deleted users
+// are not found.
+func (api *API) scimUserByID(ctx context.Context, id string) (database.User, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return database.User{}, xerrors.Errorf("user %q: %w", id, spec.ErrNotFound)
+	}
+	//nolint:gocritic // needed for SCIM
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), uid)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && user.Deleted) {
+		return database.User{}, xerrors.Errorf("user %q: %w", id, spec.ErrNotFound)
+	}
+	if err != nil {
+		return database.User{}, xerrors.Errorf("get user: %w", err)
+	}
+	return user, nil
+}
+
+// scimSetUserActive activates or suspends a user.
+func (api *API) scimSetUserActive(ctx context.Context, user database.User, active bool) (database.User, error) {
+	status := database.UserStatusSuspended
+	if active {
 		status = database.UserStatusActive
-	} else {
-		status = database.UserStatusSuspended
 	}
 
 	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
-		ID:        dbUser.ID,
+	updatedUser, err := api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(ctx), database.UpdateUserStatusParams{
+		ID:        user.ID,
 		Status:    status,
 		UpdatedAt: database.Now(),
 	})
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		return database.User{}, xerrors.Errorf("update user status: %w", err)
+	}
+	if status == database.UserStatusSuspended && user.Status != database.UserStatusSuspended {
+		api.AGPL.PublishWebhookEvent(ctx, database.WebhookEventUserSuspended, codersdk.WebhookDataUser{
+			UserID:   updatedUser.ID,
+			Username: updatedUser.Username,
+			Email:    updatedUser.Email,
+			Status:   codersdk.UserStatus(updatedUser.Status),
+		})
+	}
+	return updatedUser, nil
+}
+
+// scimOrganization returns the organization that users and groups are
+// provisioned to. Like users that sign in with OIDC, this is the first
+// organization.
+func (api *API) scimOrganization(ctx context.Context) (database.Organization, error) {
+	//nolint:gocritic // needed for SCIM
+	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return database.Organization{}, xerrors.Errorf("get organizations: %w", err)
+	}
+	if len(organizations) == 0 {
+		return database.Organization{}, xerrors.New("no organization exists")
+	}
+	return organizations[0], nil
+}
+
+func (u SCIMUser) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func scimUser(user database.User) SCIMUser {
+	sUser := SCIMUser{
+		Schemas:  []string{scimSchemaUser},
+		ID:       user.ID.String(),
+		UserName: user.Username,
+		Active:   ptr.Ref(user.Status == database.UserStatusActive),
+		Groups:   []interface{}{},
+	}
+	sUser.Emails = append(sUser.Emails, struct {
+		Primary bool   `json:"primary"`
+		Value   string `json:"value" format:"email"`
+		Type    string `json:"type"`
+		Display string `json:"display"`
+	}{
+		Primary: true,
+		Value:   user.Email,
+		Type:    "work",
+	})
+	sUser.Meta.ResourceType = "User"
+	return sUser
+}
+
+// scimListResponse is the response of SCIM queries.
+type scimListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+func scimList[T any](resources []T, total, startIndex int) scimListResponse {
+	return scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// scimFilterValue returns the value of a filter that compares an attribute
+// for equality, e.g. userName eq "alice". IdPs only use such filters to look
+// up resources, so other filters are not supported.
+func scimFilterValue(filter, attribute string) (string, error) {
+	root, err := expr.CompileFilter(filter)
+	if err != nil {
+		return "", err
+	}
+	left, right := root.Left(), root.Right()
+	if !strings.EqualFold(root.Token(), expr.Eq) ||
+		left == nil || !left.IsPath() || left.Next() != nil || !strings.EqualFold(left.Token(), attribute) ||
+		right == nil || !right.IsLiteral() {
+		return "", xerrors.Errorf("only %s eq filters are supported: %w", attribute, spec.ErrInvalidFilter)
+	}
+	var value string
+	err = json.Unmarshal([]byte(right.Token()), &value)
+	if err != nil {
+		return "", xerrors.Errorf("filter value %s is not a string: %w", right.Token(), spec.ErrInvalidFilter)
+	}
+	return value, nil
+}
+
+// @Summary SCIM 2.0: Get service provider config
+// @ID scim-get-service-provider-config
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (api *API) scimGetServiceProviderConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, sUser)
+	httpapi.Write(ctx, rw, http.StatusOK, scimServiceProviderConfig)
 }
+
+// @Summary SCIM 2.0: Get resource types
+// @ID scim-get-resource-types
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/ResourceTypes [get]
+func (api *API) scimGetResourceTypes(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimList(scimResourceTypes, len(scimResourceTypes), 1))
+}
+
+// @Summary SCIM 2.0: Get resource type by ID
+// @ID scim-get-resource-type-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Resource type ID"
+// @Success 200
+// @Router /scim/v2/ResourceTypes/{id} [get]
+func (api *API) scimGetResourceType(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	for _, resourceType := range scimResourceTypes {
+		if resourceType.ID == id {
+			httpapi.Write(ctx, rw, http.StatusOK, resourceType)
+			return
+		}
+	}
+	_ = handlerutil.WriteError(rw, xerrors.Errorf("resource type %q: %w", id, spec.ErrNotFound))
+}
+
+// @Summary SCIM 2.0: Get schemas
+// @ID scim-get-schemas
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/Schemas [get]
+func (api *API) scimGetSchemas(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimList(scimSchemas, len(scimSchemas), 1))
+}
+
+// @Summary SCIM 2.0: Get schema by ID
+// @ID scim-get-schema-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Schema URN"
+// @Success 200
+// @Router /scim/v2/Schemas/{id} [get]
+func (api *API) scimGetSchema(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	for _, schema := range scimSchemas {
+		if schema.ID == id {
+			httpapi.Write(ctx, rw, http.StatusOK, schema)
+			return
+		}
+	}
+	_ = handlerutil.WriteError(rw, xerrors.Errorf("schema %q: %w", id, spec.ErrNotFound))
+}
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+type scimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type scimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type scimServiceProviderConfigResponse struct {
+	Schemas          []string      `json:"schemas"`
+	DocumentationURI string        `json:"documentationUri"`
+	Patch            scimSupported `json:"patch"`
+	Bulk             struct {
+		scimSupported
+		MaxOperations  int `json:"maxOperations"`
+		MaxPayloadSize int `json:"maxPayloadSize"`
+	} `json:"bulk"`
+	Filter struct {
+		scimSupported
+		MaxResults int `json:"maxResults"`
+	} `json:"filter"`
+	ChangePassword        scimSupported              `json:"changePassword"`
+	Sort                  scimSupported              `json:"sort"`
+	ETag                  scimSupported              `json:"etag"`
+	AuthenticationSchemes []scimAuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  scimMeta                   `json:"meta"`
+}
+
+// scimServiceProviderConfig describes the SCIM features that are implemented,
+// so IdPs can configure themselves.
+var scimServiceProviderConfig = func() scimServiceProviderConfigResponse {
+	config := scimServiceProviderConfigResponse{
+		Schemas:          []string{scimSchemaServiceProviderConfig},
+		DocumentationURI: "https://coder.com/docs/v2/latest/admin/auth#scim-enterprise",
+		Patch:            scimSupported{Supported: true},
+		AuthenticationSchemes: []scimAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "HTTP Header",
+			Description: "The Authorization header must be the value of CODER_SCIM_API_KEY.",
+		}},
+		Meta: scimMeta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     "/scim/v2/ServiceProviderConfig",
+		},
+	}
+	// Only equality filters on userName and displayName are supported, which
+	// match a single resource.
+	config.Filter.Supported = true
+	config.Filter.MaxResults = 1
+	return config
+}()
+
+type scimResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description"`
+	Schema      string   `json:"schema"`
+	Meta        scimMeta `json:"meta"`
+}
+
+var scimResourceTypes = []scimResourceType{{
+	Schemas:     []string{scimSchemaResourceType},
+	ID:          "User",
+	Name:        "User",
+	Endpoint:    "/Users",
+	Description: "User Account",
+	Schema:      scimSchemaUser,
+	Meta: scimMeta{
+		ResourceType: "ResourceType",
+		Location:     "/scim/v2/ResourceTypes/User",
+	},
+}, {
+	Schemas:     []string{scimSchemaResourceType},
+	ID:          "Group",
+	Name:        "Group",
+	Endpoint:    "/Groups",
+	Description: "Group",
+	Schema:      scimSchemaGroup,
+	Meta: scimMeta{
+		ResourceType: "ResourceType",
+		Location:     "/scim/v2/ResourceTypes/Group",
+	},
+}}
+
+type scimAttribute struct {
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	MultiValued   bool            `json:"multiValued"`
+	Required      bool            `json:"required"`
+	CaseExact     bool            `json:"caseExact"`
+	Mutability    string          `json:"mutability"`
+	Returned      string          `json:"returned"`
+	Uniqueness    string          `json:"uniqueness"`
+	SubAttributes []scimAttribute `json:"subAttributes,omitempty"`
+}
+
+type scimSchema struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Attributes  []scimAttribute `json:"attributes"`
+	Meta        scimMeta        `json:"meta"`
+}
+
+// scimAttr returns a single-valued, optional attribute that can be read and
+// written.
+func scimAttr(name, typ string, subAttributes ...scimAttribute) scimAttribute {
+	return scimAttribute{
+		Name:          name,
+		Type:          typ,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: subAttributes,
+	}
+}
+
+// scimSchemas describes the attributes of users and groups that are stored.
+var scimSchemas = func() []scimSchema {
+	userName := scimAttr("userName", "string")
+	userName.Required = true
+	userName.Uniqueness = "server"
+	emails := scimAttr("emails", "complex",
+		scimAttr("value", "string"),
+		scimAttr("type", "string"),
+		scimAttr("primary", "boolean"),
+	)
+	emails.MultiValued = true
+	member := func(name string) scimAttribute {
+		attribute := scimAttr(name, "complex",
+			scimAttr("value", "string"),
+			scimAttr("display", "string"),
+		)
+		attribute.MultiValued = true
+		return attribute
+	}
+	groups := member("groups")
+	groups.Mutability = "readOnly"
+	displayName := scimAttr("displayName", "string")
+	displayName.Required = true
+	displayName.Uniqueness = "server"
+
+	return []scimSchema{{
+		Schemas:     []string{scimSchemaSchema},
+		ID:          scimSchemaUser,
+		Name:        "User",
+		Description: "User Account",
+		Attributes: []scimAttribute{
+			userName,
+			scimAttr("name", "complex",
+				scimAttr("givenName", "string"),
+				scimAttr("familyName", "string"),
+			),
+			emails,
+			scimAttr("active", "boolean"),
+			groups,
+		},
+		Meta: scimMeta{
+			ResourceType: "Schema",
+			Location:     "/scim/v2/Schemas/" + scimSchemaUser,
+		},
+	}, {
+		Schemas:     []string{scimSchemaSchema},
+		ID:          scimSchemaGroup,
+		Name:        "Group",
+		Description: "Group",
+		Attributes: []scimAttribute{
+			displayName,
+			member("members"),
+		},
+		Meta: scimMeta{
+			ResourceType: "Schema",
+			Location:     "/scim/v2/Schemas/" + scimSchemaGroup,
+		},
+	}}
+}()
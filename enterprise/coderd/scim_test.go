@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/cryptorand"
 	"github.com/coder/coder/enterprise/coderd"
@@ -41,7 +44,7 @@ func makeScimUser(t testing.TB) coderd.SCIMUser {
 		}{
 			{Primary: true, Value: fmt.Sprintf("%s@coder.com", rstr)},
 		},
-		Active: true,
+		Active: ptr.Ref(true),
 	}
 }
 
@@ -200,7 +203,7 @@ func TestScim(t *testing.T) {
 			err = json.NewDecoder(res.Body).Decode(&sUser)
 			require.NoError(t, err)
 
-			sUser.Active = false
+			sUser.Active = ptr.Ref(false)
 
 			res, err = client.Request(ctx, "PATCH", "/scim/v2/Users/"+sUser.ID, sUser, setScimAuth(scimAPIKey))
 			require.NoError(t, err)
@@ -213,4 +216,355 @@ func TestScim(t *testing.T) {
 			assert.Equal(t, codersdk.UserStatusSuspended, userRes.Users[0].Status)
 		})
 	})
+
+	t.Run("getUsers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := setupScim(t, nil)
+		sUser := postScimUser(ctx, t, client, scimAPIKey)
+
+		var list scimListResponse[coderd.SCIMUser]
+		res, err := client.Request(ctx, "GET", fmt.Sprintf("/scim/v2/Users?filter=%s", url.QueryEscape(fmt.Sprintf("userName eq %q", sUser.UserName))), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+		require.Equal(t, 1, list.TotalResults)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, sUser.ID, list.Resources[0].ID)
+		assert.Equal(t, sUser.Emails[0].Value, list.Resources[0].Emails[0].Value)
+		assert.True(t, *list.Resources[0].Active)
+
+		res, err = client.Request(ctx, "GET", fmt.Sprintf("/scim/v2/Users?filter=%s", url.QueryEscape(`userName eq "unknown"`)), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+		require.Zero(t, list.TotalResults)
+
+		res, err = client.Request(ctx, "GET", fmt.Sprintf("/scim/v2/Users?filter=%s", url.QueryEscape(`emails co "coder.com"`)), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		// The first user and the SCIM user.
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users?startIndex=2&count=1", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+		require.Equal(t, 2, list.TotalResults)
+		require.Equal(t, 2, list.StartIndex)
+		require.Len(t, list.Resources, 1)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users/"+sUser.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users/"+uuid.NewString(), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("putUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := setupScim(t, nil)
+		sUser := postScimUser(ctx, t, client, scimAPIKey)
+
+		sUser.UserName += "-renamed"
+		sUser.Active = ptr.Ref(false)
+		res, err := client.Request(ctx, "PUT", "/scim/v2/Users/"+sUser.ID, sUser, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		user, err := client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sUser.UserName, user.Username)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+
+		// Omitting active leaves the status unchanged.
+		sUser.UserName += "-again"
+		sUser.Active = nil
+		res, err = client.Request(ctx, "PUT", "/scim/v2/Users/"+sUser.ID, sUser, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		user, err = client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sUser.UserName, user.Username)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+
+		sUser.UserName = coderdtest.FirstUserParams.Username
+		res, err = client.Request(ctx, "PUT", "/scim/v2/Users/"+sUser.ID, sUser, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("deleteUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := setupScim(t, nil)
+		sUser := postScimUser(ctx, t, client, scimAPIKey)
+
+		res, err := client.Request(ctx, "DELETE", "/scim/v2/Users/"+sUser.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users/"+sUser.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		// Users that own workspaces are only suspended.
+		first, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationIDs[0], nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, first.OrganizationIDs[0], version.ID)
+		sUser = postScimUser(ctx, t, client, scimAPIKey)
+		_, err = client.CreateWorkspace(ctx, first.OrganizationIDs[0], sUser.ID, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "workspace",
+		})
+		require.NoError(t, err)
+
+		res, err = client.Request(ctx, "DELETE", "/scim/v2/Users/"+sUser.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+
+		user, err := client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("disabled", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, scimAPIKey := setupScim(t, license.Features{
+				codersdk.FeatureTemplateRBAC: 0,
+			})
+			res, err := client.Request(ctx, "GET", "/scim/v2/Groups", nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, scimAPIKey := setupScim(t, license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			})
+			alice := postScimUser(ctx, t, client, scimAPIKey)
+			bob := postScimUser(ctx, t, client, scimAPIKey)
+
+			// Create a group with a member.
+			sGroup := coderd.SCIMGroup{
+				DisplayName: "engineering",
+				Members:     []coderd.SCIMGroupMember{{Value: alice.ID}},
+			}
+			res, err := client.Request(ctx, "POST", "/scim/v2/Groups", sGroup, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusCreated, res.StatusCode)
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&sGroup))
+			requireGroupMembers(ctx, t, client, sGroup.ID, alice.ID)
+
+			res, err = client.Request(ctx, "POST", "/scim/v2/Groups", sGroup, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusConflict, res.StatusCode)
+
+			// Find it by name.
+			var list scimListResponse[coderd.SCIMGroup]
+			res, err = client.Request(ctx, "GET", fmt.Sprintf("/scim/v2/Groups?filter=%s", url.QueryEscape(`displayName eq "engineering"`)), nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+			require.Len(t, list.Resources, 1)
+			assert.Equal(t, sGroup.ID, list.Resources[0].ID)
+			require.Len(t, list.Resources[0].Members, 1)
+			assert.Equal(t, alice.ID, list.Resources[0].Members[0].Value)
+
+			// The group of all users isn't managed with SCIM.
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups?excludedAttributes=members", nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&list))
+			require.Len(t, list.Resources, 1)
+			assert.Empty(t, list.Resources[0].Members)
+
+			// Rename it, add bob and remove alice.
+			res, err = client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, map[string]interface{}{
+				"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+				"Operations": []map[string]interface{}{{
+					"op":    "replace",
+					"value": map[string]string{"id": sGroup.ID, "displayName": "platform"},
+				}, {
+					"op":    "add",
+					"path":  "members",
+					"value": []map[string]string{{"value": bob.ID}},
+				}, {
+					"op":   "remove",
+					"path": fmt.Sprintf("members[value eq %q]", alice.ID),
+				}},
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&sGroup))
+			assert.Equal(t, "platform", sGroup.DisplayName)
+			requireGroupMembers(ctx, t, client, sGroup.ID, bob.ID)
+
+			// Replace all members.
+			sGroup.Members = []coderd.SCIMGroupMember{{Value: alice.ID}, {Value: bob.ID}}
+			res, err = client.Request(ctx, "PUT", "/scim/v2/Groups/"+sGroup.ID, sGroup, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			requireGroupMembers(ctx, t, client, sGroup.ID, alice.ID, bob.ID)
+
+			sGroup.Members = []coderd.SCIMGroupMember{{Value: uuid.NewString()}}
+			res, err = client.Request(ctx, "PUT", "/scim/v2/Groups/"+sGroup.ID, sGroup, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+			res, err = client.Request(ctx, "DELETE", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := setupScim(t, nil)
+
+		var config struct {
+			Patch struct {
+				Supported bool `json:"supported"`
+			} `json:"patch"`
+		}
+		res, err := client.Request(ctx, "GET", "/scim/v2/ServiceProviderConfig", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&config))
+		assert.True(t, config.Patch.Supported)
+
+		var resourceTypes scimListResponse[struct {
+			ID       string `json:"id"`
+			Endpoint string `json:"endpoint"`
+		}]
+		res, err = client.Request(ctx, "GET", "/scim/v2/ResourceTypes", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&resourceTypes))
+		require.Len(t, resourceTypes.Resources, 2)
+		assert.Equal(t, "/Groups", resourceTypes.Resources[1].Endpoint)
+
+		var schemas scimListResponse[struct {
+			ID string `json:"id"`
+		}]
+		res, err = client.Request(ctx, "GET", "/scim/v2/Schemas", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&schemas))
+		require.Len(t, schemas.Resources, 2)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Schemas/"+schemas.Resources[0].ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
+
+type scimListResponse[T any] struct {
+	TotalResults int `json:"totalResults"`
+	StartIndex   int `json:"startIndex"`
+	Resources    []T `json:"Resources"`
+}
+
+// setupScim returns a client of a deployment with SCIM enabled, and the SCIM
+// API key.
+func setupScim(t *testing.T, features license.Features) (*codersdk.Client, []byte) {
+	t.Helper()
+	scimAPIKey := []byte("hi")
+	client := coderdenttest.New(t, &coderdenttest.Options{
+		SCIMAPIKey: scimAPIKey,
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		},
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+	if features == nil {
+		features = license.Features{}
+	}
+	features[codersdk.FeatureSCIM] = 1
+	coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		AccountID: "coolin",
+		Features:  features,
+	})
+	return client, scimAPIKey
+}
+
+func postScimUser(ctx context.Context, t *testing.T, client *codersdk.Client, scimAPIKey []byte) coderd.SCIMUser {
+	t.Helper()
+	sUser := makeScimUser(t)
+	res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&sUser))
+	return sUser
+}
+
+func requireGroupMembers(ctx context.Context, t *testing.T, client *codersdk.Client, groupID string, userIDs ...string) {
+	t.Helper()
+	group, err := client.Group(ctx, uuid.MustParse(groupID))
+	require.NoError(t, err)
+	memberIDs := make([]string, 0, len(group.Members))
+	for _, member := range group.Members {
+		memberIDs = append(memberIDs, member.ID.String())
+	}
+	require.ElementsMatch(t, userIDs, memberIDs)
 }
@@ -0,0 +1,615 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+)
+
+// SCIMGroup is a group of the organization that SCIM users are provisioned
+// to. Members are referenced by their user ID.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMPatchRequest is a list of changes to a resource. The value of an
+// operation depends on its path.
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op" enums:"add,remove,replace"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter, only displayName eq is supported"
+// @Param startIndex query int false "1-based index of the first group"
+// @Param count query int false "Maximum number of groups"
+// @Param excludedAttributes query string false "Set to members to omit the members of groups"
+// @Success 200
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	query, err := handlerutil.QueryRequestFromGet(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	organization, err := api.scimOrganization(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var groups []database.Group
+	if query.Filter != "" {
+		name, err := scimFilterValue(query.Filter, "displayName")
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		group, err := api.Database.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+			OrganizationID: organization.ID,
+			Name:           name,
+		})
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		if err == nil {
+			groups = append(groups, group)
+		}
+	} else {
+		groups, err = api.Database.GetGroupsByOrganizationID(ctx, organization.ID)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+	// The group of all users is managed by Coder.
+	groups = slices.Clone(groups)
+	for i, group := range groups {
+		if group.ID == organization.ID {
+			groups = slices.Delete(groups, i, i+1)
+			break
+		}
+	}
+
+	total := len(groups)
+	startIndex := 1
+	if query.Pagination != nil {
+		startIndex = query.Pagination.StartIndex
+		if startIndex > len(groups) {
+			startIndex = len(groups) + 1
+		}
+		groups = groups[startIndex-1:]
+		if count := query.Pagination.Count; count > 0 && count < len(groups) {
+			groups = groups[:count]
+		}
+	}
+	withMembers := query.Projection == nil || !slices.ContainsFunc(query.Projection.ExcludedAttributes, func(attribute string) bool {
+		return strings.EqualFold(attribute, "members")
+	})
+
+	sGroups := make([]SCIMGroup, 0, len(groups))
+	for _, group := range groups {
+		sGroup, err := api.scimGroup(ctx, group, withMembers)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		sGroups = append(sGroups, sGroup)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, scimList(sGroups, total, startIndex))
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Failure 404
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	sGroup, err := api.scimGroup(ctx, group, true)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sGroup)
+}
+
+// @Summary SCIM 2.0: Create group
+// @ID scim-create-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	organization, err := api.scimOrganization(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	err = api.scimVerifyGroupName(ctx, organization.ID, sGroup.DisplayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	members, err := api.scimGroupMemberIDs(ctx, organization.ID, sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var group database.Group
+	err = api.Database.InTx(func(tx database.Store) error {
+		group, err = tx.InsertGroup(ctx, database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           sGroup.DisplayName,
+			OrganizationID: organization.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		return api.scimSetGroupMembers(ctx, tx, group, members)
+	}, nil)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	sGroup, err = api.scimGroup(ctx, group, true)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, sGroup)
+}
+
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	var sGroup SCIMGroup
+	err = json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	members, err := api.scimGroupMemberIDs(ctx, group.OrganizationID, sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	group, err = api.scimUpdateGroup(ctx, group, sGroup.DisplayName, members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	sGroup, err = api.scimGroup(ctx, group, true)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sGroup)
+}
+
+// scimPatchGroup renames a group, and adds or removes its members.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchRequest true "Update group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	var req SCIMPatchRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	currentMembers, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	name := group.Name
+	members := map[uuid.UUID]bool{}
+	for _, member := range currentMembers {
+		members[member.ID] = true
+	}
+	// applyMembers changes the members of the group according to an
+	// operation. Removing without a list of members removes all members.
+	applyMembers := func(op string, sMembers []SCIMGroupMember) error {
+		ids := make([]uuid.UUID, 0, len(sMembers))
+		if op == "remove" {
+			for _, member := range sMembers {
+				id, err := uuid.Parse(member.Value)
+				if err != nil {
+					return xerrors.Errorf("member %q: %w", member.Value, spec.ErrInvalidValue)
+				}
+				ids = append(ids, id)
+			}
+		} else {
+			ids, err = api.scimGroupMemberIDs(ctx, group.OrganizationID, sMembers)
+			if err != nil {
+				return err
+			}
+		}
+		switch {
+		case op == "replace" || (op == "remove" && len(ids) == 0):
+			members = map[uuid.UUID]bool{}
+		case op == "remove":
+			for _, id := range ids {
+				delete(members, id)
+			}
+			return nil
+		}
+		for _, id := range ids {
+			members[id] = true
+		}
+		return nil
+	}
+
+	for _, operation := range req.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "remove" && op != "replace" {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("operation %q: %w", operation.Op, spec.ErrInvalidSyntax))
+			return
+		}
+		path := strings.ToLower(operation.Path)
+		switch {
+		case path == "":
+			// The value contains the attributes to change.
+			var value struct {
+				DisplayName *string            `json:"displayName"`
+				Members     *[]SCIMGroupMember `json:"members"`
+			}
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				_ = handlerutil.WriteError(rw, xerrors.Errorf("value: %w", spec.ErrInvalidValue))
+				return
+			}
+			if value.DisplayName != nil {
+				name = *value.DisplayName
+			}
+			if value.Members != nil {
+				err = applyMembers(op, *value.Members)
+			}
+		case path == "displayname":
+			if err := json.Unmarshal(operation.Value, &name); err != nil {
+				_ = handlerutil.WriteError(rw, xerrors.Errorf("displayName: %w", spec.ErrInvalidValue))
+				return
+			}
+		case path == "members":
+			var sMembers []SCIMGroupMember
+			if len(operation.Value) > 0 {
+				if err := json.Unmarshal(operation.Value, &sMembers); err != nil {
+					_ = handlerutil.WriteError(rw, xerrors.Errorf("members: %w", spec.ErrInvalidValue))
+					return
+				}
+			}
+			err = applyMembers(op, sMembers)
+		case op == "remove" && strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]"):
+			// e.g. members[value eq "<user ID>"]
+			var value string
+			value, err = scimFilterValue(operation.Path[len("members["):len(operation.Path)-1], "value")
+			if err == nil {
+				err = applyMembers(op, []SCIMGroupMember{{Value: value}})
+			}
+		default:
+			err = xerrors.Errorf("path %q: %w", operation.Path, spec.ErrInvalidPath)
+		}
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	group, err = api.scimUpdateGroup(ctx, group, name, maps.Keys(members))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	sGroup, err := api.scimGroup(ctx, group, true)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sGroup)
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	err = api.Database.DeleteGroupByID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimGroupByID returns the group with the ID of a SCIM request. The group of
+// all users is not found, since it's managed by Coder.
+func (api *API) scimGroupByID(ctx context.Context, id string) (database.Group, error) {
+	organization, err := api.scimOrganization(ctx)
+	if err != nil {
+		return database.Group{}, err
+	}
+	gid, err := uuid.Parse(id)
+	if err != nil || gid == organization.ID {
+		return database.Group{}, xerrors.Errorf("group %q: %w", id, spec.ErrNotFound)
+	}
+	group, err := api.Database.GetGroupByID(ctx, gid)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && group.OrganizationID != organization.ID) {
+		return database.Group{}, xerrors.Errorf("group %q: %w", id, spec.ErrNotFound)
+	}
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("get group: %w", err)
+	}
+	return group, nil
+}
+
+// scimVerifyGroupName returns an error if a group can't be given the name.
+func (api *API) scimVerifyGroupName(ctx context.Context, organizationID uuid.UUID, name string) error {
+	if name == "" || name == database.AllUsersGroup {
+		return xerrors.Errorf("displayName %q: %w", name, spec.ErrInvalidValue)
+	}
+	_, err := api.Database.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+		OrganizationID: organizationID,
+		Name:           name,
+	})
+	if err == nil {
+		return xerrors.Errorf("group %q already exists: %w", name, spec.ErrUniqueness)
+	}
+	if !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get group by name: %w", err)
+	}
+	return nil
+}
+
+// scimGroupMemberIDs returns the user IDs of members. Members must belong to
+// the organization of the group.
+func (api *API) scimGroupMemberIDs(ctx context.Context, organizationID uuid.UUID, members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("member %q: %w", member.Value, spec.ErrInvalidValue)
+		}
+		_, err = api.Database.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: organizationID,
+			UserID:         id,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("member %q is not a user of the organization: %w", member.Value, spec.ErrInvalidValue)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("get organization member: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// scimUpdateGroup renames a group and replaces its members.
+func (api *API) scimUpdateGroup(ctx context.Context, group database.Group, name string, members []uuid.UUID) (database.Group, error) {
+	if name != group.Name {
+		err := api.scimVerifyGroupName(ctx, group.OrganizationID, name)
+		if err != nil {
+			return database.Group{}, err
+		}
+	}
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		if name != group.Name {
+			group, err = tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+				ID:             group.ID,
+				Name:           name,
+				AvatarURL:      group.AvatarURL,
+				QuotaAllowance: group.QuotaAllowance,
+			})
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+		}
+		return api.scimSetGroupMembers(ctx, tx, group, members)
+	}, nil)
+	return group, err
+}
+
+// scimSetGroupMembers changes the members of a group. Like for users that sign
+// in with OIDC, the groups of each added or removed user are replaced with
+// setUserGroups.
+func (api *API) scimSetGroupMembers(ctx context.Context, tx database.Store, group database.Group, members []uuid.UUID) error {
+	groups, err := tx.GetGroupsByOrganizationID(ctx, group.OrganizationID)
+	if err != nil {
+		return xerrors.Errorf("get groups: %w", err)
+	}
+	groupNames := map[uuid.UUID][]string{}
+	for _, orgGroup := range groups {
+		if orgGroup.ID == group.OrganizationID {
+			continue
+		}
+		orgGroupMembers, err := tx.GetGroupMembers(ctx, orgGroup.ID)
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		for _, member := range orgGroupMembers {
+			groupNames[member.ID] = append(groupNames[member.ID], orgGroup.Name)
+		}
+	}
+
+	changed := map[uuid.UUID][]string{}
+	for _, id := range members {
+		if !slices.Contains(groupNames[id], group.Name) {
+			changed[id] = append(slices.Clone(groupNames[id]), group.Name)
+		}
+	}
+	for id, names := range groupNames {
+		index := slices.Index(names, group.Name)
+		if index >= 0 && !slices.Contains(members, id) {
+			changed[id] = slices.Delete(slices.Clone(names), index, index+1)
+		}
+	}
+	for id, names := range changed {
+		err := api.setUserGroups(ctx, tx, id, names)
+		if err != nil {
+			return xerrors.Errorf("set groups of user %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (api *API) scimGroup(ctx context.Context, group database.Group, withMembers bool) (SCIMGroup, error) {
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     []SCIMGroupMember{},
+	}
+	sGroup.Meta.ResourceType = "Group"
+	if !withMembers {
+		return sGroup, nil
+	}
+	members, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		return SCIMGroup{}, xerrors.Errorf("get group members: %w", err)
+	}
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	return sGroup, nil
+}
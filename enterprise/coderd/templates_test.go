@@ -206,6 +206,82 @@ func TestTemplates(t *testing.T) {
 		require.NoError(t, err)
 		require.EqualValues(t, exp, *ws.TTLMillis)
 	})
+
+	t.Run("SetRestartRequirement", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.RestartRequirement = &codersdk.TemplateRestartRequirement{
+				DaysOfWeek: []string{"monday"},
+				Weeks:      1,
+			}
+		})
+		require.Equal(t, []string{"monday"}, template.RestartRequirement.DaysOfWeek)
+		require.EqualValues(t, 1, template.RestartRequirement.Weeks)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Updating other fields keeps the restart requirement.
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:                         template.Name,
+			DisplayName:                  template.DisplayName,
+			Description:                  "new description",
+			Icon:                         template.Icon,
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"monday"}, updated.RestartRequirement.DaysOfWeek)
+		require.EqualValues(t, 1, updated.RestartRequirement.Weeks)
+
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:                         template.Name,
+			DisplayName:                  template.DisplayName,
+			Description:                  template.Description,
+			Icon:                         template.Icon,
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+			RestartRequirement: &codersdk.TemplateRestartRequirement{
+				DaysOfWeek: []string{"saturday", "sunday"},
+				Weeks:      2,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"saturday", "sunday"}, updated.RestartRequirement.DaysOfWeek)
+		require.EqualValues(t, 2, updated.RestartRequirement.Weeks)
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"saturday", "sunday"}, template.RestartRequirement.DaysOfWeek)
+		require.EqualValues(t, 2, template.RestartRequirement.Weeks)
+
+		// Invalid weekdays are rejected.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:                         template.Name,
+			DisplayName:                  template.DisplayName,
+			Description:                  template.Description,
+			Icon:                         template.Icon,
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+			RestartRequirement: &codersdk.TemplateRestartRequirement{
+				DaysOfWeek: []string{"funday"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
 }
 
 func TestTemplateACL(t *testing.T) {
@@ -0,0 +1,211 @@
+package coderd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/schedule"
+	"github.com/coder/coder/codersdk"
+)
+
+// enterpriseUserQuietHoursScheduleStore provides an
+// agpl.UserQuietHoursScheduleStore that has all fields implemented for
+// enterprise customers.
+type enterpriseUserQuietHoursScheduleStore struct {
+	defaultSchedule string
+}
+
+var _ schedule.UserQuietHoursScheduleStore = &enterpriseUserQuietHoursScheduleStore{}
+
+func newEnterpriseUserQuietHoursScheduleStore(defaultSchedule string) (*enterpriseUserQuietHoursScheduleStore, error) {
+	s := &enterpriseUserQuietHoursScheduleStore{
+		defaultSchedule: defaultSchedule,
+	}
+
+	_, err := s.parseSchedule(defaultSchedule)
+	if err != nil {
+		return nil, xerrors.Errorf("parse default schedule: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *enterpriseUserQuietHoursScheduleStore) parseSchedule(rawSchedule string) (schedule.UserQuietHoursScheduleOptions, error) {
+	userSet := true
+	if rawSchedule == "" {
+		userSet = false
+		rawSchedule = s.defaultSchedule
+	}
+	if rawSchedule == "" {
+		// Quiet hours are disabled deployment-wide.
+		return schedule.UserQuietHoursScheduleOptions{
+			Schedule: nil,
+			UserSet:  false,
+		}, nil
+	}
+
+	sched, err := schedule.Daily(rawSchedule)
+	if err != nil {
+		// This shouldn't get hit during Gets, only Sets.
+		return schedule.UserQuietHoursScheduleOptions{}, xerrors.Errorf("parse daily schedule %q: %w", rawSchedule, err)
+	}
+	// Quiet hours windows must only start once per day.
+	if sched.Min() < 24*time.Hour {
+		return schedule.UserQuietHoursScheduleOptions{}, xerrors.New("schedule must only fire once per day")
+	}
+
+	return schedule.UserQuietHoursScheduleOptions{
+		Schedule: sched,
+		UserSet:  userSet,
+	}, nil
+}
+
+func (s *enterpriseUserQuietHoursScheduleStore) Get(ctx context.Context, db database.Store, userID uuid.UUID) (schedule.UserQuietHoursScheduleOptions, error) {
+	user, err := db.GetUserByID(ctx, userID)
+	if err != nil {
+		return schedule.UserQuietHoursScheduleOptions{}, xerrors.Errorf("get user by ID: %w", err)
+	}
+
+	return s.parseSchedule(user.QuietHoursSchedule)
+}
+
+func (s *enterpriseUserQuietHoursScheduleStore) Set(ctx context.Context, db database.Store, userID uuid.UUID, rawSchedule string) (schedule.UserQuietHoursScheduleOptions, error) {
+	opts, err := s.parseSchedule(rawSchedule)
+	if err != nil {
+		return opts, xerrors.Errorf("%w: %s", schedule.ErrInvalidUserQuietHoursSchedule, err.Error())
+	}
+
+	// Use the tidy version when storing the user's schedule.
+	rawSchedule = ""
+	if opts.UserSet {
+		rawSchedule = opts.Schedule.String()
+	}
+
+	_, err = db.UpdateUserQuietHoursSchedule(ctx, database.UpdateUserQuietHoursScheduleParams{
+		ID:                 userID,
+		QuietHoursSchedule: rawSchedule,
+	})
+	if err != nil {
+		return schedule.UserQuietHoursScheduleOptions{}, xerrors.Errorf("update user quiet hours schedule: %w", err)
+	}
+
+	// We don't update workspace build deadlines when the user changes their
+	// own quiet hours schedule, because they could potentially keep their
+	// workspace running forever. The new schedule applies from the next build.
+
+	return opts, nil
+}
+
+func (api *API) restartRequirementEnabledMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		api.entitlementsMu.RLock()
+		ats := api.entitlements.Features[codersdk.FeatureAdvancedTemplateScheduling].Enabled
+		api.entitlementsMu.RUnlock()
+
+		if !ats {
+			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+				Message: "Advanced template scheduling (and user quiet hours schedule) is an Enterprise feature. Contact sales!",
+			})
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// @Summary Get user quiet hours schedule
+// @ID get-user-quiet-hours-schedule
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserQuietHoursScheduleResponse
+// @Router /users/{user}/quiet-hours [get]
+func (api *API) userQuietHoursSchedule(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	opts, err := (*api.AGPL.UserQuietHoursScheduleStore.Load()).Get(ctx, api.Database, user.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if opts.Schedule == nil {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserQuietHoursSchedule(opts))
+}
+
+// @Summary Update user quiet hours schedule
+// @ID update-user-quiet-hours-schedule
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateUserQuietHoursScheduleRequest true "Update schedule request"
+// @Success 200 {object} codersdk.UserQuietHoursScheduleResponse
+// @Router /users/{user}/quiet-hours [put]
+func (api *API) putUserQuietHoursSchedule(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		params codersdk.UpdateUserQuietHoursScheduleRequest
+	)
+
+	if !httpapi.Read(ctx, rw, r, &params) {
+		return
+	}
+
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, user.UserDataRBACObject()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	opts, err := (*api.AGPL.UserQuietHoursScheduleStore.Load()).Set(ctx, api.Database, user.ID, params.Schedule)
+	if errors.Is(err, schedule.ErrInvalidUserQuietHoursSchedule) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid quiet hours schedule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating quiet hours schedule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if opts.Schedule == nil {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserQuietHoursSchedule(opts))
+}
+
+func convertUserQuietHoursSchedule(opts schedule.UserQuietHoursScheduleOptions) codersdk.UserQuietHoursScheduleResponse {
+	loc := opts.Schedule.Location()
+	next := opts.Schedule.Next(database.Now())
+	return codersdk.UserQuietHoursScheduleResponse{
+		RawSchedule: opts.Schedule.String(),
+		UserSet:     opts.UserSet,
+		Time:        next.In(loc).Format("15:04"),
+		Timezone:    loc.String(),
+		Next:        next,
+	}
+}
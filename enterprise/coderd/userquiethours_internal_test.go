@@ -0,0 +1,28 @@
+package coderd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/schedule"
+)
+
+func TestEnterpriseUserQuietHoursScheduleStore_Set(t *testing.T) {
+	t.Parallel()
+
+	store, err := newEnterpriseUserQuietHoursScheduleStore("CRON_TZ=UTC 0 0 * * *")
+	require.NoError(t, err)
+	db := dbfake.New()
+
+	_, err = store.Set(context.Background(), db, uuid.New(), "CRON_TZ=UTC */30 * * * *")
+	require.ErrorIs(t, err, schedule.ErrInvalidUserQuietHoursSchedule)
+
+	// Errors of the database aren't caused by the schedule.
+	_, err = store.Set(context.Background(), db, uuid.New(), "CRON_TZ=UTC 30 3 * * *")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, schedule.ErrInvalidUserQuietHoursSchedule)
+}
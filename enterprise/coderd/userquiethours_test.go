@@ -0,0 +1,182 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/schedule"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/testutil"
+)
+
+func TestUserQuietHours(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		defaultQuietHoursSchedule := "CRON_TZ=America/Chicago 0 0 * * *"
+		defaultScheduleParsed, err := schedule.Daily(defaultQuietHoursSchedule)
+		require.NoError(t, err)
+		nextTime := defaultScheduleParsed.Next(time.Now().In(defaultScheduleParsed.Location()))
+		if time.Until(nextTime) < time.Hour {
+			// Use a different default schedule instead, because we want to avoid
+			// the schedule "ticking over" during this test run.
+			defaultQuietHoursSchedule = "CRON_TZ=America/Chicago 0 12 * * *"
+			defaultScheduleParsed, err = schedule.Daily(defaultQuietHoursSchedule)
+			require.NoError(t, err)
+		}
+
+		dv := coderdtest.DeploymentValues(t)
+		err = dv.UserQuietHoursSchedule.DefaultSchedule.Set(defaultQuietHoursSchedule)
+		require.NoError(t, err)
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Get quiet hours for a user that doesn't have them set.
+		sched1, err := client.UserQuietHoursSchedule(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, defaultScheduleParsed.String(), sched1.RawSchedule)
+		require.False(t, sched1.UserSet)
+		require.Equal(t, defaultScheduleParsed.Location().String(), sched1.Timezone)
+		require.WithinDuration(t, defaultScheduleParsed.Next(time.Now()), sched1.Next, 15*time.Second)
+
+		// Set their quiet hours.
+		customQuietHoursSchedule := "CRON_TZ=Australia/Sydney 0 0 * * *"
+		customScheduleParsed, err := schedule.Daily(customQuietHoursSchedule)
+		require.NoError(t, err)
+		nextTime = customScheduleParsed.Next(time.Now().In(customScheduleParsed.Location()))
+		if time.Until(nextTime) < time.Hour {
+			// Use a different schedule instead, because we want to avoid the
+			// schedule "ticking over" during this test run.
+			customQuietHoursSchedule = "CRON_TZ=Australia/Sydney 0 12 * * *"
+			customScheduleParsed, err = schedule.Daily(customQuietHoursSchedule)
+			require.NoError(t, err)
+		}
+
+		sched2, err := client.UpdateUserQuietHoursSchedule(ctx, user.UserID.String(), codersdk.UpdateUserQuietHoursScheduleRequest{
+			Schedule: customQuietHoursSchedule,
+		})
+		require.NoError(t, err)
+		require.Equal(t, customScheduleParsed.String(), sched2.RawSchedule)
+		require.True(t, sched2.UserSet)
+		require.Equal(t, customScheduleParsed.Location().String(), sched2.Timezone)
+		require.WithinDuration(t, customScheduleParsed.Next(time.Now()), sched2.Next, 15*time.Second)
+
+		// Get quiet hours for a user that has them set.
+		sched3, err := client.UserQuietHoursSchedule(ctx, user.UserID.String())
+		require.NoError(t, err)
+		require.Equal(t, sched2, sched3)
+
+		// The schedule is also returned on the user.
+		me, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, customScheduleParsed.String(), me.QuietHoursSchedule)
+
+		// Reset the user's quiet hours to the default.
+		sched4, err := client.UpdateUserQuietHoursSchedule(ctx, codersdk.Me, codersdk.UpdateUserQuietHoursScheduleRequest{
+			Schedule: "",
+		})
+		require.NoError(t, err)
+		require.Equal(t, defaultScheduleParsed.String(), sched4.RawSchedule)
+		require.False(t, sched4.UserSet)
+
+		// Invalid schedules are rejected.
+		for _, invalid := range []string{
+			"garbage",
+			"CRON_TZ=UTC 0 0 * * 1",
+			"CRON_TZ=UTC */30 * * * *",
+		} {
+			_, err = client.UpdateUserQuietHoursSchedule(ctx, codersdk.Me, codersdk.UpdateUserQuietHoursScheduleRequest{
+				Schedule: invalid,
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, invalid)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), invalid)
+		}
+	})
+
+	t.Run("NotEntitled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				// Not entitled.
+				// codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UserQuietHoursSchedule(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("RestartRequirement", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		quietHours, err := client.UpdateUserQuietHoursSchedule(ctx, codersdk.Me, codersdk.UpdateUserQuietHoursScheduleRequest{
+			Schedule: "CRON_TZ=UTC 30 3 * * *",
+		})
+		require.NoError(t, err)
+		quietHoursParsed, err := schedule.Daily(quietHours.RawSchedule)
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.RestartRequirement = &codersdk.TemplateRestartRequirement{
+				DaysOfWeek: codersdk.AllDaysOfWeek,
+				Weeks:      1,
+			}
+		})
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		// The max deadline is the first quiet hours window after the build
+		// completed, plus the restart requirement buffer.
+		require.True(t, build.MaxDeadline.Valid)
+		require.NotNil(t, build.Job.CompletedAt)
+		expected := quietHoursParsed.Next(build.Job.CompletedAt.Add(schedule.RestartRequirementBuffer))
+		require.WithinDuration(t, expected, build.MaxDeadline.Time, time.Second)
+		require.True(t, !build.Deadline.Time.After(build.MaxDeadline.Time))
+	})
+}
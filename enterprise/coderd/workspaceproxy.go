@@ -1,18 +1,25 @@
 package coderd
 
 import (
+	"context"
 	"crypto/sha256"
 	"database/sql"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/cryptorand"
@@ -65,13 +72,11 @@ func (api *API) postWorkspaceProxy(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	id := uuid.New()
-	secret, err := cryptorand.HexString(64)
+	fullToken, hashedSecret, err := generateWorkspaceProxyToken(id)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
-	hashedSecret := sha256.Sum256([]byte(secret))
-	fullToken := fmt.Sprintf("%s:%s", id, secret)
 
 	proxy, err := api.Database.InsertWorkspaceProxy(ctx, database.InsertWorkspaceProxyParams{
 		ID:                id,
@@ -80,7 +85,7 @@ func (api *API) postWorkspaceProxy(rw http.ResponseWriter, r *http.Request) {
 		Icon:              req.Icon,
 		Url:               req.URL,
 		WildcardHostname:  req.WildcardHostname,
-		TokenHashedSecret: hashedSecret[:],
+		TokenHashedSecret: hashedSecret,
 		CreatedAt:         database.Now(),
 		UpdatedAt:         database.Now(),
 	})
@@ -97,11 +102,181 @@ func (api *API) postWorkspaceProxy(rw http.ResponseWriter, r *http.Request) {
 
 	aReq.New = proxy
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWorkspaceProxyResponse{
-		Proxy:      convertProxy(proxy),
+		Proxy:      convertProxy(proxy, api.ProxyHealthInterval),
 		ProxyToken: fullToken,
 	})
 }
 
+// @Summary Get workspace proxy
+// @ID get-workspace-proxy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param workspaceproxy path string true "Proxy ID or name" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceProxy
+// @Router /workspaceproxies/{workspaceproxy} [get]
+func (api *API) workspaceProxy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		proxy = httpmw.WorkspaceProxyParam(r)
+	)
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.redactProxy(r, convertProxy(proxy, api.ProxyHealthInterval)))
+}
+
+// @Summary Update workspace proxy
+// @ID update-workspace-proxy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param workspaceproxy path string true "Proxy ID or name" format(uuid)
+// @Param request body codersdk.PatchWorkspaceProxy true "Update workspace proxy request"
+// @Success 200 {object} codersdk.UpdateWorkspaceProxyResponse
+// @Router /workspaceproxies/{workspaceproxy} [patch]
+func (api *API) patchWorkspaceProxy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		proxy             = httpmw.WorkspaceProxyParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceProxy](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	aReq.Old = proxy
+	defer commitAudit()
+
+	var req codersdk.PatchWorkspaceProxy
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	params := database.UpdateWorkspaceProxyParams{
+		ID:               proxy.ID,
+		Name:             proxy.Name,
+		DisplayName:      proxy.DisplayName,
+		Icon:             proxy.Icon,
+		Url:              proxy.Url,
+		WildcardHostname: proxy.WildcardHostname,
+	}
+	if req.Name != "" {
+		params.Name = req.Name
+	}
+	if req.DisplayName != "" {
+		params.DisplayName = req.DisplayName
+	}
+	if req.Icon != nil {
+		params.Icon = *req.Icon
+	}
+	if req.URL != "" {
+		if err := validateProxyURL(req.URL); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "URL is invalid.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		params.Url = req.URL
+	}
+	if req.WildcardHostname != nil {
+		if *req.WildcardHostname != "" {
+			if _, err := httpapi.CompileHostnamePattern(*req.WildcardHostname); err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Wildcard URL is invalid.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+		params.WildcardHostname = *req.WildcardHostname
+	}
+
+	var fullToken string
+	if req.RegenerateToken {
+		var err error
+		fullToken, params.TokenHashedSecret, err = generateWorkspaceProxyToken(proxy.ID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	updatedProxy, err := api.Database.UpdateWorkspaceProxy(ctx, params)
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace proxy with name %q already exists.", params.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = updatedProxy
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UpdateWorkspaceProxyResponse{
+		Proxy:      convertProxy(updatedProxy, api.ProxyHealthInterval),
+		ProxyToken: fullToken,
+	})
+}
+
+// @Summary Delete workspace proxy
+// @ID delete-workspace-proxy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param workspaceproxy path string true "Proxy ID or name" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /workspaceproxies/{workspaceproxy} [delete]
+func (api *API) deleteWorkspaceProxy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		proxy             = httpmw.WorkspaceProxyParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceProxy](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	aReq.Old = proxy
+	defer commitAudit()
+
+	// Proxies are soft-deleted, so the name can be reused and the token of
+	// the proxy stops working.
+	err := api.Database.UpdateWorkspaceProxyDeleted(ctx, database.UpdateWorkspaceProxyDeletedParams{
+		ID:      proxy.ID,
+		Deleted: true,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Proxy has been deleted!",
+	})
+}
+
+// generateWorkspaceProxyToken returns a new token for the proxy, and the hash
+// of its secret that's stored in the database.
+func generateWorkspaceProxyToken(id uuid.UUID) (token string, hashedSecret []byte, err error) {
+	secret, err := cryptorand.HexString(64)
+	if err != nil {
+		return "", nil, xerrors.Errorf("generate token: %w", err)
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("%s:%s", id, secret), hashed[:], nil
+}
+
 // nolint:revive
 func validateProxyURL(u string) error {
 	p, err := url.Parse(u)
@@ -133,28 +308,213 @@ func (api *API) workspaceProxies(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertProxies(proxies))
+	resp := convertProxies(proxies, api.ProxyHealthInterval)
+	for i := range resp {
+		resp[i] = api.redactProxy(r, resp[i])
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
-func convertProxies(p []database.WorkspaceProxy) []codersdk.WorkspaceProxy {
+// redactProxy removes the version and the health error of the proxy for
+// users who can't update it. Members read proxies to connect through them,
+// and don't need the details of the deployment.
+func (api *API) redactProxy(r *http.Request, proxy codersdk.WorkspaceProxy) codersdk.WorkspaceProxy {
+	if api.Authorize(r, rbac.ActionUpdate, rbac.ResourceWorkspaceProxy.WithID(proxy.ID)) {
+		return proxy
+	}
+	proxy.Version = ""
+	proxy.Status.Error = ""
+	return proxy
+}
+
+func convertProxies(p []database.WorkspaceProxy, healthInterval time.Duration) []codersdk.WorkspaceProxy {
 	resp := make([]codersdk.WorkspaceProxy, 0, len(p))
 	for _, proxy := range p {
-		resp = append(resp, convertProxy(proxy))
+		resp = append(resp, convertProxy(proxy, healthInterval))
 	}
 	return resp
 }
 
-func convertProxy(p database.WorkspaceProxy) codersdk.WorkspaceProxy {
+func convertProxy(p database.WorkspaceProxy, healthInterval time.Duration) codersdk.WorkspaceProxy {
 	return codersdk.WorkspaceProxy{
 		ID:               p.ID,
 		Name:             p.Name,
+		DisplayName:      p.DisplayName,
 		Icon:             p.Icon,
 		URL:              p.Url,
 		WildcardHostname: p.WildcardHostname,
 		CreatedAt:        p.CreatedAt,
 		UpdatedAt:        p.UpdatedAt,
 		Deleted:          p.Deleted,
+		Version:          p.Version,
+		Status:           convertProxyStatus(p, healthInterval),
+	}
+}
+
+// convertProxyStatus returns the health of the proxy. Proxies register every
+// health interval, so a proxy that missed a few heartbeats is unresponsive.
+func convertProxyStatus(p database.WorkspaceProxy, healthInterval time.Duration) codersdk.WorkspaceProxyStatus {
+	status := codersdk.WorkspaceProxyStatus{
+		Status: codersdk.ProxyHealthy,
+	}
+	if p.LastHeartbeatAt.Valid {
+		status.LastHeartbeatAt = &p.LastHeartbeatAt.Time
+	}
+	if p.HealthCheckedAt.Valid {
+		status.CheckedAt = &p.HealthCheckedAt.Time
+	}
+
+	switch {
+	case !p.LastHeartbeatAt.Valid:
+		status.Status = codersdk.ProxyUnregistered
+		status.Error = "The proxy has never registered. Start it with its token."
+	case database.Now().Sub(p.LastHeartbeatAt.Time) > 3*healthInterval:
+		status.Status = codersdk.ProxyUnresponsive
+		status.Error = fmt.Sprintf("The proxy hasn't registered since %s.", p.LastHeartbeatAt.Time.Format(time.RFC3339))
+	case p.HealthError != "":
+		status.Status = codersdk.ProxyUnreachable
+		status.Error = p.HealthError
+	}
+	return status
+}
+
+// @Summary Register workspace proxy
+// @ID register-workspace-proxy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body wsproxysdk.RegisterWorkspaceProxyRequest true "Register workspace proxy request"
+// @Success 200 {object} wsproxysdk.RegisterWorkspaceProxyResponse
+// @Router /workspaceproxies/me/register [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		proxy = httpmw.WorkspaceProxy(r)
+	)
+
+	var req wsproxysdk.RegisterWorkspaceProxyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	_, err := api.Database.RegisterWorkspaceProxy(ctx, database.RegisterWorkspaceProxyParams{
+		ID:      proxy.ID,
+		Version: req.Version,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, wsproxysdk.RegisterWorkspaceProxyResponse{
+		HeartbeatInterval: api.ProxyHealthInterval,
+	})
+}
+
+// runWorkspaceProxyHealthLoop checks that the workspace proxies are reachable
+// at their URL every health interval, and records the result in the database.
+func (api *API) runWorkspaceProxyHealthLoop(ctx context.Context) {
+	ticker := time.NewTicker(api.ProxyHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !api.AGPL.Experiments.Enabled(codersdk.ExperimentMoons) {
+			continue
+		}
+
+		err := api.checkWorkspaceProxies(ctx)
+		if err != nil && ctx.Err() == nil {
+			api.Logger.Warn(ctx, "check workspace proxies", slog.Error(err))
+		}
+	}
+}
+
+func (api *API) checkWorkspaceProxies(ctx context.Context) error {
+	//nolint:gocritic // The health loop isn't run on behalf of a user.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	proxies, err := api.Database.GetWorkspaceProxies(ctx)
+	if err != nil {
+		return xerrors.Errorf("get workspace proxies: %w", err)
+	}
+
+	var eg errgroup.Group
+	for _, proxy := range proxies {
+		proxy := proxy
+		eg.Go(func() error {
+			var healthError string
+			err := api.checkWorkspaceProxy(ctx, proxy)
+			if err != nil {
+				healthError = err.Error()
+			}
+			err = api.Database.UpdateWorkspaceProxyHealth(ctx, database.UpdateWorkspaceProxyHealthParams{
+				ID:              proxy.ID,
+				HealthCheckedAt: sql.NullTime{Time: database.Now(), Valid: true},
+				HealthError:     healthError,
+			})
+			if err != nil {
+				return xerrors.Errorf("update health of proxy %q: %w", proxy.Name, err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// checkWorkspaceProxy returns an error if the proxy isn't reachable at its
+// URL.
+func (api *API) checkWorkspaceProxy(ctx context.Context, proxy database.WorkspaceProxy) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	proxyURL, err := url.Parse(proxy.Url)
+	if err != nil {
+		return xerrors.Errorf("parse proxy url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyURL.JoinPath("/healthz").String(), nil)
+	if err != nil {
+		return xerrors.Errorf("create healthz request: %w", err)
+	}
+	client := api.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("get proxy healthz: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return xerrors.Errorf("proxy healthz returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// healthcheckWorkspaceProxies returns the workspace proxies for the
+// deployment healthcheck, or none if workspace proxies aren't enabled.
+func (api *API) healthcheckWorkspaceProxies(ctx context.Context) ([]codersdk.WorkspaceProxy, error) {
+	if !api.AGPL.Experiments.Enabled(codersdk.ExperimentMoons) {
+		return nil, nil
+	}
+	api.entitlementsMu.RLock()
+	enabled := api.entitlements.Features[codersdk.FeatureWorkspaceProxy].Enabled
+	api.entitlementsMu.RUnlock()
+	if !enabled {
+		return nil, nil
+	}
+
+	//nolint:gocritic // The healthcheck reports on all proxies.
+	proxies, err := api.Database.GetWorkspaceProxies(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace proxies: %w", err)
 	}
+	return convertProxies(proxies, api.ProxyHealthInterval), nil
 }
 
 // @Summary Issue signed workspace app token
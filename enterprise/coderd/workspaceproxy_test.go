@@ -1,8 +1,10 @@
 package coderd_test
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
+	"net/url"
 	"testing"
 
 	"github.com/google/uuid"
@@ -13,6 +15,7 @@ import (
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
+	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database/dbtestutil"
 	"github.com/coder/coder/coderd/workspaceapps"
@@ -61,7 +64,205 @@ func TestWorkspaceProxyCRUD(t *testing.T) {
 		require.Len(t, proxies, 1)
 		require.Equal(t, proxyRes.Proxy, proxies[0])
 		require.NotEmpty(t, proxyRes.ProxyToken)
+		require.Equal(t, codersdk.ProxyUnregistered, proxies[0].Status.Status)
 	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Parallel()
+
+		client := newProxyClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		proxyRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name:             namesgenerator.GetRandomName(1),
+			Icon:             "/emojis/flag.png",
+			URL:              "https://" + namesgenerator.GetRandomName(1) + ".com",
+			WildcardHostname: "*.sub.example.com",
+		})
+		require.NoError(t, err)
+
+		noWildcard := ""
+		updated, err := client.PatchWorkspaceProxy(ctx, proxyRes.Proxy.ID, codersdk.PatchWorkspaceProxy{
+			Name:             "renamed",
+			DisplayName:      "Renamed",
+			WildcardHostname: &noWildcard,
+		})
+		require.NoError(t, err)
+		require.Empty(t, updated.ProxyToken)
+		require.Equal(t, "renamed", updated.Proxy.Name)
+		require.Equal(t, "Renamed", updated.Proxy.DisplayName)
+		require.Equal(t, proxyRes.Proxy.Icon, updated.Proxy.Icon)
+		require.Equal(t, proxyRes.Proxy.URL, updated.Proxy.URL)
+		require.Empty(t, updated.Proxy.WildcardHostname)
+
+		proxy, err := client.WorkspaceProxyByName(ctx, "renamed")
+		require.NoError(t, err)
+		require.Equal(t, updated.Proxy, proxy)
+
+		_, err = client.PatchWorkspaceProxy(ctx, proxy.ID, codersdk.PatchWorkspaceProxy{
+			URL: "ftp://example.com",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+		// The old token stops working once the token was regenerated.
+		regenerated, err := client.PatchWorkspaceProxy(ctx, proxy.ID, codersdk.PatchWorkspaceProxy{
+			RegenerateToken: true,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, regenerated.ProxyToken)
+		require.NotEqual(t, proxyRes.ProxyToken, regenerated.ProxyToken)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		_ = proxyClient.SetSessionToken(proxyRes.ProxyToken)
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{})
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+
+		_ = proxyClient.SetSessionToken(regenerated.ProxyToken)
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{})
+		require.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
+		client := newProxyClient(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		proxyRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "deleted",
+			URL:  "https://" + namesgenerator.GetRandomName(1) + ".com",
+		})
+		require.NoError(t, err)
+
+		err = client.DeleteWorkspaceProxy(ctx, proxyRes.Proxy.ID)
+		require.NoError(t, err)
+
+		proxies, err := client.WorkspaceProxies(ctx)
+		require.NoError(t, err)
+		require.Empty(t, proxies)
+
+		_, err = client.WorkspaceProxyByName(ctx, "deleted")
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+		proxyClient := wsproxysdk.New(client.URL)
+		_ = proxyClient.SetSessionToken(proxyRes.ProxyToken)
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{})
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+
+		// The name of a deleted proxy can be reused.
+		_, err = client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "deleted",
+			URL:  "https://" + namesgenerator.GetRandomName(1) + ".com",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestWorkspaceProxyHealth(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.Experiments = []string{
+		string(codersdk.ExperimentMoons),
+		"*",
+	}
+	client, _, api := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: dv,
+		},
+		ProxyHealthInterval: testutil.IntervalFast,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureWorkspaceProxy: 1,
+		},
+	})
+
+	_ = coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+		Name: "running",
+	})
+	// A proxy that registers, but isn't served at its URL.
+	unreachableURL, err := url.Parse("http://127.0.0.1:1")
+	require.NoError(t, err)
+	unreachable := coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+		Name:     "unreachable",
+		ProxyURL: unreachableURL,
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	// A proxy that was never started.
+	_, err = client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+		Name: "unregistered",
+		URL:  "http://127.0.0.1:1",
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		proxy, err := client.WorkspaceProxyByName(ctx, "running")
+		return err == nil && proxy.Status.Status == codersdk.ProxyHealthy && proxy.Status.CheckedAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Eventually(t, func() bool {
+		proxy, err := client.WorkspaceProxyByName(ctx, "unreachable")
+		return err == nil && proxy.Status.Status == codersdk.ProxyUnreachable
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	proxies, err := client.WorkspaceProxies(ctx)
+	require.NoError(t, err)
+	statuses := map[string]codersdk.WorkspaceProxy{}
+	for _, proxy := range proxies {
+		statuses[proxy.Name] = proxy
+	}
+	require.Equal(t, buildinfo.Version(), statuses["running"].Version)
+	require.NotEmpty(t, statuses["unreachable"].Status.Error)
+	require.Equal(t, codersdk.ProxyUnregistered, statuses["unregistered"].Status.Status)
+	require.Nil(t, statuses["unregistered"].Status.LastHeartbeatAt)
+
+	// Members can see the health of proxies, but not why they're unhealthy.
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	proxies, err = member.WorkspaceProxies(ctx)
+	require.NoError(t, err)
+	for _, proxy := range proxies {
+		require.Empty(t, proxy.Version, proxy.Name)
+		require.Empty(t, proxy.Status.Error, proxy.Name)
+	}
+	proxy, err := member.WorkspaceProxyByName(ctx, "unreachable")
+	require.NoError(t, err)
+	require.Equal(t, codersdk.ProxyUnreachable, proxy.Status.Status)
+	require.Empty(t, proxy.Status.Error)
+
+	// A proxy that was shut down stops registering.
+	require.NoError(t, unreachable.Close())
+	require.Eventually(t, func() bool {
+		proxy, err := client.WorkspaceProxyByName(ctx, "unreachable")
+		return err == nil && proxy.Status.Status == codersdk.ProxyUnresponsive
+	}, testutil.WaitLong, testutil.IntervalFast)
+}
+
+func newProxyClient(t *testing.T) *codersdk.Client {
+	t.Helper()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.Experiments = []string{
+		string(codersdk.ExperimentMoons),
+		"*",
+	}
+	client := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: dv,
+		},
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+	_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureWorkspaceProxy: 1,
+		},
+	})
+	return client
 }
 
 func TestIssueSignedAppToken(t *testing.T) {
@@ -3,6 +3,7 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"math"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -38,6 +39,14 @@ func (c *committer) CommitQuota(
 		return nil, err
 	}
 
+	template, err := c.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	// Templates can make their workspaces cheaper or more expensive than the
+	// resources they provision.
+	cost := quotaCost(request.DailyCost, template.QuotaCostMultiplier)
+
 	var (
 		consumed int64
 		budget   int64
@@ -55,6 +64,15 @@ func (c *committer) CommitQuota(
 			return err
 		}
 
+		organization, err := s.GetOrganizationByID(ctx, workspace.OrganizationID)
+		if err != nil {
+			return err
+		}
+		organizationConsumed, err := s.GetQuotaConsumedForOrganization(ctx, workspace.OrganizationID)
+		if err != nil {
+			return err
+		}
+
 		// If the new build will reduce overall quota consumption, then we
 		// allow it even if the user is over quota.
 		netIncrease := true
@@ -63,21 +81,26 @@ func (c *committer) CommitQuota(
 			BuildNumber: build.BuildNumber - 1,
 		})
 		if err == nil {
-			if build.DailyCost < previousBuild.DailyCost {
+			if cost < previousBuild.DailyCost {
 				netIncrease = false
 			}
 		} else if !xerrors.Is(err, sql.ErrNoRows) {
 			return err
 		}
 
-		newConsumed := int64(request.DailyCost) + consumed
+		newConsumed := int64(cost) + consumed
 		if newConsumed > budget && netIncrease {
 			return nil
 		}
+		// An organization without a budget only limits spending through the
+		// allowances of its groups.
+		if organization.QuotaBudget > 0 && int64(cost)+organizationConsumed > organization.QuotaBudget && netIncrease {
+			return nil
+		}
 
 		_, err = s.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
 			ID:        build.ID,
-			DailyCost: request.DailyCost,
+			DailyCost: cost,
 		})
 		if err != nil {
 			return err
@@ -96,6 +119,7 @@ func (c *committer) CommitQuota(
 		Ok:              permit,
 		CreditsConsumed: int32(consumed),
 		Budget:          int32(budget),
+		DailyCost:       cost,
 	}, nil
 }
 
@@ -147,3 +171,98 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 		Budget:          int(quotaAllowance),
 	})
 }
+
+// @Summary Get organization quota
+// @ID get-organization-quota
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.OrganizationQuota
+// @Router /organizations/{organization}/quota [get]
+func (api *API) organizationQuota(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	consumed, err := api.Database.GetQuotaConsumedForOrganization(ctx, organization.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get consumed",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationQuota{
+		CreditsConsumed: int(consumed),
+		Budget:          int(organization.QuotaBudget),
+	})
+}
+
+// @Summary Update organization quota
+// @ID update-organization-quota
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.UpdateOrganizationQuotaRequest true "Update organization quota request"
+// @Success 200 {object} codersdk.OrganizationQuota
+// @Router /organizations/{organization}/quota [put]
+func (api *API) putOrganizationQuota(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, organization) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateOrganizationQuotaRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	organization, err := api.Database.UpdateOrganizationQuotaBudget(ctx, database.UpdateOrganizationQuotaBudgetParams{
+		ID:          organization.ID,
+		QuotaBudget: int64(req.Budget),
+		UpdatedAt:   database.Now(),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	consumed, err := api.Database.GetQuotaConsumedForOrganization(ctx, organization.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get consumed",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationQuota{
+		CreditsConsumed: int(consumed),
+		Budget:          int(organization.QuotaBudget),
+	})
+}
+
+// quotaCost applies a template's multiplier to a daily cost, saturating at the
+// bounds of int32.
+func quotaCost(dailyCost int32, multiplier float64) int32 {
+	cost := math.Round(float64(dailyCost) * multiplier)
+	switch {
+	case math.IsNaN(cost):
+		return 0
+	case cost >= math.MaxInt32:
+		return math.MaxInt32
+	case cost <= math.MinInt32:
+		return math.MinInt32
+	}
+	return int32(cost)
+}
@@ -2,6 +2,8 @@ package coderd_test
 
 import (
 	"context"
+	"math"
+	"net/http"
 	"sync"
 	"testing"
 
@@ -151,4 +153,189 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, client, 3, 3)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 	})
+
+	t.Run("TemplateCostMultiplier", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+		addQuotaAllowance(ctx, t, client, user, 5)
+
+		template := createCostlyTemplate(t, client, user.OrganizationID, 1)
+		multiplier := 3.0
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			QuotaCostMultiplier: &multiplier,
+		})
+		require.NoError(t, err)
+		require.Equal(t, multiplier, template.QuotaCostMultiplier)
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		require.EqualValues(t, 3, build.DailyCost)
+		verifyQuota(ctx, t, client, 3, 5)
+
+		// The multiplied cost of a second workspace exceeds the allowance.
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build = coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		require.Contains(t, build.Job.Error, "quota")
+		verifyQuota(ctx, t, client, 3, 5)
+
+		tooLarge := 1001.0
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			QuotaCostMultiplier: &tooLarge,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("TemplateCostMultiplierSaturates", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+		addQuotaAllowance(ctx, t, client, user, 5)
+
+		template := createCostlyTemplate(t, client, user.OrganizationID, math.MaxInt32/2)
+		multiplier := 1000.0
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			QuotaCostMultiplier: &multiplier,
+		})
+		require.NoError(t, err)
+
+		// The multiplied cost must not wrap around to a negative value that
+		// would slip under the allowance.
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		require.Contains(t, build.Job.Error, "quota")
+		verifyQuota(ctx, t, client, 0, 5)
+	})
+
+	t.Run("OrganizationBudget", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+		addQuotaAllowance(ctx, t, client, user, 10)
+
+		quota, err := client.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.UpdateOrganizationQuotaRequest{
+			Budget: 2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.OrganizationQuota{Budget: 2}, quota)
+
+		template := createCostlyTemplate(t, client, user.OrganizationID, 2)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		quota, err = client.OrganizationQuota(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.OrganizationQuota{CreditsConsumed: 2, Budget: 2}, quota)
+
+		// The user has allowance left, but the organization is out of budget.
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build = coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		require.Contains(t, build.Job.Error, "quota")
+		verifyQuota(ctx, t, client, 2, 10)
+
+		// Removing the budget lifts the limit.
+		_, err = client.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.UpdateOrganizationQuotaRequest{})
+		require.NoError(t, err)
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build = coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		verifyQuota(ctx, t, client, 4, 10)
+	})
+
+	t.Run("OrganizationBudgetMemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdenttest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err := member.OrganizationQuota(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		_, err = member.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.UpdateOrganizationQuotaRequest{
+			Budget: 1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+// addQuotaAllowance adds the user to a new group with the given allowance.
+func addQuotaAllowance(ctx context.Context, t *testing.T, client *codersdk.Client, user codersdk.CreateFirstUserResponse, allowance int) {
+	t.Helper()
+
+	group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+		Name:           "quota",
+		QuotaAllowance: allowance,
+	})
+	require.NoError(t, err)
+	_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+		AddUsers: []string{user.UserID.String()},
+	})
+	require.NoError(t, err)
+}
+
+// createCostlyTemplate creates a template whose workspaces have the given
+// daily cost.
+func createCostlyTemplate(t *testing.T, client *codersdk.Client, organizationID uuid.UUID, dailyCost int32) codersdk.Template {
+	t.Helper()
+
+	version := coderdtest.CreateTemplateVersion(t, client, organizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name:      "example",
+						Type:      "aws_instance",
+						DailyCost: dailyCost,
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	return coderdtest.CreateTemplate(t, client, organizationID, version.ID)
 }
@@ -8,11 +8,18 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/provisionersdk/proto"
 	"github.com/coder/coder/testutil"
 )
 
@@ -70,3 +77,270 @@ func TestCreateWorkspace(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestWorkspaceAutobuild(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DormantAfterInactivity", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan executor.Stats)
+		)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.InactivityTTLMillis = ptr.Ref(time.Hour.Milliseconds())
+		})
+		require.Equal(t, time.Hour.Milliseconds(), template.InactivityTTLMillis)
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		// When: the workspace has not been used for longer than the inactivity TTL.
+		tick := time.Now().Add(2 * time.Hour)
+		go func() {
+			tickCh <- tick
+			close(tickCh)
+		}()
+
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.Transitions, 1)
+		require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.NotNil(t, workspace.DormantAt)
+		// The workspace is dormant from the tick that noticed it was inactive.
+		require.WithinDuration(t, tick, *workspace.DormantAt, time.Millisecond)
+		require.Nil(t, workspace.DeletingAt)
+		require.Equal(t, codersdk.BuildReasonDormancy, workspace.LatestBuild.Reason)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Dormant workspaces cannot be started.
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "dormant:true"})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 1)
+		require.Equal(t, workspace.ID, res.Workspaces[0].ID)
+
+		// Making it active again allows it to be started.
+		err = client.UpdateWorkspaceDormancy(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancy{
+			Dormant: false,
+		})
+		require.NoError(t, err)
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.Nil(t, workspace.DormantAt)
+
+		res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "dormant:true"})
+		require.NoError(t, err)
+		require.Empty(t, res.Workspaces)
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+	})
+
+	t.Run("DormantAutoDelete", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan executor.Stats)
+		)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.InactivityTTLMillis = ptr.Ref(time.Hour.Milliseconds())
+			ctr.DormantAutoDeleteTTLMillis = ptr.Ref((24 * time.Hour).Milliseconds())
+		})
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		tickCh <- time.Now().Add(2 * time.Hour)
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.NotNil(t, workspace.DormantAt)
+		require.NotNil(t, workspace.DeletingAt)
+		require.Equal(t, workspace.DormantAt.Add(24*time.Hour), *workspace.DeletingAt)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		// Nothing happens before the autodelete TTL has elapsed.
+		tickCh <- time.Now().Add(3 * time.Hour)
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.Transitions)
+
+		tickCh <- workspace.DeletingAt.Add(time.Minute)
+		close(tickCh)
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.Transitions, 1)
+		require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.Equal(t, codersdk.BuildReasonAutodelete, workspace.LatestBuild.Reason)
+		require.Equal(t, codersdk.WorkspaceTransitionDelete, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("FailureTTLOK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan executor.Stats)
+			// The failing build logs errors, so don't fail the test on them.
+			logger = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+		)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Logger:                   &logger,
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.ProvisionComplete,
+			ProvisionApply: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Error: "test error",
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.FailureTTLMillis = ptr.Ref(time.Hour.Milliseconds())
+		})
+		require.Equal(t, time.Hour.Milliseconds(), template.FailureTTLMillis)
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+
+		// When: the start build failed longer ago than the failure TTL.
+		go func() {
+			tickCh <- build.Job.CompletedAt.Add(2 * time.Hour)
+			close(tickCh)
+		}()
+
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.Transitions, 1)
+		require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.Equal(t, codersdk.BuildReasonFailedStop, workspace.LatestBuild.Reason)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("FailureTTLTooEarly", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan executor.Stats)
+			// The failing build logs errors, so don't fail the test on them.
+			logger = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+		)
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Logger:                   &logger,
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.ProvisionComplete,
+			ProvisionApply: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Error: "test error",
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.FailureTTLMillis = ptr.Ref(time.Hour.Milliseconds())
+		})
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+
+		// When: the failure TTL has not yet elapsed.
+		go func() {
+			tickCh <- build.Job.CompletedAt.Add(10 * time.Minute)
+			close(tickCh)
+		}()
+
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.Transitions)
+	})
+}
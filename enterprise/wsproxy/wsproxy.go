@@ -3,6 +3,7 @@ package wsproxy
 import (
 	"context"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
 	"reflect"
 	"regexp"
@@ -105,6 +106,8 @@ type Server struct {
 	// Used for graceful shutdown. Required for the dialer.
 	ctx    context.Context
 	cancel context.CancelFunc
+	// heartbeatDone is closed once the proxy stopped registering with coderd.
+	heartbeatDone chan struct{}
 }
 
 func New(opts *Options) (*Server, error) {
@@ -116,7 +119,6 @@ func New(opts *Options) (*Server, error) {
 		return nil, err
 	}
 
-	// TODO: implement some ping and registration logic
 	client := wsproxysdk.New(opts.DashboardURL)
 	err := client.SetSessionToken(opts.ProxySessionToken)
 	if err != nil {
@@ -135,8 +137,18 @@ func New(opts *Options) (*Server, error) {
 		SDKClient:          client,
 		ctx:                ctx,
 		cancel:             cancel,
+		heartbeatDone:      make(chan struct{}),
 	}
 
+	// Register before serving anything, so a proxy with an invalid token
+	// fails to start.
+	registration, err := s.register(ctx)
+	if err != nil {
+		cancel()
+		return nil, xerrors.Errorf("register proxy: %w", err)
+	}
+	go s.heartbeatLoop(registration.HeartbeatInterval)
+
 	s.AppServer = &workspaceapps.Server{
 		Logger:        opts.Logger.Named("workspaceapps"),
 		DashboardURL:  opts.DashboardURL,
@@ -203,6 +215,15 @@ func New(opts *Options) (*Server, error) {
 		s.AppServer.Attach(r)
 	})
 
+	r.Route("/derp", func(r chi.Router) {
+		// Clients relay their connections to workspaces through the proxy,
+		// which forwards them to the DERP server of the primary. The latency
+		// check is forwarded too, so it measures the whole relayed path.
+		derpRelay := s.derpRelay()
+		r.Get("/", derpRelay.ServeHTTP)
+		r.Get("/latency-check", derpRelay.ServeHTTP)
+	})
+
 	r.Get("/buildinfo", s.buildInfo)
 	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("OK")) })
 
@@ -211,13 +232,71 @@ func New(opts *Options) (*Server, error) {
 
 func (s *Server) Close() error {
 	s.cancel()
+	<-s.heartbeatDone
 	return s.AppServer.Close()
 }
 
+func (s *Server) register(ctx context.Context) (wsproxysdk.RegisterWorkspaceProxyResponse, error) {
+	return s.SDKClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{
+		Version: buildinfo.Version(),
+	})
+}
+
+// heartbeatLoop registers the proxy with coderd until the server is closed.
+// coderd considers the proxy unhealthy once it stops registering.
+func (s *Server) heartbeatLoop(interval time.Duration) {
+	defer close(s.heartbeatDone)
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		registration, err := s.register(s.ctx)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			s.Logger.Warn(s.ctx, "register with coderd", slog.Error(err))
+			continue
+		}
+		if registration.HeartbeatInterval > 0 && registration.HeartbeatInterval != interval {
+			interval = registration.HeartbeatInterval
+			ticker.Reset(interval)
+		}
+	}
+}
+
 func (s *Server) DialWorkspaceAgent(id uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
 	return s.SDKClient.DialWorkspaceAgent(s.ctx, id, nil)
 }
 
+// derpRelay forwards DERP connections to the primary. The connections are
+// upgraded, so they stay open for as long as the client is connected.
+func (s *Server) derpRelay() http.Handler {
+	relay := httputil.NewSingleHostReverseProxy(s.DashboardURL)
+	director := relay.Director
+	relay.Director = func(r *http.Request) {
+		director(r)
+		r.Host = s.DashboardURL.Host
+	}
+	relay.ErrorHandler = func(rw http.ResponseWriter, r *http.Request, err error) {
+		s.Logger.Debug(r.Context(), "relay derp connection", slog.Error(err))
+		httpapi.Write(r.Context(), rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to relay the connection to the primary.",
+			Detail:  err.Error(),
+		})
+	}
+	return relay
+}
+
 func (s *Server) buildInfo(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.BuildInfoResponse{
 		ExternalURL:  buildinfo.ExternalURL(),
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -142,3 +143,30 @@ func (c *Client) IssueSignedAppTokenHTML(ctx context.Context, rw http.ResponseWr
 	}
 	return res, true
 }
+
+type RegisterWorkspaceProxyRequest struct {
+	// Version is the version of the proxy.
+	Version string `json:"version"`
+}
+
+type RegisterWorkspaceProxyResponse struct {
+	// HeartbeatInterval is how often the proxy must register again. The proxy
+	// is unhealthy if it stops registering.
+	HeartbeatInterval time.Duration `json:"heartbeat_interval"`
+}
+
+// RegisterWorkspaceProxy registers the proxy with coderd. Proxies register
+// periodically to report that they're running.
+func (c *Client) RegisterWorkspaceProxy(ctx context.Context, req RegisterWorkspaceProxyRequest) (RegisterWorkspaceProxyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaceproxies/me/register", req)
+	if err != nil {
+		return RegisterWorkspaceProxyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RegisterWorkspaceProxyResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp RegisterWorkspaceProxyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
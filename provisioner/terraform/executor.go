@@ -13,6 +13,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	tfjson "github.com/hashicorp/terraform-json"
@@ -210,7 +211,8 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		args = append(args, "-var", variable)
 	}
 
-	outWriter, doneOut := provisionLogWriter(logr)
+	resourceTimings := newTimingAggregator(timingStagePlan)
+	outWriter, doneOut := provisionLogWriter(logr, resourceTimings)
 	errWriter, doneErr := logWriter(logr, proto.LogLevel_ERROR)
 	defer func() {
 		_ = outWriter.Close()
@@ -219,14 +221,23 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		<-doneErr
 	}()
 
+	start := time.Now()
 	err := e.execWriteOutput(ctx, killCtx, args, env, outWriter, errWriter)
 	if err != nil {
 		return nil, xerrors.Errorf("terraform plan: %w", err)
 	}
+	// Wait for the whole log stream to be read, so no resource timings are
+	// missed.
+	_ = outWriter.Close()
+	<-doneOut
+	timings := append([]*proto.Timing{stageTiming(timingStagePlan, start, time.Now())}, resourceTimings.aggregate()...)
+
+	start = time.Now()
 	state, err := e.planResources(ctx, killCtx, planfilePath)
 	if err != nil {
 		return nil, err
 	}
+	timings = append(timings, stageTiming(timingStageGraph, start, time.Now()))
 	planFileByt, err := os.ReadFile(planfilePath)
 	if err != nil {
 		return nil, err
@@ -238,6 +249,7 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 				Resources:        state.Resources,
 				GitAuthProviders: state.GitAuthProviders,
 				Plan:             planFileByt,
+				Timings:          timings,
 			},
 		},
 	}, nil
@@ -330,7 +342,8 @@ func (e *executor) apply(
 		planFile.Name(),
 	}
 
-	outWriter, doneOut := provisionLogWriter(logr)
+	resourceTimings := newTimingAggregator(timingStageApply)
+	outWriter, doneOut := provisionLogWriter(logr, resourceTimings)
 	errWriter, doneErr := logWriter(logr, proto.LogLevel_ERROR)
 	defer func() {
 		_ = outWriter.Close()
@@ -339,14 +352,23 @@ func (e *executor) apply(
 		<-doneErr
 	}()
 
+	start := time.Now()
 	err = e.execWriteOutput(ctx, killCtx, args, env, outWriter, errWriter)
 	if err != nil {
 		return nil, xerrors.Errorf("terraform apply: %w", err)
 	}
+	// Wait for the whole log stream to be read, so no resource timings are
+	// missed.
+	_ = outWriter.Close()
+	<-doneOut
+	timings := append([]*proto.Timing{stageTiming(timingStageApply, start, time.Now())}, resourceTimings.aggregate()...)
+
+	start = time.Now()
 	state, err := e.stateResources(ctx, killCtx)
 	if err != nil {
 		return nil, err
 	}
+	timings = append(timings, stageTiming(timingStageGraph, start, time.Now()))
 	statefilePath := filepath.Join(e.workdir, "terraform.tfstate")
 	stateContent, err := os.ReadFile(statefilePath)
 	if err != nil {
@@ -359,6 +381,7 @@ func (e *executor) apply(
 				Resources:        state.Resources,
 				GitAuthProviders: state.GitAuthProviders,
 				State:            stateContent,
+				Timings:          timings,
 			},
 		},
 	}, nil
@@ -488,17 +511,18 @@ func readAndLog(sink logSink, r io.Reader, done chan<- any, level proto.LogLevel
 	}
 }
 
-// provisionLogWriter creates a WriteCloser that will log each JSON formatted terraform log.  The WriteCloser must be
-// closed by the caller to end logging, after which the returned channel will be closed to indicate that logging of the
-// written data has finished.  Failure to close the WriteCloser will leak a goroutine.
-func provisionLogWriter(sink logSink) (io.WriteCloser, <-chan any) {
+// provisionLogWriter creates a WriteCloser that will log each JSON formatted terraform log, and record the resource
+// timings in it to timings.  The WriteCloser must be closed by the caller to end logging, after which the returned
+// channel will be closed to indicate that logging of the written data has finished.  Failure to close the WriteCloser
+// will leak a goroutine.
+func provisionLogWriter(sink logSink, timings *timingAggregator) (io.WriteCloser, <-chan any) {
 	r, w := io.Pipe()
 	done := make(chan any)
-	go provisionReadAndLog(sink, r, done)
+	go provisionReadAndLog(sink, timings, r, done)
 	return w, done
 }
 
-func provisionReadAndLog(sink logSink, r io.Reader, done chan<- any) {
+func provisionReadAndLog(sink logSink, timings *timingAggregator, r io.Reader, done chan<- any) {
 	defer close(done)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -527,6 +551,7 @@ func provisionReadAndLog(sink logSink, r io.Reader, done chan<- any) {
 			log.Level = "info"
 			log.Message = scanner.Text()
 		}
+		timings.ingest(log)
 
 		logLevel := convertTerraformLogLevel(log.Level, sink)
 		sink.Log(&proto.Log{Level: logLevel, Output: log.Message})
@@ -564,10 +589,23 @@ func convertTerraformLogLevel(logLevel string, sink logSink) proto.LogLevel {
 }
 
 type terraformProvisionLog struct {
-	Level   string `json:"@level"`
-	Message string `json:"@message"`
+	Level     string `json:"@level"`
+	Message   string `json:"@message"`
+	Timestamp string `json:"@timestamp"`
+	Type      string `json:"type"`
+
+	Diagnostic *tfjson.Diagnostic         `json:"diagnostic,omitempty"`
+	Hook       *terraformProvisionLogHook `json:"hook,omitempty"`
+}
 
-	Diagnostic *tfjson.Diagnostic `json:"diagnostic,omitempty"`
+// terraformProvisionLogHook is the hook of log entries that report the
+// progress of an operation on a resource.
+type terraformProvisionLogHook struct {
+	Action   string `json:"action"`
+	Resource struct {
+		Addr            string `json:"addr"`
+		ImpliedProvider string `json:"implied_provider"`
+	} `json:"resource"`
 }
 
 // syncWriter wraps an io.Writer in a sync.Mutex.
@@ -122,6 +122,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 	}
 
 	s.logger.Debug(ctx, "running initialization")
+	initStart := time.Now()
 	err = e.init(ctx, killCtx, sink)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -136,6 +137,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 		return xerrors.Errorf("initialize terraform: %w", err)
 	}
 	s.logger.Debug(ctx, "ran initialization")
+	initTiming := stageTiming(timingStageInit, initStart, time.Now())
 	env, err := provisionEnv(config, request.GetPlan().GetParameterValues(), request.GetPlan().GetRichParameterValues(), request.GetPlan().GetGitAuthProviders())
 	if err != nil {
 		return err
@@ -164,6 +166,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 			}
 			return xerrors.Errorf("plan terraform: %w", err)
 		}
+		resp.GetComplete().Timings = append([]*proto.Timing{initTiming}, resp.GetComplete().Timings...)
 		return stream.Send(resp)
 	}
 	// Must be apply
@@ -184,6 +187,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 			},
 		})
 	}
+	resp.GetComplete().Timings = append([]*proto.Timing{initTiming}, resp.GetComplete().Timings...)
 	return stream.Send(resp)
 }
 
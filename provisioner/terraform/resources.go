@@ -60,11 +60,30 @@ type agentAppAttributes struct {
 	Healthcheck []appHealthcheckAttributes `mapstructure:"healthcheck"`
 }
 
+// A mapping of attributes on the "coder_script" resource.
+type agentScriptAttributes struct {
+	AgentID          string `mapstructure:"agent_id"`
+	DisplayName      string `mapstructure:"display_name"`
+	Script           string `mapstructure:"script"`
+	Cron             string `mapstructure:"cron"`
+	RunOrder         int32  `mapstructure:"run_order"`
+	StartBlocksLogin bool   `mapstructure:"start_blocks_login"`
+	RunOnStart       bool   `mapstructure:"run_on_start"`
+	RunOnStop        bool   `mapstructure:"run_on_stop"`
+	TimeoutSeconds   int32  `mapstructure:"timeout"`
+	LogPath          string `mapstructure:"log_path"`
+}
+
 // A mapping of attributes on the "healthcheck" resource.
 type appHealthcheckAttributes struct {
-	URL       string `mapstructure:"url"`
-	Interval  int32  `mapstructure:"interval"`
-	Threshold int32  `mapstructure:"threshold"`
+	Type              string  `mapstructure:"type"`
+	URL               string  `mapstructure:"url"`
+	Address           string  `mapstructure:"address"`
+	Command           string  `mapstructure:"command"`
+	ExpectedExitCodes []int32 `mapstructure:"expected_exit_codes"`
+	OutputRegex       string  `mapstructure:"output_regex"`
+	Interval          int32   `mapstructure:"interval"`
+	Threshold         int32   `mapstructure:"threshold"`
 }
 
 // A mapping of attributes on the "coder_metadata" resource.
@@ -326,10 +345,20 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string, rawParameterNa
 
 			var healthcheck *proto.Healthcheck
 			if len(attrs.Healthcheck) != 0 {
+				attrsHealthcheck := attrs.Healthcheck[0]
+				healthcheckType, err := convertHealthcheckType(attrsHealthcheck)
+				if err != nil {
+					return nil, xerrors.Errorf("app %q: %w", attrs.Slug, err)
+				}
 				healthcheck = &proto.Healthcheck{
-					Url:       attrs.Healthcheck[0].URL,
-					Interval:  attrs.Healthcheck[0].Interval,
-					Threshold: attrs.Healthcheck[0].Threshold,
+					Type:              healthcheckType,
+					Url:               attrsHealthcheck.URL,
+					Address:           attrsHealthcheck.Address,
+					Command:           attrsHealthcheck.Command,
+					ExpectedExitCodes: attrsHealthcheck.ExpectedExitCodes,
+					OutputRegex:       attrsHealthcheck.OutputRegex,
+					Interval:          attrsHealthcheck.Interval,
+					Threshold:         attrsHealthcheck.Threshold,
 				}
 			}
 
@@ -365,6 +394,43 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string, rawParameterNa
 		}
 	}
 
+	// Associate scripts with agents.
+	for _, resources := range tfResourcesByLabel {
+		for _, resource := range resources {
+			if resource.Type != "coder_script" {
+				continue
+			}
+
+			var attrs agentScriptAttributes
+			err = mapstructure.Decode(resource.AttributeValues, &attrs)
+			if err != nil {
+				return nil, xerrors.Errorf("decode script attributes: %w", err)
+			}
+			if attrs.DisplayName == "" {
+				attrs.DisplayName = resource.Name
+			}
+
+			for _, agents := range resourceAgents {
+				for _, agent := range agents {
+					if agent.Id != attrs.AgentID {
+						continue
+					}
+					agent.Scripts = append(agent.Scripts, &proto.Script{
+						DisplayName:      attrs.DisplayName,
+						Script:           attrs.Script,
+						Cron:             attrs.Cron,
+						RunOrder:         attrs.RunOrder,
+						StartBlocksLogin: attrs.StartBlocksLogin,
+						RunOnStart:       attrs.RunOnStart,
+						RunOnStop:        attrs.RunOnStop,
+						TimeoutSeconds:   attrs.TimeoutSeconds,
+						LogPath:          attrs.LogPath,
+					})
+				}
+			}
+		}
+	}
+
 	// Associate metadata blocks with resources.
 	resourceMetadata := map[string][]*proto.Resource_Metadata{}
 	resourceHidden := map[string]bool{}
@@ -439,7 +505,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string, rawParameterNa
 			if resource.Mode == tfjson.DataResourceMode {
 				continue
 			}
-			if resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" {
+			if resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" || resource.Type == "coder_script" {
 				continue
 			}
 			label := convertAddressToLabel(resource.Address)
@@ -664,3 +730,26 @@ func orderedRichParametersResources(tfResourcesRichParameters []*tfjson.StateRes
 	}
 	return ordered
 }
+
+// convertHealthcheckType returns the type of an app healthcheck. Older
+// providers don't set a type, in which case it's inferred from the attribute
+// that is set.
+func convertHealthcheckType(healthcheck appHealthcheckAttributes) (proto.HealthcheckType, error) {
+	switch strings.ToLower(healthcheck.Type) {
+	case "":
+		switch {
+		case healthcheck.Command != "":
+			return proto.HealthcheckType_COMMAND, nil
+		case healthcheck.Address != "" && healthcheck.URL == "":
+			return proto.HealthcheckType_TCP, nil
+		}
+		return proto.HealthcheckType_HTTP, nil
+	case "http":
+		return proto.HealthcheckType_HTTP, nil
+	case "tcp":
+		return proto.HealthcheckType_TCP, nil
+	case "command":
+		return proto.HealthcheckType_COMMAND, nil
+	}
+	return 0, xerrors.Errorf("unknown healthcheck type %q", healthcheck.Type)
+}
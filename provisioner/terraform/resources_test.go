@@ -176,6 +176,44 @@ func TestConvertResources(t *testing.T) {
 				}},
 			}},
 		},
+		// Scripts are associated with the agent they reference.
+		"agent-scripts": {
+			resources: []*proto.Resource{{
+				Name: "dev",
+				Type: "null_resource",
+				Agents: []*proto.Agent{{
+					Name:            "main",
+					OperatingSystem: "linux",
+					Architecture:    "amd64",
+					Scripts: []*proto.Script{
+						{
+							DisplayName: "Backup",
+							Script:      "tar -czf /tmp/backup.tar.gz ~/project",
+							Cron:        "0 * * * *",
+							LogPath:     "backup.log",
+						},
+						{
+							DisplayName:      "Install dependencies",
+							Script:           "npm ci",
+							RunOnStart:       true,
+							StartBlocksLogin: true,
+							TimeoutSeconds:   600,
+						},
+						{
+							// Defaults to the name of the resource.
+							DisplayName: "cleanup",
+							Script:      "rm -rf /tmp/cache",
+							RunOnStop:   true,
+						},
+					},
+					Auth:                         &proto.Agent_Token{},
+					LoginBeforeReady:             true,
+					ConnectionTimeoutSeconds:     120,
+					StartupScriptTimeoutSeconds:  300,
+					ShutdownScriptTimeoutSeconds: 300,
+				}},
+			}},
+		},
 		"mapped-apps": {
 			resources: []*proto.Resource{{
 				Name: "dev",
@@ -498,6 +536,98 @@ func TestAppSlugValidation(t *testing.T) {
 	require.ErrorContains(t, err, "duplicate app slug")
 }
 
+func TestAppHealthcheckTypes(t *testing.T) {
+	t.Parallel()
+
+	// nolint:dogsled
+	_, filename, _, _ := runtime.Caller(0)
+
+	dir := filepath.Join(filepath.Dir(filename), "testdata", "multiple-apps")
+	tfPlanGraph, err := os.ReadFile(filepath.Join(dir, "multiple-apps.tfplan.dot"))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		Name        string
+		Healthcheck map[string]interface{}
+		Expected    *proto.Healthcheck
+		Error       string
+	}{{
+		Name: "InferredTCP",
+		Healthcheck: map[string]interface{}{
+			"address":   "localhost:5432",
+			"interval":  float64(5),
+			"threshold": float64(6),
+		},
+		Expected: &proto.Healthcheck{
+			Type:      proto.HealthcheckType_TCP,
+			Address:   "localhost:5432",
+			Interval:  5,
+			Threshold: 6,
+		},
+	}, {
+		Name: "Command",
+		Healthcheck: map[string]interface{}{
+			"type":                "command",
+			"command":             "pg_isready",
+			"expected_exit_codes": []interface{}{float64(0), float64(2)},
+			"output_regex":        "accepting connections",
+			"interval":            float64(5),
+			"threshold":           float64(6),
+		},
+		Expected: &proto.Healthcheck{
+			Type:              proto.HealthcheckType_COMMAND,
+			Command:           "pg_isready",
+			ExpectedExitCodes: []int32{0, 2},
+			OutputRegex:       "accepting connections",
+			Interval:          5,
+			Threshold:         6,
+		},
+	}, {
+		Name: "UnknownType",
+		Healthcheck: map[string]interface{}{
+			"type": "udp",
+		},
+		Error: "unknown healthcheck type",
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			tfPlanRaw, err := os.ReadFile(filepath.Join(dir, "multiple-apps.tfplan.json"))
+			require.NoError(t, err)
+			var tfPlan tfjson.Plan
+			err = json.Unmarshal(tfPlanRaw, &tfPlan)
+			require.NoError(t, err)
+
+			for _, resource := range tfPlan.PlannedValues.RootModule.Resources {
+				if resource.Address == "coder_app.app2" {
+					resource.AttributeValues["healthcheck"] = []interface{}{tc.Healthcheck}
+				}
+			}
+
+			state, err := terraform.ConvertState([]*tfjson.StateModule{tfPlan.PlannedValues.RootModule}, string(tfPlanGraph), nil)
+			if tc.Error != "" {
+				require.ErrorContains(t, err, tc.Error)
+				return
+			}
+			require.NoError(t, err)
+			var found bool
+			for _, resource := range state.Resources {
+				for _, agent := range resource.Agents {
+					for _, app := range agent.Apps {
+						if app.Slug != "app2" {
+							continue
+						}
+						found = true
+						require.True(t, protobuf.Equal(tc.Expected, app.Healthcheck), "got %v", app.Healthcheck)
+					}
+				}
+			}
+			require.True(t, found, "app2 not found")
+		})
+	}
+}
+
 func TestInstanceTypeAssociation(t *testing.T) {
 	t.Parallel()
 	type tc struct {
@@ -636,6 +766,9 @@ func sortResources(resources []*proto.Resource) {
 			sort.Slice(agent.Apps, func(i, j int) bool {
 				return agent.Apps[i].Slug < agent.Apps[j].Slug
 			})
+			sort.Slice(agent.Scripts, func(i, j int) bool {
+				return agent.Scripts[i].DisplayName < agent.Scripts[j].DisplayName
+			})
 		}
 		sort.Slice(resource.Agents, func(i, j int) bool {
 			return resource.Agents[i].Name < resource.Agents[j].Name
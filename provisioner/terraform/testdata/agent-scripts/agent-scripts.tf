@@ -0,0 +1,43 @@
+terraform {
+  required_providers {
+    coder = {
+      source  = "coder/coder"
+      version = "0.12.0"
+    }
+  }
+}
+
+resource "coder_agent" "main" {
+  os   = "linux"
+  arch = "amd64"
+}
+
+resource "coder_script" "install" {
+  agent_id           = coder_agent.main.id
+  display_name       = "Install dependencies"
+  script             = "npm ci"
+  run_on_start       = true
+  start_blocks_login = true
+  timeout            = 600
+}
+
+resource "coder_script" "backup" {
+  agent_id     = coder_agent.main.id
+  display_name = "Backup"
+  script       = "tar -czf /tmp/backup.tar.gz ~/project"
+  cron         = "0 * * * *"
+  log_path     = "backup.log"
+}
+
+# The display name defaults to the name of the resource.
+resource "coder_script" "cleanup" {
+  agent_id    = coder_agent.main.id
+  script      = "rm -rf /tmp/cache"
+  run_on_stop = true
+}
+
+resource "null_resource" "dev" {
+  depends_on = [
+    coder_agent.main
+  ]
+}
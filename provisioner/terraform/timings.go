@@ -0,0 +1,95 @@
+package terraform
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/coder/coder/provisionersdk/proto"
+)
+
+// Stages of a provision that timings are recorded for.
+const (
+	timingStageInit  = "init"
+	timingStagePlan  = "plan"
+	timingStageGraph = "graph"
+	timingStageApply = "apply"
+)
+
+// timingSourceTerraform is the source of the timings that cover a whole stage.
+const timingSourceTerraform = "terraform"
+
+// stageTiming returns the timing of a whole stage.
+func stageTiming(stage string, start, end time.Time) *proto.Timing {
+	return &proto.Timing{
+		Start:  timestamppb.New(start),
+		End:    timestamppb.New(end),
+		Stage:  stage,
+		Source: timingSourceTerraform,
+	}
+}
+
+// timingAggregator collects the timings of the resources that terraform
+// reports in its JSON log stream during a stage.
+type timingAggregator struct {
+	stage string
+
+	mut     sync.Mutex
+	started map[string]*proto.Timing
+	timings []*proto.Timing
+}
+
+func newTimingAggregator(stage string) *timingAggregator {
+	return &timingAggregator{
+		stage:   stage,
+		started: make(map[string]*proto.Timing),
+	}
+}
+
+// ingest records the start or the end of a resource operation. Log entries
+// for anything else are ignored.
+func (t *timingAggregator) ingest(log terraformProvisionLog) {
+	if log.Hook == nil || log.Hook.Resource.Addr == "" {
+		return
+	}
+	ts, err := time.Parse(time.RFC3339Nano, log.Timestamp)
+	if err != nil {
+		return
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	addr := log.Hook.Resource.Addr
+	switch log.Type {
+	case "apply_start", "refresh_start":
+		action := log.Hook.Action
+		if log.Type == "refresh_start" {
+			action = "read"
+		}
+		t.started[addr] = &proto.Timing{
+			Start:    timestamppb.New(ts),
+			Stage:    t.stage,
+			Source:   log.Hook.Resource.ImpliedProvider,
+			Action:   action,
+			Resource: addr,
+		}
+	case "apply_complete", "apply_errored", "refresh_complete":
+		timing, ok := t.started[addr]
+		if !ok {
+			return
+		}
+		delete(t.started, addr)
+		timing.End = timestamppb.New(ts)
+		t.timings = append(t.timings, timing)
+	}
+}
+
+// aggregate returns the timings of the resource operations that completed,
+// in the order they completed.
+func (t *timingAggregator) aggregate() []*proto.Timing {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	return append([]*proto.Timing{}, t.timings...)
+}
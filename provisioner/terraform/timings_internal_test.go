@@ -0,0 +1,48 @@
+package terraform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/provisionersdk/proto"
+)
+
+func TestProvisionLogWriter_Timings(t *testing.T) {
+	t.Parallel()
+
+	logr := &mockLogger{}
+	timings := newTimingAggregator(timingStageApply)
+	writer, doneLogging := provisionLogWriter(logr, timings)
+
+	_, err := writer.Write([]byte(`{"@level":"info","@message":"docker_image.main: Creating...","@timestamp":"2023-04-18T10:00:00.000000Z","hook":{"resource":{"addr":"docker_image.main","implied_provider":"docker"},"action":"create"},"type":"apply_start"}
+{"@level":"info","@message":"coder_agent.main: Creating...","@timestamp":"2023-04-18T10:00:00.500000Z","hook":{"resource":{"addr":"coder_agent.main","implied_provider":"coder"},"action":"create"},"type":"apply_start"}
+{"@level":"info","@message":"coder_agent.main: Creation complete after 0s","@timestamp":"2023-04-18T10:00:01.000000Z","hook":{"resource":{"addr":"coder_agent.main","implied_provider":"coder"},"action":"create","elapsed_seconds":0},"type":"apply_complete"}
+Releasing state lock. This may take a few moments...
+{"@level":"info","@message":"docker_image.main: Creation complete after 5s","@timestamp":"2023-04-18T10:00:05.000000Z","hook":{"resource":{"addr":"docker_image.main","implied_provider":"docker"},"action":"create","elapsed_seconds":5},"type":"apply_complete"}
+{"@level":"info","@message":"docker_container.main: Creating...","@timestamp":"2023-04-18T10:00:05.000000Z","hook":{"resource":{"addr":"docker_container.main","implied_provider":"docker"},"action":"create"},"type":"apply_start"}
+`))
+	require.NoError(t, err)
+	err = writer.Close()
+	require.NoError(t, err)
+	<-doneLogging
+
+	// The resource that never completed has no timing.
+	got := timings.aggregate()
+	require.Len(t, got, 2)
+
+	require.Equal(t, "coder_agent.main", got[0].Resource)
+	require.Equal(t, "coder", got[0].Source)
+	require.Equal(t, "create", got[0].Action)
+	require.Equal(t, timingStageApply, got[0].Stage)
+	require.Equal(t, 500*time.Millisecond, got[0].End.AsTime().Sub(got[0].Start.AsTime()))
+
+	require.Equal(t, "docker_image.main", got[1].Resource)
+	require.Equal(t, "docker", got[1].Source)
+	require.Equal(t, 5*time.Second, got[1].End.AsTime().Sub(got[1].Start.AsTime()))
+
+	// Timings don't affect the logs.
+	require.Len(t, logr.logs, 6)
+	require.Equal(t, &proto.Log{Level: proto.LogLevel_INFO, Output: "Releasing state lock. This may take a few moments..."}, logr.logs[3])
+}
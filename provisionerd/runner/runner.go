@@ -994,11 +994,18 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 		})
 		return r.failedJobf("commit quota: %+v", err)
 	}
-	for _, line := range []string{
+	lines := []string{
 		fmt.Sprintf("Build cost       —   %v", cost),
+	}
+	if resp.DailyCost != int32(cost) {
+		// The template multiplies the cost of its workspaces.
+		lines = append(lines, fmt.Sprintf("Adjusted cost    —   %v", resp.DailyCost))
+	}
+	lines = append(lines,
 		fmt.Sprintf("Budget           —   %v", resp.Budget),
 		fmt.Sprintf("Credits consumed —   %v", resp.CreditsConsumed),
-	} {
+	)
+	for _, line := range lines {
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_INFO,
@@ -1013,7 +1020,7 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_WARN,
 			CreatedAt: time.Now().UnixMilli(),
-			Output:    "This build would exceed your quota. Failing.",
+			Output:    "This build would exceed your quota or the budget of your organization. Failing.",
 			Stage:     stage,
 		})
 		return r.failedJobf("insufficient quota")
@@ -1097,6 +1104,7 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 			WorkspaceBuild: &proto.CompletedJob_WorkspaceBuild{
 				State:     completedApply.GetState(),
 				Resources: completedApply.GetResources(),
+				Timings:   append(completedPlan.GetTimings(), completedApply.GetTimings()...),
 			},
 		},
 	}, nil
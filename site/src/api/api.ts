@@ -212,9 +212,13 @@ export const getTemplate = async (
 
 export const getTemplates = async (
   organizationId: string,
+  params?: TypesGen.TemplateFilter,
 ): Promise<TypesGen.Template[]> => {
   const response = await axios.get<TypesGen.Template[]>(
     `/api/v2/organizations/${organizationId}/templates`,
+    {
+      params,
+    },
   )
   return response.data
 }
@@ -867,6 +871,15 @@ export const getAgentListeningPorts = async (
   return response.data
 }
 
+export const getAgentReconnectingPTYs = async (
+  agentID: string,
+): Promise<TypesGen.WorkspaceAgentReconnectingPTYsResponse> => {
+  const response = await axios.get(
+    `/api/v2/workspaceagents/${agentID}/reconnecting-ptys`,
+  )
+  return response.data
+}
+
 // getDeploymentSSHConfig is used by the VSCode-Extension.
 export const getDeploymentSSHConfig =
   async (): Promise<TypesGen.SSHConfigResponse> => {
@@ -31,6 +31,15 @@ export interface AgentStatsReportResponse {
   readonly tx_bytes: number
 }
 
+// From codersdk/workspacebuilds.go
+export interface AgentTiming {
+  readonly workspace_agent_id: string
+  readonly workspace_agent_name: string
+  readonly started_at: string
+  readonly ended_at: string
+  readonly stage: WorkspaceBuildTimingStage
+}
+
 // From codersdk/deployment.go
 export interface AppHostResponse {
   readonly host: string
@@ -146,6 +155,15 @@ export interface ComputedParameter extends Parameter {
   readonly default_source_value: boolean
 }
 
+// From codersdk/roles.go
+export interface CreateCustomRoleRequest {
+  readonly name: string
+  readonly display_name: string
+  readonly site_permissions: Permission[]
+  readonly organization_permissions: Permission[]
+  readonly user_permissions: Permission[]
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string
@@ -181,6 +199,18 @@ export interface CreateParameterRequest {
   readonly destination_scheme: ParameterDestinationScheme
 }
 
+// From codersdk/provisionerkeys.go
+export interface CreateProvisionerKeyRequest {
+  readonly name: string
+  readonly tags: Record<string, string>
+}
+
+// From codersdk/provisionerkeys.go
+export interface CreateProvisionerKeyResponse {
+  readonly provisioner_key: ProvisionerKey
+  readonly key: string
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
   readonly name: string
@@ -191,6 +221,10 @@ export interface CreateTemplateRequest {
   readonly parameter_values?: CreateParameterRequest[]
   readonly default_ttl_ms?: number
   readonly max_ttl_ms?: number
+  readonly inactivity_ttl_ms?: number
+  readonly dormant_autodelete_ttl_ms?: number
+  readonly failure_ttl_ms?: number
+  readonly restart_requirement?: TemplateRestartRequirement
   readonly allow_user_cancel_workspace_jobs?: boolean
   readonly allow_user_autostart?: boolean
   readonly allow_user_autostop?: boolean
@@ -243,6 +277,20 @@ export interface CreateUserRequest {
   readonly organization_id: string
 }
 
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+  readonly name: string
+  readonly url: string
+  readonly events: WebhookEvent[]
+  readonly secret?: string
+}
+
+// From codersdk/webhooks.go
+export interface CreateWebhookResponse {
+  readonly webhook: Webhook
+  readonly secret: string
+}
+
 // From codersdk/workspaces.go
 export interface CreateWorkspaceBuildRequest {
   readonly template_version_id?: string
@@ -278,6 +326,19 @@ export interface CreateWorkspaceRequest {
   readonly ttl_ms?: number
   readonly parameter_values?: CreateParameterRequest[]
   readonly rich_parameter_values?: WorkspaceBuildParameter[]
+  readonly automatic_updates?: AutomaticUpdates
+}
+
+// From codersdk/roles.go
+export interface CustomRole {
+  readonly name: string
+  readonly display_name: string
+  readonly organization_id?: string
+  readonly site_permissions: Permission[]
+  readonly organization_permissions: Permission[]
+  readonly user_permissions: Permission[]
+  readonly created_at: string
+  readonly updated_at: string
 }
 
 // From codersdk/templates.go
@@ -321,6 +382,12 @@ export interface DangerousConfig {
   readonly allow_path_app_site_owner_access: boolean
 }
 
+// From codersdk/workspaceportshare.go
+export interface DeleteWorkspacePortShareRequest {
+  readonly agent_name: string
+  readonly port: number
+}
+
 // From codersdk/deployment.go
 export interface DeploymentDAUsResponse {
   readonly entries: DAUEntry[]
@@ -388,6 +455,8 @@ export interface DeploymentValues {
   readonly config_ssh?: SSHConfig
   readonly wgtunnel_host?: string
   readonly disable_owner_workspace_exec?: boolean
+  readonly webhooks?: WebhooksConfig
+  readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig
   // This is likely an enum in an external package ("github.com/coder/coder/cli/clibase.YAMLConfigPath")
   readonly config?: string
   readonly write_config?: boolean
@@ -461,7 +530,12 @@ export interface Group {
 
 // From codersdk/workspaceapps.go
 export interface Healthcheck {
+  readonly type: WorkspaceAppHealthcheckType
   readonly url: string
+  readonly address?: string
+  readonly command?: string
+  readonly expected_exit_codes?: number[]
+  readonly output_regex?: string
   readonly interval: number
   readonly threshold: number
 }
@@ -567,6 +641,12 @@ export interface OrganizationMember {
   readonly roles: Role[]
 }
 
+// From codersdk/workspaces.go
+export interface OrganizationQuota {
+  readonly credits_consumed: number
+  readonly budget: number
+}
+
 // From codersdk/pagination.go
 export interface Pagination {
   readonly after_id?: string
@@ -621,6 +701,28 @@ export interface PatchTemplateVersionRequest {
   readonly name: string
 }
 
+// From codersdk/workspaceproxy.go
+export interface PatchWorkspaceProxy {
+  readonly name?: string
+  readonly display_name?: string
+  readonly icon?: string
+  readonly url?: string
+  readonly wildcard_hostname?: string
+  readonly regenerate_token?: boolean
+}
+
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean
+  readonly resource_type: RBACResource
+  readonly action: string
+}
+
+// From codersdk/workspacesshcertificate.go
+export interface PostWorkspaceSSHCertificateRequest {
+  readonly public_key: string
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean
@@ -651,9 +753,19 @@ export interface ProvisionerDaemon {
   readonly id: string
   readonly created_at: string
   readonly updated_at?: string
+  readonly last_seen_at?: string
   readonly name: string
+  readonly version: string
   readonly provisioners: ProvisionerType[]
   readonly tags: Record<string, string>
+  readonly key_id?: string
+  readonly current_job?: ProvisionerDaemonJob
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerDaemonJob {
+  readonly id: string
+  readonly status: ProvisionerJobStatus
 }
 
 // From codersdk/provisionerdaemons.go
@@ -681,11 +793,64 @@ export interface ProvisionerJobLog {
   readonly output: string
 }
 
+// From codersdk/provisionerkeys.go
+export interface ProvisionerKey {
+  readonly id: string
+  readonly created_at: string
+  readonly organization_id: string
+  readonly name: string
+  readonly tags: Record<string, string>
+}
+
+// From codersdk/workspacebuilds.go
+export interface ProvisionerTiming {
+  readonly job_id: string
+  readonly started_at: string
+  readonly ended_at: string
+  readonly stage: WorkspaceBuildTimingStage
+  readonly source: string
+  readonly action: string
+  readonly resource: string
+}
+
 // From codersdk/workspaces.go
 export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/insights.go
+export interface QuotaInsightsInterval {
+  readonly start_time: string
+  readonly end_time: string
+  readonly credits_spent: number
+  readonly users: QuotaSpend[]
+  readonly groups: QuotaSpend[]
+  readonly templates: QuotaSpend[]
+}
+
+// From codersdk/insights.go
+export interface QuotaInsightsRequest {
+  readonly start_time: string
+  readonly end_time: string
+  readonly interval: InsightsReportInterval
+}
+
+// From codersdk/insights.go
+export interface QuotaInsightsResponse {
+  readonly start_time: string
+  readonly end_time: string
+  readonly interval: InsightsReportInterval
+  readonly credits_spent: number
+  readonly intervals: QuotaInsightsInterval[]
+}
+
+// From codersdk/insights.go
+export interface QuotaSpend {
+  readonly id: string
+  readonly name: string
+  readonly credits_spent: number
+}
+
 // From codersdk/deployment.go
 export interface RateLimitConfig {
   readonly disable_all: boolean
@@ -805,11 +970,21 @@ export interface Template {
   readonly icon: string
   readonly default_ttl_ms: number
   readonly max_ttl_ms: number
+  readonly inactivity_ttl_ms: number
+  readonly dormant_autodelete_ttl_ms: number
+  readonly failure_ttl_ms: number
   readonly created_by_id: string
   readonly created_by_name: string
+  readonly restart_requirement: TemplateRestartRequirement
   readonly allow_user_autostart: boolean
   readonly allow_user_autostop: boolean
   readonly allow_user_cancel_workspace_jobs: boolean
+  readonly deprecated: boolean
+  readonly deprecation_message: string
+  readonly require_active_version: boolean
+  readonly max_port_share_level: WorkspaceAppSharingLevel
+  readonly session_recording: boolean
+  readonly quota_cost_multiplier: number
 }
 
 // From codersdk/templates.go
@@ -840,11 +1015,22 @@ export interface TemplateExample {
   readonly markdown: string
 }
 
+// From codersdk/organizations.go
+export interface TemplateFilter {
+  readonly include_deprecated: boolean
+}
+
 // From codersdk/templates.go
 export interface TemplateGroup extends Group {
   readonly role: TemplateRole
 }
 
+// From codersdk/templates.go
+export interface TemplateRestartRequirement {
+  readonly days_of_week: string[]
+  readonly weeks: number
+}
+
 // From codersdk/templates.go
 export interface TemplateUser extends User {
   readonly role: TemplateRole
@@ -861,6 +1047,8 @@ export interface TemplateVersion {
   readonly job: ProvisionerJob
   readonly readme: string
   readonly created_by: User
+  readonly promoted_at?: string
+  readonly promoted_by?: User
 }
 
 // From codersdk/templateversions.go
@@ -957,6 +1145,19 @@ export interface UpdateCheckResponse {
   readonly url: string
 }
 
+// From codersdk/roles.go
+export interface UpdateCustomRoleRequest {
+  readonly display_name: string
+  readonly site_permissions: Permission[]
+  readonly organization_permissions: Permission[]
+  readonly user_permissions: Permission[]
+}
+
+// From codersdk/workspaces.go
+export interface UpdateOrganizationQuotaRequest {
+  readonly budget: number
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[]
@@ -976,9 +1177,18 @@ export interface UpdateTemplateMeta {
   readonly icon?: string
   readonly default_ttl_ms?: number
   readonly max_ttl_ms?: number
+  readonly inactivity_ttl_ms?: number
+  readonly dormant_autodelete_ttl_ms?: number
+  readonly failure_ttl_ms?: number
   readonly allow_user_autostart?: boolean
   readonly allow_user_autostop?: boolean
   readonly allow_user_cancel_workspace_jobs?: boolean
+  readonly restart_requirement?: TemplateRestartRequirement
+  readonly deprecation_message?: string
+  readonly require_active_version?: boolean
+  readonly max_port_share_level?: WorkspaceAppSharingLevel
+  readonly session_recording?: boolean
+  readonly quota_cost_multiplier?: number
 }
 
 // From codersdk/users.go
@@ -992,11 +1202,40 @@ export interface UpdateUserProfileRequest {
   readonly username: string
 }
 
+// From codersdk/users.go
+export interface UpdateUserQuietHoursScheduleRequest {
+  readonly schedule: string
+}
+
+// From codersdk/webhooks.go
+export interface UpdateWebhookRequest {
+  readonly name?: string
+  readonly url?: string
+  readonly events?: WebhookEvent[]
+  readonly enabled?: boolean
+}
+
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceAutomaticUpdatesRequest {
+  readonly automatic_updates: AutomaticUpdates
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceDormancy {
+  readonly dormant: boolean
+}
+
+// From codersdk/workspaceproxy.go
+export interface UpdateWorkspaceProxyResponse {
+  readonly proxy: WorkspaceProxy
+  readonly proxy_token?: string
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
   readonly name?: string
@@ -1012,6 +1251,14 @@ export interface UploadResponse {
   readonly hash: string
 }
 
+// From codersdk/workspaceportshare.go
+export interface UpsertWorkspacePortShareRequest {
+  readonly agent_name: string
+  readonly port: number
+  readonly share_level: WorkspaceAppSharingLevel
+  readonly protocol: WorkspacePortShareProtocol
+}
+
 // From codersdk/users.go
 export interface User {
   readonly id: string
@@ -1023,6 +1270,21 @@ export interface User {
   readonly organization_ids: string[]
   readonly roles: Role[]
   readonly avatar_url: string
+  readonly quiet_hours_schedule: string
+}
+
+// From codersdk/deployment.go
+export interface UserQuietHoursScheduleConfig {
+  readonly default_schedule: string
+}
+
+// From codersdk/users.go
+export interface UserQuietHoursScheduleResponse {
+  readonly raw_schedule: string
+  readonly user_set: boolean
+  readonly time: string
+  readonly timezone: string
+  readonly next: string
 }
 
 // From codersdk/users.go
@@ -1048,6 +1310,82 @@ export interface VariableValue {
   readonly value: string
 }
 
+// From codersdk/webhooks.go
+export interface Webhook {
+  readonly id: string
+  readonly name: string
+  readonly url: string
+  readonly events: WebhookEvent[]
+  readonly enabled: boolean
+  readonly created_by: string
+  readonly created_at: string
+  readonly updated_at: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDataTemplateVersionPromoted {
+  readonly organization_id: string
+  readonly template_id: string
+  readonly template_name: string
+  readonly template_version_id: string
+  readonly template_version_name: string
+  readonly previous_template_version_id: string
+  readonly promoted_by: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDataUser {
+  readonly user_id: string
+  readonly username: string
+  readonly email: string
+  readonly status: UserStatus
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDataWorkspaceBuild {
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly workspace_owner_id: string
+  readonly workspace_owner_name: string
+  readonly template_id: string
+  readonly template_name: string
+  readonly template_version_id: string
+  readonly build_id: string
+  readonly build_number: number
+  readonly transition: WorkspaceTransition
+  readonly reason: BuildReason
+  readonly error?: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDelivery {
+  readonly id: string
+  readonly webhook_id: string
+  readonly event: WebhookEvent
+  readonly payload: Record<string, string>
+  readonly created_at: string
+  readonly attempts: number
+  readonly delivered_at?: string
+  readonly next_attempt_at?: string
+  readonly last_status_code: number
+  readonly last_error: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayload {
+  readonly id: string
+  readonly event: WebhookEvent
+  readonly timestamp: string
+  readonly data: Record<string, string>
+}
+
+// From codersdk/deployment.go
+export interface WebhooksConfig {
+  readonly max_attempts: number
+  readonly timeout: number
+  readonly retry_backoff: number
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string
@@ -1067,6 +1405,11 @@ export interface Workspace {
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
   readonly last_used_at: string
+  readonly dormant_at?: string
+  readonly deleting_at?: string
+  readonly template_deprecation_message?: string
+  readonly automatic_updates: AutomaticUpdates
+  readonly template_require_active_version: boolean
 }
 
 // From codersdk/workspaceagents.go
@@ -1092,6 +1435,7 @@ export interface WorkspaceAgent {
   readonly expanded_directory?: string
   readonly version: string
   readonly apps: WorkspaceApp[]
+  readonly scripts: WorkspaceAgentScript[]
   readonly latency?: Record<string, DERPRegion>
   readonly connection_timeout_seconds: number
   readonly troubleshooting_url: string
@@ -1099,6 +1443,36 @@ export interface WorkspaceAgent {
   readonly startup_script_timeout_seconds: number
   readonly shutdown_script?: string
   readonly shutdown_script_timeout_seconds: number
+  readonly parent_id?: string
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentDebugLog {
+  readonly id: number
+  readonly created_at: string
+  readonly output: string
+  readonly level: LogLevel
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentFileHashResponse {
+  readonly length: number
+  readonly sha256: string
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentFileInfo {
+  readonly path: string
+  readonly name: string
+  readonly size: number
+  readonly mode: number
+  readonly mod_time: string
+  readonly is_dir: boolean
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentListFilesResponse {
+  readonly files: WorkspaceAgentFileInfo[]
 }
 
 // From codersdk/workspaceagentconn.go
@@ -1136,12 +1510,45 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTY {
+  readonly id: string
+  readonly name?: string
+  readonly backend: string
+  readonly command: string
+  readonly active_connections: number
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTYsResponse {
+  readonly sessions: WorkspaceAgentReconnectingPTY[]
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentScript {
+  readonly id: string
+  readonly display_name: string
+  readonly script: string
+  readonly cron?: string
+  readonly run_order: number
+  readonly start_blocks_login: boolean
+  readonly run_on_start: boolean
+  readonly run_on_stop: boolean
+  readonly timeout_seconds: number
+  readonly log_path?: string
+  readonly status: WorkspaceAgentScriptStatus
+  readonly exit_code: number
+  readonly started_at?: string
+  readonly completed_at?: string
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentStartupLog {
   readonly id: number
   readonly created_at: string
   readonly output: string
   readonly level: LogLevel
+  readonly script_id: string
 }
 
 // From codersdk/workspaceapps.go
@@ -1189,6 +1596,12 @@ export interface WorkspaceBuildParameter {
   readonly value: string
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildTimings {
+  readonly provisioner_timings: ProvisionerTiming[]
+  readonly agent_timings: AgentTiming[]
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly WorkspaceID: string
@@ -1223,16 +1636,33 @@ export interface WorkspaceOptions {
   readonly include_deleted?: boolean
 }
 
+// From codersdk/workspaceportshare.go
+export interface WorkspacePortShare {
+  readonly workspace_id: string
+  readonly agent_name: string
+  readonly port: number
+  readonly share_level: WorkspaceAppSharingLevel
+  readonly protocol: WorkspacePortShareProtocol
+}
+
+// From codersdk/workspaceportshare.go
+export interface WorkspacePortShares {
+  readonly shares: WorkspacePortShare[]
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy {
   readonly id: string
   readonly name: string
+  readonly display_name: string
   readonly icon: string
   readonly url: string
   readonly wildcard_hostname: string
   readonly created_at: string
   readonly updated_at: string
   readonly deleted: boolean
+  readonly version: string
+  readonly status: WorkspaceProxyStatus
 }
 
 // From codersdk/deployment.go
@@ -1241,6 +1671,14 @@ export interface WorkspaceProxyBuildInfo {
   readonly dashboard_url: string
 }
 
+// From codersdk/workspaceproxy.go
+export interface WorkspaceProxyStatus {
+  readonly status: ProxyHealthStatus
+  readonly error?: string
+  readonly last_heartbeat_at?: string
+  readonly checked_at?: string
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceQuota {
   readonly credits_consumed: number
@@ -1269,6 +1707,12 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean
 }
 
+// From codersdk/workspacesshcertificate.go
+export interface WorkspaceSSHCertificate {
+  readonly certificate: string
+  readonly expires_at: string
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string
@@ -1286,6 +1730,7 @@ export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"]
 
 // From codersdk/audit.go
 export type AuditAction =
+  | "connect"
   | "create"
   | "delete"
   | "login"
@@ -1295,6 +1740,7 @@ export type AuditAction =
   | "stop"
   | "write"
 export const AuditActions: AuditAction[] = [
+  "connect",
   "create",
   "delete",
   "login",
@@ -1305,11 +1751,24 @@ export const AuditActions: AuditAction[] = [
   "write",
 ]
 
+// From codersdk/workspaces.go
+export type AutomaticUpdates = "always" | "never"
+export const AutomaticUpdateses: AutomaticUpdates[] = ["always", "never"]
+
 // From codersdk/workspacebuilds.go
-export type BuildReason = "autostart" | "autostop" | "initiator"
+export type BuildReason =
+  | "autodelete"
+  | "autostart"
+  | "autostop"
+  | "dormancy"
+  | "failedstop"
+  | "initiator"
 export const BuildReasons: BuildReason[] = [
+  "autodelete",
   "autostart",
   "autostop",
+  "dormancy",
+  "failedstop",
   "initiator",
 ]
 
@@ -1361,6 +1820,10 @@ export const GitProviders: GitProvider[] = [
   "gitlab",
 ]
 
+// From codersdk/insights.go
+export type InsightsReportInterval = "day" | "week"
+export const InsightsReportIntervals: InsightsReportInterval[] = ["day", "week"]
+
 // From codersdk/provisionerdaemons.go
 export type JobErrorCode =
   | "MISSING_TEMPLATE_PARAMETER"
@@ -1438,6 +1901,19 @@ export const ProvisionerStorageMethods: ProvisionerStorageMethod[] = ["file"]
 export type ProvisionerType = "echo" | "terraform"
 export const ProvisionerTypes: ProvisionerType[] = ["echo", "terraform"]
 
+// From codersdk/workspaceproxy.go
+export type ProxyHealthStatus =
+  | "ok"
+  | "unreachable"
+  | "unregistered"
+  | "unresponsive"
+export const ProxyHealthStatuses: ProxyHealthStatus[] = [
+  "ok",
+  "unreachable",
+  "unregistered",
+  "unresponsive",
+]
+
 // From codersdk/rbacresources.go
 export type RBACResource =
   | "api_key"
@@ -1445,6 +1921,7 @@ export type RBACResource =
   | "assign_org_role"
   | "assign_role"
   | "audit_log"
+  | "custom_role"
   | "debug_info"
   | "deployment_config"
   | "deployment_stats"
@@ -1459,6 +1936,7 @@ export type RBACResource =
   | "template"
   | "user"
   | "user_data"
+  | "webhook"
   | "workspace"
   | "workspace_execution"
   | "workspace_proxy"
@@ -1468,6 +1946,7 @@ export const RBACResources: RBACResource[] = [
   "assign_org_role",
   "assign_role",
   "audit_log",
+  "custom_role",
   "debug_info",
   "deployment_config",
   "deployment_stats",
@@ -1482,6 +1961,7 @@ export const RBACResources: RBACResource[] = [
   "template",
   "user",
   "user_data",
+  "webhook",
   "workspace",
   "workspace_execution",
   "workspace_proxy",
@@ -1490,22 +1970,28 @@ export const RBACResources: RBACResource[] = [
 // From codersdk/audit.go
 export type ResourceType =
   | "api_key"
+  | "custom_role"
   | "git_ssh_key"
   | "group"
   | "license"
+  | "provisioner_key"
   | "template"
   | "template_version"
   | "user"
+  | "webhook"
   | "workspace"
   | "workspace_build"
 export const ResourceTypes: ResourceType[] = [
   "api_key",
+  "custom_role",
   "git_ssh_key",
   "group",
   "license",
+  "provisioner_key",
   "template",
   "template_version",
   "user",
+  "webhook",
   "workspace",
   "workspace_build",
 ]
@@ -1533,6 +2019,23 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ]
 
+// From codersdk/webhooks.go
+export type WebhookEvent =
+  | "template_version_promoted"
+  | "user_created"
+  | "user_suspended"
+  | "workspace_build_failed"
+  | "workspace_build_started"
+  | "workspace_build_succeeded"
+export const WebhookEvents: WebhookEvent[] = [
+  "template_version_promoted",
+  "user_created",
+  "user_suspended",
+  "workspace_build_failed",
+  "workspace_build_started",
+  "workspace_build_succeeded",
+]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -1556,6 +2059,21 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
   "starting",
 ]
 
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentScriptStatus =
+  | "error"
+  | "ok"
+  | "pending"
+  | "running"
+  | "timed_out"
+export const WorkspaceAgentScriptStatuses: WorkspaceAgentScriptStatus[] = [
+  "error",
+  "ok",
+  "pending",
+  "running",
+  "timed_out",
+]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStatus =
   | "connected"
@@ -1582,6 +2100,14 @@ export const WorkspaceAppHealths: WorkspaceAppHealth[] = [
   "unhealthy",
 ]
 
+// From codersdk/workspaceapps.go
+export type WorkspaceAppHealthcheckType = "command" | "http" | "tcp"
+export const WorkspaceAppHealthcheckTypes: WorkspaceAppHealthcheckType[] = [
+  "command",
+  "http",
+  "tcp",
+]
+
 // From codersdk/workspaceapps.go
 export type WorkspaceAppSharingLevel = "authenticated" | "owner" | "public"
 export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
@@ -1590,6 +2116,30 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ]
 
+// From codersdk/workspacebuilds.go
+export type WorkspaceBuildTimingStage =
+  | "apply"
+  | "connect"
+  | "graph"
+  | "init"
+  | "plan"
+  | "startup_script"
+export const WorkspaceBuildTimingStages: WorkspaceBuildTimingStage[] = [
+  "apply",
+  "connect",
+  "graph",
+  "init",
+  "plan",
+  "startup_script",
+]
+
+// From codersdk/workspaceportshare.go
+export type WorkspacePortShareProtocol = "http" | "https"
+export const WorkspacePortShareProtocols: WorkspacePortShareProtocol[] = [
+  "http",
+  "https",
+]
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
@@ -38,6 +38,7 @@ import { AppLink } from "../AppLink/AppLink"
 import { SSHButton } from "../SSHButton/SSHButton"
 import { Stack } from "../Stack/Stack"
 import { TerminalLink } from "../TerminalLink/TerminalLink"
+import { TerminalSessionsButton } from "../TerminalSessionsButton/TerminalSessionsButton"
 import { AgentLatency } from "./AgentLatency"
 import { AgentMetadata } from "./AgentMetadata"
 import { AgentStatus } from "./AgentStatus"
@@ -257,6 +258,12 @@ export const AgentRow: FC<AgentRowProps> = ({
                     agentName={agent.name}
                     userName={workspace.owner_name}
                   />
+                  <TerminalSessionsButton
+                    agentId={agent.id}
+                    agentName={agent.name}
+                    workspaceName={workspace.name}
+                    userName={workspace.owner_name}
+                  />
                   {!hideSSHButton && (
                     <SSHButton
                       workspaceName={workspace.name}
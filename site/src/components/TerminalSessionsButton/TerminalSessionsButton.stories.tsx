@@ -0,0 +1,49 @@
+import { Story } from "@storybook/react"
+import { MockWorkspace, MockWorkspaceAgent } from "testHelpers/entities"
+import {
+  TerminalSessionsList,
+  TerminalSessionsListProps,
+} from "./TerminalSessionsButton"
+
+export default {
+  title: "components/TerminalSessionsList",
+  component: TerminalSessionsList,
+}
+
+const Template: Story<TerminalSessionsListProps> = (args) => (
+  <TerminalSessionsList {...args} />
+)
+
+const getHref = () =>
+  `/@${MockWorkspace.owner_name}/${MockWorkspace.name}.${MockWorkspaceAgent.name}/terminal`
+
+export const Sessions = Template.bind({})
+Sessions.args = {
+  getHref,
+  sessions: [
+    {
+      id: "7d6f6a2e-6c1b-4a7e-9f1e-1d6c2e0b5a31",
+      backend: "tmux",
+      command: "",
+      active_connections: 1,
+    },
+    {
+      id: "2b0d5f3e-8a6c-5d9f-b2c4-7e1a3f5d9c20",
+      name: "build",
+      backend: "tmux",
+      command: "make build",
+      active_connections: 0,
+    },
+  ],
+}
+
+export const Empty = Template.bind({})
+Empty.args = {
+  getHref,
+  sessions: [],
+}
+
+export const Loading = Template.bind({})
+Loading.args = {
+  getHref,
+}
@@ -0,0 +1,231 @@
+import Button from "@material-ui/core/Button"
+import Link from "@material-ui/core/Link"
+import Popover from "@material-ui/core/Popover"
+import { makeStyles } from "@material-ui/core/styles"
+import ViewListOutlined from "@material-ui/icons/ViewListOutlined"
+import { getAgentReconnectingPTYs } from "api/api"
+import { getErrorMessage } from "api/errors"
+import { WorkspaceAgentReconnectingPTY } from "api/typesGenerated"
+import { Stack } from "components/Stack/Stack"
+import { FC, useEffect, useRef, useState } from "react"
+import { generateRandomString } from "utils/random"
+import { HelpTooltipText, HelpTooltipTitle } from "../Tooltips/HelpTooltip"
+
+export const Language = {
+  buttonText: "Sessions",
+  title: "Terminal sessions",
+  description:
+    "Sessions of the web terminal and of coder ssh --session keep running when you disconnect. Select a session to reattach to it.",
+  empty: "There are no terminal sessions.",
+  loading: "Loading sessions...",
+  error: "Unable to fetch terminal sessions.",
+  unnamed: "Web terminal",
+  shell: "shell",
+  connections: (count: number): string =>
+    count === 1 ? "1 connection" : `${count} connections`,
+}
+
+export interface TerminalSessionsButtonProps {
+  agentId: string
+  agentName: string
+  workspaceName: string
+  userName: string
+}
+
+export const terminalSessionURL = (
+  userName: string,
+  workspaceName: string,
+  agentName: string,
+  sessionId: string,
+): string => {
+  const params = new URLSearchParams({ reconnect: sessionId })
+  return `/@${userName}/${workspaceName}.${agentName}/terminal?${params.toString()}`
+}
+
+export interface TerminalSessionsListProps {
+  sessions?: WorkspaceAgentReconnectingPTY[]
+  error?: unknown
+  getHref: (session: WorkspaceAgentReconnectingPTY) => string
+}
+
+export const TerminalSessionsList: FC<TerminalSessionsListProps> = ({
+  sessions,
+  error,
+  getHref,
+}) => {
+  const styles = useStyles()
+
+  if (error) {
+    return (
+      <HelpTooltipText>
+        {getErrorMessage(error, Language.error)}
+      </HelpTooltipText>
+    )
+  }
+  if (!sessions) {
+    return <HelpTooltipText>{Language.loading}</HelpTooltipText>
+  }
+  if (sessions.length === 0) {
+    return <HelpTooltipText>{Language.empty}</HelpTooltipText>
+  }
+  return (
+    <Stack spacing={1} className={styles.list}>
+      {sessions.map((session) => {
+        const href = getHref(session)
+        const details = [
+          session.command || Language.shell,
+          session.backend,
+          Language.connections(session.active_connections),
+        ].join(" · ")
+        return (
+          <Link
+            key={session.id}
+            href={href}
+            target="_blank"
+            className={styles.session}
+            onClick={(event) => {
+              event.preventDefault()
+              window.open(
+                href,
+                `Terminal - ${generateRandomString(12)}`,
+                "width=900,height=600",
+              )
+            }}
+          >
+            <span className={styles.sessionName}>
+              {session.name || Language.unnamed}
+            </span>
+            <span className={styles.sessionDetails}>{details}</span>
+          </Link>
+        )
+      })}
+    </Stack>
+  )
+}
+
+export const TerminalSessionsButton: FC<TerminalSessionsButtonProps> = ({
+  agentId,
+  agentName,
+  workspaceName,
+  userName,
+}) => {
+  const anchorRef = useRef<HTMLButtonElement>(null)
+  const [isOpen, setIsOpen] = useState(false)
+  const styles = useStyles()
+  const [sessions, setSessions] = useState<WorkspaceAgentReconnectingPTY[]>()
+  const [error, setError] = useState<unknown>()
+
+  // Sessions are fetched every time the popover opens, since they start and
+  // end outside of the dashboard.
+  useEffect(() => {
+    if (!isOpen) {
+      return
+    }
+    let canceled = false
+    setSessions(undefined)
+    setError(undefined)
+    getAgentReconnectingPTYs(agentId)
+      .then((res) => {
+        if (!canceled) {
+          setSessions(res.sessions)
+        }
+      })
+      .catch((err) => {
+        if (!canceled) {
+          setError(err)
+        }
+      })
+    return () => {
+      canceled = true
+    }
+  }, [agentId, isOpen])
+
+  return (
+    <>
+      <Button
+        className={styles.button}
+        startIcon={<ViewListOutlined />}
+        size="small"
+        ref={anchorRef}
+        onClick={() => {
+          setIsOpen(true)
+        }}
+      >
+        {Language.buttonText}
+      </Button>
+      <Popover
+        classes={{ paper: styles.popoverPaper }}
+        open={isOpen}
+        anchorEl={anchorRef.current}
+        onClose={() => {
+          setIsOpen(false)
+        }}
+        anchorOrigin={{
+          vertical: "bottom",
+          horizontal: "left",
+        }}
+        transformOrigin={{
+          vertical: "top",
+          horizontal: "left",
+        }}
+      >
+        <HelpTooltipTitle>{Language.title}</HelpTooltipTitle>
+        <HelpTooltipText>{Language.description}</HelpTooltipText>
+        <TerminalSessionsList
+          sessions={sessions}
+          error={error}
+          getHref={(session) =>
+            terminalSessionURL(userName, workspaceName, agentName, session.id)
+          }
+        />
+      </Popover>
+    </>
+  )
+}
+
+const useStyles = makeStyles((theme) => ({
+  popoverPaper: {
+    padding: `${theme.spacing(2.5)}px ${theme.spacing(3.5)}px ${theme.spacing(
+      3.5,
+    )}px`,
+    width: theme.spacing(52),
+    color: theme.palette.text.secondary,
+    marginTop: theme.spacing(0.25),
+  },
+
+  list: {
+    marginTop: theme.spacing(2),
+  },
+
+  session: {
+    display: "flex",
+    flexDirection: "column",
+    padding: theme.spacing(1, 1.5),
+    borderRadius: theme.shape.borderRadius,
+    border: `1px solid ${theme.palette.divider}`,
+    textDecoration: "none !important",
+
+    "&:hover": {
+      backgroundColor: theme.palette.action.hover,
+    },
+  },
+
+  sessionName: {
+    color: theme.palette.text.primary,
+    fontWeight: 600,
+  },
+
+  sessionDetails: {
+    fontSize: 12,
+    color: theme.palette.text.secondary,
+  },
+
+  button: {
+    whiteSpace: "nowrap",
+    backgroundColor: theme.palette.background.default,
+
+    "&:hover": {
+      backgroundColor: `${theme.palette.background.default} !important`,
+    },
+  },
+}))
@@ -99,6 +99,11 @@ export const TemplateScheduleForm: FC<TemplateScheduleForm> = ({
           : undefined,
         allow_user_autostart: formData.allow_user_autostart,
         allow_user_autostop: formData.allow_user_autostop,
+        // dormancy and failure settings are not editable here yet, so keep
+        // them as-is.
+        inactivity_ttl_ms: template.inactivity_ttl_ms,
+        dormant_autodelete_ttl_ms: template.dormant_autodelete_ttl_ms,
+        failure_ttl_ms: template.failure_ttl_ms,
       })
     },
     initialTouched,
@@ -43,6 +43,12 @@ WithTemplates.args = {
         description:
           "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. ",
       },
+      {
+        ...MockTemplate,
+        description: "🪦 An old template that workspaces shouldn't use",
+        deprecated: true,
+        deprecation_message: "Use the new template instead.",
+      },
     ],
     examples: [],
   },
@@ -42,6 +42,7 @@ import { combineClasses } from "utils/combineClasses"
 import { colors } from "theme/colors"
 import ArrowForwardOutlined from "@material-ui/icons/ArrowForwardOutlined"
 import { Avatar } from "components/Avatar/Avatar"
+import { Pill } from "components/Pill/Pill"
 
 export const Language = {
   developerCount: (activeCount: number): string => {
@@ -57,6 +58,7 @@ export const Language = {
   templateTooltipText:
     "With templates you can create a common configuration for your workspaces using Terraform.",
   templateTooltipLink: "Manage templates",
+  deprecatedLabel: "Deprecated",
 }
 
 const TemplateHelpTooltip: React.FC = () => {
@@ -91,17 +93,28 @@ const TemplateRow: FC<{ template: Template }> = ({ template }) => {
       className={combineClasses([clickableClassName, styles.tableRow])}
     >
       <TableCell>
-        <AvatarData
-          title={
-            template.display_name.length > 0
-              ? template.display_name
-              : template.name
-          }
-          subtitle={template.description}
-          avatar={
-            hasIcon && <Avatar src={template.icon} variant="square" fitImage />
-          }
-        />
+        <Stack direction="row" alignItems="center" spacing={2}>
+          <AvatarData
+            title={
+              template.display_name.length > 0
+                ? template.display_name
+                : template.name
+            }
+            subtitle={template.description}
+            avatar={
+              hasIcon && (
+                <Avatar src={template.icon} variant="square" fitImage />
+              )
+            }
+          />
+          <Maybe condition={template.deprecated}>
+            <Pill
+              text={Language.deprecatedLabel}
+              type="warning"
+              title={template.deprecation_message}
+            />
+          </Maybe>
+        </Stack>
       </TableCell>
 
       <TableCell className={styles.secondary}>
@@ -123,6 +136,7 @@ const TemplateRow: FC<{ template: Template }> = ({ template }) => {
           className={styles.actionButton}
           startIcon={<ArrowForwardOutlined />}
           title={`Create a workspace using the ${template.display_name} template`}
+          disabled={template.deprecated}
           onClick={(e) => {
             e.stopPropagation()
             navigate(`/templates/${template.name}/workspace`)
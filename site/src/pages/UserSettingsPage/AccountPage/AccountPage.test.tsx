@@ -37,6 +37,7 @@ describe("AccountPage", () => {
           roles: [],
           avatar_url: "",
           last_seen_at: new Date().toString(),
+          quiet_hours_schedule: "",
           ...data,
         }),
       )
@@ -155,6 +155,7 @@ export const MockUser: TypesGen.User = {
   roles: [MockOwnerRole],
   avatar_url: "https://avatars.githubusercontent.com/u/95932066?s=200&v=4",
   last_seen_at: "",
+  quiet_hours_schedule: "",
 }
 
 export const MockUserAdmin: TypesGen.User = {
@@ -167,6 +168,7 @@ export const MockUserAdmin: TypesGen.User = {
   roles: [MockUserAdminRole],
   avatar_url: "",
   last_seen_at: "",
+  quiet_hours_schedule: "",
 }
 
 export const MockUser2: TypesGen.User = {
@@ -179,6 +181,7 @@ export const MockUser2: TypesGen.User = {
   roles: [],
   avatar_url: "",
   last_seen_at: "2022-09-14T19:12:21Z",
+  quiet_hours_schedule: "",
 }
 
 export const SuspendedMockUser: TypesGen.User = {
@@ -191,12 +194,14 @@ export const SuspendedMockUser: TypesGen.User = {
   roles: [],
   avatar_url: "",
   last_seen_at: "",
+  quiet_hours_schedule: "",
 }
 
 export const MockProvisioner: TypesGen.ProvisionerDaemon = {
   created_at: "",
   id: "test-provisioner",
   name: "Test Provisioner",
+  version: "v99.999.9999+c9cdf14",
   provisioners: ["echo"],
   tags: {},
 }
@@ -292,10 +297,23 @@ export const MockTemplate: TypesGen.Template = {
   description: "This is a test description.",
   default_ttl_ms: 24 * 60 * 60 * 1000,
   max_ttl_ms: 2 * 24 * 60 * 60 * 1000,
+  inactivity_ttl_ms: 0,
+  dormant_autodelete_ttl_ms: 0,
+  failure_ttl_ms: 0,
+  restart_requirement: {
+    days_of_week: [],
+    weeks: 0,
+  },
   created_by_id: "test-creator-id",
   created_by_name: "test_creator",
   icon: "/icon/code.svg",
   allow_user_cancel_workspace_jobs: true,
+  deprecated: false,
+  deprecation_message: "",
+  require_active_version: false,
+  max_port_share_level: "owner",
+  session_recording: false,
+  quota_cost_multiplier: 1,
 }
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -377,6 +395,7 @@ export const MockWorkspaceApp: TypesGen.WorkspaceApp = {
   url: "",
   sharing_level: "owner",
   healthcheck: {
+    type: "http",
     url: "",
     interval: 0,
     threshold: 0,
@@ -385,6 +404,7 @@ export const MockWorkspaceApp: TypesGen.WorkspaceApp = {
 
 export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
   apps: [MockWorkspaceApp],
+  scripts: [],
   architecture: "amd64",
   created_at: "",
   environment_variables: {},
@@ -670,6 +690,8 @@ export const MockWorkspace: TypesGen.Workspace = {
   ttl_ms: 2 * 60 * 60 * 1000,
   latest_build: MockWorkspaceBuild,
   last_used_at: "2022-05-16T15:29:10.302441433Z",
+  automatic_updates: "never",
+  template_require_active_version: MockTemplate.require_active_version,
 }
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -61,7 +61,10 @@ export const templatesMachine = createMachine(
     services: {
       load: async ({ organizationId, permissions }) => {
         const [templates, examples] = await Promise.all([
-          API.getTemplates(organizationId),
+          // Deprecated templates are hidden from users who can't manage them.
+          API.getTemplates(organizationId, {
+            include_deprecated: permissions.createTemplates,
+          }),
           permissions.createTemplates
             ? API.getTemplateExamples(organizationId)
             : Promise.resolve([]),
@@ -67,6 +67,11 @@ type Options struct {
 	BlockEndpoints bool
 	Logger         slog.Logger
 	ListenPort     uint16
+	// ForwardTCPHost returns the host that TCP connections to ports without
+	// a listener are forwarded to, e.g. the address of a container. They're
+	// forwarded to 127.0.0.1 if it's nil, and closed if it returns an empty
+	// host.
+	ForwardTCPHost func() string
 }
 
 // NewConn constructs a new Wireguard server that will accept connections from the addresses provided.
@@ -207,6 +212,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	dialContext, dialCancel := context.WithCancel(context.Background())
 	server := &Conn{
 		blockEndpoints:           options.BlockEndpoints,
+		forwardTCPHost:           options.ForwardTCPHost,
 		dialContext:              dialContext,
 		dialCancel:               dialCancel,
 		closed:                   make(chan struct{}),
@@ -308,6 +314,7 @@ type Conn struct {
 	closed         chan struct{}
 	logger         slog.Logger
 	blockEndpoints bool
+	forwardTCPHost func() string
 
 	dialer           *tsdial.Dialer
 	tunDevice        *tstun.Wrapper
@@ -770,7 +777,15 @@ func (*Conn) forwardTCPSockOpts(port uint16) []tcpip.SettableSocketOption {
 
 func (c *Conn) forwardTCPToLocal(conn net.Conn, port uint16) {
 	defer conn.Close()
-	dialAddrStr := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))
+	host := "127.0.0.1"
+	if c.forwardTCPHost != nil {
+		host = c.forwardTCPHost()
+		if host == "" {
+			c.logger.Debug(c.dialContext, "no host to forward to", slog.F("port", port))
+			return
+		}
+	}
+	dialAddrStr := net.JoinHostPort(host, strconv.Itoa(int(port)))
 	var stdDialer net.Dialer
 	server, err := stdDialer.DialContext(c.dialContext, "tcp", dialAddrStr)
 	if err != nil {